@@ -0,0 +1,849 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yourusername/oci-arm-provisioner/internal/bundle"
+	"github.com/yourusername/oci-arm-provisioner/internal/config"
+	"github.com/yourusername/oci-arm-provisioner/internal/confirm"
+	"github.com/yourusername/oci-arm-provisioner/internal/fixtures"
+	"github.com/yourusername/oci-arm-provisioner/internal/jsondiff"
+	"github.com/yourusername/oci-arm-provisioner/internal/logger"
+	"github.com/yourusername/oci-arm-provisioner/internal/notifier"
+	"github.com/yourusername/oci-arm-provisioner/internal/provisioner"
+	"github.com/yourusername/oci-arm-provisioner/internal/service"
+	"github.com/yourusername/oci-arm-provisioner/internal/update"
+	"github.com/yourusername/oci-arm-provisioner/internal/wizard"
+)
+
+// rootOptions holds the flags of the root command, which runs the provisioner.
+type rootOptions struct {
+	configPath         string
+	headless           bool
+	setup              bool
+	setupNotifications bool
+	demo               bool
+}
+
+// usageError marks errors caused by how the command was invoked (exit code 2).
+type usageError struct{ error }
+
+// reportedError marks errors that were already logged; they only set the exit code.
+type reportedError struct{ error }
+
+// execute runs the command tree and returns the process exit code.
+func execute(root *cobra.Command, args []string) int {
+	root.SetArgs(legacyFlags(root, args))
+	cmd, err := root.ExecuteC()
+	if err == nil {
+		return 0
+	}
+	if errors.As(err, new(reportedError)) {
+		return 1
+	}
+	fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+	if errors.As(err, new(usageError)) || !ran(cmd) {
+		fmt.Fprintf(os.Stderr, "Run '%s --help' for usage.\n", cmd.CommandPath())
+		return 2
+	}
+	return 1
+}
+
+// legacyFlags rewrites the single-dash long flags of releases before the command tree
+// (-headless, -config x.yaml) to their double-dash form, so existing service units
+// and scripts keep working. Shorthands and everything after "--" are left alone.
+func legacyFlags(root *cobra.Command, args []string) []string {
+	root.InitDefaultHelpFlag() // -help, as the flag package accepted
+	root.InitDefaultVersionFlag()
+	out := make([]string, 0, len(args))
+	for i, arg := range args {
+		if arg == "--" {
+			return append(out, args[i:]...)
+		}
+		name, _, _ := strings.Cut(strings.TrimPrefix(arg, "-"), "=")
+		if strings.HasPrefix(arg, "-") && !strings.HasPrefix(arg, "--") && len(name) > 1 &&
+			(root.Flags().Lookup(name) != nil || root.PersistentFlags().Lookup(name) != nil) {
+			arg = "-" + arg
+		}
+		out = append(out, arg)
+	}
+	return out
+}
+
+// ran reports whether cmd got past flag and argument validation.
+func ran(cmd *cobra.Command) bool {
+	return cmd.Annotations["ran"] == "true"
+}
+
+// newRootCmd builds the command tree.
+func newRootCmd() *cobra.Command {
+	var opts rootOptions
+	root := &cobra.Command{
+		Use:   "oci-arm-provisioner",
+		Short: "Keep trying to launch an Always Free ARM instance on OCI until one sticks",
+		Long: `oci-arm-provisioner retries launching Ampere A1 instances on Oracle Cloud until
+capacity frees up, then verifies the instance and notifies you.
+
+Without a command it opens the dashboard (TUI). Use --headless for a log-only
+process (containers, cron, services) and --setup to write config.yaml.`,
+		Example: `  oci-arm-provisioner --setup               # create config.yaml interactively
+  oci-arm-provisioner                       # run with the dashboard
+  oci-arm-provisioner --headless --config /etc/oci/config.yaml
+  oci-arm-provisioner service install       # run at boot`,
+		Version:       version,
+		Args:          cobra.NoArgs,
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			// Cobra checks flag groups after this hook; check them first so a conflict is a usage error.
+			if err := cmd.ValidateFlagGroups(); err != nil {
+				return usageError{err}
+			}
+			if cmd.Annotations == nil {
+				cmd.Annotations = make(map[string]string)
+			}
+			cmd.Annotations["ran"] = "true"
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return run(opts)
+		},
+	}
+
+	flags := root.PersistentFlags()
+	flags.StringVar(&opts.configPath, "config", "", "Path to config.yaml (default: search standard locations)")
+	root.MarkPersistentFlagFilename("config", "yaml", "yml")
+
+	local := root.Flags()
+	local.BoolVar(&opts.headless, "headless", false, "Run in headless mode (log-only, no TUI)")
+	local.BoolVar(&stateless, "stateless", false, "Never write to disk: log to stdout only, keep state in memory (implies --headless)")
+	local.BoolVar(&accessible, "accessible", false, "Screen-reader friendly output: plain labeled lines, no colors, emoji or box drawing")
+	local.BoolVar(&opts.setup, "setup", false, "Run the OCI setup wizard (config.yaml)")
+	local.BoolVar(&opts.setupNotifications, "setup-notifications", false, "Run the notification setup wizard")
+	local.BoolVar(&opts.demo, "demo", false, "Run the dashboard against a simulated OCI: no credentials, config or files needed")
+	root.MarkFlagsMutuallyExclusive("setup", "setup-notifications")
+	root.MarkFlagsMutuallyExclusive("demo", "headless")
+	root.MarkFlagsMutuallyExclusive("demo", "stateless")
+
+	root.AddCommand(
+		newServiceCmd(&opts),
+		newConfigCmd(&opts),
+		newNotifyCmd(&opts),
+		newImportCmd(&opts),
+		newDebugCmd(&opts),
+		newSimulateCmd(&opts),
+		newDryRunCmd(&opts),
+		newSelfUpdateCmd(),
+		newGenFixturesCmd(),
+	)
+	root.AddCommand(&cobra.Command{
+		Use:   "version",
+		Short: "Print the version",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			fmt.Fprintln(cmd.OutOrStdout(), version)
+		},
+	})
+	return root
+}
+
+// group returns a command that only holds subcommands; run bare, it prints its help.
+func group(use, short, long string, children ...*cobra.Command) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   use,
+		Short: short,
+		Long:  long,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.Help()
+			return usageError{fmt.Errorf("%s needs a command", cmd.CommandPath())}
+		},
+	}
+	cmd.AddCommand(children...)
+	return cmd
+}
+
+// confirmFlags adds --yes, and --force for the same, to a command that asks with
+// confirm before it changes or deletes something.
+func confirmFlags(cmd *cobra.Command, yes *bool) {
+	cmd.Flags().BoolVarP(yes, "yes", "y", false, "Don't ask for confirmation (for scripts)")
+	cmd.Flags().BoolVar(yes, "force", false, "Same as --yes")
+}
+
+// newServiceCmd builds `service install|uninstall|status`.
+func newServiceCmd(opts *rootOptions) *cobra.Command {
+	install := &cobra.Command{
+		Use:   "install",
+		Short: "Install and start the background service",
+		Long: `Install the provisioner as a headless background service (systemd, launchd or
+the Windows Service Manager) and start it. The config path is resolved now and
+pinned in the service definition, so the service does not depend on its working
+directory. On Windows, run from an Administrator prompt.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configPath := opts.configPath
+			if configPath == "" {
+				if _, found, err := config.LoadConfig(""); err == nil {
+					configPath = found
+				} else {
+					fmt.Fprintf(os.Stderr, "⚠️  Config not loadable (%v); the service will search standard locations.\n", err)
+				}
+			}
+			svcOpts, err := service.DefaultOptions(configPath)
+			if err != nil {
+				return err
+			}
+			if err := service.Install(svcOpts); err != nil {
+				return fmt.Errorf("install failed: %w", err)
+			}
+			fmt.Printf("✅ Service '%s' installed and started.\n", service.Name)
+			return nil
+		},
+	}
+	var yes bool
+	uninstall := &cobra.Command{
+		Use:   "uninstall",
+		Short: "Stop and remove the background service",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := confirm.Terminal(yes).Confirm(confirm.Action{
+				What:    "Stop and remove the background service",
+				Targets: []string{service.Name},
+			}); err != nil {
+				return err
+			}
+			if err := service.Uninstall(); err != nil {
+				return fmt.Errorf("uninstall failed: %w", err)
+			}
+			fmt.Printf("✅ Service '%s' removed.\n", service.Name)
+			return nil
+		},
+	}
+	confirmFlags(uninstall, &yes)
+	status := &cobra.Command{
+		Use:   "status",
+		Short: "Show whether the background service is installed and running",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			st, err := service.Status()
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%s: %s\n", service.Name, st)
+			return nil
+		},
+	}
+	return group("service", "Manage the background service",
+		"Install, remove or inspect the provisioner running as a background service.",
+		install, uninstall, status)
+}
+
+// newConfigCmd builds `config history|rollback|schema|tune`.
+func newConfigCmd(opts *rootOptions) *cobra.Command {
+	resolve := func() (string, error) {
+		path := config.ResolvePath(opts.configPath)
+		if path == "" {
+			return "", fmt.Errorf("config.yaml not found in standard locations")
+		}
+		return path, nil
+	}
+
+	history := &cobra.Command{
+		Use:   "history",
+		Short: "List the recorded changes to config.yaml",
+		Long: `List the changes the provisioner made to config.yaml (wizards, TUI edits,
+rollbacks), newest last. 'config rollback' undoes the most recent one.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := resolve()
+			if err != nil {
+				return err
+			}
+			entries, err := config.History(path)
+			if err != nil {
+				return err
+			}
+			if len(entries) == 0 {
+				fmt.Printf("No recorded changes for %s\n", path)
+				return nil
+			}
+			for _, e := range entries {
+				fmt.Printf("#%-3d %s  %-8s  %s\n", e.Seq, e.Time.Format("2006-01-02 15:04:05"), e.Action, e.Reason)
+			}
+			return nil
+		},
+	}
+	var yes bool
+	rollback := &cobra.Command{
+		Use:   "rollback",
+		Short: "Undo the most recent change to config.yaml",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := resolve()
+			if err != nil {
+				return err
+			}
+			target, err := config.RollbackTarget(path)
+			if err != nil {
+				return fmt.Errorf("rollback failed: %w", err)
+			}
+			if err := confirm.Terminal(yes).Confirm(confirm.Action{
+				What: "Overwrite " + path + " with its contents before",
+				Targets: []string{fmt.Sprintf("#%d %s  %s", target.Seq,
+					target.Time.Format("2006-01-02 15:04:05"), target.Reason)},
+			}); err != nil {
+				return err
+			}
+			entry, err := config.Rollback(path)
+			if err != nil {
+				return fmt.Errorf("rollback failed: %w", err)
+			}
+			fmt.Printf("✅ Reverted change #%d in %s\n", entry.Reverts, path)
+			return nil
+		},
+	}
+	confirmFlags(rollback, &yes)
+	schema := &cobra.Command{
+		Use:   "schema [file]",
+		Short: "Write a JSON Schema for config.yaml, for editor autocompletion",
+		Long: `Write a JSON Schema for config.yaml to file, or to stdout without one. Editors
+with a YAML language server (VS Code's YAML extension, Neovim, JetBrains) use it
+to complete and check options while you edit. Point the first line of
+config.yaml at it:
+
+  # yaml-language-server: $schema=./config.schema.json`,
+		Example: `  oci-arm-provisioner config schema config.schema.json`,
+		Args:    cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := config.Schema()
+			if err != nil {
+				return err
+			}
+			if len(args) == 0 {
+				fmt.Println(string(data))
+				return nil
+			}
+			if err := os.WriteFile(args[0], append(data, '\n'), 0644); err != nil {
+				return err
+			}
+			fmt.Printf("✅ Wrote %s\n", args[0])
+			return nil
+		},
+	}
+	tune := &cobra.Command{
+		Use:   "tune",
+		Short: "Propose scheduler timings for your accounts and save them",
+		Long: `Ask how many accounts and regions run from this machine's IP and how hard to
+push (conservative, balanced or aggressive), then propose cycle, delay and jitter
+values and, once confirmed, write the scheduler section of config.yaml.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := resolve()
+			if err != nil {
+				return err
+			}
+			l, err := logger.New("logs")
+			if err != nil {
+				return fmt.Errorf("failed to initialize logger: %w", err)
+			}
+			return wizard.TuneScheduler(l, path, bufio.NewReader(os.Stdin))
+		},
+	}
+	return group("config", "Inspect, tune and undo changes to config.yaml",
+		"Every change the provisioner writes to config.yaml is journaled; list or revert them. 'config schema' helps editors check it, 'config tune' proposes scheduler timings.",
+		history, rollback, schema, tune)
+}
+
+// newNotifyCmd builds `notify preview`.
+func newNotifyCmd(opts *rootOptions) *cobra.Command {
+	preview := &cobra.Command{
+		Use:   "preview",
+		Short: "Print every notification as it would be sent, without sending",
+		Long: `Render every notification kind for each configured provider and print the
+requests instead of sending them. A missing or broken config previews all
+providers with default settings.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var cfg config.NotificationConfig
+			if loaded, _, err := config.LoadConfig(opts.configPath); err == nil {
+				cfg = loaded.Notifications
+			}
+
+			n := notifier.NewPreview(cfg)
+			for _, sample := range notifier.Samples {
+				reqs, err := n.Render(sample)
+				if err != nil {
+					return fmt.Errorf("%s: %w", sample.Name, err)
+				}
+				fmt.Printf("======== %s ========\n\n", sample.Name)
+				for _, r := range reqs {
+					fmt.Println(r.String())
+				}
+			}
+			return nil
+		},
+	}
+	var providers []string
+	test := &cobra.Command{
+		Use:   "test",
+		Short: "Send a sample success and digest message through the configured providers",
+		Long: `Send a sample success and digest message with example values through every
+configured provider, or only those given with --provider, and print the result
+of each. Exits 1 if any send failed, so it can check a config edit in a script.
+Batching and rate limits are skipped. The provisioner doesn't need to be running.`,
+		Example: `  oci-arm-provisioner notify test
+  oci-arm-provisioner notify test --provider telegram,ntfy`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, _, err := config.LoadConfig(opts.configPath)
+			if err != nil {
+				return err
+			}
+			n := notifier.New(cfg.Notifications)
+			n.Notes = cfg.AccountNotes()
+
+			configured := n.Providers()
+			if len(configured) == 0 {
+				return fmt.Errorf("no notification providers are configured")
+			}
+			selected := configured
+			if len(providers) > 0 {
+				selected = providers
+			}
+			for _, p := range selected {
+				if !slices.Contains(configured, p) {
+					return usageError{fmt.Errorf("provider %q is not configured (configured: %s)", p, strings.Join(configured, ", "))}
+				}
+			}
+
+			out := cmd.OutOrStdout()
+			if !cfg.Notifications.Enabled {
+				fmt.Fprintln(out, "⚠️  notifications.enabled is false: the provisioner itself won't send anything")
+			}
+			failed := 0
+			for _, p := range selected {
+				only := n.Only(p)
+				for _, sample := range notifier.TestSamples {
+					if err := sample.Send(only); err != nil {
+						failed++
+						fmt.Fprintf(out, "❌ %-9s %-8s %v\n", p, sample.Name, err)
+					} else {
+						fmt.Fprintf(out, "✅ %-9s %s\n", p, sample.Name)
+					}
+				}
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d of %d test messages failed", failed, len(selected)*len(notifier.TestSamples))
+			}
+			return nil
+		},
+	}
+	test.Flags().StringSliceVar(&providers, "provider", nil, "Only these providers: "+strings.Join(notifier.ProviderNames, ", "))
+	test.RegisterFlagCompletionFunc("provider", cobra.FixedCompletions(notifier.ProviderNames, cobra.ShellCompDirectiveNoFileComp))
+
+	return group("notify", "Work with notifications",
+		"Check how notifications look before anything is sent, and that they arrive.",
+		preview, test)
+}
+
+// newImportCmd builds `import-oci-snippet [file]`. The snippet is read from file, or
+// pasted on stdin.
+func newImportCmd(opts *rootOptions) *cobra.Command {
+	var name, key string
+	cmd := &cobra.Command{
+		Use:   "import-oci-snippet [file]",
+		Short: "Add an account from the OCI Console config snippet",
+		Long: `Add an account to config.yaml from the 'Configuration file preview' shown in
+OCI Console -> Profile -> API keys. Without a file, paste the snippet and end it
+with an empty line.`,
+		Example: `  oci-arm-provisioner import-oci-snippet
+  oci-arm-provisioner import-oci-snippet --name work --key ~/.oci/work.pem snippet.txt`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			in := bufio.NewReader(os.Stdin)
+			if len(args) == 0 {
+				fmt.Println("Paste the 'Configuration file preview' from OCI Console -> Profile -> API keys,")
+				fmt.Println("then press ENTER on an empty line:")
+			} else {
+				f, err := os.Open(args[0])
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				// The blank line ends the snippet; stdin stays available for the key path prompt.
+				in = bufio.NewReader(io.MultiReader(f, strings.NewReader("\n\n"), os.Stdin))
+			}
+
+			path := config.ResolvePath(opts.configPath)
+			if path == "" {
+				path = "config.yaml"
+			}
+
+			l, err := logger.New("logs")
+			if err != nil {
+				return fmt.Errorf("failed to initialize logger: %w", err)
+			}
+			if err := wizard.ImportOCISnippet(l, path, in, name, key); err != nil {
+				return fmt.Errorf("import failed: %w", err)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&name, "name", "", "Account name (default: the snippet's profile, e.g. 'default')")
+	cmd.Flags().StringVar(&key, "key", "", "Path to the private key (default: from the snippet, or prompt)")
+	cmd.MarkFlagFilename("key", "pem")
+	return cmd
+}
+
+// newDebugCmd builds `debug bundle [dir]`: it packs a redacted config, the recent log,
+// state and version info into a tarball to attach to a GitHub issue.
+func newDebugCmd(opts *rootOptions) *cobra.Command {
+	bundleCmd := &cobra.Command{
+		Use:   "bundle [output dir]",
+		Short: "Pack redacted config, logs and state for a bug report",
+		Long: `Write debug-bundle-<time>.tar.gz with the redacted config, the end of the log,
+state, attempt history and version info. Secrets, OCIDs and IP addresses are
+redacted, but look it over before attaching it to an issue.`,
+		Args: cobra.MaximumNArgs(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return nil, cobra.ShellCompDirectiveFilterDirs
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir := "."
+			if len(args) > 0 {
+				dir = args[0]
+			}
+
+			bundleOpts := bundle.Options{LogDir: "logs"}
+			// A broken config is often the bug being reported, so include it as-is (redacted).
+			if path := config.ResolvePath(opts.configPath); path != "" {
+				bundleOpts.Config, _ = os.ReadFile(path)
+				bundleOpts.StateFile = filepath.Join(filepath.Dir(path), "state.json")
+			}
+			if cfg, _, err := config.LoadConfig(opts.configPath); err == nil {
+				bundleOpts.StateFile = cfg.StateFile
+				bundleOpts.Status = bundle.Status(cfg)
+			}
+
+			out, err := bundle.Create(dir, bundleOpts)
+			if err != nil {
+				return fmt.Errorf("failed to create debug bundle: %w", err)
+			}
+			fmt.Printf("📦 Wrote %s\n", out)
+			fmt.Println("Secrets and OCIDs are redacted, but please look it over before attaching it to an issue.")
+			return nil
+		},
+	}
+	return group("debug", "Troubleshooting helpers", "Collect information for bug reports.", bundleCmd)
+}
+
+// newSimulateCmd builds `simulate`, which estimates time-to-provision for a few cycle
+// intervals by replaying capacity through the scheduler.
+func newSimulateCmd(opts *rootOptions) *cobra.Command {
+	var (
+		history   string
+		perDay    float64
+		window    time.Duration
+		intervals []time.Duration
+		rateLimit int
+		horizon   time.Duration
+		runs      int
+	)
+	cmd := &cobra.Command{
+		Use:   "simulate",
+		Short: "Estimate time-to-provision for different cycle intervals",
+		Long: `Replay capacity through the scheduler (cycle order, account delay, cycle
+interval and per-tenancy pacing) on a virtual clock, and report how long your
+enabled accounts would take to get an instance and how many attempts that costs.
+
+By default the capacity comes from the launch attempts in the log: every success
+is taken as a window of free capacity lasting --window. With --windows-per-day,
+windows open at random instead; use it when the log has no success yet.`,
+		Example: `  oci-arm-provisioner simulate
+  oci-arm-provisioner simulate --windows-per-day 2 --window 10m
+  oci-arm-provisioner simulate --cycle-interval 1m,5m,15m --rate-limit 10`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, _, err := config.LoadConfig(opts.configPath)
+			if err != nil {
+				return err
+			}
+			tenancies := make(map[string]string)
+			for name, acc := range cfg.Accounts {
+				if acc.Enabled {
+					tenancies[name] = acc.TenancyOCID
+				}
+			}
+			if len(tenancies) == 0 {
+				return errors.New("no enabled accounts to simulate")
+			}
+
+			simOpts := provisioner.SimOptions{
+				Tenancies:   tenancies,
+				AttemptTime: 5 * time.Second,
+				RateLimit:   rateLimit,
+				Horizon:     horizon,
+				Runs:        runs,
+				Seed:        time.Now().UnixNano(),
+			}
+			if perDay > 0 {
+				simOpts.Capacity = provisioner.SyntheticCapacity(perDay, window, 30*24*time.Hour, rand.New(rand.NewSource(simOpts.Seed)))
+				fmt.Printf("🎲 Synthetic capacity: %.3g windows a day, each open %v.\n", perDay, window)
+			} else {
+				if history == "" {
+					history = filepath.Join(cfg.Logging.LogDir, "provisioner.log")
+				}
+				f, err := os.Open(history)
+				if err != nil {
+					return fmt.Errorf("failed to read attempt history: %w (use --windows-per-day for a synthetic pattern)", err)
+				}
+				obs, err := provisioner.ReadHistory(f)
+				f.Close()
+				if err != nil {
+					return fmt.Errorf("failed to read attempt history: %w", err)
+				}
+				if simOpts.Capacity, err = provisioner.CapacityFromHistory(obs, window); err != nil {
+					return err
+				}
+				fmt.Printf("📼 Replaying %d capacity windows seen in %d attempts over %s (%s), each open %v.\n",
+					len(simOpts.Capacity.Windows), len(obs), formatDays(simOpts.Capacity.Period-window), history, window)
+			}
+			fmt.Printf("   %d accounts, %d runs each, giving up after %s.\n\n", len(tenancies), runs, formatDays(horizon))
+
+			current := time.Duration(cfg.Scheduler.CycleIntervalSeconds) * time.Second
+			if !cmd.Flags().Changed("cycle-interval") {
+				intervals = []time.Duration{time.Minute, 5 * time.Minute, 15 * time.Minute, 30 * time.Minute}
+			}
+			if !slices.Contains(intervals, current) {
+				intervals = append(intervals, current)
+			}
+			slices.Sort(intervals)
+
+			tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(tw, "CYCLE INTERVAL\tMEDIAN\tP90\tPROVISIONED\tATTEMPTS/DAY\tRATE LIMITED")
+			for _, interval := range intervals {
+				simOpts.Scheduler = cfg.Scheduler
+				simOpts.Scheduler.CycleIntervalSeconds = int(interval.Seconds())
+				res := provisioner.Simulate(simOpts)
+
+				label := interval.String()
+				if interval == current {
+					label += " (current)"
+				}
+				median, p90 := "-", "-"
+				if res.Provisioned > 0 {
+					median, p90 = formatDays(res.Median), formatDays(res.P90)
+				}
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%d%%\t%.0f\t%d\n", label, median, p90,
+					100*res.Provisioned/res.Hunts, res.PerDay, res.RateLimited)
+			}
+			tw.Flush()
+			fmt.Println("\nShorter intervals find capacity sooner but spend more requests; OCI may rate limit or flag aggressive polling.")
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&history, "history", "", "Log file to replay (default: provisioner.log in the log dir)")
+	flags.Float64Var(&perDay, "windows-per-day", 0, "Use random capacity windows, this many a day on average, instead of the history")
+	flags.DurationVar(&window, "window", 5*time.Minute, "How long freed capacity stays available")
+	flags.DurationSliceVar(&intervals, "cycle-interval", nil, "Cycle intervals to compare (default 1m,5m,15m,30m and the configured one)")
+	flags.IntVar(&rateLimit, "rate-limit", 0, "Attempts per minute a tenancy gets before a 429 (0 = never rate limited)")
+	flags.DurationVar(&horizon, "horizon", 30*24*time.Hour, "Give up on an account after this long")
+	flags.IntVar(&runs, "runs", 200, "Runs per interval, each starting at a random point of the capacity")
+	cmd.MarkFlagFilename("history", "log")
+	return cmd
+}
+
+// newGenFixturesCmd builds `genfixtures`, a developer command that writes anonymized
+// sample installs for integration tests.
+func newGenFixturesCmd() *cobra.Command {
+	var (
+		shapes []string
+		seed   int64
+	)
+	var names []string
+	for _, s := range fixtures.Shapes {
+		names = append(names, s.Name)
+	}
+	cmd := &cobra.Command{
+		Use:    "genfixtures [output dir]",
+		Short:  "Write sample configs, state and attempt histories for tests",
+		Hidden: true,
+		Long: `Write one directory per install shape with a config.yaml, freshly generated
+keys, a state.json and a logs/provisioner.log of launch attempts. Every
+identifier is fake. Shapes: ` + strings.Join(names, ", ") + `.`,
+		Example: `  oci-arm-provisioner genfixtures                      # into testdata/fixtures
+  oci-arm-provisioner genfixtures --shape five --seed 7 /tmp/fixtures`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir := filepath.Join("testdata", "fixtures")
+			if len(args) > 0 {
+				dir = args[0]
+			}
+			if len(shapes) == 0 {
+				shapes = names
+			}
+			var selected []fixtures.Shape
+			for _, name := range shapes {
+				shape, ok := fixtures.Lookup(name)
+				if !ok {
+					return usageError{fmt.Errorf("unknown shape %q (want one of %s)", name, strings.Join(names, ", "))}
+				}
+				selected = append(selected, shape)
+			}
+			for _, shape := range selected {
+				out := filepath.Join(dir, shape.Name)
+				if err := fixtures.Generate(out, shape, seed); err != nil {
+					return fmt.Errorf("failed to generate %s: %w", shape.Name, err)
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "🧪 %s: %s\n", out, shape.Description)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringSliceVar(&shapes, "shape", nil, "Shapes to generate (default all)")
+	cmd.Flags().Int64Var(&seed, "seed", 1, "Seed for identifiers and histories; the same seed gives the same files")
+	cmd.RegisterFlagCompletionFunc("shape", cobra.FixedCompletions(names, cobra.ShellCompDirectiveNoFileComp))
+	return cmd
+}
+
+// formatDays formats d as e.g. "3d4h", "5h12m" or "40s".
+func formatDays(d time.Duration) string {
+	switch {
+	case d >= 24*time.Hour:
+		return fmt.Sprintf("%dd%dh", d/(24*time.Hour), d%(24*time.Hour)/time.Hour)
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh%dm", d/time.Hour, d%time.Hour/time.Minute)
+	default:
+		return d.Round(time.Second).String()
+	}
+}
+
+// newDryRunCmd builds `dry-run`: it prints the launch request of every enabled account as
+// canonical JSON, or compares them with a saved copy.
+func newDryRunCmd(opts *rootOptions) *cobra.Command {
+	var diff string
+	cmd := &cobra.Command{
+		Use:   "dry-run",
+		Short: "Print the launch request of each account without launching",
+		Long: `Build the LaunchInstance request of every enabled account and print it as JSON
+with sorted keys, so the same config always prints the same bytes. Nothing is
+launched; only image aliases are looked up in OCI. The size is the configured one,
+before any free tier shrinking, and with availability_domain: auto the AD is left
+out. An account whose request can't be built shows its error instead.
+
+Save the output and pass it to --diff after a config change, to review what the
+change does to the requests. With --diff the exit code is 1 if anything changed.`,
+		Example: `  oci-arm-provisioner dry-run > launch.json
+  oci-arm-provisioner dry-run --diff launch.json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var previous []byte
+			if diff != "" {
+				var err error
+				if previous, err = os.ReadFile(diff); err != nil {
+					return err
+				}
+			}
+			cfg, _, err := config.LoadConfig(opts.configPath)
+			if err != nil {
+				return err
+			}
+			cfg.MQTT.Broker = "" // Nothing happens worth publishing
+
+			// stdout carries the JSON
+			l := logger.NewConsole()
+			l.SetConsoleOutput(os.Stderr)
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+			defer cancel()
+			requests := make(map[string]any)
+			for _, p := range provisioner.New(cfg, l, notifier.NewTracker()).PreviewLaunches(ctx) {
+				if p.Err != nil {
+					l.Error(p.Account, fmt.Sprintf("Can't build the launch request: %v", p.Err))
+					requests[p.Account] = map[string]string{"error": p.Err.Error()}
+					continue
+				}
+				requests[p.Account] = p.Details
+			}
+			out, err := jsondiff.Canonical(requests)
+			if err != nil {
+				return err
+			}
+			if diff == "" {
+				_, err := os.Stdout.Write(out)
+				return err
+			}
+
+			lines, err := jsondiff.Diff(previous, out)
+			if err != nil {
+				return fmt.Errorf("%s: %w", diff, err)
+			}
+			if len(lines) == 0 {
+				fmt.Printf("✅ The launch requests match %s\n", diff)
+				return nil
+			}
+			for _, line := range lines {
+				fmt.Println(line)
+			}
+			fmt.Fprintf(os.Stderr, "%d change(s) from %s\n", len(lines), diff)
+			return reportedError{errors.New("launch requests changed")}
+		},
+	}
+	cmd.Flags().StringVar(&diff, "diff", "", "Compare with a saved dry-run output instead of printing it")
+	cmd.MarkFlagFilename("diff", "json")
+	return cmd
+}
+
+// newSelfUpdateCmd builds `self-update`, which downloads the latest release and replaces
+// the running binary.
+func newSelfUpdateCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "self-update",
+		Short: "Replace this binary with the latest release",
+		Long: `Download the latest GitHub release for this platform, verify its checksum and
+replace the running binary. Restart the provisioner (or its service) afterwards.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+			defer cancel()
+
+			checker := update.NewChecker()
+			rel, err := checker.Latest(ctx)
+			if err != nil {
+				return fmt.Errorf("update check failed: %w", err)
+			}
+			if !update.IsNewer(rel.Version(), version) {
+				fmt.Printf("✅ Already up to date (%s).\n", version)
+				return nil
+			}
+
+			exe, err := os.Executable()
+			if err != nil {
+				return fmt.Errorf("failed to locate executable: %w", err)
+			}
+			if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+				exe = resolved
+			}
+
+			fmt.Printf("⬇️  Updating %s -> %s...\n", version, rel.Version())
+			if err := checker.Apply(ctx, rel, exe); err != nil {
+				return fmt.Errorf("update failed: %w", err)
+			}
+			fmt.Printf("✅ Updated to %s. Restart the provisioner (or its service) to use it.\n", rel.Version())
+			return nil
+		},
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/oci-arm-provisioner/internal/config"
+)
+
+func TestExecute_ExitCodes(t *testing.T) {
+	for _, tc := range []struct {
+		args []string
+		want int
+	}{
+		{[]string{"version"}, 0},
+		{[]string{"bogus"}, 2},
+		{[]string{"service"}, 2},
+		{[]string{"debug", "bundle", "a", "b"}, 2},
+		{[]string{"--setup", "--setup-notifications"}, 2},
+		{[]string{"--demo", "--headless"}, 2},
+		{[]string{"genfixtures", "--shape", "bogus"}, 2},
+	} {
+		root := newRootCmd()
+		root.SetOut(io.Discard)
+		if got := execute(root, tc.args); got != tc.want {
+			t.Errorf("%v: exit code %d, want %d", tc.args, got, tc.want)
+		}
+	}
+}
+
+func TestLegacyFlags(t *testing.T) {
+	args := []string{"-headless", "-config", "a.yaml", "-config=b.yaml", "--setup", "service", "-y", "-bogus", "--", "-setup"}
+	want := []string{"--headless", "--config", "a.yaml", "--config=b.yaml", "--setup", "service", "-y", "-bogus", "--", "-setup"}
+	if got := legacyFlags(newRootCmd(), args); !slices.Equal(got, want) {
+		t.Errorf("legacyFlags = %q, want %q", got, want)
+	}
+
+	// Single-dash long flags parse like before the command tree
+	root := newRootCmd()
+	root.SetOut(io.Discard)
+	if got := execute(root, []string{"-version"}); got != 0 {
+		t.Errorf("-version: exit code %d, want 0", got)
+	}
+}
+
+func TestCompletion(t *testing.T) {
+	for _, shell := range []string{"bash", "zsh", "fish", "powershell"} {
+		var out bytes.Buffer
+		root := newRootCmd()
+		root.SetOut(&out)
+		if code := execute(root, []string{"completion", shell}); code != 0 {
+			t.Fatalf("completion %s: exit code %d", shell, code)
+		}
+		if !strings.Contains(out.String(), "oci-arm-provisioner") {
+			t.Errorf("completion %s: script does not mention the binary", shell)
+		}
+	}
+
+	var out bytes.Buffer
+	root := newRootCmd()
+	root.SetOut(&out)
+	execute(root, []string{"__complete", "service", ""})
+	for _, sub := range []string{"install", "uninstall", "status"} {
+		if !strings.Contains(out.String(), sub) {
+			t.Errorf("expected %q among service completions:\n%s", sub, out.String())
+		}
+	}
+}
+
+func TestNotifyTest(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if strings.Contains(string(body), "Digest") {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	os.WriteFile(path, []byte("accounts: {}\nnotifications:\n  enabled: true\n  webhook_url: "+server.URL+"\n  batch_window: 1h\n"), 0600)
+
+	var out bytes.Buffer
+	root := newRootCmd()
+	root.SetOut(&out)
+	if code := execute(root, []string{"notify", "test", "--config", path}); code != 1 {
+		t.Errorf("expected exit code 1 for the failed digest, got %d", code)
+	}
+	if len(bodies) == 0 || !strings.Contains(bodies[0], "203.0.113.42") {
+		t.Errorf("expected the sample success to be sent right away, got %q", bodies)
+	}
+	if !strings.Contains(out.String(), "✅ webhook   success") || !strings.Contains(out.String(), "❌ webhook   digest") {
+		t.Errorf("expected per-provider results, got:\n%s", out.String())
+	}
+
+	root = newRootCmd()
+	root.SetOut(io.Discard)
+	if code := execute(root, []string{"notify", "test", "--config", path, "--provider", "telegram"}); code != 2 {
+		t.Errorf("expected exit code 2 for a provider that isn't configured, got %d", code)
+	}
+}
+
+func TestConfigRollback_Confirm(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	os.WriteFile(path, []byte("accounts: {}\n"), 0600)
+	if err := config.Write(path, []byte("accounts: {}\n# edited\n"), "test edit"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Tests have no terminal to answer on, or only an empty one
+	root := newRootCmd()
+	root.SetOut(io.Discard)
+	if code := execute(root, []string{"config", "rollback", "--config", path}); code != 1 {
+		t.Errorf("expected exit code 1 without --yes, got %d", code)
+	}
+	if data, _ := os.ReadFile(path); !strings.Contains(string(data), "# edited") {
+		t.Errorf("expected the config unchanged without confirmation, got %q", data)
+	}
+
+	root = newRootCmd()
+	root.SetOut(io.Discard)
+	if code := execute(root, []string{"config", "rollback", "--config", path, "--yes"}); code != 0 {
+		t.Fatalf("expected exit code 0 with --yes, got %d", code)
+	}
+	if data, _ := os.ReadFile(path); strings.Contains(string(data), "# edited") {
+		t.Errorf("expected the edit reverted, got %q", data)
+	}
+}
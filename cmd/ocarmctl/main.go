@@ -0,0 +1,212 @@
+// Command ocarmctl manages a running oci-arm-provisioner through its management API
+// (api.listen in config.yaml): status, logs, pause, resume, trigger and reload.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yourusername/oci-arm-provisioner/internal/api"
+	"github.com/yourusername/oci-arm-provisioner/internal/config"
+	"github.com/yourusername/oci-arm-provisioner/internal/control"
+	"github.com/yourusername/oci-arm-provisioner/internal/logstore"
+)
+
+// followInterval is how often 'logs -f' asks for new lines.
+const followInterval = 2 * time.Second
+
+// usageError marks errors caused by how the command was invoked (exit code 2).
+type usageError struct{ error }
+
+func main() {
+	// 'logs -f' runs until Ctrl+C
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	root := newRootCmd()
+	root.SetContext(ctx)
+	code := execute(root, os.Args[1:])
+	stop()
+	os.Exit(code)
+}
+
+// execute runs the command tree and returns the process exit code.
+func execute(root *cobra.Command, args []string) int {
+	root.SetArgs(args)
+	cmd, err := root.ExecuteC()
+	if err == nil {
+		return 0
+	}
+	fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+	if errors.As(err, new(usageError)) {
+		fmt.Fprintf(os.Stderr, "Run '%s --help' for usage.\n", cmd.CommandPath())
+		return 2
+	}
+	return 1
+}
+
+// envOr returns the environment variable key, or def when it is unset.
+func envOr(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+// newRootCmd builds the command tree.
+func newRootCmd() *cobra.Command {
+	client := &api.Client{}
+	root := &cobra.Command{
+		Use:   "ocarmctl",
+		Short: "Manage a running oci-arm-provisioner",
+		Long: `ocarmctl talks to the management API of a running oci-arm-provisioner, so it can
+be checked and steered without attaching to its terminal or container.
+
+Enable the API with api.listen in the daemon's config.yaml.`,
+		Example: `  ocarmctl status
+  ocarmctl pause work          # pause one account
+  ocarmctl trigger             # run a cycle now
+  ocarmctl logs -f
+  ocarmctl --addr 10.0.0.5:8737 --token "$TOKEN" status`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	root.PersistentFlags().StringVar(&client.Addr, "addr", envOr("OCARMCTL_ADDR", config.DefaultAPIAddr), "daemon API address, host:port or URL (env OCARMCTL_ADDR)")
+	root.PersistentFlags().StringVar(&client.Token, "token", os.Getenv("OCARMCTL_TOKEN"), "API token, api.token in the daemon's config (env OCARMCTL_TOKEN)")
+	root.SetFlagErrorFunc(func(_ *cobra.Command, err error) error { return usageError{err} })
+
+	root.AddCommand(
+		newStatusCmd(client),
+		newLogsCmd(client),
+		newSendCmd(client, control.ActionPause, "Pause all accounts, or one", true),
+		newSendCmd(client, control.ActionResume, "Resume all accounts, or one", true),
+		newSendCmd(client, control.ActionTrigger, "Run a cycle now instead of waiting for the next one", false),
+		newSendCmd(client, control.ActionReload, "Re-read config.yaml (headless mode only)", false),
+	)
+	return root
+}
+
+func newStatusCmd(client *api.Client) *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Show the daemon's accounts and cycles",
+		Args:  noArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			s, err := client.Status(cmd.Context())
+			if err != nil {
+				return err
+			}
+			printStatus(cmd.OutOrStdout(), s)
+			return nil
+		},
+	}
+}
+
+// printStatus writes s as a table.
+func printStatus(out io.Writer, s api.Status) {
+	state := "running"
+	if s.Paused {
+		state = "paused"
+	}
+	fmt.Fprintf(out, "oci-arm-provisioner %s, %s, %d cycles since %s\n\n", s.Version, state, s.Cycles, s.Started.Local().Format(time.DateTime))
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ACCOUNT\tSTATE\tREGION\tATTEMPTS\tCAPACITY\tDETAIL")
+	for _, a := range s.Accounts {
+		detail := a.LastError
+		if a.State == api.StateProvisioned {
+			detail = a.InstanceID
+			if a.PublicIP != "" {
+				detail = a.PublicIP + " " + detail
+			}
+			for _, d := range a.Delivery {
+				detail += fmt.Sprintf(", %s %s", d.Provider, d.Status)
+			}
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%d\t%s\n", a.Name, a.State, a.Region, a.Attempts, a.CapacityErrors, detail)
+	}
+	tw.Flush()
+}
+
+func newLogsCmd(client *api.Client) *cobra.Command {
+	var n int
+	var follow bool
+	cmd := &cobra.Command{
+		Use:   "logs",
+		Short: "Print the daemon's recent log lines",
+		Args:  noArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if n <= 0 {
+				return usageError{fmt.Errorf("-n must be positive")}
+			}
+			entries, err := client.Logs(cmd.Context(), 0, n)
+			if err != nil {
+				return err
+			}
+			var seq int64
+			for {
+				for _, e := range entries {
+					fmt.Fprintf(cmd.OutOrStdout(), "%s [%s] %s: %s\n", e.Time.Local().Format(time.DateTime), e.Level, e.Account, e.Message)
+					seq = e.Seq
+				}
+				if !follow {
+					return nil
+				}
+				select {
+				case <-cmd.Context().Done():
+					return nil
+				case <-time.After(followInterval):
+				}
+				if entries, err = client.Logs(cmd.Context(), seq, logstore.Size); err != nil {
+					return err
+				}
+			}
+		},
+	}
+	cmd.Flags().IntVarP(&n, "lines", "n", 50, "number of lines to print")
+	cmd.Flags().BoolVarP(&follow, "follow", "f", false, "keep printing new lines")
+	return cmd
+}
+
+// newSendCmd builds a command that sends action to the daemon.
+func newSendCmd(client *api.Client, action, short string, perAccount bool) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   action,
+		Short: short,
+		Args:  noArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := control.Command{Action: action}
+			if len(args) == 1 {
+				c.Account = args[0]
+			}
+			if err := client.Send(cmd.Context(), c); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "✅ Sent %s; it runs between cycles\n", c.Action)
+			return nil
+		},
+	}
+	if perAccount {
+		cmd.Use = action + " [account]"
+		cmd.Args = func(_ *cobra.Command, args []string) error {
+			if len(args) > 1 {
+				return usageError{fmt.Errorf("%s takes at most one account", action)}
+			}
+			return nil
+		}
+	}
+	return cmd
+}
+
+// noArgs rejects positional arguments as a usage error.
+func noArgs(cmd *cobra.Command, args []string) error {
+	if len(args) > 0 {
+		return usageError{fmt.Errorf("%s takes no arguments", cmd.CommandPath())}
+	}
+	return nil
+}
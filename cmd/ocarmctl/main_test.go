@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/oci-arm-provisioner/internal/api"
+)
+
+func TestCommands(t *testing.T) {
+	var posted []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case api.PathStatus:
+			json.NewEncoder(w).Encode(api.Status{Version: "v1.0.0", Cycles: 7, Accounts: []api.Account{
+				{Name: "home", State: api.StateProvisioned, Region: "eu-frankfurt-1", PublicIP: "203.0.113.9", InstanceID: "ocid1.instance.oc1..a"},
+				{Name: "work", State: api.StateHunting, Region: "us-ashburn-1", Attempts: 12, CapacityErrors: 11, LastError: "Out of host capacity"},
+			}})
+		case api.PathCommands:
+			body, _ := io.ReadAll(r.Body)
+			posted = append(posted, string(body))
+			w.WriteHeader(http.StatusAccepted)
+			json.NewEncoder(w).Encode(api.Queued{Command: "pause"})
+		}
+	}))
+	defer ts.Close()
+
+	run := func(args ...string) (int, string) {
+		var out bytes.Buffer
+		root := newRootCmd()
+		root.SetOut(&out)
+		return execute(root, append([]string{"--addr", ts.URL}, args...)), out.String()
+	}
+
+	code, out := run("status")
+	if code != 0 || !strings.Contains(out, "7 cycles") || !strings.Contains(out, "203.0.113.9") || !strings.Contains(out, "Out of host capacity") {
+		t.Errorf("unexpected status output (exit %d):\n%s", code, out)
+	}
+
+	if code, _ := run("pause", "work"); code != 0 {
+		t.Errorf("pause: exit %d", code)
+	}
+	if code, _ := run("trigger"); code != 0 {
+		t.Errorf("trigger: exit %d", code)
+	}
+	if strings.Join(posted, "") != `{"action":"pause","account":"work"}{"action":"trigger"}` {
+		t.Errorf("unexpected commands %q", posted)
+	}
+
+	if code, _ := run("pause", "work", "home"); code != 2 {
+		t.Errorf("expected exit 2 for two accounts, got %d", code)
+	}
+	if code, _ := run("trigger", "work"); code != 2 {
+		t.Errorf("expected exit 2 for an account on trigger, got %d", code)
+	}
+}
@@ -0,0 +1,233 @@
+// Package api is the daemon's management API: status, recent log lines and the
+// control_dir actions over HTTP, for ocarmctl and scripts.
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/yourusername/oci-arm-provisioner/internal/buildinfo"
+	"github.com/yourusername/oci-arm-provisioner/internal/config"
+	"github.com/yourusername/oci-arm-provisioner/internal/control"
+	"github.com/yourusername/oci-arm-provisioner/internal/logstore"
+	"github.com/yourusername/oci-arm-provisioner/internal/provisioner"
+)
+
+// Routes of the API.
+const (
+	PathStatus   = "/v1/status"   // GET: Status.
+	PathLogs     = "/v1/logs"     // GET ?since=<seq>&n=<count>: Logs.
+	PathCommands = "/v1/commands" // POST a control file's JSON, e.g. {"action": "pause"}.
+)
+
+// Account states in Status.
+const (
+	StateHunting     = "hunting"
+	StatePaused      = "paused"
+	StateProvisioned = "provisioned"
+	StateLimited     = "limited" // Waiting for exhausted service limits; LastError says which.
+)
+
+// Status is the response of GET /v1/status.
+type Status struct {
+	Version  string    `json:"version"`
+	Started  time.Time `json:"started"`
+	Paused   bool      `json:"paused"` // All accounts, until resumed.
+	Cycles   int       `json:"cycles"`
+	Accounts []Account `json:"accounts"`
+}
+
+// Account is one enabled account in Status. The YAML tags are for status_dir files.
+type Account struct {
+	Name           string    `json:"name" yaml:"name"`
+	State          string    `json:"state" yaml:"state"`
+	Region         string    `json:"region" yaml:"region"`
+	Attempts       int       `json:"attempts" yaml:"attempts"`
+	LastAttempt    time.Time `json:"last_attempt,omitzero" yaml:"last_attempt,omitempty"`
+	CapacityErrors int       `json:"capacity_errors" yaml:"capacity_errors"`
+	LastError      string    `json:"last_error,omitempty" yaml:"last_error,omitempty"`
+	InstanceID     string    `json:"instance_id,omitempty" yaml:"instance_id,omitempty"`
+	PublicIP       string    `json:"public_ip,omitempty" yaml:"public_ip,omitempty"`
+	// Outcome of the success notification on each provider, once one was sent.
+	Delivery []Delivery `json:"delivery,omitempty" yaml:"delivery,omitempty"`
+}
+
+// Delivery is one provider's outcome of a success notification.
+type Delivery struct {
+	Provider string `json:"provider" yaml:"provider"`
+	Status   string `json:"status" yaml:"status"` // delivered, acknowledged or failed
+}
+
+// Logs is the response of GET /v1/logs.
+type Logs struct {
+	Entries []logstore.Entry `json:"entries"`
+}
+
+// Queued is the response to a command; it runs between cycles.
+type Queued struct {
+	Command string `json:"queued"`
+}
+
+// errorBody is the response of a failed request.
+type errorBody struct {
+	Error string `json:"error"`
+}
+
+// Snapshot returns the status of p. It only reads state that is safe to read while p
+// runs a cycle.
+func Snapshot(p *provisioner.Provisioner, paused bool) Status {
+	stats := p.Tracker.Snapshot()
+	s := Status{
+		Version:  buildinfo.String(),
+		Started:  stats.StartTime,
+		Paused:   paused,
+		Cycles:   stats.TotalCycles,
+		Accounts: []Account{},
+	}
+	var names []string
+	for name, acc := range p.Config.Accounts {
+		if acc.Enabled {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		st := stats.Accounts[name]
+		a := Account{
+			Name:           name,
+			State:          StateHunting,
+			Region:         p.Config.Accounts[name].Region,
+			Attempts:       st.Attempts,
+			LastAttempt:    st.LastAttempt,
+			CapacityErrors: st.CapacityErrors,
+			LastError:      st.LastError,
+		}
+		if st.Limited != "" {
+			a.State, a.LastError = StateLimited, "service limit exhausted: "+st.Limited
+		}
+		if p.IsAccountPaused(name) {
+			a.State = StatePaused
+		}
+		if instances := p.State.Instances(name); len(instances) > 0 {
+			last := instances[len(instances)-1]
+			a.State, a.InstanceID, a.PublicIP = StateProvisioned, last.ID, last.PublicIP
+		}
+		if receipt, ok := p.State.Notification(name); ok {
+			for _, d := range receipt.Deliveries {
+				a.Delivery = append(a.Delivery, Delivery{Provider: d.Provider, Status: d.Status})
+			}
+		}
+		s.Accounts = append(s.Accounts, a)
+	}
+	return s
+}
+
+// server answers the API requests.
+type server struct {
+	token    string
+	status   func() Status
+	logs     *logstore.Store
+	commands chan control.Command
+}
+
+// Serve listens on cfg.Listen until ctx is done and returns the commands clients send,
+// to be applied like control_dir commands. The channel is closed once the server stopped.
+func Serve(ctx context.Context, cfg config.APIConfig, status func() Status, logs *logstore.Store, onError func(error)) <-chan control.Command {
+	s := &server{token: cfg.Token, status: status, logs: logs, commands: make(chan control.Command, 16)}
+	ln, err := net.Listen("tcp", cfg.Listen)
+	if err != nil {
+		onError(err)
+		close(s.commands)
+		return s.commands
+	}
+	srv := &http.Server{Handler: s.handler(), ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			onError(err)
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+		close(s.commands)
+	}()
+	return s.commands
+}
+
+// handler routes the API and checks the token.
+func (s *server) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+PathStatus, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, s.status())
+	})
+	mux.HandleFunc("GET "+PathLogs, s.serveLogs)
+	mux.HandleFunc("POST "+PathCommands, s.serveCommand)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.token != "" && subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+s.token)) != 1 {
+			writeJSON(w, http.StatusUnauthorized, errorBody{"missing or wrong token"})
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+func (s *server) serveLogs(w http.ResponseWriter, r *http.Request) {
+	since, n := int64(0), 100
+	if v := r.URL.Query().Get("since"); v != "" {
+		var err error
+		if since, err = strconv.ParseInt(v, 10, 64); err != nil {
+			writeJSON(w, http.StatusBadRequest, errorBody{"since must be a sequence number"})
+			return
+		}
+	}
+	if v := r.URL.Query().Get("n"); v != "" {
+		var err error
+		if n, err = strconv.Atoi(v); err != nil || n <= 0 {
+			writeJSON(w, http.StatusBadRequest, errorBody{"n must be a positive number"})
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, Logs{Entries: s.logs.Since(since, n)})
+}
+
+func (s *server) serveCommand(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, 4096))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorBody{err.Error()})
+		return
+	}
+	cmd, err := control.Parse(body)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorBody{err.Error()})
+		return
+	}
+	if cmd.Account != "" && !slices.ContainsFunc(s.status().Accounts, func(a Account) bool { return a.Name == cmd.Account }) {
+		writeJSON(w, http.StatusNotFound, errorBody{fmt.Sprintf("unknown account '%s'", cmd.Account)})
+		return
+	}
+	cmd.File = "api"
+	select {
+	case s.commands <- cmd:
+		writeJSON(w, http.StatusAccepted, Queued{Command: cmd.Action})
+	default:
+		writeJSON(w, http.StatusServiceUnavailable, errorBody{"too many commands waiting for the current cycle"})
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,102 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/oci-arm-provisioner/internal/config"
+	"github.com/yourusername/oci-arm-provisioner/internal/control"
+	"github.com/yourusername/oci-arm-provisioner/internal/logstore"
+)
+
+// newTestServer serves the API with a fixed status and returns a client for it.
+func newTestServer(t *testing.T, token string) (*server, *Client) {
+	s := &server{
+		token: token,
+		status: func() Status {
+			return Status{Cycles: 3, Accounts: []Account{{Name: "work", State: StateHunting, Delivery: []Delivery{{Provider: "ntfy", Status: "acknowledged"}}}}}
+		},
+		logs:     logstore.New(logstore.Size),
+		commands: make(chan control.Command, 1),
+	}
+	ts := httptest.NewServer(s.handler())
+	t.Cleanup(ts.Close)
+	return s, &Client{Addr: ts.URL, Token: token}
+}
+
+func TestServer(t *testing.T) {
+	ctx := context.Background()
+	s, client := newTestServer(t, "secret")
+
+	status, err := client.Status(ctx)
+	if err != nil || status.Cycles != 3 || len(status.Accounts) != 1 {
+		t.Fatalf("unexpected status %+v, %v", status, err)
+	}
+	if d := status.Accounts[0].Delivery; len(d) != 1 || d[0] != (Delivery{Provider: "ntfy", Status: "acknowledged"}) {
+		t.Errorf("unexpected delivery %+v", d)
+	}
+
+	if err := client.Send(ctx, control.Command{Action: control.ActionPause, Account: "work"}); err != nil {
+		t.Fatal(err)
+	}
+	if cmd := <-s.commands; cmd.Action != control.ActionPause || cmd.Account != "work" || cmd.File != "api" {
+		t.Errorf("unexpected command %+v", cmd)
+	}
+
+	for cmd, want := range map[control.Command]string{
+		{Action: control.ActionPause, Account: "home"}: "404",
+		{Action: "stop"}: "400",
+		{Action: control.ActionTrigger, Account: "work"}: "only applies to pause and resume",
+	} {
+		if err := client.Send(ctx, cmd); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%+v: expected %q, got %v", cmd, want, err)
+		}
+	}
+
+	// The queue is full until the loop takes a command
+	client.Send(ctx, control.Command{Action: control.ActionTrigger})
+	if err := client.Send(ctx, control.Command{Action: control.ActionTrigger}); err == nil || !strings.Contains(err.Error(), "503") {
+		t.Errorf("expected 503 with a full queue, got %v", err)
+	}
+
+	wrong := &Client{Addr: client.Addr, Token: "guess"}
+	if _, err := wrong.Status(ctx); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("expected 401 with a wrong token, got %v", err)
+	}
+}
+
+func TestLogs(t *testing.T) {
+	ctx := context.Background()
+	s, client := newTestServer(t, "")
+	for i := 1; i <= logstore.Size+5; i++ {
+		s.logs.Add("info", "work", fmt.Sprintf("line %d", i))
+	}
+
+	entries, err := client.Logs(ctx, 0, 2)
+	if err != nil || len(entries) != 2 || entries[1].Message != fmt.Sprintf("line %d", logstore.Size+5) {
+		t.Fatalf("expected the two newest lines, got %+v, %v", entries, err)
+	}
+
+	entries, _ = client.Logs(ctx, 0, 2*logstore.Size)
+	if len(entries) != logstore.Size || entries[0].Seq != 6 {
+		t.Errorf("expected the oldest lines dropped, got %d from seq %d", len(entries), entries[0].Seq)
+	}
+
+	last := entries[len(entries)-1].Seq
+	s.logs.Add("warn", "work", "new")
+	entries, _ = client.Logs(ctx, last, 10)
+	if len(entries) != 1 || entries[0].Message != "new" {
+		t.Errorf("expected only the new line, got %+v", entries)
+	}
+}
+
+func TestServe_ListenError(t *testing.T) {
+	var failed error
+	commands := Serve(context.Background(), config.APIConfig{Listen: "256.0.0.1:1"}, nil, logstore.New(logstore.Size), func(err error) { failed = err })
+	if _, open := <-commands; open || failed == nil {
+		t.Errorf("expected a closed channel and an error, got %v", failed)
+	}
+}
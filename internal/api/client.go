@@ -0,0 +1,92 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/yourusername/oci-arm-provisioner/internal/control"
+	"github.com/yourusername/oci-arm-provisioner/internal/logstore"
+)
+
+// Client talks to a daemon's API.
+type Client struct {
+	Addr  string // host:port, or a URL for an API behind a proxy.
+	Token string
+	HTTP  *http.Client // Default: 10s timeout.
+}
+
+// Status returns the daemon's status.
+func (c *Client) Status(ctx context.Context) (Status, error) {
+	var s Status
+	err := c.do(ctx, http.MethodGet, PathStatus, nil, &s)
+	return s, err
+}
+
+// Logs returns the log lines after seq, at most the n newest.
+func (c *Client) Logs(ctx context.Context, seq int64, n int) ([]logstore.Entry, error) {
+	var l Logs
+	err := c.do(ctx, http.MethodGet, fmt.Sprintf("%s?since=%d&n=%d", PathLogs, seq, n), nil, &l)
+	return l.Entries, err
+}
+
+// Send queues a command; the daemon runs it between cycles.
+func (c *Client) Send(ctx context.Context, cmd control.Command) error {
+	return c.do(ctx, http.MethodPost, PathCommands, cmd, &Queued{})
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body, out any) error {
+	base := c.Addr
+	if !strings.Contains(base, "://") {
+		base = "http://" + base
+	}
+	u, err := url.Parse(strings.TrimRight(base, "/") + path)
+	if err != nil {
+		return err
+	}
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	client := c.HTTP
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		if errors.Is(err, syscall.ECONNREFUSED) {
+			return fmt.Errorf("no daemon API at %s (set api.listen in config.yaml and restart the daemon)", c.Addr)
+		}
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		var e errorBody
+		if json.NewDecoder(resp.Body).Decode(&e) == nil && e.Error != "" {
+			return fmt.Errorf("%s: %s", resp.Status, e.Error)
+		}
+		return fmt.Errorf("%s", resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,43 @@
+// Package buildinfo exposes the running version to the packages that report it
+// (OCI request headers, logs and notifications).
+package buildinfo
+
+import "fmt"
+
+// Product is the tool name used in User-Agent and opc-client-info headers.
+const Product = "oci-arm-provisioner"
+
+// Version is set by main at startup from the -ldflags version.
+var Version = "dev"
+
+// String returns the version for display, e.g. "v0.2.1".
+func String() string {
+	if Version == "dev" || Version == "" {
+		return "dev"
+	}
+	return "v" + Version
+}
+
+// ClientInfo is the opc-client-info header value, e.g. "oci-arm-provisioner/0.2.1".
+func ClientInfo() string {
+	return Product + "/" + Version
+}
+
+// UserAgent is the product token appended to the OCI SDK User-Agent. The install ID is
+// random per deployment (see state.InstallID), so it identifies nothing but the install.
+func UserAgent(installID, extra string) string {
+	comment := "install " + installID
+	if installID == "" {
+		comment = ""
+	}
+	if extra != "" {
+		if comment != "" {
+			comment += "; "
+		}
+		comment += extra
+	}
+	if comment == "" {
+		return ClientInfo()
+	}
+	return fmt.Sprintf("%s (%s)", ClientInfo(), comment)
+}
@@ -0,0 +1,25 @@
+package buildinfo
+
+import "testing"
+
+func TestUserAgent(t *testing.T) {
+	Version = "1.2.3"
+	defer func() { Version = "dev" }()
+
+	cases := []struct {
+		installID, extra, want string
+	}{
+		{"", "", "oci-arm-provisioner/1.2.3"},
+		{"a1b2c3d4", "", "oci-arm-provisioner/1.2.3 (install a1b2c3d4)"},
+		{"a1b2c3d4", "homelab", "oci-arm-provisioner/1.2.3 (install a1b2c3d4; homelab)"},
+		{"", "homelab", "oci-arm-provisioner/1.2.3 (homelab)"},
+	}
+	for _, c := range cases {
+		if got := UserAgent(c.installID, c.extra); got != c.want {
+			t.Errorf("UserAgent(%q, %q) = %q, want %q", c.installID, c.extra, got, c.want)
+		}
+	}
+	if String() != "v1.2.3" {
+		t.Errorf("expected v1.2.3, got %s", String())
+	}
+}
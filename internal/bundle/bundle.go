@@ -0,0 +1,278 @@
+// Package bundle builds the `debug bundle` tarball attached to bug reports: redacted
+// config, recent logs, attempt history, version info and a status snapshot.
+package bundle
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yourusername/oci-arm-provisioner/internal/buildinfo"
+	"github.com/yourusername/oci-arm-provisioner/internal/config"
+	"github.com/yourusername/oci-arm-provisioner/internal/notifier"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultLogLines is how much of provisioner.log goes into a bundle.
+const DefaultLogLines = 2000
+
+const redacted = "<redacted>"
+
+// secretKeys are config keys whose values never leave the machine.
+var secretKeys = map[string]bool{
+	"user_ocid":                true,
+	"tenancy_ocid":             true,
+	"fingerprint":              true,
+	"key_content":              true, // The private key PEM itself
+	"key_env":                  true,
+	"key_vault_secret_ocid":    true,
+	"compartment_ocid":         true,
+	"subnet_ocid":              true,
+	"ssh_public_key":           true,
+	"cloud_init_vars":          true,
+	"webhook_url":              true,
+	"telegram_token":           true,
+	"telegram_chat_id":         true,
+	"ntfy_topic":               true,
+	"gotify_url":               true,
+	"teams_webhook_url":        true,
+	"signal_url":               true,
+	"signal_number":            true,
+	"signal_recipients":        true,
+	"whatsapp_token":           true,
+	"whatsapp_phone_number_id": true,
+	"whatsapp_recipient":       true,
+	"gotify_token":             true,
+	"redis_url":                true,
+	"username":                 true, // mqtt
+	"password":                 true, // mqtt
+	"token":                    true, // api
+	"url":                      true, // ip_hook
+	"command":                  true, // ip_hook; may embed tokens
+	"par_url":                  true, // success_report; the URL is the credential
+	"proxy_url":                true, // may embed the proxy's password
+}
+
+var (
+	// ocidPattern keeps the resource type and region of an OCID and drops its unique part.
+	ocidPattern = regexp.MustCompile(`(ocid1\.[a-z0-9]+\.[a-z0-9-]+\.[a-z0-9-]*)\.[a-z0-9._-]+`)
+	ipv4Pattern = regexp.MustCompile(`\b\d{1,3}\.\d{1,3}\.\d{1,3}\.\d{1,3}\b`)
+	ansiPattern = regexp.MustCompile(`\x1b\[[0-9;?]*[a-zA-Z]`)
+)
+
+// Options selects what goes into a bundle. Empty fields are skipped.
+type Options struct {
+	Config    []byte          // Raw config.yaml; redacted before it is added.
+	StateFile string          // state.json
+	LogDir    string          // Directory containing provisioner.log.
+	LogLines  int             // Tail length of the log (default DefaultLogLines).
+	Status    string          // Rendered status snapshot (e.g. the TUI screen).
+	Stats     *notifier.Stats // Attempt history of the running process, if any.
+	Now       time.Time       // Bundle timestamp (default time.Now).
+}
+
+// RedactText removes terminal colors, OCID unique parts and IP addresses from free text
+// (logs, status).
+func RedactText(s string) string {
+	s = ansiPattern.ReplaceAllString(s, "")
+	s = ocidPattern.ReplaceAllString(s, "$1."+redacted)
+	return ipv4Pattern.ReplaceAllString(s, "x.x.x.x")
+}
+
+// RedactConfig blanks credentials and notification secrets in a config file while
+// keeping its structure, so the settings themselves can still be reviewed.
+func RedactConfig(data []byte) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("error parsing yaml: %w", err)
+	}
+	redactNode(&doc)
+	var out bytes.Buffer
+	enc := yaml.NewEncoder(&out)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, err
+	}
+	return []byte(RedactText(out.String())), nil
+}
+
+func redactNode(n *yaml.Node) {
+	if n.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			if secretKeys[n.Content[i].Value] {
+				blank(n.Content[i+1])
+				continue
+			}
+			redactNode(n.Content[i+1])
+		}
+		return
+	}
+	for _, c := range n.Content {
+		redactNode(c)
+	}
+}
+
+// blank replaces every non-empty scalar under n with a placeholder.
+func blank(n *yaml.Node) {
+	if n.Kind == yaml.ScalarNode {
+		if n.Value != "" {
+			n.Value, n.Tag, n.Style = redacted, "!!str", 0
+		}
+		return
+	}
+	for i, c := range n.Content {
+		if n.Kind == yaml.MappingNode && i%2 == 0 {
+			continue // keep map keys, e.g. cloud_init_vars names
+		}
+		blank(c)
+	}
+}
+
+// Status renders a plain-text overview of the configured accounts, for bundles made
+// outside the TUI.
+func Status(cfg *config.Config) string {
+	names := make([]string, 0, len(cfg.Accounts))
+	for name := range cfg.Accounts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-16s %-8s %-16s %-22s %-6s %-6s %s\n", "ACCOUNT", "ENABLED", "REGION", "SHAPE", "OCPUS", "MEM", "AD")
+	for _, name := range names {
+		acc := cfg.Accounts[name]
+		fmt.Fprintf(&b, "%-16s %-8t %-16s %-22s %-6g %-6g %s\n",
+			name, acc.Enabled, acc.Region, acc.Shape, acc.OCPUs, acc.MemoryGB, acc.AvailabilityDomain)
+	}
+	fmt.Fprintf(&b, "\ncycle interval: %ds, account delay: %ds\n",
+		cfg.Scheduler.CycleIntervalSeconds, cfg.Scheduler.AccountDelaySeconds)
+	return b.String()
+}
+
+// Create writes a bundle named debug-bundle-<time>.tar.gz into dir and returns its path.
+func Create(dir string, opts Options) (string, error) {
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	path := filepath.Join(dir, "debug-bundle-"+opts.Now.Format("20060102-150405")+".tar.gz")
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return "", err
+	}
+	if err := Write(f, opts); err != nil {
+		f.Close()
+		os.Remove(path)
+		return "", err
+	}
+	return path, f.Close()
+}
+
+// file is one entry of the tarball.
+type file struct{ name, content string }
+
+// Write streams the bundle as a gzipped tarball to w.
+func Write(w io.Writer, opts Options) error {
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	if opts.LogLines <= 0 {
+		opts.LogLines = DefaultLogLines
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	add := func(name, content string) error {
+		hdr := &tar.Header{Name: name, Mode: 0600, Size: int64(len(content)), ModTime: opts.Now}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err := io.WriteString(tw, content)
+		return err
+	}
+
+	files := []file{{"version.txt", versionInfo(opts.Now)}}
+	if opts.Config != nil {
+		cfg, err := RedactConfig(opts.Config)
+		if err != nil {
+			// Still useful: say why instead of dropping it.
+			cfg = []byte(fmt.Sprintf("# config could not be parsed: %v\n", err))
+		}
+		files = append(files, file{"config.yaml", string(cfg)})
+	}
+	if opts.StateFile != "" {
+		if data, err := os.ReadFile(opts.StateFile); err == nil {
+			files = append(files, file{"state.json", RedactText(string(data))})
+		}
+	}
+	if opts.LogDir != "" {
+		if tail, err := tailFile(filepath.Join(opts.LogDir, "provisioner.log"), opts.LogLines); err == nil {
+			files = append(files, file{"provisioner.log", RedactText(tail)})
+		}
+	}
+	if opts.Stats != nil {
+		files = append(files, file{"attempts.txt", RedactText(attempts(*opts.Stats))})
+	}
+	if opts.Status != "" {
+		files = append(files, file{"status.txt", RedactText(opts.Status)})
+	}
+
+	for _, f := range files {
+		if err := add(f.name, f.content); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+func versionInfo(now time.Time) string {
+	return fmt.Sprintf("version: %s\nuser_agent: %s\ngo: %s\nplatform: %s/%s\ncreated: %s\n",
+		buildinfo.String(), buildinfo.UserAgent("", ""), runtime.Version(),
+		runtime.GOOS, runtime.GOARCH, now.UTC().Format(time.RFC3339))
+}
+
+// attempts renders the per-account counters of the running process.
+func attempts(s notifier.Stats) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "started: %s\ncycles: %d, capacity errors: %d, other errors: %d, successes: %d\n",
+		s.StartTime.UTC().Format(time.RFC3339), s.TotalCycles, s.CapacityErrors, s.OtherErrors, s.SuccessCount)
+	for _, name := range s.AccountNames() {
+		fmt.Fprintf(&b, "\n[%s]\n%s\n", name, s.Accounts[name].Summary())
+	}
+	return b.String()
+}
+
+// tailFile returns the last n lines of the file at path.
+func tailFile(path string, n int) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	lines := make([]string, 0, n)
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for sc.Scan() {
+		if len(lines) == n {
+			lines = lines[1:]
+		}
+		lines = append(lines, sc.Text())
+	}
+	if err := sc.Err(); err != nil {
+		return "", err
+	}
+	return strings.Join(lines, "\n") + "\n", nil
+}
@@ -0,0 +1,98 @@
+// Package cloudinit renders the optional first-boot presets (docker, k3s, tailscale...)
+// into a single user-data script passed to the instance at launch.
+package cloudinit
+
+import (
+	"bytes"
+	"embed"
+	"encoding/base64"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+//go:embed presets/*.sh
+var presetFS embed.FS
+
+// header runs before every preset. OCI platform images ship an iptables policy that
+// rejects everything but SSH, so presets open their ports with open_port.
+const header = `#!/bin/bash
+# Generated by oci-arm-provisioner
+set -euxo pipefail
+exec > >(tee -a /var/log/oci-arm-provisioner-init.log) 2>&1
+
+open_port() {
+  iptables -I INPUT 6 -m state --state NEW -p tcp --dport "$1" -j ACCEPT
+  netfilter-persistent save 2>/dev/null || service iptables save 2>/dev/null || true
+}
+`
+
+// Presets returns the names of the available presets, sorted.
+func Presets() []string {
+	entries, _ := presetFS.ReadDir("presets")
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, strings.TrimSuffix(e.Name(), ".sh"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Render concatenates the named presets into one script. Variables are looked up in
+// vars; a missing required variable or an unknown preset is an error.
+func Render(presets []string, vars map[string]string) (string, error) {
+	if len(presets) == 0 {
+		return "", nil
+	}
+
+	funcs := template.FuncMap{
+		"required": func(name string) (string, error) {
+			v, ok := vars[name]
+			if !ok || v == "" {
+				return "", fmt.Errorf("variable %s is required", name)
+			}
+			return shellQuote(v), nil
+		},
+		"optional": func(name, def string) string {
+			if v, ok := vars[name]; ok && v != "" {
+				return shellQuote(v)
+			}
+			return shellQuote(def)
+		},
+	}
+
+	var b bytes.Buffer
+	b.WriteString(header)
+	for _, name := range presets {
+		src, err := presetFS.ReadFile(path.Join("presets", name+".sh"))
+		if err != nil {
+			return "", fmt.Errorf("unknown preset %q (available: %s)", name, strings.Join(Presets(), ", "))
+		}
+		tmpl, err := template.New(name).Funcs(funcs).Parse(string(src))
+		if err != nil {
+			return "", fmt.Errorf("preset %s: %w", name, err)
+		}
+		b.WriteString("\n")
+		if err := tmpl.Execute(&b, nil); err != nil {
+			return "", fmt.Errorf("preset %s: %w", name, err)
+		}
+	}
+	return b.String(), nil
+}
+
+// UserData renders the presets and encodes them for the OCI "user_data" metadata key.
+// It returns "" when no presets are selected.
+func UserData(presets []string, vars map[string]string) (string, error) {
+	script, err := Render(presets, vars)
+	if err != nil || script == "" {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString([]byte(script)), nil
+}
+
+// shellQuote wraps s in single quotes so user values can't break out of the script.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'"'"'`) + "'"
+}
@@ -0,0 +1,61 @@
+package cloudinit
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func TestRender_AllPresets(t *testing.T) {
+	vars := map[string]string{"TAILSCALE_AUTHKEY": "tskey-abc"}
+	for _, name := range Presets() {
+		if _, err := Render([]string{name}, vars); err != nil {
+			t.Errorf("preset %s failed to render: %v", name, err)
+		}
+	}
+}
+
+func TestRender_Variables(t *testing.T) {
+	script, err := Render([]string{"docker", "tailscale"}, map[string]string{
+		"TAILSCALE_AUTHKEY": "tskey-'; rm -rf /",
+		"DOCKER_USER":       "opc",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(script, "#!/bin/bash") {
+		t.Error("expected a bash script")
+	}
+	if !strings.Contains(script, "usermod -aG docker 'opc'") {
+		t.Error("expected DOCKER_USER to override the default")
+	}
+	if !strings.Contains(script, `--authkey='tskey-'"'"'; rm -rf /'`) {
+		t.Errorf("expected the auth key to be shell-quoted, got:\n%s", script)
+	}
+}
+
+func TestRender_Errors(t *testing.T) {
+	if _, err := Render([]string{"tailscale"}, nil); err == nil || !strings.Contains(err.Error(), "TAILSCALE_AUTHKEY") {
+		t.Errorf("expected missing TAILSCALE_AUTHKEY error, got %v", err)
+	}
+	if _, err := Render([]string{"wordpress"}, nil); err == nil || !strings.Contains(err.Error(), "unknown preset") {
+		t.Errorf("expected unknown preset error, got %v", err)
+	}
+}
+
+func TestUserData(t *testing.T) {
+	if data, err := UserData(nil, nil); err != nil || data != "" {
+		t.Errorf("expected no user data without presets, got %q, %v", data, err)
+	}
+	data, err := UserData([]string{"k3s"}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	script, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		t.Fatalf("user data is not base64: %v", err)
+	}
+	if !strings.Contains(string(script), "get.k3s.io") {
+		t.Error("expected the k3s installer in the script")
+	}
+}
@@ -0,0 +1,6 @@
+# Docker Engine + compose plugin
+if ! command -v docker >/dev/null 2>&1; then
+  curl -fsSL https://get.docker.com | sh
+fi
+systemctl enable --now docker
+usermod -aG docker {{optional "DOCKER_USER" "ubuntu"}} || true
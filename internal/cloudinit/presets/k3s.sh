@@ -0,0 +1,3 @@
+# Single-node k3s (Kubernetes API on 6443)
+curl -sfL https://get.k3s.io | INSTALL_K3S_CHANNEL={{optional "K3S_CHANNEL" "stable"}} sh -s - --write-kubeconfig-mode 644
+open_port 6443
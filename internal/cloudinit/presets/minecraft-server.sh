@@ -0,0 +1,10 @@
+# Minecraft Java server (itzg/minecraft-server) on 25565
+if ! command -v docker >/dev/null 2>&1; then
+  curl -fsSL https://get.docker.com | sh
+fi
+systemctl enable --now docker
+docker run -d --name minecraft --restart unless-stopped \
+  -p 25565:25565 -v /srv/minecraft:/data \
+  -e EULA=TRUE -e VERSION={{optional "MINECRAFT_VERSION" "LATEST"}} -e MEMORY={{optional "MINECRAFT_MEMORY" "8G"}} \
+  itzg/minecraft-server
+open_port 25565
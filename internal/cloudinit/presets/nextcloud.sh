@@ -0,0 +1,14 @@
+# Nextcloud All-in-One (setup interface on 8080)
+if ! command -v docker >/dev/null 2>&1; then
+  curl -fsSL https://get.docker.com | sh
+fi
+systemctl enable --now docker
+docker run -d --name nextcloud-aio-mastercontainer --restart always \
+  -p 80:80 -p 8080:8080 -p 8443:8443 \
+  -e NEXTCLOUD_DATADIR={{optional "NEXTCLOUD_DATADIR" "/srv/nextcloud"}} \
+  -v nextcloud_aio_mastercontainer:/mnt/docker-aio-config \
+  -v /var/run/docker.sock:/var/run/docker.sock:ro \
+  nextcloud/all-in-one:latest
+open_port 80
+open_port 8080
+open_port 8443
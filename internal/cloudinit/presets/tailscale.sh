@@ -0,0 +1,3 @@
+# Tailscale (joins the tailnet with a pre-auth key, enables Tailscale SSH)
+curl -fsSL https://tailscale.com/install.sh | sh
+tailscale up --authkey={{required "TAILSCALE_AUTHKEY"}} --ssh
@@ -0,0 +1,264 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// AccountField is a key/value written into a new account entry.
+type AccountField struct {
+	Key   string
+	Value any
+}
+
+// HasDefaults reports whether the config file at path has a top-level 'defaults:' block.
+func HasDefaults(path string) (bool, error) {
+	doc, err := readNode(path)
+	if err != nil {
+		return false, err
+	}
+	return len(doc.Content) > 0 && mappingValue(doc.Content[0], "defaults") != nil, nil
+}
+
+// AddAccount adds a new account to the config file at path, in field order. The entry is
+// inserted as text right below 'accounts:' so the rest of the file (comments, spacing)
+// is left byte-for-byte intact. The change goes through Write.
+func AddAccount(path, name string, fields []AccountField, reason string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error reading config: %w", err)
+	}
+	doc, err := readNode(path)
+	if err != nil {
+		return err
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return fmt.Errorf("%s: expected a YAML mapping", path)
+	}
+	root := doc.Content[0]
+
+	// Locate the 'accounts:' key; without one, append it at the end of the file.
+	var key, accounts *yaml.Node
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == "accounts" {
+			key, accounts = root.Content[i], root.Content[i+1]
+		}
+	}
+	indent := 2
+	switch {
+	case accounts == nil:
+	case accounts.Kind == yaml.MappingNode && accounts.Style&yaml.FlowStyle == 0:
+		if mappingValue(accounts, name) != nil {
+			return fmt.Errorf("account '%s' already exists in %s", name, path)
+		}
+		if len(accounts.Content) > 0 {
+			indent = accounts.Content[0].Column - 1
+		}
+	case accounts.Kind == yaml.ScalarNode && accounts.Tag == "!!null" && accounts.Value == "":
+	default:
+		return fmt.Errorf("%s: 'accounts' must be a block mapping to add an account", path)
+	}
+
+	var block strings.Builder
+	pad := strings.Repeat(" ", indent)
+	fmt.Fprintf(&block, "%s%s:\n", pad, name)
+	for _, f := range fields {
+		value, err := f.encode()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&block, "%s%s%s: %s\n", pad, pad, f.Key, value)
+	}
+
+	content := string(data)
+	if key == nil {
+		if content != "" && !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		content += "\naccounts:\n" + block.String()
+	} else {
+		lines := strings.SplitAfter(content, "\n")
+		if !strings.HasSuffix(lines[key.Line-1], "\n") {
+			lines[key.Line-1] += "\n"
+		}
+		content = strings.Join(lines[:key.Line], "") + block.String() + strings.Join(lines[key.Line:], "")
+	}
+
+	// Make sure the result still parses before touching the file
+	var check Config
+	if err := yaml.Unmarshal([]byte(content), &check); err != nil {
+		return fmt.Errorf("adding account would break %s: %w", path, err)
+	}
+	if check.Accounts[name] == nil {
+		return fmt.Errorf("adding account to %s failed", path)
+	}
+	return Write(path, []byte(content), reason)
+}
+
+// SetAccountFields updates (or adds) fields of an existing account in the config file at
+// path. Like AddAccount it edits the text in place, so comments and spacing survive.
+func SetAccountFields(path, name string, fields []AccountField, reason string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error reading config: %w", err)
+	}
+	doc, err := readNode(path)
+	if err != nil {
+		return err
+	}
+	if len(doc.Content) == 0 {
+		return fmt.Errorf("%s: expected a YAML mapping", path)
+	}
+
+	var key, acc *yaml.Node
+	if accounts := mappingValue(doc.Content[0], "accounts"); accounts != nil && accounts.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(accounts.Content); i += 2 {
+			if accounts.Content[i].Value == name {
+				key, acc = accounts.Content[i], accounts.Content[i+1]
+			}
+		}
+	}
+	if acc == nil {
+		return fmt.Errorf("account '%s' not found in %s", name, path)
+	}
+	content, err := setFields(path, data, key, acc, fields, "account '"+name+"'")
+	if err != nil {
+		return err
+	}
+	return Write(path, []byte(content), reason)
+}
+
+// SetSchedulerFields updates (or adds) fields of the scheduler section in the config file
+// at path, adding the section if it is missing. Comments and spacing survive.
+func SetSchedulerFields(path string, fields []AccountField, reason string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error reading config: %w", err)
+	}
+	doc, err := readNode(path)
+	if err != nil {
+		return err
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return fmt.Errorf("%s: expected a YAML mapping", path)
+	}
+
+	root := doc.Content[0]
+	var content string
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == "scheduler" {
+			if content, err = setFields(path, data, root.Content[i], root.Content[i+1], fields, "scheduler"); err != nil {
+				return err
+			}
+		}
+	}
+	if content == "" {
+		var b strings.Builder
+		b.Write(data)
+		if len(data) > 0 && !strings.HasSuffix(string(data), "\n") {
+			b.WriteString("\n")
+		}
+		b.WriteString("\nscheduler:\n")
+		for _, f := range fields {
+			value, err := f.encode()
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(&b, "  %s: %s\n", f.Key, value)
+		}
+		content = b.String()
+	}
+	return Write(path, []byte(content), reason)
+}
+
+// setFields rewrites the single-line fields of the block mapping m (under key) in the
+// config text, and adds the missing ones right below key. what names m in errors.
+func setFields(path string, data []byte, key, m *yaml.Node, fields []AccountField, what string) (string, error) {
+	if m.Kind != yaml.MappingNode || m.Style&yaml.FlowStyle != 0 {
+		return "", fmt.Errorf("%s: %s must be a block mapping to edit it", path, what)
+	}
+
+	lines := strings.SplitAfter(string(data), "\n")
+	indent := key.Column + 1
+	if len(m.Content) > 0 {
+		indent = m.Content[0].Column - 1
+	}
+	var added strings.Builder
+	for _, f := range fields {
+		value, err := f.encode()
+		if err != nil {
+			return "", err
+		}
+
+		var k, v *yaml.Node
+		for i := 0; i+1 < len(m.Content); i += 2 {
+			if m.Content[i].Value == f.Key {
+				k, v = m.Content[i], m.Content[i+1]
+			}
+		}
+		if k == nil {
+			fmt.Fprintf(&added, "%s%s: %s\n", strings.Repeat(" ", indent), f.Key, value)
+			continue
+		}
+		if v.Kind != yaml.ScalarNode || v.Line != k.Line || v.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 {
+			return "", fmt.Errorf("%s: '%s' of %s must be a single-line value to edit it", path, f.Key, what)
+		}
+
+		// Rewrite the line, keeping its indentation and trailing comment
+		line := lines[k.Line-1]
+		newline := ""
+		if strings.HasSuffix(line, "\n") {
+			newline = "\n"
+		}
+		comment := ""
+		if v.LineComment != "" {
+			comment = " " + v.LineComment
+		}
+		lines[k.Line-1] = line[:k.Column-1] + f.Key + ": " + value + comment + newline
+	}
+	if added.Len() > 0 {
+		if !strings.HasSuffix(lines[key.Line-1], "\n") {
+			lines[key.Line-1] += "\n"
+		}
+		lines[key.Line-1] += added.String()
+	}
+	content := strings.Join(lines, "")
+
+	// Make sure the result still parses before touching the file
+	var check Config
+	if err := yaml.Unmarshal([]byte(content), &check); err != nil {
+		return "", fmt.Errorf("editing %s would break %s: %w", what, path, err)
+	}
+	return content, nil
+}
+
+// encode renders the field value as a single-line YAML scalar; strings are quoted.
+func (f AccountField) encode() (string, error) {
+	v := &yaml.Node{}
+	if err := v.Encode(f.Value); err != nil {
+		return "", fmt.Errorf("encoding %s: %w", f.Key, err)
+	}
+	if v.Tag == "!!str" {
+		v.Style = yaml.DoubleQuotedStyle
+	}
+	out, err := yaml.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("encoding %s: %w", f.Key, err)
+	}
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
+
+func readNode(path string) (*yaml.Node, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading config: %w", err)
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("error parsing yaml: %w", err)
+	}
+	return &doc, nil
+}
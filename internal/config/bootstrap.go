@@ -0,0 +1,82 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Values of the auth option.
+const (
+	AuthAPIKey            = "api_key"
+	AuthInstancePrincipal = "instance_principal"
+	AuthSecurityToken     = "security_token"
+)
+
+// AuthModes are the accepted values of auth; empty means api_key.
+var AuthModes = []string{AuthAPIKey, AuthInstancePrincipal, AuthSecurityToken}
+
+// BootstrapConfig hands off from the free x86 micro instance the tool runs on to the A1
+// instance it launched: run a migration command, then terminate the x86 host.
+type BootstrapConfig struct {
+	MigrateCommand     string `yaml:"migrate_command,omitempty"`       // Run through the shell with OCI_ACCOUNT, OCI_INSTANCE_ID, OCI_PUBLIC_IP, OCI_PRIVATE_IP and OCI_ATTEMPT_ID set.
+	Timeout            string `yaml:"timeout,omitempty"`               // Limit on migrate_command, e.g. "1h"; default 30m (DefaultBootstrapTimeout).
+	TerminateHost      bool   `yaml:"terminate_host,omitempty"`        // Terminate this host once the command succeeded.
+	KeepHostBootVolume bool   `yaml:"keep_host_boot_volume,omitempty"` // Keep the host's boot volume when terminating it.
+}
+
+// DefaultBootstrapTimeout bounds migrate_command when timeout is not set.
+const DefaultBootstrapTimeout = 30 * time.Minute
+
+// Enabled reports whether anything runs after the launch.
+func (b BootstrapConfig) Enabled() bool {
+	return b.MigrateCommand != "" || b.TerminateHost
+}
+
+// TimeoutDuration returns the limit on migrate_command.
+func (b BootstrapConfig) TimeoutDuration() time.Duration {
+	if d, err := time.ParseDuration(b.Timeout); err == nil && d > 0 {
+		return d
+	}
+	return DefaultBootstrapTimeout
+}
+
+// UsesInstancePrincipal reports whether the account authenticates as the instance the
+// tool runs on rather than with an API key.
+func (a *AccountConfig) UsesInstancePrincipal() bool {
+	return a.Auth == AuthInstancePrincipal
+}
+
+// UsesAPIKey reports whether the account signs with its key and the identity fields.
+func (a *AccountConfig) UsesAPIKey() bool {
+	return a.Auth == "" || a.Auth == AuthAPIKey
+}
+
+// validateBootstrap checks the account's auth mode and bootstrap block.
+func validateBootstrap(acc *AccountConfig) error {
+	if acc.Auth != "" && !slices.Contains(AuthModes, acc.Auth) {
+		return fmt.Errorf("auth must be one of %s, got '%s'", strings.Join(AuthModes, ", "), acc.Auth)
+	}
+	if !acc.UsesAPIKey() && (acc.KeyFile != "" || acc.HasInlineKey() || acc.KeyVaultSecretOCID != "" || acc.CredentialsDir != "") {
+		return fmt.Errorf("auth: %s does not use key_file, key_content, key_env, key_vault_secret_ocid or credentials_dir", acc.Auth)
+	}
+	if acc.UsesInstancePrincipal() && (acc.OCIProfile != "" || acc.OCIConfigFile != "") {
+		return fmt.Errorf("auth: %s does not use oci_profile or oci_config_file", acc.Auth)
+	}
+
+	b := acc.Bootstrap
+	if b.Timeout != "" {
+		if d, err := time.ParseDuration(b.Timeout); err != nil || d <= 0 {
+			return fmt.Errorf("bootstrap: timeout must be a duration like 1h, got '%s'", b.Timeout)
+		}
+	}
+	if !b.Enabled() && (b.Timeout != "" || b.KeepHostBootVolume) {
+		return fmt.Errorf("bootstrap: set migrate_command or terminate_host")
+	}
+	// Only an instance principal is sure to be the host's own tenancy
+	if b.Enabled() && !acc.UsesInstancePrincipal() {
+		return fmt.Errorf("bootstrap: requires auth: instance_principal")
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package config
+
+import (
+	"bufio"
+	"crypto/md5"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Files of a credentials_dir. The metadata is the "Configuration file preview" the OCI
+// Console shows after adding an API key (an OCI CLI config profile); its key_file line,
+// if any, is ignored.
+const (
+	CredentialsKeyFile  = "key.pem"
+	CredentialsMetadata = "config"
+)
+
+// applyCredentialsDir loads an account's key and identity from its credentials_dir,
+// after checking the directory and both files as a unit: owned by the current user (or
+// root, for read-only mounts) and not writable by anyone else.
+func applyCredentialsDir(acc *AccountConfig) error {
+	dir := expandHome(acc.CredentialsDir)
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	acc.CredentialsDir = dir
+	if acc.KeyFile != "" {
+		return errors.New("set either key_file or credentials_dir, not both")
+	}
+	if acc.OCIProfile != "" || acc.OCIConfigFile != "" {
+		return errors.New("set either oci_profile or credentials_dir, not both")
+	}
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("credentials_dir: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("credentials_dir: %s is not a directory", dir)
+	}
+	keyFile := filepath.Join(dir, CredentialsKeyFile)
+	metadata := filepath.Join(dir, CredentialsMetadata)
+	for _, path := range []string{dir, keyFile, metadata} {
+		if err := checkCredentialsFile(path); err != nil {
+			return fmt.Errorf("credentials_dir: %w", err)
+		}
+	}
+
+	profile, err := readOCIProfile(metadata, "")
+	if err != nil {
+		return fmt.Errorf("credentials_dir: %s: %w", metadata, err)
+	}
+	for _, f := range []struct {
+		name  string
+		field *string
+		value string
+	}{
+		{"user_ocid", &acc.UserOCID, profile["user"]},
+		{"tenancy_ocid", &acc.TenancyOCID, profile["tenancy"]},
+		{"fingerprint", &acc.Fingerprint, profile["fingerprint"]},
+		{"region", &acc.Region, profile["region"]},
+	} {
+		if f.value == "" {
+			continue
+		}
+		if *f.field != "" && *f.field != f.value {
+			return fmt.Errorf("credentials_dir: %s is %q in %s but %q in the config", f.name, f.value, metadata, *f.field)
+		}
+		*f.field = f.value
+	}
+
+	// A key that doesn't match the fingerprint would fail every request with a 401
+	fingerprint, err := keyFingerprint(keyFile)
+	if err != nil {
+		return fmt.Errorf("credentials_dir: %s: %w", keyFile, err)
+	}
+	if acc.Fingerprint != "" && !strings.EqualFold(acc.Fingerprint, fingerprint) {
+		return fmt.Errorf("credentials_dir: %s has fingerprint %s, but the account expects %s", keyFile, fingerprint, acc.Fingerprint)
+	}
+	acc.KeyFile = keyFile
+	return nil
+}
+
+// readOCIProfile returns the keys of the named profile of an OCI CLI config file, or of
+// the first one if name is empty.
+func readOCIProfile(path, name string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	profile := make(map[string]string)
+	sections, matched := 0, name == ""
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+			continue
+		case strings.HasPrefix(line, "["):
+			sections++
+			if name == "" {
+				if sections > 1 {
+					return profile, nil
+				}
+				continue
+			}
+			if matched {
+				return profile, nil
+			}
+			matched = strings.TrimSpace(strings.Trim(line, "[]")) == name
+			continue
+		}
+		if !matched {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("unexpected line %q", line)
+		}
+		if i := strings.Index(value, " #"); i >= 0 {
+			value = value[:i]
+		}
+		profile[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if !matched {
+		return nil, fmt.Errorf("no profile [%s]", name)
+	}
+	return profile, nil
+}
+
+// keyFingerprint returns the OCI fingerprint of an RSA private key: the MD5 of its
+// public key, as colon-separated hex.
+func keyFingerprint(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return "", errors.New("no PEM block")
+	}
+	var key *rsa.PrivateKey
+	if k, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		key = k
+	} else if k8, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		key, _ = k8.(*rsa.PrivateKey)
+	}
+	if key == nil {
+		return "", errors.New("not an RSA private key")
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return "", err
+	}
+	sum := md5.Sum(der)
+	hex := make([]string, len(sum))
+	for i, b := range sum {
+		hex[i] = fmt.Sprintf("%02x", b)
+	}
+	return strings.Join(hex, ":"), nil
+}
+
+// expandHome replaces a leading "~" or "~/" with the user's home directory. Other
+// users' homes ("~bob/...") are left alone.
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") && !strings.HasPrefix(path, "~"+string(filepath.Separator)) {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[1:])
+}
@@ -0,0 +1,27 @@
+//go:build !windows
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// checkCredentialsFile rejects a credentials file or directory that someone other than
+// the current user (or root) owns or can write to, since they could swap the key.
+func checkCredentialsFile(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if info.Mode().Perm()&0022 != 0 {
+		return fmt.Errorf("%s is writable by group or others (%o); run chmod go-w", path, info.Mode().Perm())
+	}
+	if st, ok := info.Sys().(*syscall.Stat_t); ok {
+		if uid := int(st.Uid); uid != os.Getuid() && uid != 0 {
+			return fmt.Errorf("%s is owned by uid %d, not by you (uid %d) or root", path, uid, os.Getuid())
+		}
+	}
+	return nil
+}
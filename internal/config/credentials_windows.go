@@ -0,0 +1,10 @@
+package config
+
+import "os"
+
+// checkCredentialsFile only checks that path exists; Windows protects files with ACLs,
+// which the mode bits don't reflect.
+func checkCredentialsFile(path string) error {
+	_, err := os.Stat(path)
+	return err
+}
@@ -0,0 +1,52 @@
+package config
+
+// Demo returns the built-in config of --demo: three accounts in different regions, hunting
+// against a simulated OCI with short timings, and notifications that only show up in the
+// log. Nothing is read from or written to disk.
+func Demo() *Config {
+	account := func(notes, region, ad string, ocpus, memory float32) *AccountConfig {
+		return &AccountConfig{
+			Enabled:            true,
+			Notes:              notes,
+			UserOCID:           "ocid1.user.oc1..demo",
+			TenancyOCID:        "ocid1.tenancy.oc1..demo-" + region,
+			Fingerprint:        "00:00:00:00:00:00:00:00:00:00:00:00:00:00:00:00",
+			Region:             region,
+			CompartmentOCID:    "ocid1.compartment.oc1..demo-" + region,
+			AvailabilityDomain: ad,
+			SubnetOCID:         "ocid1.subnet.oc1." + region + ".demo",
+			ImageOCID:          "ocid1.image.oc1." + region + ".demo",
+			SSHPublicKey:       "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIDemoDemoDemoDemoDemoDemoDemoDemoDemoDemo demo",
+			Shape:              "VM.Standard.A1.Flex",
+			OCPUs:              ocpus,
+			MemoryGB:           memory,
+			InstanceCount:      1,
+			BootVolumeSizeGB:   50,
+			DisplayName:        "arm-{{.Account}}-{{.Seq}}",
+			FallbackAfter:      DefaultFallbackAfter,
+		}
+	}
+
+	cfg := &Config{
+		Demo: true,
+		Accounts: map[string]*AccountConfig{
+			"personal": account("demo, rotating ADs", "eu-frankfurt-1", "auto", 4, 24),
+			"family":   account("demo", "us-ashburn-1", "auto", 2, 12),
+			"work":     account("demo, fixed AD", "uk-london-1", "Uocm:UK-LONDON-1-AD-1", 2, 12),
+		},
+		Scheduler: SchedulerConfig{AccountDelaySeconds: 4, CycleIntervalSeconds: 30, PollIntervalSeconds: 1, JitterPercent: 10},
+		Timeouts:  TimeoutsConfig{LaunchSeconds: DefaultLaunchSeconds, VerifyMinutes: DefaultVerifyMinutes, PollIntervalSeconds: 1},
+		Retry:     RetryConfig{BaseIntervalMinutes: 15, MaxIntervalMinutes: 120, BreakerThreshold: 5, BreakerCooldownMinutes: 30, ThrottleRatio: 0.5},
+		Logging:   LoggingConfig{LogDir: "logs", ConsoleFormat: "pretty"},
+		MQTT:      MQTTConfig{TopicPrefix: "oci-arm-provisioner"},
+	}
+	cfg.Accounts["personal"].TryAllADs = true
+	n := &cfg.Notifications
+	n.Enabled = true
+	n.NtfyTopic = "oci-arm-provisioner-demo" // Delivered to the log, see provisioner's demo
+	n.WebhookFormat = "discord"
+	n.AnnouncementInterval = "6h"
+	n.WhatsAppTemplate = "oci_instance_ready"
+	n.WhatsAppLanguage = "en_US"
+	return cfg
+}
@@ -0,0 +1,148 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix starts the environment variables that override config fields: the YAML path
+// in upper case, joined by '_', e.g. OCIARM_SCHEDULER_CYCLE_INTERVAL_SECONDS=300 or
+// OCIARM_ACCOUNTS_PERSONAL_REGION=eu-frankfurt-1.
+const EnvPrefix = "OCIARM_"
+
+// envName is how a YAML key or account name appears in a variable name.
+func envName(key string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		}
+		return '_'
+	}, key)
+}
+
+// applyEnvOverrides sets the fields named by OCIARM_* variables in the config's node tree,
+// before 'defaults:' is merged and the config is decoded and validated. Lists and maps
+// take YAML flow syntax, e.g. OCIARM_ACCOUNTS_WORK_CLOUD_INIT='[docker, tailscale]'.
+// Variables that name no field are errors, so a typo doesn't go unnoticed.
+func applyEnvOverrides(doc *yaml.Node, environ []string) error {
+	if doc.Kind != yaml.DocumentNode {
+		return nil
+	}
+	if len(doc.Content) == 0 {
+		doc.Content = []*yaml.Node{{Kind: yaml.MappingNode}}
+	}
+	for _, kv := range environ {
+		name, value, _ := strings.Cut(kv, "=")
+		path, ok := strings.CutPrefix(name, EnvPrefix)
+		// Empty values are skipped, like unset variables passed through by Compose
+		if !ok || value == "" {
+			continue
+		}
+		node, err := envValue(value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		if !setEnvOverride(doc.Content[0], reflect.TypeOf(Config{}), path, node) {
+			return fmt.Errorf("%s: no config field by that name", name)
+		}
+	}
+	return nil
+}
+
+// envValue parses a variable's value: YAML flow syntax for lists and maps, otherwise a
+// plain scalar.
+func envValue(value string) (*yaml.Node, error) {
+	if !strings.HasPrefix(value, "[") && !strings.HasPrefix(value, "{") {
+		return &yaml.Node{Kind: yaml.ScalarNode, Value: value}, nil
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(value), &doc); err != nil {
+		return nil, err
+	}
+	return doc.Content[0], nil
+}
+
+// setEnvOverride sets the field of mapping m (of type t) named by path to value. Struct
+// fields and accounts are matched by their env names; only existing accounts can be
+// overridden. It reports whether path named a field.
+func setEnvOverride(m *yaml.Node, t reflect.Type, path string, value *yaml.Node) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		for i := range t.NumField() {
+			key, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+			if key == "" || key == "-" {
+				continue
+			}
+			if path == envName(key) {
+				setMappingValue(m, key, value)
+				return true
+			}
+			rest, ok := strings.CutPrefix(path, envName(key)+"_")
+			if ok && nested(t.Field(i).Type) && setEnvOverride(childMapping(m, key), t.Field(i).Type, rest, value) {
+				return true
+			}
+		}
+	case reflect.Map:
+		for i := 0; i+1 < len(m.Content); i += 2 {
+			key := m.Content[i].Value
+			if rest, ok := strings.CutPrefix(path, envName(key)+"_"); ok && setEnvOverride(childMapping(m, key), t.Elem(), rest, value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// nested reports whether a field of type t holds fields an override can name: a struct,
+// or a map of structs like accounts.
+func nested(t reflect.Type) bool {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Map {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct
+}
+
+// childMapping returns the mapping under key in m, creating it if needed. An alias is
+// replaced by a copy, so the override doesn't leak into the other users of the anchor.
+func childMapping(m *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value != key {
+			continue
+		}
+		child := m.Content[i+1]
+		if child.Kind == yaml.AliasNode {
+			target := resolveAlias(child)
+			copied := *target
+			copied.Anchor = ""
+			copied.Content = append([]*yaml.Node(nil), target.Content...)
+			m.Content[i+1] = &copied
+		}
+		if m.Content[i+1].Kind != yaml.MappingNode {
+			m.Content[i+1] = &yaml.Node{Kind: yaml.MappingNode}
+		}
+		return m.Content[i+1]
+	}
+	child := &yaml.Node{Kind: yaml.MappingNode}
+	m.Content = append(m.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, child)
+	return child
+}
+
+// setMappingValue sets key in mapping m to value.
+func setMappingValue(m *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			m.Content[i+1] = value
+			return
+		}
+	}
+	m.Content = append(m.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, value)
+}
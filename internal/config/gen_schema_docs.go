@@ -0,0 +1,99 @@
+//go:build ignore
+
+// gen_schema_docs generates schema_docs.go from the doc comments of the config structs,
+// so the JSON Schema describes every option the way the code does.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const output = "schema_docs.go"
+
+func main() {
+	out := flag.String("o", output, "file to write")
+	flag.Parse()
+
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, ".", func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go") && fi.Name() != output
+	}, parser.ParseComments)
+	if err != nil {
+		log.Fatalf("parse: %v", err)
+	}
+
+	docs := make(map[string]string)
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			ast.Inspect(file, func(n ast.Node) bool {
+				ts, ok := n.(*ast.TypeSpec)
+				if !ok {
+					return true
+				}
+				st, ok := ts.Type.(*ast.StructType)
+				if !ok {
+					return false
+				}
+				for _, f := range st.Fields.List {
+					if f.Tag == nil || len(f.Names) == 0 {
+						continue
+					}
+					tag := reflect.StructTag(strings.Trim(f.Tag.Value, "`")).Get("yaml")
+					if tag == "" || tag == "-" {
+						continue
+					}
+					var text []string
+					for _, group := range []*ast.CommentGroup{f.Doc, f.Comment} {
+						s := strings.Join(strings.Fields(group.Text()), " ")
+						// Section headings like "OCI Authentication Details" don't describe the field
+						if s == "" || (group == f.Doc && len(group.List) == 1 && !strings.HasSuffix(s, ".")) {
+							continue
+						}
+						// Docs start with the Go name; users know the YAML one
+						if rest, ok := strings.CutPrefix(s, f.Names[0].Name+" "); ok {
+							s = strings.Split(tag, ",")[0] + " " + rest
+						}
+						text = append(text, s)
+					}
+					if len(text) > 0 {
+						docs[ts.Name.Name+"."+f.Names[0].Name] = strings.Join(text, " ")
+					}
+				}
+				return false
+			})
+		}
+	}
+
+	keys := make([]string, 0, len(docs))
+	for k := range docs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by gen_schema_docs.go; DO NOT EDIT.\n\npackage config\n\n")
+	buf.WriteString("// fieldDocs are the doc comments of the config fields, keyed by Type.Field.\nvar fieldDocs = map[string]string{\n")
+	for _, k := range keys {
+		fmt.Fprintf(&buf, "\t%q: %q,\n", k, docs[k])
+	}
+	buf.WriteString("}\n")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatalf("format: %v\n%s", err, buf.Bytes())
+	}
+	if err := os.WriteFile(*out, src, 0644); err != nil {
+		log.Fatalf("write %s: %v", *out, err)
+	}
+}
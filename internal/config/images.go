@@ -0,0 +1,50 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ImageAlias is a platform image picked by operating system and version instead of by
+// OCID, e.g. "ubuntu-22.04-arm" or "oracle-linux-9".
+type ImageAlias struct {
+	OperatingSystem string // As OCI names it, e.g. "Canonical Ubuntu".
+	Version         string // e.g. "22.04" or "9".
+	Minimal         bool   // Ubuntu's Minimal images.
+}
+
+func (a ImageAlias) String() string {
+	s := a.OperatingSystem + " " + a.Version
+	if a.Minimal {
+		s += " Minimal"
+	}
+	return s
+}
+
+// imageSystems maps the alias prefixes to OCI's operating system names.
+var imageSystems = map[string]string{
+	"ubuntu":       "Canonical Ubuntu",
+	"oracle-linux": "Oracle Linux",
+	"ol":           "Oracle Linux",
+}
+
+// imageAliasPattern is <os>-<version>[-minimal][-arm]. The suffix is only for readability:
+// the image must be compatible with the launch shape either way.
+var imageAliasPattern = regexp.MustCompile(`^([a-z-]+?)-(\d+(?:\.\d+)?)(-minimal)?(-arm|-aarch64)?$`)
+
+// ParseImageAlias parses the image option.
+func ParseImageAlias(s string) (ImageAlias, error) {
+	m := imageAliasPattern.FindStringSubmatch(strings.ToLower(strings.TrimSpace(s)))
+	if m == nil {
+		return ImageAlias{}, fmt.Errorf("image must look like ubuntu-22.04-arm or oracle-linux-9, got '%s'", s)
+	}
+	system, ok := imageSystems[m[1]]
+	if !ok {
+		return ImageAlias{}, fmt.Errorf("image: unknown operating system '%s' (use ubuntu or oracle-linux)", m[1])
+	}
+	if m[3] != "" && system != "Canonical Ubuntu" {
+		return ImageAlias{}, fmt.Errorf("image: only ubuntu has minimal images")
+	}
+	return ImageAlias{OperatingSystem: system, Version: m[2], Minimal: m[3] != ""}, nil
+}
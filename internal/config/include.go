@@ -0,0 +1,193 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// IncludeSource is a shared YAML snippet of account fields, e.g. a community-maintained
+// list of image OCIDs per region, fetched when the config is loaded.
+type IncludeSource struct {
+	URL    string `yaml:"url"`    // https:// only.
+	SHA256 string `yaml:"sha256"` // Hex SHA-256 of the file; a file that changed is refused.
+}
+
+// includeMaxBytes caps the size of an included snippet.
+const includeMaxBytes = 1 << 20
+
+// includeBlocked are the fields an included snippet may not set: credentials and
+// whether the account runs stay in the local config.
+var includeBlocked = append([]string{"enabled", "include", "user_ocid", "tenancy_ocid", "fingerprint", "auth", "oci_profile", "oci_config_file"}, keySources...)
+
+// includeClient fetches included snippets.
+var includeClient = &http.Client{Timeout: 15 * time.Second}
+
+// IncludeCacheDir keeps a copy of every verified snippet, named by its checksum, so a
+// restart doesn't need the network. Empty disables the cache (--stateless).
+var IncludeCacheDir = func() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "oci-arm-provisioner", "includes")
+}()
+
+// applyIncludes merges the snippets listed under an account's 'include:' (or the one
+// under 'defaults:') into the account, below the keys it sets itself and above the other
+// defaults. A snippet is a mapping of account fields; fields under its 'regions:' mapping
+// apply to accounts in that region and win over the snippet's top-level ones.
+func applyIncludes(doc *yaml.Node) error {
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return nil
+	}
+	root := doc.Content[0]
+	accounts := mappingValue(root, "accounts")
+	if accounts == nil || accounts.Kind != yaml.MappingNode {
+		return nil
+	}
+	defaults := mappingValue(root, "defaults")
+	fetched := make(map[IncludeSource]*yaml.Node)
+
+	for i := 0; i+1 < len(accounts.Content); i += 2 {
+		name, acc := accounts.Content[i].Value, resolveAlias(accounts.Content[i+1])
+		if acc.Kind != yaml.MappingNode {
+			continue
+		}
+		list, region := mappingValue(acc, "include"), mappingValue(acc, "region")
+		if list == nil && defaults != nil {
+			list = mappingValue(defaults, "include")
+		}
+		if region == nil && defaults != nil {
+			region = mappingValue(defaults, "region")
+		}
+		if list == nil {
+			continue
+		}
+		var sources []IncludeSource
+		if err := list.Decode(&sources); err != nil {
+			return fmt.Errorf("account '%s': include: %w", name, err)
+		}
+		for _, src := range sources {
+			snippet, ok := fetched[src]
+			if !ok {
+				var err error
+				if snippet, err = fetchInclude(src); err != nil {
+					return fmt.Errorf("account '%s': include %s: %w", name, src.URL, err)
+				}
+				fetched[src] = snippet
+			}
+			if region != nil {
+				if fields := mappingValue(snippet, "regions"); fields != nil {
+					if err := mergeInclude(acc, mappingValue(fields, region.Value)); err != nil {
+						return fmt.Errorf("account '%s': include %s: %w", name, src.URL, err)
+					}
+				}
+			}
+			if err := mergeInclude(acc, snippet); err != nil {
+				return fmt.Errorf("account '%s': include %s: %w", name, src.URL, err)
+			}
+		}
+	}
+	return nil
+}
+
+// mergeInclude copies the fields of snippet that acc doesn't set into acc.
+func mergeInclude(acc, snippet *yaml.Node) error {
+	if snippet == nil {
+		return nil
+	}
+	if snippet.Kind != yaml.MappingNode {
+		return fmt.Errorf("expected a mapping of account fields")
+	}
+	present := mappingKeys(acc)
+	for i := 0; i+1 < len(snippet.Content); i += 2 {
+		key := snippet.Content[i]
+		switch {
+		case key.Value == "regions":
+			continue
+		case slices.Contains(includeBlocked, key.Value):
+			return fmt.Errorf("%s can't be set by an included snippet", key.Value)
+		case present[key.Value]:
+			continue
+		}
+		acc.Content = append(acc.Content, key, snippet.Content[i+1])
+	}
+	return nil
+}
+
+// fetchInclude returns the parsed snippet of src, from the cache or over HTTPS, after
+// checking its checksum.
+func fetchInclude(src IncludeSource) (*yaml.Node, error) {
+	sum := strings.ToLower(src.SHA256)
+	if !strings.HasPrefix(src.URL, "https://") {
+		return nil, fmt.Errorf("url must start with https://")
+	}
+	if b, err := hex.DecodeString(sum); err != nil || len(b) != sha256.Size {
+		return nil, fmt.Errorf("sha256 must be the file's 64 hex digit checksum")
+	}
+
+	cached := ""
+	if IncludeCacheDir != "" {
+		cached = filepath.Join(IncludeCacheDir, sum+".yaml")
+	}
+	data, err := os.ReadFile(cached)
+	if cached == "" || err != nil || checksum(data) != sum {
+		if data, err = download(src.URL); err != nil {
+			return nil, err
+		}
+		if got := checksum(data); got != sum {
+			return nil, fmt.Errorf("checksum mismatch: the file's sha256 is %s; check what changed before pinning it", got)
+		}
+		if cached != "" {
+			// Best effort: without the cache the next load fetches again
+			if os.MkdirAll(IncludeCacheDir, 0o700) == nil {
+				_ = os.WriteFile(cached, data, 0o600)
+			}
+		}
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("error parsing yaml: %w", err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("expected a mapping of account fields")
+	}
+	return doc.Content[0], nil
+}
+
+// download fetches url, refusing bodies over includeMaxBytes.
+func download(url string) ([]byte, error) {
+	resp, err := includeClient.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, includeMaxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > includeMaxBytes {
+		return nil, fmt.Errorf("larger than %d bytes", includeMaxBytes)
+	}
+	return data, nil
+}
+
+// checksum returns the hex SHA-256 of data.
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
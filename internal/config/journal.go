@@ -0,0 +1,201 @@
+package config
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+// HistoryDir is created next to the config file and holds the change journal and backups.
+const HistoryDir = ".config-history"
+
+const journalFile = "journal.jsonl"
+
+// JournalEntry records a single change to the config file.
+type JournalEntry struct {
+	Seq     int       `json:"seq"`
+	Time    time.Time `json:"time"`
+	Action  string    `json:"action"`            // "write" or "rollback"
+	Reason  string    `json:"reason"`            // Who/what made the change, e.g. "setup wizard".
+	Backup  string    `json:"backup,omitempty"`  // Copy of the file before the change; empty if it did not exist.
+	Reverts int       `json:"reverts,omitempty"` // For rollbacks, the Seq of the undone change.
+}
+
+// ResolvePath returns path, or the first config file found in the standard locations.
+// Unlike LoadConfig it does not parse the file, so it works on broken configs.
+func ResolvePath(path string) string {
+	if path == "" {
+		path = findConfig()
+	}
+	if path == "" {
+		return ""
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
+// Write is the single entry point for modifying a config file. The change is journaled
+// before it is applied, the previous contents are kept as a timestamped backup, and the
+// new file is written atomically (temp file + rename), or in place where the file is a
+// bind mount that can't be replaced.
+func Write(path string, content []byte, reason string) error {
+	_, err := mutate(path, content, JournalEntry{Action: "write", Reason: reason})
+	return err
+}
+
+// Rollback reverts the most recent change that has not already been rolled back.
+// The rollback is itself journaled, so it can be inspected with History.
+func Rollback(path string) (JournalEntry, error) {
+	target, err := RollbackTarget(path)
+	if err != nil {
+		return JournalEntry{}, err
+	}
+	content, err := os.ReadFile(filepath.Join(historyDir(path), target.Backup))
+	if err != nil {
+		return JournalEntry{}, fmt.Errorf("reading backup for change #%d: %w", target.Seq, err)
+	}
+	return mutate(path, content, JournalEntry{
+		Action:  "rollback",
+		Reason:  fmt.Sprintf("revert #%d (%s)", target.Seq, target.Reason),
+		Reverts: target.Seq,
+	})
+}
+
+// RollbackTarget returns the change Rollback would revert, so it can be shown first.
+func RollbackTarget(path string) (JournalEntry, error) {
+	entries, err := History(path)
+	if err != nil {
+		return JournalEntry{}, err
+	}
+
+	reverted := make(map[int]bool)
+	for _, e := range entries {
+		if e.Action == "rollback" {
+			reverted[e.Reverts] = true
+		}
+	}
+
+	for i := len(entries) - 1; i >= 0; i-- {
+		target := entries[i]
+		if target.Action != "write" || reverted[target.Seq] {
+			continue
+		}
+		if target.Backup == "" {
+			return JournalEntry{}, fmt.Errorf("change #%d created the file; nothing to restore", target.Seq)
+		}
+		return target, nil
+	}
+	return JournalEntry{}, errors.New("no changes to roll back")
+}
+
+// History returns the journal entries for a config file, oldest first.
+func History(path string) ([]JournalEntry, error) {
+	f, err := os.Open(filepath.Join(historyDir(path), journalFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening journal: %w", err)
+	}
+	defer f.Close()
+
+	var entries []JournalEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e JournalEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("corrupt journal entry: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, scanner.Err()
+}
+
+func historyDir(path string) string {
+	return filepath.Join(filepath.Dir(path), HistoryDir)
+}
+
+// mutate backs up the current file, journals entry, then atomically replaces the file.
+func mutate(path string, content []byte, entry JournalEntry) (JournalEntry, error) {
+	dir := historyDir(path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return entry, fmt.Errorf("creating history dir: %w", err)
+	}
+
+	entries, err := History(path)
+	if err != nil {
+		return entry, err
+	}
+	entry.Seq = 1
+	if len(entries) > 0 {
+		entry.Seq = entries[len(entries)-1].Seq + 1
+	}
+	entry.Time = time.Now()
+
+	// 1. Backup the current contents (config files hold secrets, keep them private)
+	mode := os.FileMode(0600)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+		current, err := os.ReadFile(path)
+		if err != nil {
+			return entry, fmt.Errorf("reading current config: %w", err)
+		}
+		entry.Backup = fmt.Sprintf("%s.%s.%d", filepath.Base(path), entry.Time.Format("20060102-150405"), entry.Seq)
+		if err := os.WriteFile(filepath.Join(dir, entry.Backup), current, 0600); err != nil {
+			return entry, fmt.Errorf("writing backup: %w", err)
+		}
+	}
+
+	// 2. Journal the change before applying it
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return entry, err
+	}
+	jf, err := os.OpenFile(filepath.Join(dir, journalFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return entry, fmt.Errorf("opening journal: %w", err)
+	}
+	if _, err := jf.Write(append(line, '\n')); err != nil {
+		jf.Close()
+		return entry, fmt.Errorf("writing journal: %w", err)
+	}
+	if err := jf.Close(); err != nil {
+		return entry, fmt.Errorf("writing journal: %w", err)
+	}
+
+	// 3. Atomic replace
+	return entry, replace(path, content, mode)
+}
+
+// rename is os.Rename; tests swap it to simulate a bind-mounted config file.
+var rename = os.Rename
+
+// replace writes content to a temp file and renames it over path. A config file that is
+// bind-mounted on its own (./config.yaml:/app/config.yaml in docker-compose) can't be
+// renamed over (EBUSY, or EXDEV on some mounts), so it is then written in place; the
+// backup taken before still allows a rollback.
+func replace(path string, content []byte, mode os.FileMode) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, content, mode); err != nil {
+		return fmt.Errorf("writing config: %w", err)
+	}
+	err := rename(tmp, path)
+	if err == nil {
+		return nil
+	}
+	os.Remove(tmp)
+	if !errors.Is(err, syscall.EBUSY) && !errors.Is(err, syscall.EXDEV) {
+		return fmt.Errorf("replacing config: %w", err)
+	}
+	if err := os.WriteFile(path, content, mode); err != nil {
+		return fmt.Errorf("writing config in place: %w", err)
+	}
+	return nil
+}
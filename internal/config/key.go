@@ -0,0 +1,79 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// MaxKeySize bounds an API signing key, read from a file or given inline.
+const MaxKeySize = 16 * 1024
+
+// keySources are the alternative ways to give an account's API signing key. An account
+// uses one; a default of another kind doesn't apply to it.
+var keySources = []string{"key_file", "key_content", "key_env", "key_vault_secret_ocid", "credentials_dir"}
+
+// HasInlineKey reports whether the key is given by key_content or key_env rather than
+// by a file.
+func (a *AccountConfig) HasInlineKey() bool {
+	return a.KeyContent != "" || a.KeyEnv != ""
+}
+
+// UsesKeyFile reports whether the account signs with the key in key_file.
+func (a *AccountConfig) UsesKeyFile() bool {
+	return a.UsesAPIKey() && !a.HasInlineKey() && a.KeyVaultSecretOCID == ""
+}
+
+// checkKeySource rejects accounts that give their key more than one way.
+func checkKeySource(acc *AccountConfig) error {
+	var set []string
+	for name, value := range map[string]string{"key_file": acc.KeyFile, "key_content": acc.KeyContent, "key_env": acc.KeyEnv, "key_vault_secret_ocid": acc.KeyVaultSecretOCID} {
+		if value != "" {
+			set = append(set, name)
+		}
+	}
+	if len(set) > 1 {
+		return errors.New("set only one of key_file, key_content, key_env and key_vault_secret_ocid")
+	}
+	if len(set) == 1 && set[0] != "key_file" && acc.CredentialsDir != "" {
+		return fmt.Errorf("set either %s or credentials_dir, not both", set[0])
+	}
+	if acc.KeyVaultSecretOCID != "" && !strings.HasPrefix(acc.KeyVaultSecretOCID, "ocid1.vaultsecret.") {
+		return fmt.Errorf("key_vault_secret_ocid must be a secret OCID (ocid1.vaultsecret...), got '%s'", acc.KeyVaultSecretOCID)
+	}
+	return nil
+}
+
+// PrivateKeyPEM returns the inline key: key_content, or the value of the key_env
+// variable. Escaped line breaks ("\n" in a one-line variable or secret) are unescaped.
+func (a *AccountConfig) PrivateKeyPEM() ([]byte, error) {
+	content := a.KeyContent
+	if a.KeyEnv != "" {
+		content = os.Getenv(a.KeyEnv)
+		if content == "" {
+			return nil, fmt.Errorf("key_env: $%s is not set", a.KeyEnv)
+		}
+	}
+	if !strings.Contains(content, "\n") {
+		content = strings.ReplaceAll(content, `\n`, "\n")
+	}
+	if len(content) > MaxKeySize {
+		return nil, fmt.Errorf("%s: key too large (%d bytes), max is %d", a.KeySource(), len(content), MaxKeySize)
+	}
+	return []byte(content), nil
+}
+
+// KeySource describes where the account's key comes from, for errors and logs.
+func (a *AccountConfig) KeySource() string {
+	switch {
+	case a.KeyEnv != "":
+		return "key_env $" + a.KeyEnv
+	case a.KeyContent != "":
+		return "key_content"
+	case a.KeyVaultSecretOCID != "":
+		return "key_vault_secret_ocid " + a.KeyVaultSecretOCID
+	default:
+		return a.KeyFile
+	}
+}
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// NameVars are the fields available to display_name and hostname_label templates,
+// e.g. "arm-{{.Account}}-{{.Seq}}".
+type NameVars struct {
+	Account string
+	Region  string
+	Shape   string
+	Seq     int // Sequence number of the instance being launched, starting at 1.
+}
+
+var hostnameInvalid = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// IsNameTemplate reports whether s contains template actions.
+func IsNameTemplate(s string) bool {
+	return strings.Contains(s, "{{")
+}
+
+// RenderName expands a display_name template. Plain strings are returned unchanged.
+func RenderName(tmpl string, vars NameVars) (string, error) {
+	if !IsNameTemplate(tmpl) {
+		return tmpl, nil
+	}
+	t, err := template.New("name").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, vars); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// RenderHostname expands a hostname_label template and coerces the result into a
+// valid DNS label (lowercase letters, digits and hyphens, max 63 chars).
+func RenderHostname(tmpl string, vars NameVars) (string, error) {
+	name, err := RenderName(tmpl, vars)
+	if err != nil || !IsNameTemplate(tmpl) {
+		return name, err
+	}
+	name = hostnameInvalid.ReplaceAllString(strings.ToLower(name), "-")
+	name = strings.Trim(name, "-")
+	if len(name) > 63 {
+		name = strings.TrimRight(name[:63], "-")
+	}
+	return name, nil
+}
+
+// validateInstanceNames checks that the names of an account with instance_count > 1
+// differ between instances, so each one can be found again by its display name and
+// doesn't take another's hostname.
+func validateInstanceNames(acc *AccountConfig, vars NameVars) error {
+	next := vars
+	next.Seq++
+	first, _ := RenderName(acc.DisplayName, vars)
+	second, _ := RenderName(acc.DisplayName, next)
+	if first == second {
+		return fmt.Errorf("instance_count: display_name must tell the instances apart, e.g. \"arm-{{.Account}}-{{.Seq}}\"")
+	}
+	first, _ = RenderHostname(acc.HostnameLabel, vars)
+	second, _ = RenderHostname(acc.HostnameLabel, next)
+	if first != "" && first == second && !acc.HostnameAutoSuffix {
+		return fmt.Errorf("instance_count: hostname_label must contain {{.Seq}}, or set hostname_auto_suffix: true")
+	}
+	return nil
+}
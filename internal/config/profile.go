@@ -0,0 +1,113 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Defaults for oci_profile and oci_config_file, as the OCI CLI uses them.
+const (
+	DefaultOCIConfigFile = "~/.oci/config"
+	DefaultOCIProfile    = "DEFAULT"
+)
+
+// UsesOCIProfile reports whether the account reads its credentials from an OCI CLI
+// profile: always with auth: security_token, and for API keys when oci_profile is set.
+func (a *AccountConfig) UsesOCIProfile() bool {
+	return a.Auth == AuthSecurityToken || (a.UsesAPIKey() && (a.OCIProfile != "" || a.OCIConfigFile != ""))
+}
+
+// profileField is an account field filled from an OCI CLI profile.
+type profileField struct {
+	name  string
+	field *string
+	value string
+}
+
+// applyOCIProfile resolves oci_profile and oci_config_file and fills the account from the
+// profile: the user, tenancy, fingerprint, key file and region for an API key, or the
+// tenancy and region of a session from "oci session authenticate". Fields already set
+// must match the profile, except region, which may differ to hunt elsewhere.
+func applyOCIProfile(acc *AccountConfig) error {
+	if acc.OCIProfile == "" {
+		acc.OCIProfile = DefaultOCIProfile
+	}
+	if acc.OCIConfigFile == "" {
+		acc.OCIConfigFile = DefaultOCIConfigFile
+	}
+	path := expandHome(acc.OCIConfigFile)
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	acc.OCIConfigFile = path
+
+	create := "oci setup config"
+	if acc.Auth == AuthSecurityToken {
+		create = "oci session authenticate --profile-name " + acc.OCIProfile
+	}
+	profile, err := readOCIProfile(path, acc.OCIProfile)
+	if err != nil {
+		return fmt.Errorf("oci_config_file: %s: %w (create it with: %s)", path, err, create)
+	}
+
+	fields := []profileField{{"tenancy_ocid", &acc.TenancyOCID, profile["tenancy"]}}
+	if acc.Auth == AuthSecurityToken {
+		if profile["security_token_file"] == "" || profile["key_file"] == "" {
+			return fmt.Errorf("oci_profile %s has no session (create one with: %s)", acc.OCIProfile, create)
+		}
+	} else {
+		if profile["security_token_file"] != "" {
+			return fmt.Errorf("oci_profile %s holds a session: set auth: %s", acc.OCIProfile, AuthSecurityToken)
+		}
+		if profile["pass_phrase"] != "" {
+			return fmt.Errorf("oci_profile %s: keys with a pass_phrase are not supported", acc.OCIProfile)
+		}
+		keyFile := profile["key_file"]
+		if keyFile != "" {
+			keyFile = expandHome(keyFile)
+			if !filepath.IsAbs(keyFile) {
+				// The OCI CLI resolves relative paths from the config file's directory
+				keyFile = filepath.Join(filepath.Dir(path), keyFile)
+			}
+		}
+		fields = append(fields,
+			profileField{"user_ocid", &acc.UserOCID, profile["user"]},
+			profileField{"fingerprint", &acc.Fingerprint, profile["fingerprint"]},
+		)
+		// key_content, key_env or key_vault_secret_ocid replace the profile's key file
+		if acc.UsesKeyFile() {
+			fields = append(fields, profileField{"key_file", &acc.KeyFile, keyFile})
+		}
+	}
+	for _, f := range fields {
+		if f.value == "" {
+			continue
+		}
+		if *f.field != "" && *f.field != f.value && expandHome(*f.field) != f.value {
+			return fmt.Errorf("%s is %q but oci_profile %s has %q", f.name, *f.field, acc.OCIProfile, f.value)
+		}
+		*f.field = f.value
+	}
+	if acc.Region == "" {
+		acc.Region = profile["region"]
+	}
+	return nil
+}
+
+// SessionTokenFile returns the session token file of an auth: security_token account,
+// read from its profile each time so a new session is picked up.
+func (a *AccountConfig) SessionTokenFile() (string, error) {
+	profile, err := readOCIProfile(a.OCIConfigFile, a.OCIProfile)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", a.OCIConfigFile, err)
+	}
+	path := expandHome(profile["security_token_file"])
+	if path == "" {
+		return "", fmt.Errorf("oci_profile %s has no security_token_file", a.OCIProfile)
+	}
+	if _, err := os.Stat(path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
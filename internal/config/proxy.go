@@ -0,0 +1,39 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// proxySchemes are the proxy_url schemes Go's HTTP transport can dial through.
+var proxySchemes = map[string]bool{"http": true, "https": true, "socks5": true}
+
+// validateProxyURL checks a proxy_url. Errors leave out the URL, which may hold a password.
+func validateProxyURL(s string) error {
+	if s == "" {
+		return nil
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return fmt.Errorf("proxy_url is not a valid URL")
+	}
+	if !proxySchemes[u.Scheme] {
+		return fmt.Errorf("proxy_url must start with http://, https:// or socks5://, got %q", u.Scheme+"://")
+	}
+	if u.Hostname() == "" || (u.Path != "" && u.Path != "/") {
+		return fmt.Errorf("proxy_url must be a bare %s://host:port", u.Scheme)
+	}
+	return nil
+}
+
+// Proxy returns the parsed proxy_url, or nil to connect directly.
+func (a *AccountConfig) Proxy() *url.URL {
+	if a.ProxyURL == "" {
+		return nil
+	}
+	u, err := url.Parse(a.ProxyURL)
+	if err != nil {
+		return nil // Rejected by LoadConfig
+	}
+	return u
+}
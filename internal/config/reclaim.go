@@ -0,0 +1,28 @@
+package config
+
+import "errors"
+
+// ReclaimConfig watches a provisioned account's instances and hunts again once they are
+// gone, terminated outside the tool or reclaimed by Oracle.
+type ReclaimConfig struct {
+	Relaunch        bool `yaml:"relaunch,omitempty"`          // Check the instances every cycle and hunt again when the last is gone.
+	ReuseBootVolume bool `yaml:"reuse_boot_volume,omitempty"` // Launch the new instance from the old one's boot volume if it survived, keeping its data.
+}
+
+// validateReclaim checks the account's reclaim block.
+func validateReclaim(acc *AccountConfig) error {
+	r := acc.Reclaim
+	if r.ReuseBootVolume && !r.Relaunch {
+		return errors.New("reclaim: reuse_boot_volume needs relaunch: true")
+	}
+	if r.Relaunch && acc.Teardown.Enabled() {
+		return errors.New("reclaim: relaunch does not work with teardown (use teardown.relaunch)")
+	}
+	return nil
+}
+
+// ReusesBootVolume reports whether a launch may start from the boot volume of an
+// instance that is gone: after a reclaim, or after upgrade_existing replaced it.
+func (a *AccountConfig) ReusesBootVolume() bool {
+	return a.Reclaim.ReuseBootVolume || a.UpgradeExisting
+}
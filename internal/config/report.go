@@ -0,0 +1,42 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// SuccessReportConfig uploads a record of each provisioned instance to an Object Storage
+// bucket through a pre-authenticated request, so it outlives the machine the tool runs on.
+type SuccessReportConfig struct {
+	PARURL  string `yaml:"par_url"` // Bucket PAR allowing object writes, ending in /o/. Secret: prefer OCI_SUCCESS_REPORT_PAR_URL.
+	Timeout string `yaml:"timeout"` // Per upload; default 30s.
+}
+
+// Enabled reports whether reports are uploaded.
+func (r SuccessReportConfig) Enabled() bool {
+	return r.PARURL != ""
+}
+
+// validateSuccessReport checks the PAR URL looks like a bucket PAR and adds the trailing
+// slash object names are appended after.
+func validateSuccessReport(r *SuccessReportConfig) error {
+	if r.Timeout != "" {
+		if _, err := time.ParseDuration(r.Timeout); err != nil {
+			return fmt.Errorf("success_report.timeout: %w", err)
+		}
+	}
+	if !r.Enabled() {
+		return nil
+	}
+	u, err := url.Parse(r.PARURL)
+	if err != nil || u.Scheme != "https" || !strings.Contains(u.Path, "/p/") || !strings.HasSuffix(strings.TrimSuffix(u.Path, "/"), "/o") {
+		// The URL itself is a credential, so it is not repeated in the error
+		return fmt.Errorf("success_report.par_url: expected a bucket pre-authenticated request URL like https://objectstorage.<region>.oraclecloud.com/p/<token>/n/<namespace>/b/<bucket>/o/")
+	}
+	if !strings.HasSuffix(r.PARURL, "/") {
+		r.PARURL += "/"
+	}
+	return nil
+}
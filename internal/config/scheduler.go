@@ -0,0 +1,91 @@
+package config
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MaxJitterPercent is the largest scheduler.jitter_percent accepted.
+const MaxJitterPercent = 50
+
+// SchedulerPreset is a named set of scheduler timings.
+type SchedulerPreset struct {
+	Name        string
+	Description string
+	Scheduler   SchedulerConfig // Poll interval left at its default.
+}
+
+// SchedulerPresets are the values scheduler.preset accepts, gentlest first.
+var SchedulerPresets = []SchedulerPreset{
+	{
+		Name:        "conservative",
+		Description: "long gaps, for many accounts on one IP or tenancies that were flagged before",
+		Scheduler:   SchedulerConfig{AccountDelaySeconds: 600, CycleIntervalSeconds: 1800, TenancyIntervalSeconds: 120, JitterPercent: 20},
+	},
+	{
+		Name:        "balanced",
+		Description: "the default timings, with a little jitter",
+		Scheduler:   SchedulerConfig{AccountDelaySeconds: 450, CycleIntervalSeconds: 900, TenancyIntervalSeconds: 60, JitterPercent: 10},
+	},
+	{
+		Name:        "aggressive",
+		Description: "short gaps, for one or two accounts hunting a busy region",
+		Scheduler:   SchedulerConfig{AccountDelaySeconds: 60, CycleIntervalSeconds: 300, TenancyIntervalSeconds: 30, JitterPercent: 5},
+	},
+}
+
+// LookupSchedulerPreset returns the preset called name.
+func LookupSchedulerPreset(name string) (SchedulerPreset, bool) {
+	for _, p := range SchedulerPresets {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return SchedulerPreset{}, false
+}
+
+// schedulerPresetNames lists the names of SchedulerPresets.
+func schedulerPresetNames() []string {
+	names := make([]string, len(SchedulerPresets))
+	for i, p := range SchedulerPresets {
+		names[i] = p.Name
+	}
+	return names
+}
+
+// Jitter returns d moved randomly by up to JitterPercent of it, either way.
+func (s SchedulerConfig) Jitter(d time.Duration) time.Duration {
+	spread := d * time.Duration(s.JitterPercent) / 100
+	if spread <= 0 {
+		return d
+	}
+	return d - spread + rand.N(2*spread+1)
+}
+
+// applySchedulerPreset fills cfg's scheduler timings from scheduler.preset, before the
+// document is decoded so the fields set next to it win.
+func applySchedulerPreset(doc *yaml.Node, cfg *Config) error {
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return nil
+	}
+	scheduler := mappingValue(doc.Content[0], "scheduler")
+	if scheduler == nil {
+		return nil
+	}
+	name := mappingValue(scheduler, "preset")
+	if name == nil || name.Value == "" {
+		return nil
+	}
+	p, ok := LookupSchedulerPreset(name.Value)
+	if !ok {
+		return fmt.Errorf("scheduler.preset: unknown preset %q (expected %s)", name.Value, strings.Join(schedulerPresetNames(), ", "))
+	}
+	p.Scheduler.PollIntervalSeconds = cfg.Scheduler.PollIntervalSeconds
+	p.Scheduler.Preset = p.Name
+	cfg.Scheduler = p.Scheduler
+	return nil
+}
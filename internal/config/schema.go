@@ -0,0 +1,91 @@
+package config
+
+//go:generate go run gen_schema_docs.go
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/yourusername/oci-arm-provisioner/internal/cloudinit"
+)
+
+// SchemaID is the $id of the config schema.
+const SchemaID = "https://github.com/yourusername/oci-arm-provisioner/config.schema.json"
+
+// schemaEnums are the accepted values of string options, keyed by Type.Field.
+var schemaEnums = map[string][]string{
+	"NotificationConfig.WebhookFormat": WebhookFormats,
+	"LoggingConfig.ConsoleFormat":      ConsoleFormats,
+	"StateBackendConfig.Type":          StateBackendTypes,
+	"AccountConfig.Auth":               AuthModes,
+	"SchedulerConfig.Preset":           schedulerPresetNames(),
+}
+
+// Schema returns a JSON Schema (draft-07) for config.yaml, built from the Config structs
+// and their doc comments, for editors with a YAML language server.
+func Schema() ([]byte, error) {
+	root := schemaFor(reflect.TypeOf(Config{}))
+	root["$schema"] = "http://json-schema.org/draft-07/schema#"
+	root["$id"] = SchemaID
+	root["title"] = "oci-arm-provisioner config.yaml"
+	// Top-level x- keys hold YAML anchors to merge into accounts
+	root["patternProperties"] = map[string]any{"^x-": map[string]any{}}
+	schemaExtras(root)
+	return json.MarshalIndent(root, "", "  ")
+}
+
+// schemaFor returns the schema of a config type.
+func schemaFor(t reflect.Type) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice:
+		return map[string]any{"type": "array", "items": schemaFor(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": schemaFor(t.Elem())}
+	case reflect.Struct:
+		props := make(map[string]any)
+		for i := range t.NumField() {
+			f := t.Field(i)
+			name, _, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+			if !f.IsExported() || name == "" || name == "-" {
+				continue
+			}
+			key := t.Name() + "." + f.Name
+			prop := schemaFor(f.Type)
+			if doc, ok := fieldDocs[key]; ok {
+				prop["description"] = doc
+			}
+			if enum, ok := schemaEnums[key]; ok {
+				prop["enum"] = enum
+			}
+			props[name] = prop
+		}
+		return map[string]any{"type": "object", "properties": props, "additionalProperties": false}
+	default:
+		return map[string]any{"type": "string"}
+	}
+}
+
+// schemaExtras adds what the types alone don't say: map keys and list values limited to
+// known names.
+func schemaExtras(root map[string]any) {
+	props := root["properties"].(map[string]any)
+	notifications := props["notifications"].(map[string]any)["properties"].(map[string]any)
+	notifications["alert_throttle"].(map[string]any)["propertyNames"] = map[string]any{"enum": AlertClasses}
+
+	account := props["accounts"].(map[string]any)["additionalProperties"].(map[string]any)
+	for _, acc := range []map[string]any{account, props["defaults"].(map[string]any)} {
+		cloudInit := acc["properties"].(map[string]any)["cloud_init"].(map[string]any)
+		cloudInit["items"] = map[string]any{"type": "string", "enum": cloudinit.Presets()}
+	}
+}
@@ -0,0 +1,153 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ShapeOption is a shape to launch and its size: the account's shape, or an entry of its
+// shape_fallbacks.
+type ShapeOption struct {
+	Shape    string  `yaml:"shape"`
+	OCPUs    float32 `yaml:"ocpus,omitempty"`     // Flexible shapes only; defaults to the account's ocpus.
+	MemoryGB float32 `yaml:"memory_gb,omitempty"` // Flexible shapes only; defaults to the account's memory_gb.
+
+	// Fixed shapes come with their size, so a launch sends no shape config. Set by Shapes.
+	Fixed bool `yaml:"-"`
+}
+
+func (o ShapeOption) String() string {
+	return fmt.Sprintf("%s (%.0f OCPUs / %.0f GB)", o.Shape, o.OCPUs, o.MemoryGB)
+}
+
+// DefaultFallbackAfter is how many capacity errors in a row move an account to its next
+// shape when fallback_after is not set.
+const DefaultFallbackAfter = 3
+
+// shapeLimit is what OCI accepts for a shape. Shapes not listed are only checked for
+// positive sizes.
+type shapeLimit struct {
+	fixed                        bool
+	minOCPUs, maxOCPUs           float32
+	minMemPerOCPU, maxMemPerOCPU float32
+	maxMemoryGB                  float32
+}
+
+var shapeLimits = map[string]shapeLimit{
+	"VM.Standard.A1.Flex":    {minOCPUs: 1, maxOCPUs: 80, minMemPerOCPU: 1, maxMemPerOCPU: 64, maxMemoryGB: 512},
+	"VM.Standard.E2.1.Micro": {fixed: true, minOCPUs: 1, maxOCPUs: 1, minMemPerOCPU: 1, maxMemPerOCPU: 1, maxMemoryGB: 1},
+}
+
+// Shapes returns the shapes to try in order: the account's shape, then its
+// shape_fallbacks, with sizes filled in.
+func (a *AccountConfig) Shapes() []ShapeOption {
+	shapes := []ShapeOption{{Shape: a.Shape, OCPUs: a.OCPUs, MemoryGB: a.MemoryGB}}
+	for _, opt := range a.ShapeFallbacks {
+		if opt.OCPUs == 0 {
+			opt.OCPUs = a.OCPUs
+		}
+		if opt.MemoryGB == 0 {
+			opt.MemoryGB = a.MemoryGB
+		}
+		shapes = append(shapes, opt)
+	}
+	for i, opt := range shapes {
+		if limit, ok := shapeLimits[opt.Shape]; ok && limit.fixed {
+			shapes[i].OCPUs, shapes[i].MemoryGB, shapes[i].Fixed = limit.maxOCPUs, limit.maxMemoryGB, true
+		}
+	}
+	return shapes
+}
+
+// validateShapes checks the account's shape and every fallback against the shape's limits.
+func validateShapes(a *AccountConfig) error {
+	for i, opt := range a.ShapeFallbacks {
+		if strings.TrimSpace(opt.Shape) == "" {
+			return fmt.Errorf("shape_fallbacks[%d]: shape is required", i)
+		}
+		limit, ok := shapeLimits[opt.Shape]
+		if ok && limit.fixed && (opt.OCPUs != 0 || opt.MemoryGB != 0) {
+			return fmt.Errorf("shape_fallbacks[%d]: %s is a fixed shape (%.0f OCPU / %.0f GB); remove ocpus and memory_gb", i, opt.Shape, limit.maxOCPUs, limit.maxMemoryGB)
+		}
+	}
+	if limit, ok := shapeLimits[a.Shape]; ok && limit.fixed && (a.OCPUs != limit.maxOCPUs || a.MemoryGB != limit.maxMemoryGB) {
+		return fmt.Errorf("shape: %s is a fixed shape; set ocpus: %.0f and memory_gb: %.0f", a.Shape, limit.maxOCPUs, limit.maxMemoryGB)
+	}
+
+	for i, opt := range a.Shapes() {
+		field := "shape"
+		if i > 0 {
+			field = fmt.Sprintf("shape_fallbacks[%d]", i-1)
+		}
+		if opt.OCPUs <= 0 || opt.MemoryGB <= 0 {
+			return fmt.Errorf("%s: ocpus and memory_gb must be positive", field)
+		}
+		limit, ok := shapeLimits[opt.Shape]
+		if !ok || limit.fixed {
+			continue
+		}
+		if opt.OCPUs < limit.minOCPUs || opt.OCPUs > limit.maxOCPUs {
+			return fmt.Errorf("%s: %s takes %.0f to %.0f OCPUs, got %g", field, opt.Shape, limit.minOCPUs, limit.maxOCPUs, opt.OCPUs)
+		}
+		perOCPU := opt.MemoryGB / opt.OCPUs
+		if perOCPU < limit.minMemPerOCPU || perOCPU > limit.maxMemPerOCPU || opt.MemoryGB > limit.maxMemoryGB {
+			return fmt.Errorf("%s: %s takes %.0f to %.0f GB of memory per OCPU (at most %.0f GB), got %g GB for %g OCPUs",
+				field, opt.Shape, limit.minMemPerOCPU, limit.maxMemPerOCPU, limit.maxMemoryGB, opt.MemoryGB, opt.OCPUs)
+		}
+	}
+	return nil
+}
+
+// Slices returns the sizes split_on_capacity tries in order: the account's shape at full
+// size, then halved while the OCPUs stay whole, e.g. 4/24, 2/12, 1/6.
+func (a *AccountConfig) Slices() []ShapeOption {
+	full := a.Shapes()[0]
+	slices := []ShapeOption{full}
+	for opt := full; opt.OCPUs >= 2 && float32(int(opt.OCPUs/2)) == opt.OCPUs/2; {
+		opt.OCPUs, opt.MemoryGB = opt.OCPUs/2, opt.MemoryGB/2
+		slices = append(slices, opt)
+	}
+	return slices
+}
+
+// GuardsFreeTier reports whether A1 launches are checked against what is left of the
+// free-tier allotment. On by default; PAYG accounts turn it off with free_tier_guard: false.
+func (a *AccountConfig) GuardsFreeTier() bool {
+	return a.FreeTierGuard == nil || *a.FreeTierGuard
+}
+
+// validateSplit checks that split_on_capacity has something to split, and that no other
+// option also changes what is launched.
+func validateSplit(a *AccountConfig) error {
+	if !a.SplitOnCapacity {
+		return nil
+	}
+	switch {
+	case len(a.Slices()) < 2 || a.Shapes()[0].Fixed:
+		return fmt.Errorf("split_on_capacity needs a flexible shape with an even number of ocpus, got %s", a.Shapes()[0])
+	case len(a.ShapeFallbacks) > 0:
+		return fmt.Errorf("split_on_capacity and shape_fallbacks both change the size after capacity errors; use one")
+	case a.InstanceCount > 1:
+		return fmt.Errorf("split_on_capacity replaces a single instance; it doesn't work with instance_count")
+	case a.DedicatedVMHostOCID != "":
+		return fmt.Errorf("split_on_capacity doesn't apply on a dedicated VM host, which has no capacity errors")
+	}
+	return nil
+}
+
+// validateUpgrade checks upgrade_existing has a single instance of a flexible shape to
+// compare with.
+func validateUpgrade(a *AccountConfig) error {
+	if !a.UpgradeExisting {
+		return nil
+	}
+	switch {
+	case a.Shapes()[0].Fixed:
+		return fmt.Errorf("upgrade_existing needs a flexible shape, %s has a single size", a.Shape)
+	case a.InstanceCount > 1:
+		return fmt.Errorf("upgrade_existing replaces a single instance; it doesn't work with instance_count")
+	case a.SplitOnCapacity:
+		return fmt.Errorf("upgrade_existing and split_on_capacity both replace a smaller instance; split_on_capacity already merges its slices up")
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// SweepConfig tries every availability domain and fault domain of the region in one
+// cycle, one launch request after the other, for when capacity may just have appeared.
+type SweepConfig struct {
+	Enabled      bool `yaml:"enabled"`
+	DelaySeconds int  `yaml:"delay_seconds,omitempty"` // Between two launch requests; default 2 (DefaultSweepDelaySeconds).
+	MaxRequests  int  `yaml:"max_requests,omitempty"`  // Launch requests per cycle at most; default 9 (DefaultSweepMaxRequests).
+}
+
+// Sweep defaults: a three-AD region has nine AD and fault domain combinations.
+const (
+	DefaultSweepDelaySeconds = 2
+	DefaultSweepMaxRequests  = 9
+)
+
+// Delay returns the pause between two launch requests of a sweep.
+func (s SweepConfig) Delay() time.Duration {
+	return time.Duration(s.DelaySeconds) * time.Second
+}
+
+// validateSweep checks the account's sweep and fills in its defaults.
+func validateSweep(a *AccountConfig) error {
+	s := &a.Sweep
+	if !s.Enabled {
+		return nil
+	}
+	switch {
+	case s.DelaySeconds < 0:
+		return fmt.Errorf("sweep.delay_seconds must not be negative")
+	case s.MaxRequests < 0:
+		return fmt.Errorf("sweep.max_requests must not be negative")
+	case a.DedicatedVMHostOCID != "":
+		return fmt.Errorf("sweep doesn't apply to a dedicated VM host, which has its own fault domain")
+	}
+	if s.DelaySeconds == 0 {
+		s.DelaySeconds = DefaultSweepDelaySeconds
+	}
+	if s.MaxRequests == 0 {
+		s.MaxRequests = DefaultSweepMaxRequests
+	}
+	return nil
+}
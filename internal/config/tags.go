@@ -0,0 +1,58 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OCI limits for tag keys and values.
+const (
+	maxTagKeyLength   = 100
+	maxTagValueLength = 256
+)
+
+// validateTags checks the account's freeform_tags and defined_tags against OCI's rules
+// for tag names, so a typo fails at load time instead of on every launch.
+func validateTags(a *AccountConfig) error {
+	for key, value := range a.FreeformTags {
+		if err := validateTag(key, value); err != nil {
+			return fmt.Errorf("freeform_tags: %w", err)
+		}
+	}
+	for namespace, tags := range a.DefinedTags {
+		if err := validateTagName(namespace); err != nil {
+			return fmt.Errorf("defined_tags: namespace %w", err)
+		}
+		if strings.Contains(namespace, ".") {
+			return fmt.Errorf("defined_tags: namespace '%s' must not contain '.'", namespace)
+		}
+		for key, value := range tags {
+			if err := validateTag(key, value); err != nil {
+				return fmt.Errorf("defined_tags.%s: %w", namespace, err)
+			}
+		}
+	}
+	return nil
+}
+
+func validateTag(key, value string) error {
+	if err := validateTagName(key); err != nil {
+		return err
+	}
+	if len(value) > maxTagValueLength {
+		return fmt.Errorf("value of '%s' is longer than %d characters", key, maxTagValueLength)
+	}
+	return nil
+}
+
+func validateTagName(name string) error {
+	switch {
+	case name == "":
+		return fmt.Errorf("names must not be empty")
+	case strings.ContainsAny(name, " \t\n"):
+		return fmt.Errorf("'%s' must not contain spaces", name)
+	case len(name) > maxTagKeyLength:
+		return fmt.Errorf("'%s' is longer than %d characters", name, maxTagKeyLength)
+	}
+	return nil
+}
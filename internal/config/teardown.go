@@ -0,0 +1,74 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// TeardownConfig terminates an account's instances once they reach an age or a date, for
+// temporary experiments or cycling free-tier resources.
+type TeardownConfig struct {
+	AfterDays      int    `yaml:"after_days,omitempty"`       // Days after the instance was created.
+	At             string `yaml:"at,omitempty"`               // A date like "2025-12-31" (midnight, local time) or an RFC 3339 time.
+	WarnHours      int    `yaml:"warn_hours,omitempty"`       // Notify this long before; default 24 (DefaultTeardownWarnHours).
+	KeepBootVolume bool   `yaml:"keep_boot_volume,omitempty"` // Keep the boot volume; it still counts against the free tier.
+	Relaunch       bool   `yaml:"relaunch,omitempty"`         // Hunt for a new instance afterwards instead of pausing the account.
+}
+
+// DefaultTeardownWarnHours is how long before a teardown the warning is sent when
+// warn_hours is not set.
+const DefaultTeardownWarnHours = 24
+
+// Enabled reports whether instances are torn down at all.
+func (t TeardownConfig) Enabled() bool {
+	return t.AfterDays > 0 || t.At != ""
+}
+
+// Due returns when an instance created at created is torn down: the earlier of
+// after_days and at. It returns false if teardown is not configured.
+func (t TeardownConfig) Due(created time.Time) (time.Time, bool) {
+	var due time.Time
+	if t.AfterDays > 0 {
+		due = created.AddDate(0, 0, t.AfterDays)
+	}
+	if at, err := parseTeardownAt(t.At); err == nil && !at.IsZero() && (due.IsZero() || at.Before(due)) {
+		due = at
+	}
+	return due, !due.IsZero()
+}
+
+// Warning returns how long before the teardown the warning is sent.
+func (t TeardownConfig) Warning() time.Duration {
+	if t.WarnHours > 0 {
+		return time.Duration(t.WarnHours) * time.Hour
+	}
+	return DefaultTeardownWarnHours * time.Hour
+}
+
+// parseTeardownAt parses the at option; an empty one is the zero time.
+func parseTeardownAt(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if at, err := time.Parse(time.RFC3339, s); err == nil {
+		return at, nil
+	}
+	return time.ParseInLocation("2006-01-02", s, time.Local)
+}
+
+// validateTeardown checks the account's teardown schedule.
+func validateTeardown(t TeardownConfig) error {
+	if t.AfterDays < 0 {
+		return fmt.Errorf("teardown: after_days must not be negative (got %d)", t.AfterDays)
+	}
+	if t.WarnHours < 0 {
+		return fmt.Errorf("teardown: warn_hours must not be negative (got %d)", t.WarnHours)
+	}
+	if _, err := parseTeardownAt(t.At); err != nil {
+		return fmt.Errorf("teardown: at must be a date like 2025-12-31 or an RFC 3339 time, got '%s'", t.At)
+	}
+	if !t.Enabled() && (t.WarnHours > 0 || t.KeepBootVolume || t.Relaunch) {
+		return fmt.Errorf("teardown: set after_days or at")
+	}
+	return nil
+}
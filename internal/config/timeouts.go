@@ -0,0 +1,39 @@
+package config
+
+import "fmt"
+
+// TimeoutsConfig bounds the steps of a launch.
+type TimeoutsConfig struct {
+	LaunchSeconds int `yaml:"launch_seconds"` // One launch attempt, with its lookups; default 60.
+	VerifyMinutes int `yaml:"verify_minutes"` // Checking a launched instance: RUNNING, shape and IPs; default 6.
+
+	// PollIntervalSeconds is how often instance state is polled; GetInstance results are
+	// cached this long. Default: scheduler.poll_interval_seconds (10).
+	PollIntervalSeconds int `yaml:"poll_interval_seconds,omitempty"`
+}
+
+// Timeout defaults and the shortest values accepted. A verification needs a minute past
+// the wait for RUNNING to look up the shape and IPs.
+const (
+	DefaultLaunchSeconds = 60
+	DefaultVerifyMinutes = 6
+	MinLaunchSeconds     = 10
+	MinVerifyMinutes     = 2
+	MinPollInterval      = 5
+)
+
+// validateTimeouts checks the timeouts; poll_interval_seconds falls back to poll, the
+// scheduler's.
+func validateTimeouts(t *TimeoutsConfig, poll int) error {
+	switch {
+	case t.LaunchSeconds < MinLaunchSeconds:
+		return fmt.Errorf("timeouts.launch_seconds: expected at least %d, got %d", MinLaunchSeconds, t.LaunchSeconds)
+	case t.VerifyMinutes < MinVerifyMinutes:
+		return fmt.Errorf("timeouts.verify_minutes: expected at least %d, got %d", MinVerifyMinutes, t.VerifyMinutes)
+	case t.PollIntervalSeconds == 0:
+		t.PollIntervalSeconds = poll
+	case t.PollIntervalSeconds < MinPollInterval:
+		return fmt.Errorf("timeouts.poll_interval_seconds: expected at least %d, got %d", MinPollInterval, t.PollIntervalSeconds)
+	}
+	return nil
+}
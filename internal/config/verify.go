@@ -0,0 +1,25 @@
+package config
+
+import "fmt"
+
+// DefaultCloudInitTimeoutMinutes bounds the wait of verify.wait_for_cloud_init.
+const DefaultCloudInitTimeoutMinutes = 20
+
+// VerifyConfig adds checks to the verification of a launched instance.
+type VerifyConfig struct {
+	// WaitForCloudInit holds the success notification until cloud-init reports on the
+	// serial console that it finished, so the instance is set up when you hear about it.
+	WaitForCloudInit        bool `yaml:"wait_for_cloud_init"`
+	CloudInitTimeoutMinutes int  `yaml:"cloud_init_timeout_minutes,omitempty"` // Give up after this long; default 20.
+}
+
+// validateVerify checks the account's verify settings and fills in the default timeout.
+func validateVerify(v *VerifyConfig) error {
+	switch {
+	case v.CloudInitTimeoutMinutes < 0:
+		return fmt.Errorf("verify.cloud_init_timeout_minutes must not be negative, got %d", v.CloudInitTimeoutMinutes)
+	case v.CloudInitTimeoutMinutes == 0:
+		v.CloudInitTimeoutMinutes = DefaultCloudInitTimeoutMinutes
+	}
+	return nil
+}
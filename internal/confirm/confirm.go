@@ -0,0 +1,67 @@
+// Package confirm asks before a command changes or deletes something that can't be
+// undone, listing exactly what it affects. Scripts skip the prompt with --yes.
+package confirm
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ErrDeclined is returned when the user answers anything but yes.
+var ErrDeclined = errors.New("aborted, nothing was changed")
+
+// Action describes what a command is about to do.
+type Action struct {
+	What    string   // e.g. "Revert config.yaml to change #3".
+	Targets []string // The files (or services, OCIDs) affected, one per line.
+}
+
+// Prompt asks for confirmation on In and writes the question to Out.
+type Prompt struct {
+	In          io.Reader
+	Out         io.Writer
+	Yes         bool // --yes: don't ask.
+	Interactive bool // In is a terminal; without one and without Yes, nothing is done.
+}
+
+// Terminal returns a Prompt on stdin and stderr, so the question doesn't end up in
+// redirected output.
+func Terminal(yes bool) Prompt {
+	return Prompt{In: os.Stdin, Out: os.Stderr, Yes: yes, Interactive: IsTerminal(os.Stdin)}
+}
+
+// IsTerminal reports whether f is a character device rather than a pipe or file.
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// Confirm lists a's targets and returns nil if the user agrees (or Yes is set). Without
+// a terminal it refuses rather than reading an answer from a pipe.
+func (p Prompt) Confirm(a Action) error {
+	fmt.Fprintf(p.Out, "⚠️  %s:\n", a.What)
+	for _, t := range a.Targets {
+		fmt.Fprintf(p.Out, "   - %s\n", t)
+	}
+	if p.Yes {
+		return nil
+	}
+	if !p.Interactive {
+		return fmt.Errorf("not asking without a terminal: pass --yes to confirm")
+	}
+
+	fmt.Fprint(p.Out, "Continue? (y/n): ")
+	answer, err := bufio.NewReader(p.In).ReadString('\n')
+	if err != nil && answer == "" {
+		return ErrDeclined
+	}
+	answer = strings.TrimSpace(answer)
+	if !strings.EqualFold(answer, "y") && !strings.EqualFold(answer, "yes") {
+		return ErrDeclined
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package confirm
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestConfirm(t *testing.T) {
+	uninstall := Action{What: "Remove service", Targets: []string{"oci-arm-provisioner"}}
+	rollback := Action{What: "Revert change #3", Targets: []string{"config.yaml"}}
+
+	for _, tc := range []struct {
+		name        string
+		action      Action
+		input       string
+		yes, noTerm bool
+		wantErr     string
+	}{
+		{"yes flag", uninstall, "", true, true, ""},
+		{"no terminal", rollback, "y\n", false, true, "pass --yes"},
+		{"answered y", rollback, "Y\n", false, false, ""},
+		{"answered no", rollback, "n\n", false, false, ErrDeclined.Error()},
+		{"empty input", rollback, "", false, false, ErrDeclined.Error()},
+		{"answered yes", uninstall, "yes\n", false, false, ""},
+	} {
+		var out bytes.Buffer
+		p := Prompt{In: strings.NewReader(tc.input), Out: &out, Yes: tc.yes, Interactive: !tc.noTerm}
+		err := p.Confirm(tc.action)
+		if tc.wantErr == "" && err != nil {
+			t.Errorf("%s: unexpected error %v", tc.name, err)
+		}
+		if tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)) {
+			t.Errorf("%s: expected a %q error, got %v", tc.name, tc.wantErr, err)
+		}
+		for _, target := range tc.action.Targets {
+			if !strings.Contains(out.String(), target) {
+				t.Errorf("%s: expected %s listed in:\n%s", tc.name, target, out.String())
+			}
+		}
+	}
+
+	var out bytes.Buffer
+	err := Prompt{In: strings.NewReader("nope\n"), Out: &out, Interactive: true}.Confirm(uninstall)
+	if !errors.Is(err, ErrDeclined) || !strings.Contains(out.String(), "Continue? (y/n)") {
+		t.Errorf("expected a declined prompt, got %v:\n%s", err, out.String())
+	}
+}
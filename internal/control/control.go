@@ -0,0 +1,136 @@
+// Package control reads commands dropped as JSON files into a watched directory, so the
+// provisioner can be paused, resumed, triggered or reloaded without a control port or bot.
+package control
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Actions a command file can ask for.
+const (
+	ActionPause   = "pause"   // Pause everything, or only Account.
+	ActionResume  = "resume"  // Resume everything, or only Account.
+	ActionTrigger = "trigger" // Run a cycle now instead of waiting for the next one.
+	ActionReload  = "reload"  // Re-read config.yaml.
+)
+
+// Actions are the accepted values of Command.Action.
+var Actions = []string{ActionPause, ActionResume, ActionTrigger, ActionReload}
+
+// PollInterval is how often the directory is checked for new files.
+const PollInterval = 2 * time.Second
+
+// FailedSuffix is appended to command files that could not be read, for the user to inspect.
+const FailedSuffix = ".failed"
+
+// Command is the content of a command file, e.g. {"action": "pause", "account": "work"}.
+type Command struct {
+	Action  string `json:"action"`
+	Account string `json:"account,omitempty"` // Only for pause and resume; empty means all accounts.
+
+	File string `json:"-"` // Name of the file the command came from.
+}
+
+// String describes c for the log.
+func (c Command) String() string {
+	if c.Account != "" {
+		return fmt.Sprintf("%s %s (%s)", c.Action, c.Account, c.File)
+	}
+	return fmt.Sprintf("%s (%s)", c.Action, c.File)
+}
+
+// Parse decodes and checks the content of a command file.
+func Parse(data []byte) (Command, error) {
+	var c Command
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&c); err != nil {
+		return c, fmt.Errorf("invalid JSON: %w", err)
+	}
+	c.Action = strings.ToLower(strings.TrimSpace(c.Action))
+	if !slices.Contains(Actions, c.Action) {
+		return c, fmt.Errorf("action must be one of %s, got %q", strings.Join(Actions, ", "), c.Action)
+	}
+	if c.Account != "" && c.Action != ActionPause && c.Action != ActionResume {
+		return c, fmt.Errorf("account only applies to pause and resume")
+	}
+	return c, nil
+}
+
+// Take reads the *.json files in dir in name order and removes them, so each command
+// runs once. Files that can't be parsed are renamed with FailedSuffix and reported
+// through onError. Hidden files are skipped, so editors and "write, then rename" tools
+// can stage a command as ".cmd.json" first.
+func Take(dir string, onError func(file string, err error)) []Command {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		onError(dir, err)
+		return nil
+	}
+	var cmds []Command
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || strings.HasPrefix(name, ".") || filepath.Ext(name) != ".json" {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			onError(name, err)
+			continue
+		}
+		cmd, err := Parse(data)
+		if err != nil {
+			onError(name, err)
+			if err := os.Rename(path, path+FailedSuffix); err != nil {
+				onError(name, err)
+			}
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			// Running it again every poll would be worse than not running it
+			onError(name, fmt.Errorf("not run, could not remove it: %w", err))
+			continue
+		}
+		cmd.File = name
+		cmds = append(cmds, cmd)
+	}
+	return cmds
+}
+
+// Watch polls dir until ctx is done and sends every command found. The directory is
+// created if it doesn't exist.
+func Watch(ctx context.Context, dir string, onError func(file string, err error)) <-chan Command {
+	out := make(chan Command)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		onError(dir, err)
+	}
+	go func() {
+		defer close(out)
+		ticker := time.NewTicker(PollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			for _, cmd := range Take(dir, onError) {
+				select {
+				case out <- cmd:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out
+}
@@ -0,0 +1,98 @@
+package control
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	for input, wantErr := range map[string]string{
+		`{"action": "pause"}`:                     "",
+		`{"action": "Resume", "account": "work"}`: "",
+		`{"action": "trigger"}`:                   "",
+		`{"action": "reload"}`:                    "",
+		`{"action": "stop"}`:                      "action must be one of",
+		`{"action": "trigger", "account": "w"}`:   "only applies to pause and resume",
+		`{"action": "pause", "acount": "work"}`:   "unknown field",
+		`pause`:                                   "invalid JSON",
+	} {
+		_, err := Parse([]byte(input))
+		if wantErr == "" && err != nil {
+			t.Errorf("%s: unexpected error %v", input, err)
+		}
+		if wantErr != "" && (err == nil || !strings.Contains(err.Error(), wantErr)) {
+			t.Errorf("%s: expected a %q error, got %v", input, wantErr, err)
+		}
+	}
+
+	cmd, _ := Parse([]byte(`{"action": " RESUME ", "account": "work"}`))
+	if cmd.Action != ActionResume || cmd.Account != "work" {
+		t.Errorf("unexpected command %+v", cmd)
+	}
+}
+
+func TestTake(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("02-resume.json", `{"action": "resume", "account": "work"}`)
+	write("01-pause.json", `{"action": "pause"}`)
+	write("03-bad.json", `{"action": "explode"}`)
+	write(".staged.json", `{"action": "reload"}`)
+	write("notes.txt", `{"action": "reload"}`)
+
+	var failed []string
+	cmds := Take(dir, func(file string, err error) { failed = append(failed, file) })
+
+	if len(cmds) != 2 || cmds[0].Action != ActionPause || cmds[1].Account != "work" || cmds[0].File != "01-pause.json" {
+		t.Fatalf("expected pause then resume work, got %+v", cmds)
+	}
+	if len(failed) != 1 || failed[0] != "03-bad.json" {
+		t.Errorf("expected 03-bad.json to fail, got %v", failed)
+	}
+
+	var left []string
+	entries, _ := os.ReadDir(dir)
+	for _, e := range entries {
+		left = append(left, e.Name())
+	}
+	if strings.Join(left, ",") != ".staged.json,03-bad.json.failed,notes.txt" {
+		t.Errorf("expected run commands removed and the bad one kept, got %v", left)
+	}
+
+	// Each command runs once
+	if cmds := Take(dir, func(string, error) {}); len(cmds) != 0 {
+		t.Errorf("expected no commands the second time, got %+v", cmds)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "control")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	commands := Watch(ctx, dir, func(file string, err error) { t.Errorf("%s: %v", file, err) })
+	if err := os.WriteFile(filepath.Join(dir, "go.json"), []byte(`{"action": "trigger"}`), 0600); err != nil {
+		t.Fatalf("expected the directory to be created: %v", err)
+	}
+
+	select {
+	case cmd := <-commands:
+		if cmd.Action != ActionTrigger {
+			t.Errorf("expected trigger, got %+v", cmd)
+		}
+	case <-time.After(3 * PollInterval):
+		t.Fatal("no command received")
+	}
+
+	cancel()
+	for range commands {
+	}
+}
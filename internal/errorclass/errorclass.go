@@ -0,0 +1,88 @@
+// Package errorclass sorts the errors of OCI API calls into the outcomes the provisioner
+// reacts to differently: capacity to wait for, limits to check, rate limits to back off
+// from, credentials or policies to fix, and faults of the network or OCI itself.
+package errorclass
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+)
+
+// Class is the kind of an API call outcome.
+type Class string
+
+const (
+	None             Class = ""                  // No error.
+	Capacity         Class = "capacity"          // Out of host capacity; retry later or elsewhere.
+	Limit            Class = "limit"             // A service limit or quota is used up.
+	RateLimit        Class = "rate_limit"        // 429 TooManyRequests; back off.
+	Auth             Class = "auth"              // 401 NotAuthenticated, or unusable local credentials.
+	NotAuthorized    Class = "not_authorized"    // 403 NotAuthorized, or 404 NotAuthorizedOrNotFound: a missing policy.
+	InvalidParameter Class = "invalid_parameter" // 400 InvalidParameter or MissingParameter: a config error.
+	Server           Class = "server"            // Any other 5xx: OCI misbehaving.
+	Network          Class = "network"           // The request never got an answer: DNS, TLS, resets, timeouts.
+	Canceled         Class = "canceled"          // The caller gave up, e.g. on shutdown.
+	Other            Class = "other"             // Any other answer from OCI.
+)
+
+// Classifier is implemented by errors that know their class, such as local credential
+// problems that should be treated like OCI rejecting the key.
+type Classifier interface {
+	ErrorClass() Class
+}
+
+// Of returns the class of err, looking through wrapping errors.
+func Of(err error) Class {
+	if err == nil {
+		return None
+	}
+	var c Classifier
+	if errors.As(err, &c) {
+		return c.ErrorClass()
+	}
+	var serviceErr common.ServiceError
+	if !errors.As(err, &serviceErr) {
+		if errors.Is(err, context.Canceled) {
+			return Canceled
+		}
+		return Network
+	}
+
+	status, code := serviceErr.GetHTTPStatusCode(), serviceErr.GetCode()
+	msg := strings.ToLower(serviceErr.GetMessage())
+	switch {
+	case code == "LimitExceeded" || strings.Contains(msg, "service limit"):
+		return Limit
+	case code == "OutOfHostCapacity" || code == "OutOfCapacity" || strings.Contains(msg, "capacity"):
+		return Capacity
+	case status == 429 || code == "TooManyRequests":
+		return RateLimit
+	case status == 401 || code == "NotAuthenticated":
+		return Auth
+	case status == 403 || code == "NotAuthorized" || code == "NotAuthorizedOrNotFound":
+		return NotAuthorized
+	case code == "InvalidParameter" || code == "MissingParameter":
+		return InvalidParameter
+	case status >= 500:
+		return Server
+	}
+	return Other
+}
+
+// Retryable reports whether the same request may succeed later without a config change.
+func (c Class) Retryable() bool {
+	switch c {
+	case Capacity, RateLimit, Server, Network:
+		return true
+	}
+	return false
+}
+
+// Transient reports whether c means a misbehaving network or OCI endpoint rather than a
+// meaningful answer. Capacity errors and rate limits are expected answers.
+func (c Class) Transient() bool {
+	return c == Server || c == Network
+}
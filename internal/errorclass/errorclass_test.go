@@ -0,0 +1,63 @@
+package errorclass
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+)
+
+type serviceError struct {
+	status        int
+	code, message string
+}
+
+func (e serviceError) Error() string           { return e.message }
+func (e serviceError) GetHTTPStatusCode() int  { return e.status }
+func (e serviceError) GetMessage() string      { return e.message }
+func (e serviceError) GetCode() string         { return e.code }
+func (e serviceError) GetOpcRequestID() string { return "req-id" }
+
+type localAuthError struct{}
+
+func (localAuthError) Error() string     { return "key file unreadable" }
+func (localAuthError) ErrorClass() Class { return Auth }
+
+func TestOf(t *testing.T) {
+	for _, c := range []struct {
+		err  error
+		want Class
+	}{
+		{nil, None},
+		{serviceError{500, "InternalError", "Out of host capacity."}, Capacity},
+		{serviceError{400, "LimitExceeded", "The following service limits were exceeded: standard-a1-core-count"}, Limit},
+		{serviceError{429, "TooManyRequests", "Too many requests for the user"}, RateLimit},
+		{serviceError{401, "NotAuthenticated", "The required information to complete authentication was not provided"}, Auth},
+		{serviceError{404, "NotAuthorizedOrNotFound", "Authorization failed or requested resource not found"}, NotAuthorized},
+		{serviceError{400, "InvalidParameter", "Invalid hostnameLabel"}, InvalidParameter},
+		{serviceError{502, "BadGateway", "Bad Gateway"}, Server},
+		{serviceError{409, "Conflict", "Conflict"}, Other},
+		{fmt.Errorf("launch: %w", serviceError{429, "TooManyRequests", "slow down"}), RateLimit},
+		{fmt.Errorf("credentials: %w", localAuthError{}), Auth},
+		{io.ErrUnexpectedEOF, Network},
+		{fmt.Errorf("list: %w", context.Canceled), Canceled},
+	} {
+		if got := Of(c.err); got != c.want {
+			t.Errorf("Of(%v) = %q, want %q", c.err, got, c.want)
+		}
+	}
+}
+
+func TestClass_Retryable(t *testing.T) {
+	for class, want := range map[Class]bool{
+		Capacity: true, RateLimit: true, Server: true, Network: true,
+		Limit: false, Auth: false, NotAuthorized: false, InvalidParameter: false, Canceled: false,
+	} {
+		if got := class.Retryable(); got != want {
+			t.Errorf("%s.Retryable() = %v, want %v", class, got, want)
+		}
+	}
+	if !Server.Transient() || !Network.Transient() || Capacity.Transient() || RateLimit.Transient() {
+		t.Error("expected only server and network errors to be transient")
+	}
+}
@@ -0,0 +1,215 @@
+// Package fixtures generates anonymized sample installs (config, keys, state and attempt
+// history) for integration tests of config loading, reloads and the TUI.
+package fixtures
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	mrand "math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/yourusername/oci-arm-provisioner/internal/config"
+	"github.com/yourusername/oci-arm-provisioner/internal/state"
+)
+
+// Shape describes one kind of install.
+type Shape struct {
+	Name        string
+	Description string
+	Accounts    int
+	Launched    int  // Accounts whose history ends with an instance launched.
+	BrokenKey   bool // The first account's key is truncated, so LoadConfig fails.
+}
+
+// Shapes are the installs Generate can write.
+var Shapes = []Shape{
+	{Name: "single", Description: "One account, still hunting", Accounts: 1},
+	{Name: "five", Description: "Five accounts in different regions, two already launched", Accounts: 5, Launched: 2},
+	{Name: "broken-key", Description: "One account whose credentials_dir holds a truncated key", Accounts: 1, BrokenKey: true},
+}
+
+// Lookup returns the shape with the given name.
+func Lookup(name string) (Shape, bool) {
+	for _, s := range Shapes {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return Shape{}, false
+}
+
+// Files written by Generate, relative to its directory.
+const (
+	ConfigFile  = "config.yaml"
+	StateFile   = "state.json"
+	HistoryFile = "logs/provisioner.log"
+)
+
+var (
+	accountNames = []string{"alpha", "bravo", "charlie", "delta", "echo"}
+	regions      = []string{"eu-frankfurt-1", "us-ashburn-1", "uk-london-1", "ap-tokyo-1", "sa-saopaulo-1"}
+)
+
+// historyStart is when every generated history begins, so histories only differ by seed.
+var historyStart = time.Date(2025, 1, 6, 8, 0, 0, 0, time.Local)
+
+// Generate writes an install of the given shape to dir. The same seed gives the same
+// OCIDs and history; keys are always fresh.
+func Generate(dir string, shape Shape, seed int64) error {
+	if shape.Accounts > len(accountNames) {
+		return fmt.Errorf("shape %s: at most %d accounts", shape.Name, len(accountNames))
+	}
+	rng := mrand.New(mrand.NewSource(seed))
+	if err := os.MkdirAll(filepath.Join(dir, "logs"), 0755); err != nil {
+		return err
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+
+	var cfg strings.Builder
+	cfg.WriteString("# Generated by `oci-arm-provisioner genfixtures`; all identifiers are fake.\naccounts:\n")
+	var accounts []string
+	for i := range shape.Accounts {
+		name := accountNames[i]
+		accounts = append(accounts, name)
+		broken := shape.BrokenKey && i == 0
+		if err := writeAccount(&cfg, dir, name, regions[i], broken, rng); err != nil {
+			return fmt.Errorf("account %s: %w", name, err)
+		}
+	}
+	cfg.WriteString(`
+scheduler:
+  cycle_interval_seconds: 60
+  account_delay_seconds: 10
+
+logging:
+  log_dir: logs
+`)
+	if err := os.WriteFile(filepath.Join(dir, ConfigFile), []byte(cfg.String()), 0600); err != nil {
+		return err
+	}
+	return writeHistory(dir, accounts, shape.Launched, rng)
+}
+
+// writeAccount appends an account to cfg and writes its key. A broken account gets a
+// credentials_dir whose key is cut short; the others use key_file.
+func writeAccount(cfg *strings.Builder, dir, name, region string, broken bool, rng *mrand.Rand) error {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return err
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return err
+	}
+	sum := md5.Sum(der)
+	hex := make([]string, len(sum))
+	for i, b := range sum {
+		hex[i] = fmt.Sprintf("%02x", b)
+	}
+	fingerprint := strings.Join(hex, ":")
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	user, tenancy := ocid("user", rng), ocid("tenancy", rng)
+
+	fmt.Fprintf(cfg, "  %s:\n    enabled: true\n", name)
+	if broken {
+		credDir := filepath.Join(dir, "credentials", name)
+		if err := os.MkdirAll(credDir, 0700); err != nil {
+			return err
+		}
+		// Only the owner may write these, or LoadConfig rejects them before reading the key
+		os.Chmod(credDir, 0700)
+		profile := fmt.Sprintf("[DEFAULT]\nuser=%s\nfingerprint=%s\ntenancy=%s\nregion=%s\n", user, fingerprint, tenancy, region)
+		if err := os.WriteFile(filepath.Join(credDir, config.CredentialsMetadata), []byte(profile), 0600); err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(credDir, config.CredentialsKeyFile), keyPEM[:len(keyPEM)/2], 0600); err != nil {
+			return err
+		}
+		fmt.Fprintf(cfg, "    credentials_dir: %q\n", credDir)
+	} else {
+		keyFile := filepath.Join(dir, "keys", name+".pem")
+		if err := os.MkdirAll(filepath.Dir(keyFile), 0700); err != nil {
+			return err
+		}
+		if err := os.WriteFile(keyFile, keyPEM, 0600); err != nil {
+			return err
+		}
+		fmt.Fprintf(cfg, "    user_ocid: %q\n    tenancy_ocid: %q\n    fingerprint: %q\n    key_file: %q\n    region: %q\n",
+			user, tenancy, fingerprint, keyFile, region)
+	}
+	fmt.Fprintf(cfg, `    compartment_id: %q
+    subnet_id: %q
+    image_id: %q
+    ocpus: 4
+    memory_gb: 24
+    boot_volume_size_gb: 50
+    display_name: "arm-%s"
+`, ocid("compartment", rng), ocid("subnet", rng), ocid("image", rng), name)
+	return nil
+}
+
+// writeHistory writes a log of capacity errors for every account, ending with a launch
+// for the first launched accounts, and the state those attempts leave behind.
+func writeHistory(dir string, accounts []string, launched int, rng *mrand.Rand) error {
+	st, _ := state.Open(nil)
+	var log strings.Builder
+	line := func(at time.Time, account, level, msg string) {
+		fmt.Fprintf(&log, "%s [%s] [%s] %s\n", at.Format("2006/01/02 15:04:05"), account, level, msg)
+	}
+
+	at := historyStart
+	cycles := 50 + rng.Intn(100)
+	for cycle := range cycles {
+		fmt.Fprintf(&log, "%s [SECTION] === Cycle %d Started at %s ===\n", at.Format("2006/01/02 15:04:05"), cycle+1, at.Format("2006-01-02 15:04:05"))
+		for i, name := range accounts {
+			if st.Sequence(name) > 0 {
+				continue
+			}
+			line(at, name, "INFO", fmt.Sprintf("Launching instance 'arm-%s'...", name))
+			at = at.Add(time.Duration(2+rng.Intn(4)) * time.Second)
+			// Launched accounts succeed at staggered cycles, the first one in the last cycle
+			if i < launched && cycle == cycles-1-i*cycles/(launched+1) {
+				id := ocid("instance", rng)
+				line(at, name, "SUCCESS", "Instance Launched: "+id)
+				st.RecordAttempt(name, false, at)
+				st.RecordInstance(name, state.Instance{ID: id, Name: "arm-" + name, PublicIP: fmt.Sprintf("203.0.113.%d", 10+i)})
+				st.CommitSequence(name, 1)
+				continue
+			}
+			line(at, name, "WARN", "Capacity/Limit error. Will retry.")
+			st.RecordAttempt(name, true, at)
+			at = at.Add(10 * time.Second)
+		}
+		at = at.Add(time.Minute)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, HistoryFile), []byte(log.String()), 0644); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+	return state.FileBackend{Path: filepath.Join(dir, StateFile)}.Write(data)
+}
+
+// ocid returns a fake OCID of the given resource type.
+func ocid(kind string, rng *mrand.Rand) string {
+	const chars = "abcdefghijklmnopqrstuvwxyz234567"
+	b := make([]byte, 52)
+	for i := range b {
+		b[i] = chars[rng.Intn(len(chars))]
+	}
+	return fmt.Sprintf("ocid1.%s.oc1..aaaaaaaa%s", kind, b)
+}
@@ -0,0 +1,101 @@
+package fixtures
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/oci-arm-provisioner/internal/config"
+	"github.com/yourusername/oci-arm-provisioner/internal/provisioner"
+	"github.com/yourusername/oci-arm-provisioner/internal/state"
+)
+
+func TestGenerate(t *testing.T) {
+	for _, tc := range []struct {
+		shape     string
+		accounts  int
+		instances int
+		wantErr   string
+	}{
+		{shape: "single", accounts: 1},
+		{shape: "five", accounts: 5, instances: 2},
+		{shape: "broken-key", wantErr: "no PEM block"},
+	} {
+		t.Run(tc.shape, func(t *testing.T) {
+			shape, ok := Lookup(tc.shape)
+			if !ok {
+				t.Fatalf("unknown shape %s", tc.shape)
+			}
+			dir := t.TempDir()
+			if err := Generate(dir, shape, 1); err != nil {
+				t.Fatalf("Generate failed: %v", err)
+			}
+
+			cfg, _, err := config.LoadConfig(filepath.Join(dir, ConfigFile))
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("expected a %q error, got %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadConfig failed: %v", err)
+			}
+			if len(cfg.Accounts) != tc.accounts {
+				t.Errorf("expected %d accounts, got %d", tc.accounts, len(cfg.Accounts))
+			}
+
+			st, err := state.Open(state.FileBackend{Path: cfg.StateFile})
+			if err != nil {
+				t.Fatalf("state.Open failed: %v", err)
+			}
+			instances := 0
+			for name := range cfg.Accounts {
+				instances += len(st.Instances(name))
+				if st.Accounts[name] == nil || st.Accounts[name].Attempts == 0 {
+					t.Errorf("account %s has no attempts in the state", name)
+				}
+			}
+			if instances != tc.instances {
+				t.Errorf("expected %d instances in the state, got %d", tc.instances, instances)
+			}
+
+			f, err := os.Open(filepath.Join(dir, HistoryFile))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+			obs, err := provisioner.ReadHistory(f)
+			if err != nil {
+				t.Fatalf("ReadHistory failed: %v", err)
+			}
+			attempts, successes := 0, 0
+			for _, acc := range st.Accounts {
+				attempts += acc.Attempts
+			}
+			for _, o := range obs {
+				if o.Success {
+					successes++
+				}
+			}
+			if len(obs) != attempts || successes != tc.instances {
+				t.Errorf("history has %d attempts (%d successes), state has %d (%d instances)", len(obs), successes, attempts, tc.instances)
+			}
+		})
+	}
+}
+
+func TestGenerate_Deterministic(t *testing.T) {
+	shape, _ := Lookup("five")
+	a, b := t.TempDir(), t.TempDir()
+	Generate(a, shape, 42)
+	Generate(b, shape, 42)
+	for _, file := range []string{StateFile, HistoryFile} {
+		x, _ := os.ReadFile(filepath.Join(a, file))
+		y, _ := os.ReadFile(filepath.Join(b, file))
+		if len(x) == 0 || string(x) != string(y) {
+			t.Errorf("%s differs between runs with the same seed", file)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+// Package iphook tells the user's own firewall or allowlist about an instance's public
+// IP, by POSTing to a webhook and/or running a command.
+package iphook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/yourusername/oci-arm-provisioner/internal/buildinfo"
+	"github.com/yourusername/oci-arm-provisioner/internal/config"
+)
+
+// DefaultTimeout bounds each webhook call or command run.
+const DefaultTimeout = 30 * time.Second
+
+// Event is a new or changed public IP.
+type Event struct {
+	Account    string `json:"account"`
+	InstanceID string `json:"instance_id"`
+	PublicIP   string `json:"public_ip"`
+	PreviousIP string `json:"previous_ip"`          // Empty after a launch or a first discovery.
+	AttemptID  string `json:"attempt_id,omitempty"` // The launch attempt that got the IP, if any.
+}
+
+// Hook runs the configured webhook and command. Client is overridable for tests.
+type Hook struct {
+	Config config.IPHookConfig
+	Client *http.Client
+}
+
+// New creates a Hook, or returns nil if none is configured.
+func New(cfg config.IPHookConfig) *Hook {
+	if !cfg.Enabled() {
+		return nil
+	}
+	return &Hook{Config: cfg, Client: &http.Client{}}
+}
+
+// Run delivers ev to the webhook and the command. Both are attempted; the errors of
+// either are returned together. A nil *Hook does nothing.
+func (h *Hook) Run(ctx context.Context, ev Event) error {
+	if h == nil {
+		return nil
+	}
+	timeout := DefaultTimeout
+	if d, err := time.ParseDuration(h.Config.Timeout); err == nil && d > 0 {
+		timeout = d
+	}
+
+	var errs []error
+	if h.Config.URL != "" {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		if err := h.post(ctx, ev); err != nil {
+			errs = append(errs, fmt.Errorf("webhook: %w", err))
+		}
+		cancel()
+	}
+	if h.Config.Command != "" {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		if err := h.exec(ctx, ev); err != nil {
+			errs = append(errs, fmt.Errorf("command: %w", err))
+		}
+		cancel()
+	}
+	return errors.Join(errs...)
+}
+
+func (h *Hook) post(ctx context.Context, ev Event) error {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.Config.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", buildinfo.UserAgent("", ""))
+
+	resp, err := h.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+func (h *Hook) exec(ctx context.Context, ev Event) error {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", h.Config.Command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", h.Config.Command)
+	}
+	cmd.Env = append(os.Environ(),
+		"OCI_ACCOUNT="+ev.Account,
+		"OCI_INSTANCE_ID="+ev.InstanceID,
+		"OCI_PUBLIC_IP="+ev.PublicIP,
+		"OCI_PREVIOUS_IP="+ev.PreviousIP,
+		"OCI_ATTEMPT_ID="+ev.AttemptID,
+	)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		if tail := strings.TrimSpace(string(out)); tail != "" {
+			return fmt.Errorf("%w: %s", err, lastLine(tail))
+		}
+		return err
+	}
+	return nil
+}
+
+// lastLine returns the last line of s, which is usually the error.
+func lastLine(s string) string {
+	return s[strings.LastIndex(s, "\n")+1:]
+}
@@ -0,0 +1,71 @@
+package iphook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/oci-arm-provisioner/internal/config"
+)
+
+var event = Event{Account: "personal", InstanceID: "ocid1.instance.test", PublicIP: "203.0.113.7", PreviousIP: "203.0.113.6"}
+
+func TestRun_Webhook(t *testing.T) {
+	var got Event
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer srv.Close()
+
+	if err := New(config.IPHookConfig{URL: srv.URL}).Run(context.Background(), event); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if got != event {
+		t.Errorf("webhook received %+v, want %+v", got, event)
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "denied", http.StatusForbidden)
+	}))
+	defer failing.Close()
+	err := New(config.IPHookConfig{URL: failing.URL}).Run(context.Background(), event)
+	if err == nil || !strings.Contains(err.Error(), "status 403: denied") {
+		t.Errorf("expected the webhook status in the error, got %v", err)
+	}
+}
+
+func TestRun_Command(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	out := filepath.Join(t.TempDir(), "ip")
+	hook := New(config.IPHookConfig{Command: `echo "$OCI_ACCOUNT $OCI_PREVIOUS_IP $OCI_PUBLIC_IP" > ` + out})
+	if err := hook.Run(context.Background(), event); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	data, _ := os.ReadFile(out)
+	if got := strings.TrimSpace(string(data)); got != "personal 203.0.113.6 203.0.113.7" {
+		t.Errorf("command saw %q", got)
+	}
+
+	err := New(config.IPHookConfig{Command: "echo nope >&2; exit 3"}).Run(context.Background(), event)
+	if err == nil || !strings.Contains(err.Error(), "nope") {
+		t.Errorf("expected the command's output in the error, got %v", err)
+	}
+}
+
+func TestNew_Disabled(t *testing.T) {
+	if New(config.IPHookConfig{}) != nil {
+		t.Error("expected no hook without url or command")
+	}
+	var h *Hook
+	if err := h.Run(context.Background(), event); err != nil {
+		t.Errorf("expected a nil hook to do nothing, got %v", err)
+	}
+}
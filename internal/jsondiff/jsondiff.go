@@ -0,0 +1,135 @@
+// Package jsondiff renders values as canonical JSON and compares two such documents
+// field by field, so a reviewer sees which values changed rather than which lines moved.
+package jsondiff
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Canonical renders v as indented JSON with the keys of every object sorted, so the same
+// value always gives the same bytes. Null fields are left out, as the OCI SDK does when it
+// sends a request.
+func Canonical(v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var generic any
+	if err := decode(data, &generic); err != nil {
+		return nil, err
+	}
+	// Maps marshal with sorted keys
+	out, err := json.MarshalIndent(dropNulls(generic), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
+
+// Diff compares two JSON documents and returns one line per changed value, ordered by
+// path: "- path: old" for removed, "+ path: new" for added and "~ path: old -> new" for
+// changed values. Array elements are compared by index.
+func Diff(old, new []byte) ([]string, error) {
+	var a, b any
+	if err := decode(old, &a); err != nil {
+		return nil, fmt.Errorf("old: %w", err)
+	}
+	if err := decode(new, &b); err != nil {
+		return nil, fmt.Errorf("new: %w", err)
+	}
+	before, after := make(map[string]string), make(map[string]string)
+	flatten("", a, before)
+	flatten("", b, after)
+
+	paths := make([]string, 0, len(before)+len(after))
+	for p := range before {
+		paths = append(paths, p)
+	}
+	for p := range after {
+		if _, ok := before[p]; !ok {
+			paths = append(paths, p)
+		}
+	}
+	slices.Sort(paths)
+
+	var lines []string
+	for _, p := range paths {
+		was, had := before[p]
+		is, has := after[p]
+		switch {
+		case !has:
+			lines = append(lines, fmt.Sprintf("- %s: %s", p, was))
+		case !had:
+			lines = append(lines, fmt.Sprintf("+ %s: %s", p, is))
+		case was != is:
+			lines = append(lines, fmt.Sprintf("~ %s: %s -> %s", p, was, is))
+		}
+	}
+	return lines, nil
+}
+
+// dropNulls removes the null fields of every object in v.
+func dropNulls(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, child := range v {
+			if child == nil {
+				delete(v, k)
+			} else {
+				v[k] = dropNulls(child)
+			}
+		}
+	case []any:
+		for i, child := range v {
+			v[i] = dropNulls(child)
+		}
+	}
+	return v
+}
+
+// decode parses JSON keeping numbers as written, so large integers survive.
+func decode(data []byte, v any) error {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	return d.Decode(v)
+}
+
+// flatten records every leaf of v under its dotted path, e.g. "main.shapeConfig.ocpus".
+// Empty objects and arrays are leaves too, so adding one shows up.
+func flatten(path string, v any, into map[string]string) {
+	switch v := v.(type) {
+	case map[string]any:
+		if len(v) == 0 {
+			into[path] = "{}"
+		}
+		for k, child := range v {
+			flatten(join(path, k), child, into)
+		}
+	case []any:
+		if len(v) == 0 {
+			into[path] = "[]"
+		}
+		for i, child := range v {
+			flatten(path+"["+strconv.Itoa(i)+"]", child, into)
+		}
+	default:
+		data, _ := json.Marshal(v)
+		into[path] = string(data)
+	}
+}
+
+// join appends key to path, quoting keys that contain a dot.
+func join(path, key string) string {
+	if strings.ContainsAny(key, ".[]") {
+		key = strconv.Quote(key)
+	}
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
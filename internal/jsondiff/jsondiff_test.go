@@ -0,0 +1,59 @@
+package jsondiff
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCanonical(t *testing.T) {
+	type inner struct {
+		Zeta  int     `json:"zeta"`
+		Alpha int     `json:"alpha"`
+		Unset *string `json:"unset"`
+	}
+	got, err := Canonical(map[string]any{"b": inner{Zeta: 1, Alpha: 2}, "a": []int{3}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{
+  "a": [
+    3
+  ],
+  "b": {
+    "alpha": 2,
+    "zeta": 1
+  }
+}
+`
+	if string(got) != want {
+		t.Errorf("expected sorted keys without nulls, got\n%s", got)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	old := `{"main": {"shape": "VM.Standard.A1.Flex", "shapeConfig": {"ocpus": 4}, "tags": ["a", "b"], "dead": true}, "gone": {}}`
+	new := `{"main": {"shape": "VM.Standard.A1.Flex", "shapeConfig": {"ocpus": 2, "memoryInGBs": 12}, "tags": ["a"], "a.b": 1}}`
+
+	lines, err := Diff([]byte(old), []byte(new))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		`- gone: {}`,
+		`+ main."a.b": 1`,
+		`- main.dead: true`,
+		`+ main.shapeConfig.memoryInGBs: 12`,
+		`~ main.shapeConfig.ocpus: 4 -> 2`,
+		`- main.tags[1]: "b"`,
+	}
+	if got := strings.Join(lines, "\n"); got != strings.Join(want, "\n") {
+		t.Errorf("unexpected diff:\n%s", got)
+	}
+
+	if lines, _ := Diff([]byte(new), []byte(new)); len(lines) != 0 {
+		t.Errorf("expected no differences, got %v", lines)
+	}
+	if _, err := Diff([]byte("{"), []byte(new)); err == nil || !strings.HasPrefix(err.Error(), "old:") {
+		t.Errorf("expected an error for the old document, got %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// levelNames are the words accessible output uses instead of icons and colors.
+var levelNames = map[string]string{
+	"INFO":    "Info",
+	"WARN":    "Warning",
+	"ERROR":   "Error",
+	"SUCCESS": "Success",
+}
+
+// plainReplacer spells out the symbols that carry meaning; it runs before the rest
+// are dropped.
+var plainReplacer = strings.NewReplacer(
+	"✓✓", "acknowledged",
+	"✓", "ok",
+	"✗", "failed",
+	"▶ ", "selected: ",
+	" · ", ", ",
+	" → ", " to ",
+	" -> ", " to ",
+	"↑", "up",
+	"↓", "down",
+	"…", "...",
+)
+
+// SetAccessible switches the console to screen-reader friendly output: one labeled line
+// per event, with no colors, emoji or box drawing. The log file is unchanged.
+func (l *Logger) SetAccessible(on bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.accessible = on
+}
+
+// Accessible reports whether accessible output is on.
+func (l *Logger) Accessible() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.accessible
+}
+
+// PlainText makes s readable by screen readers and braille displays: meaningful
+// symbols become words, and emoji, box drawing and other pictographs are removed.
+func PlainText(s string) string {
+	s = plainReplacer.Replace(s)
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case r == '\u200d' || r == '\ufe0e' || r == '\ufe0f' || r == '\u20e3':
+			return -1 // Emoji joiners and presentation selectors.
+		case r >= 0x2000 && (unicode.Is(unicode.So, r) || unicode.Is(unicode.Sk, r)):
+			return -1
+		}
+		return r
+	}, s)
+
+	// Removed symbols leave double spaces behind
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.Join(strings.Fields(line), " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// LevelName spells out a log level ("WARN" or "warn") for accessible output.
+func LevelName(level string) string {
+	if name, ok := levelNames[strings.ToUpper(level)]; ok {
+		return name
+	}
+	return level
+}
+
+// AccessibleLine formats a log event for accessible output,
+// e.g. "12:00:00 Warning, personal: OCI Error 500".
+func AccessibleLine(ts, level, account, msg string) string {
+	return fmt.Sprintf("%s %s, %s: %s", ts, LevelName(level), account, PlainText(msg))
+}
+
+// celebrateAccessible announces a success as labeled lines. The caller holds mu.
+func (l *Logger) celebrateAccessible(account string, details interface{}) {
+	fmt.Fprintf(l.out, "Success: instance provisioned for account %s.\n", account)
+	if v, ok := details.(verifiedDetails); ok {
+		fmt.Fprintf(l.out, "Instance ID: %s\n", v.GetInstanceID())
+		fmt.Fprintf(l.out, "Public IP: %s\n", v.GetPublicIP())
+		if ipv6 := detailsIPv6(details); ipv6 != "" {
+			fmt.Fprintf(l.out, "IPv6: %s\n", ipv6)
+		}
+		fmt.Fprintf(l.out, "Specs: %.0f OCPUs, %.0f GB RAM\n", v.GetOCPUs(), v.GetMemoryGB())
+		fmt.Fprintf(l.out, "State: %s\n", v.GetState())
+		fmt.Fprintf(l.out, "Region: %s\n", v.GetRegion())
+	}
+}
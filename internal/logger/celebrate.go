@@ -0,0 +1,86 @@
+package logger
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// Bell pattern timing: each "." rings the terminal bell, each "-" pauses.
+const (
+	bellGap   = 150 * time.Millisecond // Terminals merge bells sent back-to-back.
+	bellPause = 400 * time.Millisecond
+)
+
+// CelebrationOptions controls the audible part of Celebrate. The zero value rings once.
+type CelebrationOptions struct {
+	Silent    bool   // No bells or sounds at all (shared offices).
+	Beeps     int    // Number of bells when Pattern is empty. <= 0 means 1.
+	Pattern   string // Custom bell pattern, e.g. "...-...": "." = bell, "-" = pause.
+	SoundFile string // Audio file played with the platform's player, if set.
+}
+
+// SetCelebration configures how Celebrate announces a success.
+func (l *Logger) SetCelebration(opts CelebrationOptions) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.celebration = opts
+}
+
+// bellPattern returns the effective pattern for opts.
+func (o CelebrationOptions) bellPattern() string {
+	if o.Silent {
+		return ""
+	}
+	if o.Pattern != "" {
+		return o.Pattern
+	}
+	if o.Beeps <= 0 {
+		return "."
+	}
+	return strings.Repeat(".", o.Beeps)
+}
+
+// ring plays a bell pattern on the console. The first bell is written by the caller
+// (which holds mu); the rest run here so Celebrate never blocks the provisioner.
+func (l *Logger) ring(pattern string) {
+	for _, c := range pattern {
+		switch c {
+		case '.':
+			time.Sleep(bellGap)
+			l.mu.Lock()
+			fmt.Fprint(l.out, "\a")
+			l.mu.Unlock()
+		case '-':
+			time.Sleep(bellPause)
+		}
+	}
+}
+
+// playSound launches the platform's audio player for file in the background.
+func (l *Logger) playSound(file string) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("afplay", file)
+	case "windows":
+		cmd = exec.Command("powershell", "-NoProfile", "-Command",
+			fmt.Sprintf("(New-Object Media.SoundPlayer '%s').PlaySync()", strings.ReplaceAll(file, "'", "''")))
+	default:
+		for _, player := range [][]string{{"paplay"}, {"aplay", "-q"}, {"ffplay", "-nodisp", "-autoexit", "-loglevel", "quiet"}} {
+			if _, err := exec.LookPath(player[0]); err == nil {
+				cmd = exec.Command(player[0], append(player[1:], file)...)
+				break
+			}
+		}
+	}
+	if cmd == nil {
+		l.Warn("CELEBRATE", "No audio player found (tried paplay, aplay, ffplay)")
+		return
+	}
+	if err := cmd.Run(); err != nil {
+		l.Warn("CELEBRATE", fmt.Sprintf("Failed to play %s: %v", file, err))
+	}
+}
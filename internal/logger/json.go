@@ -0,0 +1,63 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// jsonEvent is one line of JSON console output.
+type jsonEvent struct {
+	Time    time.Time `json:"time"`
+	Level   string    `json:"level"`
+	Account string    `json:"account,omitempty"`
+	Attempt string    `json:"attempt,omitempty"` // Correlation ID of the launch attempt in progress.
+	Msg     string    `json:"msg"`
+
+	// Instance details, on the success event written by Celebrate
+	InstanceID string  `json:"instance_id,omitempty"`
+	PublicIP   string  `json:"public_ip,omitempty"`
+	IPv6       string  `json:"ipv6,omitempty"`
+	OCPUs      float32 `json:"ocpus,omitempty"`
+	MemoryGB   float32 `json:"memory_gb,omitempty"`
+	State      string  `json:"state,omitempty"`
+	Region     string  `json:"region,omitempty"`
+}
+
+// SetJSON switches the console to one JSON object per line (logging.console_format: json),
+// for container log drivers: no colors, sections, banners or bells. It takes precedence
+// over accessible output. The log file is unchanged.
+func (l *Logger) SetJSON(on bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.json = on
+}
+
+// JSON reports whether JSON console output is on.
+func (l *Logger) JSON() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.json
+}
+
+// jsonLine renders ev as a line of JSON.
+func jsonLine(ev jsonEvent) string {
+	ev.Level = strings.ToLower(ev.Level)
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return ""
+	}
+	return string(data) + "\n"
+}
+
+// celebrateJSON writes a success event with the instance details. The caller holds mu.
+func (l *Logger) celebrateJSON(account string, details interface{}) {
+	ev := jsonEvent{Time: time.Now(), Level: "SUCCESS", Account: account, Msg: "Instance provisioned"}
+	if v, ok := details.(verifiedDetails); ok {
+		ev.InstanceID, ev.PublicIP, ev.State, ev.Region = v.GetInstanceID(), v.GetPublicIP(), v.GetState(), v.GetRegion()
+		ev.OCPUs, ev.MemoryGB = v.GetOCPUs(), v.GetMemoryGB()
+		ev.IPv6 = detailsIPv6(details)
+	}
+	fmt.Fprint(l.out, jsonLine(ev))
+}
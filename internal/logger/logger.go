@@ -32,6 +32,12 @@ type Logger struct {
 	out   io.Writer // Console output (Standard Output)
 	file  io.Writer // File output (Append only)
 	hooks []LogHook
+
+	celebration CelebrationOptions
+	accessible  bool // See SetAccessible.
+	json        bool // See SetJSON.
+	repeats     repeats
+	attempts    map[string]string // Correlation ID of each account's current attempt; see SetAttempt.
 }
 
 // New initializes a new Logger instance.
@@ -59,6 +65,15 @@ func New(logDir string) (*Logger, error) {
 	}, nil
 }
 
+// NewConsole returns a Logger that only writes to stdout, for read-only filesystems.
+func NewConsole() *Logger {
+	return &Logger{
+		out:   os.Stdout,
+		file:  io.Discard,
+		hooks: make([]LogHook, 0),
+	}
+}
+
 // AddHook registers a function to be called on every log event
 func (l *Logger) AddHook(hook LogHook) {
 	l.mu.Lock()
@@ -66,12 +81,41 @@ func (l *Logger) AddHook(hook LogHook) {
 	l.hooks = append(l.hooks, hook)
 }
 
+// SetAttempt tags the account's log lines with the correlation ID of the launch attempt
+// in progress, until it is set again. An empty id removes the tag.
+func (l *Logger) SetAttempt(account, id string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if id == "" {
+		delete(l.attempts, account)
+		return
+	}
+	if l.attempts == nil {
+		l.attempts = make(map[string]string)
+	}
+	l.attempts[account] = id
+}
+
+// Attempt returns the account's current attempt ID, or "".
+func (l *Logger) Attempt(account string) string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.attempts[account]
+}
+
 // format constructs formatted strings for console (colored) and file (structured/timestamped) output.
 // returns (consoleMsg, fileMsg)
 func (l *Logger) format(level, color, icon, account, msg string) (string, string) {
 	// Trigger hooks
 	l.triggerHooks(level, account, msg)
 
+	// Lines of an attempt in progress carry its ID: [personal #3f9c0a1e7b2d]
+	attempt := l.Attempt(account)
+	label := account
+	if attempt != "" {
+		label += " #" + attempt
+	}
+
 	now := time.Now()
 	tsConsole := now.Format("15:04:05")
 	tsFile := now.Format("2006/01/02 15:04:05")
@@ -81,13 +125,20 @@ func (l *Logger) format(level, color, icon, account, msg string) (string, string
 	console := fmt.Sprintf("%s[%s]%s %s %s[%s]%s %s%s%s\n",
 		Gray, tsConsole, Reset,
 		icon,
-		Cyan, account, Reset,
+		Cyan, label, Reset,
 		color, msg, Reset,
 	)
 
 	// File Format: YYYY/MM/DD HH:mm:ss [Account] [LEVEL] Msg
 	// Example: 2023/01/01 12:00:00 [personal] [WARN] OCI Error 500
-	file := fmt.Sprintf("%s [%s] [%s] %s\n", tsFile, account, level, msg)
+	file := fmt.Sprintf("%s [%s] [%s] %s\n", tsFile, label, level, msg)
+
+	switch {
+	case l.JSON():
+		console = jsonLine(jsonEvent{Time: now, Level: level, Account: account, Attempt: attempt, Msg: msg})
+	case l.Accessible():
+		console = AccessibleLine(tsConsole, level, label, msg) + "\n"
+	}
 	return console, file
 }
 
@@ -134,10 +185,22 @@ func (l *Logger) SetConsoleOutput(w io.Writer) {
 	l.out = w
 }
 
+// log writes an event, collapsing lines the account keeps repeating (see repeats).
+func (l *Logger) log(level, color, icon, account, msg string) {
+	ok, due := l.repeats.allow(level, account, msg)
+	for _, r := range due {
+		c, f := l.format(r.level, "", "🔁", r.account, r.String())
+		l.write(c, f)
+	}
+	if ok {
+		c, f := l.format(level, color, icon, account, msg)
+		l.write(c, f)
+	}
+}
+
 // Info logs general informational messages.
 func (l *Logger) Info(account, msg string) {
-	c, f := l.format("INFO", "", "ℹ️", account, msg)
-	l.write(c, f)
+	l.log("INFO", "", "ℹ️", account, msg)
 }
 
 // Success logs positive outcomes (e.g., instance created).
@@ -148,14 +211,12 @@ func (l *Logger) Success(account, msg string) {
 
 // Warn logs warnings or recoverable errors (e.g., capacity limits).
 func (l *Logger) Warn(account, msg string) {
-	c, f := l.format("WARN", Yellow, "⚠️", account, msg)
-	l.write(c, f)
+	l.log("WARN", Yellow, "⚠️", account, msg)
 }
 
 // Error logs critical failures.
 func (l *Logger) Error(account, msg string) {
-	c, f := l.format("ERROR", Red, "❌", account, msg)
-	l.write(c, f)
+	l.log("ERROR", Red, "❌", account, msg)
 }
 
 // Section logs a visual divider to separate logical execution blocks (cycles).
@@ -163,7 +224,13 @@ func (l *Logger) Section(msg string) {
 	line := strings.Repeat("=", 60)
 	// Console: Blue Divider (Visual only)
 	l.mu.Lock()
-	fmt.Fprintf(l.out, "%s%s\n%s%s\n", Blue, line, msg, Reset)
+	if l.json {
+		fmt.Fprint(l.out, jsonLine(jsonEvent{Time: time.Now(), Level: "INFO", Msg: msg}))
+	} else if l.accessible {
+		fmt.Fprintf(l.out, "Section: %s\n", PlainText(msg))
+	} else {
+		fmt.Fprintf(l.out, "%s%s\n%s%s\n", Blue, line, msg, Reset)
+	}
 
 	// File: Timestamped Entry with generic tag
 	ts := time.Now().Format("2006/01/02 15:04:05")
@@ -176,20 +243,75 @@ func (l *Logger) Plain(msg string) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 	// Console: Plain text
-	fmt.Fprintln(l.out, msg)
+	if l.json {
+		fmt.Fprint(l.out, jsonLine(jsonEvent{Time: time.Now(), Level: "INFO", Msg: msg}))
+	} else if l.accessible {
+		fmt.Fprintln(l.out, PlainText(msg))
+	} else {
+		fmt.Fprintln(l.out, msg)
+	}
 
 	// File: Timestamped Info
 	ts := time.Now().Format("2006/01/02 15:04:05")
 	fmt.Fprintf(l.file, "%s [INFO] %s\n", ts, msg)
 }
 
+// verifiedDetails is what Celebrate shows of a verified instance.
+type verifiedDetails interface {
+	GetInstanceID() string
+	GetPublicIP() string
+	GetOCPUs() float32
+	GetMemoryGB() float32
+	GetState() string
+	GetRegion() string
+}
+
+// ipv6Details is implemented by details that carry the instance's IPv6 address.
+type ipv6Details interface {
+	GetIPv6() string
+}
+
+// detailsIPv6 returns the IPv6 address of details, if it has one.
+func detailsIPv6(details interface{}) string {
+	if d, ok := details.(ipv6Details); ok {
+		return d.GetIPv6()
+	}
+	return ""
+}
+
 // Celebrate logs a prominent success banner with instance details and a terminal beep.
+// The beep is configurable via SetCelebration.
 func (l *Logger) Celebrate(account string, details interface{}) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
-	// Terminal beep
-	fmt.Fprint(l.out, "\a")
+	// Terminal beep (first bell now, the rest of the pattern in the background); a bell
+	// would corrupt a JSON stream
+	if pattern := l.celebration.bellPattern(); pattern != "" && !l.json {
+		if pattern[0] == '.' {
+			fmt.Fprint(l.out, "\a")
+			pattern = pattern[1:]
+		}
+		if pattern != "" {
+			go l.ring(pattern)
+		}
+	}
+	if file := l.celebration.SoundFile; file != "" && !l.celebration.Silent {
+		go l.playSound(file)
+	}
+
+	// File logging
+	ts := time.Now().Format("2006/01/02 15:04:05")
+	fmt.Fprintf(l.file, "%s [SUCCESS] === INSTANCE PROVISIONED FOR ACCOUNT [%s] ===\n", ts, account)
+
+	if l.json {
+		l.celebrateJSON(account, details)
+		return
+	}
+	if l.accessible {
+		l.celebrateAccessible(account, details)
+		return
+	}
 
 	// ASCII Art Banner
 	banner := `
@@ -203,18 +325,12 @@ func (l *Logger) Celebrate(account string, details interface{}) {
 
 	fmt.Fprint(l.out, banner)
 
-	// Extract details if available
-	type verifiedDetails interface {
-		GetInstanceID() string
-		GetPublicIP() string
-		GetOCPUs() float32
-		GetMemoryGB() float32
-		GetState() string
-		GetRegion() string
-	}
-
 	// Try to extract structured details
 	if v, ok := details.(verifiedDetails); ok {
+		publicIP := v.GetPublicIP()
+		if ipv6 := detailsIPv6(details); ipv6 != "" {
+			publicIP += " / " + ipv6
+		}
 		box := fmt.Sprintf(`
 %s┌─────────────────────────────────────────────────────────────────────┐%s
 %s│ Account:     %-55s │%s
@@ -228,7 +344,7 @@ func (l *Logger) Celebrate(account string, details interface{}) {
 			Cyan, Reset,
 			Cyan, account, Reset,
 			Cyan, v.GetInstanceID(), Reset,
-			Cyan, v.GetPublicIP(), Reset,
+			Cyan, publicIP, Reset,
 			Cyan, fmt.Sprintf("%.0f OCPUs / %.0f GB RAM", v.GetOCPUs(), v.GetMemoryGB()), Reset,
 			Cyan, v.GetState()+" ✓", Reset,
 			Cyan, v.GetRegion(), Reset,
@@ -236,8 +352,4 @@ func (l *Logger) Celebrate(account string, details interface{}) {
 		)
 		fmt.Fprint(l.out, box)
 	}
-
-	// File logging
-	ts := time.Now().Format("2006/01/02 15:04:05")
-	fmt.Fprintf(l.file, "%s [SUCCESS] === INSTANCE PROVISIONED FOR ACCOUNT [%s] ===\n", ts, account)
 }
@@ -1,10 +1,13 @@
 package logger
 
 import (
+	"bytes"
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestNew_CreatesDirectory(t *testing.T) {
@@ -159,3 +162,215 @@ func TestLogger_Celebrate_WithDetails(t *testing.T) {
 		t.Error("Log file missing celebration text")
 	}
 }
+
+func TestLogger_Celebrate_Silent(t *testing.T) {
+	l, err := New(filepath.Join(t.TempDir(), "logs"))
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	var buf bytes.Buffer
+	l.SetConsoleOutput(&buf)
+
+	l.SetCelebration(CelebrationOptions{Silent: true, Beeps: 3})
+	l.Celebrate("test-account", nil)
+	if strings.Contains(buf.String(), "\a") {
+		t.Error("Silent celebration rang the bell")
+	}
+
+	buf.Reset()
+	l.SetCelebration(CelebrationOptions{})
+	l.Celebrate("test-account", nil)
+	if !strings.HasPrefix(buf.String(), "\a") {
+		t.Error("Default celebration did not ring the bell")
+	}
+}
+
+func TestCelebrationOptions_BellPattern(t *testing.T) {
+	cases := []struct {
+		opts CelebrationOptions
+		want string
+	}{
+		{CelebrationOptions{}, "."},
+		{CelebrationOptions{Beeps: 3}, "..."},
+		{CelebrationOptions{Beeps: 3, Pattern: ".-."}, ".-."},
+		{CelebrationOptions{Silent: true, Pattern: "..."}, ""},
+	}
+	for _, c := range cases {
+		if got := c.opts.bellPattern(); got != c.want {
+			t.Errorf("%+v: got %q, want %q", c.opts, got, c.want)
+		}
+	}
+}
+
+func TestNewConsole_NoFile(t *testing.T) {
+	dir := t.TempDir()
+	wd, _ := os.Getwd()
+	os.Chdir(dir)
+	defer os.Chdir(wd)
+
+	l := NewConsole()
+	var out bytes.Buffer
+	l.out = &out
+
+	l.Info("TEST", "console only")
+	l.Section("Section")
+	l.Plain("plain")
+
+	if !strings.Contains(out.String(), "console only") {
+		t.Errorf("expected console output, got %q", out.String())
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("expected no files written, got %v", entries)
+	}
+}
+
+func TestLogger_Accessible(t *testing.T) {
+	l := NewConsole()
+	var out bytes.Buffer
+	l.out = &out
+	l.SetAccessible(true)
+	l.SetCelebration(CelebrationOptions{Silent: true})
+
+	l.Warn("main", "⏳ Waited 1m0s for the tenancy's request budget")
+	l.Section("🔄 Cycle #3")
+	l.Plain("📂 Config: config.yaml")
+	l.Celebrate("main", &mockCelebrateDetails{})
+
+	got := out.String()
+	for _, want := range []string{
+		" Warning, main: Waited 1m0s for the tenancy's request budget\n",
+		"Section: Cycle #3\n",
+		"\nConfig: config.yaml\n",
+		"Success: instance provisioned for account main.\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in %q", want, got)
+		}
+	}
+	if strings.ContainsAny(got, "\033═┌│⏳🔄📂") {
+		t.Errorf("expected no colors, box drawing or emoji, got %q", got)
+	}
+}
+
+func TestLogger_JSON(t *testing.T) {
+	l := NewConsole()
+	var out bytes.Buffer
+	l.out = &out
+	l.SetJSON(true)
+	l.SetAccessible(true)
+
+	l.Warn("main", "Capacity/Limit error. Will retry.")
+	l.Section("Cycle 3 Started")
+	l.Plain("📂 Config: config.yaml")
+	l.Celebrate("main", &mockCelebrateDetails{})
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected one line per event, got %q", out.String())
+	}
+	var events []jsonEvent
+	for _, line := range lines {
+		var ev jsonEvent
+		if err := json.Unmarshal([]byte(line), &ev); err != nil {
+			t.Fatalf("not JSON: %q (%v)", line, err)
+		}
+		events = append(events, ev)
+	}
+	if ev := events[0]; ev.Level != "warn" || ev.Account != "main" || ev.Msg != "Capacity/Limit error. Will retry." || ev.Time.IsZero() {
+		t.Errorf("unexpected warning %+v", ev)
+	}
+	if events[1].Msg != "Cycle 3 Started" || events[2].Level != "info" {
+		t.Errorf("expected sections and plain lines as info events, got %+v %+v", events[1], events[2])
+	}
+	if ev := events[3]; ev.Level != "success" || ev.InstanceID != "inst-123" || ev.PublicIP != "10.0.0.1" || ev.OCPUs != 4 {
+		t.Errorf("unexpected success event %+v", ev)
+	}
+	if strings.ContainsAny(out.String(), "\033\a═") {
+		t.Errorf("expected no colors, bells or banners, got %q", out.String())
+	}
+}
+
+func TestPlainText(t *testing.T) {
+	cases := map[string]string{
+		"👥 Already being hunted":       "Already being hunted",
+		"⚠️ Hits: 3":                   "Hits: 3",
+		"ntfy ✓✓ · webhook ✗":          "ntfy acknowledged, webhook failed",
+		"▶ us-ashburn-1 (current)":     "selected: us-ashburn-1 (current)",
+		"IP 1.2.3.4 -> 5.6.7.8":        "IP 1.2.3.4 to 5.6.7.8",
+		"Temperature 20°C, © Oracle":   "Temperature 20°C, © Oracle",
+		"──────\nline   with  gaps 🚀🎉": "\nline with gaps",
+	}
+	for in, want := range cases {
+		if got := PlainText(in); got != want {
+			t.Errorf("PlainText(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestLogger_CollapsesRepeats(t *testing.T) {
+	l := NewConsole()
+	var out bytes.Buffer
+	l.out = &out
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	l.repeats.now = func() time.Time { return now }
+
+	// One attempt every 30s for 10 minutes
+	for i := 0; i < 20; i++ {
+		l.Warn("main", "Capacity/Limit error. Will retry.")
+		l.Warn("other", "Capacity/Limit error. Will retry.")
+		now = now.Add(30 * time.Second)
+	}
+	if got := strings.Count(out.String(), "[main]"); got != repeatBurst+1 {
+		t.Errorf("expected the burst plus one refilled line for main, got %d:\n%s", got, out.String())
+	}
+	if got := strings.Count(out.String(), "[other]"); got != repeatBurst+1 {
+		t.Errorf("expected accounts to have their own budget, got %d lines for other", got)
+	}
+
+	// The count is reported once the window has passed
+	out.Reset()
+	now = now.Add(10 * time.Minute)
+	l.Info("main", "Cycle finished")
+	if !strings.Contains(out.String(), "last message repeated 16 times (window 15m): Capacity/Limit error. Will retry.") {
+		t.Errorf("expected a summary of the suppressed lines, got:\n%s", out.String())
+	}
+
+	// DEBUG gets the full stream
+	out.Reset()
+	l.SetLevel("debug")
+	for i := 0; i < 10; i++ {
+		l.Warn("main", "Capacity/Limit error. Will retry.")
+	}
+	if got := strings.Count(out.String(), "[main]"); got != 10 {
+		t.Errorf("expected every line at DEBUG, got %d", got)
+	}
+}
+
+func TestLogger_Attempt(t *testing.T) {
+	logDir := t.TempDir()
+	l, err := New(logDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var console bytes.Buffer
+	l.SetConsoleOutput(&console)
+	l.SetJSON(true)
+
+	l.SetAttempt("work", "3f9c0a1e7b2d")
+	l.Info("work", "Launching")
+	l.Info("home", "Waiting")
+	l.SetAttempt("work", "")
+	l.Info("work", "Done")
+
+	content, _ := os.ReadFile(filepath.Join(logDir, "provisioner.log"))
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	if len(lines) != 3 || !strings.Contains(lines[0], "[work #3f9c0a1e7b2d] [INFO] Launching") ||
+		!strings.Contains(lines[1], "[home] [INFO]") || !strings.Contains(lines[2], "[work] [INFO] Done") {
+		t.Errorf("expected only the attempt's line tagged, got %q", lines)
+	}
+
+	var ev jsonEvent
+	if err := json.Unmarshal([]byte(strings.Split(console.String(), "\n")[0]), &ev); err != nil || ev.Attempt != "3f9c0a1e7b2d" || ev.Account != "work" {
+		t.Errorf("expected the attempt in the JSON line, got %+v, %v", ev, err)
+	}
+}
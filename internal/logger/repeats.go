@@ -0,0 +1,118 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// An account looping on the same capacity error would otherwise write the same lines
+// thousands of times a day. Each distinct line of an account gets a token bucket of
+// repeatBurst lines per repeatWindow; lines over budget are counted instead of written,
+// and reported as one summary line once the window has passed.
+const (
+	repeatWindow = 15 * time.Minute
+	repeatBurst  = 3
+)
+
+// repeatKey identifies a distinct line of an account.
+type repeatKey struct {
+	level, account, msg string
+}
+
+// repeated is a line that was not written n times.
+type repeated struct {
+	repeatKey
+	n int
+}
+
+// String is the line written in place of the suppressed copies.
+func (r repeated) String() string {
+	return fmt.Sprintf("last message repeated %d times (window %dm): %s", r.n, int(repeatWindow.Minutes()), r.msg)
+}
+
+// repeatBucket is the budget of one distinct line.
+type repeatBucket struct {
+	tokens     float64
+	refilled   time.Time
+	seen       time.Time // Last occurrence.
+	suppressed int       // Occurrences not written since the last summary.
+	since      time.Time // First of those.
+}
+
+// repeats holds the buckets. The zero value is ready to use.
+type repeats struct {
+	mu      sync.Mutex
+	debug   bool // Collapsing off: logging.level DEBUG gets the full stream.
+	now     func() time.Time
+	buckets map[repeatKey]*repeatBucket
+	swept   time.Time
+}
+
+// SetLevel applies logging.level. "DEBUG" writes every line; anything else collapses
+// identical lines an account repeats more than a few times per 15 minutes.
+func (l *Logger) SetLevel(level string) {
+	l.repeats.mu.Lock()
+	defer l.repeats.mu.Unlock()
+	l.repeats.debug = strings.EqualFold(level, "DEBUG")
+}
+
+// allow reports whether a line may be written. It also returns the summaries that are
+// due, for this or other lines, to be written first.
+func (r *repeats) allow(level, account, msg string) (bool, []repeated) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.debug {
+		return true, nil
+	}
+	if r.now == nil {
+		r.now = time.Now
+	}
+	if r.buckets == nil {
+		r.buckets = make(map[repeatKey]*repeatBucket)
+	}
+	now := r.now()
+
+	var due []repeated
+	summarize := func(k repeatKey, b *repeatBucket) {
+		due = append(due, repeated{k, b.suppressed})
+		b.suppressed = 0
+	}
+
+	// Report lines that stopped repeating, and forget idle ones (once a minute)
+	if now.Sub(r.swept) >= time.Minute {
+		r.swept = now
+		for k, b := range r.buckets {
+			if b.suppressed > 0 && now.Sub(b.since) >= repeatWindow {
+				summarize(k, b)
+			}
+			if b.suppressed == 0 && now.Sub(b.seen) >= repeatWindow {
+				delete(r.buckets, k)
+			}
+		}
+	}
+
+	key := repeatKey{level, account, msg}
+	b := r.buckets[key]
+	if b == nil {
+		b = &repeatBucket{tokens: repeatBurst, refilled: now}
+		r.buckets[key] = b
+	}
+	b.seen = now
+	b.tokens = min(repeatBurst, b.tokens+now.Sub(b.refilled).Seconds()*repeatBurst/repeatWindow.Seconds())
+	b.refilled = now
+
+	if b.suppressed > 0 && now.Sub(b.since) >= repeatWindow {
+		summarize(key, b)
+	}
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, due
+	}
+	if b.suppressed == 0 {
+		b.since = now
+	}
+	b.suppressed++
+	return false, due
+}
@@ -0,0 +1,88 @@
+// Package logstore keeps the latest log lines in a fixed-size ring that any number of
+// readers (the TUI, the management API) follow by sequence number. A reader that falls
+// behind misses the oldest lines instead of blocking the logger or growing memory.
+package logstore
+
+import (
+	"sync"
+	"time"
+)
+
+// Size is how many recent log lines a store keeps by default.
+const Size = 1000
+
+// Entry is a log line, numbered so readers can follow the log.
+type Entry struct {
+	Seq     int64     `json:"seq"`
+	Time    time.Time `json:"time"`
+	Level   string    `json:"level"`
+	Account string    `json:"account"`
+	Message string    `json:"message"`
+}
+
+// Store keeps the latest log lines. Register Add as a logger hook.
+type Store struct {
+	mu      sync.Mutex
+	ring    []Entry
+	oldest  int   // Index of the oldest line once the ring is full.
+	seq     int64 // Seq of the newest line.
+	changed chan struct{}
+}
+
+// New returns an empty store keeping the latest size lines.
+func New(size int) *Store {
+	return &Store{ring: make([]Entry, 0, max(size, 1)), changed: make(chan struct{})}
+}
+
+// Add records a log line; it has the signature of logger.LogHook.
+func (s *Store) Add(level, account, msg string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seq++
+	e := Entry{Seq: s.seq, Time: time.Now(), Level: level, Account: account, Message: msg}
+	if len(s.ring) < cap(s.ring) {
+		s.ring = append(s.ring, e)
+	} else {
+		s.ring[s.oldest] = e
+		s.oldest = (s.oldest + 1) % len(s.ring)
+	}
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// Since returns the lines after seq, oldest first, at most the n newest.
+func (s *Store) Since(seq int64, n int) []Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	first := s.seq - int64(len(s.ring)) + 1 // Seq of the oldest line kept
+	skip := max(seq-first+1, 0)
+	count := max(int64(len(s.ring))-skip, 0)
+	if count > int64(n) {
+		skip += count - int64(n)
+		count = int64(n)
+	}
+	out := make([]Entry, 0, count)
+	for i := skip; i < skip+count; i++ {
+		out = append(out, s.ring[(int64(s.oldest)+i)%int64(len(s.ring))])
+	}
+	return out
+}
+
+// Last returns the seq of the newest line, 0 before the first.
+func (s *Store) Last() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.seq
+}
+
+// Changed returns a channel that is closed once there are lines after seq.
+func (s *Store) Changed(seq int64) <-chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.seq > seq {
+		done := make(chan struct{})
+		close(done)
+		return done
+	}
+	return s.changed
+}
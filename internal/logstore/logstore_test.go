@@ -0,0 +1,69 @@
+package logstore
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestStore_Since(t *testing.T) {
+	s := New(3)
+	if got := s.Since(0, 10); len(got) != 0 {
+		t.Fatalf("expected no lines, got %+v", got)
+	}
+	for i := 1; i <= 5; i++ {
+		s.Add("info", "work", fmt.Sprintf("line %d", i))
+	}
+
+	for _, tc := range []struct {
+		seq  int64
+		n    int
+		want string
+	}{
+		{0, 10, "[3 4 5]"}, // the oldest lines are overwritten
+		{3, 10, "[4 5]"},
+		{0, 2, "[4 5]"}, // the newest n
+		{5, 10, "[]"},
+		{9, 10, "[]"},
+	} {
+		var seqs []int64
+		for _, e := range s.Since(tc.seq, tc.n) {
+			seqs = append(seqs, e.Seq)
+		}
+		if got := fmt.Sprint(seqs); got != tc.want {
+			t.Errorf("Since(%d, %d): expected %s, got %s", tc.seq, tc.n, tc.want, got)
+		}
+	}
+	if e := s.Since(4, 1); e[0].Message != "line 5" || e[0].Level != "info" || e[0].Account != "work" {
+		t.Errorf("unexpected entry %+v", e[0])
+	}
+	if s.Last() != 5 {
+		t.Errorf("expected last seq 5, got %d", s.Last())
+	}
+}
+
+func TestStore_Changed(t *testing.T) {
+	s := New(Size)
+	s.Add("info", "", "first")
+	select {
+	case <-s.Changed(0):
+	default:
+		t.Fatal("expected a closed channel with lines after the cursor")
+	}
+
+	// Every reader waiting at the newest line is woken by the next one
+	waiting := []<-chan struct{}{s.Changed(1), s.Changed(1)}
+	select {
+	case <-waiting[0]:
+		t.Fatal("expected to wait for a new line")
+	default:
+	}
+	s.Add("warn", "", "second")
+	for i, ch := range waiting {
+		select {
+		case <-ch:
+		case <-time.After(time.Second):
+			t.Errorf("reader %d wasn't woken", i)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+// Package mqtt publishes provisioning events to an MQTT broker, so Home Assistant and
+// other home-automation systems can react to them.
+package mqtt
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/yourusername/oci-arm-provisioner/internal/config"
+	"github.com/yourusername/oci-arm-provisioner/internal/notifier"
+)
+
+// Event types, also the last topic level.
+const (
+	Attempt  = "attempt"  // A launch request was sent.
+	Capacity = "capacity" // It was answered "Out of host capacity".
+	Success  = "success"  // An instance was launched.
+	Digest   = "digest"   // Periodic summary (notifications.digest_interval).
+)
+
+// Event is the JSON payload of every message.
+type Event struct {
+	Type       string    `json:"event"`
+	Account    string    `json:"account,omitempty"`
+	Time       time.Time `json:"time"`
+	InstanceID string    `json:"instance_id,omitempty"`
+	PublicIP   string    `json:"public_ip,omitempty"`
+	Region     string    `json:"region,omitempty"`
+	AttemptID  string    `json:"attempt_id,omitempty"` // Correlation ID of the launch attempt, also in the log.
+
+	// Success only: how the instance came up.
+	RunningSeconds  float64 `json:"running_seconds,omitempty"`
+	PublicIPSeconds float64 `json:"public_ip_seconds,omitempty"`
+	SpecsMismatch   bool    `json:"specs_mismatch,omitempty"`
+
+	Digest *DigestData `json:"digest,omitempty"`
+}
+
+// DigestData is the summary carried by a digest event.
+type DigestData struct {
+	UptimeSeconds  int64 `json:"uptime_seconds"`
+	Cycles         int   `json:"cycles"`
+	CapacityErrors int   `json:"capacity_errors"`
+	OtherErrors    int   `json:"other_errors"`
+	Provisioned    int   `json:"provisioned"`
+}
+
+// DigestEvent builds a digest event from the run statistics.
+func DigestEvent(stats notifier.Stats, now time.Time) Event {
+	return Event{Type: Digest, Time: now, Digest: &DigestData{
+		UptimeSeconds:  int64(now.Sub(stats.StartTime).Seconds()),
+		Cycles:         stats.TotalCycles,
+		CapacityErrors: stats.CapacityErrors,
+		OtherErrors:    stats.OtherErrors,
+		Provisioned:    stats.SuccessCount,
+	}}
+}
+
+// connectWarning is how long New waits before reporting a broker it cannot reach.
+const connectWarning = 30 * time.Second
+
+// Publisher sends events to the broker in the background. Messages published while the
+// broker is unreachable are queued and sent once it connects.
+type Publisher struct {
+	client  paho.Client
+	prefix  string
+	onError func(err error) // Connection and publish errors; may be nil.
+}
+
+// New connects to the configured broker, or returns nil if none is configured. It does
+// not wait for the connection; onError receives connection and publish errors.
+func New(cfg config.MQTTConfig, onError func(err error)) (*Publisher, error) {
+	if !cfg.Enabled() {
+		return nil, nil
+	}
+	p := &Publisher{prefix: cfg.TopicPrefix, onError: onError}
+
+	clientID := cfg.ClientID
+	if clientID == "" {
+		host, _ := os.Hostname()
+		clientID = "oci-arm-provisioner-" + host
+	}
+	opts := paho.NewClientOptions().
+		AddBroker(cfg.Broker).
+		SetClientID(clientID).
+		SetUsername(cfg.Username).
+		SetPassword(cfg.Password).
+		SetConnectRetry(true).
+		SetAutoReconnect(true).
+		SetOrderMatters(false).
+		SetWill(p.statusTopic(), "offline", 1, true).
+		SetOnConnectHandler(func(c paho.Client) {
+			c.Publish(p.statusTopic(), 1, true, "online")
+		}).
+		SetConnectionLostHandler(func(_ paho.Client, err error) {
+			p.fail(fmt.Errorf("connection lost: %w", err))
+		})
+
+	if cfg.CAFile != "" || cfg.Insecure {
+		tlsCfg := &tls.Config{InsecureSkipVerify: cfg.Insecure}
+		if cfg.CAFile != "" {
+			pem, err := os.ReadFile(cfg.CAFile)
+			if err != nil {
+				return nil, fmt.Errorf("mqtt.ca_file: %w", err)
+			}
+			tlsCfg.RootCAs = x509.NewCertPool()
+			if !tlsCfg.RootCAs.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("mqtt.ca_file: no certificates in %s", cfg.CAFile)
+			}
+		}
+		opts.SetTLSConfig(tlsCfg)
+	}
+
+	p.client = paho.NewClient(opts)
+	connect := p.client.Connect()
+	go func() {
+		if !connect.WaitTimeout(connectWarning) {
+			p.fail(fmt.Errorf("broker %s not reachable yet; events are queued until it is", cfg.Broker))
+		}
+	}()
+	return p, nil
+}
+
+// Publish sends ev without waiting for the broker. Success events are retained, so a
+// subscriber that starts later still sees the last one. A nil *Publisher does nothing.
+func (p *Publisher) Publish(ev Event) {
+	if p == nil {
+		return
+	}
+	topic, payload, err := p.message(ev)
+	if err != nil {
+		p.fail(err)
+		return
+	}
+	token := p.client.Publish(topic, 1, ev.Type == Success, payload)
+	go func() {
+		if token.WaitTimeout(time.Minute) && token.Error() != nil {
+			p.fail(fmt.Errorf("publish %s: %w", topic, token.Error()))
+		}
+	}()
+}
+
+// Close marks the provisioner offline and disconnects. A nil *Publisher does nothing.
+func (p *Publisher) Close() {
+	if p == nil {
+		return
+	}
+	if p.client.IsConnected() {
+		p.client.Publish(p.statusTopic(), 1, true, "offline").WaitTimeout(2 * time.Second)
+	}
+	p.client.Disconnect(250)
+}
+
+// message returns the topic and payload of ev: <prefix>/<account>/<type>, or
+// <prefix>/<type> for events without an account.
+func (p *Publisher) message(ev Event) (string, []byte, error) {
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		return "", nil, err
+	}
+	if ev.Account != "" {
+		return p.topic(topicLevel(ev.Account), ev.Type), payload, nil
+	}
+	return p.topic(ev.Type), payload, nil
+}
+
+// statusTopic carries "online" or "offline" (retained), for availability tracking.
+func (p *Publisher) statusTopic() string {
+	return p.topic("status")
+}
+
+// topic joins levels under the prefix, if any.
+func (p *Publisher) topic(levels ...string) string {
+	if p.prefix != "" {
+		levels = append([]string{p.prefix}, levels...)
+	}
+	return strings.Join(levels, "/")
+}
+
+func (p *Publisher) fail(err error) {
+	if p.onError != nil {
+		p.onError(err)
+	}
+}
+
+// topicLevel makes an account name safe to use as one topic level.
+func topicLevel(s string) string {
+	return strings.NewReplacer("/", "_", "+", "_", "#", "_").Replace(s)
+}
@@ -0,0 +1,120 @@
+package mqtt
+
+import (
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eclipse/paho.mqtt.golang/packets"
+	"github.com/yourusername/oci-arm-provisioner/internal/config"
+	"github.com/yourusername/oci-arm-provisioner/internal/notifier"
+)
+
+// published is a PUBLISH received by fakeBroker.
+type published struct {
+	topic   string
+	payload []byte
+	retain  bool
+}
+
+// fakeBroker accepts one client and forwards what it publishes.
+func fakeBroker(t *testing.T) (string, <-chan published) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	out := make(chan published, 10)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			cp, err := packets.ReadPacket(conn)
+			if err != nil {
+				return
+			}
+			switch p := cp.(type) {
+			case *packets.ConnectPacket:
+				packets.NewControlPacket(packets.Connack).Write(conn)
+			case *packets.PublishPacket:
+				if p.Qos == 1 {
+					ack := packets.NewControlPacket(packets.Puback).(*packets.PubackPacket)
+					ack.MessageID = p.MessageID
+					ack.Write(conn)
+				}
+				out <- published{p.TopicName, p.Payload, p.Retain}
+			case *packets.PingreqPacket:
+				packets.NewControlPacket(packets.Pingresp).Write(conn)
+			}
+		}
+	}()
+	return "tcp://" + ln.Addr().String(), out
+}
+
+func receive(t *testing.T, ch <-chan published) published {
+	select {
+	case p := <-ch:
+		return p
+	case <-time.After(5 * time.Second):
+		t.Fatal("nothing published")
+		return published{}
+	}
+}
+
+func TestPublisher_Events(t *testing.T) {
+	broker, got := fakeBroker(t)
+	p, err := New(config.MQTTConfig{Broker: broker, TopicPrefix: "oci"}, func(err error) { t.Log(err) })
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer p.Close()
+
+	if status := receive(t, got); status.topic != "oci/status" || string(status.payload) != "online" || !status.retain {
+		t.Errorf("expected retained online status, got %+v", status)
+	}
+
+	p.Publish(Event{Type: Capacity, Account: "home/lab", Region: "eu-frankfurt-1"})
+	msg := receive(t, got)
+	var ev Event
+	json.Unmarshal(msg.payload, &ev)
+	if msg.topic != "oci/home_lab/capacity" || msg.retain || ev.Type != Capacity || ev.Region != "eu-frankfurt-1" {
+		t.Errorf("unexpected capacity message %s %s (retain %v)", msg.topic, msg.payload, msg.retain)
+	}
+
+	p.Publish(Event{Type: Success, Account: "personal", InstanceID: "ocid1.instance.test", PublicIP: "203.0.113.7"})
+	if msg := receive(t, got); msg.topic != "oci/personal/success" || !msg.retain || !strings.Contains(string(msg.payload), `"public_ip":"203.0.113.7"`) {
+		t.Errorf("expected a retained success message, got %s %s", msg.topic, msg.payload)
+	}
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	p.Publish(DigestEvent(notifier.Stats{StartTime: start, TotalCycles: 96, CapacityErrors: 90}, start.Add(24*time.Hour)))
+	msg = receive(t, got)
+	ev = Event{}
+	json.Unmarshal(msg.payload, &ev)
+	if msg.topic != "oci/digest" || ev.Digest == nil || ev.Digest.UptimeSeconds != 86400 || ev.Digest.CapacityErrors != 90 {
+		t.Errorf("unexpected digest message %s %s", msg.topic, msg.payload)
+	}
+}
+
+func TestNew_Config(t *testing.T) {
+	if p, err := New(config.MQTTConfig{}, nil); p != nil || err != nil {
+		t.Errorf("expected no publisher without a broker, got %v, %v", p, err)
+	}
+	// A nil publisher is safe to use
+	var p *Publisher
+	p.Publish(Event{Type: Attempt})
+	p.Close()
+
+	ca := filepath.Join(t.TempDir(), "ca.pem")
+	os.WriteFile(ca, []byte("not a certificate"), 0600)
+	if _, err := New(config.MQTTConfig{Broker: "ssl://localhost:8883", CAFile: ca}, nil); err == nil || !strings.Contains(err.Error(), "no certificates") {
+		t.Errorf("expected a CA file error, got %v", err)
+	}
+}
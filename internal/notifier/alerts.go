@@ -0,0 +1,182 @@
+package notifier
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Class groups error alerts that share a template and a throttle window.
+type Class string
+
+const (
+	ClassAuth         Class = "auth"         // OCI rejected the credentials, or the key file is unusable.
+	ClassCapacity     Class = "capacity"     // Capacity hunting milestones.
+	ClassVerification Class = "verification" // An instance launched but could not be verified.
+	ClassDuplicate    Class = "duplicate"    // Another copy of the tool hunts the same account.
+	ClassPermissions  Class = "permissions"  // IAM policies don't let the user launch in the compartment.
+	ClassErrorRate    Class = "error_rate"   // Most API calls fail for reasons other than capacity; attempts are slowed down.
+)
+
+// alertTemplate describes how an alert class is rendered and throttled.
+type alertTemplate struct {
+	Title    string
+	Resolved string // Title of the recovery message; empty if the class has none.
+	Hint     string // What the user should do about it.
+	Color    int
+	Priority int
+	Tags     string
+	Throttle time.Duration // Default window; override with notifications.alert_throttle.
+}
+
+var alertTemplates = map[Class]alertTemplate{
+	ClassAuth: {
+		Title:    "🔑 Authentication Failed",
+		Resolved: "🔓 Authentication Restored",
+		Hint:     "Check user_ocid, fingerprint and key_file, and that the API key is still listed in the OCI Console.",
+		Color:    ColorError,
+		Priority: 5,
+		Tags:     "key,rotating_light",
+		Throttle: 6 * time.Hour,
+	},
+	ClassCapacity: {
+		Title:    "📈 Capacity Milestone",
+		Hint:     "Still hunting - nothing to do.",
+		Color:    ColorInfo,
+		Priority: 2,
+		Tags:     "chart_with_upwards_trend",
+		Throttle: 12 * time.Hour,
+	},
+	ClassVerification: {
+		Title:    "🔍 Verification Failed",
+		Hint:     "The instance launched but did not pass verification; check it in the OCI Console.",
+		Color:    ColorError,
+		Priority: 4,
+		Tags:     "mag,warning",
+		Throttle: time.Hour,
+	},
+	ClassDuplicate: {
+		Title:    "👥 Duplicate Provisioner",
+		Resolved: "👤 Duplicate Provisioner Gone",
+		Hint:     "Another copy on this machine uses the same OCI user, so this one skips the account until it stops. Stop one of them.",
+		Color:    ColorError,
+		Priority: 4,
+		Tags:     "busts_in_silhouette,warning",
+		Throttle: 6 * time.Hour,
+	},
+	ClassPermissions: {
+		Title:    "🛂 Missing IAM Policies",
+		Resolved: "🛂 IAM Policies OK",
+		Hint:     "Ask a tenancy administrator to add the statements under Identity & Security > Policies, with your group's name. A wrong OCID gives the same error.",
+		Color:    ColorError,
+		Priority: 5,
+		Tags:     "passport_control,warning",
+		Throttle: 6 * time.Hour,
+	},
+	ClassErrorRate: {
+		Title:    "🐢 Slowing Down",
+		Resolved: "🐇 Back to Full Speed",
+		Hint:     "Requests fail for the same non-capacity reason, usually the network, DNS, an OCI outage or revoked credentials. Attempts run less often until requests succeed again.",
+		Color:    ColorError,
+		Priority: 4,
+		Tags:     "turtle,warning",
+		Throttle: 6 * time.Hour,
+	},
+}
+
+// Alert is an error-class notification for one account.
+type Alert struct {
+	Class   Class
+	Account string
+	Detail  string  // The error, or what happened.
+	Fields  []Field // Extra fields shown after the detail.
+}
+
+// throttle remembers recent alerts per class and account.
+type throttle struct {
+	mu         sync.Mutex
+	last       map[string]time.Time // Last alert sent.
+	suppressed map[string]int       // Alerts dropped since then.
+}
+
+// throttleWindow returns the configured window for class, or the template default.
+func (n *Notifier) throttleWindow(class Class) time.Duration {
+	if v, ok := n.Config.AlertThrottle[string(class)]; ok {
+		if d, err := time.ParseDuration(v); err == nil {
+			return d
+		}
+	}
+	return alertTemplates[class].Throttle
+}
+
+// SendAlert sends a class-specific alert. Repeats for the same class and account within
+// the class's throttle window are dropped and counted in the next alert, so a flapping
+// problem produces one clear message instead of a stream.
+func (n *Notifier) SendAlert(a Alert) error {
+	tmpl, ok := alertTemplates[a.Class]
+	if !ok {
+		return fmt.Errorf("unknown alert class %q", a.Class)
+	}
+
+	key := string(a.Class) + "/" + a.Account
+	now := n.clock()
+	window := n.throttleWindow(a.Class)
+
+	t := &n.throttle
+	t.mu.Lock()
+	if t.last == nil {
+		t.last, t.suppressed = make(map[string]time.Time), make(map[string]int)
+	}
+	if last, ok := t.last[key]; ok && now.Sub(last) < window {
+		t.suppressed[key]++
+		t.mu.Unlock()
+		return nil
+	}
+	suppressed := t.suppressed[key]
+	t.last[key] = now
+	t.suppressed[key] = 0
+	t.mu.Unlock()
+
+	fields := []Field{{Name: "Account", Value: a.Account}}
+	if a.Detail != "" {
+		fields = append(fields, Field{Name: "Details", Value: a.Detail})
+	}
+	fields = append(fields, a.Fields...)
+	if suppressed > 0 {
+		fields = append(fields, Field{Name: "Repeats", Value: fmt.Sprintf("%d more since the last alert", suppressed)})
+	}
+	fields = append(fields, Field{Name: "What To Do", Value: tmpl.Hint})
+
+	return n.Send(Message{
+		Title:    tmpl.Title,
+		Fields:   fields,
+		Color:    tmpl.Color,
+		Priority: tmpl.Priority,
+		Tags:     tmpl.Tags,
+	})
+}
+
+// ResolveAlert clears the throttle for class and account. If an alert was sent, and the
+// class has a recovery message, it is sent so the user knows the problem went away.
+func (n *Notifier) ResolveAlert(class Class, account string) error {
+	key := string(class) + "/" + account
+
+	t := &n.throttle
+	t.mu.Lock()
+	_, alerted := t.last[key]
+	delete(t.last, key)
+	delete(t.suppressed, key)
+	t.mu.Unlock()
+
+	tmpl := alertTemplates[class]
+	if !alerted || tmpl.Resolved == "" {
+		return nil
+	}
+	return n.Send(Message{
+		Title:    tmpl.Resolved,
+		Fields:   []Field{{Name: "Account", Value: account}},
+		Color:    ColorSuccess,
+		Priority: 3,
+		Tags:     "white_check_mark",
+	})
+}
@@ -0,0 +1,79 @@
+package notifier
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourusername/oci-arm-provisioner/internal/config"
+)
+
+func TestSendAlert_ThrottlesPerClassAndAccount(t *testing.T) {
+	n, sent := recordingNotifier(config.NotificationConfig{AlertThrottle: map[string]string{"verification": "0s"}})
+	now := time.Date(2025, 1, 2, 15, 0, 0, 0, time.UTC)
+	n.now = func() time.Time { return now }
+
+	auth := Alert{Class: ClassAuth, Account: "personal", Detail: "401 NotAuthenticated"}
+	n.SendAlert(auth)
+	n.SendAlert(auth)
+	n.SendAlert(auth)
+	n.SendAlert(Alert{Class: ClassAuth, Account: "work"})
+	if got := len(sent()); got != 2 {
+		t.Fatalf("expected one auth alert per account, got %d", got)
+	}
+
+	// A class with throttling disabled always goes through
+	n.SendAlert(Alert{Class: ClassVerification, Account: "personal"})
+	n.SendAlert(Alert{Class: ClassVerification, Account: "personal"})
+	if got := len(sent()); got != 4 {
+		t.Fatalf("expected unthrottled verification alerts, got %d sends", got)
+	}
+
+	// After the window the next alert reports what was dropped
+	now = now.Add(6*time.Hour + time.Second)
+	n.SendAlert(auth)
+	payloads := sent()
+	if len(payloads) != 5 {
+		t.Fatalf("expected auth alert after the window, got %d sends", len(payloads))
+	}
+	embed := payloads[4].Embeds[0]
+	if embed.Title != "🔑 Authentication Failed" {
+		t.Errorf("unexpected title %q", embed.Title)
+	}
+	var repeats string
+	for _, f := range embed.Fields {
+		if f.Name == "Repeats" {
+			repeats = f.Value
+		}
+	}
+	if !strings.HasPrefix(repeats, "2 more") {
+		t.Errorf("expected 2 suppressed repeats, got %q", repeats)
+	}
+
+	if err := n.SendAlert(Alert{Class: "bogus"}); err == nil {
+		t.Error("expected unknown class to be rejected")
+	}
+}
+
+func TestResolveAlert(t *testing.T) {
+	n, sent := recordingNotifier(config.NotificationConfig{})
+
+	n.ResolveAlert(ClassAuth, "personal")
+	if got := len(sent()); got != 0 {
+		t.Fatalf("expected no recovery message without a prior alert, got %d", got)
+	}
+
+	n.SendAlert(Alert{Class: ClassAuth, Account: "personal"})
+	n.ResolveAlert(ClassAuth, "personal")
+	n.ResolveAlert(ClassAuth, "personal")
+	payloads := sent()
+	if len(payloads) != 2 || payloads[1].Embeds[0].Title != "🔓 Authentication Restored" {
+		t.Fatalf("expected one recovery message, got %+v", payloads)
+	}
+
+	// Resolved: the next failure alerts right away
+	n.SendAlert(Alert{Class: ClassAuth, Account: "personal"})
+	if got := len(sent()); got != 3 {
+		t.Errorf("expected a new alert after recovery, got %d sends", got)
+	}
+}
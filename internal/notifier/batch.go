@@ -0,0 +1,174 @@
+package notifier
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxBatchFields mirrors Discord's per-embed field limit.
+const maxBatchFields = 25
+
+// batcher coalesces bursts of generic messages so providers receive one
+// combined alert instead of tripping their own rate limits.
+type batcher struct {
+	mu       sync.Mutex
+	window   time.Duration // Coalesce messages fired within this window. 0 = disabled.
+	perMin   int           // Max deliveries per interval. 0 = unlimited.
+	interval time.Duration // Rate-limit window (one minute outside of tests).
+	sent     []time.Time
+	pending  []Message
+	timer    *time.Timer
+}
+
+// prune drops send timestamps older than the rate-limit interval. Caller holds mu.
+func (b *batcher) prune(now time.Time) {
+	cutoff := now.Add(-b.interval)
+	i := 0
+	for i < len(b.sent) && !b.sent[i].After(cutoff) {
+		i++
+	}
+	b.sent = b.sent[i:]
+}
+
+// limited reports whether another delivery would exceed the rate limit. Caller holds mu.
+func (b *batcher) limited() bool {
+	return b.perMin > 0 && len(b.sent) >= b.perMin
+}
+
+// record counts a delivery that bypassed the batcher against the rate limit.
+func (b *batcher) record(now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.prune(now)
+	b.sent = append(b.sent, now)
+}
+
+// Send delivers msg to all enabled providers, subject to rate limiting and batching.
+// When a message is deferred, Send returns nil and delivery errors are reported via OnError.
+func (n *Notifier) Send(msg Message) error {
+	msg = n.annotate(msg)
+	b := &n.batch
+	b.mu.Lock()
+	now := time.Now()
+	b.prune(now)
+
+	if b.window <= 0 && !b.limited() && len(b.pending) == 0 {
+		b.sent = append(b.sent, now)
+		b.mu.Unlock()
+		return n.deliver(msg)
+	}
+
+	b.pending = append(b.pending, msg)
+	if b.timer == nil {
+		delay := b.window
+		if b.limited() {
+			if wait := b.sent[0].Add(b.interval).Sub(now); wait > delay {
+				delay = wait
+			}
+		}
+		b.timer = time.AfterFunc(delay, n.flushPending)
+	}
+	b.mu.Unlock()
+	return nil
+}
+
+// annotate adds the account notes to msg's "Account" field.
+func (n *Notifier) annotate(msg Message) Message {
+	if len(n.Notes) == 0 {
+		return msg
+	}
+	fields := make([]Field, len(msg.Fields))
+	for i, f := range msg.Fields {
+		if f.Name == "Account" {
+			f.Value = n.accountLabel(f.Value)
+		}
+		fields[i] = f
+	}
+	msg.Fields = fields
+	return msg
+}
+
+// flushPending runs when the batch window closes.
+func (n *Notifier) flushPending() {
+	b := &n.batch
+	b.mu.Lock()
+	b.timer = nil
+	now := time.Now()
+	b.prune(now)
+
+	if len(b.pending) == 0 {
+		b.mu.Unlock()
+		return
+	}
+	if b.limited() {
+		// Still over the limit: retry once the oldest delivery ages out.
+		b.timer = time.AfterFunc(b.sent[0].Add(b.interval).Sub(now), n.flushPending)
+		b.mu.Unlock()
+		return
+	}
+
+	msgs := b.pending
+	b.pending = nil
+	b.sent = append(b.sent, now)
+	b.mu.Unlock()
+
+	if err := n.deliver(combine(msgs)); err != nil && n.OnError != nil {
+		n.OnError(err)
+	}
+}
+
+// Flush immediately delivers any pending batch, ignoring the rate limit.
+// Call it on shutdown so queued alerts are not lost.
+func (n *Notifier) Flush() error {
+	b := &n.batch
+	b.mu.Lock()
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	msgs := b.pending
+	b.pending = nil
+	b.mu.Unlock()
+
+	if len(msgs) == 0 {
+		return nil
+	}
+	return n.deliver(combine(msgs))
+}
+
+// combine merges queued messages into a single alert, one field per original message.
+func combine(msgs []Message) Message {
+	if len(msgs) == 1 {
+		return msgs[0]
+	}
+
+	out := Message{
+		Title: fmt.Sprintf("📦 %d Notifications", len(msgs)),
+		Color: ColorInfo,
+		Tags:  "package",
+	}
+	for i, m := range msgs {
+		if m.Color == ColorError {
+			out.Color = ColorError
+		}
+		if m.Priority > out.Priority {
+			out.Priority = m.Priority
+		}
+		if i == maxBatchFields-1 && len(msgs) > maxBatchFields {
+			out.Fields = append(out.Fields, Field{Name: "…", Value: fmt.Sprintf("and %d more", len(msgs)-i)})
+			continue
+		}
+		if i >= maxBatchFields {
+			continue
+		}
+
+		parts := make([]string, 0, len(m.Fields))
+		for _, f := range m.Fields {
+			parts = append(parts, f.Name+": "+f.Value)
+		}
+		out.Fields = append(out.Fields, Field{Name: m.Title, Value: strings.Join(parts, "\n")})
+	}
+	return out
+}
@@ -0,0 +1,102 @@
+package notifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yourusername/oci-arm-provisioner/internal/config"
+)
+
+// recordingNotifier returns a Notifier whose webhook payloads are captured.
+func recordingNotifier(cfg config.NotificationConfig) (*Notifier, func() []discordPayload) {
+	cfg.WebhookURL = "http://discord.mock"
+	n := New(cfg)
+
+	var mu sync.Mutex
+	var payloads []discordPayload
+	n.Client.Transport = &mockTransport{
+		RoundTripFunc: func(req *http.Request) (*http.Response, error) {
+			var p discordPayload
+			json.NewDecoder(req.Body).Decode(&p)
+			mu.Lock()
+			payloads = append(payloads, p)
+			mu.Unlock()
+			return &http.Response{StatusCode: 200, Body: io.NopCloser(bytes.NewBufferString("{}"))}, nil
+		},
+	}
+	return n, func() []discordPayload {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]discordPayload(nil), payloads...)
+	}
+}
+
+func TestSend_BatchWindowCombinesBurst(t *testing.T) {
+	n, sent := recordingNotifier(config.NotificationConfig{BatchWindow: "50ms"})
+
+	for _, acc := range []string{"a", "b", "c"} {
+		if err := n.Send(Message{Title: "Verification failed", Fields: []Field{{Name: "Account", Value: acc}}, Color: ColorError}); err != nil {
+			t.Fatalf("Send failed: %v", err)
+		}
+	}
+	if got := len(sent()); got != 0 {
+		t.Fatalf("expected messages to be held during the window, got %d sends", got)
+	}
+
+	time.Sleep(150 * time.Millisecond)
+
+	payloads := sent()
+	if len(payloads) != 1 {
+		t.Fatalf("expected 1 combined message, got %d", len(payloads))
+	}
+	embed := payloads[0].Embeds[0]
+	if embed.Title != "📦 3 Notifications" {
+		t.Errorf("unexpected title %q", embed.Title)
+	}
+	if embed.Color != ColorError {
+		t.Error("combined message should escalate to error color")
+	}
+	if len(embed.Fields) != 3 {
+		t.Errorf("expected one field per message, got %d", len(embed.Fields))
+	}
+}
+
+func TestSend_RateLimitDefersOverflow(t *testing.T) {
+	n, sent := recordingNotifier(config.NotificationConfig{RateLimitPerMinute: 2})
+	n.batch.interval = 100 * time.Millisecond
+
+	for i := 0; i < 4; i++ {
+		n.Send(Message{Title: "event", Fields: []Field{{Name: "n", Value: "x"}}})
+	}
+	if got := len(sent()); got != 2 {
+		t.Fatalf("expected 2 immediate sends before limiting, got %d", got)
+	}
+
+	time.Sleep(250 * time.Millisecond)
+
+	payloads := sent()
+	if len(payloads) != 3 {
+		t.Fatalf("expected overflow delivered as 1 combined message, got %d total sends", len(payloads))
+	}
+	if payloads[2].Embeds[0].Title != "📦 2 Notifications" {
+		t.Errorf("unexpected combined title %q", payloads[2].Embeds[0].Title)
+	}
+}
+
+func TestFlush_DeliversPending(t *testing.T) {
+	n, sent := recordingNotifier(config.NotificationConfig{BatchWindow: "1h"})
+
+	n.Send(Message{Title: "queued"})
+	if err := n.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	payloads := sent()
+	if len(payloads) != 1 || payloads[0].Embeds[0].Title != "queued" {
+		t.Fatalf("expected single queued message to be flushed as-is, got %+v", payloads)
+	}
+}
@@ -0,0 +1,218 @@
+package notifier
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// successAttempts is how many times a success notification is tried per provider.
+const successAttempts = 3
+
+// DeliveryStatus is the outcome of a notification on one provider.
+type DeliveryStatus string
+
+const (
+	DeliveryDelivered    DeliveryStatus = "delivered"    // The provider accepted it.
+	DeliveryFailed       DeliveryStatus = "failed"       // Every attempt failed.
+	DeliveryAcknowledged DeliveryStatus = "acknowledged" // The user pressed the acknowledge button.
+)
+
+// Delivery tracks one provider's copy of a notification.
+type Delivery struct {
+	Provider string
+	Status   DeliveryStatus
+	Attempts int
+	Error    string    // Last error, if any.
+	At       time.Time // Last status change.
+}
+
+// Receipt records what happened to a success notification on each provider.
+type Receipt struct {
+	Account    string
+	InstanceID string
+	AckID      string // Token carried by the acknowledge buttons.
+	SentAt     time.Time
+	Deliveries []Delivery
+}
+
+// Delivered reports whether at least one provider got the notification to the user.
+func (r Receipt) Delivered() bool {
+	for _, d := range r.Deliveries {
+		if d.Status == DeliveryDelivered || d.Status == DeliveryAcknowledged {
+			return true
+		}
+	}
+	return false
+}
+
+// Pending returns the providers that delivered the notification with an acknowledge
+// button the user hasn't pressed yet.
+func (r Receipt) Pending() []string {
+	var pending []string
+	for _, d := range r.Deliveries {
+		if d.Status == DeliveryDelivered && (d.Provider == "ntfy" || d.Provider == "telegram") {
+			pending = append(pending, d.Provider)
+		}
+	}
+	return pending
+}
+
+// Acknowledge marks provider as acknowledged and reports whether anything changed.
+func (r *Receipt) Acknowledge(provider string, at time.Time) bool {
+	for i, d := range r.Deliveries {
+		if d.Provider == provider && d.Status == DeliveryDelivered {
+			r.Deliveries[i].Status, r.Deliveries[i].At = DeliveryAcknowledged, at
+			return true
+		}
+	}
+	return false
+}
+
+// providerSend is one provider's copy of a message.
+type providerSend struct {
+	provider string
+	send     func() error
+}
+
+// sendTracked sends to every provider, retrying failures with backoff, and records the
+// outcome for each in r.
+func (n *Notifier) sendTracked(r *Receipt, sends []providerSend) error {
+	var errs []error
+	for _, s := range sends {
+		d := Delivery{Provider: s.provider}
+		delay := n.retryDelay
+		for {
+			d.Attempts++
+			err := s.send()
+			if err == nil {
+				d.Status, d.Error = DeliveryDelivered, ""
+				break
+			}
+			d.Status, d.Error = DeliveryFailed, err.Error()
+			if d.Attempts == successAttempts {
+				errs = append(errs, err)
+				break
+			}
+			time.Sleep(delay)
+			delay *= 2
+		}
+		d.At = n.clock()
+		r.Deliveries = append(r.Deliveries, d)
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("notification errors: %v", errs)
+	}
+	return nil
+}
+
+// ackID derives the acknowledge token from the instance ID, so a message renders the same
+// every time it is previewed.
+func ackID(instanceID string) string {
+	sum := sha256.Sum256([]byte(instanceID))
+	return hex.EncodeToString(sum[:4])
+}
+
+// ntfyAckTopic is where the ntfy acknowledge button posts; it is polled by Acknowledged.
+func (n *Notifier) ntfyAckTopic() string {
+	return "https://ntfy.sh/" + n.Config.NtfyTopic + "-ack"
+}
+
+// ntfyAckAction renders the ntfy Actions header for the acknowledge button.
+func (n *Notifier) ntfyAckAction(id string) string {
+	return fmt.Sprintf("http, ✅ Got it, %s, method=POST, body=ack:%s, clear=true", n.ntfyAckTopic(), id)
+}
+
+// telegramAckButton is the inline keyboard carrying the acknowledge button.
+func telegramAckButton(id string) *telegramMarkup {
+	return &telegramMarkup{InlineKeyboard: [][]telegramButton{{{Text: "✅ Got it", CallbackData: "ack:" + id}}}}
+}
+
+// Acknowledged returns the providers on which the user pressed the acknowledge button
+// for r since it was sent.
+func (n *Notifier) Acknowledged(r Receipt) ([]string, error) {
+	var acked []string
+	var errs []error
+	for _, provider := range r.Pending() {
+		var ok bool
+		var err error
+		switch provider {
+		case "ntfy":
+			ok, err = n.ntfyAcked(r)
+		case "telegram":
+			ok, err = n.telegramAcked(r)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", provider, err))
+		}
+		if ok {
+			acked = append(acked, provider)
+		}
+	}
+	if len(errs) > 0 {
+		return acked, fmt.Errorf("checking acknowledgements: %v", errs)
+	}
+	return acked, nil
+}
+
+// ntfyAcked polls the ack topic for the receipt's token.
+func (n *Notifier) ntfyAcked(r Receipt) (bool, error) {
+	url := fmt.Sprintf("%s/json?poll=1&since=%d", n.ntfyAckTopic(), r.SentAt.Unix())
+	resp, err := n.Client.Get(url)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return false, fmt.Errorf("ntfy poll failed: %d", resp.StatusCode)
+	}
+
+	sc := bufio.NewScanner(resp.Body)
+	for sc.Scan() {
+		var event struct {
+			Event   string `json:"event"`
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(sc.Bytes(), &event) == nil && event.Event == "message" && event.Message == "ack:"+r.AckID {
+			return true, nil
+		}
+	}
+	return false, sc.Err()
+}
+
+// telegramAcked looks for a press of the receipt's button among the bot's pending updates.
+// Updates are not confirmed, so other tools reading the same bot still see them.
+func (n *Notifier) telegramAcked(r Receipt) (bool, error) {
+	base := "https://api.telegram.org/bot" + n.Config.TelegramToken
+	resp, err := n.Client.Get(base + "/getUpdates")
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return false, fmt.Errorf("getUpdates failed: %d", resp.StatusCode)
+	}
+
+	var updates struct {
+		Result []struct {
+			CallbackQuery *struct {
+				ID   string `json:"id"`
+				Data string `json:"data"`
+			} `json:"callback_query"`
+		} `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&updates); err != nil {
+		return false, err
+	}
+	for _, u := range updates.Result {
+		if q := u.CallbackQuery; q != nil && q.Data == "ack:"+r.AckID {
+			// Stops the button spinner; too-old queries are rejected, which is fine.
+			n.postJSON(base+"/answerCallbackQuery", map[string]string{"callback_query_id": q.ID, "text": "✅ Acknowledged"}, nil)
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,111 @@
+package notifier
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/oci-arm-provisioner/internal/config"
+)
+
+func TestSendSuccessVerified_Receipt(t *testing.T) {
+	n := New(config.NotificationConfig{
+		WebhookURL:  "http://discord.mock",
+		NtfyTopic:   "topic",
+		GotifyURL:   "http://gotify.mock",
+		GotifyToken: "token",
+	})
+	n.retryDelay = 0
+	gotifyCalls := 0
+	var actions string
+	n.Client.Transport = &mockTransport{
+		RoundTripFunc: func(req *http.Request) (*http.Response, error) {
+			status := http.StatusOK
+			switch {
+			case strings.Contains(req.URL.Host, "discord"):
+				status = http.StatusBadGateway // never gets through
+			case strings.Contains(req.URL.Host, "gotify"):
+				if gotifyCalls++; gotifyCalls == 1 {
+					status = http.StatusServiceUnavailable // gets through on retry
+				}
+			case strings.Contains(req.URL.Host, "ntfy"):
+				actions = req.Header.Get("Actions")
+			}
+			return &http.Response{StatusCode: status, Body: io.NopCloser(bytes.NewBufferString("{}"))}, nil
+		},
+	}
+
+	r, err := n.SendSuccessVerified("acc", &mockVerifiedDetails{instanceID: "ocid1.instance.test"})
+	if err == nil {
+		t.Error("expected an error for the webhook that never got through")
+	}
+
+	got := make(map[string]Delivery)
+	for _, d := range r.Deliveries {
+		got[d.Provider] = d
+	}
+	if d := got["webhook"]; d.Status != DeliveryFailed || d.Attempts != successAttempts {
+		t.Errorf("webhook: expected failed after %d attempts, got %+v", successAttempts, d)
+	}
+	if d := got["gotify"]; d.Status != DeliveryDelivered || d.Attempts != 2 {
+		t.Errorf("gotify: expected delivered on attempt 2, got %+v", d)
+	}
+	if d := got["ntfy"]; d.Status != DeliveryDelivered || d.Attempts != 1 {
+		t.Errorf("ntfy: expected delivered on attempt 1, got %+v", d)
+	}
+	if !strings.Contains(actions, "topic-ack") || !strings.Contains(actions, "body=ack:"+r.AckID) {
+		t.Errorf("expected an acknowledge button posting to the ack topic, got %q", actions)
+	}
+	if p := r.Pending(); len(p) != 1 || p[0] != "ntfy" {
+		t.Errorf("expected only ntfy to await acknowledgement, got %v", p)
+	}
+	if !r.Delivered() {
+		t.Error("expected the receipt to count as delivered")
+	}
+	if (Receipt{InstanceID: "x", Deliveries: []Delivery{got["webhook"]}}).Delivered() {
+		t.Error("expected a receipt with only failures not to count as delivered")
+	}
+}
+
+func TestAcknowledged(t *testing.T) {
+	n := New(config.NotificationConfig{NtfyTopic: "topic", TelegramToken: "tg", TelegramChatID: "1"})
+	r := Receipt{AckID: "abcd1234", Deliveries: []Delivery{
+		{Provider: "ntfy", Status: DeliveryDelivered},
+		{Provider: "telegram", Status: DeliveryDelivered},
+	}}
+	answered := false
+	n.Client.Transport = &mockTransport{
+		RoundTripFunc: func(req *http.Request) (*http.Response, error) {
+			body := "{}"
+			switch {
+			case strings.HasSuffix(req.URL.Path, "/topic-ack/json"):
+				body = `{"event":"open"}` + "\n" + `{"event":"message","message":"ack:other"}` + "\n"
+			case strings.HasSuffix(req.URL.Path, "/getUpdates"):
+				body = fmt.Sprintf(`{"ok":true,"result":[{"update_id":1,"callback_query":{"id":"q1","data":"ack:%s"}}]}`, r.AckID)
+			case strings.HasSuffix(req.URL.Path, "/answerCallbackQuery"):
+				answered = true
+			}
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body))}, nil
+		},
+	}
+
+	acked, err := n.Acknowledged(r)
+	if err != nil {
+		t.Fatalf("Acknowledged: %v", err)
+	}
+	if len(acked) != 1 || acked[0] != "telegram" {
+		t.Errorf("expected only telegram acknowledged, got %v", acked)
+	}
+	if !answered {
+		t.Error("expected the button press to be answered")
+	}
+	if !r.Acknowledge("telegram", previewTime) || r.Acknowledge("telegram", previewTime) {
+		t.Error("expected Acknowledge to change the status exactly once")
+	}
+	if p := r.Pending(); len(p) != 1 || p[0] != "ntfy" {
+		t.Errorf("expected ntfy still pending, got %v", p)
+	}
+}
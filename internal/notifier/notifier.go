@@ -4,24 +4,63 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"html"
 	"net/http"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/yourusername/oci-arm-provisioner/internal/buildinfo"
 	"github.com/yourusername/oci-arm-provisioner/internal/config"
 )
 
-// Notifier handles sending alerts to various platforms (Discord, Telegram, Ntfy).
+// Notifier handles sending alerts to various platforms (Discord, Telegram, Ntfy, Gotify, Teams, Signal, WhatsApp).
 type Notifier struct {
 	Config config.NotificationConfig
 	Client *http.Client
+
+	// OnError receives delivery errors for messages flushed asynchronously by the batcher.
+	OnError func(err error)
+
+	// Notes are per-account annotations (accounts.<name>.notes) shown next to the account name.
+	Notes map[string]string
+
+	batch      batcher
+	throttle   throttle
+	status     providerStatuses
+	retryDelay time.Duration    // First wait before retrying a failed success notification.
+	now        func() time.Time // Clock used in rendered messages and alert throttling; fixed in previews and tests.
 }
 
 // New creates a new Notifier instance with the given configuration.
 func New(cfg config.NotificationConfig) *Notifier {
+	window, _ := time.ParseDuration(cfg.BatchWindow)
 	return &Notifier{
 		Config: cfg,
 		Client: &http.Client{Timeout: 10 * time.Second},
+		batch: batcher{
+			window:   window,
+			perMin:   cfg.RateLimitPerMinute,
+			interval: time.Minute,
+		},
+		retryDelay: 2 * time.Second,
+		now:        time.Now,
+	}
+}
+
+// accountLabel returns the account name with its notes, e.g. "family (mum's account)".
+func (n *Notifier) accountLabel(account string) string {
+	if notes := n.Notes[account]; notes != "" {
+		return account + " (" + notes + ")"
+	}
+	return account
+}
+
+func (n *Notifier) clock() time.Time {
+	if n.now == nil {
+		return time.Now()
 	}
+	return n.now()
 }
 
 // --- Payload Structures ---
@@ -46,6 +85,11 @@ type field struct {
 	Inline bool   `json:"inline"`
 }
 
+// footerText signs webhook embeds with the running version.
+func footerText() string {
+	return "OCI ARM Provisioner " + buildinfo.String()
+}
+
 const (
 	ColorSuccess = 5763719
 	ColorError   = 15548997
@@ -54,9 +98,19 @@ const (
 
 // Telegram
 type telegramPayload struct {
-	ChatID    string `json:"chat_id"`
-	Text      string `json:"text"`
-	ParseMode string `json:"parse_mode"`
+	ChatID      string          `json:"chat_id"`
+	Text        string          `json:"text"`
+	ParseMode   string          `json:"parse_mode"`
+	ReplyMarkup *telegramMarkup `json:"reply_markup,omitempty"`
+}
+
+type telegramMarkup struct {
+	InlineKeyboard [][]telegramButton `json:"inline_keyboard"`
+}
+
+type telegramButton struct {
+	Text         string `json:"text"`
+	CallbackData string `json:"callback_data"`
 }
 
 // Gotify
@@ -98,30 +152,43 @@ func (n *Notifier) postJSON(url string, payload interface{}, headers map[string]
 
 // --- Senders ---
 
-func (n *Notifier) sendWebhook(payload discordPayload) error {
+func (n *Notifier) sendWebhook(payload discordPayload) (err error) {
 	if n.Config.WebhookURL == "" {
 		return nil
 	}
+	defer func() { n.status.record("webhook", err, n.clock()) }()
+	switch n.Config.WebhookFormat {
+	case "mattermost", "rocketchat":
+		return n.postJSON(n.Config.WebhookURL, chatMessage(payload, n.Config.WebhookFormat), nil)
+	}
 	return n.postJSON(n.Config.WebhookURL, payload, nil)
 }
 
 func (n *Notifier) sendTelegram(text string) error {
+	return n.sendTelegramMarkup(text, nil)
+}
+
+func (n *Notifier) sendTelegramMarkup(text string, markup *telegramMarkup) (err error) {
 	if n.Config.TelegramToken == "" || n.Config.TelegramChatID == "" {
 		return nil
 	}
+	defer func() { n.status.record("telegram", err, n.clock()) }()
 	url := fmt.Sprintf("https://api.telegram.org/bot%s/sendMessage", n.Config.TelegramToken)
 	payload := telegramPayload{
-		ChatID:    n.Config.TelegramChatID,
-		Text:      text,
-		ParseMode: "HTML",
+		ChatID:      n.Config.TelegramChatID,
+		Text:        text,
+		ParseMode:   "HTML",
+		ReplyMarkup: markup,
 	}
 	return n.postJSON(url, payload, nil)
 }
 
-func (n *Notifier) sendNtfy(message, title string, priority int, tags string) error {
+// sendNtfy publishes a message; actions is an optional ntfy Actions header (action buttons).
+func (n *Notifier) sendNtfy(message, title string, priority int, tags, actions string) (err error) {
 	if n.Config.NtfyTopic == "" {
 		return nil
 	}
+	defer func() { n.status.record("ntfy", err, n.clock()) }()
 	url := fmt.Sprintf("https://ntfy.sh/%s", n.Config.NtfyTopic)
 	// Ntfy usually takes raw body, but json is also supported.
 	// For raw body we can't use postJSON easily without changing signature.
@@ -134,6 +201,9 @@ func (n *Notifier) sendNtfy(message, title string, priority int, tags string) er
 	req.Header.Set("Priority", fmt.Sprintf("%d", priority))
 	req.Header.Set("Tags", tags)
 	req.Header.Set("Markdown", "yes")
+	if actions != "" {
+		req.Header.Set("Actions", actions)
+	}
 
 	resp, err := n.Client.Do(req)
 	if err != nil {
@@ -146,10 +216,11 @@ func (n *Notifier) sendNtfy(message, title string, priority int, tags string) er
 	return nil
 }
 
-func (n *Notifier) sendGotify(message, title string, priority int) error {
+func (n *Notifier) sendGotify(message, title string, priority int) (err error) {
 	if n.Config.GotifyURL == "" || n.Config.GotifyToken == "" {
 		return nil
 	}
+	defer func() { n.status.record("gotify", err, n.clock()) }()
 	// Sanitize URL (ensure no trailing slash logic or just rely on user)
 	// Assuming well formed URL for now.
 	url := fmt.Sprintf("%s/message?token=%s", n.Config.GotifyURL, n.Config.GotifyToken)
@@ -173,6 +244,7 @@ func (n *Notifier) sendGotify(message, title string, priority int) error {
 // Returns an aggregate error if any provider fails.
 func (n *Notifier) SendSuccess(account, instanceID, region string) error {
 	var errs []error
+	account = n.accountLabel(account)
 
 	// 1. Discord/Slack Webhook
 	if n.Config.WebhookURL != "" {
@@ -188,7 +260,7 @@ func (n *Notifier) SendSuccess(account, instanceID, region string) error {
 				{Name: "Region", Value: region, Inline: true},
 				{Name: "Instance ID", Value: instanceID, Inline: false},
 			},
-			Footer: &footer{Text: "OCI ARM Provisioner • " + time.Now().Format("2006-01-02 15:04:05")},
+			Footer: &footer{Text: footerText() + " • " + n.clock().Format("2006-01-02 15:04:05")},
 		}
 		if err := n.sendWebhook(discordPayload{Content: content, Embeds: []discordEmbed{embed}}); err != nil {
 			errs = append(errs, err)
@@ -197,7 +269,7 @@ func (n *Notifier) SendSuccess(account, instanceID, region string) error {
 
 	// 2. Telegram
 	if n.Config.TelegramToken != "" {
-		msg := fmt.Sprintf("<b>🚀 Instance Launched!</b>\n\n<b>Account:</b> %s\n<b>Region:</b> %s\n<b>Instance ID:</b> <code>%s</code>", account, region, instanceID)
+		msg := fmt.Sprintf("<b>🚀 Instance Launched!</b>\n\n<b>Account:</b> %s\n<b>Region:</b> %s\n<b>Instance ID:</b> <code>%s</code>", html.EscapeString(account), region, instanceID)
 		if n.Config.InsistentPing {
 			msg = "🚨 <b>ATTENTION!</b> 🚨\n\n" + msg
 		}
@@ -213,7 +285,7 @@ func (n *Notifier) SendSuccess(account, instanceID, region string) error {
 			priority = 5
 		}
 		msg := fmt.Sprintf("**Instance Launched!**\n\n**Account:** %s\n**Region:** %s\n**ID:** `%s`", account, region, instanceID)
-		if err := n.sendNtfy(msg, "🚀 OCI Provision Success", priority, "tada,rocket"); err != nil {
+		if err := n.sendNtfy(msg, "🚀 OCI Provision Success", priority, "tada,rocket", ""); err != nil {
 			errs = append(errs, err)
 		}
 	}
@@ -230,12 +302,171 @@ func (n *Notifier) SendSuccess(account, instanceID, region string) error {
 		}
 	}
 
+	// 5. Microsoft Teams
+	if n.Config.TeamsWebhookURL != "" {
+		card := teamsMessage("✅ OCI Instance Launched Successfully", ColorSuccess, []Field{
+			{Name: "Account", Value: account},
+			{Name: "Region", Value: region},
+			{Name: "Instance ID", Value: instanceID},
+		}, footerText()+" • "+n.clock().Format("2006-01-02 15:04:05"))
+		if err := n.sendTeams(card); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	// 6. Signal
+	if n.signalEnabled() {
+		msg := fmt.Sprintf("**Account:** %s\n**Region:** %s\n**ID:** `%s`", account, region, instanceID)
+		if err := n.sendSignal("🚀 Instance Launched!", msg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	// 7. WhatsApp
+	if n.whatsappEnabled() {
+		if err := n.sendWhatsApp(account, region, "Pending...", instanceID); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("notification errors: %v", errs)
+	}
+	return nil
+}
+
+// Message is a provider-agnostic alert. Each provider renders it in its native format.
+type Message struct {
+	Title    string
+	Fields   []Field
+	Color    int    // Discord embed color (ColorSuccess, ColorError, ColorInfo).
+	Priority int    // Ntfy scale 1-5; Gotify receives double this value.
+	Tags     string // Ntfy tags (comma-separated emoji shortcodes).
+}
+
+// Field is a labelled value within a Message.
+type Field struct {
+	Name  string
+	Value string
+}
+
+// deliver renders a generic Message for all enabled providers and sends it immediately.
+func (n *Notifier) deliver(msg Message) error {
+	var errs []error
+
+	// 1. Discord/Slack Webhook
+	if n.Config.WebhookURL != "" {
+		fields := make([]field, 0, len(msg.Fields))
+		for _, f := range msg.Fields {
+			fields = append(fields, field{Name: f.Name, Value: f.Value, Inline: len(f.Value) < 40})
+		}
+		embed := discordEmbed{
+			Title:  msg.Title,
+			Color:  msg.Color,
+			Fields: fields,
+			Footer: &footer{Text: footerText() + " • " + n.clock().Format("2006-01-02 15:04:05")},
+		}
+		if err := n.sendWebhook(discordPayload{Embeds: []discordEmbed{embed}}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	// 2. Telegram
+	if n.Config.TelegramToken != "" {
+		var b strings.Builder
+		b.WriteString("<b>" + html.EscapeString(msg.Title) + "</b>\n")
+		for _, f := range msg.Fields {
+			fmt.Fprintf(&b, "\n<b>%s:</b> %s", html.EscapeString(f.Name), html.EscapeString(f.Value))
+		}
+		if err := n.sendTelegram(b.String()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	// 3. Ntfy, 4. Gotify & 6. Signal share the same Markdown body.
+	var md strings.Builder
+	for i, f := range msg.Fields {
+		if i > 0 {
+			md.WriteString("\n")
+		}
+		fmt.Fprintf(&md, "**%s:** %s", f.Name, f.Value)
+	}
+	priority := msg.Priority
+	if priority == 0 {
+		priority = 3
+	}
+
+	if n.Config.NtfyTopic != "" {
+		if err := n.sendNtfy(md.String(), msg.Title, priority, msg.Tags, ""); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if n.Config.GotifyURL != "" {
+		if err := n.sendGotify(md.String(), msg.Title, priority*2); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	// 5. Microsoft Teams
+	if n.Config.TeamsWebhookURL != "" {
+		card := teamsMessage(msg.Title, msg.Color, msg.Fields, footerText()+" • "+n.clock().Format("2006-01-02 15:04:05"))
+		if err := n.sendTeams(card); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	// 6. Signal
+	if n.signalEnabled() {
+		if err := n.sendSignal(msg.Title, md.String()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
 	if len(errs) > 0 {
 		return fmt.Errorf("notification errors: %v", errs)
 	}
 	return nil
 }
 
+// SendUpdateAvailable announces that a newer release has been published.
+func (n *Notifier) SendUpdateAvailable(current, latest, url string) error {
+	return n.Send(Message{
+		Title: "⬆️ Update Available",
+		Fields: []Field{
+			{Name: "Current", Value: current},
+			{Name: "Latest", Value: latest},
+			{Name: "Release", Value: url},
+		},
+		Color:    ColorInfo,
+		Priority: 3,
+		Tags:     "arrow_up",
+	})
+}
+
+// SendExitSummary delivers the end-of-run report. It bypasses batching since the process is exiting.
+func (n *Notifier) SendExitSummary(stats Stats) error {
+	msg := Message{
+		Title: "🛑 Provisioner Stopped",
+		Fields: []Field{
+			{Name: "Uptime", Value: n.clock().Sub(stats.StartTime).Round(time.Second).String()},
+			{Name: "Cycles", Value: fmt.Sprintf("%d", stats.TotalCycles)},
+			{Name: "Provisioned", Value: fmt.Sprintf("%d", stats.SuccessCount)},
+			{Name: "Version", Value: buildinfo.String()},
+		},
+		Color:    ColorInfo,
+		Priority: 3,
+		Tags:     "stop_sign",
+	}
+	for _, name := range stats.AccountNames() {
+		if len(msg.Fields) >= maxBatchFields {
+			break
+		}
+		msg.Fields = append(msg.Fields, Field{Name: n.accountLabel(name), Value: stats.Accounts[name].Summary()})
+	}
+	return n.deliver(msg)
+}
+
 // VerifiedInstanceDetails is an interface for receiving verified instance information.
 type VerifiedInstanceDetails interface {
 	GetInstanceID() string
@@ -246,14 +477,33 @@ type VerifiedInstanceDetails interface {
 	GetRegion() string
 }
 
+// ipv6Details is implemented by details that carry the instance's IPv6 address.
+type ipv6Details interface {
+	GetIPv6() string
+}
+
+// hostKeyDetails is implemented by details that carry the instance's SSH host key
+// fingerprints, e.g. "ED25519 SHA256:...".
+type hostKeyDetails interface {
+	GetHostKeys() []string
+}
+
+// ordinalDetails is implemented by details of accounts that keep several instances:
+// this is instance n of count.
+type ordinalDetails interface {
+	GetOrdinal() (n, count int)
+}
+
 // SendSuccessVerified triggers a "Success" alert with verified instance details.
-// Includes Public IP and verified specs in notifications.
-func (n *Notifier) SendSuccessVerified(account string, details VerifiedInstanceDetails) error {
+// Includes Public IP and verified specs in notifications. Failed providers are retried,
+// and the returned Receipt records what reached each one.
+func (n *Notifier) SendSuccessVerified(account string, details VerifiedInstanceDetails) (Receipt, error) {
 	if details == nil {
-		return fmt.Errorf("no verified instance details provided")
+		return Receipt{}, fmt.Errorf("no verified instance details provided")
 	}
+	// Success alerts are never delayed, but they still count toward the rate limit.
+	n.batch.record(time.Now())
 
-	var errs []error
 	instanceID := details.GetInstanceID()
 	region := details.GetRegion()
 	publicIP := details.GetPublicIP()
@@ -263,6 +513,33 @@ func (n *Notifier) SendSuccessVerified(account string, details VerifiedInstanceD
 	if publicIP == "" {
 		publicIP = "Pending..."
 	}
+	var ipv6 string
+	if d, ok := details.(ipv6Details); ok {
+		ipv6 = d.GetIPv6()
+	}
+	// Markdown line of the IPv6 address for ntfy, Gotify and Signal
+	var mdIPv6 string
+	if ipv6 != "" {
+		mdIPv6 = "\n**IPv6:** `" + ipv6 + "`"
+	}
+	var hostKeys []string
+	if d, ok := details.(hostKeyDetails); ok {
+		hostKeys = d.GetHostKeys()
+	}
+	// Markdown list of the fingerprints for ntfy, Gotify and Signal
+	var mdKeys string
+	if len(hostKeys) > 0 {
+		mdKeys = "\n**SSH Host Keys:**\n`" + strings.Join(hostKeys, "`\n`") + "`"
+	}
+
+	receipt := Receipt{Account: account, InstanceID: instanceID, AckID: ackID(instanceID), SentAt: n.clock()}
+	account = n.accountLabel(account)
+	if d, ok := details.(ordinalDetails); ok {
+		if i, count := d.GetOrdinal(); count > 1 {
+			account += fmt.Sprintf(" (instance %d of %d)", i, count)
+		}
+	}
+	var sends []providerSend
 
 	// 1. Discord/Slack Webhook
 	if n.Config.WebhookURL != "" {
@@ -281,11 +558,17 @@ func (n *Notifier) SendSuccessVerified(account string, details VerifiedInstanceD
 				{Name: "Specs", Value: specs, Inline: true},
 				{Name: "Instance ID", Value: "`" + instanceID + "`", Inline: false},
 			},
-			Footer: &footer{Text: "OCI ARM Provisioner • " + time.Now().Format("2006-01-02 15:04:05")},
+			Footer: &footer{Text: footerText() + " • " + n.clock().Format("2006-01-02 15:04:05")},
 		}
-		if err := n.sendWebhook(discordPayload{Content: content, Embeds: []discordEmbed{embed}}); err != nil {
-			errs = append(errs, err)
+		if ipv6 != "" {
+			embed.Fields = append(embed.Fields, field{Name: "IPv6", Value: "`" + ipv6 + "`"})
+		}
+		if len(hostKeys) > 0 {
+			embed.Fields = append(embed.Fields, field{Name: "SSH Host Keys", Value: "```\n" + strings.Join(hostKeys, "\n") + "\n```"})
 		}
+		sends = append(sends, providerSend{"webhook", func() error {
+			return n.sendWebhook(discordPayload{Content: content, Embeds: []discordEmbed{embed}})
+		}})
 	}
 
 	// 2. Telegram
@@ -297,13 +580,19 @@ func (n *Notifier) SendSuccessVerified(account string, details VerifiedInstanceD
 			"<b>Public IP:</b> <code>%s</code>\n"+
 			"<b>Specs:</b> %s\n"+
 			"<b>Instance ID:</b> <code>%s</code>",
-			account, region, state, publicIP, specs, instanceID)
+			html.EscapeString(account), region, state, publicIP, specs, instanceID)
+		if ipv6 != "" {
+			msg += "\n<b>IPv6:</b> <code>" + ipv6 + "</code>"
+		}
+		if len(hostKeys) > 0 {
+			msg += "\n<b>SSH Host Keys:</b>\n<code>" + html.EscapeString(strings.Join(hostKeys, "\n")) + "</code>"
+		}
 		if n.Config.InsistentPing {
 			msg = "🚨 <b>ATTENTION!</b> 🚨\n\n" + msg
 		}
-		if err := n.sendTelegram(msg); err != nil {
-			errs = append(errs, err)
-		}
+		sends = append(sends, providerSend{"telegram", func() error {
+			return n.sendTelegramMarkup(msg, telegramAckButton(receipt.AckID))
+		}})
 	}
 
 	// 3. Ntfy
@@ -319,10 +608,10 @@ func (n *Notifier) SendSuccessVerified(account string, details VerifiedInstanceD
 			"**Public IP:** `%s`\n"+
 			"**Specs:** %s\n"+
 			"**ID:** `%s`",
-			account, region, state, publicIP, specs, instanceID)
-		if err := n.sendNtfy(msg, "🚀 OCI Provision Success", priority, "tada,rocket,white_check_mark"); err != nil {
-			errs = append(errs, err)
-		}
+			account, region, state, publicIP, specs, instanceID) + mdIPv6 + mdKeys
+		sends = append(sends, providerSend{"ntfy", func() error {
+			return n.sendNtfy(msg, "🚀 OCI Provision Success", priority, "tada,rocket,white_check_mark", n.ntfyAckAction(receipt.AckID))
+		}})
 	}
 
 	// 4. Gotify
@@ -338,16 +627,57 @@ func (n *Notifier) SendSuccessVerified(account string, details VerifiedInstanceD
 			"**Public IP:** `%s`\n"+
 			"**Specs:** %s\n"+
 			"**ID:** `%s`",
-			account, region, state, publicIP, specs, instanceID)
-		if err := n.sendGotify(msg, "🚀 OCI Provision Success", priority); err != nil {
-			errs = append(errs, err)
+			account, region, state, publicIP, specs, instanceID) + mdIPv6 + mdKeys
+		sends = append(sends, providerSend{"gotify", func() error {
+			return n.sendGotify(msg, "🚀 OCI Provision Success", priority)
+		}})
+	}
+
+	// 5. Microsoft Teams
+	if n.Config.TeamsWebhookURL != "" {
+		fields := []Field{
+			{Name: "Account", Value: account},
+			{Name: "Region", Value: region},
+			{Name: "State", Value: state + " ✓"},
+			{Name: "Public IP", Value: publicIP},
+			{Name: "Specs", Value: specs},
+			{Name: "Instance ID", Value: instanceID},
 		}
+		if ipv6 != "" {
+			fields = append(fields, Field{Name: "IPv6", Value: ipv6})
+		}
+		if len(hostKeys) > 0 {
+			fields = append(fields, Field{Name: "SSH Host Keys", Value: strings.Join(hostKeys, "\n")})
+		}
+		card := teamsMessage("✅ OCI Instance Launched & Verified", ColorSuccess, fields, footerText()+" • "+n.clock().Format("2006-01-02 15:04:05"))
+		sends = append(sends, providerSend{"teams", func() error {
+			return n.sendTeams(card)
+		}})
 	}
 
-	if len(errs) > 0 {
-		return fmt.Errorf("notification errors: %v", errs)
+	// 6. Signal
+	if n.signalEnabled() {
+		msg := fmt.Sprintf("**Account:** %s\n"+
+			"**Region:** %s\n"+
+			"**State:** %s ✓\n"+
+			"**Public IP:** `%s`\n"+
+			"**Specs:** %s\n"+
+			"**ID:** `%s`",
+			account, region, state, publicIP, specs, instanceID) + mdIPv6 + mdKeys
+		sends = append(sends, providerSend{"signal", func() error {
+			return n.sendSignal("🚀 Instance Launched & Verified!", msg)
+		}})
 	}
-	return nil
+
+	// 7. WhatsApp
+	if n.whatsappEnabled() {
+		sends = append(sends, providerSend{"whatsapp", func() error {
+			return n.sendWhatsApp(account, region, publicIP, instanceID)
+		}})
+	}
+
+	err := n.sendTracked(&receipt, sends)
+	return receipt, err
 }
 
 // Stats holds metrics for the digest
@@ -358,11 +688,23 @@ type Stats struct {
 	OtherErrors     int
 	SuccessCount    int
 	LastSuccessTime time.Time
+	Accounts        map[string]AccountStats
+	Costs           map[string]Cost // PAYG accounts with cost_report enabled.
+}
+
+// costNames returns the accounts with a cost report, sorted.
+func (s Stats) costNames() []string {
+	names := make([]string, 0, len(s.Costs))
+	for name := range s.Costs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
 
 // SendDigest triggers a status report alert to all enabled providers.
 func (n *Notifier) SendDigest(stats Stats) error {
-	uptime := time.Since(stats.StartTime).Round(time.Second)
+	uptime := n.clock().Sub(stats.StartTime).Round(time.Second)
 	var errs []error
 
 	// Discord
@@ -376,7 +718,10 @@ func (n *Notifier) SendDigest(stats Stats) error {
 				{Name: "Capacity Limits", Value: fmt.Sprintf("%d", stats.CapacityErrors), Inline: true},
 				{Name: "Other Errors", Value: fmt.Sprintf("%d", stats.OtherErrors), Inline: true},
 			},
-			Footer: &footer{Text: "OCI ARM Provisioner"},
+			Footer: &footer{Text: footerText()},
+		}
+		for _, name := range stats.costNames() {
+			embed.Fields = append(embed.Fields, field{Name: "💰 Cost: " + n.accountLabel(name), Value: stats.Costs[name].String(), Inline: true})
 		}
 		if err := n.sendWebhook(discordPayload{Embeds: []discordEmbed{embed}}); err != nil {
 			errs = append(errs, err)
@@ -387,25 +732,54 @@ func (n *Notifier) SendDigest(stats Stats) error {
 	if n.Config.TelegramToken != "" {
 		msg := fmt.Sprintf("<b>📊 Daily Digest</b>\n\n🕒 <b>Uptime:</b> %s\n🔄 <b>Cycles:</b> %d\n⚠️ <b>Capacity Hits:</b> %d\n❌ <b>Errors:</b> %d",
 			uptime.String(), stats.TotalCycles, stats.CapacityErrors, stats.OtherErrors)
+		for _, name := range stats.costNames() {
+			msg += fmt.Sprintf("\n💰 <b>Cost (%s):</b> %s", html.EscapeString(n.accountLabel(name)), html.EscapeString(stats.Costs[name].String()))
+		}
 		if err := n.sendTelegram(msg); err != nil {
 			errs = append(errs, err)
 		}
 	}
 
+	// Ntfy, Gotify & Signal share the same Markdown body.
+	md := fmt.Sprintf("**Daily Digest**\n\n🕒 **Uptime:** %s\n🔄 **Cycles:** %d\n⚠️ **Capacity Hits:** %d\n❌ **Errors:** %d",
+		uptime.String(), stats.TotalCycles, stats.CapacityErrors, stats.OtherErrors)
+	for _, name := range stats.costNames() {
+		md += fmt.Sprintf("\n💰 **Cost (%s):** %s", n.accountLabel(name), stats.Costs[name].String())
+	}
+
 	// Ntfy
 	if n.Config.NtfyTopic != "" {
-		msg := fmt.Sprintf("**Daily Digest**\n\n🕒 **Uptime:** %s\n🔄 **Cycles:** %d\n⚠️ **Capacity Hits:** %d\n❌ **Errors:** %d",
-			uptime.String(), stats.TotalCycles, stats.CapacityErrors, stats.OtherErrors)
-		if err := n.sendNtfy(msg, "📊 Status Report", 3, "chart_with_upwards_trend"); err != nil {
+		if err := n.sendNtfy(md, "📊 Status Report", 3, "chart_with_upwards_trend", ""); err != nil {
 			errs = append(errs, err)
 		}
 	}
 
 	// Gotify
 	if n.Config.GotifyURL != "" {
-		msg := fmt.Sprintf("**Daily Digest**\n\n🕒 **Uptime:** %s\n🔄 **Cycles:** %d\n⚠️ **Capacity Hits:** %d\n❌ **Errors:** %d",
-			uptime.String(), stats.TotalCycles, stats.CapacityErrors, stats.OtherErrors)
-		if err := n.sendGotify(msg, "📊 Status Report", 4); err != nil {
+		if err := n.sendGotify(md, "📊 Status Report", 4); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	// Microsoft Teams
+	if n.Config.TeamsWebhookURL != "" {
+		fields := []Field{
+			{Name: "Uptime", Value: uptime.String()},
+			{Name: "Total Cycles", Value: fmt.Sprintf("%d", stats.TotalCycles)},
+			{Name: "Capacity Limits", Value: fmt.Sprintf("%d", stats.CapacityErrors)},
+			{Name: "Other Errors", Value: fmt.Sprintf("%d", stats.OtherErrors)},
+		}
+		for _, name := range stats.costNames() {
+			fields = append(fields, Field{Name: "💰 Cost: " + n.accountLabel(name), Value: stats.Costs[name].String()})
+		}
+		if err := n.sendTeams(teamsMessage("📊 Daily Execution Digest", ColorInfo, fields, footerText())); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	// Signal
+	if n.signalEnabled() {
+		if err := n.sendSignal("📊 Status Report", strings.TrimPrefix(md, "**Daily Digest**\n\n")); err != nil {
 			errs = append(errs, err)
 		}
 	}
@@ -27,14 +27,24 @@ func (m *mockTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 
 func TestNotifier_AllProviders(t *testing.T) {
 	cfg := config.NotificationConfig{
-		Enabled:        true,
-		InsistentPing:  true,
-		WebhookURL:     "http://discord.mock",
-		TelegramToken:  "tg-token",
-		TelegramChatID: "tg-chat",
-		NtfyTopic:      "ntfy-topic",
-		GotifyURL:      "http://gotify.mock",
-		GotifyToken:    "gotify-token",
+		Enabled:          true,
+		InsistentPing:    true,
+		WebhookURL:       "http://discord.mock",
+		TelegramToken:    "tg-token",
+		TelegramChatID:   "tg-chat",
+		NtfyTopic:        "ntfy-topic",
+		GotifyURL:        "http://gotify.mock",
+		GotifyToken:      "gotify-token",
+		TeamsWebhookURL:  "http://teams.mock",
+		SignalURL:        "http://signal.mock/",
+		SignalNumber:     "+15550000000",
+		SignalRecipients: []string{"+15551111111"},
+
+		WhatsAppToken:         "wa-token",
+		WhatsAppPhoneNumberID: "1234",
+		WhatsAppRecipient:     "15552222222",
+		WhatsAppTemplate:      "oci_instance_ready",
+		WhatsAppLanguage:      "en_US",
 	}
 
 	n := New(cfg)
@@ -97,6 +107,39 @@ func TestNotifier_AllProviders(t *testing.T) {
 				if p.Title != "🚀 OCI Provision Success" {
 					t.Error("Gotify invalid title")
 				}
+
+			} else if strings.Contains(url, "teams") {
+				hits["teams"] = true
+				var p teamsPayload
+				json.NewDecoder(req.Body).Decode(&p)
+				if p.Type != "message" || len(p.Attachments) != 1 || p.Attachments[0].ContentType != "application/vnd.microsoft.card.adaptive" {
+					t.Errorf("Teams invalid message: %+v", p)
+				} else if body := p.Attachments[0].Content.Body; len(body) < 2 || body[0].Color != "Good" || len(body[1].Facts) != 3 || body[1].Facts[0].Value != "test-acct" {
+					t.Errorf("Teams invalid card: %+v", body)
+				}
+
+			} else if strings.Contains(url, "signal") {
+				hits["signal"] = true
+				if url != "http://signal.mock/v2/send" {
+					t.Errorf("Signal invalid URL %s", url)
+				}
+				var p signalPayload
+				json.NewDecoder(req.Body).Decode(&p)
+				if p.Number != "+15550000000" || len(p.Recipients) != 1 || p.TextMode != "styled" || !strings.Contains(p.Message, "**Account:** test-acct") {
+					t.Errorf("Signal invalid payload: %+v", p)
+				}
+
+			} else if strings.Contains(url, "graph.facebook.com") {
+				hits["whatsapp"] = true
+				if url != whatsappAPI+"/1234/messages" || req.Header.Get("Authorization") != "Bearer wa-token" {
+					t.Errorf("WhatsApp invalid request %s", url)
+				}
+				var p whatsappPayload
+				json.NewDecoder(req.Body).Decode(&p)
+				if p.To != "15552222222" || p.Type != "template" || p.Template.Name != "oci_instance_ready" ||
+					len(p.Template.Components) != 1 || len(p.Template.Components[0].Parameters) != 4 {
+					t.Errorf("WhatsApp invalid payload: %+v", p)
+				}
 			}
 
 			return &http.Response{
@@ -112,7 +155,7 @@ func TestNotifier_AllProviders(t *testing.T) {
 		t.Fatalf("SendSuccess failed: %v", err)
 	}
 
-	expected := []string{"discord", "telegram", "ntfy", "gotify"}
+	expected := []string{"discord", "telegram", "ntfy", "gotify", "teams", "signal", "whatsapp"}
 	for _, p := range expected {
 		if !hits[p] {
 			t.Errorf("Provider %s was not called", p)
@@ -165,6 +208,36 @@ func TestNotifier_SendDigest(t *testing.T) {
 	}
 }
 
+func TestNotifier_WebhookFormats(t *testing.T) {
+	for format, mention := range map[string]string{"mattermost": "@channel", "rocketchat": "@all"} {
+		n := New(config.NotificationConfig{WebhookURL: "http://chat.mock", WebhookFormat: format, InsistentPing: true})
+		var p chatPayload
+		n.Client.Transport = &mockTransport{
+			RoundTripFunc: func(req *http.Request) (*http.Response, error) {
+				json.NewDecoder(req.Body).Decode(&p)
+				return &http.Response{StatusCode: 200, Body: io.NopCloser(bytes.NewBufferString("{}"))}, nil
+			},
+		}
+		if err := n.SendSuccess("test-acct", "inst-1", "region-1"); err != nil {
+			t.Fatalf("%s: SendSuccess failed: %v", format, err)
+		}
+
+		if !strings.HasPrefix(p.Text, mention) || strings.Contains(p.Text, "@everyone") {
+			t.Errorf("%s: expected %s mention, got %q", format, mention, p.Text)
+		}
+		if len(p.Attachments) != 1 {
+			t.Fatalf("%s: expected 1 attachment, got %+v", format, p)
+		}
+		a := p.Attachments[0]
+		if a.Color != "#57F287" || a.Title != "✅ OCI Instance Launched Successfully" || len(a.Fields) != 3 || a.Fields[0].Value != "test-acct" || !a.Fields[0].Short {
+			t.Errorf("%s: invalid attachment %+v", format, a)
+		}
+		if footer := a.Footer + a.Text; !strings.HasPrefix(footer, "OCI ARM Provisioner") {
+			t.Errorf("%s: missing footer, got %+v", format, a)
+		}
+	}
+}
+
 // --- SendSuccessVerified Tests ---
 
 // mockVerifiedDetails implements VerifiedInstanceDetails interface for testing
@@ -253,7 +326,7 @@ func TestSendSuccessVerified_AllProviders(t *testing.T) {
 		region:     "us-ashburn-1",
 	}
 
-	if err := n.SendSuccessVerified("test-account", details); err != nil {
+	if _, err := n.SendSuccessVerified("test-account", details); err != nil {
 		t.Fatalf("SendSuccessVerified failed: %v", err)
 	}
 
@@ -273,8 +346,198 @@ func TestSendSuccessVerified_NilDetails(t *testing.T) {
 
 	n := New(cfg)
 
-	err := n.SendSuccessVerified("test", nil)
+	_, err := n.SendSuccessVerified("test", nil)
 	if err == nil {
 		t.Error("expected error for nil details")
 	}
 }
+
+func TestNotifier_SendGeneric(t *testing.T) {
+	cfg := config.NotificationConfig{
+		Enabled:        true,
+		WebhookURL:     "http://discord.mock",
+		TelegramToken:  "tg-token",
+		TelegramChatID: "tg-chat",
+		GotifyURL:      "http://gotify.mock",
+		GotifyToken:    "gotify-token",
+	}
+	n := New(cfg)
+	hits := make(map[string]bool)
+
+	n.Client.Transport = &mockTransport{
+		RoundTripFunc: func(req *http.Request) (*http.Response, error) {
+			url := req.URL.String()
+			if strings.Contains(url, "discord") {
+				hits["discord"] = true
+				var p discordPayload
+				json.NewDecoder(req.Body).Decode(&p)
+				if len(p.Embeds) == 0 || p.Embeds[0].Title != "⬆️ Update Available" || len(p.Embeds[0].Fields) != 3 {
+					t.Errorf("Discord embed mismatch: %+v", p.Embeds)
+				}
+			} else if strings.Contains(url, "telegram") {
+				hits["telegram"] = true
+				var p telegramPayload
+				json.NewDecoder(req.Body).Decode(&p)
+				if !strings.Contains(p.Text, "<b>Latest:</b> 0.3.0") {
+					t.Errorf("Telegram text mismatch: %s", p.Text)
+				}
+			} else if strings.Contains(url, "gotify") {
+				hits["gotify"] = true
+				var p gotifyPayload
+				json.NewDecoder(req.Body).Decode(&p)
+				if p.Priority != 6 {
+					t.Errorf("Gotify priority should double ntfy scale, got %d", p.Priority)
+				}
+			}
+			return &http.Response{StatusCode: 200, Body: io.NopCloser(bytes.NewBufferString("{}"))}, nil
+		},
+	}
+
+	if err := n.SendUpdateAvailable("0.2.1", "0.3.0", "https://example.com"); err != nil {
+		t.Fatalf("SendUpdateAvailable failed: %v", err)
+	}
+	for _, p := range []string{"discord", "telegram", "gotify"} {
+		if !hits[p] {
+			t.Errorf("Provider %s was not called", p)
+		}
+	}
+}
+
+func TestTracker_ADStreak(t *testing.T) {
+	tracker := NewTracker()
+	tracker.RecordCapacityIn("a", "AD-1")
+	tracker.RecordCapacityIn("a", "AD-1")
+	if got := tracker.ADStats("a")["AD-1"]; got.Streak != 2 || !got.LastLaunch.IsZero() {
+		t.Errorf("expected a streak of 2 capacity errors, got %+v", got)
+	}
+
+	tracker.RecordLaunchIn("a", "AD-1")
+	got := tracker.ADStats("a")["AD-1"]
+	if got.Streak != 0 || got.CapacityErrors != 2 || got.LastLaunch.IsZero() {
+		t.Errorf("expected a launch to end the streak, got %+v", got)
+	}
+	if tracker.ADStats("b") != nil {
+		t.Error("expected no stats for an unknown account")
+	}
+}
+
+func TestTracker_PerAccountStats(t *testing.T) {
+	tracker := NewTracker()
+	tracker.RecordAttempt("a")
+	tracker.RecordAttempt("a")
+	tracker.RecordCapacity("a")
+	tracker.RecordError("a", io.ErrUnexpectedEOF)
+	tracker.RecordAttemptIn("b", "AD-1")
+	tracker.RecordCapacityIn("b", "AD-1")
+	tracker.RecordAttemptIn("b", "AD-2")
+	tracker.RecordSuccess("b", "ocid1.instance.oc1..b", "1.2.3.4")
+	tracker.RecordVerification("b", Verification{RunningAfter: 41 * time.Second, PublicIPAfter: 43200 * time.Millisecond, SpecsMismatch: true})
+
+	stats := tracker.Snapshot()
+	if stats.CapacityErrors != 2 || stats.OtherErrors != 1 || stats.SuccessCount != 1 {
+		t.Errorf("global counters not updated: %+v", stats)
+	}
+	if got := stats.AccountNames(); len(got) != 2 || got[0] != "a" || got[1] != "b" {
+		t.Errorf("expected sorted names [a b], got %v", got)
+	}
+
+	a := stats.Accounts["a"].Summary()
+	if !strings.Contains(a, "2 attempts, 1 capacity hits, 1 errors") || !strings.Contains(a, "Last error: unexpected EOF") {
+		t.Errorf("unexpected summary for a: %q", a)
+	}
+	b := stats.Accounts["b"].Summary()
+	if !strings.Contains(b, "Provisioned: ocid1.instance.oc1..b (1.2.3.4)") || !strings.Contains(b, "Capacity hits by AD: AD-1 1/1, AD-2 0/1") ||
+		!strings.Contains(b, "Came up: RUNNING after 41s, public IP after 43s, specs mismatch") {
+		t.Errorf("unexpected summary for b: %q", b)
+	}
+
+	// Snapshots must not alias tracker state
+	tracker.RecordSuccess("b", "ocid1.instance.oc1..c", "")
+	if len(stats.Accounts["b"].Instances) != 1 {
+		t.Error("snapshot was mutated by later RecordSuccess")
+	}
+}
+
+func TestNotifier_AccountNotes(t *testing.T) {
+	n, sent := recordingNotifier(config.NotificationConfig{})
+	n.Notes = map[string]string{"family": "mum's account"}
+
+	n.Send(Message{Title: "Alert", Fields: []Field{{Name: "Account", Value: "family"}}})
+	n.Send(Message{Title: "Alert", Fields: []Field{{Name: "Account", Value: "work"}}})
+	receipt, err := n.SendSuccessVerified("family", &mockVerifiedDetails{instanceID: "ocid1.instance.test", state: "RUNNING"})
+	if err != nil {
+		t.Fatalf("SendSuccessVerified failed: %v", err)
+	}
+	if receipt.Account != "family" {
+		t.Errorf("expected the receipt to keep the bare account name, got %q", receipt.Account)
+	}
+
+	payloads := sent()
+	if len(payloads) != 3 {
+		t.Fatalf("expected 3 sends, got %d", len(payloads))
+	}
+	for i, want := range []string{"family (mum's account)", "work", "family (mum's account)"} {
+		if got := payloads[i].Embeds[0].Fields[0].Value; got != want {
+			t.Errorf("send %d: expected Account %q, got %q", i, want, got)
+		}
+	}
+}
+
+// mockOrdinalDetails is instance n of count.
+type mockOrdinalDetails struct {
+	mockVerifiedDetails
+	n, count int
+}
+
+func (m *mockOrdinalDetails) GetOrdinal() (int, int) { return m.n, m.count }
+
+func TestSendSuccessVerified_InstanceOrdinal(t *testing.T) {
+	n, sent := recordingNotifier(config.NotificationConfig{})
+	base := mockVerifiedDetails{instanceID: "ocid1.instance.test", state: "RUNNING"}
+	n.SendSuccessVerified("work", &mockOrdinalDetails{mockVerifiedDetails: base, n: 2, count: 2})
+	n.SendSuccessVerified("work", &mockOrdinalDetails{mockVerifiedDetails: base, n: 1, count: 1})
+
+	payloads := sent()
+	if len(payloads) != 2 {
+		t.Fatalf("expected 2 sends, got %d", len(payloads))
+	}
+	for i, want := range []string{"work (instance 2 of 2)", "work"} {
+		if got := payloads[i].Embeds[0].Fields[0].Value; got != want {
+			t.Errorf("send %d: expected Account %q, got %q", i, want, got)
+		}
+	}
+}
+
+func TestNotifier_ProviderStatus(t *testing.T) {
+	n := New(config.NotificationConfig{WebhookURL: "http://discord.mock", NtfyTopic: "topic"})
+	n.Client.Transport = &mockTransport{
+		RoundTripFunc: func(req *http.Request) (*http.Response, error) {
+			status := 200
+			if strings.Contains(req.URL.String(), "ntfy") {
+				status = 403
+			}
+			return &http.Response{StatusCode: status, Body: io.NopCloser(bytes.NewBufferString("{}"))}, nil
+		},
+	}
+
+	if st := n.Status(); len(st) != 2 || st[0].Provider != "webhook" || st[1].Provider != "ntfy" || st[0].Sends != 0 {
+		t.Fatalf("expected two unused providers, got %+v", st)
+	}
+	if err := n.SendTest("webhook"); err != nil {
+		t.Errorf("webhook test failed: %v", err)
+	}
+	if err := n.SendTest("ntfy"); err == nil {
+		t.Error("expected the ntfy test to fail")
+	}
+	if err := n.SendTest("pigeon"); err == nil {
+		t.Error("expected an error for an unknown provider")
+	}
+
+	st := n.Status()
+	if !st[0].OK || st[0].Sends != 1 || st[0].LastError != "" {
+		t.Errorf("unexpected webhook status %+v", st[0])
+	}
+	if st[1].OK || st[1].Sends != 1 || !strings.Contains(st[1].LastError, "403") {
+		t.Errorf("unexpected ntfy status %+v", st[1])
+	}
+}
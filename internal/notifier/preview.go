@@ -0,0 +1,259 @@
+package notifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yourusername/oci-arm-provisioner/internal/config"
+)
+
+// Placeholder endpoints used when previewing, so no secrets end up in the output.
+const (
+	previewWebhookURL  = "https://discord.example/api/webhooks/ID/TOKEN"
+	previewGotifyURL   = "https://gotify.example"
+	previewTeamsURL    = "https://example.webhook.office.com/webhookb2/ID"
+	previewSignalURL   = "https://signal-api.example"
+	previewPlaceholder = "<redacted>"
+)
+
+// previewTime is the fixed clock used for previews so rendered output is reproducible.
+var previewTime = time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC)
+
+// Request is a provider request captured instead of being sent.
+type Request struct {
+	Provider string
+	Method   string
+	URL      string
+	Header   http.Header
+	Body     []byte
+}
+
+// String renders the request as headers followed by the (indented, for JSON) body.
+func (r Request) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "### %s: %s %s\n", r.Provider, r.Method, r.URL)
+
+	keys := make([]string, 0, len(r.Header))
+	for k := range r.Header {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&b, "%s: %s\n", k, strings.Join(r.Header[k], ", "))
+	}
+	b.WriteString("\n")
+
+	var pretty bytes.Buffer
+	if json.Indent(&pretty, r.Body, "", "  ") == nil {
+		b.Write(pretty.Bytes())
+	} else {
+		b.Write(r.Body)
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// recorder is an http.RoundTripper that captures requests and answers 200 OK.
+type recorder struct {
+	mu       sync.Mutex
+	requests []Request
+}
+
+func (r *recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		body, _ = io.ReadAll(req.Body)
+		req.Body.Close()
+	}
+	r.mu.Lock()
+	r.requests = append(r.requests, Request{
+		Provider: providerFor(req),
+		Method:   req.Method,
+		URL:      req.URL.String(),
+		Header:   req.Header.Clone(),
+		Body:     body,
+	})
+	r.mu.Unlock()
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader("{}")),
+		Header:     make(http.Header),
+		Request:    req,
+	}, nil
+}
+
+func (r *recorder) take() []Request {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := r.requests
+	r.requests = nil
+	return out
+}
+
+func providerFor(req *http.Request) string {
+	switch {
+	case req.URL.Host == "api.telegram.org":
+		return "telegram"
+	case req.URL.Host == "ntfy.sh":
+		return "ntfy"
+	case strings.HasPrefix(req.URL.String(), previewGotifyURL):
+		return "gotify"
+	case strings.HasPrefix(req.URL.String(), previewTeamsURL):
+		return "teams"
+	case strings.HasPrefix(req.URL.String(), previewSignalURL):
+		return "signal"
+	case req.URL.Host == "graph.facebook.com":
+		return "whatsapp"
+	default:
+		return "webhook"
+	}
+}
+
+// Sample is a named example notification used by `notify preview` and the golden tests.
+type Sample struct {
+	Name string
+	Send func(n *Notifier) error
+}
+
+type sampleInstance struct{}
+
+func (sampleInstance) GetInstanceID() string { return "ocid1.instance.oc1.sa-saopaulo-1.example" }
+func (sampleInstance) GetPublicIP() string   { return "203.0.113.42" }
+func (sampleInstance) GetIPv6() string       { return "2001:db8::42" }
+func (sampleInstance) GetOCPUs() float32     { return 4 }
+func (sampleInstance) GetMemoryGB() float32  { return 24 }
+func (sampleInstance) GetState() string      { return "RUNNING" }
+func (sampleInstance) GetRegion() string     { return "sa-saopaulo-1" }
+func (sampleInstance) GetHostKeys() []string {
+	return []string{"ECDSA SHA256:8fQmZ1tV0nR3kYw5pXc2uLh6sGd9aJe4bTo7iNq1vWs", "ED25519 SHA256:Jr2xKb7YpE4mVt9cQw1nHs6uLz3aFg8dRo5iTy0kPe"}
+}
+
+func sampleStats() Stats {
+	return Stats{
+		StartTime:      previewTime.Add(-26 * time.Hour),
+		TotalCycles:    104,
+		CapacityErrors: 97,
+		OtherErrors:    2,
+		Accounts: map[string]AccountStats{
+			"personal": {Attempts: 52, CapacityErrors: 50},
+			"work":     {Attempts: 52, CapacityErrors: 47, OtherErrors: 2, LastError: "TooManyRequests"},
+		},
+		Costs: map[string]Cost{
+			"work": {Spent: 3.2, Projected: 7.85, Currency: "USD"},
+		},
+	}
+}
+
+// Samples lists one example of every notification kind.
+var Samples = []Sample{
+	{"success", func(n *Notifier) error {
+		_, err := n.SendSuccessVerified("personal", sampleInstance{})
+		return err
+	}},
+	{"alert", func(n *Notifier) error {
+		return n.deliver(Message{
+			Title: "⚠️ Circuit Breaker Open",
+			Fields: []Field{
+				{Name: "Reason", Value: "Too many consecutive API failures (last: Service <Unavailable>)"},
+				{Name: "Cooldown", Value: "30m0s"},
+			},
+			Color:    ColorError,
+			Priority: 4,
+			Tags:     "warning",
+		})
+	}},
+	{"auth_alert", func(n *Notifier) error {
+		return n.SendAlert(Alert{
+			Class:   ClassAuth,
+			Account: "work",
+			Detail:  "Service error:NotAuthenticated. The required information to complete authentication was not provided or was incorrect. http status code: 401",
+		})
+	}},
+	{"milestone", func(n *Notifier) error {
+		return n.SendAlert(Alert{
+			Class:   ClassCapacity,
+			Account: "personal",
+			Detail:  "7 days hunting, 312 capacity errors",
+			Fields: []Field{
+				{Name: "Attempts", Value: "1,008"},
+				{Name: "Capacity Errors", Value: "312"},
+				{Name: "Hunting For", Value: "168h2m0s"},
+			},
+		})
+	}},
+	{"digest", func(n *Notifier) error {
+		return n.SendDigest(sampleStats())
+	}},
+	{"exit_summary", func(n *Notifier) error {
+		return n.SendExitSummary(sampleStats())
+	}},
+}
+
+// TestSamples are what `notify test` really sends: a success and a digest with the
+// example values, at the current time.
+var TestSamples = []Sample{
+	Samples[0],
+	{"digest", func(n *Notifier) error {
+		stats := sampleStats()
+		stats.StartTime = n.clock().Add(-26 * time.Hour)
+		return n.SendDigest(stats)
+	}},
+}
+
+// NewPreview returns a Notifier that renders messages for the providers enabled in cfg
+// (or all of them if none are) and captures the requests instead of sending them.
+// Credentials are replaced by placeholders and the clock is fixed.
+func NewPreview(cfg config.NotificationConfig) *Notifier {
+	none := cfg.WebhookURL == "" && cfg.TelegramToken == "" && cfg.NtfyTopic == "" && cfg.GotifyURL == "" && cfg.TeamsWebhookURL == "" && cfg.SignalURL == "" && cfg.WhatsAppToken == ""
+	if none || cfg.WebhookURL != "" {
+		cfg.WebhookURL = previewWebhookURL
+	}
+	if none || cfg.TelegramToken != "" {
+		cfg.TelegramToken, cfg.TelegramChatID = previewPlaceholder, previewPlaceholder
+	}
+	if none || cfg.NtfyTopic != "" {
+		cfg.NtfyTopic = previewPlaceholder
+	}
+	if none || cfg.GotifyURL != "" {
+		cfg.GotifyURL, cfg.GotifyToken = previewGotifyURL, previewPlaceholder
+	}
+	if none || cfg.TeamsWebhookURL != "" {
+		cfg.TeamsWebhookURL = previewTeamsURL
+	}
+	if none || cfg.SignalURL != "" {
+		cfg.SignalURL, cfg.SignalNumber, cfg.SignalRecipients = previewSignalURL, previewPlaceholder, []string{previewPlaceholder}
+	}
+	if none || cfg.WhatsAppToken != "" {
+		cfg.WhatsAppToken, cfg.WhatsAppPhoneNumberID, cfg.WhatsAppRecipient = previewPlaceholder, "PHONE_NUMBER_ID", previewPlaceholder
+		if cfg.WhatsAppTemplate == "" {
+			cfg.WhatsAppTemplate, cfg.WhatsAppLanguage = "oci_instance_ready", "en_US"
+		}
+	}
+	cfg.Enabled = true
+	cfg.BatchWindow, cfg.RateLimitPerMinute = "", 0
+
+	n := New(cfg)
+	n.Client.Transport = &recorder{}
+	n.now = func() time.Time { return previewTime }
+	return n
+}
+
+// Render sends sample through a preview Notifier and returns the captured requests.
+func (n *Notifier) Render(sample Sample) ([]Request, error) {
+	rec, ok := n.Client.Transport.(*recorder)
+	if !ok {
+		return nil, fmt.Errorf("notifier is not a preview")
+	}
+	rec.take()
+	if err := sample.Send(n); err != nil {
+		return nil, err
+	}
+	return rec.take(), nil
+}
@@ -0,0 +1,71 @@
+package notifier
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/oci-arm-provisioner/internal/config"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files in testdata/")
+
+// TestGoldenPayloads pins the rendered payload of every notification kind for every
+// provider. Run `go test ./internal/notifier -update` after an intended format change
+// and review the diff.
+func TestGoldenPayloads(t *testing.T) {
+	n := NewPreview(config.NotificationConfig{})
+	for _, sample := range Samples {
+		t.Run(sample.Name, func(t *testing.T) {
+			reqs, err := n.Render(sample)
+			if err != nil {
+				t.Fatalf("render failed: %v", err)
+			}
+			wantReqs := 6
+			if sample.Name == "success" {
+				wantReqs++ // WhatsApp only sends the success template.
+			}
+			if len(reqs) != wantReqs {
+				t.Fatalf("expected %d provider requests, got %d", wantReqs, len(reqs))
+			}
+			var b strings.Builder
+			for _, r := range reqs {
+				b.WriteString(r.String())
+				b.WriteString("\n")
+			}
+
+			golden := filepath.Join("testdata", sample.Name+".golden")
+			if *update {
+				if err := os.MkdirAll("testdata", 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(golden, []byte(b.String()), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("missing golden file (run with -update): %v", err)
+			}
+			if b.String() != string(want) {
+				t.Errorf("%s payloads changed; run with -update and review the diff.\ngot:\n%s", sample.Name, b.String())
+			}
+		})
+	}
+}
+
+func TestNewPreview_OnlyConfiguredProviders(t *testing.T) {
+	n := NewPreview(config.NotificationConfig{NtfyTopic: "secret-topic"})
+	reqs, err := n.Render(Samples[0])
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+	if len(reqs) != 1 || reqs[0].Provider != "ntfy" {
+		t.Fatalf("expected a single ntfy request, got %v", reqs)
+	}
+	if strings.Contains(reqs[0].URL, "secret-topic") {
+		t.Error("expected the topic to be redacted")
+	}
+}
@@ -0,0 +1,32 @@
+package notifier
+
+import "strings"
+
+// Signal messages go through a signal-cli-rest-api server
+// (https://github.com/bbernhard/signal-cli-rest-api) linked to a Signal number.
+type signalPayload struct {
+	Message    string   `json:"message"`
+	Number     string   `json:"number"`
+	Recipients []string `json:"recipients"`
+	TextMode   string   `json:"text_mode"` // "styled" renders **bold** and `mono`.
+}
+
+// signalEnabled reports whether the server, sender and at least one recipient are set.
+func (n *Notifier) signalEnabled() bool {
+	return n.Config.SignalURL != "" && n.Config.SignalNumber != "" && len(n.Config.SignalRecipients) > 0
+}
+
+// sendSignal sends a styled message with a bold title line.
+func (n *Notifier) sendSignal(title, message string) (err error) {
+	if !n.signalEnabled() {
+		return nil
+	}
+	defer func() { n.status.record("signal", err, n.clock()) }()
+	payload := signalPayload{
+		Message:    "**" + title + "**\n\n" + message,
+		Number:     n.Config.SignalNumber,
+		Recipients: n.Config.SignalRecipients,
+		TextMode:   "styled",
+	}
+	return n.postJSON(strings.TrimRight(n.Config.SignalURL, "/")+"/v2/send", payload, nil)
+}
@@ -1,8 +1,15 @@
 package notifier
 
 import (
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
 	"sync"
 	"time"
+
+	"github.com/yourusername/oci-arm-provisioner/internal/errorclass"
 )
 
 type Tracker struct {
@@ -13,11 +20,77 @@ type Tracker struct {
 	OtherErrors     int
 	SuccessCount    int
 	LastSuccessTime time.Time
+	accounts        map[string]*AccountStats
+}
+
+// AccountStats holds the per-account counters reported in the exit summary.
+type AccountStats struct {
+	Attempts       int
+	LastAttempt    time.Time
+	CapacityErrors int
+	OtherErrors    int
+	ErrorClasses   map[errorclass.Class]int // OtherErrors by class.
+	LastError      string
+	Limited        string             // Service limits used up that hold launches back; empty when none.
+	Instances      []InstanceRecord   // Instances provisioned during this run.
+	ADs            map[string]ADStats // Attempts by availability domain, when known.
+	Verifications  []Verification     // How the instances of this run came up.
+}
+
+// Verification is how long a launched instance took to come up.
+type Verification struct {
+	RunningAfter  time.Duration // 0 if it never reached RUNNING.
+	PublicIPAfter time.Duration // 0 if no public IP was seen.
+	SpecsMismatch bool
+}
+
+// String formats v for summaries, e.g. "RUNNING after 41s, public IP after 43s".
+func (v Verification) String() string {
+	parts := []string{"never RUNNING"}
+	if v.RunningAfter > 0 {
+		parts[0] = fmt.Sprintf("RUNNING after %v", v.RunningAfter.Round(time.Second))
+	}
+	if v.PublicIPAfter > 0 {
+		parts = append(parts, fmt.Sprintf("public IP after %v", v.PublicIPAfter.Round(time.Second)))
+	} else {
+		parts = append(parts, "no public IP")
+	}
+	if v.SpecsMismatch {
+		parts = append(parts, "specs mismatch")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ADStats counts the launch attempts of an account in one availability domain.
+type ADStats struct {
+	Attempts       int
+	CapacityErrors int
+	Streak         int       // Capacity errors in a row since the last launch there.
+	LastLaunch     time.Time // Last launch OCI accepted there.
+}
+
+// Cost is the spend of a pay-as-you-go tenancy for the current month.
+type Cost struct {
+	Spent     float64 // Month to date.
+	Projected float64 // Spent plus the Usage API forecast for the rest of the month.
+	Currency  string
+}
+
+// String formats the cost for messages, e.g. "12.34 USD (projected 30.00)".
+func (c Cost) String() string {
+	return fmt.Sprintf("%.2f %s (projected %.2f)", c.Spent, c.Currency, c.Projected)
+}
+
+// InstanceRecord identifies an instance launched during this run.
+type InstanceRecord struct {
+	ID       string
+	PublicIP string
 }
 
 func NewTracker() *Tracker {
 	return &Tracker{
 		StartTime: time.Now(),
+		accounts:  make(map[string]*AccountStats),
 	}
 }
 
@@ -46,9 +119,143 @@ func (t *Tracker) IncSuccess() {
 	t.LastSuccessTime = time.Now()
 }
 
+// account returns the stats entry for name, creating it if needed. Caller holds mu.
+func (t *Tracker) account(name string) *AccountStats {
+	if t.accounts == nil {
+		t.accounts = make(map[string]*AccountStats)
+	}
+	acc, ok := t.accounts[name]
+	if !ok {
+		acc = &AccountStats{}
+		t.accounts[name] = acc
+	}
+	return acc
+}
+
+// RecordAttempt counts a launch attempt for an account.
+func (t *Tracker) RecordAttempt(account string) {
+	t.RecordAttemptIn(account, "")
+}
+
+// RecordAttemptIn counts a launch attempt for an account in an availability domain.
+func (t *Tracker) RecordAttemptIn(account, ad string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	acc := t.account(account)
+	acc.Attempts++
+	acc.LastAttempt = time.Now()
+	acc.recordAD(ad, func(s *ADStats) { s.Attempts++ })
+}
+
+// RecordCapacity counts an "Out of host capacity" response for an account.
+func (t *Tracker) RecordCapacity(account string) {
+	t.RecordCapacityIn(account, "")
+}
+
+// RecordCapacityIn counts an "Out of host capacity" response for an account in an
+// availability domain.
+func (t *Tracker) RecordCapacityIn(account, ad string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.CapacityErrors++
+	acc := t.account(account)
+	acc.CapacityErrors++
+	acc.recordAD(ad, func(s *ADStats) {
+		s.CapacityErrors++
+		s.Streak++
+	})
+}
+
+// RecordLaunchIn notes that OCI accepted a launch of an account in an availability
+// domain, which ends its streak of capacity errors there.
+func (t *Tracker) RecordLaunchIn(account, ad string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.account(account).recordAD(ad, func(s *ADStats) {
+		s.Streak = 0
+		s.LastLaunch = time.Now()
+	})
+}
+
+// ADStats returns the per-AD stats of an account.
+func (t *Tracker) ADStats(account string) map[string]ADStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if acc, ok := t.accounts[account]; ok {
+		return maps.Clone(acc.ADs)
+	}
+	return nil
+}
+
+// recordAD updates the stats of an AD; a blank AD is not tracked. Caller holds mu.
+func (a *AccountStats) recordAD(ad string, update func(*ADStats)) {
+	if ad == "" {
+		return
+	}
+	if a.ADs == nil {
+		a.ADs = make(map[string]ADStats)
+	}
+	s := a.ADs[ad]
+	update(&s)
+	a.ADs[ad] = s
+}
+
+// RecordError counts a non-capacity failure for an account and remembers it.
+func (t *Tracker) RecordError(account string, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.OtherErrors++
+	acc := t.account(account)
+	acc.OtherErrors++
+	if err != nil {
+		acc.LastError = err.Error()
+		if acc.ErrorClasses == nil {
+			acc.ErrorClasses = make(map[errorclass.Class]int)
+		}
+		acc.ErrorClasses[errorclass.Of(err)]++
+	}
+}
+
+// SetLimited records that an account waits for exhausted service limits, described by
+// detail, or that it no longer does when detail is empty.
+func (t *Tracker) SetLimited(account, detail string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.account(account).Limited = detail
+}
+
+// RecordSuccess counts a provisioned instance for an account.
+func (t *Tracker) RecordSuccess(account, instanceID, publicIP string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.SuccessCount++
+	t.LastSuccessTime = time.Now()
+	acc := t.account(account)
+	acc.Instances = append(acc.Instances, InstanceRecord{ID: instanceID, PublicIP: publicIP})
+}
+
+// RecordVerification counts how a launched instance of an account came up.
+func (t *Tracker) RecordVerification(account string, v Verification) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	acc := t.account(account)
+	acc.Verifications = append(acc.Verifications, v)
+}
+
 func (t *Tracker) Snapshot() Stats {
 	t.mu.Lock()
 	defer t.mu.Unlock()
+
+	accounts := make(map[string]AccountStats, len(t.accounts))
+	for name, acc := range t.accounts {
+		cp := *acc
+		cp.Instances = append([]InstanceRecord(nil), acc.Instances...)
+		cp.ADs = maps.Clone(acc.ADs)
+		cp.ErrorClasses = maps.Clone(acc.ErrorClasses)
+		cp.Verifications = slices.Clone(acc.Verifications)
+		accounts[name] = cp
+	}
+
 	return Stats{
 		StartTime:       t.StartTime,
 		TotalCycles:     t.TotalCycles,
@@ -56,5 +263,50 @@ func (t *Tracker) Snapshot() Stats {
 		OtherErrors:     t.OtherErrors,
 		SuccessCount:    t.SuccessCount,
 		LastSuccessTime: t.LastSuccessTime,
+		Accounts:        accounts,
+	}
+}
+
+// AccountNames returns the accounts with recorded activity, sorted.
+func (s Stats) AccountNames() []string {
+	names := make([]string, 0, len(s.Accounts))
+	for name := range s.Accounts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Summary renders a one-paragraph description of an account's activity.
+func (a AccountStats) Summary() string {
+	lines := []string{fmt.Sprintf("%d attempts, %d capacity hits, %d errors", a.Attempts, a.CapacityErrors, a.OtherErrors)}
+	for _, inst := range a.Instances {
+		ip := inst.PublicIP
+		if ip == "" {
+			ip = "no public IP"
+		}
+		lines = append(lines, fmt.Sprintf("Provisioned: %s (%s)", inst.ID, ip))
+	}
+	for _, v := range a.Verifications {
+		lines = append(lines, "Came up: "+v.String())
+	}
+	if len(a.ADs) > 1 {
+		ads := slices.Sorted(maps.Keys(a.ADs))
+		for i, ad := range ads {
+			ads[i] = fmt.Sprintf("%s %d/%d", ad, a.ADs[ad].CapacityErrors, a.ADs[ad].Attempts)
+		}
+		lines = append(lines, "Capacity hits by AD: "+strings.Join(ads, ", "))
+	}
+	if len(a.ErrorClasses) > 0 {
+		classes := slices.Sorted(maps.Keys(a.ErrorClasses))
+		parts := make([]string, len(classes))
+		for i, c := range classes {
+			parts[i] = fmt.Sprintf("%s %d", c, a.ErrorClasses[c])
+		}
+		lines = append(lines, "Errors by kind: "+strings.Join(parts, ", "))
+	}
+	if a.LastError != "" {
+		lines = append(lines, "Last error: "+a.LastError)
 	}
+	return strings.Join(lines, "\n")
 }
@@ -0,0 +1,144 @@
+package notifier
+
+import (
+	"fmt"
+	"html"
+	"sync"
+	"time"
+)
+
+// ProviderStatus is what happened to the messages sent to one provider during this run.
+type ProviderStatus struct {
+	Provider  string
+	Sends     int       // Attempts, successful or not.
+	LastSent  time.Time // Last attempt; zero before the first.
+	OK        bool      // The last attempt was accepted.
+	LastError string    // Most recent failure, kept after later successes.
+	ErrorAt   time.Time
+}
+
+// providerStatuses records the outcome of every send, by provider.
+type providerStatuses struct {
+	mu sync.Mutex
+	m  map[string]ProviderStatus
+}
+
+// record stores the outcome of a send to provider.
+func (s *providerStatuses) record(provider string, err error, at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.m == nil {
+		s.m = make(map[string]ProviderStatus)
+	}
+	st := s.m[provider]
+	st.Provider = provider
+	st.Sends++
+	st.LastSent, st.OK = at, err == nil
+	if err != nil {
+		st.LastError, st.ErrorAt = err.Error(), at
+	}
+	s.m[provider] = st
+}
+
+// ProviderNames are all providers, in delivery order.
+var ProviderNames = []string{"webhook", "telegram", "ntfy", "gotify", "teams", "signal", "whatsapp"}
+
+// Only returns a Notifier with this one's settings and notes that sends to provider
+// alone, straight away: without batching or rate limiting.
+func (n *Notifier) Only(provider string) *Notifier {
+	cfg := n.Config
+	if provider != "webhook" {
+		cfg.WebhookURL = ""
+	}
+	if provider != "telegram" {
+		cfg.TelegramToken = ""
+	}
+	if provider != "ntfy" {
+		cfg.NtfyTopic = ""
+	}
+	if provider != "gotify" {
+		cfg.GotifyURL = ""
+	}
+	if provider != "teams" {
+		cfg.TeamsWebhookURL = ""
+	}
+	if provider != "signal" {
+		cfg.SignalURL = ""
+	}
+	if provider != "whatsapp" {
+		cfg.WhatsAppToken = ""
+	}
+	cfg.BatchWindow, cfg.RateLimitPerMinute = "", 0
+
+	only := New(cfg)
+	only.Notes = n.Notes
+	only.Client = n.Client
+	return only
+}
+
+// Providers returns the enabled providers, in delivery order.
+func (n *Notifier) Providers() []string {
+	var providers []string
+	for _, p := range []struct {
+		name    string
+		enabled bool
+	}{
+		{"webhook", n.Config.WebhookURL != ""},
+		{"telegram", n.Config.TelegramToken != "" && n.Config.TelegramChatID != ""},
+		{"ntfy", n.Config.NtfyTopic != ""},
+		{"gotify", n.Config.GotifyURL != "" && n.Config.GotifyToken != ""},
+		{"teams", n.Config.TeamsWebhookURL != ""},
+		{"signal", n.signalEnabled()},
+		{"whatsapp", n.whatsappEnabled()},
+	} {
+		if p.enabled {
+			providers = append(providers, p.name)
+		}
+	}
+	return providers
+}
+
+// Status returns the send history of every enabled provider, in delivery order.
+// Providers nothing was sent to yet have only their name set.
+func (n *Notifier) Status() []ProviderStatus {
+	n.status.mu.Lock()
+	defer n.status.mu.Unlock()
+	var statuses []ProviderStatus
+	for _, p := range n.Providers() {
+		st := n.status.m[p]
+		st.Provider = p
+		statuses = append(statuses, st)
+	}
+	return statuses
+}
+
+// SendTest sends a short test message to a single provider, bypassing batching and
+// throttling. WhatsApp gets its success template filled with placeholders.
+func (n *Notifier) SendTest(provider string) error {
+	title := "🔔 Test Notification"
+	text := fmt.Sprintf("If you can read this, %s notifications from OCI ARM Provisioner work.", provider)
+	stamp := footerText() + " • " + n.clock().Format("2006-01-02 15:04:05")
+
+	switch provider {
+	case "webhook":
+		return n.sendWebhook(discordPayload{Embeds: []discordEmbed{{
+			Title:  title,
+			Color:  ColorInfo,
+			Fields: []field{{Name: "Status", Value: text}},
+			Footer: &footer{Text: stamp},
+		}}})
+	case "telegram":
+		return n.sendTelegram("<b>" + html.EscapeString(title) + "</b>\n\n" + html.EscapeString(text))
+	case "ntfy":
+		return n.sendNtfy(text, title, 3, "bell", "")
+	case "gotify":
+		return n.sendGotify(text, title, 6)
+	case "teams":
+		return n.sendTeams(teamsMessage(title, ColorInfo, []Field{{Name: "Status", Value: text}}, stamp))
+	case "signal":
+		return n.sendSignal(title, text)
+	case "whatsapp":
+		return n.sendWhatsApp("test", "test-region", "0.0.0.0", "test-instance")
+	}
+	return fmt.Errorf("unknown provider '%s'", provider)
+}
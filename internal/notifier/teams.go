@@ -0,0 +1,81 @@
+package notifier
+
+// Microsoft Teams incoming webhooks (and Power Automate "post to a channel when a webhook
+// request is received" workflows) take an Adaptive Card wrapped in a message.
+type teamsPayload struct {
+	Type        string            `json:"type"`
+	Attachments []teamsAttachment `json:"attachments"`
+}
+
+type teamsAttachment struct {
+	ContentType string    `json:"contentType"`
+	Content     teamsCard `json:"content"`
+}
+
+type teamsCard struct {
+	Schema  string            `json:"$schema"`
+	Type    string            `json:"type"`
+	Version string            `json:"version"`
+	Body    []teamsElement    `json:"body"`
+	MSTeams map[string]string `json:"msteams,omitempty"`
+}
+
+// teamsElement is a TextBlock or a FactSet.
+type teamsElement struct {
+	Type     string      `json:"type"`
+	Text     string      `json:"text,omitempty"`
+	Weight   string      `json:"weight,omitempty"`
+	Size     string      `json:"size,omitempty"`
+	Color    string      `json:"color,omitempty"`
+	IsSubtle bool        `json:"isSubtle,omitempty"`
+	Wrap     bool        `json:"wrap,omitempty"`
+	Facts    []teamsFact `json:"facts,omitempty"`
+}
+
+type teamsFact struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+// teamsColors maps the embed colors to Adaptive Card text colors.
+var teamsColors = map[int]string{
+	ColorSuccess: "Good",
+	ColorError:   "Attention",
+	ColorInfo:    "Accent",
+}
+
+// teamsMessage renders a title, facts and a footer as an Adaptive Card.
+func teamsMessage(title string, color int, fields []Field, footer string) teamsPayload {
+	facts := make([]teamsFact, 0, len(fields))
+	for _, f := range fields {
+		facts = append(facts, teamsFact{Title: f.Name, Value: f.Value})
+	}
+	body := []teamsElement{
+		{Type: "TextBlock", Text: title, Weight: "Bolder", Size: "Medium", Color: teamsColors[color], Wrap: true},
+		{Type: "FactSet", Facts: facts},
+	}
+	if footer != "" {
+		body = append(body, teamsElement{Type: "TextBlock", Text: footer, Size: "Small", IsSubtle: true, Wrap: true})
+	}
+	return teamsPayload{
+		Type: "message",
+		Attachments: []teamsAttachment{{
+			ContentType: "application/vnd.microsoft.card.adaptive",
+			Content: teamsCard{
+				Schema:  "http://adaptivecards.io/schemas/adaptive-card.json",
+				Type:    "AdaptiveCard",
+				Version: "1.4",
+				Body:    body,
+				MSTeams: map[string]string{"width": "Full"},
+			},
+		}},
+	}
+}
+
+func (n *Notifier) sendTeams(payload teamsPayload) (err error) {
+	if n.Config.TeamsWebhookURL == "" {
+		return nil
+	}
+	defer func() { n.status.record("teams", err, n.clock()) }()
+	return n.postJSON(n.Config.TeamsWebhookURL, payload, nil)
+}
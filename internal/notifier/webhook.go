@@ -0,0 +1,59 @@
+package notifier
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Mattermost and Rocket.Chat incoming webhooks ignore Discord embeds; they take
+// Slack-style attachments instead (notifications.webhook_format).
+type chatPayload struct {
+	Text        string           `json:"text,omitempty"`
+	Attachments []chatAttachment `json:"attachments,omitempty"`
+}
+
+type chatAttachment struct {
+	Fallback string      `json:"fallback,omitempty"`
+	Color    string      `json:"color"`
+	Title    string      `json:"title"`
+	Text     string      `json:"text,omitempty"`
+	Fields   []chatField `json:"fields,omitempty"`
+	Footer   string      `json:"footer,omitempty"`
+}
+
+type chatField struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+	Short bool   `json:"short"`
+}
+
+// chatMentions is what each format uses to notify the whole channel instead of @everyone.
+var chatMentions = map[string]string{
+	"mattermost": "@channel",
+	"rocketchat": "@all",
+}
+
+// chatMessage converts a Discord payload for a Mattermost or Rocket.Chat webhook.
+func chatMessage(p discordPayload, format string) chatPayload {
+	out := chatPayload{Text: strings.ReplaceAll(p.Content, "@everyone", chatMentions[format])}
+	for _, e := range p.Embeds {
+		a := chatAttachment{
+			Fallback: e.Title,
+			Color:    fmt.Sprintf("#%06X", e.Color),
+			Title:    e.Title,
+		}
+		for _, f := range e.Fields {
+			a.Fields = append(a.Fields, chatField{Title: f.Name, Value: f.Value, Short: f.Inline})
+		}
+		if e.Footer != nil {
+			// Rocket.Chat attachments have no footer
+			if format == "rocketchat" {
+				a.Text = e.Footer.Text
+			} else {
+				a.Footer = e.Footer.Text
+			}
+		}
+		out.Attachments = append(out.Attachments, a)
+	}
+	return out
+}
@@ -0,0 +1,65 @@
+package notifier
+
+import "fmt"
+
+// whatsappAPI is the WhatsApp Business Cloud API base URL.
+const whatsappAPI = "https://graph.facebook.com/v21.0"
+
+// WhatsApp only lets a business start a conversation with a pre-approved template, so the
+// success message is sent as one. The template (whatsapp_template) must have a body with
+// four variables: {{1}} account, {{2}} region, {{3}} public IP and {{4}} instance ID.
+type whatsappPayload struct {
+	MessagingProduct string           `json:"messaging_product"`
+	To               string           `json:"to"`
+	Type             string           `json:"type"`
+	Template         whatsappTemplate `json:"template"`
+}
+
+type whatsappTemplate struct {
+	Name       string              `json:"name"`
+	Language   whatsappLanguage    `json:"language"`
+	Components []whatsappComponent `json:"components"`
+}
+
+type whatsappLanguage struct {
+	Code string `json:"code"`
+}
+
+type whatsappComponent struct {
+	Type       string              `json:"type"`
+	Parameters []whatsappParameter `json:"parameters"`
+}
+
+type whatsappParameter struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// whatsappEnabled reports whether the token, sender phone number ID and recipient are set.
+func (n *Notifier) whatsappEnabled() bool {
+	return n.Config.WhatsAppToken != "" && n.Config.WhatsAppPhoneNumberID != "" && n.Config.WhatsAppRecipient != ""
+}
+
+// sendWhatsApp sends the success template filled with the given variables, in order.
+func (n *Notifier) sendWhatsApp(vars ...string) (err error) {
+	if !n.whatsappEnabled() {
+		return nil
+	}
+	defer func() { n.status.record("whatsapp", err, n.clock()) }()
+	params := make([]whatsappParameter, 0, len(vars))
+	for _, v := range vars {
+		params = append(params, whatsappParameter{Type: "text", Text: v})
+	}
+	payload := whatsappPayload{
+		MessagingProduct: "whatsapp",
+		To:               n.Config.WhatsAppRecipient,
+		Type:             "template",
+		Template: whatsappTemplate{
+			Name:       n.Config.WhatsAppTemplate,
+			Language:   whatsappLanguage{Code: n.Config.WhatsAppLanguage},
+			Components: []whatsappComponent{{Type: "body", Parameters: params}},
+		},
+	}
+	url := fmt.Sprintf("%s/%s/messages", whatsappAPI, n.Config.WhatsAppPhoneNumberID)
+	return n.postJSON(url, payload, map[string]string{"Authorization": "Bearer " + n.Config.WhatsAppToken})
+}
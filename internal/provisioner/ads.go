@@ -0,0 +1,62 @@
+package provisioner
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/identity"
+)
+
+// launchADs returns the availability domains to try this cycle: the configured one or,
+// with "auto", the region's ADs by preferADs, starting from the next one in turn (only
+// the first without try_all_ads or sweep). Right after a merge-up it is the AD the
+// capacity report found room in.
+func (w *AccountWorker) launchADs(ctx context.Context) ([]string, error) {
+	if ad := w.mergeAD; ad != "" {
+		w.mergeAD = ""
+		return []string{ad}, nil
+	}
+	if w.Config.AvailabilityDomain != "auto" {
+		return []string{w.Config.AvailabilityDomain}, nil
+	}
+	req := identity.ListAvailabilityDomainsRequest{
+		CompartmentId: common.String(w.Config.TenancyOCID),
+	}
+	resp, err := w.IdentityClient.ListAvailabilityDomains(ctx, req)
+	w.observe(err)
+	if err != nil {
+		w.Tracker.RecordError(w.AccountName, err)
+		return nil, fmt.Errorf("failed to list ADs: %w", err)
+	}
+	if len(resp.Items) == 0 {
+		return nil, fmt.Errorf("no ADs found")
+	}
+
+	start := w.adIndex % len(resp.Items)
+	w.adIndex++
+	var ads []string
+	for i := range resp.Items {
+		ads = append(ads, *resp.Items[(start+i)%len(resp.Items)].Name)
+	}
+	ads = w.preferADs(ads)
+	if !w.Config.TryAllADs && !w.Config.Sweep.Enabled {
+		ads = ads[:1]
+	}
+	return ads, nil
+}
+
+// preferADs orders ads by their recent results: the fewest capacity errors in a row
+// first, then the most recent launch. Ties keep the rotation order.
+func (w *AccountWorker) preferADs(ads []string) []string {
+	stats := w.Tracker.ADStats(w.AccountName)
+	slices.SortStableFunc(ads, func(a, b string) int {
+		if c := cmp.Compare(stats[a].Streak, stats[b].Streak); c != 0 {
+			return c
+		}
+		return stats[b].LastLaunch.Compare(stats[a].LastLaunch)
+	})
+	return ads
+}
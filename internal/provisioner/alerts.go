@@ -0,0 +1,40 @@
+package provisioner
+
+import (
+	"fmt"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/yourusername/oci-arm-provisioner/internal/errorclass"
+	"github.com/yourusername/oci-arm-provisioner/internal/notifier"
+)
+
+// credentialsError wraps local credential problems (missing or unreadable key file) so
+// they are alerted like OCI authentication failures.
+type credentialsError struct{ err error }
+
+func (e credentialsError) Error() string                { return e.err.Error() }
+func (e credentialsError) Unwrap() error                { return e.err }
+func (e credentialsError) ErrorClass() errorclass.Class { return errorclass.Auth }
+
+// isAuthError reports whether err means the account's credentials are not accepted.
+func isAuthError(err error) bool {
+	return errorclass.Of(err) == errorclass.Auth
+}
+
+// reportAuth sends a (throttled) alert when the account can't authenticate, and a
+// recovery message once OCI answers one of its requests again.
+func (w *AccountWorker) reportAuth(err error) {
+	if isAuthError(err) {
+		if nerr := w.Notifier.SendAlert(notifier.Alert{Class: notifier.ClassAuth, Account: w.AccountName, Detail: err.Error(), Fields: remedyFields(err)}); nerr != nil {
+			w.Logger.Error(w.AccountName, fmt.Sprintf("Notification failed: %v", nerr))
+		}
+		return
+	}
+	// Only a response from OCI proves the credentials work; network errors prove nothing.
+	if _, ok := common.IsServiceError(err); err != nil && !ok {
+		return
+	}
+	if nerr := w.Notifier.ResolveAlert(notifier.ClassAuth, w.AccountName); nerr != nil {
+		w.Logger.Error(w.AccountName, fmt.Sprintf("Notification failed: %v", nerr))
+	}
+}
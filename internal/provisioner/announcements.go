@@ -0,0 +1,110 @@
+package provisioner
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/oracle/oci-go-sdk/v65/announcementsservice"
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/yourusername/oci-arm-provisioner/internal/notifier"
+)
+
+// announcementKeywords flag notices that threaten free-tier resources even when
+// OCI files them as plain information.
+var announcementKeywords = []string{
+	"verify", "verification", "idle", "reclaim", "always free", "free tier",
+	"trial", "suspend", "terminat",
+}
+
+// isRelevantAnnouncement reports whether an announcement needs the user's attention.
+func isRelevantAnnouncement(a announcementsservice.AnnouncementSummary) bool {
+	switch a.AnnouncementType {
+	case announcementsservice.BaseAnnouncementAnnouncementTypeActionRequired,
+		announcementsservice.BaseAnnouncementAnnouncementTypeActionRecommended:
+		return true
+	}
+	summary := strings.ToLower(safeString(a.Summary))
+	for _, kw := range announcementKeywords {
+		if strings.Contains(summary, kw) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckAnnouncements polls the Announcements API for active tenancy notices and
+// alerts once per announcement. It runs at most once per AnnouncementInterval.
+func (w *AccountWorker) CheckAnnouncements(ctx context.Context) error {
+	if w.AnnouncementInterval <= 0 || time.Since(w.lastAnnouncementCheck) < w.AnnouncementInterval {
+		return nil
+	}
+	w.lastAnnouncementCheck = time.Now()
+
+	if err := w.initClients(); err != nil {
+		return err
+	}
+	if w.AnnouncementClient == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	req := announcementsservice.ListAnnouncementsRequest{
+		CompartmentId:  common.String(w.Config.TenancyOCID),
+		LifecycleState: announcementsservice.ListAnnouncementsLifecycleStateActive,
+	}
+	for {
+		resp, err := w.AnnouncementClient.ListAnnouncements(ctx, req)
+		if err != nil {
+			return err
+		}
+		for _, a := range resp.Items {
+			if !isRelevantAnnouncement(a) {
+				continue
+			}
+			isNew, err := w.State.MarkAnnouncement(w.AccountName, safeString(a.Id))
+			if err != nil {
+				w.Logger.Warn("STATE", fmt.Sprintf("Failed to persist announcement: %v", err))
+			}
+			if isNew {
+				w.alertAnnouncement(a)
+			}
+		}
+		if resp.OpcNextPage == nil {
+			return nil
+		}
+		req.Page = resp.OpcNextPage
+	}
+}
+
+func (w *AccountWorker) alertAnnouncement(a announcementsservice.AnnouncementSummary) {
+	summary := safeString(a.Summary)
+	w.Logger.Warn(w.AccountName, fmt.Sprintf("📢 OCI announcement (%s): %s", a.AnnouncementType, summary))
+
+	color := notifier.ColorInfo
+	if a.AnnouncementType == announcementsservice.BaseAnnouncementAnnouncementTypeActionRequired {
+		color = notifier.ColorError
+	}
+	fields := []notifier.Field{
+		{Name: "Account", Value: w.AccountName},
+		{Name: "Type", Value: string(a.AnnouncementType)},
+		{Name: "Summary", Value: summary},
+	}
+	if len(a.AffectedRegions) > 0 {
+		fields = append(fields, notifier.Field{Name: "Regions", Value: strings.Join(a.AffectedRegions, ", ")})
+	}
+	fields = append(fields, notifier.Field{Name: "Details", Value: "https://cloud.oracle.com/announcements"})
+
+	if err := w.Notifier.Send(notifier.Message{
+		Title:    "📢 OCI Tenancy Announcement",
+		Fields:   fields,
+		Color:    color,
+		Priority: 4,
+		Tags:     "loudspeaker,warning",
+	}); err != nil {
+		w.Logger.Error(w.AccountName, fmt.Sprintf("Notification failed: %v", err))
+	}
+}
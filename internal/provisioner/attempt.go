@@ -0,0 +1,52 @@
+package provisioner
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync/atomic"
+)
+
+// attempt is a launch attempt's correlation ID. Every OCI call made with its context
+// sends "<id>-<n>" as opc-request-id, which OCI keeps as the prefix of its own request
+// ID, so the attempt can be found in OCI's logs and in a support request.
+type attempt struct {
+	id    string
+	calls atomic.Int64
+}
+
+type attemptKey struct{}
+
+// newAttemptID returns a random 12 hex digit ID.
+func newAttemptID() string {
+	b := make([]byte, 6)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// attemptFrom returns the attempt ctx belongs to, or nil.
+func attemptFrom(ctx context.Context) *attempt {
+	a, _ := ctx.Value(attemptKey{}).(*attempt)
+	return a
+}
+
+// requestID returns the opc-request-id of the attempt's next OCI call.
+func (a *attempt) requestID() string {
+	return fmt.Sprintf("%s-%d", a.id, a.calls.Add(1))
+}
+
+// beginAttempt starts a launch attempt: its ID tags the account's log lines, MQTT events
+// and hooks until endAttempt, and the OCI calls made with the returned context.
+func (w *AccountWorker) beginAttempt(ctx context.Context) context.Context {
+	a := &attempt{id: newAttemptID()}
+	w.attemptID = a.id
+	w.Logger.SetAttempt(w.AccountName, a.id)
+	return context.WithValue(ctx, attemptKey{}, a)
+}
+
+// endAttempt stops tagging the account's log lines and events.
+func (w *AccountWorker) endAttempt() {
+	w.attemptID = ""
+	w.Logger.SetAttempt(w.AccountName, "")
+}
@@ -0,0 +1,62 @@
+package provisioner
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yourusername/oci-arm-provisioner/internal/config"
+)
+
+// backoffWait is how long to wait after misses capacity misses in a row:
+// base_interval_minutes, doubling with each miss up to max_interval_minutes.
+func backoffWait(r config.RetryConfig, misses int) time.Duration {
+	base := time.Duration(r.BaseIntervalMinutes) * time.Minute
+	limit := max(time.Duration(r.MaxIntervalMinutes)*time.Minute, base)
+	wait := base
+	for i := 1; i < misses && wait < limit; i++ {
+		wait *= 2
+	}
+	return min(wait, limit)
+}
+
+// backoffEnabled reports whether retry configures a capacity backoff.
+func (w *AccountWorker) backoffEnabled() bool {
+	return w.Retry.ExponentialBackoff && w.Retry.BaseIntervalMinutes > 0
+}
+
+// backOff counts a cycle that ended without capacity and, with exponential_backoff,
+// holds the account's next attempt back for backoffWait. The backoff is kept in the
+// state, so a restart waits it out too.
+func (w *AccountWorker) backOff() {
+	if !w.backoffEnabled() {
+		return
+	}
+	b, _ := w.State.Backoff(w.AccountName)
+	b.Misses++
+	wait := backoffWait(w.Retry, b.Misses)
+	b.Until = time.Now().Add(wait)
+	if err := w.State.SetBackoff(w.AccountName, &b); err != nil {
+		w.Logger.Warn(w.AccountName, fmt.Sprintf("Failed to persist backoff: %v", err))
+	}
+	w.Logger.Info(w.AccountName, fmt.Sprintf("⏳ %d capacity misses in a row - next attempt in %v", b.Misses, wait))
+}
+
+// backingOff returns how long the account still waits out its capacity backoff, or 0.
+// A wait saved under a longer max_interval_minutes is cut to the current one.
+func (w *AccountWorker) backingOff() time.Duration {
+	b, ok := w.State.Backoff(w.AccountName)
+	if !ok || !w.backoffEnabled() {
+		return 0
+	}
+	return min(max(time.Until(b.Until), 0), backoffWait(w.Retry, b.Misses))
+}
+
+// resetBackoff ends the account's capacity backoff after a launch.
+func (w *AccountWorker) resetBackoff() {
+	if _, ok := w.State.Backoff(w.AccountName); !ok {
+		return
+	}
+	if err := w.State.SetBackoff(w.AccountName, nil); err != nil {
+		w.Logger.Warn(w.AccountName, fmt.Sprintf("Failed to persist backoff: %v", err))
+	}
+}
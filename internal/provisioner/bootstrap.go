@@ -0,0 +1,145 @@
+package provisioner
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/core"
+	"github.com/yourusername/oci-arm-provisioner/internal/notifier"
+)
+
+// metadataURL returns the OCID of the instance the tool runs on. Tests override it.
+var metadataURL = "http://169.254.169.254/opc/v2/instance/id"
+
+// hostInstanceID asks the instance metadata service which instance this is.
+func hostInstanceID(ctx context.Context) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, metadataURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer Oracle")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("instance metadata unavailable (not running on OCI?): %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 512))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("instance metadata: status %d", resp.StatusCode)
+	}
+	return strings.TrimSpace(string(body)), nil
+}
+
+// bootstrap hands off to the instance that was just verified: it runs migrate_command
+// and then terminates the host the tool runs on. A failed command, or an instance that
+// failed verification, keeps the host.
+func (w *AccountWorker) bootstrap(ctx context.Context) {
+	b := w.Config.Bootstrap
+	v := w.Verified
+	if !b.Enabled() || v == nil {
+		return
+	}
+	if !v.Verified || v.PublicIP == "" {
+		w.Logger.Warn(w.AccountName, "Not running the bootstrap: the new instance was not verified with a public IP, keeping this host")
+		return
+	}
+
+	if b.MigrateCommand != "" {
+		w.Logger.Info(w.AccountName, "🚚 Running the migration command...")
+		if err := w.migrate(ctx); err != nil {
+			w.Logger.Error(w.AccountName, fmt.Sprintf("Migration command failed, keeping this host: %v", err))
+			w.notifyBootstrap("⚠️ Migration Failed", fmt.Sprintf("%v - this host was kept", err), notifier.ColorError)
+			return
+		}
+		w.Logger.Success(w.AccountName, "🚚 Migration command finished")
+	}
+	if !b.TerminateHost {
+		w.notifyBootstrap("🚚 Migration Finished", "This host keeps running", notifier.ColorSuccess)
+		return
+	}
+
+	host, err := hostInstanceID(ctx)
+	if err == nil && host == v.InstanceID {
+		err = fmt.Errorf("the host is the new instance")
+	}
+	if err != nil {
+		w.Logger.Error(w.AccountName, fmt.Sprintf("Not terminating this host: %v", err))
+		w.notifyBootstrap("⚠️ Host Not Terminated", err.Error(), notifier.ColorError)
+		return
+	}
+
+	// Say goodbye first: once the host is gone nothing else gets sent
+	w.Logger.Warn(w.AccountName, fmt.Sprintf("🧨 Terminating this host (%s)", host))
+	w.notifyBootstrap("🧨 Terminating This Host", host, notifier.ColorInfo)
+	if err := w.Notifier.Flush(); err != nil {
+		w.Logger.Error(w.AccountName, fmt.Sprintf("Notification failed: %v", err))
+	}
+	_, err = w.ComputeClient.TerminateInstance(ctx, core.TerminateInstanceRequest{
+		InstanceId:         common.String(host),
+		PreserveBootVolume: common.Bool(b.KeepHostBootVolume),
+	})
+	w.observe(err)
+	if err != nil {
+		w.Logger.Error(w.AccountName, fmt.Sprintf("Failed to terminate this host: %v", err))
+		w.notifyBootstrap("⚠️ Host Not Terminated", err.Error(), notifier.ColorError)
+	}
+}
+
+// migrate runs migrate_command with the new instance's details in its environment.
+func (w *AccountWorker) migrate(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, w.Config.Bootstrap.TimeoutDuration())
+	defer cancel()
+
+	command := w.Config.Bootstrap.MigrateCommand
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	cmd.Env = append(os.Environ(),
+		"OCI_ACCOUNT="+w.AccountName,
+		"OCI_INSTANCE_ID="+w.Verified.InstanceID,
+		"OCI_PUBLIC_IP="+w.Verified.PublicIP,
+		"OCI_PRIVATE_IP="+w.Verified.PrivateIP,
+		"OCI_ATTEMPT_ID="+w.attemptID,
+	)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		if tail := strings.TrimSpace(string(out)); tail != "" {
+			return fmt.Errorf("%w: %s", err, tail[strings.LastIndex(tail, "\n")+1:])
+		}
+		return err
+	}
+	return nil
+}
+
+// notifyBootstrap reports a step of the hand-off.
+func (w *AccountWorker) notifyBootstrap(title, detail string, color int) {
+	if err := w.Notifier.Send(notifier.Message{
+		Title: title,
+		Fields: []notifier.Field{
+			{Name: "Account", Value: w.AccountName},
+			{Name: "New Instance", Value: fmt.Sprintf("%s · %s", w.Verified.InstanceID, orNoIP(w.Verified.PublicIP))},
+			{Name: "Details", Value: detail},
+		},
+		Color:    color,
+		Priority: 4,
+		Tags:     "truck",
+	}); err != nil {
+		w.Logger.Error(w.AccountName, fmt.Sprintf("Notification failed: %v", err))
+	}
+}
@@ -0,0 +1,80 @@
+package provisioner
+
+import (
+	"sync"
+	"time"
+
+	"github.com/yourusername/oci-arm-provisioner/internal/errorclass"
+)
+
+// Breaker is an error-budget circuit breaker shared by all account workers.
+// After Threshold consecutive transient API failures (timeouts, network errors,
+// 5xx unrelated to capacity) it opens and blocks attempts for Cooldown.
+// A nil *Breaker is valid and never opens.
+type Breaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	openUntil time.Time
+	now       func() time.Time
+}
+
+// NewBreaker creates a Breaker. A threshold <= 0 disables it (returns nil).
+func NewBreaker(threshold int, cooldown time.Duration) *Breaker {
+	if threshold <= 0 {
+		return nil
+	}
+	return &Breaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+}
+
+// Allow reports whether attempts may proceed, and if not, how long until the breaker closes.
+func (b *Breaker) Allow() (bool, time.Duration) {
+	if b == nil {
+		return true, 0
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if remaining := b.openUntil.Sub(b.now()); remaining > 0 {
+		return false, remaining
+	}
+	return true, 0
+}
+
+// Success resets the consecutive failure count.
+func (b *Breaker) Success() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = 0
+}
+
+// Failure records a transient failure. It returns true only on the call that opens the breaker,
+// so callers can alert exactly once per trip.
+func (b *Breaker) Failure() bool {
+	if b == nil {
+		return false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	if b.failures < b.threshold {
+		return false
+	}
+	b.failures = 0
+	b.openUntil = b.now().Add(b.cooldown)
+	return true
+}
+
+// isTransientAPIError reports whether err indicates a misbehaving network or OCI
+// endpoint rather than a meaningful API answer. Capacity errors and rate limits
+// are expected responses and do not count against the error budget.
+func isTransientAPIError(err error) bool {
+	return errorclass.Of(err).Transient()
+}
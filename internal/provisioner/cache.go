@@ -0,0 +1,99 @@
+package provisioner
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/core"
+)
+
+// defaultPollInterval is used when a worker has no configured PollInterval.
+const defaultPollInterval = 10 * time.Second
+
+// ifNoneMatchKey carries a cached ETag from instanceCache to conditionalGet.
+type ifNoneMatchKey struct{}
+
+// conditionalGet is an OCI client interceptor that turns GET requests carrying a
+// cached ETag in their context into conditional requests.
+func conditionalGet(req *http.Request) error {
+	if etag, ok := req.Context().Value(ifNoneMatchKey{}).(string); ok && req.Method == http.MethodGet {
+		req.Header.Set("If-None-Match", etag)
+	}
+	return nil
+}
+
+// instanceCache wraps a ComputeClientOps and caches GetInstance responses for one
+// polling interval, so repeated status checks within that window cost no API calls.
+// Once an entry expires it is revalidated with If-None-Match; a 304 reuses the cached body.
+type instanceCache struct {
+	ComputeClientOps
+	ttl     time.Duration
+	now     func() time.Time
+	mu      sync.Mutex
+	entries map[string]instanceEntry
+}
+
+type instanceEntry struct {
+	resp    core.GetInstanceResponse
+	fetched time.Time
+}
+
+func newInstanceCache(client ComputeClientOps, ttl time.Duration) *instanceCache {
+	return &instanceCache{
+		ComputeClientOps: client,
+		ttl:              ttl,
+		now:              time.Now,
+		entries:          make(map[string]instanceEntry),
+	}
+}
+
+// GetInstance returns a cached response while it is fresh, otherwise fetches (conditionally, if an ETag is known).
+func (c *instanceCache) GetInstance(ctx context.Context, request core.GetInstanceRequest) (core.GetInstanceResponse, error) {
+	id := safeString(request.InstanceId)
+
+	c.mu.Lock()
+	entry, cached := c.entries[id]
+	c.mu.Unlock()
+
+	if cached && c.now().Sub(entry.fetched) < c.ttl {
+		return entry.resp, nil
+	}
+	if cached && entry.resp.Etag != nil {
+		ctx = context.WithValue(ctx, ifNoneMatchKey{}, *entry.resp.Etag)
+	}
+
+	resp, err := c.ComputeClientOps.GetInstance(ctx, request)
+	if cached && isNotModified(resp, err) {
+		resp, err = entry.resp, nil
+	}
+	if err != nil {
+		return resp, err
+	}
+
+	c.mu.Lock()
+	c.entries[id] = instanceEntry{resp: resp, fetched: c.now()}
+	c.mu.Unlock()
+	return resp, nil
+}
+
+// isNotModified reports whether OCI answered a conditional request with 304.
+func isNotModified(resp core.GetInstanceResponse, err error) bool {
+	if resp.RawResponse != nil && resp.RawResponse.StatusCode == http.StatusNotModified {
+		return true
+	}
+	if serviceErr, ok := common.IsServiceError(err); ok {
+		return serviceErr.GetHTTPStatusCode() == http.StatusNotModified
+	}
+	return false
+}
+
+// pollInterval returns how often instance state should be polled.
+func (w *AccountWorker) pollInterval() time.Duration {
+	if w.PollInterval > 0 {
+		return w.PollInterval
+	}
+	return defaultPollInterval
+}
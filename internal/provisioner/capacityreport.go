@@ -0,0 +1,69 @@
+package provisioner
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/core"
+	"github.com/yourusername/oci-arm-provisioner/internal/config"
+)
+
+// probeCapacity asks the capacity report, with capacity_probe, which of ads have room
+// for opt, and returns those with the fault domain the report saw room in ("" if it
+// didn't say). If a report fails, all ads are tried as usual.
+func (w *AccountWorker) probeCapacity(ctx context.Context, opt config.ShapeOption, ads []string) ([]string, map[string]string) {
+	if !w.Config.CapacityProbe || w.Config.DedicatedVMHostOCID != "" {
+		return ads, nil
+	}
+	shape := core.CreateCapacityReportShapeAvailabilityDetails{InstanceShape: common.String(opt.Shape)}
+	if !opt.Fixed {
+		shape.InstanceShapeConfig = &core.CapacityReportInstanceShapeConfig{
+			Ocpus:       common.Float32(opt.OCPUs),
+			MemoryInGBs: common.Float32(opt.MemoryGB),
+		}
+	}
+
+	var room, seen []string
+	faultDomains := make(map[string]string)
+	for _, ad := range ads {
+		resp, err := w.ComputeClient.CreateComputeCapacityReport(ctx, core.CreateComputeCapacityReportRequest{
+			CreateComputeCapacityReportDetails: core.CreateComputeCapacityReportDetails{
+				CompartmentId:       common.String(w.Config.TenancyOCID),
+				AvailabilityDomain:  common.String(ad),
+				ShapeAvailabilities: []core.CreateCapacityReportShapeAvailabilityDetails{shape},
+			},
+		})
+		w.observe(err)
+		if err != nil {
+			w.Logger.Warn(w.AccountName, fmt.Sprintf("Could not get a capacity report for %s, launching anyway: %v", ad, err))
+			return ads, nil
+		}
+		status, fd, ok := reportedRoom(resp.ShapeAvailabilities)
+		if !ok {
+			seen = append(seen, ad+": "+status)
+			continue
+		}
+		seen = append(seen, strings.TrimSpace(ad+": "+status+" "+fd))
+		room = append(room, ad)
+		faultDomains[ad] = fd
+	}
+	w.Logger.Info(w.AccountName, fmt.Sprintf("📡 Capacity report for %s: %s", opt.Shape, strings.Join(seen, ", ")))
+	return room, faultDomains
+}
+
+// reportedRoom returns the first fault domain the report has room in, if any has, or
+// else the status it gave, e.g. OUT_OF_HOST_CAPACITY.
+func reportedRoom(availabilities []core.CapacityReportShapeAvailability) (status, faultDomain string, ok bool) {
+	status = "no report"
+	for _, a := range availabilities {
+		if a.AvailabilityStatus == core.CapacityReportShapeAvailabilityAvailabilityStatusAvailable {
+			return string(a.AvailabilityStatus), safeString(a.FaultDomain), true
+		}
+		if a.AvailabilityStatus != "" {
+			status = string(a.AvailabilityStatus)
+		}
+	}
+	return status, "", false
+}
@@ -0,0 +1,99 @@
+package provisioner
+
+//go:generate go run gen_mocks.go
+
+import (
+	"context"
+
+	"github.com/oracle/oci-go-sdk/v65/announcementsservice"
+	"github.com/oracle/oci-go-sdk/v65/core"
+	"github.com/oracle/oci-go-sdk/v65/identity"
+	"github.com/oracle/oci-go-sdk/v65/limits"
+	"github.com/oracle/oci-go-sdk/v65/secrets"
+	"github.com/oracle/oci-go-sdk/v65/usageapi"
+	"github.com/oracle/oci-go-sdk/v65/workrequests"
+)
+
+// The interfaces below are the subset of the OCI SDK used by the provisioner.
+// Mocks for tests are generated from this file; run `go generate ./internal/provisioner`
+// after adding a method.
+
+// ComputeClientOps defines the interface for OCI Compute operations, enabling testing/mocking.
+type ComputeClientOps interface {
+	LaunchInstance(ctx context.Context, request core.LaunchInstanceRequest) (core.LaunchInstanceResponse, error)
+	ListInstances(ctx context.Context, request core.ListInstancesRequest) (core.ListInstancesResponse, error)
+	GetInstance(ctx context.Context, request core.GetInstanceRequest) (core.GetInstanceResponse, error)
+	TerminateInstance(ctx context.Context, request core.TerminateInstanceRequest) (core.TerminateInstanceResponse, error)
+	ListVnicAttachments(ctx context.Context, request core.ListVnicAttachmentsRequest) (core.ListVnicAttachmentsResponse, error)
+	ListBootVolumeAttachments(ctx context.Context, request core.ListBootVolumeAttachmentsRequest) (core.ListBootVolumeAttachmentsResponse, error)
+	ListImages(ctx context.Context, request core.ListImagesRequest) (core.ListImagesResponse, error)
+	CaptureConsoleHistory(ctx context.Context, request core.CaptureConsoleHistoryRequest) (core.CaptureConsoleHistoryResponse, error)
+	GetConsoleHistory(ctx context.Context, request core.GetConsoleHistoryRequest) (core.GetConsoleHistoryResponse, error)
+	GetConsoleHistoryContent(ctx context.Context, request core.GetConsoleHistoryContentRequest) (core.GetConsoleHistoryContentResponse, error)
+	DeleteConsoleHistory(ctx context.Context, request core.DeleteConsoleHistoryRequest) (core.DeleteConsoleHistoryResponse, error)
+	CreateComputeCapacityReport(ctx context.Context, request core.CreateComputeCapacityReportRequest) (core.CreateComputeCapacityReportResponse, error)
+}
+
+// VirtualNetworkClientOps defines the interface for OCI Virtual Network operations.
+type VirtualNetworkClientOps interface {
+	GetVnic(ctx context.Context, request core.GetVnicRequest) (core.GetVnicResponse, error)
+	GetSubnet(ctx context.Context, request core.GetSubnetRequest) (core.GetSubnetResponse, error)
+	ListPrivateIps(ctx context.Context, request core.ListPrivateIpsRequest) (core.ListPrivateIpsResponse, error)
+	GetPublicIp(ctx context.Context, request core.GetPublicIpRequest) (core.GetPublicIpResponse, error)
+	GetPublicIpByPrivateIpId(ctx context.Context, request core.GetPublicIpByPrivateIpIdRequest) (core.GetPublicIpByPrivateIpIdResponse, error)
+	ListPublicIps(ctx context.Context, request core.ListPublicIpsRequest) (core.ListPublicIpsResponse, error)
+	CreatePublicIp(ctx context.Context, request core.CreatePublicIpRequest) (core.CreatePublicIpResponse, error)
+	UpdatePublicIp(ctx context.Context, request core.UpdatePublicIpRequest) (core.UpdatePublicIpResponse, error)
+	DeletePublicIp(ctx context.Context, request core.DeletePublicIpRequest) (core.DeletePublicIpResponse, error)
+}
+
+// BlockstorageClientOps defines the interface for OCI Block Storage operations.
+type BlockstorageClientOps interface {
+	GetBootVolume(ctx context.Context, request core.GetBootVolumeRequest) (core.GetBootVolumeResponse, error)
+}
+
+// IdentityClientOps defines the interface for OCI Identity operations.
+type IdentityClientOps interface {
+	ListAvailabilityDomains(ctx context.Context, request identity.ListAvailabilityDomainsRequest) (identity.ListAvailabilityDomainsResponse, error)
+	ListRegionSubscriptions(ctx context.Context, request identity.ListRegionSubscriptionsRequest) (identity.ListRegionSubscriptionsResponse, error)
+	ListFaultDomains(ctx context.Context, request identity.ListFaultDomainsRequest) (identity.ListFaultDomainsResponse, error)
+}
+
+// WorkRequestClientOps defines the interface for OCI Work Request operations.
+type WorkRequestClientOps interface {
+	GetWorkRequest(ctx context.Context, request workrequests.GetWorkRequestRequest) (workrequests.GetWorkRequestResponse, error)
+	ListWorkRequestErrors(ctx context.Context, request workrequests.ListWorkRequestErrorsRequest) (workrequests.ListWorkRequestErrorsResponse, error)
+}
+
+// AnnouncementClientOps defines the interface for OCI Announcements operations.
+type AnnouncementClientOps interface {
+	ListAnnouncements(ctx context.Context, request announcementsservice.ListAnnouncementsRequest) (announcementsservice.ListAnnouncementsResponse, error)
+}
+
+// UsageClientOps defines the interface for OCI Usage API operations.
+type UsageClientOps interface {
+	RequestSummarizedUsages(ctx context.Context, request usageapi.RequestSummarizedUsagesRequest) (usageapi.RequestSummarizedUsagesResponse, error)
+}
+
+// LimitsClientOps defines the interface for OCI Limits operations.
+type LimitsClientOps interface {
+	GetResourceAvailability(ctx context.Context, request limits.GetResourceAvailabilityRequest) (limits.GetResourceAvailabilityResponse, error)
+}
+
+// SecretsClientOps defines the interface for OCI Vault secret retrieval.
+type SecretsClientOps interface {
+	GetSecretBundle(ctx context.Context, request secrets.GetSecretBundleRequest) (secrets.GetSecretBundleResponse, error)
+}
+
+// Compile-time checks that the SDK clients still satisfy the interfaces.
+var (
+	_ ComputeClientOps        = (*core.ComputeClient)(nil)
+	_ VirtualNetworkClientOps = (*core.VirtualNetworkClient)(nil)
+	_ BlockstorageClientOps   = (*core.BlockstorageClient)(nil)
+	_ IdentityClientOps       = (*identity.IdentityClient)(nil)
+	_ WorkRequestClientOps    = (*workrequests.WorkRequestClient)(nil)
+	_ AnnouncementClientOps   = (*announcementsservice.AnnouncementClient)(nil)
+	_ UsageClientOps          = (*usageapi.UsageapiClient)(nil)
+	_ LimitsClientOps         = (*limits.LimitsClient)(nil)
+	_ SecretsClientOps        = (*secrets.SecretsClient)(nil)
+)
@@ -0,0 +1,45 @@
+package provisioner
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/yourusername/oci-arm-provisioner/internal/config"
+)
+
+// cloudInitFinished matches the line cloud-init prints when its last stage is done, e.g.
+// "Cloud-init v. 24.1.3 finished at Thu, 16 Oct 2026 10:01:13 +0000. Datasource
+// DataSourceOracle.  Up 73.09 seconds".
+var cloudInitFinished = regexp.MustCompile(`Cloud-init v\. \S+ finished at .*Up ([\d.]+) seconds`)
+
+// waitForCloudInit polls the instance's serial console until cloud-init finishes, for
+// verify.wait_for_cloud_init, and returns the uptime it finished at. It gives up after
+// verify.cloud_init_timeout_minutes, or at once if console history can't be captured.
+func (w *AccountWorker) waitForCloudInit(ctx context.Context, instanceID string) (time.Duration, error) {
+	timeout := time.Duration(w.Config.Verify.CloudInitTimeoutMinutes) * time.Minute
+	if timeout <= 0 {
+		timeout = config.DefaultCloudInitTimeoutMinutes * time.Minute
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	w.Logger.Info(w.AccountName, "⏳ Waiting for cloud-init to finish...")
+	for {
+		console, err := w.consoleHistory(ctx, instanceID)
+		if err != nil && ctx.Err() == nil {
+			return 0, err
+		}
+		if m := cloudInitFinished.FindStringSubmatch(console); m != nil {
+			seconds, _ := strconv.ParseFloat(m[1], 64)
+			return time.Duration(seconds * float64(time.Second)), nil
+		}
+		select {
+		case <-ctx.Done():
+			return 0, fmt.Errorf("cloud-init did not finish within %v", timeout)
+		case <-time.After(w.pollInterval()):
+		}
+	}
+}
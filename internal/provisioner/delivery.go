@@ -0,0 +1,83 @@
+package provisioner
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/oci-arm-provisioner/internal/notifier"
+	"github.com/yourusername/oci-arm-provisioner/internal/state"
+)
+
+// How often, and for how long, acknowledge buttons are polled after a success.
+var (
+	ackPollInterval = 30 * time.Second
+	ackTimeout      = 24 * time.Hour
+)
+
+// recordReceipt logs and persists where a success notification got through.
+func (w *AccountWorker) recordReceipt(r notifier.Receipt) {
+	for _, d := range r.Deliveries {
+		switch {
+		case d.Status == notifier.DeliveryFailed:
+			w.Logger.Error(w.AccountName, fmt.Sprintf("Success notification NOT delivered via %s after %d attempts: %s", d.Provider, d.Attempts, d.Error))
+		case d.Attempts > 1:
+			w.Logger.Info(w.AccountName, fmt.Sprintf("Success notification delivered via %s after %d attempts", d.Provider, d.Attempts))
+		}
+	}
+	w.saveReceipt(r)
+}
+
+// saveReceipt persists the delivery record for the TUI and later runs.
+func (w *AccountWorker) saveReceipt(r notifier.Receipt) {
+	saved := state.Receipt{InstanceID: r.InstanceID, SentAt: r.SentAt}
+	for _, d := range r.Deliveries {
+		saved.Deliveries = append(saved.Deliveries, state.Delivery{
+			Provider: d.Provider,
+			Status:   string(d.Status),
+			Attempts: d.Attempts,
+			Error:    d.Error,
+			At:       d.At,
+		})
+	}
+	if err := w.State.SetNotification(w.AccountName, saved); err != nil {
+		w.Logger.Warn(w.AccountName, fmt.Sprintf("Failed to persist notification delivery: %v", err))
+	}
+}
+
+// watchAcks polls for presses of the acknowledge buttons until all were pressed, ackTimeout
+// passes or ctx ends.
+func (w *AccountWorker) watchAcks(ctx context.Context, r notifier.Receipt) {
+	if len(r.Pending()) == 0 {
+		return
+	}
+	ticker := time.NewTicker(ackPollInterval)
+	defer ticker.Stop()
+	deadline := time.After(ackTimeout)
+	warned := false
+
+	for len(r.Pending()) > 0 {
+		select {
+		case <-ctx.Done():
+			return
+		case <-deadline:
+			w.Logger.Warn(w.AccountName, fmt.Sprintf("Success notification not acknowledged within %v (%v)", ackTimeout, r.Pending()))
+			return
+		case <-ticker.C:
+		}
+
+		acked, err := w.Notifier.Acknowledged(r)
+		if err != nil && !warned {
+			w.Logger.Warn(w.AccountName, err.Error())
+			warned = true // once; the poll keeps retrying
+		}
+		for _, provider := range acked {
+			if r.Acknowledge(provider, time.Now()) {
+				w.Logger.Success(w.AccountName, fmt.Sprintf("Success notification acknowledged via %s", provider))
+			}
+		}
+		if len(acked) > 0 {
+			w.saveReceipt(r)
+		}
+	}
+}
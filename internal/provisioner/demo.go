@@ -0,0 +1,350 @@
+package provisioner
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/core"
+	"github.com/oracle/oci-go-sdk/v65/identity"
+	"github.com/yourusername/oci-arm-provisioner/internal/config"
+	"github.com/yourusername/oci-arm-provisioner/internal/logger"
+)
+
+// Odds of a simulated launch; the rest fail with "Out of host capacity".
+const (
+	demoLaunchOdds    = 0.08
+	demoRateLimitOdds = 0.05
+)
+
+// demoBootTime is how long a simulated instance is PROVISIONING before it runs.
+const demoBootTime = 8 * time.Second
+
+// demoCloud is the simulated OCI of --demo, one per account: launches mostly fail for
+// capacity, now and then hit the rate limit, and sometimes succeed with an instance that
+// boots within seconds and prints its SSH host keys.
+type demoCloud struct {
+	region string
+
+	mu        sync.Mutex
+	rng       *rand.Rand
+	instances map[string]*demoInstance
+}
+
+type demoInstance struct {
+	core.Instance
+	launched time.Time
+}
+
+var (
+	_ ComputeClientOps        = (*demoCloud)(nil)
+	_ VirtualNetworkClientOps = (*demoCloud)(nil)
+	_ IdentityClientOps       = (*demoCloud)(nil)
+)
+
+func newDemoCloud(region string) *demoCloud {
+	return &demoCloud{
+		region:    region,
+		rng:       rand.New(rand.NewSource(time.Now().UnixNano() + int64(demoHash(region)))),
+		instances: make(map[string]*demoInstance),
+	}
+}
+
+// demoError is a simulated OCI service error.
+type demoError struct {
+	status  int
+	code    string
+	message string
+}
+
+func (e demoError) Error() string {
+	return fmt.Sprintf("Error returned by Compute Service. Http Status Code: %d. Error Code: %s. Message: %s", e.status, e.code, e.message)
+}
+func (e demoError) GetHTTPStatusCode() int  { return e.status }
+func (e demoError) GetMessage() string      { return e.message }
+func (e demoError) GetCode() string         { return e.code }
+func (e demoError) GetOpcRequestID() string { return "demo" }
+
+// useDemo points every worker at a simulated OCI and delivers notifications to the log
+// instead of the network (--demo).
+func (p *Provisioner) useDemo() {
+	p.Notifier.Client = &http.Client{Transport: demoNotifications{p.Logger}}
+	p.identityFor = func(_ *config.AccountConfig, region string) (IdentityClientOps, error) {
+		return newDemoCloud(region), nil
+	}
+	for _, w := range p.Workers {
+		w.useDemo()
+	}
+	p.Logger.Warn("DEMO", "🎬 Demo mode: OCI is simulated and notifications only show up here - no credentials are used")
+}
+
+// useDemo gives the worker its own simulated OCI.
+func (w *AccountWorker) useDemo() {
+	cloud := newDemoCloud(w.Config.Region)
+	w.ComputeClient, w.IdentityClient, w.VirtualNetworkClient = cloud, cloud, cloud
+}
+
+// demoNotifications answers every notification request and logs what it would send.
+type demoNotifications struct {
+	log *logger.Logger
+}
+
+func (d demoNotifications) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodPost {
+		var body []byte
+		if req.Body != nil {
+			body, _ = io.ReadAll(req.Body)
+			req.Body.Close()
+		}
+		var lines []string
+		for _, line := range strings.Split(strings.ReplaceAll(string(body), "**", ""), "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				lines = append(lines, line)
+			}
+		}
+		d.log.Info("NOTIFY", fmt.Sprintf("📣 %s: %s", req.Header.Get("Title"), strings.Join(lines, " · ")))
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Status:     "200 OK",
+		Header:     make(http.Header),
+		Body:       io.NopCloser(strings.NewReader("")),
+		Request:    req,
+	}, nil
+}
+
+// demoHash derives stable fake values, e.g. an instance's IP, from s.
+func demoHash(s string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(s))
+	return h.Sum32()
+}
+
+// instance returns the instance with its current lifecycle state.
+func (c *demoCloud) instance(id string) (core.Instance, bool) {
+	inst, ok := c.instances[id]
+	if !ok {
+		return core.Instance{}, false
+	}
+	if inst.LifecycleState == core.InstanceLifecycleStateProvisioning && time.Since(inst.launched) >= demoBootTime {
+		inst.LifecycleState = core.InstanceLifecycleStateRunning
+	}
+	return inst.Instance, true
+}
+
+func (c *demoCloud) LaunchInstance(ctx context.Context, request core.LaunchInstanceRequest) (core.LaunchInstanceResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch r := c.rng.Float64(); {
+	case r < demoRateLimitOdds:
+		return core.LaunchInstanceResponse{}, demoError{http.StatusTooManyRequests, "TooManyRequests", "Too many requests for the user"}
+	case r >= demoRateLimitOdds+demoLaunchOdds:
+		return core.LaunchInstanceResponse{}, demoError{http.StatusInternalServerError, "InternalError", "Out of host capacity."}
+	}
+
+	d := request.LaunchInstanceDetails
+	now := time.Now()
+	inst := core.Instance{
+		Id:                 common.String(fmt.Sprintf("ocid1.instance.oc1.%s.demo%08x", c.region, c.rng.Uint32())),
+		DisplayName:        d.DisplayName,
+		CompartmentId:      d.CompartmentId,
+		AvailabilityDomain: d.AvailabilityDomain,
+		FaultDomain:        d.FaultDomain,
+		Region:             common.String(c.region),
+		Shape:              d.Shape,
+		LifecycleState:     core.InstanceLifecycleStateProvisioning,
+		FreeformTags:       d.FreeformTags,
+		DefinedTags:        d.DefinedTags,
+		TimeCreated:        &common.SDKTime{Time: now},
+	}
+	if inst.FaultDomain == nil {
+		inst.FaultDomain = common.String(fmt.Sprintf("FAULT-DOMAIN-%d", c.rng.Intn(3)+1))
+	}
+	if d.ShapeConfig != nil {
+		inst.ShapeConfig = &core.InstanceShapeConfig{Ocpus: d.ShapeConfig.Ocpus, MemoryInGBs: d.ShapeConfig.MemoryInGBs}
+	}
+	c.instances[*inst.Id] = &demoInstance{Instance: inst, launched: now}
+	return core.LaunchInstanceResponse{Instance: inst, OpcRequestId: common.String("demo")}, nil
+}
+
+func (c *demoCloud) ListInstances(ctx context.Context, request core.ListInstancesRequest) (core.ListInstancesResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var items []core.Instance
+	for id := range c.instances {
+		inst, _ := c.instance(id)
+		if safeString(inst.CompartmentId) != safeString(request.CompartmentId) {
+			continue
+		}
+		if request.DisplayName != nil && safeString(inst.DisplayName) != *request.DisplayName {
+			continue
+		}
+		items = append(items, inst)
+	}
+	return core.ListInstancesResponse{Items: items}, nil
+}
+
+func (c *demoCloud) GetInstance(ctx context.Context, request core.GetInstanceRequest) (core.GetInstanceResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	inst, ok := c.instance(safeString(request.InstanceId))
+	if !ok {
+		return core.GetInstanceResponse{}, demoError{http.StatusNotFound, "NotAuthorizedOrNotFound", "Authorization failed or requested resource not found."}
+	}
+	return core.GetInstanceResponse{Instance: inst}, nil
+}
+
+func (c *demoCloud) TerminateInstance(ctx context.Context, request core.TerminateInstanceRequest) (core.TerminateInstanceResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if inst, ok := c.instances[safeString(request.InstanceId)]; ok {
+		inst.LifecycleState = core.InstanceLifecycleStateTerminated
+	}
+	return core.TerminateInstanceResponse{}, nil
+}
+
+func (c *demoCloud) ListVnicAttachments(ctx context.Context, request core.ListVnicAttachmentsRequest) (core.ListVnicAttachmentsResponse, error) {
+	id := safeString(request.InstanceId)
+	return core.ListVnicAttachmentsResponse{Items: []core.VnicAttachment{{
+		InstanceId:     request.InstanceId,
+		VnicId:         common.String("ocid1.vnic.oc1." + c.region + "." + id),
+		LifecycleState: core.VnicAttachmentLifecycleStateAttached,
+	}}}, nil
+}
+
+func (c *demoCloud) ListBootVolumeAttachments(ctx context.Context, request core.ListBootVolumeAttachmentsRequest) (core.ListBootVolumeAttachmentsResponse, error) {
+	return core.ListBootVolumeAttachmentsResponse{}, nil
+}
+
+func (c *demoCloud) ListImages(ctx context.Context, request core.ListImagesRequest) (core.ListImagesResponse, error) {
+	return core.ListImagesResponse{Items: []core.Image{{
+		Id:                     common.String("ocid1.image.oc1." + c.region + ".demo"),
+		DisplayName:            common.String("Canonical-Ubuntu-24.04-aarch64-2026.09.30-0"),
+		OperatingSystem:        common.String("Canonical Ubuntu"),
+		OperatingSystemVersion: common.String("24.04"),
+	}}}, nil
+}
+
+func (c *demoCloud) CaptureConsoleHistory(ctx context.Context, request core.CaptureConsoleHistoryRequest) (core.CaptureConsoleHistoryResponse, error) {
+	resp := core.CaptureConsoleHistoryResponse{}
+	resp.Id = common.String("ocid1.consolehistory.oc1." + c.region + ".demo")
+	resp.InstanceId = request.CaptureConsoleHistoryDetails.InstanceId
+	resp.LifecycleState = core.ConsoleHistoryLifecycleStateSucceeded
+	return resp, nil
+}
+
+func (c *demoCloud) GetConsoleHistory(ctx context.Context, request core.GetConsoleHistoryRequest) (core.GetConsoleHistoryResponse, error) {
+	resp := core.GetConsoleHistoryResponse{}
+	resp.Id = request.InstanceConsoleHistoryId
+	resp.LifecycleState = core.ConsoleHistoryLifecycleStateSucceeded
+	return resp, nil
+}
+
+func (c *demoCloud) GetConsoleHistoryContent(ctx context.Context, request core.GetConsoleHistoryContentRequest) (core.GetConsoleHistoryContentResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var b strings.Builder
+	b.WriteString("ci-info: no authorized SSH keys fingerprints found for user opc.\n")
+	b.WriteString("-----BEGIN SSH HOST KEY FINGERPRINTS-----\n")
+	for _, key := range []struct {
+		bits int
+		kind string
+	}{{256, "ECDSA"}, {256, "ED25519"}, {3072, "RSA"}} {
+		fmt.Fprintf(&b, "%d SHA256:%08x%08xDemo root@arm (%s)\n", key.bits, c.rng.Uint32(), c.rng.Uint32(), key.kind)
+	}
+	b.WriteString("-----END SSH HOST KEY FINGERPRINTS-----\n")
+	return core.GetConsoleHistoryContentResponse{Value: common.String(b.String())}, nil
+}
+
+func (c *demoCloud) DeleteConsoleHistory(ctx context.Context, request core.DeleteConsoleHistoryRequest) (core.DeleteConsoleHistoryResponse, error) {
+	return core.DeleteConsoleHistoryResponse{}, nil
+}
+
+func (c *demoCloud) CreateComputeCapacityReport(ctx context.Context, request core.CreateComputeCapacityReportRequest) (core.CreateComputeCapacityReportResponse, error) {
+	return core.CreateComputeCapacityReportResponse{}, nil
+}
+
+func (c *demoCloud) GetVnic(ctx context.Context, request core.GetVnicRequest) (core.GetVnicResponse, error) {
+	n := demoHash(safeString(request.VnicId))%250 + 2
+	return core.GetVnicResponse{Vnic: core.Vnic{
+		Id:        request.VnicId,
+		PublicIp:  common.String(fmt.Sprintf("203.0.113.%d", n)),
+		PrivateIp: common.String(fmt.Sprintf("10.0.0.%d", n)),
+		IsPrimary: common.Bool(true),
+	}}, nil
+}
+
+func (c *demoCloud) GetSubnet(ctx context.Context, request core.GetSubnetRequest) (core.GetSubnetResponse, error) {
+	return core.GetSubnetResponse{Subnet: core.Subnet{Id: request.SubnetId, CidrBlock: common.String("10.0.0.0/24")}}, nil
+}
+
+func (c *demoCloud) ListPrivateIps(ctx context.Context, request core.ListPrivateIpsRequest) (core.ListPrivateIpsResponse, error) {
+	return core.ListPrivateIpsResponse{}, nil
+}
+
+func (c *demoCloud) GetPublicIp(ctx context.Context, request core.GetPublicIpRequest) (core.GetPublicIpResponse, error) {
+	return core.GetPublicIpResponse{}, demoError{http.StatusNotFound, "NotAuthorizedOrNotFound", "Authorization failed or requested resource not found."}
+}
+
+func (c *demoCloud) GetPublicIpByPrivateIpId(ctx context.Context, request core.GetPublicIpByPrivateIpIdRequest) (core.GetPublicIpByPrivateIpIdResponse, error) {
+	return core.GetPublicIpByPrivateIpIdResponse{}, demoError{http.StatusNotFound, "NotAuthorizedOrNotFound", "Authorization failed or requested resource not found."}
+}
+
+func (c *demoCloud) ListPublicIps(ctx context.Context, request core.ListPublicIpsRequest) (core.ListPublicIpsResponse, error) {
+	return core.ListPublicIpsResponse{}, nil
+}
+
+func (c *demoCloud) CreatePublicIp(ctx context.Context, request core.CreatePublicIpRequest) (core.CreatePublicIpResponse, error) {
+	return core.CreatePublicIpResponse{}, nil
+}
+
+func (c *demoCloud) UpdatePublicIp(ctx context.Context, request core.UpdatePublicIpRequest) (core.UpdatePublicIpResponse, error) {
+	return core.UpdatePublicIpResponse{}, nil
+}
+
+func (c *demoCloud) DeletePublicIp(ctx context.Context, request core.DeletePublicIpRequest) (core.DeletePublicIpResponse, error) {
+	return core.DeletePublicIpResponse{}, nil
+}
+
+// demoADs are the simulated region's availability domains, e.g. "Uocm:EU-FRANKFURT-1-AD-1".
+func (c *demoCloud) demoADs() []string {
+	ads := make([]string, 3)
+	for i := range ads {
+		ads[i] = fmt.Sprintf("Uocm:%s-AD-%d", strings.ToUpper(c.region), i+1)
+	}
+	return ads
+}
+
+func (c *demoCloud) ListAvailabilityDomains(ctx context.Context, request identity.ListAvailabilityDomainsRequest) (identity.ListAvailabilityDomainsResponse, error) {
+	var items []identity.AvailabilityDomain
+	for _, ad := range c.demoADs() {
+		items = append(items, identity.AvailabilityDomain{Name: common.String(ad), CompartmentId: request.CompartmentId})
+	}
+	return identity.ListAvailabilityDomainsResponse{Items: items}, nil
+}
+
+func (c *demoCloud) ListRegionSubscriptions(ctx context.Context, request identity.ListRegionSubscriptionsRequest) (identity.ListRegionSubscriptionsResponse, error) {
+	return identity.ListRegionSubscriptionsResponse{Items: []identity.RegionSubscription{{
+		RegionName:   common.String(c.region),
+		Status:       identity.RegionSubscriptionStatusReady,
+		IsHomeRegion: common.Bool(true),
+	}}}, nil
+}
+
+func (c *demoCloud) ListFaultDomains(ctx context.Context, request identity.ListFaultDomainsRequest) (identity.ListFaultDomainsResponse, error) {
+	var items []identity.FaultDomain
+	for i := 1; i <= 3; i++ {
+		items = append(items, identity.FaultDomain{
+			Name:               common.String(fmt.Sprintf("FAULT-DOMAIN-%d", i)),
+			AvailabilityDomain: request.AvailabilityDomain,
+		})
+	}
+	return identity.ListFaultDomainsResponse{Items: items}, nil
+}
@@ -0,0 +1,158 @@
+package provisioner
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/core"
+	"github.com/yourusername/oci-arm-provisioner/internal/buildinfo"
+	"github.com/yourusername/oci-arm-provisioner/internal/notifier"
+	"github.com/yourusername/oci-arm-provisioner/internal/state"
+)
+
+// Freeform tags put on every launched instance, so it can be found again after a
+// reinstall or a move to another machine.
+const (
+	ownerTagKey   = "managed-by"
+	accountTagKey = "oci-arm-provisioner-account"
+)
+
+// ownerTags marks an instance as launched by this tool for the worker's account.
+func (w *AccountWorker) ownerTags() map[string]string {
+	return map[string]string{ownerTagKey: buildinfo.Product, accountTagKey: w.AccountName}
+}
+
+// freeformTags returns the account's freeform_tags plus the owner tags, which win so
+// discovery keeps finding the instance.
+func (w *AccountWorker) freeformTags() map[string]string {
+	tags := make(map[string]string, len(w.Config.FreeformTags)+2)
+	maps.Copy(tags, w.Config.FreeformTags)
+	maps.Copy(tags, w.ownerTags())
+	return tags
+}
+
+// definedTags returns the account's defined_tags in the form the SDK takes, or nil.
+func (w *AccountWorker) definedTags() map[string]map[string]interface{} {
+	if len(w.Config.DefinedTags) == 0 {
+		return nil
+	}
+	tags := make(map[string]map[string]interface{}, len(w.Config.DefinedTags))
+	for namespace, keys := range w.Config.DefinedTags {
+		tags[namespace] = make(map[string]interface{}, len(keys))
+		for key, value := range keys {
+			tags[namespace][key] = value
+		}
+	}
+	return tags
+}
+
+// managedInstances lists the live instances tagged for the worker's account.
+func (w *AccountWorker) managedInstances(ctx context.Context) ([]core.Instance, error) {
+	var found []core.Instance
+	req := core.ListInstancesRequest{CompartmentId: common.String(w.Config.CompartmentOCID)}
+	for {
+		resp, err := w.ComputeClient.ListInstances(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		for _, inst := range resp.Items {
+			if inst.FreeformTags[ownerTagKey] != buildinfo.Product || inst.FreeformTags[accountTagKey] != w.AccountName {
+				continue
+			}
+			switch inst.LifecycleState {
+			case core.InstanceLifecycleStateTerminated, core.InstanceLifecycleStateTerminating:
+				continue
+			}
+			found = append(found, inst)
+		}
+		if resp.OpcNextPage == nil {
+			return found, nil
+		}
+		req.Page = resp.OpcNextPage
+	}
+}
+
+// discover lists the live instances tagged for the worker's account, with their IPs.
+func (w *AccountWorker) discover(ctx context.Context) ([]state.Instance, error) {
+	if err := w.initClients(); err != nil {
+		return nil, err
+	}
+	managed, err := w.managedInstances(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// What the state knows about an instance (its boot volume, whether it is a slice) stays
+	known := make(map[string]state.Instance)
+	for _, inst := range w.State.Instances(w.AccountName) {
+		known[inst.ID] = inst
+	}
+	var found []state.Instance
+	for _, live := range managed {
+		id := safeString(live.Id)
+		publicIP, _, err := w.primaryIPs(ctx, id)
+		if err != nil {
+			w.Logger.Warn(w.AccountName, fmt.Sprintf("Could not look up the IP of %s: %v", id, err))
+		}
+		inst := known[id]
+		inst.ID, inst.Name, inst.PublicIP = id, safeString(live.DisplayName), publicIP
+		found = append(found, inst)
+	}
+	return found, nil
+}
+
+// Discover finds instances this tool launched earlier (by their ownership tags) and
+// reconciles them into the state, so a reinstall or a new machine doesn't hunt again
+// for accounts that already have one. Accounts with instance_count tagged instances are
+// marked provisioned.
+func (p *Provisioner) Discover(ctx context.Context) {
+	var fields []notifier.Field
+	for _, w := range p.Workers {
+		instances, err := w.discover(ctx)
+		if err != nil {
+			p.Logger.Warn(w.AccountName, fmt.Sprintf("Instance discovery failed: %v", err))
+			continue
+		}
+		for _, inst := range instances {
+			isNew, err := w.trackInstance(ctx, inst)
+			if err != nil {
+				p.Logger.Warn(w.AccountName, fmt.Sprintf("Failed to persist instance: %v", err))
+			}
+			p.Logger.Success(w.AccountName, fmt.Sprintf("🔎 Found managed instance %s (%s) %s", inst.Name, inst.PublicIP, inst.ID))
+			if isNew && w.State.Notified(w.AccountName, inst.ID) {
+				p.Logger.Info(w.AccountName, fmt.Sprintf("🔕 %s was announced before - not notifying again", inst.ID))
+				continue
+			}
+			if isNew {
+				fields = append(fields, notifier.Field{Name: w.AccountName, Value: fmt.Sprintf("%s · %s · %s", inst.Name, orNoIP(inst.PublicIP), inst.ID)})
+			}
+		}
+		if len(instances) >= w.instanceCount() {
+			p.Provisioned[w.AccountName] = true
+		}
+	}
+
+	// Only instances the state didn't know about are news
+	if len(fields) == 0 {
+		return
+	}
+	msg := notifier.Message{
+		Title:  "🔎 Existing Instances Found",
+		Fields: fields,
+		Color:  notifier.ColorInfo,
+		Tags:   "mag",
+	}
+	if err := p.Notifier.Send(msg); err != nil {
+		p.Logger.Error("DISCOVERY", fmt.Sprintf("Notification failed: %v", err))
+	}
+}
+
+// orNoIP shows a missing public IP as such.
+func orNoIP(ip string) string {
+	if ip == "" {
+		return "no public IP"
+	}
+	return ip
+}
@@ -0,0 +1,54 @@
+package provisioner
+
+import (
+	"context"
+	"sort"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/core"
+)
+
+// LaunchPreview is the launch request an account would send, or why it can't be built.
+type LaunchPreview struct {
+	Account string
+	Details core.LaunchInstanceDetails
+	Err     error
+}
+
+// PreviewLaunches builds the launch request of every enabled account, sorted by name,
+// without sending it. Only image aliases are looked up in OCI. The request has the
+// configured shape and size, before any free tier shrinking, and the configured AD; with
+// "auto" the AD is left out, as it is picked per cycle.
+func (p *Provisioner) PreviewLaunches(ctx context.Context) []LaunchPreview {
+	previews := make([]LaunchPreview, 0, len(p.Workers))
+	for _, w := range p.Workers {
+		details, err := w.previewLaunch(ctx)
+		previews = append(previews, LaunchPreview{Account: w.AccountName, Details: details, Err: err})
+	}
+	sort.Slice(previews, func(i, j int) bool { return previews[i].Account < previews[j].Account })
+	return previews
+}
+
+// previewLaunch builds the account's next launch request.
+func (w *AccountWorker) previewLaunch(ctx context.Context) (core.LaunchInstanceDetails, error) {
+	if err := w.initClients(); err != nil {
+		return core.LaunchInstanceDetails{}, err
+	}
+	displayName, hostname, err := w.instanceNames(w.State.Sequence(w.AccountName) + 1)
+	if err != nil {
+		return core.LaunchInstanceDetails{}, err
+	}
+	shape := w.shape()
+	source, err := w.imageSource(ctx, shape.Shape)
+	if err != nil {
+		return core.LaunchInstanceDetails{}, err
+	}
+	details, err := w.launchDetails(shape, shape.OCPUs, shape.MemoryGB, source, displayName, hostname)
+	if err != nil {
+		return core.LaunchInstanceDetails{}, err
+	}
+	if ad := w.Config.AvailabilityDomain; ad != "auto" {
+		details.AvailabilityDomain = common.String(ad)
+	}
+	return details, nil
+}
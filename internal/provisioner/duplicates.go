@@ -0,0 +1,121 @@
+package provisioner
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/yourusername/oci-arm-provisioner/internal/notifier"
+)
+
+// Accounts this process hunts, by lock key. The locks are held until exit, so a config
+// reload (a new Provisioner in the same process) keeps them, and the OS drops them if
+// the process dies.
+var (
+	claimsMu sync.Mutex
+	claims   = make(map[string]*flock.Flock)
+)
+
+// lockHolder describes the process holding an account lock; written next to the lock.
+type lockHolder struct {
+	PID   int       `json:"pid"`
+	Host  string    `json:"host"`
+	Since time.Time `json:"since"`
+}
+
+func (h lockHolder) String() string {
+	if h.PID == 0 {
+		return "another process"
+	}
+	return fmt.Sprintf("pid %d on %s, since %s", h.PID, h.Host, h.Since.Format("2006-01-02 15:04"))
+}
+
+// lockKey identifies the OCI user an account signs requests as. Two accounts with the
+// same user, even in different configs or regions, share one request budget.
+func lockKey(tenancy, user string) string {
+	sum := sha256.Sum256([]byte(tenancy + "/" + user))
+	return hex.EncodeToString(sum[:8])
+}
+
+// claimAccount takes the machine-wide lock for key in dir. It returns false and the
+// current holder when another process already has it. Problems with the owner file
+// only lose the holder's description, so they go to warn.
+func claimAccount(dir, key string, warn func(string)) (bool, lockHolder, error) {
+	claimsMu.Lock()
+	defer claimsMu.Unlock()
+	if _, ok := claims[key]; ok {
+		return true, lockHolder{}, nil
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return false, lockHolder{}, err
+	}
+	lock := flock.New(filepath.Join(dir, key+".lock"))
+	ok, err := lock.TryLock()
+	if err != nil {
+		return false, lockHolder{}, err
+	}
+	ownerPath := filepath.Join(dir, key+".json")
+	if !ok {
+		var holder lockHolder
+		data, err := os.ReadFile(ownerPath)
+		if err == nil {
+			err = json.Unmarshal(data, &holder)
+		}
+		if err != nil {
+			warn(fmt.Sprintf("Failed to read the lock holder: %v", err))
+		}
+		return false, holder, nil
+	}
+
+	claims[key] = lock
+	host, _ := os.Hostname()
+	data, err := json.Marshal(lockHolder{PID: os.Getpid(), Host: host, Since: time.Now()})
+	if err == nil {
+		err = os.WriteFile(ownerPath, data, 0600)
+	}
+	if err != nil {
+		warn(fmt.Sprintf("Failed to record this process as the lock holder: %v", err))
+	}
+	return true, lockHolder{}, nil
+}
+
+// claim reports whether this process may hunt the worker's account. When another copy
+// of the tool on this machine already hunts it with the same OCI user, the account is
+// skipped (and alerted on once) instead of doubling the request volume; it is picked up
+// again once the other copy exits. Locking problems never block provisioning.
+func (p *Provisioner) claim(w *AccountWorker) bool {
+	// Instance principals and session tokens have no user OCID to tell deployments
+	// apart, so they skip the check rather than block each other
+	if p.lockDir == "" || w.Config.TenancyOCID == "" || w.Config.UserOCID == "" {
+		return true
+	}
+	warn := func(msg string) { p.Logger.Warn(w.AccountName, msg) }
+	ok, holder, err := claimAccount(p.lockDir, lockKey(w.Config.TenancyOCID, w.Config.UserOCID), warn)
+	if err != nil {
+		p.Logger.Warn(w.AccountName, fmt.Sprintf("Duplicate check unavailable: %v", err))
+		return true
+	}
+	if ok {
+		if err := p.Notifier.ResolveAlert(notifier.ClassDuplicate, w.AccountName); err != nil {
+			p.Logger.Error(w.AccountName, fmt.Sprintf("Notification failed: %v", err))
+		}
+		return true
+	}
+
+	p.Logger.Warn(w.AccountName, fmt.Sprintf("👥 Already being hunted by %s - skipping to avoid doubling requests", holder))
+	if err := p.Notifier.SendAlert(notifier.Alert{
+		Class:   notifier.ClassDuplicate,
+		Account: w.AccountName,
+		Detail:  "Another provisioner is hunting with the same OCI user: " + holder.String(),
+	}); err != nil {
+		p.Logger.Error(w.AccountName, fmt.Sprintf("Notification failed: %v", err))
+	}
+	return false
+}
@@ -0,0 +1,13 @@
+package provisioner
+
+import (
+	"time"
+
+	"github.com/yourusername/oci-arm-provisioner/internal/mqtt"
+)
+
+// publish sends an event of the account to the MQTT broker, if one is configured.
+func (w *AccountWorker) publish(ev mqtt.Event) {
+	ev.Account, ev.Time, ev.AttemptID = w.AccountName, time.Now(), w.attemptID
+	w.Events.Publish(ev)
+}
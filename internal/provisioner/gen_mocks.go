@@ -0,0 +1,95 @@
+//go:build ignore
+
+// gen_mocks generates mocks_test.go from the *ClientOps interfaces in clients.go.
+// Each mock has one <Method>Func field per method; unset funcs return a zero response.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"log"
+	"os"
+	"strings"
+)
+
+const (
+	source = "clients.go"
+	output = "mocks_test.go"
+)
+
+func main() {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, source, nil, 0)
+	if err != nil {
+		log.Fatalf("parse %s: %v", source, err)
+	}
+
+	expr := func(n ast.Node) string {
+		var buf bytes.Buffer
+		printer.Fprint(&buf, fset, n)
+		return buf.String()
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by gen_mocks.go from %s; DO NOT EDIT.\n\n", source)
+	fmt.Fprintf(&out, "package %s\n\nimport (\n", file.Name.Name)
+	for _, imp := range file.Imports {
+		fmt.Fprintf(&out, "\t%s\n", imp.Path.Value)
+	}
+	out.WriteString(")\n")
+
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			ts := spec.(*ast.TypeSpec)
+			iface, ok := ts.Type.(*ast.InterfaceType)
+			if !ok || !strings.HasSuffix(ts.Name.Name, "ClientOps") {
+				continue
+			}
+
+			mock := "Mock" + strings.TrimSuffix(ts.Name.Name, "Ops")
+			fmt.Fprintf(&out, "\n// %s mocks the %s interface.\ntype %s struct {\n", mock, ts.Name.Name, mock)
+			for _, m := range iface.Methods.List {
+				fmt.Fprintf(&out, "\t%sFunc %s\n", m.Names[0].Name, expr(m.Type))
+			}
+			out.WriteString("}\n")
+
+			for _, m := range iface.Methods.List {
+				name := m.Names[0].Name
+				fn := m.Type.(*ast.FuncType)
+				if fn.Results == nil || len(fn.Results.List) != 2 {
+					log.Fatalf("%s.%s: expected (Response, error) results", ts.Name.Name, name)
+				}
+
+				var params, args []string
+				for _, p := range fn.Params.List {
+					for _, n := range p.Names {
+						params = append(params, n.Name+" "+expr(p.Type))
+						args = append(args, n.Name)
+					}
+				}
+				resp := expr(fn.Results.List[0].Type)
+
+				fmt.Fprintf(&out, "\nfunc (m *%s) %s(%s) (%s, error) {\n", mock, name, strings.Join(params, ", "), resp)
+				fmt.Fprintf(&out, "\tif m.%sFunc != nil {\n\t\treturn m.%sFunc(%s)\n\t}\n", name, name, strings.Join(args, ", "))
+				fmt.Fprintf(&out, "\treturn %s{}, nil\n}\n", resp)
+			}
+		}
+	}
+
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		log.Fatalf("format: %v\n%s", err, out.Bytes())
+	}
+	if err := os.WriteFile(output, src, 0644); err != nil {
+		log.Fatalf("write %s: %v", output, err)
+	}
+}
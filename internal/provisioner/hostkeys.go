@@ -0,0 +1,110 @@
+package provisioner
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/core"
+)
+
+// hostKeyWait bounds how long the success notification waits for cloud-init to print
+// the SSH host key fingerprints to the serial console.
+const hostKeyWait = 3 * time.Minute
+
+// consoleHistoryLength is how much of the console history is read, from the start.
+const consoleHistoryLength = 1 << 20
+
+// hostKeyLine matches a fingerprint line of cloud-init's console block, e.g.
+// "ci-info: 256 SHA256:AbC... root@arm (ED25519)".
+var hostKeyLine = regexp.MustCompile(`\d+ (SHA256:\S+) .*\((\w+)\)\s*$`)
+
+// parseHostKeys returns the fingerprints between cloud-init's BEGIN and END SSH HOST KEY
+// FINGERPRINTS markers, e.g. "ED25519 SHA256:AbC...".
+func parseHostKeys(console string) []string {
+	var keys []string
+	in := false
+	scanner := bufio.NewScanner(strings.NewReader(console))
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.Contains(line, "-----BEGIN SSH HOST KEY FINGERPRINTS-----"):
+			in, keys = true, nil
+		case strings.Contains(line, "-----END SSH HOST KEY FINGERPRINTS-----"):
+			return keys
+		case in:
+			if m := hostKeyLine.FindStringSubmatch(line); m != nil {
+				keys = append(keys, m[2]+" "+m[1])
+			}
+		}
+	}
+	return nil
+}
+
+// hostKeys waits for the instance's SSH host key fingerprints to appear on its serial
+// console, so the success notification can show them before the first connection.
+// It gives up after hostKeyWait, or at once if console history can't be captured.
+func (w *AccountWorker) hostKeys(ctx context.Context, instanceID string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, hostKeyWait)
+	defer cancel()
+	for {
+		console, err := w.consoleHistory(ctx, instanceID)
+		if err != nil {
+			return nil, err
+		}
+		if keys := parseHostKeys(console); len(keys) > 0 {
+			return keys, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("cloud-init did not print the host keys within %v", hostKeyWait)
+		case <-time.After(w.pollInterval()):
+		}
+	}
+}
+
+// consoleHistory captures and reads the instance's serial console output, then deletes
+// the capture.
+func (w *AccountWorker) consoleHistory(ctx context.Context, instanceID string) (string, error) {
+	resp, err := w.ComputeClient.CaptureConsoleHistory(ctx, core.CaptureConsoleHistoryRequest{
+		CaptureConsoleHistoryDetails: core.CaptureConsoleHistoryDetails{InstanceId: common.String(instanceID)},
+	})
+	w.observe(err)
+	if err != nil {
+		return "", fmt.Errorf("console history capture failed: %w", err)
+	}
+	if resp.Id == nil {
+		return "", fmt.Errorf("console history capture returned no ID")
+	}
+	id := resp.Id
+	defer w.ComputeClient.DeleteConsoleHistory(context.WithoutCancel(ctx), core.DeleteConsoleHistoryRequest{InstanceConsoleHistoryId: id})
+
+	for state := resp.LifecycleState; state != core.ConsoleHistoryLifecycleStateSucceeded; {
+		if state == core.ConsoleHistoryLifecycleStateFailed {
+			return "", fmt.Errorf("console history capture failed")
+		}
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(w.pollInterval()):
+		}
+		got, err := w.ComputeClient.GetConsoleHistory(ctx, core.GetConsoleHistoryRequest{InstanceConsoleHistoryId: id})
+		if err != nil {
+			return "", fmt.Errorf("console history capture failed: %w", err)
+		}
+		state = got.LifecycleState
+	}
+
+	content, err := w.ComputeClient.GetConsoleHistoryContent(ctx, core.GetConsoleHistoryContentRequest{
+		InstanceConsoleHistoryId: id,
+		Length:                   common.Int(consoleHistoryLength),
+	})
+	if err != nil {
+		return "", fmt.Errorf("console history read failed: %w", err)
+	}
+	return safeString(content.Value), nil
+}
@@ -0,0 +1,99 @@
+package provisioner
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/core"
+	"github.com/yourusername/oci-arm-provisioner/internal/mqtt"
+	"github.com/yourusername/oci-arm-provisioner/internal/notifier"
+)
+
+// maxHostnameSuffix bounds the search for a free label ("armvm-2" ... "armvm-99").
+const maxHostnameSuffix = 99
+
+// isHostnameConflict reports whether a launch was rejected because the VNIC hostname
+// label is already taken in the subnet, typically by a terminated instance's VNIC.
+func isHostnameConflict(err error) bool {
+	serviceErr, ok := common.IsServiceError(err)
+	if !ok || serviceErr.GetHTTPStatusCode() != 400 {
+		return false
+	}
+	msg := strings.ToLower(serviceErr.GetMessage())
+	return strings.Contains(msg, "hostname") && (strings.Contains(msg, "already") || strings.Contains(msg, "in use") || strings.Contains(msg, "conflict"))
+}
+
+// relaunchWithFreeHostname handles a launch rejected by a hostname label conflict. With
+// hostname_auto_suffix it retries once with the first free "<label>-N" in the subnet and
+// reports the change; otherwise it returns launchErr with a hint.
+func (w *AccountWorker) relaunchWithFreeHostname(ctx context.Context, req core.LaunchInstanceRequest, launchErr error) (core.LaunchInstanceResponse, error) {
+	label := *req.CreateVnicDetails.HostnameLabel
+	if !w.Config.HostnameAutoSuffix {
+		w.Logger.Error(w.AccountName, fmt.Sprintf("Hostname '%s' is already in use in the subnet - change hostname_label or set hostname_auto_suffix: true", label))
+		return core.LaunchInstanceResponse{}, launchErr
+	}
+
+	free, err := w.freeHostname(ctx, label)
+	w.observe(err)
+	if err != nil {
+		return core.LaunchInstanceResponse{}, fmt.Errorf("hostname '%s' is in use and no free label was found: %w", label, err)
+	}
+	w.Logger.Warn(w.AccountName, fmt.Sprintf("🏷️ Hostname '%s' is in use in the subnet - retrying as '%s'", label, free))
+	if err := w.Notifier.Send(notifier.Message{
+		Title: "🏷️ Hostname Adjusted",
+		Fields: []notifier.Field{
+			{Name: "Account", Value: w.AccountName},
+			{Name: "Requested", Value: label},
+			{Name: "Using", Value: free},
+			{Name: "Why", Value: "The label is still held by another VNIC in the subnet (often a terminated instance)."},
+		},
+		Color: notifier.ColorInfo,
+		Tags:  "label",
+	}); err != nil {
+		w.Logger.Error(w.AccountName, fmt.Sprintf("Notification failed: %v", err))
+	}
+
+	vnic := *req.CreateVnicDetails
+	vnic.HostnameLabel = common.String(free)
+	req.CreateVnicDetails = &vnic
+	if req.OpcRetryToken != nil {
+		req.OpcRetryToken = common.String(*req.OpcRetryToken + "-h")
+	}
+	w.Tracker.RecordAttemptIn(w.AccountName, *req.AvailabilityDomain)
+	w.publish(mqtt.Event{Type: mqtt.Attempt, Region: w.Config.Region})
+	resp, err := w.ComputeClient.LaunchInstance(ctx, req)
+	w.observe(err)
+	return resp, err
+}
+
+// freeHostname returns the first "<label>-N" not used by any private IP in the subnet.
+func (w *AccountWorker) freeHostname(ctx context.Context, label string) (string, error) {
+	used := map[string]bool{label: true}
+	req := core.ListPrivateIpsRequest{SubnetId: common.String(w.Config.SubnetOCID)}
+	for {
+		resp, err := w.VirtualNetworkClient.ListPrivateIps(ctx, req)
+		if err != nil {
+			return "", fmt.Errorf("failed to list subnet hostnames: %w", err)
+		}
+		for _, ip := range resp.Items {
+			if ip.HostnameLabel != nil {
+				used[strings.ToLower(*ip.HostnameLabel)] = true
+			}
+		}
+		if resp.OpcNextPage == nil {
+			break
+		}
+		req.Page = resp.OpcNextPage
+	}
+
+	for n := 2; n <= maxHostnameSuffix; n++ {
+		suffix := fmt.Sprintf("-%d", n)
+		candidate := strings.TrimRight(label[:min(len(label), 63-len(suffix))], "-") + suffix
+		if !used[candidate] {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("'%s-2' to '%s-%d' are all taken", label, label, maxHostnameSuffix)
+}
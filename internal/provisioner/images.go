@@ -0,0 +1,113 @@
+package provisioner
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/core"
+	"github.com/yourusername/oci-arm-provisioner/internal/config"
+)
+
+// imageCacheTTL is how long a resolved image alias is reused. Long hunts then pick up
+// new platform image releases without listing images every cycle.
+const imageCacheTTL = 24 * time.Hour
+
+// resolvedImage is the image an alias resolved to for a shape.
+type resolvedImage struct {
+	id, name string
+	at       time.Time
+}
+
+// imageID returns the image to launch shape with: image_ocid, or the newest platform
+// image matching the image alias, cached per shape. If the lookup fails, the last
+// resolved image is used.
+func (w *AccountWorker) imageID(ctx context.Context, shape string) (string, error) {
+	if w.Config.Image == "" {
+		return w.Config.ImageOCID, nil
+	}
+	alias, err := config.ParseImageAlias(w.Config.Image)
+	if err != nil {
+		return "", err
+	}
+	cached, ok := w.images[shape]
+	if ok && time.Since(cached.at) < imageCacheTTL {
+		return cached.id, nil
+	}
+
+	img, err := w.latestImage(ctx, alias, shape)
+	w.observe(err)
+	if err != nil {
+		if ok {
+			w.Logger.Warn(w.AccountName, fmt.Sprintf("Could not look for a newer %s image, keeping %s: %v", alias, cached.name, err))
+			return cached.id, nil
+		}
+		return "", fmt.Errorf("image %s: %w", w.Config.Image, err)
+	}
+
+	if w.images == nil {
+		w.images = make(map[string]resolvedImage)
+	}
+	name := *img.Id
+	if img.DisplayName != nil {
+		name = *img.DisplayName
+	}
+	if !ok || cached.id != *img.Id {
+		w.Logger.Info(w.AccountName, fmt.Sprintf("🖼️ Image %s for %s: %s", w.Config.Image, shape, name))
+	}
+	w.images[shape] = resolvedImage{id: *img.Id, name: name, at: time.Now()}
+	return *img.Id, nil
+}
+
+// latestImage lists the region's images of alias's operating system that run on shape,
+// newest first, and returns the first of alias's version.
+func (w *AccountWorker) latestImage(ctx context.Context, alias config.ImageAlias, shape string) (core.Image, error) {
+	compartment := w.Config.CompartmentOCID
+	if compartment == "" {
+		compartment = w.Config.TenancyOCID
+	}
+	req := core.ListImagesRequest{
+		CompartmentId:   common.String(compartment),
+		OperatingSystem: common.String(alias.OperatingSystem),
+		Shape:           common.String(shape),
+		LifecycleState:  core.ImageLifecycleStateAvailable,
+		SortBy:          core.ListImagesSortByTimecreated,
+		SortOrder:       core.ListImagesSortOrderDesc,
+	}
+	for {
+		resp, err := w.ComputeClient.ListImages(ctx, req)
+		if err != nil {
+			return core.Image{}, err
+		}
+		for _, img := range resp.Items {
+			if img.Id != nil && imageMatches(img, alias) {
+				return img, nil
+			}
+		}
+		if resp.OpcNextPage == nil {
+			break
+		}
+		req.Page = resp.OpcNextPage
+	}
+	return core.Image{}, fmt.Errorf("no %s image for %s in %s", alias, shape, w.Config.Region)
+}
+
+// imageMatches reports whether img is alias's version. Versions come as "22.04", "9" or,
+// for Ubuntu's Minimal images, "22.04 Minimal aarch64".
+func imageMatches(img core.Image, alias config.ImageAlias) bool {
+	if img.OperatingSystemVersion == nil {
+		return false
+	}
+	version := *img.OperatingSystemVersion
+	fields := strings.Fields(version)
+	if len(fields) == 0 || fields[0] != alias.Version {
+		return false
+	}
+	minimal := strings.Contains(strings.ToLower(version), "minimal")
+	if img.DisplayName != nil {
+		minimal = minimal || strings.Contains(strings.ToLower(*img.DisplayName), "minimal")
+	}
+	return minimal == alias.Minimal
+}
@@ -0,0 +1,71 @@
+package provisioner
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/yourusername/oci-arm-provisioner/internal/iphook"
+	"github.com/yourusername/oci-arm-provisioner/internal/state"
+)
+
+// trackInstance records inst in the state and, if its public IP is new or changed, runs
+// the ip_hook. It reports whether the instance was not known before.
+func (w *AccountWorker) trackInstance(ctx context.Context, inst state.Instance) (bool, error) {
+	previous := ""
+	for _, known := range w.State.Instances(w.AccountName) {
+		if known.ID == inst.ID {
+			previous = known.PublicIP
+		}
+	}
+	isNew, err := w.State.RecordInstance(w.AccountName, inst)
+
+	if inst.PublicIP != "" && inst.PublicIP != previous {
+		w.runIPHook(ctx, iphook.Event{Account: w.AccountName, InstanceID: inst.ID, PublicIP: inst.PublicIP, PreviousIP: previous})
+	}
+	return isNew, err
+}
+
+// runIPHook tells the user's firewall about a new public IP.
+func (w *AccountWorker) runIPHook(ctx context.Context, ev iphook.Event) {
+	if w.IPHook == nil {
+		return
+	}
+	ev.AttemptID = w.attemptID
+	if err := w.IPHook.Run(ctx, ev); err != nil {
+		w.Logger.Error(w.AccountName, fmt.Sprintf("IP hook failed for %s: %v", ev.PublicIP, err))
+		return
+	}
+	if ev.PreviousIP == "" {
+		w.Logger.Info(w.AccountName, fmt.Sprintf("🧱 IP hook ran for %s", ev.PublicIP))
+	} else {
+		w.Logger.Info(w.AccountName, fmt.Sprintf("🧱 IP hook ran: %s -> %s", ev.PreviousIP, ev.PublicIP))
+	}
+}
+
+// watchIPs re-reads the public IP of the account's recorded instances and runs the
+// ip_hook for any that changed (e.g. an ephemeral IP after a stop/start). It does nothing
+// unless a hook is configured, so it costs no API calls otherwise.
+func (w *AccountWorker) watchIPs(ctx context.Context) {
+	if w.IPHook == nil {
+		return
+	}
+	if err := w.initClients(); err != nil {
+		return
+	}
+	for _, inst := range w.State.Instances(w.AccountName) {
+		publicIP, _, err := w.primaryIPs(ctx, inst.ID)
+		w.observe(err)
+		if err != nil {
+			w.Logger.Warn(w.AccountName, fmt.Sprintf("Could not check the IP of %s: %v", inst.Name, err))
+			continue
+		}
+		if publicIP == "" || publicIP == inst.PublicIP {
+			continue
+		}
+		w.Logger.Warn(w.AccountName, fmt.Sprintf("Public IP of %s changed: %s -> %s", inst.Name, orNoIP(inst.PublicIP), publicIP))
+		inst.PublicIP = publicIP
+		if _, err := w.trackInstance(ctx, inst); err != nil {
+			w.Logger.Warn(w.AccountName, fmt.Sprintf("Failed to persist instance: %v", err))
+		}
+	}
+}
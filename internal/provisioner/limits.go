@@ -0,0 +1,186 @@
+package provisioner
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/limits"
+	"github.com/yourusername/oci-arm-provisioner/internal/notifier"
+)
+
+// limitName matches the service limit names listed in a LimitExceeded message, e.g.
+// "The following service limits were exceeded: standard-a1-core-count".
+var limitName = regexp.MustCompile(`\b[a-z0-9]+(?:-[a-z0-9]+)*-count\b`)
+
+// a1Limits are the compute limits an A1 launch counts against.
+var a1Limits = []string{"standard-a1-core-count", "standard-a1-memory-count"}
+
+// limitBlock is a service limit found exhausted, after a LimitExceeded response or by
+// the check_limits preflight.
+type limitBlock struct {
+	names []string // Limits named by the error.
+	ads   []string // ADs they were checked in; launches resume once one has room.
+}
+
+// limitNames returns the limits a LimitExceeded error is about. OCI lists them in the
+// message; A1 launches fall back to the A1 core and memory limits.
+func limitNames(err error, shape string) []string {
+	if serviceErr, ok := common.IsServiceError(err); ok {
+		if names := limitName.FindAllString(serviceErr.GetMessage(), -1); len(names) > 0 {
+			return names
+		}
+	}
+	if isA1Shape(shape) {
+		return a1Limits
+	}
+	return nil
+}
+
+// exhaustedLimits checks the named compute limits in ad and describes the ones without
+// room for ocpus/memory, e.g. "standard-a1-core-count (4 used, 0 available)".
+func (w *AccountWorker) exhaustedLimits(ctx context.Context, names []string, ad string, ocpus, memory float32) ([]string, error) {
+	if w.LimitsClient == nil {
+		return nil, fmt.Errorf("limits client not available")
+	}
+	var exhausted []string
+	for _, name := range names {
+		resp, err := w.LimitsClient.GetResourceAvailability(ctx, limits.GetResourceAvailabilityRequest{
+			ServiceName:        common.String("compute"),
+			LimitName:          common.String(name),
+			CompartmentId:      common.String(w.Config.TenancyOCID),
+			AvailabilityDomain: common.String(ad),
+		})
+		w.observe(err)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		need := int64(1)
+		switch {
+		case strings.HasSuffix(name, "-core-count"):
+			need = int64(ocpus)
+		case strings.HasSuffix(name, "-memory-count"):
+			need = int64(memory)
+		}
+		if available := safeInt64(resp.Available); available < need {
+			exhausted = append(exhausted, fmt.Sprintf("%s (%d used, %d available)", name, safeInt64(resp.Used), available))
+		}
+	}
+	return exhausted, nil
+}
+
+// limitExceeded handles a LimitExceeded response: if the limits it names are really used
+// up, the account stops launching until they have room again and the user is told once
+// what to do. It reports whether the account is blocked; otherwise the error is retried
+// like a capacity error (another launch may have been holding the quota).
+func (w *AccountWorker) limitExceeded(ctx context.Context, err error, shape, ad string, ocpus, memory float32) bool {
+	names := limitNames(err, shape)
+	if len(names) == 0 {
+		return false
+	}
+	exhausted, checkErr := w.exhaustedLimits(ctx, names, ad, ocpus, memory)
+	if checkErr != nil {
+		w.Logger.Warn(w.AccountName, fmt.Sprintf("Could not check service limits: %v", checkErr))
+		return false
+	}
+	if len(exhausted) == 0 {
+		return false
+	}
+
+	w.blockOnLimits(names, []string{ad}, exhausted, ocpus, memory)
+	return true
+}
+
+// limitsPreflight checks, with check_limits, that the A1 limits have room for the launch
+// before it is sent, and returns the ADs that do. None means the account is blocked until
+// limitBlocked finds room again. If the check fails, all ads are tried as usual.
+func (w *AccountWorker) limitsPreflight(ctx context.Context, shape string, ads []string, ocpus, memory float32) []string {
+	if !w.Config.CheckLimits || !isA1Shape(shape) {
+		return ads
+	}
+	var room, exhausted []string
+	for _, ad := range ads {
+		used, err := w.exhaustedLimits(ctx, a1Limits, ad, ocpus, memory)
+		if err != nil {
+			w.Logger.Warn(w.AccountName, fmt.Sprintf("Could not check service limits: %v", err))
+			return ads
+		}
+		if len(used) == 0 {
+			room = append(room, ad)
+		} else if exhausted == nil {
+			exhausted = used
+		}
+	}
+	if len(room) == 0 {
+		w.blockOnLimits(a1Limits, ads, exhausted, ocpus, memory)
+	}
+	return room
+}
+
+// blockOnLimits stops the account's launches until the exhausted limits have room in one
+// of ads, and tells the user once what to do.
+func (w *AccountWorker) blockOnLimits(names, ads, exhausted []string, ocpus, memory float32) {
+	w.limits = &limitBlock{names: names, ads: ads}
+	w.Tracker.SetLimited(w.AccountName, strings.Join(exhausted, ", "))
+	w.Logger.Error(w.AccountName, fmt.Sprintf("⛔ Service limit exhausted: %s. Launches stop until it has room.", strings.Join(exhausted, ", ")))
+	if w.limitAlerted {
+		return
+	}
+	w.limitAlerted = true
+	if err := w.Notifier.Send(notifier.Message{
+		Title: "⛔ Service Limit Exhausted",
+		Fields: []notifier.Field{
+			{Name: "Account", Value: w.AccountName},
+			{Name: "Limits", Value: strings.Join(exhausted, "\n")},
+			{Name: "Requested", Value: fmt.Sprintf("%.0f OCPUs / %.0f GB in %s", ocpus, memory, strings.Join(ads, ", "))},
+			{Name: "Hint", Value: "Terminate instances you no longer need, or request a service limit increase in the Console (Governance > Limits, Quotas and Usage). Launches resume once the limit has room."},
+		},
+		Color:    notifier.ColorError,
+		Priority: 4,
+		Tags:     "no_entry,warning",
+	}); err != nil {
+		w.Logger.Error(w.AccountName, fmt.Sprintf("Notification failed: %v", err))
+	}
+}
+
+// limitBlocked re-checks the limits that stopped the account. It reports whether they
+// are still exhausted in all of its ADs; once one has room the account launches again.
+func (w *AccountWorker) limitBlocked(ctx context.Context, ocpus, memory float32) bool {
+	if w.limits == nil {
+		return false
+	}
+	var exhausted []string
+	for _, ad := range w.limits.ads {
+		used, err := w.exhaustedLimits(ctx, w.limits.names, ad, ocpus, memory)
+		if err != nil {
+			w.Logger.Warn(w.AccountName, fmt.Sprintf("Could not check service limits: %v", err))
+			return true
+		}
+		if len(used) == 0 {
+			exhausted = nil
+			break
+		}
+		if exhausted == nil {
+			exhausted = used
+		}
+	}
+	if len(exhausted) > 0 {
+		w.Tracker.SetLimited(w.AccountName, strings.Join(exhausted, ", "))
+		w.Logger.Info(w.AccountName, fmt.Sprintf("⛔ Service limit still exhausted (%s) - skipping", strings.Join(exhausted, ", ")))
+		return true
+	}
+	w.Logger.Info(w.AccountName, "Service limit has room again - resuming launches")
+	w.limits, w.limitAlerted = nil, false
+	w.Tracker.SetLimited(w.AccountName, "")
+	return false
+}
+
+// safeInt64 safely dereferences an int64 pointer
+func safeInt64(n *int64) int64 {
+	if n == nil {
+		return 0
+	}
+	return *n
+}
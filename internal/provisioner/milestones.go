@@ -0,0 +1,67 @@
+package provisioner
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yourusername/oci-arm-provisioner/internal/notifier"
+)
+
+// checkMilestones sends a low-priority capacity alert when the account crosses the next
+// attempt or day threshold (see config.MilestoneConfig).
+func (w *AccountWorker) checkMilestones() {
+	if w.Milestones.Attempts <= 0 && w.Milestones.Days <= 0 {
+		return
+	}
+	stats := w.Tracker.Snapshot()
+	acc := stats.Accounts[w.AccountName]
+	hunting := time.Since(stats.StartTime)
+
+	var reached []string
+	if step := w.Milestones.Attempts; step > 0 {
+		if w.nextAttempts == 0 {
+			w.nextAttempts = step
+		}
+		if acc.Attempts >= w.nextAttempts {
+			reached = append(reached, fmt.Sprintf("%s attempts on account %s", formatCount(acc.Attempts), w.AccountName))
+			w.nextAttempts = (acc.Attempts/step + 1) * step
+		}
+	}
+	if step := w.Milestones.Days; step > 0 {
+		if w.nextDays == 0 {
+			w.nextDays = step
+		}
+		if days := int(hunting / (24 * time.Hour)); days >= w.nextDays {
+			reached = append(reached, fmt.Sprintf("%d days hunting, %s capacity errors", days, formatCount(acc.CapacityErrors)))
+			w.nextDays = (days/step + 1) * step
+		}
+	}
+	if len(reached) == 0 {
+		return
+	}
+
+	w.Logger.Info(w.AccountName, "🏁 Milestone: "+strings.Join(reached, "; "))
+	if err := w.Notifier.SendAlert(notifier.Alert{
+		Class:   notifier.ClassCapacity,
+		Account: w.AccountName,
+		Detail:  strings.Join(reached, "\n"),
+		Fields: []notifier.Field{
+			{Name: "Attempts", Value: formatCount(acc.Attempts)},
+			{Name: "Capacity Errors", Value: formatCount(acc.CapacityErrors)},
+			{Name: "Hunting For", Value: hunting.Round(time.Minute).String()},
+		},
+	}); err != nil {
+		w.Logger.Error(w.AccountName, fmt.Sprintf("Notification failed: %v", err))
+	}
+}
+
+// formatCount renders n with thousands separators, e.g. 1,000.
+func formatCount(n int) string {
+	s := strconv.Itoa(n)
+	for i := len(s) - 3; i > 0 && s[i-1] != '-'; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return s
+}
@@ -0,0 +1,298 @@
+// Code generated by gen_mocks.go from clients.go; DO NOT EDIT.
+
+package provisioner
+
+import (
+	"context"
+	"github.com/oracle/oci-go-sdk/v65/announcementsservice"
+	"github.com/oracle/oci-go-sdk/v65/core"
+	"github.com/oracle/oci-go-sdk/v65/identity"
+	"github.com/oracle/oci-go-sdk/v65/limits"
+	"github.com/oracle/oci-go-sdk/v65/secrets"
+	"github.com/oracle/oci-go-sdk/v65/usageapi"
+	"github.com/oracle/oci-go-sdk/v65/workrequests"
+)
+
+// MockComputeClient mocks the ComputeClientOps interface.
+type MockComputeClient struct {
+	LaunchInstanceFunc              func(ctx context.Context, request core.LaunchInstanceRequest) (core.LaunchInstanceResponse, error)
+	ListInstancesFunc               func(ctx context.Context, request core.ListInstancesRequest) (core.ListInstancesResponse, error)
+	GetInstanceFunc                 func(ctx context.Context, request core.GetInstanceRequest) (core.GetInstanceResponse, error)
+	TerminateInstanceFunc           func(ctx context.Context, request core.TerminateInstanceRequest) (core.TerminateInstanceResponse, error)
+	ListVnicAttachmentsFunc         func(ctx context.Context, request core.ListVnicAttachmentsRequest) (core.ListVnicAttachmentsResponse, error)
+	ListBootVolumeAttachmentsFunc   func(ctx context.Context, request core.ListBootVolumeAttachmentsRequest) (core.ListBootVolumeAttachmentsResponse, error)
+	ListImagesFunc                  func(ctx context.Context, request core.ListImagesRequest) (core.ListImagesResponse, error)
+	CaptureConsoleHistoryFunc       func(ctx context.Context, request core.CaptureConsoleHistoryRequest) (core.CaptureConsoleHistoryResponse, error)
+	GetConsoleHistoryFunc           func(ctx context.Context, request core.GetConsoleHistoryRequest) (core.GetConsoleHistoryResponse, error)
+	GetConsoleHistoryContentFunc    func(ctx context.Context, request core.GetConsoleHistoryContentRequest) (core.GetConsoleHistoryContentResponse, error)
+	DeleteConsoleHistoryFunc        func(ctx context.Context, request core.DeleteConsoleHistoryRequest) (core.DeleteConsoleHistoryResponse, error)
+	CreateComputeCapacityReportFunc func(ctx context.Context, request core.CreateComputeCapacityReportRequest) (core.CreateComputeCapacityReportResponse, error)
+}
+
+func (m *MockComputeClient) LaunchInstance(ctx context.Context, request core.LaunchInstanceRequest) (core.LaunchInstanceResponse, error) {
+	if m.LaunchInstanceFunc != nil {
+		return m.LaunchInstanceFunc(ctx, request)
+	}
+	return core.LaunchInstanceResponse{}, nil
+}
+
+func (m *MockComputeClient) ListInstances(ctx context.Context, request core.ListInstancesRequest) (core.ListInstancesResponse, error) {
+	if m.ListInstancesFunc != nil {
+		return m.ListInstancesFunc(ctx, request)
+	}
+	return core.ListInstancesResponse{}, nil
+}
+
+func (m *MockComputeClient) GetInstance(ctx context.Context, request core.GetInstanceRequest) (core.GetInstanceResponse, error) {
+	if m.GetInstanceFunc != nil {
+		return m.GetInstanceFunc(ctx, request)
+	}
+	return core.GetInstanceResponse{}, nil
+}
+
+func (m *MockComputeClient) TerminateInstance(ctx context.Context, request core.TerminateInstanceRequest) (core.TerminateInstanceResponse, error) {
+	if m.TerminateInstanceFunc != nil {
+		return m.TerminateInstanceFunc(ctx, request)
+	}
+	return core.TerminateInstanceResponse{}, nil
+}
+
+func (m *MockComputeClient) ListVnicAttachments(ctx context.Context, request core.ListVnicAttachmentsRequest) (core.ListVnicAttachmentsResponse, error) {
+	if m.ListVnicAttachmentsFunc != nil {
+		return m.ListVnicAttachmentsFunc(ctx, request)
+	}
+	return core.ListVnicAttachmentsResponse{}, nil
+}
+
+func (m *MockComputeClient) ListBootVolumeAttachments(ctx context.Context, request core.ListBootVolumeAttachmentsRequest) (core.ListBootVolumeAttachmentsResponse, error) {
+	if m.ListBootVolumeAttachmentsFunc != nil {
+		return m.ListBootVolumeAttachmentsFunc(ctx, request)
+	}
+	return core.ListBootVolumeAttachmentsResponse{}, nil
+}
+
+func (m *MockComputeClient) ListImages(ctx context.Context, request core.ListImagesRequest) (core.ListImagesResponse, error) {
+	if m.ListImagesFunc != nil {
+		return m.ListImagesFunc(ctx, request)
+	}
+	return core.ListImagesResponse{}, nil
+}
+
+func (m *MockComputeClient) CaptureConsoleHistory(ctx context.Context, request core.CaptureConsoleHistoryRequest) (core.CaptureConsoleHistoryResponse, error) {
+	if m.CaptureConsoleHistoryFunc != nil {
+		return m.CaptureConsoleHistoryFunc(ctx, request)
+	}
+	return core.CaptureConsoleHistoryResponse{}, nil
+}
+
+func (m *MockComputeClient) GetConsoleHistory(ctx context.Context, request core.GetConsoleHistoryRequest) (core.GetConsoleHistoryResponse, error) {
+	if m.GetConsoleHistoryFunc != nil {
+		return m.GetConsoleHistoryFunc(ctx, request)
+	}
+	return core.GetConsoleHistoryResponse{}, nil
+}
+
+func (m *MockComputeClient) GetConsoleHistoryContent(ctx context.Context, request core.GetConsoleHistoryContentRequest) (core.GetConsoleHistoryContentResponse, error) {
+	if m.GetConsoleHistoryContentFunc != nil {
+		return m.GetConsoleHistoryContentFunc(ctx, request)
+	}
+	return core.GetConsoleHistoryContentResponse{}, nil
+}
+
+func (m *MockComputeClient) DeleteConsoleHistory(ctx context.Context, request core.DeleteConsoleHistoryRequest) (core.DeleteConsoleHistoryResponse, error) {
+	if m.DeleteConsoleHistoryFunc != nil {
+		return m.DeleteConsoleHistoryFunc(ctx, request)
+	}
+	return core.DeleteConsoleHistoryResponse{}, nil
+}
+
+func (m *MockComputeClient) CreateComputeCapacityReport(ctx context.Context, request core.CreateComputeCapacityReportRequest) (core.CreateComputeCapacityReportResponse, error) {
+	if m.CreateComputeCapacityReportFunc != nil {
+		return m.CreateComputeCapacityReportFunc(ctx, request)
+	}
+	return core.CreateComputeCapacityReportResponse{}, nil
+}
+
+// MockVirtualNetworkClient mocks the VirtualNetworkClientOps interface.
+type MockVirtualNetworkClient struct {
+	GetVnicFunc                  func(ctx context.Context, request core.GetVnicRequest) (core.GetVnicResponse, error)
+	GetSubnetFunc                func(ctx context.Context, request core.GetSubnetRequest) (core.GetSubnetResponse, error)
+	ListPrivateIpsFunc           func(ctx context.Context, request core.ListPrivateIpsRequest) (core.ListPrivateIpsResponse, error)
+	GetPublicIpFunc              func(ctx context.Context, request core.GetPublicIpRequest) (core.GetPublicIpResponse, error)
+	GetPublicIpByPrivateIpIdFunc func(ctx context.Context, request core.GetPublicIpByPrivateIpIdRequest) (core.GetPublicIpByPrivateIpIdResponse, error)
+	ListPublicIpsFunc            func(ctx context.Context, request core.ListPublicIpsRequest) (core.ListPublicIpsResponse, error)
+	CreatePublicIpFunc           func(ctx context.Context, request core.CreatePublicIpRequest) (core.CreatePublicIpResponse, error)
+	UpdatePublicIpFunc           func(ctx context.Context, request core.UpdatePublicIpRequest) (core.UpdatePublicIpResponse, error)
+	DeletePublicIpFunc           func(ctx context.Context, request core.DeletePublicIpRequest) (core.DeletePublicIpResponse, error)
+}
+
+func (m *MockVirtualNetworkClient) GetVnic(ctx context.Context, request core.GetVnicRequest) (core.GetVnicResponse, error) {
+	if m.GetVnicFunc != nil {
+		return m.GetVnicFunc(ctx, request)
+	}
+	return core.GetVnicResponse{}, nil
+}
+
+func (m *MockVirtualNetworkClient) GetSubnet(ctx context.Context, request core.GetSubnetRequest) (core.GetSubnetResponse, error) {
+	if m.GetSubnetFunc != nil {
+		return m.GetSubnetFunc(ctx, request)
+	}
+	return core.GetSubnetResponse{}, nil
+}
+
+func (m *MockVirtualNetworkClient) ListPrivateIps(ctx context.Context, request core.ListPrivateIpsRequest) (core.ListPrivateIpsResponse, error) {
+	if m.ListPrivateIpsFunc != nil {
+		return m.ListPrivateIpsFunc(ctx, request)
+	}
+	return core.ListPrivateIpsResponse{}, nil
+}
+
+func (m *MockVirtualNetworkClient) GetPublicIp(ctx context.Context, request core.GetPublicIpRequest) (core.GetPublicIpResponse, error) {
+	if m.GetPublicIpFunc != nil {
+		return m.GetPublicIpFunc(ctx, request)
+	}
+	return core.GetPublicIpResponse{}, nil
+}
+
+func (m *MockVirtualNetworkClient) GetPublicIpByPrivateIpId(ctx context.Context, request core.GetPublicIpByPrivateIpIdRequest) (core.GetPublicIpByPrivateIpIdResponse, error) {
+	if m.GetPublicIpByPrivateIpIdFunc != nil {
+		return m.GetPublicIpByPrivateIpIdFunc(ctx, request)
+	}
+	return core.GetPublicIpByPrivateIpIdResponse{}, nil
+}
+
+func (m *MockVirtualNetworkClient) ListPublicIps(ctx context.Context, request core.ListPublicIpsRequest) (core.ListPublicIpsResponse, error) {
+	if m.ListPublicIpsFunc != nil {
+		return m.ListPublicIpsFunc(ctx, request)
+	}
+	return core.ListPublicIpsResponse{}, nil
+}
+
+func (m *MockVirtualNetworkClient) CreatePublicIp(ctx context.Context, request core.CreatePublicIpRequest) (core.CreatePublicIpResponse, error) {
+	if m.CreatePublicIpFunc != nil {
+		return m.CreatePublicIpFunc(ctx, request)
+	}
+	return core.CreatePublicIpResponse{}, nil
+}
+
+func (m *MockVirtualNetworkClient) UpdatePublicIp(ctx context.Context, request core.UpdatePublicIpRequest) (core.UpdatePublicIpResponse, error) {
+	if m.UpdatePublicIpFunc != nil {
+		return m.UpdatePublicIpFunc(ctx, request)
+	}
+	return core.UpdatePublicIpResponse{}, nil
+}
+
+func (m *MockVirtualNetworkClient) DeletePublicIp(ctx context.Context, request core.DeletePublicIpRequest) (core.DeletePublicIpResponse, error) {
+	if m.DeletePublicIpFunc != nil {
+		return m.DeletePublicIpFunc(ctx, request)
+	}
+	return core.DeletePublicIpResponse{}, nil
+}
+
+// MockBlockstorageClient mocks the BlockstorageClientOps interface.
+type MockBlockstorageClient struct {
+	GetBootVolumeFunc func(ctx context.Context, request core.GetBootVolumeRequest) (core.GetBootVolumeResponse, error)
+}
+
+func (m *MockBlockstorageClient) GetBootVolume(ctx context.Context, request core.GetBootVolumeRequest) (core.GetBootVolumeResponse, error) {
+	if m.GetBootVolumeFunc != nil {
+		return m.GetBootVolumeFunc(ctx, request)
+	}
+	return core.GetBootVolumeResponse{}, nil
+}
+
+// MockIdentityClient mocks the IdentityClientOps interface.
+type MockIdentityClient struct {
+	ListAvailabilityDomainsFunc func(ctx context.Context, request identity.ListAvailabilityDomainsRequest) (identity.ListAvailabilityDomainsResponse, error)
+	ListRegionSubscriptionsFunc func(ctx context.Context, request identity.ListRegionSubscriptionsRequest) (identity.ListRegionSubscriptionsResponse, error)
+	ListFaultDomainsFunc        func(ctx context.Context, request identity.ListFaultDomainsRequest) (identity.ListFaultDomainsResponse, error)
+}
+
+func (m *MockIdentityClient) ListAvailabilityDomains(ctx context.Context, request identity.ListAvailabilityDomainsRequest) (identity.ListAvailabilityDomainsResponse, error) {
+	if m.ListAvailabilityDomainsFunc != nil {
+		return m.ListAvailabilityDomainsFunc(ctx, request)
+	}
+	return identity.ListAvailabilityDomainsResponse{}, nil
+}
+
+func (m *MockIdentityClient) ListRegionSubscriptions(ctx context.Context, request identity.ListRegionSubscriptionsRequest) (identity.ListRegionSubscriptionsResponse, error) {
+	if m.ListRegionSubscriptionsFunc != nil {
+		return m.ListRegionSubscriptionsFunc(ctx, request)
+	}
+	return identity.ListRegionSubscriptionsResponse{}, nil
+}
+
+func (m *MockIdentityClient) ListFaultDomains(ctx context.Context, request identity.ListFaultDomainsRequest) (identity.ListFaultDomainsResponse, error) {
+	if m.ListFaultDomainsFunc != nil {
+		return m.ListFaultDomainsFunc(ctx, request)
+	}
+	return identity.ListFaultDomainsResponse{}, nil
+}
+
+// MockWorkRequestClient mocks the WorkRequestClientOps interface.
+type MockWorkRequestClient struct {
+	GetWorkRequestFunc        func(ctx context.Context, request workrequests.GetWorkRequestRequest) (workrequests.GetWorkRequestResponse, error)
+	ListWorkRequestErrorsFunc func(ctx context.Context, request workrequests.ListWorkRequestErrorsRequest) (workrequests.ListWorkRequestErrorsResponse, error)
+}
+
+func (m *MockWorkRequestClient) GetWorkRequest(ctx context.Context, request workrequests.GetWorkRequestRequest) (workrequests.GetWorkRequestResponse, error) {
+	if m.GetWorkRequestFunc != nil {
+		return m.GetWorkRequestFunc(ctx, request)
+	}
+	return workrequests.GetWorkRequestResponse{}, nil
+}
+
+func (m *MockWorkRequestClient) ListWorkRequestErrors(ctx context.Context, request workrequests.ListWorkRequestErrorsRequest) (workrequests.ListWorkRequestErrorsResponse, error) {
+	if m.ListWorkRequestErrorsFunc != nil {
+		return m.ListWorkRequestErrorsFunc(ctx, request)
+	}
+	return workrequests.ListWorkRequestErrorsResponse{}, nil
+}
+
+// MockAnnouncementClient mocks the AnnouncementClientOps interface.
+type MockAnnouncementClient struct {
+	ListAnnouncementsFunc func(ctx context.Context, request announcementsservice.ListAnnouncementsRequest) (announcementsservice.ListAnnouncementsResponse, error)
+}
+
+func (m *MockAnnouncementClient) ListAnnouncements(ctx context.Context, request announcementsservice.ListAnnouncementsRequest) (announcementsservice.ListAnnouncementsResponse, error) {
+	if m.ListAnnouncementsFunc != nil {
+		return m.ListAnnouncementsFunc(ctx, request)
+	}
+	return announcementsservice.ListAnnouncementsResponse{}, nil
+}
+
+// MockUsageClient mocks the UsageClientOps interface.
+type MockUsageClient struct {
+	RequestSummarizedUsagesFunc func(ctx context.Context, request usageapi.RequestSummarizedUsagesRequest) (usageapi.RequestSummarizedUsagesResponse, error)
+}
+
+func (m *MockUsageClient) RequestSummarizedUsages(ctx context.Context, request usageapi.RequestSummarizedUsagesRequest) (usageapi.RequestSummarizedUsagesResponse, error) {
+	if m.RequestSummarizedUsagesFunc != nil {
+		return m.RequestSummarizedUsagesFunc(ctx, request)
+	}
+	return usageapi.RequestSummarizedUsagesResponse{}, nil
+}
+
+// MockLimitsClient mocks the LimitsClientOps interface.
+type MockLimitsClient struct {
+	GetResourceAvailabilityFunc func(ctx context.Context, request limits.GetResourceAvailabilityRequest) (limits.GetResourceAvailabilityResponse, error)
+}
+
+func (m *MockLimitsClient) GetResourceAvailability(ctx context.Context, request limits.GetResourceAvailabilityRequest) (limits.GetResourceAvailabilityResponse, error) {
+	if m.GetResourceAvailabilityFunc != nil {
+		return m.GetResourceAvailabilityFunc(ctx, request)
+	}
+	return limits.GetResourceAvailabilityResponse{}, nil
+}
+
+// MockSecretsClient mocks the SecretsClientOps interface.
+type MockSecretsClient struct {
+	GetSecretBundleFunc func(ctx context.Context, request secrets.GetSecretBundleRequest) (secrets.GetSecretBundleResponse, error)
+}
+
+func (m *MockSecretsClient) GetSecretBundle(ctx context.Context, request secrets.GetSecretBundleRequest) (secrets.GetSecretBundleResponse, error) {
+	if m.GetSecretBundleFunc != nil {
+		return m.GetSecretBundleFunc(ctx, request)
+	}
+	return secrets.GetSecretBundleResponse{}, nil
+}
@@ -0,0 +1,156 @@
+package provisioner
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/yourusername/oci-arm-provisioner/internal/errorclass"
+)
+
+// Pacer shares a request budget between accounts that belong to the same tenancy.
+// OCI rate limits are mostly per tenancy, so two "accounts" for different users of one
+// tenancy hitting the API back to back only earn each other 429s. The Pacer serializes
+// their attempts, spaces them at least Interval apart, and backs the whole tenancy off
+// (doubling up to maxPacerBackoff) when any of them is rate limited.
+// A nil *Pacer is valid and never waits.
+type Pacer struct {
+	mu        sync.Mutex
+	interval  time.Duration
+	tenancies map[string]*tenancyBudget
+	now       func() time.Time
+	sleep     func(ctx context.Context, d time.Duration) error
+}
+
+// tenancyBudget is the pacing state of one tenancy.
+type tenancyBudget struct {
+	turn     chan struct{} // Held by the account currently attempting.
+	shared   bool          // More than one account uses the tenancy; Interval applies.
+	last     time.Time     // End of the previous attempt.
+	holdoff  time.Time     // No attempts before this (429 backoff).
+	backoff  time.Duration // Current 429 backoff; doubles on repeats.
+	accounts []string
+}
+
+const (
+	minPacerBackoff = time.Minute
+	maxPacerBackoff = 15 * time.Minute
+)
+
+// NewPacer creates a Pacer for the given account -> tenancy OCID mapping. Accounts of
+// one tenancy are kept interval apart; tenancies with a single account are only subject
+// to the 429 backoff.
+func NewPacer(tenancies map[string]string, interval time.Duration) *Pacer {
+	p := &Pacer{
+		interval:  interval,
+		tenancies: make(map[string]*tenancyBudget),
+		now:       time.Now,
+		sleep:     sleepCtx,
+	}
+	for account, tenancy := range tenancies {
+		b := p.tenancies[tenancy]
+		if b == nil {
+			b = &tenancyBudget{turn: make(chan struct{}, 1)}
+			p.tenancies[tenancy] = b
+		}
+		b.accounts = append(b.accounts, account)
+		b.shared = len(b.accounts) > 1
+		sort.Strings(b.accounts)
+	}
+	return p
+}
+
+// Wait blocks until tenancy may make its next attempt and takes its turn. The returned
+// duration is how long it had to wait for the budget (not for another account's turn).
+// Every successful Wait must be paired with Done.
+func (p *Pacer) Wait(ctx context.Context, tenancy string) (time.Duration, error) {
+	b := p.budget(tenancy)
+	if b == nil {
+		return 0, nil
+	}
+	select {
+	case b.turn <- struct{}{}:
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+
+	p.mu.Lock()
+	ready := b.holdoff
+	if b.shared && !b.last.IsZero() {
+		if next := b.last.Add(p.interval); next.After(ready) {
+			ready = next
+		}
+	}
+	wait := ready.Sub(p.now())
+	p.mu.Unlock()
+
+	if wait <= 0 {
+		return 0, nil
+	}
+	if err := p.sleep(ctx, wait); err != nil {
+		<-b.turn
+		return 0, err
+	}
+	return wait, nil
+}
+
+// Done ends the attempt started by Wait and frees the tenancy for the next account.
+func (p *Pacer) Done(tenancy string) {
+	b := p.budget(tenancy)
+	if b == nil {
+		return
+	}
+	p.mu.Lock()
+	b.last = p.now()
+	p.mu.Unlock()
+	<-b.turn
+}
+
+// Observe feeds an API call outcome into the tenancy's budget: a 429 pushes back every
+// account of the tenancy, and a successful call ends the backoff streak. It returns the
+// backoff applied, or 0.
+func (p *Pacer) Observe(tenancy string, err error) time.Duration {
+	b := p.budget(tenancy)
+	if b == nil {
+		return 0
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if errorclass.Of(err) != errorclass.RateLimit {
+		if err == nil {
+			b.backoff = 0
+		}
+		return 0
+	}
+	b.backoff = min(max(2*b.backoff, minPacerBackoff), maxPacerBackoff)
+	b.holdoff = p.now().Add(b.backoff)
+	return b.backoff
+}
+
+// Accounts returns the accounts sharing tenancy's budget.
+func (p *Pacer) Accounts(tenancy string) []string {
+	if b := p.budget(tenancy); b != nil {
+		return b.accounts
+	}
+	return nil
+}
+
+func (p *Pacer) budget(tenancy string) *tenancyBudget {
+	if p == nil {
+		return nil
+	}
+	return p.tenancies[tenancy]
+}
+
+// sleepCtx waits for d or until ctx is done.
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
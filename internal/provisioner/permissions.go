@@ -0,0 +1,150 @@
+package provisioner
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/core"
+	"github.com/yourusername/oci-arm-provisioner/internal/notifier"
+)
+
+// isNotAuthorizedOrNotFound reports whether err is OCI's 404, which it returns both for
+// resources that don't exist and for ones the user may not see.
+func isNotAuthorizedOrNotFound(err error) bool {
+	serviceErr, ok := common.IsServiceError(err)
+	return ok && serviceErr.GetHTTPStatusCode() == 404
+}
+
+// missingPolicy is a permission check OCI answered with NotAuthorizedOrNotFound.
+type missingPolicy struct {
+	What      string // What the provisioner could not do.
+	Statement string // The policy statement that allows it.
+}
+
+// policyCheck is a read-only call standing in for a permission a launch needs.
+type policyCheck struct {
+	what   string
+	verb   string // e.g. "manage instance-family"
+	needed func(w *AccountWorker) bool
+	call   func(ctx context.Context, w *AccountWorker) error
+}
+
+// policyChecks are run before the first launch, after the existing-instance check has
+// listed the compartment's instances. Reading proves little about "use" or "manage",
+// but a 404 here means the launch can't work either.
+var policyChecks = []policyCheck{
+	{
+		what: "read subnet_ocid",
+		verb: "use virtual-network-family",
+		call: func(ctx context.Context, w *AccountWorker) error {
+			_, err := w.VirtualNetworkClient.GetSubnet(ctx, core.GetSubnetRequest{SubnetId: common.String(w.Config.SubnetOCID)})
+			return err
+		},
+	},
+	{
+		what:   "list reserved public IPs",
+		verb:   "manage public-ips",
+		needed: func(w *AccountWorker) bool { return w.Config.ReservedPublicIP != "" },
+		call: func(ctx context.Context, w *AccountWorker) error {
+			_, err := w.VirtualNetworkClient.ListPublicIps(ctx, core.ListPublicIpsRequest{
+				Scope:         core.ListPublicIpsScopeRegion,
+				Lifetime:      core.ListPublicIpsLifetimeReserved,
+				CompartmentId: common.String(w.compartment()),
+				Limit:         common.Int(1),
+			})
+			return err
+		},
+	},
+}
+
+// launchPolicies are the verbs OCI documents for "let users launch instances".
+var launchPolicies = []string{"manage instance-family", "use volume-family", "use virtual-network-family"}
+
+// compartment is where instances are launched; the tenancy when none is set.
+func (w *AccountWorker) compartment() string {
+	if w.Config.CompartmentOCID != "" {
+		return w.Config.CompartmentOCID
+	}
+	return w.Config.TenancyOCID
+}
+
+// policyStatement renders "Allow group <your-group> to <verb> in <scope>" for the account.
+func (w *AccountWorker) policyStatement(verb string) string {
+	subject := "group <your-group>"
+	if w.Config.UsesInstancePrincipal() {
+		subject = "dynamic-group <your-dynamic-group>"
+	}
+	scope := "tenancy"
+	if c := w.compartment(); c != w.Config.TenancyOCID {
+		scope = "compartment id " + c
+	}
+	return fmt.Sprintf("Allow %s to %s in %s", subject, verb, scope)
+}
+
+// checkPolicies runs the permission checks and returns those OCI refused. Other
+// errors (network, authentication) are left to the calls that follow.
+func (w *AccountWorker) checkPolicies(ctx context.Context) []missingPolicy {
+	var missing []missingPolicy
+	for _, c := range policyChecks {
+		if c.needed != nil && !c.needed(w) {
+			continue
+		}
+		err := c.call(ctx, w)
+		w.observe(err)
+		if isNotAuthorizedOrNotFound(err) {
+			missing = append(missing, missingPolicy{What: c.what, Statement: w.policyStatement(c.verb)})
+		}
+	}
+	return missing
+}
+
+// policiesBlocked checks the account's IAM policies until they pass once, or again after
+// a launch was refused. listErr is the result of listing the compartment's instances.
+// It reports the statements to add and returns an error while some are missing.
+func (w *AccountWorker) policiesBlocked(ctx context.Context, listErr error) error {
+	if w.policiesOK && listErr == nil {
+		return nil
+	}
+	var missing []missingPolicy
+	if isNotAuthorizedOrNotFound(listErr) {
+		missing = append(missing, missingPolicy{What: "list instances in the compartment", Statement: w.policyStatement("manage instance-family")})
+	}
+	missing = append(missing, w.checkPolicies(ctx)...)
+	if len(missing) == 0 {
+		w.policiesOK = true
+		if err := w.Notifier.ResolveAlert(notifier.ClassPermissions, w.AccountName); err != nil {
+			w.Logger.Error(w.AccountName, fmt.Sprintf("Notification failed: %v", err))
+		}
+		return nil
+	}
+
+	var what, statements []string
+	for _, m := range missing {
+		what = append(what, m.What)
+		statements = append(statements, m.Statement)
+		w.Logger.Error(w.AccountName, fmt.Sprintf("🛂 Can't %s. Add this policy (or check the OCID): %s", m.What, m.Statement))
+	}
+	if err := w.Notifier.SendAlert(notifier.Alert{
+		Class:   notifier.ClassPermissions,
+		Account: w.AccountName,
+		Detail:  "NotAuthorizedOrNotFound: can't " + strings.Join(what, ", "),
+		Fields:  []notifier.Field{{Name: "Policy Statements", Value: strings.Join(statements, "\n")}},
+	}); err != nil {
+		w.Logger.Error(w.AccountName, fmt.Sprintf("Notification failed: %v", err))
+	}
+	return fmt.Errorf("missing IAM policies: %s", strings.Join(statements, "; "))
+}
+
+// explainLaunchNotFound turns a NotAuthorizedOrNotFound launch error into what to check.
+// The checks run again next cycle.
+func (w *AccountWorker) explainLaunchNotFound(err error) error {
+	w.policiesOK = false
+	var statements []string
+	for _, verb := range launchPolicies {
+		statements = append(statements, w.policyStatement(verb))
+	}
+	return fmt.Errorf("launch refused with NotAuthorizedOrNotFound: check that the image, subnet_ocid and compartment_ocid exist in %s, and that these policies are in place: %s: %w",
+		w.Config.Region, strings.Join(statements, "; "), err)
+}
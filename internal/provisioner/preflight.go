@@ -0,0 +1,177 @@
+package provisioner
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/core"
+	"github.com/oracle/oci-go-sdk/v65/usageapi"
+	"github.com/yourusername/oci-arm-provisioner/internal/config"
+	"github.com/yourusername/oci-arm-provisioner/internal/notifier"
+)
+
+// Always Free Ampere A1 allotment per tenancy.
+const (
+	FreeTierOCPUs    = 4
+	FreeTierMemoryGB = 24
+)
+
+// isA1Shape reports whether shape counts against the Ampere A1 free-tier allotment.
+func isA1Shape(shape string) bool {
+	return strings.Contains(shape, ".A1.")
+}
+
+// a1Usage sums the OCPUs and memory of active A1 instances in the tenancy root
+// and the configured compartment. Instances in other compartments are not counted.
+func (w *AccountWorker) a1Usage(ctx context.Context) (float32, float32, error) {
+	compartments := []string{w.Config.TenancyOCID}
+	if w.Config.CompartmentOCID != "" && w.Config.CompartmentOCID != w.Config.TenancyOCID {
+		compartments = append(compartments, w.Config.CompartmentOCID)
+	}
+
+	var ocpus, memory float32
+	for _, compartment := range compartments {
+		req := core.ListInstancesRequest{CompartmentId: common.String(compartment)}
+		for {
+			resp, err := w.ComputeClient.ListInstances(ctx, req)
+			if err != nil {
+				return 0, 0, err
+			}
+			for _, inst := range resp.Items {
+				if !isA1Shape(safeString(inst.Shape)) || inst.ShapeConfig == nil {
+					continue
+				}
+				if inst.LifecycleState == core.InstanceLifecycleStateTerminated ||
+					inst.LifecycleState == core.InstanceLifecycleStateTerminating {
+					continue
+				}
+				if inst.ShapeConfig.Ocpus != nil {
+					ocpus += *inst.ShapeConfig.Ocpus
+				}
+				if inst.ShapeConfig.MemoryInGBs != nil {
+					memory += *inst.ShapeConfig.MemoryInGBs
+				}
+			}
+			if resp.OpcNextPage == nil {
+				break
+			}
+			req.Page = resp.OpcNextPage
+		}
+	}
+	return ocpus, memory, nil
+}
+
+// preflight checks a launch of shape against what is left of the free-tier A1 allotment.
+// It returns the specs to launch with, or ok=false when the launch must be skipped.
+// With auto_shrink the request is reduced to fit, otherwise the user is alerted (once).
+// Accounts with free_tier_guard: false launch as requested.
+func (w *AccountWorker) preflight(ctx context.Context, shape config.ShapeOption) (ocpus, memory float32, ok bool, err error) {
+	wantOCPUs, wantMemory := shape.OCPUs, shape.MemoryGB
+	if !isA1Shape(shape.Shape) || !w.Config.GuardsFreeTier() {
+		return wantOCPUs, wantMemory, true, nil
+	}
+
+	usedOCPUs, usedMemory, err := w.a1Usage(ctx)
+	if err != nil {
+		return 0, 0, false, fmt.Errorf("preflight usage check failed: %w", err)
+	}
+	freeOCPUs, freeMemory := FreeTierOCPUs-usedOCPUs, FreeTierMemoryGB-usedMemory
+	if wantOCPUs <= freeOCPUs && wantMemory <= freeMemory {
+		return wantOCPUs, wantMemory, true, nil
+	}
+
+	usage := fmt.Sprintf("%.0f/%d OCPUs, %.0f/%d GB already in use", usedOCPUs, FreeTierOCPUs, usedMemory, FreeTierMemoryGB)
+	if w.Config.AutoShrink && freeOCPUs >= 1 && freeMemory >= 1 {
+		ocpus, memory = min(wantOCPUs, freeOCPUs), min(wantMemory, freeMemory)
+		w.Logger.Warn(w.AccountName, fmt.Sprintf("Free tier partly used (%s). Shrinking request to %.0f OCPUs / %.0f GB.", usage, ocpus, memory))
+		return ocpus, memory, true, nil
+	}
+
+	w.Logger.Warn(w.AccountName, fmt.Sprintf("Request (%.0f OCPUs / %.0f GB) exceeds the remaining free tier (%s). Skipping launch.", wantOCPUs, wantMemory, usage))
+	if !w.preflightAlerted {
+		w.preflightAlerted = true
+		hint := "Set auto_shrink: true to launch with what is left."
+		if freeOCPUs < 1 || freeMemory < 1 {
+			hint = "The allotment is fully used."
+		}
+		if err := w.Notifier.Send(notifier.Message{
+			Title: "📏 Free Tier Allotment In Use",
+			Fields: []notifier.Field{
+				{Name: "Account", Value: w.AccountName},
+				{Name: "Usage", Value: usage},
+				{Name: "Requested", Value: fmt.Sprintf("%.0f OCPUs / %.0f GB", wantOCPUs, wantMemory)},
+				{Name: "Hint", Value: hint},
+			},
+			Color:    notifier.ColorError,
+			Priority: 4,
+			Tags:     "straight_ruler,warning",
+		}); err != nil {
+			w.Logger.Error(w.AccountName, fmt.Sprintf("Notification failed: %v", err))
+		}
+	}
+	return 0, 0, false, nil
+}
+
+// MonthToDateCost returns the tenancy's spend since the start of the month and the
+// Usage API forecast for the whole month. Used for PAYG accounts with cost_report.
+func (w *AccountWorker) MonthToDateCost(ctx context.Context) (notifier.Cost, error) {
+	if err := w.initClients(); err != nil {
+		return notifier.Cost{}, err
+	}
+	if w.UsageClient == nil {
+		return notifier.Cost{}, fmt.Errorf("usage client not available")
+	}
+
+	// The Usage API wants day-aligned UTC timestamps.
+	now := time.Now().UTC()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	tomorrow := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+	nextMonth := monthStart.AddDate(0, 1, 0)
+
+	details := usageapi.RequestSummarizedUsagesDetails{
+		TenantId:          common.String(w.Config.TenancyOCID),
+		TimeUsageStarted:  &common.SDKTime{Time: monthStart},
+		TimeUsageEnded:    &common.SDKTime{Time: tomorrow},
+		Granularity:       usageapi.RequestSummarizedUsagesDetailsGranularityDaily,
+		QueryType:         usageapi.RequestSummarizedUsagesDetailsQueryTypeCost,
+		IsAggregateByTime: common.Bool(false),
+	}
+	if tomorrow.Before(nextMonth) {
+		details.Forecast = &usageapi.Forecast{
+			TimeForecastStarted: &common.SDKTime{Time: tomorrow},
+			TimeForecastEnded:   &common.SDKTime{Time: nextMonth},
+		}
+	}
+
+	var cost notifier.Cost
+	req := usageapi.RequestSummarizedUsagesRequest{RequestSummarizedUsagesDetails: details}
+	for {
+		resp, err := w.UsageClient.RequestSummarizedUsages(ctx, req)
+		if err != nil {
+			return notifier.Cost{}, err
+		}
+		for _, item := range resp.Items {
+			if item.ComputedAmount == nil {
+				continue
+			}
+			amount := float64(*item.ComputedAmount)
+			if item.IsForecast != nil && *item.IsForecast {
+				cost.Projected += amount
+				continue
+			}
+			cost.Spent += amount
+			if cost.Currency == "" {
+				cost.Currency = safeString(item.Currency)
+			}
+		}
+		if resp.OpcNextPage == nil {
+			break
+		}
+		req.Page = resp.OpcNextPage
+	}
+	cost.Projected += cost.Spent
+	return cost, nil
+}
@@ -0,0 +1,133 @@
+package provisioner
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/yourusername/oci-arm-provisioner/internal/config"
+)
+
+// probeTimeout bounds each endpoint probe, so an unreachable region doesn't hold up startup.
+const probeTimeout = 10 * time.Second
+
+// Probe is the reachability of one region's Compute API endpoint.
+type Probe struct {
+	Region   string
+	Realm    string // e.g. "oc1", or the realm_domain; empty when the SDK doesn't know the region.
+	Endpoint string
+	Accounts []string // Enabled accounts launching in the region.
+	Latency  time.Duration
+	Err      error
+}
+
+// regionEndpoint returns the Compute API endpoint for region and its realm: the realm ID,
+// or realmDomain if set, which also replaces the domain of the endpoint.
+func regionEndpoint(region, realmDomain string) (endpoint, realm string) {
+	r := common.StringToRegion(region)
+	endpoint = r.EndpointForTemplate("iaas", "https://iaas.{region}.{secondLevelDomain}")
+	if realmDomain != "" {
+		return withRealmDomain(endpoint, region, realmDomain), realmDomain
+	}
+	realm, _ = r.RealmID()
+	return endpoint, realm
+}
+
+// withRealmDomain replaces the domain after the region in an endpoint URL, keeping any
+// service prefix before it.
+func withRealmDomain(endpoint, region, realmDomain string) string {
+	if realmDomain == "" {
+		return endpoint
+	}
+	i := strings.Index(endpoint, "."+common.StringToRegion(region).SecondLevelDomain())
+	if i < 0 {
+		return endpoint
+	}
+	return endpoint[:i] + "." + realmDomain
+}
+
+// setRealmDomain points client at the account's realm_domain, if any.
+func (w *AccountWorker) setRealmDomain(client *common.BaseClient, region string) {
+	client.Host = withRealmDomain(client.Host, region, w.Config.RealmDomain)
+}
+
+// probeRegions measures the round trip to the endpoint of every region an enabled account
+// launches in. Any HTTP response counts as reachable; OCI answers unauthenticated
+// requests with an error.
+func probeRegions(ctx context.Context, cfg *config.Config, client *http.Client) []Probe {
+	byEndpoint := make(map[string]*Probe)
+	for name, acc := range cfg.Accounts {
+		if !acc.Enabled {
+			continue
+		}
+		endpoint, realm := regionEndpoint(acc.Region, acc.RealmDomain)
+		p, ok := byEndpoint[endpoint]
+		if !ok {
+			p = &Probe{Region: acc.Region, Realm: realm, Endpoint: endpoint}
+			byEndpoint[endpoint] = p
+		}
+		p.Accounts = append(p.Accounts, name)
+	}
+
+	var wg sync.WaitGroup
+	probes := make([]Probe, 0, len(byEndpoint))
+	for _, p := range byEndpoint {
+		sort.Strings(p.Accounts)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.Latency, p.Err = probeEndpoint(ctx, client, p.Endpoint)
+		}()
+	}
+	wg.Wait()
+	for _, p := range byEndpoint {
+		probes = append(probes, *p)
+	}
+	sort.Slice(probes, func(i, j int) bool { return probes[i].Endpoint < probes[j].Endpoint })
+	return probes
+}
+
+// probeEndpoint times one request to endpoint.
+func probeEndpoint(ctx context.Context, client *http.Client, endpoint string) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, endpoint, nil)
+	if err != nil {
+		return 0, err
+	}
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	return time.Since(start), nil
+}
+
+// ProbeRegions logs the reachability of every configured region, and warns about
+// region names the SDK doesn't know (typos, or realms that need realm_domain).
+func (p *Provisioner) ProbeRegions(ctx context.Context) {
+	if p.Config.Demo {
+		return
+	}
+	for _, probe := range probeRegions(ctx, p.Config, http.DefaultClient) {
+		accounts := strings.Join(probe.Accounts, ", ")
+		if probe.Realm == "" {
+			p.Logger.Warn("REGION", fmt.Sprintf("'%s' (%s) is not a known OCI region - check the spelling, or set realm_domain for a government or dedicated realm", probe.Region, accounts))
+		}
+		if probe.Err != nil {
+			p.Logger.Warn("REGION", fmt.Sprintf("%s unreachable (%s): %v", probe.Endpoint, accounts, probe.Err))
+			continue
+		}
+		realm := probe.Realm
+		if realm == "" {
+			realm = "unknown realm"
+		}
+		p.Logger.Info("REGION", fmt.Sprintf("📡 %s (%s) reachable in %v (%s)", probe.Region, realm, probe.Latency.Round(time.Millisecond), accounts))
+	}
+}
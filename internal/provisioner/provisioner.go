@@ -6,36 +6,33 @@ import (
 	"crypto/x509"
 	"encoding/pem"
 	"fmt"
+	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/oracle/oci-go-sdk/v65/announcementsservice"
 	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/common/auth"
 	"github.com/oracle/oci-go-sdk/v65/core"
 	"github.com/oracle/oci-go-sdk/v65/identity"
+	"github.com/oracle/oci-go-sdk/v65/limits"
+	"github.com/oracle/oci-go-sdk/v65/usageapi"
+	"github.com/oracle/oci-go-sdk/v65/workrequests"
+	"github.com/yourusername/oci-arm-provisioner/internal/buildinfo"
+	"github.com/yourusername/oci-arm-provisioner/internal/cloudinit"
 	"github.com/yourusername/oci-arm-provisioner/internal/config"
+	"github.com/yourusername/oci-arm-provisioner/internal/errorclass"
+	"github.com/yourusername/oci-arm-provisioner/internal/iphook"
 	"github.com/yourusername/oci-arm-provisioner/internal/logger"
+	"github.com/yourusername/oci-arm-provisioner/internal/mqtt"
 	"github.com/yourusername/oci-arm-provisioner/internal/notifier"
+	"github.com/yourusername/oci-arm-provisioner/internal/report"
+	"github.com/yourusername/oci-arm-provisioner/internal/state"
 )
 
-// ComputeClientOps defines the interface for OCI Compute operations, enabling testing/mocking.
-type ComputeClientOps interface {
-	LaunchInstance(ctx context.Context, request core.LaunchInstanceRequest) (core.LaunchInstanceResponse, error)
-	ListInstances(ctx context.Context, request core.ListInstancesRequest) (core.ListInstancesResponse, error)
-	GetInstance(ctx context.Context, request core.GetInstanceRequest) (core.GetInstanceResponse, error)
-	ListVnicAttachments(ctx context.Context, request core.ListVnicAttachmentsRequest) (core.ListVnicAttachmentsResponse, error)
-}
-
-// VirtualNetworkClientOps defines the interface for OCI Virtual Network operations.
-type VirtualNetworkClientOps interface {
-	GetVnic(ctx context.Context, request core.GetVnicRequest) (core.GetVnicResponse, error)
-}
-
-// IdentityClientOps defines the interface for OCI Identity operations.
-type IdentityClientOps interface {
-	ListAvailabilityDomains(ctx context.Context, request identity.ListAvailabilityDomainsRequest) (identity.ListAvailabilityDomainsResponse, error)
-}
-
 // SimpleConfigProvider is a wrapper around OCI's RawConfigurationProvider to support
 // in-memory RSA keys loaded from files that might not use standard paths.
 type SimpleConfigProvider struct {
@@ -57,12 +54,34 @@ type Provisioner struct {
 	Tracker     *notifier.Tracker
 	Workers     []*AccountWorker // List of initialized workers for enabled accounts.
 	Provisioned map[string]bool  // Tracks accounts that have successfully provisioned.
+	Breaker     *Breaker         // Shared error budget; nil when disabled.
+	Pacer       *Pacer           // Per-tenancy request budget.
+	State       *state.State     // Persistent runtime state (naming sequences).
+	Events      *mqtt.Publisher  // MQTT events; nil when no broker is configured. Close it when done.
+
+	// OnVerified, if set, receives the verified details of each instance RunCycle launches.
+	OnVerified func(account string, v *VerifiedInstance)
+
+	pausedMu sync.RWMutex
+	paused   map[string]bool // Accounts skipped by RunCycle until resumed.
+
+	retargetMu sync.Mutex
+	retargets  map[string]Target // Pending region/AD switches, applied by RunCycle.
+	userAgent  string
+	lockDir    string // Per-user lock files that detect duplicate daemons; empty disables.
+
+	// identityFor overrides the Identity client used by Regions/AvailabilityDomains (tests).
+	identityFor func(acc *config.AccountConfig, region string) (IdentityClientOps, error)
 }
 
 // New initializes the Provisioner manager.
 // It iterates through the enabled accounts in the configuration and creates an AccountWorker for each.
 func New(cfg *config.Config, log *logger.Logger, tracker *notifier.Tracker) *Provisioner {
 	n := notifier.New(cfg.Notifications)
+	n.Notes = cfg.AccountNotes()
+	n.OnError = func(err error) {
+		log.Error("NOTIFIER", fmt.Sprintf("Batched notification failed: %v", err))
+	}
 
 	p := &Provisioner{
 		Config:      cfg,
@@ -71,22 +90,101 @@ func New(cfg *config.Config, log *logger.Logger, tracker *notifier.Tracker) *Pro
 		Tracker:     tracker,
 		Workers:     make([]*AccountWorker, 0),
 		Provisioned: make(map[string]bool),
+		paused:      make(map[string]bool),
+		Breaker:     NewBreaker(cfg.Retry.BreakerThreshold, time.Duration(cfg.Retry.BreakerCooldownMinutes)*time.Minute),
+	}
+
+	// Load persistent state; fall back to memory so a bad file or unreachable store never
+	// blocks provisioning
+	backend, where, err := p.stateBackend()
+	if err != nil {
+		log.Warn("STATE", fmt.Sprintf("State backend unavailable: %v", err))
+		backend, where = nil, "memory only"
+	}
+	st, err := state.Open(backend)
+	if err != nil {
+		log.Warn("STATE", fmt.Sprintf("%v - naming sequences will not persist", err))
+		st, _ = state.Open(nil)
+		where = "memory only"
+	}
+	p.State = st
+	log.Info("STATE", fmt.Sprintf("State: %s", where))
+
+	// Stateless runs write no files, so they skip the duplicate daemon check
+	if cfg.StateFile != "" {
+		p.lockDir = filepath.Join(os.TempDir(), "oci-arm-provisioner")
 	}
 
+	announcementInterval, _ := time.ParseDuration(cfg.Notifications.AnnouncementInterval)
+
+	installID, err := st.InstallID()
+	if err != nil {
+		log.Warn("STATE", fmt.Sprintf("Failed to persist install ID: %v", err))
+	}
+	userAgent := buildinfo.UserAgent(installID, cfg.UserAgent)
+	p.userAgent = userAgent
+	log.Info("INIT", fmt.Sprintf("%s %s (install %s)", buildinfo.Product, buildinfo.String(), installID))
+
+	// Accounts of one tenancy share its rate limits, so they share a budget too
+	tenancies := make(map[string]string)
+	for name, acc := range cfg.Accounts {
+		if acc.Enabled {
+			tenancies[name] = acc.TenancyOCID
+		}
+	}
+	p.Pacer = NewPacer(tenancies, time.Duration(cfg.Scheduler.TenancyIntervalSeconds)*time.Second)
+
+	ipHook := iphook.New(cfg.IPHook)
+	successReport := report.New(cfg.SuccessReport)
+
+	events, err := mqtt.New(cfg.MQTT, func(err error) {
+		log.Warn("MQTT", err.Error())
+	})
+	if err != nil {
+		log.Error("MQTT", fmt.Sprintf("Event publishing disabled: %v", err))
+	} else if events != nil {
+		log.Info("MQTT", fmt.Sprintf("Publishing events to %s under %s/", cfg.MQTT.Broker, cfg.MQTT.TopicPrefix))
+	}
+	p.Events = events
+
 	// Initialize workers for all enabled accounts
 	for name, accConfig := range cfg.Accounts {
 		if accConfig.Enabled {
 			worker := &AccountWorker{
-				AccountName: name,
-				Config:      accConfig,
-				Logger:      log,
-				Notifier:    n,
-				Tracker:     tracker,
+				AccountName:   name,
+				Config:        accConfig,
+				Logger:        log,
+				Notifier:      n,
+				Tracker:       tracker,
+				Breaker:       p.Breaker,
+				Pacer:         p.Pacer,
+				State:         p.State,
+				IPHook:        ipHook,
+				Report:        successReport,
+				Events:        events,
+				PollInterval:  time.Duration(cfg.Timeouts.PollIntervalSeconds) * time.Second,
+				LaunchTimeout: time.Duration(cfg.Timeouts.LaunchSeconds) * time.Second,
+				VerifyTimeout: time.Duration(cfg.Timeouts.VerifyMinutes) * time.Minute,
+				ThrottleRatio: cfg.Retry.ThrottleRatio,
+				Retry:         cfg.Retry,
+				UserAgent:     userAgent,
+
+				AnnouncementInterval: announcementInterval,
+				Milestones:           cfg.Notifications.Milestones,
 			}
 			p.Workers = append(p.Workers, worker)
+
+			// Keep accounts paused across restarts and reloads
+			if st.Paused(name) {
+				p.paused[name] = true
+				log.Info(name, "⏸️ Still paused - resume the account to hunt again")
+			}
 		}
 	}
 
+	if cfg.Demo {
+		p.useDemo()
+	}
 	return p
 }
 
@@ -94,7 +192,7 @@ func New(cfg *config.Config, log *logger.Logger, tracker *notifier.Tracker) *Pro
 // It respects the configured delay between accounts to avoid IP correlation/rate-limiting.
 func (p *Provisioner) RunCycle(ctx context.Context) {
 	p.Tracker.IncCycle()
-	for i, worker := range p.Workers {
+	for i := range p.Workers {
 		// Check for cancellation before starting work on an account
 		select {
 		case <-ctx.Done():
@@ -102,28 +200,95 @@ func (p *Provisioner) RunCycle(ctx context.Context) {
 		default:
 		}
 
+		// Restart the worker first if its region/AD was switched
+		worker := p.applyRetarget(i)
+
+		// Stop the cycle while the circuit breaker is open
+		if ok, remaining := p.Breaker.Allow(); !ok {
+			p.Logger.Warn("BREAKER", fmt.Sprintf("Circuit open - pausing all attempts for another %v", remaining.Round(time.Second)))
+			return
+		}
+
+		// Tenancy notices matter most once the instance exists, so check them first
+		if err := worker.CheckAnnouncements(ctx); err != nil {
+			p.Logger.Warn(worker.AccountName, fmt.Sprintf("Announcements check failed: %v", err))
+		}
+
 		// Skip accounts that are already provisioned
 		if p.Provisioned[worker.AccountName] {
 			p.Logger.Info(worker.AccountName, "✅ Already provisioned - skipping")
+			worker.watchIPs(ctx)
+			p.checkTeardown(ctx, worker)
+			p.checkReclaimed(ctx, worker)
+			if !p.checkMergeUp(ctx, worker) {
+				continue
+			}
+		}
+
+		// Skip accounts the user has paused
+		if p.IsAccountPaused(worker.AccountName) {
+			p.Logger.Info(worker.AccountName, "⏸️ Paused - skipping")
+			continue
+		}
+
+		// Slow down accounts whose requests keep failing for reasons other than capacity
+		if worker.throttled() {
+			p.Logger.Info(worker.AccountName, "🐢 Slowed down - skipping this cycle")
 			continue
 		}
 
+		// Space out attempts after capacity misses in a row, as retry configures
+		if left := worker.backingOff(); left > 0 {
+			p.Logger.Info(worker.AccountName, fmt.Sprintf("⏳ Backing off after capacity misses - next attempt in %v", left.Round(time.Second)))
+			continue
+		}
+
+		// Skip accounts another copy of the tool is already hunting
+		if !p.claim(worker) {
+			continue
+		}
+
+		// Take the tenancy's turn; accounts sharing it must not hit the API back to back
+		tenancy := worker.Config.TenancyOCID
+		waited, err := p.Pacer.Wait(ctx, tenancy)
+		if err != nil {
+			return
+		}
+		if waited > 0 {
+			p.Logger.Info(worker.AccountName, fmt.Sprintf("⏳ Waited %v for the tenancy's request budget (shared with %s)",
+				waited.Round(time.Second), strings.Join(p.Pacer.Accounts(tenancy), ", ")))
+		}
+
 		// Execute provision logic for the worker
 		success, _, err := worker.Provision(ctx)
+		p.Pacer.Done(tenancy)
 		if err != nil {
 			p.Logger.Error(worker.AccountName, fmt.Sprintf("Cycle failed: %v", err))
 		}
+		worker.showRemedy(err)
+		worker.reportAuth(err)
 
-		// Mark as provisioned on success
+		// Mark as provisioned once all of the account's instances exist
 		if success {
-			p.Provisioned[worker.AccountName] = true
+			if p.OnVerified != nil && worker.Verified != nil {
+				p.OnVerified(worker.AccountName, worker.Verified)
+			}
+			if worker.launchesLeft > 0 {
+				p.Logger.Info(worker.AccountName, fmt.Sprintf("🔁 %d more instance(s) to launch", worker.launchesLeft))
+			} else {
+				p.Provisioned[worker.AccountName] = true
+				worker.bootstrap(ctx)
+			}
+		} else {
+			worker.checkMilestones()
 		}
+		worker.endAttempt()
 
 		// Sleep between accounts (but not after the last one)
 		if i < len(p.Workers)-1 {
 			if p.Config.Scheduler.AccountDelaySeconds > 0 {
-				delay := time.Duration(p.Config.Scheduler.AccountDelaySeconds) * time.Second
-				p.Logger.Info("SCHEDULER", fmt.Sprintf("Waiting %ds before next account...", p.Config.Scheduler.AccountDelaySeconds))
+				delay := p.Config.Scheduler.Jitter(time.Duration(p.Config.Scheduler.AccountDelaySeconds) * time.Second)
+				p.Logger.Info("SCHEDULER", fmt.Sprintf("Waiting %ds before next account...", int(delay.Seconds())))
 
 				select {
 				case <-ctx.Done():
@@ -136,6 +301,60 @@ func (p *Provisioner) RunCycle(ctx context.Context) {
 	}
 }
 
+// SetAccountPaused pauses or resumes a single account without affecting the others.
+// It returns false if the account is unknown or already in the requested state.
+func (p *Provisioner) SetAccountPaused(name string, paused bool) bool {
+	if _, ok := p.Config.Accounts[name]; !ok {
+		return false
+	}
+
+	if !p.setPaused(name, paused) {
+		return false
+	}
+
+	msg := notifier.Message{
+		Title:  "▶️ Account Resumed",
+		Fields: []notifier.Field{{Name: "Account", Value: name}},
+		Color:  notifier.ColorInfo,
+		Tags:   "arrow_forward",
+	}
+	if paused {
+		msg.Title = "⏸️ Account Paused"
+		msg.Tags = "pause_button"
+		p.Logger.Warn(name, "Paused - attempts suspended until resumed")
+	} else {
+		p.Logger.Info(name, "Resumed")
+	}
+	if err := p.Notifier.Send(msg); err != nil {
+		p.Logger.Error(name, fmt.Sprintf("Notification failed: %v", err))
+	}
+	return true
+}
+
+// setPaused updates the pause of an account and persists it, so a restart or reload
+// keeps it. It returns false if the account already was in that state.
+func (p *Provisioner) setPaused(name string, paused bool) bool {
+	p.pausedMu.Lock()
+	if p.paused[name] == paused {
+		p.pausedMu.Unlock()
+		return false
+	}
+	p.paused[name] = paused
+	p.pausedMu.Unlock()
+
+	if err := p.State.SetPaused(name, paused); err != nil {
+		p.Logger.Warn(name, fmt.Sprintf("Failed to persist pause: %v", err))
+	}
+	return true
+}
+
+// IsAccountPaused reports whether an account is currently paused.
+func (p *Provisioner) IsAccountPaused(name string) bool {
+	p.pausedMu.RLock()
+	defer p.pausedMu.RUnlock()
+	return p.paused[name]
+}
+
 // AccountWorker handles the provisioning logic for a single OCI account.
 type AccountWorker struct {
 	AccountName          string
@@ -143,41 +362,94 @@ type AccountWorker struct {
 	Logger               *logger.Logger
 	Notifier             *notifier.Notifier
 	Tracker              *notifier.Tracker
+	Breaker              *Breaker
+	Pacer                *Pacer
+	State                *state.State
+	IPHook               *iphook.Hook       // Runs on a new or changed public IP; nil when not configured.
+	Report               *report.Uploader   // Uploads a record of each provisioned instance; nil when not configured.
+	Events               *mqtt.Publisher    // Attempt, capacity and success events; nil when not configured.
+	Verified             *VerifiedInstance  // Set when Provision launches an instance.
+	PollInterval         time.Duration      // Instance status polling interval (and GetInstance cache TTL).
+	LaunchTimeout        time.Duration      // timeouts.launch_seconds: one Provision call.
+	VerifyTimeout        time.Duration      // timeouts.verify_minutes: verifying a launched instance.
+	ThrottleRatio        float64            // retry.throttle_ratio; 0 disables self-throttling.
+	Retry                config.RetryConfig // Capacity backoff, with retry.exponential_backoff.
 	ComputeClient        ComputeClientOps
 	IdentityClient       IdentityClientOps
 	VirtualNetworkClient VirtualNetworkClientOps
+	WorkRequestClient    WorkRequestClientOps  // May be nil when the other clients were injected (tests).
+	AnnouncementClient   AnnouncementClientOps // May be nil when the other clients were injected (tests).
+	UsageClient          UsageClientOps        // Only created for accounts with cost_report enabled.
+	LimitsClient         LimitsClientOps       // May be nil when the other clients were injected (tests).
+	BlockstorageClient   BlockstorageClientOps // Only created for accounts with reclaim.reuse_boot_volume or upgrade_existing.
+	SecretsClient        SecretsClientOps      // Only created for accounts with key_vault_secret_ocid.
+	vaultKey             []byte                // The key read from the vault; in memory only.
+
+	UserAgent string // Product token appended to the SDK User-Agent (see buildinfo.UserAgent).
+
+	AnnouncementInterval  time.Duration // 0 disables the announcements check.
+	lastAnnouncementCheck time.Time
+
+	preflightAlerted bool // Free-tier usage alert already sent.
+
+	shapeIndex  int // Position in the account's shape chain (see config.AccountConfig.Shapes).
+	shapeMisses int // Capacity errors in a row on the current shape.
+	splitLevel  int // Position in the account's slices with split_on_capacity (see config.AccountConfig.Slices).
+
+	lastMergeCheck time.Time // Last capacity report for a slice's full size.
+	mergeAD        string    // AD the capacity report had room in; the next launch goes there.
+	adIndex        int       // Next AD to start from with availability_domain: auto.
+
+	failures failureRate // Recent API call outcomes for self-throttling.
+
+	limits       *limitBlock // Exhausted service limit that stopped launches, until it has room.
+	limitAlerted bool        // Service limit guidance already sent.
+
+	teardownWarned map[string]bool // Instances whose teardown warning was sent.
+
+	images map[string]resolvedImage // Image alias resolved per shape.
+	fds    map[string][]string      // Fault domains per AD, for sweep.
+
+	remedyShown string // Problem of the fix last logged; see showRemedy.
+	lastFailure error  // Last request counted as a failure for self-throttling.
+
+	policiesOK bool   // IAM policy checks passed and no launch was refused since.
+	attemptID  string // Correlation ID of the attempt in progress; see beginAttempt.
+
+	launchesLeft int // Instances still to launch after the last one, with instance_count.
+
+	Milestones   config.MilestoneConfig // Capacity milestone thresholds; zero disables.
+	nextAttempts int                    // Attempt count of the next milestone.
+	nextDays     int                    // Hunting days of the next milestone.
 }
 
 // getProvider loads the OCI credentials and creates a ConfigurationProvider.
 // It performs security checks on the key file permissions and size.
 func (w *AccountWorker) getProvider() (common.ConfigurationProvider, error) {
-	// 1. Safety Checks: Verify key file existence and size.
-	info, err := os.Stat(w.Config.KeyFile)
-	if err != nil {
-		return nil, fmt.Errorf("key file not found: %s", w.Config.KeyFile)
-	}
-
-	const MaxKeySize = 16 * 1024
-	if info.Size() > MaxKeySize {
-		return nil, fmt.Errorf("key file too large (%d bytes), max is %d", info.Size(), MaxKeySize)
+	// 0. Instance principals sign as the instance the tool runs on
+	if w.Config.UsesInstancePrincipal() {
+		provider, err := auth.InstancePrincipalConfigurationProviderForRegion(common.StringToRegion(w.Config.Region))
+		if err != nil {
+			return nil, fmt.Errorf("instance principal unavailable (not running on an OCI instance?): %w", err)
+		}
+		return provider, nil
 	}
-
-	// Permission warning
-	mode := info.Mode()
-	if mode&0077 != 0 {
-		w.Logger.Warn(w.AccountName, fmt.Sprintf("Key file '%s' has permissive permissions (%o). It should be 400 or 600.", w.Config.KeyFile, mode))
+	// OCI CLI sessions sign with the token and key of their profile
+	if w.Config.Auth == config.AuthSecurityToken {
+		return w.newSessionProvider()
 	}
 
-	// 2. Read Key File
-	content, err := os.ReadFile(w.Config.KeyFile)
+	// 1-2. Read the key from its file, or inline from key_content or key_env
+	content, err := w.readKey()
 	if err != nil {
-		return nil, fmt.Errorf("failed to read key file: %w", err)
+		return nil, err
 	}
+	source := w.Config.KeySource()
 
 	// 3. Decode PEM
 	block, _ := pem.Decode(content)
 	if block == nil {
-		return nil, fmt.Errorf("failed to decode PEM block from %s", w.Config.KeyFile)
+		return nil, fmt.Errorf("failed to decode PEM block from %s", source)
 	}
 
 	// 4. Parse RSA Key (Try PKCS1 first, then PKCS8)
@@ -193,7 +465,7 @@ func (w *AccountWorker) getProvider() (common.ConfigurationProvider, error) {
 	}
 
 	if key == nil {
-		return nil, fmt.Errorf("failed to parse private key from %s (ensure RSA PEM)", w.Config.KeyFile)
+		return nil, fmt.Errorf("failed to parse private key from %s (ensure RSA PEM)", source)
 	}
 
 	// Create OCI Provider
@@ -212,15 +484,50 @@ func (w *AccountWorker) getProvider() (common.ConfigurationProvider, error) {
 	}, nil
 }
 
-// initClients initializes the OCI Compute, Identity, and VirtualNetwork clients if they haven't been already.
+// readKey returns the account's private key PEM. Inline, vault and file keys get the
+// same size limit; key files are also checked for permissive permissions.
+func (w *AccountWorker) readKey() ([]byte, error) {
+	if w.Config.HasInlineKey() {
+		return w.Config.PrivateKeyPEM()
+	}
+	if w.Config.KeyVaultSecretOCID != "" {
+		return w.readVaultKey()
+	}
+
+	// Safety Checks: Verify key file existence and size.
+	info, err := os.Stat(w.Config.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("key file not found: %s", w.Config.KeyFile)
+	}
+	if info.Size() > config.MaxKeySize {
+		return nil, fmt.Errorf("key file too large (%d bytes), max is %d", info.Size(), config.MaxKeySize)
+	}
+
+	// Permission warning
+	mode := info.Mode()
+	if mode&0077 != 0 {
+		w.Logger.Warn(w.AccountName, fmt.Sprintf("Key file '%s' has permissive permissions (%o). It should be 400 or 600.", w.Config.KeyFile, mode))
+	}
+
+	content, err := os.ReadFile(w.Config.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+	return content, nil
+}
+
+// initClients initializes the OCI Compute, Identity, VirtualNetwork, and WorkRequest clients if they haven't been already.
 func (w *AccountWorker) initClients() error {
+	if err := w.refreshSession(context.Background()); err != nil {
+		return err
+	}
 	if w.ComputeClient != nil && w.IdentityClient != nil && w.VirtualNetworkClient != nil {
 		return nil
 	}
 
 	provider, err := w.getProvider()
 	if err != nil {
-		return err
+		return credentialsError{err}
 	}
 
 	if w.ComputeClient == nil {
@@ -228,7 +535,11 @@ func (w *AccountWorker) initClients() error {
 		if err != nil {
 			return fmt.Errorf("failed to create compute client: %w", err)
 		}
-		w.ComputeClient = &client
+		client.Interceptor = conditionalGet
+		w.setRealmDomain(&client.BaseClient, w.Config.Region)
+		w.tagRequests(&client.BaseClient)
+		w.useProxy(&client.BaseClient)
+		w.ComputeClient = newInstanceCache(&client, w.pollInterval())
 	}
 
 	if w.IdentityClient == nil {
@@ -236,6 +547,9 @@ func (w *AccountWorker) initClients() error {
 		if err != nil {
 			return fmt.Errorf("failed to create identity client: %w", err)
 		}
+		w.setRealmDomain(&client.BaseClient, w.Config.Region)
+		w.tagRequests(&client.BaseClient)
+		w.useProxy(&client.BaseClient)
 		w.IdentityClient = &client
 	}
 
@@ -244,144 +558,602 @@ func (w *AccountWorker) initClients() error {
 		if err != nil {
 			return fmt.Errorf("failed to create virtual network client: %w", err)
 		}
+		w.setRealmDomain(&client.BaseClient, w.Config.Region)
+		w.tagRequests(&client.BaseClient)
+		w.useProxy(&client.BaseClient)
 		w.VirtualNetworkClient = &client
 	}
 
+	if w.WorkRequestClient == nil {
+		client, err := workrequests.NewWorkRequestClientWithConfigurationProvider(provider)
+		if err != nil {
+			return fmt.Errorf("failed to create work request client: %w", err)
+		}
+		w.setRealmDomain(&client.BaseClient, w.Config.Region)
+		w.tagRequests(&client.BaseClient)
+		w.useProxy(&client.BaseClient)
+		w.WorkRequestClient = &client
+	}
+
+	if w.AnnouncementClient == nil {
+		client, err := announcementsservice.NewAnnouncementClientWithConfigurationProvider(provider)
+		if err != nil {
+			return fmt.Errorf("failed to create announcements client: %w", err)
+		}
+		w.setRealmDomain(&client.BaseClient, w.Config.Region)
+		w.tagRequests(&client.BaseClient)
+		w.useProxy(&client.BaseClient)
+		w.AnnouncementClient = &client
+	}
+
+	if w.LimitsClient == nil {
+		client, err := limits.NewLimitsClientWithConfigurationProvider(provider)
+		if err != nil {
+			return fmt.Errorf("failed to create limits client: %w", err)
+		}
+		w.setRealmDomain(&client.BaseClient, w.Config.Region)
+		w.tagRequests(&client.BaseClient)
+		w.useProxy(&client.BaseClient)
+		w.LimitsClient = &client
+	}
+
+	if w.BlockstorageClient == nil && w.Config.ReusesBootVolume() {
+		client, err := core.NewBlockstorageClientWithConfigurationProvider(provider)
+		if err != nil {
+			return fmt.Errorf("failed to create block storage client: %w", err)
+		}
+		w.setRealmDomain(&client.BaseClient, w.Config.Region)
+		w.tagRequests(&client.BaseClient)
+		w.useProxy(&client.BaseClient)
+		w.BlockstorageClient = &client
+	}
+
+	if w.UsageClient == nil && w.Config.CostReport {
+		client, err := usageapi.NewUsageapiClientWithConfigurationProvider(provider)
+		if err != nil {
+			return fmt.Errorf("failed to create usage client: %w", err)
+		}
+		w.setRealmDomain(&client.BaseClient, w.Config.Region)
+		w.tagRequests(&client.BaseClient)
+		w.useProxy(&client.BaseClient)
+		w.UsageClient = &client
+	}
+
 	return nil
 }
 
-// Provision attempts to create the configured instance.
+// tagRequests identifies this tool and deployment on every request made by client:
+// the User-Agent gets the product token and opc-client-info the tool version.
+func (w *AccountWorker) tagRequests(client *common.BaseClient) {
+	if w.UserAgent != "" {
+		client.UserAgent += " " + w.UserAgent
+	}
+	next := client.Interceptor
+	client.Interceptor = func(req *http.Request) error {
+		req.Header.Set("opc-client-info", buildinfo.ClientInfo())
+		if a := attemptFrom(req.Context()); a != nil && req.Header.Get("opc-request-id") == "" {
+			req.Header.Set("opc-request-id", a.requestID())
+		}
+		if next != nil {
+			return next(req)
+		}
+		return nil
+	}
+}
+
+// imageSource returns the launch source for shape's image, with the boot volume settings.
+func (w *AccountWorker) imageSource(ctx context.Context, shape string) (core.InstanceSourceDetails, error) {
+	imageID, err := w.imageID(ctx, shape)
+	if err != nil {
+		return nil, err
+	}
+	image := core.InstanceSourceViaImageDetails{
+		ImageId:             common.String(imageID),
+		BootVolumeSizeInGBs: common.Int64(w.Config.BootVolumeSizeGB),
+	}
+	if w.Config.BootVolumeVPUsPerGB > 0 {
+		image.BootVolumeVpusPerGB = common.Int64(w.Config.BootVolumeVPUsPerGB)
+	}
+	return image, nil
+}
+
+// launchDetails builds the launch request of shape at ocpus/memory from source. The AD,
+// fault domain and retry token are set per launch.
+func (w *AccountWorker) launchDetails(shape config.ShapeOption, ocpus, memory float32, source core.InstanceSourceDetails, displayName, hostname string) (core.LaunchInstanceDetails, error) {
+	metadata := map[string]string{
+		"ssh_authorized_keys": w.Config.SSHPublicKey,
+	}
+	userData, err := cloudinit.UserData(w.Config.CloudInit, w.Config.CloudInitVars)
+	if err != nil {
+		return core.LaunchInstanceDetails{}, fmt.Errorf("cloud_init: %w", err)
+	}
+	if userData != "" {
+		metadata["user_data"] = userData
+	}
+
+	details := core.LaunchInstanceDetails{
+		CompartmentId: common.String(w.Config.CompartmentOCID),
+		DisplayName:   common.String(displayName),
+		Shape:         common.String(shape.Shape),
+		SourceDetails: source,
+		CreateVnicDetails: &core.CreateVnicDetails{
+			SubnetId:       common.String(w.Config.SubnetOCID),
+			AssignPublicIp: common.Bool(true),
+			HostnameLabel:  common.String(hostname),
+		},
+		Metadata:     metadata,
+		FreeformTags: w.freeformTags(),
+		DefinedTags:  w.definedTags(),
+	}
+	if w.Config.AssignIPv6 {
+		details.CreateVnicDetails.AssignIpv6Ip = common.Bool(true)
+	}
+	if w.Config.DedicatedVMHostOCID != "" {
+		details.DedicatedVmHostId = common.String(w.Config.DedicatedVMHostOCID)
+	}
+	// Fixed shapes reject a shape config; their size comes with the shape
+	if !shape.Fixed {
+		details.ShapeConfig = &core.LaunchInstanceShapeConfigDetails{
+			Ocpus:       common.Float32(ocpus),
+			MemoryInGBs: common.Float32(memory),
+		}
+	}
+	return details, nil
+}
+
+// launchRetries describes the launch errors Provision retries, by class.
+var launchRetries = map[errorclass.Class]string{
+	errorclass.Capacity:  "Out of host capacity",
+	errorclass.Limit:     "Service limit reached",
+	errorclass.RateLimit: "Rate limited",
+	errorclass.Server:    "OCI server error",
+}
+
+// isCapacityError reports whether err is OCI's out-of-capacity or limit response.
+func isCapacityError(err error) bool {
+	class := errorclass.Of(err)
+	return class == errorclass.Capacity || class == errorclass.Limit
+}
+
+// isDedicatedHostError reports whether err is a launch failure on the account's dedicated
+// VM host. The host's capacity is reserved, so these are config errors (wrong AD, shape or
+// size), not capacity to wait for.
+func (w *AccountWorker) isDedicatedHostError(err error) bool {
+	return w.Config.DedicatedVMHostOCID != "" && isCapacityError(err)
+}
+
+// Provision attempts to create the configured instance, or the next one of instance_count.
 // It checks for existing instances, resolves the AD, and handles OCI errors/retries.
 // Returns: (success, retryable, error)
 func (w *AccountWorker) Provision(parentCtx context.Context) (bool, bool, error) {
+	parentCtx = w.beginAttempt(parentCtx)
+
 	// Add timeout to prevent hanging on network issues
-	ctx, cancel := context.WithTimeout(parentCtx, 60*time.Second)
+	ctx, cancel := context.WithTimeout(parentCtx, w.launchTimeout())
 	defer cancel()
 
 	if err := w.initClients(); err != nil {
 		return false, false, err
 	}
 
-	w.Logger.Info(w.AccountName, "Checking for existing instances...")
-	existing, err := w.checkExisting(ctx)
+	// Resolve templated names: an existing instance carries the last committed
+	// sequence number, a new launch takes the next one.
+	seq := w.State.Sequence(w.AccountName)
+	existingName, _, err := w.instanceNames(max(seq, 1))
 	if err != nil {
 		return false, false, err
 	}
-	if existing {
-		w.Logger.Info(w.AccountName, "Instance already exists. Stopping.")
-		return true, false, nil
+	displayName, hostname, err := w.instanceNames(seq + 1)
+	if err != nil {
+		return false, false, err
 	}
 
-	// Auto-Detect Availability Domain if set to "auto"
-	ad := w.Config.AvailabilityDomain
-	if ad == "auto" {
-		req := identity.ListAvailabilityDomainsRequest{
-			CompartmentId: common.String(w.Config.TenancyOCID),
+	w.Logger.Info(w.AccountName, "Checking for existing instances...")
+	existing, err := w.checkExisting(ctx, existingName)
+	w.observe(err)
+	if isNotAuthorizedOrNotFound(err) {
+		err = w.policiesBlocked(ctx, err)
+	}
+	if err != nil {
+		w.Tracker.RecordError(w.AccountName, err)
+		return false, false, err
+	}
+	count := w.instanceCount()
+	w.launchesLeft = 0
+	if existing != nil && count == 1 {
+		have, want, small := w.undersized(*existing)
+		if !small {
+			w.Logger.Info(w.AccountName, "Instance already exists. Stopping.")
+			w.trackExisting(ctx, *existing)
+			return true, false, nil
 		}
-		resp, err := w.IdentityClient.ListAvailabilityDomains(ctx, req)
+		gone, err := w.upgradeExisting(parentCtx, *existing, have, want)
 		if err != nil {
-			return false, false, fmt.Errorf("failed to list ADs: %w", err)
+			w.Tracker.RecordError(w.AccountName, err)
+			return false, false, err
 		}
-		if len(resp.Items) == 0 {
-			return false, false, fmt.Errorf("no ADs found")
+		if !gone {
+			// Keep checking for room, unless the upgrade is off
+			return !w.Config.UpgradeExisting, false, nil
 		}
-		// Typically pick the first one, or round-robin could be implemented here.
-		ad = *resp.Items[0].Name
-		w.Logger.Info(w.AccountName, fmt.Sprintf("Auto-selected AD: %s", ad))
+		// Waiting for the termination can outlast the launch timeout, so the relaunch
+		// gets a fresh one
+		var relaunchCancel context.CancelFunc
+		ctx, relaunchCancel = context.WithTimeout(parentCtx, w.launchTimeout())
+		defer relaunchCancel()
+	}
+	have := 0
+	if count > 1 {
+		managed, err := w.managedInstances(ctx)
+		w.observe(err)
+		if err != nil {
+			w.Tracker.RecordError(w.AccountName, err)
+			return false, false, err
+		}
+		if have = len(managed); have >= count {
+			w.Logger.Info(w.AccountName, fmt.Sprintf("All %d instances exist. Stopping.", count))
+			return true, false, nil
+		}
+		w.Logger.Info(w.AccountName, fmt.Sprintf("%d of %d instances exist - launching instance %d", have, count, have+1))
+	}
+	if err := w.policiesBlocked(ctx, nil); err != nil {
+		w.Tracker.RecordError(w.AccountName, err)
+		return false, false, err
 	}
 
-	w.Logger.Info(w.AccountName, fmt.Sprintf("Launching instance '%s'...", w.Config.DisplayName))
+	// Preflight: make sure the request fits in what is left of the free-tier allotment
+	shape := w.shape()
+	ocpus, memory, ok, err := w.preflight(ctx, shape)
+	w.observe(err)
+	if err != nil {
+		w.Tracker.RecordError(w.AccountName, err)
+		return false, false, err
+	}
+	if !ok {
+		return false, false, nil
+	}
+	if w.limitBlocked(ctx, ocpus, memory) {
+		return false, false, nil
+	}
 
-	// Construct Launch Request
-	req := core.LaunchInstanceRequest{
-		LaunchInstanceDetails: core.LaunchInstanceDetails{
-			AvailabilityDomain: common.String(ad),
-			CompartmentId:      common.String(w.Config.CompartmentOCID),
-			DisplayName:        common.String(w.Config.DisplayName),
-			Shape:              common.String(w.Config.Shape),
-			ShapeConfig: &core.LaunchInstanceShapeConfigDetails{
-				Ocpus:       common.Float32(w.Config.OCPUs),
-				MemoryInGBs: common.Float32(w.Config.MemoryGB),
-			},
-			SourceDetails: core.InstanceSourceViaImageDetails{
-				ImageId:             common.String(w.Config.ImageOCID),
-				BootVolumeSizeInGBs: common.Int64(w.Config.BootVolumeSizeGB),
-			},
-			CreateVnicDetails: &core.CreateVnicDetails{
-				SubnetId:       common.String(w.Config.SubnetOCID),
-				AssignPublicIp: common.Bool(true),
-				HostnameLabel:  common.String(w.Config.HostnameLabel),
-			},
-			Metadata: map[string]string{
-				"ssh_authorized_keys": w.Config.SSHPublicKey,
-			},
-		},
+	// Availability Domains to try: with "auto", the region's ADs take turns
+	ads, err := w.launchADs(ctx)
+	if err != nil {
+		return false, false, err
 	}
 
-	// API Call
-	resp, err := w.ComputeClient.LaunchInstance(ctx, req)
+	// A reclaimed instance's boot volume replaces the image, in the volume's AD
+	var source core.InstanceSourceDetails
+	volume, reuse, err := w.reusableBootVolume(ctx)
 	if err != nil {
+		w.Tracker.RecordError(w.AccountName, err)
+		return false, false, err
+	}
+	if reuse {
+		w.Logger.Info(w.AccountName, fmt.Sprintf("♻️ Launching from the boot volume of %s", volume.Instance))
+		source = core.InstanceSourceViaBootVolumeDetails{BootVolumeId: common.String(volume.ID)}
+		ads = []string{volume.AvailabilityDomain}
+	} else if source, err = w.imageSource(ctx, shape.Shape); err != nil {
+		w.Tracker.RecordError(w.AccountName, err)
+		return false, false, err
+	}
+
+	// Construct Launch Request
+	details, err := w.launchDetails(shape, ocpus, memory, source, displayName, hostname)
+	if err != nil {
+		return false, false, err
+	}
+	req := core.LaunchInstanceRequest{LaunchInstanceDetails: details}
+
+	// With check_limits, only ADs whose limits have room for the launch
+	if ads = w.limitsPreflight(ctx, shape.Shape, ads, ocpus, memory); len(ads) == 0 {
+		return false, false, nil
+	}
+	// With capacity_probe, only ADs the capacity report has room in
+	probed := shape
+	probed.OCPUs, probed.MemoryGB = ocpus, memory
+	ads, faultDomains := w.probeCapacity(ctx, probed, ads)
+	if len(ads) == 0 {
+		w.Logger.Warn(w.AccountName, "📡 No AD reported room - skipping the launch this cycle. Will retry.")
+		w.capacityMiss()
+		return false, true, nil
+	}
+
+	// API Call, in each AD (and with sweep, fault domain) in turn until one has capacity
+	targets := w.launchTargets(ctx, ads, faultDomains)
+	var resp core.LaunchInstanceResponse
+	var launchedAD string
+	for i, target := range targets {
+		ad := target.ad
+		launchedAD = ad
+		if i > 0 && w.Config.Sweep.Enabled {
+			if err := sleepCtx(ctx, w.Config.Sweep.Delay()); err != nil {
+				// The sweep shares the launch timeout; running out of it is a miss too
+				if parentCtx.Err() == nil {
+					w.Logger.Warn(w.AccountName, fmt.Sprintf("Sweep stopped after %d of %d requests: launch timeout of %v reached (raise timeouts.launch_seconds). Will retry.", i, len(targets), w.launchTimeout()))
+					w.capacityMiss()
+				}
+				return false, true, nil
+			}
+		}
+		if w.Config.AvailabilityDomain == "auto" {
+			w.Logger.Info(w.AccountName, fmt.Sprintf("Auto-selected AD: %s", ad))
+		}
+		w.Logger.Info(w.AccountName, fmt.Sprintf("Launching instance '%s'...", displayName))
+		req.AvailabilityDomain = common.String(ad)
+		req.FaultDomain = nil
+		if target.faultDomain != "" {
+			req.FaultDomain = common.String(target.faultDomain)
+		}
+		// A retry of this exact request (e.g. after a timeout) can't launch twice
+		req.OpcRetryToken = common.String(fmt.Sprintf("%s-ad%d", w.attemptID, i+1))
+		w.Tracker.RecordAttemptIn(w.AccountName, ad)
+		w.publish(mqtt.Event{Type: mqtt.Attempt, Region: w.Config.Region})
+		resp, err = w.ComputeClient.LaunchInstance(ctx, req)
+		w.observe(err)
+		if isHostnameConflict(err) {
+			resp, err = w.relaunchWithFreeHostname(ctx, req, err)
+		}
+		if err := w.State.RecordAttempt(w.AccountName, isCapacityError(err) && !w.isDedicatedHostError(err), time.Now()); err != nil {
+			w.Logger.Warn(w.AccountName, fmt.Sprintf("Failed to persist attempt history: %v", err))
+		}
+		if err == nil {
+			break
+		}
 		if serviceErr, ok := common.IsServiceError(err); ok {
 			code := serviceErr.GetHTTPStatusCode()
-			msg := strings.ToLower(serviceErr.GetMessage())
 
-			w.Logger.Warn(w.AccountName, fmt.Sprintf("OCI Error %d: %s", code, serviceErr.GetMessage()))
+			w.Logger.Warn(w.AccountName, fmt.Sprintf("OCI Error %d: %s (opc-request-id %s)", code, serviceErr.GetMessage(), serviceErr.GetOpcRequestID()))
 
-			// Handle Capacity/Limit errors gracefully (Retryable)
-			if code == 500 || strings.Contains(msg, "capacity") || strings.Contains(msg, "limit") {
-				w.Logger.Warn(w.AccountName, "Capacity/Limit error. Will retry.")
-				w.Tracker.IncCapacity()
-				return false, true, nil
+			if w.isDedicatedHostError(err) {
+				err = fmt.Errorf("dedicated VM host %s rejected the launch (check its AD, shape and free OCPUs/memory): %w", w.Config.DedicatedVMHostOCID, err)
+				w.Tracker.RecordError(w.AccountName, err)
+				return false, false, err
+			}
+
+			// A service limit that is really used up won't free itself: stop instead of retrying
+			class := errorclass.Of(err)
+			if class == errorclass.Limit && w.limitExceeded(ctx, err, shape.Shape, ad, ocpus, memory) {
+				err = fmt.Errorf("service limit exhausted: %w", err)
+				w.Tracker.RecordError(w.AccountName, err)
+				return false, false, err
 			}
-			// Handle Rate Limiting (Retryable)
-			if code == 429 {
-				w.Logger.Warn(w.AccountName, "Rate limited. Will retry.")
-				w.Tracker.IncError()
+
+			switch {
+			case class == errorclass.Capacity || class == errorclass.Limit:
+				// Retryable, in the next AD if any
+				w.Logger.Warn(w.AccountName, launchRetries[class]+". Will retry.")
+				w.Tracker.RecordCapacityIn(w.AccountName, ad)
+				w.publish(mqtt.Event{Type: mqtt.Capacity, Region: w.Config.Region})
+				if i < len(targets)-1 {
+					continue
+				}
+				w.capacityMiss()
+				return false, true, nil
+			case class.Retryable():
+				// Rate limits and server errors: retry next cycle, another AD won't help
+				w.Logger.Warn(w.AccountName, launchRetries[class]+". Will retry.")
+				w.Tracker.RecordError(w.AccountName, err)
 				return false, true, nil
 			}
 		}
 		// Non-retryable error
-		w.Tracker.IncError()
+		if isNotAuthorizedOrNotFound(err) {
+			err = w.explainLaunchNotFound(err)
+		}
+		w.Tracker.RecordError(w.AccountName, err)
 		return false, false, err
 	}
 
 	// SUCCESS! Instance was launched.
 	instanceID := *resp.Instance.Id
-	w.Logger.Success(w.AccountName, fmt.Sprintf("Instance Launched: %s", instanceID))
+	w.Tracker.RecordLaunchIn(w.AccountName, launchedAD)
+	w.resetBackoff()
+	w.Logger.Success(w.AccountName, fmt.Sprintf("Instance Launched: %s (opc-request-id %s)", instanceID, safeString(resp.OpcRequestId)))
+
+	slice := w.launchedSlice()
+	if err := w.State.CommitSequence(w.AccountName, seq+1); err != nil {
+		w.Logger.Warn(w.AccountName, fmt.Sprintf("Failed to persist naming sequence: %v", err))
+	}
+	if reuse {
+		if err := w.State.SetBootVolume(w.AccountName, nil); err != nil {
+			w.Logger.Warn(w.AccountName, fmt.Sprintf("Failed to persist boot volume: %v", err))
+		}
+	}
 
 	// Extended verification with longer timeout context
-	verifyCtx, verifyCancel := context.WithTimeout(parentCtx, 6*time.Minute)
+	verifyCtx, verifyCancel := context.WithTimeout(parentCtx, w.verifyTimeout())
 	defer verifyCancel()
 
-	verified, verifyErr := w.VerifyInstance(verifyCtx, instanceID)
+	verified, verifyErr := w.verifyInstance(verifyCtx, instanceID, ocpus, memory)
 	if verifyErr != nil {
 		w.Logger.Warn(w.AccountName, fmt.Sprintf("Verification warning: %v", verifyErr))
+		if err := w.Notifier.SendAlert(notifier.Alert{
+			Class:   notifier.ClassVerification,
+			Account: w.AccountName,
+			Detail:  verifyErr.Error(),
+			Fields:  []notifier.Field{{Name: "Instance", Value: instanceID}},
+		}); err != nil {
+			w.Logger.Error(w.AccountName, fmt.Sprintf("Notification failed: %v", err))
+		}
+	}
+
+	// Swap the ephemeral public IP for the reserved one, so the address survives stop/start
+	if verifyErr == nil && w.Config.ReservedPublicIP != "" {
+		ip, err := w.reservePublicIP(verifyCtx, instanceID)
+		if err != nil {
+			w.Logger.Warn(w.AccountName, fmt.Sprintf("Reserved public IP not assigned: %v", err))
+		} else {
+			w.Logger.Info(w.AccountName, fmt.Sprintf("📌 Reserved public IP %s assigned", ip))
+			verified.PublicIP = ip
+		}
 	}
 
-	// Track success
-	w.Tracker.IncSuccess()
+	// SSH host keys, so the first connection can be checked against the notification
+	if verifyErr == nil {
+		keys, err := w.hostKeys(verifyCtx, instanceID)
+		if err != nil {
+			w.Logger.Warn(w.AccountName, fmt.Sprintf("SSH host keys not available: %v", err))
+		} else {
+			w.Logger.Info(w.AccountName, "🔑 SSH host keys: "+strings.Join(keys, ", "))
+		}
+		verified.HostKeys = keys
+	}
+
+	// With verify.wait_for_cloud_init, announce the instance once it is set up
+	if verifyErr == nil && w.Config.Verify.WaitForCloudInit {
+		if uptime, err := w.waitForCloudInit(parentCtx, instanceID); err != nil {
+			w.Logger.Warn(w.AccountName, fmt.Sprintf("Not waiting for cloud-init any longer: %v", err))
+			verified.Errors = append(verified.Errors, err.Error())
+		} else {
+			w.Logger.Info(w.AccountName, fmt.Sprintf("☁️ cloud-init finished %v after boot", uptime.Round(time.Second)))
+		}
+	}
+
+	// Track success; a failed verification still leaves the instance ID to show
+	if verified == nil {
+		verified = &VerifiedInstance{InstanceID: instanceID, Region: w.Config.Region, Errors: []string{verifyErr.Error()}}
+	}
+	verified.Number, verified.Count = have+1, count
+	w.launchesLeft = count - have - 1
+	w.Verified = verified
+	publicIP := verified.PublicIP
+	w.Tracker.RecordSuccess(w.AccountName, instanceID, publicIP)
+	w.recordVerification(verified, shape.Shape, verifyErr)
+	w.publish(mqtt.Event{
+		Type: mqtt.Success, Region: w.Config.Region, InstanceID: instanceID, PublicIP: publicIP,
+		RunningSeconds:  verified.RunningAfter.Seconds(),
+		PublicIPSeconds: verified.PublicIPAfter.Seconds(),
+		SpecsMismatch:   verified.SpecsMismatch,
+	})
+	if _, err := w.trackInstance(parentCtx, state.Instance{ID: instanceID, Name: displayName, PublicIP: publicIP, Slice: slice}); err != nil {
+		w.Logger.Warn(w.AccountName, fmt.Sprintf("Failed to persist instance: %v", err))
+	}
 
 	// Celebration Banner with terminal beep
 	w.Logger.Celebrate(w.AccountName, verified)
 
-	// Send notification with verified details - log any failures
-	if err := w.Notifier.SendSuccessVerified(w.AccountName, verified); err != nil {
-		w.Logger.Error(w.AccountName, fmt.Sprintf("Notification failed: %v", err))
+	// Send notification with verified details - log any failures. An instance announced
+	// before, by an earlier run, is only logged.
+	if w.State.Notified(w.AccountName, instanceID) {
+		w.Logger.Info(w.AccountName, fmt.Sprintf("🔕 Success for %s was already notified - not sending it again", instanceID))
+	} else {
+		receipt, err := w.Notifier.SendSuccessVerified(w.AccountName, verified)
+		if err != nil {
+			w.Logger.Error(w.AccountName, fmt.Sprintf("Notification failed: %v", err))
+		}
+		if receipt.InstanceID != "" {
+			w.recordReceipt(receipt)
+			go w.watchAcks(parentCtx, receipt)
+		}
+		// Only a notification that reached the user is never repeated
+		if receipt.Delivered() {
+			if err := w.State.MarkNotified(w.AccountName, instanceID); err != nil {
+				w.Logger.Warn(w.AccountName, fmt.Sprintf("Failed to persist notification delivery: %v", err))
+			}
+		}
 	}
 
+	// A durable record outside this machine, after the notification so it isn't delayed
+	w.uploadReport(parentCtx, verified)
+
 	return true, false, nil
 }
 
-// checkExisting queries OCI to see if an instance with the configured DisplayName already exists
-// and is in a non-terminated state.
-func (w *AccountWorker) checkExisting(ctx context.Context) (bool, error) {
+// observe feeds an API call outcome into the tenancy's request budget, the account's
+// failure ratio and the shared circuit breaker, and alerts once when the error budget is
+// exhausted.
+func (w *AccountWorker) observe(err error) {
+	w.recordOutcome(err)
+	if backoff := w.Pacer.Observe(w.Config.TenancyOCID, err); backoff > 0 {
+		w.Logger.Warn(w.AccountName, fmt.Sprintf("Tenancy rate limited - holding its accounts back for %v", backoff))
+	}
+	if !isTransientAPIError(err) {
+		w.Breaker.Success()
+		return
+	}
+	if !w.Breaker.Failure() {
+		return
+	}
+
+	cooldown := w.Breaker.cooldown
+	w.Logger.Error("BREAKER", fmt.Sprintf("Too many consecutive API failures (last: %v). Pausing all accounts for %v.", err, cooldown))
+	if err := w.Notifier.Send(notifier.Message{
+		Title: "🔌 Circuit Breaker Open",
+		Fields: []notifier.Field{
+			{Name: "Account", Value: w.AccountName},
+			{Name: "Paused For", Value: cooldown.String()},
+			{Name: "Last Error", Value: err.Error()},
+		},
+		Color:    notifier.ColorError,
+		Priority: 4,
+		Tags:     "electric_plug,warning",
+	}); err != nil {
+		w.Logger.Error(w.AccountName, fmt.Sprintf("Notification failed: %v", err))
+	}
+}
+
+// instanceCount is how many instances the account keeps: instance_count, at least 1.
+func (w *AccountWorker) instanceCount() int {
+	return max(w.Config.InstanceCount, 1)
+}
+
+// instanceNames renders the display name and hostname label for sequence number seq.
+func (w *AccountWorker) instanceNames(seq int) (string, string, error) {
+	vars := config.NameVars{
+		Account: w.AccountName,
+		Region:  w.Config.Region,
+		Shape:   w.Config.Shape,
+		Seq:     seq,
+	}
+	displayName, err := config.RenderName(w.Config.DisplayName, vars)
+	if err != nil {
+		return "", "", fmt.Errorf("display_name template: %w", err)
+	}
+	hostname, err := config.RenderHostname(w.Config.HostnameLabel, vars)
+	if err != nil {
+		return "", "", fmt.Errorf("hostname_label template: %w", err)
+	}
+	return displayName, hostname, nil
+}
+
+// trackExisting records an instance found by its display name, so teardown, reclaim
+// and the IP hook watch it like one the tool launched.
+func (w *AccountWorker) trackExisting(ctx context.Context, live core.Instance) {
+	id := safeString(live.Id)
+	if id == "" {
+		return
+	}
+	var inst state.Instance
+	for _, known := range w.State.Instances(w.AccountName) {
+		if known.ID == id {
+			inst = known
+		}
+	}
+	inst.ID, inst.Name = id, safeString(live.DisplayName)
+	publicIP, _, err := w.primaryIPs(ctx, id)
+	w.observe(err)
+	if err != nil {
+		w.Logger.Warn(w.AccountName, fmt.Sprintf("Could not look up the IP of %s: %v", id, err))
+	}
+	if publicIP != "" {
+		inst.PublicIP = publicIP
+	}
+	if _, err := w.trackInstance(ctx, inst); err != nil {
+		w.Logger.Warn(w.AccountName, fmt.Sprintf("Failed to persist instance: %v", err))
+	}
+}
+
+// checkExisting queries OCI for an instance with the given display name that is running
+// or coming up, and returns it, or nil if there is none.
+func (w *AccountWorker) checkExisting(ctx context.Context, displayName string) (*core.Instance, error) {
 	req := core.ListInstancesRequest{
 		CompartmentId: common.String(w.Config.CompartmentOCID),
-		DisplayName:   common.String(w.Config.DisplayName),
+		DisplayName:   common.String(displayName),
 	}
 	resp, err := w.ComputeClient.ListInstances(ctx, req)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 	for _, inst := range resp.Items {
 		state := inst.LifecycleState
@@ -389,8 +1161,8 @@ func (w *AccountWorker) checkExisting(ctx context.Context) (bool, error) {
 		if state == core.InstanceLifecycleStateRunning ||
 			state == core.InstanceLifecycleStateProvisioning ||
 			state == core.InstanceLifecycleStateStarting {
-			return true, nil
+			return &inst, nil
 		}
 	}
-	return false, nil
+	return nil, nil
 }
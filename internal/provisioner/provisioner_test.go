@@ -2,71 +2,47 @@ package provisioner
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/gofrs/flock"
+	"github.com/oracle/oci-go-sdk/v65/announcementsservice"
+	"github.com/oracle/oci-go-sdk/v65/common"
 	"github.com/oracle/oci-go-sdk/v65/core"
 	"github.com/oracle/oci-go-sdk/v65/identity"
+	"github.com/oracle/oci-go-sdk/v65/limits"
+	"github.com/oracle/oci-go-sdk/v65/secrets"
+	"github.com/oracle/oci-go-sdk/v65/usageapi"
+	"github.com/yourusername/oci-arm-provisioner/internal/buildinfo"
 	"github.com/yourusername/oci-arm-provisioner/internal/config"
+	"github.com/yourusername/oci-arm-provisioner/internal/errorclass"
+	"github.com/yourusername/oci-arm-provisioner/internal/iphook"
 	"github.com/yourusername/oci-arm-provisioner/internal/logger"
 	"github.com/yourusername/oci-arm-provisioner/internal/notifier"
+	"github.com/yourusername/oci-arm-provisioner/internal/report"
+	"github.com/yourusername/oci-arm-provisioner/internal/state"
 )
 
 // --- Mocks ---
 
-type MockClient struct {
-	ListInstancesFunc       func(ctx context.Context, request core.ListInstancesRequest) (core.ListInstancesResponse, error)
-	LaunchInstanceFunc      func(ctx context.Context, request core.LaunchInstanceRequest) (core.LaunchInstanceResponse, error)
-	ListADsFunc             func(ctx context.Context, request identity.ListAvailabilityDomainsRequest) (identity.ListAvailabilityDomainsResponse, error)
-	GetInstanceFunc         func(ctx context.Context, request core.GetInstanceRequest) (core.GetInstanceResponse, error)
-	ListVnicAttachmentsFunc func(ctx context.Context, request core.ListVnicAttachmentsRequest) (core.ListVnicAttachmentsResponse, error)
-}
-
-func (m *MockClient) ListInstances(ctx context.Context, request core.ListInstancesRequest) (core.ListInstancesResponse, error) {
-	if m.ListInstancesFunc != nil {
-		return m.ListInstancesFunc(ctx, request)
-	}
-	return core.ListInstancesResponse{}, nil
-}
-
-func (m *MockClient) LaunchInstance(ctx context.Context, request core.LaunchInstanceRequest) (core.LaunchInstanceResponse, error) {
-	if m.LaunchInstanceFunc != nil {
-		return m.LaunchInstanceFunc(ctx, request)
-	}
-	return core.LaunchInstanceResponse{}, nil
-}
-
-func (m *MockClient) ListAvailabilityDomains(ctx context.Context, request identity.ListAvailabilityDomainsRequest) (identity.ListAvailabilityDomainsResponse, error) {
-	if m.ListADsFunc != nil {
-		return m.ListADsFunc(ctx, request)
-	}
-	return identity.ListAvailabilityDomainsResponse{}, nil
-}
-
-func (m *MockClient) GetInstance(ctx context.Context, request core.GetInstanceRequest) (core.GetInstanceResponse, error) {
-	if m.GetInstanceFunc != nil {
-		return m.GetInstanceFunc(ctx, request)
-	}
-	return core.GetInstanceResponse{}, nil
-}
-
-func (m *MockClient) ListVnicAttachments(ctx context.Context, request core.ListVnicAttachmentsRequest) (core.ListVnicAttachmentsResponse, error) {
-	if m.ListVnicAttachmentsFunc != nil {
-		return m.ListVnicAttachmentsFunc(ctx, request)
-	}
-	return core.ListVnicAttachmentsResponse{}, nil
-}
-
-// MockVirtualNetworkClient mocks VirtualNetworkClientOps interface
-type MockVirtualNetworkClient struct {
-	GetVnicFunc func(ctx context.Context, request core.GetVnicRequest) (core.GetVnicResponse, error)
-}
-
-func (m *MockVirtualNetworkClient) GetVnic(ctx context.Context, request core.GetVnicRequest) (core.GetVnicResponse, error) {
-	if m.GetVnicFunc != nil {
-		return m.GetVnicFunc(ctx, request)
-	}
-	return core.GetVnicResponse{}, nil
-}
+// Client mocks are generated into mocks_test.go (see gen_mocks.go).
 
 // Helper to create mocked service error
 func newServiceError(status int, message string) error {
@@ -96,7 +72,7 @@ func newMockLogger() *logger.Logger {
 }
 
 func TestAccountWorker_Provision_InstanceExists(t *testing.T) {
-	mock := &MockClient{
+	mock := &MockComputeClient{
 		ListInstancesFunc: func(ctx context.Context, request core.ListInstancesRequest) (core.ListInstancesResponse, error) {
 			return core.ListInstancesResponse{
 				Items: []core.Instance{
@@ -114,7 +90,7 @@ func TestAccountWorker_Provision_InstanceExists(t *testing.T) {
 		Notifier:             notifier.New(config.NotificationConfig{Enabled: false}),
 		Tracker:              notifier.NewTracker(),
 		ComputeClient:        mock,
-		IdentityClient:       mock,
+		IdentityClient:       &MockIdentityClient{},
 		VirtualNetworkClient: &MockVirtualNetworkClient{},
 	}
 
@@ -135,14 +111,10 @@ func TestAccountWorker_Provision_LaunchSuccess(t *testing.T) {
 	ocpus := float32(4)
 	memory := float32(24)
 
-	mock := &MockClient{
+	mock := &MockComputeClient{
 		ListInstancesFunc: func(ctx context.Context, request core.ListInstancesRequest) (core.ListInstancesResponse, error) {
 			return core.ListInstancesResponse{Items: []core.Instance{}}, nil
 		},
-		ListADsFunc: func(ctx context.Context, request identity.ListAvailabilityDomainsRequest) (identity.ListAvailabilityDomainsResponse, error) {
-			ad := "AD-1"
-			return identity.ListAvailabilityDomainsResponse{Items: []identity.AvailabilityDomain{{Name: &ad}}}, nil
-		},
 		LaunchInstanceFunc: func(ctx context.Context, request core.LaunchInstanceRequest) (core.LaunchInstanceResponse, error) {
 			return core.LaunchInstanceResponse{Instance: core.Instance{Id: &instID}}, nil
 		},
@@ -165,15 +137,21 @@ func TestAccountWorker_Provision_LaunchSuccess(t *testing.T) {
 	}
 
 	w := &AccountWorker{
-		AccountName:          "test",
-		Config:               &config.AccountConfig{AvailabilityDomain: "auto", OCPUs: 4, MemoryGB: 24},
-		Logger:               newMockLogger(),
-		Notifier:             notifier.New(config.NotificationConfig{Enabled: false}),
-		Tracker:              notifier.NewTracker(),
-		ComputeClient:        mock,
-		IdentityClient:       mock,
+		AccountName:   "test",
+		Config:        &config.AccountConfig{AvailabilityDomain: "auto", OCPUs: 4, MemoryGB: 24},
+		Logger:        newMockLogger(),
+		Notifier:      notifier.New(config.NotificationConfig{Enabled: false}),
+		Tracker:       notifier.NewTracker(),
+		ComputeClient: mock,
+		IdentityClient: &MockIdentityClient{
+			ListAvailabilityDomainsFunc: func(ctx context.Context, request identity.ListAvailabilityDomainsRequest) (identity.ListAvailabilityDomainsResponse, error) {
+				ad := "AD-1"
+				return identity.ListAvailabilityDomainsResponse{Items: []identity.AvailabilityDomain{{Name: &ad}}}, nil
+			},
+		},
 		VirtualNetworkClient: &MockVirtualNetworkClient{},
 	}
+	w.State, _ = state.Open(nil)
 
 	success, retry, err := w.Provision(context.Background())
 	if err != nil {
@@ -185,10 +163,22 @@ func TestAccountWorker_Provision_LaunchSuccess(t *testing.T) {
 	if retry {
 		t.Error("expected retry=false")
 	}
+	if w.Verified == nil || w.Verified.InstanceID != instID || w.Verified.OCPUs != ocpus {
+		t.Errorf("expected the verified instance to be kept, got %+v", w.Verified)
+	}
+
+	// How it came up is kept in the history and the run's metrics
+	history := w.State.Verifications("test")
+	if len(history) != 1 || history[0].InstanceID != instID || history[0].RunningSeconds <= 0 || history[0].PublicIPSeconds != 0 || history[0].SpecsMismatch {
+		t.Errorf("expected a verification without public IP in the history, got %+v", history)
+	}
+	if got := w.Tracker.Snapshot().Accounts["test"].Verifications; len(got) != 1 || got[0].RunningAfter <= 0 {
+		t.Errorf("expected the verification in the metrics, got %+v", got)
+	}
 }
 
 func TestAccountWorker_Provision_OutOfCapacity(t *testing.T) {
-	mock := &MockClient{
+	mock := &MockComputeClient{
 		ListInstancesFunc: func(ctx context.Context, request core.ListInstancesRequest) (core.ListInstancesResponse, error) {
 			return core.ListInstancesResponse{Items: []core.Instance{}}, nil
 		},
@@ -204,7 +194,7 @@ func TestAccountWorker_Provision_OutOfCapacity(t *testing.T) {
 		Notifier:             notifier.New(config.NotificationConfig{Enabled: false}),
 		Tracker:              notifier.NewTracker(),
 		ComputeClient:        mock,
-		IdentityClient:       mock,
+		IdentityClient:       &MockIdentityClient{},
 		VirtualNetworkClient: &MockVirtualNetworkClient{},
 	}
 
@@ -220,8 +210,334 @@ func TestAccountWorker_Provision_OutOfCapacity(t *testing.T) {
 	}
 }
 
+func TestAccountWorker_Provision_ShapeFallback(t *testing.T) {
+	var launched []core.LaunchInstanceRequest
+	mock := &MockComputeClient{
+		ListInstancesFunc: func(ctx context.Context, request core.ListInstancesRequest) (core.ListInstancesResponse, error) {
+			return core.ListInstancesResponse{Items: []core.Instance{}}, nil
+		},
+		LaunchInstanceFunc: func(ctx context.Context, request core.LaunchInstanceRequest) (core.LaunchInstanceResponse, error) {
+			launched = append(launched, request)
+			return core.LaunchInstanceResponse{}, newServiceError(500, "Out of host capacity")
+		},
+	}
+
+	w := &AccountWorker{
+		AccountName: "test",
+		Config: &config.AccountConfig{
+			AvailabilityDomain: "AD-1",
+			Shape:              "VM.Standard.E4.Flex",
+			OCPUs:              2,
+			MemoryGB:           8,
+			ShapeFallbacks:     []config.ShapeOption{{Shape: "VM.Standard.E2.1.Micro"}},
+			FallbackAfter:      2,
+		},
+		Logger:               newMockLogger(),
+		Notifier:             notifier.New(config.NotificationConfig{Enabled: false}),
+		Tracker:              notifier.NewTracker(),
+		ComputeClient:        mock,
+		IdentityClient:       &MockIdentityClient{},
+		VirtualNetworkClient: &MockVirtualNetworkClient{},
+	}
+
+	for range 5 {
+		if _, retry, err := w.Provision(context.Background()); err != nil || !retry {
+			t.Fatalf("expected a retry, got retry=%v err=%v", retry, err)
+		}
+	}
+
+	want := []string{"VM.Standard.E4.Flex", "VM.Standard.E4.Flex", "VM.Standard.E2.1.Micro", "VM.Standard.E2.1.Micro", "VM.Standard.E4.Flex"}
+	if len(launched) != len(want) {
+		t.Fatalf("expected %d launches, got %d", len(want), len(launched))
+	}
+	for i, req := range launched {
+		if *req.Shape != want[i] {
+			t.Errorf("launch %d: expected shape %s, got %s", i, want[i], *req.Shape)
+		}
+		if fixed := want[i] == "VM.Standard.E2.1.Micro"; fixed != (req.ShapeConfig == nil) {
+			t.Errorf("launch %d (%s): unexpected shape config %+v", i, *req.Shape, req.ShapeConfig)
+		}
+	}
+}
+
+func TestAccountWorker_Provision_DedicatedHost(t *testing.T) {
+	var hostID string
+	mock := &MockComputeClient{
+		ListInstancesFunc: func(ctx context.Context, request core.ListInstancesRequest) (core.ListInstancesResponse, error) {
+			return core.ListInstancesResponse{Items: []core.Instance{}}, nil
+		},
+		LaunchInstanceFunc: func(ctx context.Context, request core.LaunchInstanceRequest) (core.LaunchInstanceResponse, error) {
+			hostID = *request.DedicatedVmHostId
+			return core.LaunchInstanceResponse{}, newServiceError(500, "Out of host capacity")
+		},
+	}
+
+	w := &AccountWorker{
+		AccountName:          "test",
+		Config:               &config.AccountConfig{AvailabilityDomain: "AD-1", DedicatedVMHostOCID: "ocid1.dedicatedvmhost.oc1..host"},
+		Logger:               newMockLogger(),
+		Notifier:             notifier.New(config.NotificationConfig{Enabled: false}),
+		Tracker:              notifier.NewTracker(),
+		ComputeClient:        mock,
+		IdentityClient:       &MockIdentityClient{},
+		VirtualNetworkClient: &MockVirtualNetworkClient{},
+	}
+
+	// The host's capacity is reserved: a capacity error is a config bug, not worth retrying
+	success, retry, err := w.Provision(context.Background())
+	if success || retry {
+		t.Errorf("expected success=false retry=false, got %v %v", success, retry)
+	}
+	if err == nil || !strings.Contains(err.Error(), "dedicated VM host") {
+		t.Errorf("expected a dedicated host error, got %v", err)
+	}
+	if hostID != "ocid1.dedicatedvmhost.oc1..host" {
+		t.Errorf("expected the launch on the dedicated host, got %q", hostID)
+	}
+}
+
+func TestAccountWorker_Provision_RotateADs(t *testing.T) {
+	for _, tc := range []struct {
+		tryAll bool
+		want   []string
+	}{
+		{tryAll: false, want: []string{"AD-1", "AD-2", "AD-3", "AD-1"}},
+		{tryAll: true, want: []string{"AD-1", "AD-2", "AD-3", "AD-2", "AD-3", "AD-1", "AD-3", "AD-1", "AD-2", "AD-1", "AD-2", "AD-3"}},
+	} {
+		var launched []string
+		mock := &MockComputeClient{
+			ListInstancesFunc: func(ctx context.Context, request core.ListInstancesRequest) (core.ListInstancesResponse, error) {
+				return core.ListInstancesResponse{Items: []core.Instance{}}, nil
+			},
+			LaunchInstanceFunc: func(ctx context.Context, request core.LaunchInstanceRequest) (core.LaunchInstanceResponse, error) {
+				launched = append(launched, *request.AvailabilityDomain)
+				return core.LaunchInstanceResponse{}, newServiceError(500, "Out of host capacity")
+			},
+		}
+		identityMock := &MockIdentityClient{
+			ListAvailabilityDomainsFunc: func(ctx context.Context, request identity.ListAvailabilityDomainsRequest) (identity.ListAvailabilityDomainsResponse, error) {
+				return identity.ListAvailabilityDomainsResponse{Items: []identity.AvailabilityDomain{
+					{Name: common.String("AD-1")}, {Name: common.String("AD-2")}, {Name: common.String("AD-3")},
+				}}, nil
+			},
+		}
+
+		w := &AccountWorker{
+			AccountName:          "test",
+			Config:               &config.AccountConfig{AvailabilityDomain: "auto", TryAllADs: tc.tryAll},
+			Logger:               newMockLogger(),
+			Notifier:             notifier.New(config.NotificationConfig{Enabled: false}),
+			Tracker:              notifier.NewTracker(),
+			ComputeClient:        mock,
+			IdentityClient:       identityMock,
+			VirtualNetworkClient: &MockVirtualNetworkClient{},
+		}
+		for range 4 {
+			if _, retry, err := w.Provision(context.Background()); err != nil || !retry {
+				t.Fatalf("expected a retry, got retry=%v err=%v", retry, err)
+			}
+		}
+
+		if !slices.Equal(launched, tc.want) {
+			t.Errorf("try_all_ads=%v: expected launches in %v, got %v", tc.tryAll, tc.want, launched)
+		}
+		if ads := w.Tracker.Snapshot().Accounts["test"].ADs; len(ads) != 3 || ads["AD-1"].CapacityErrors != ads["AD-1"].Attempts {
+			t.Errorf("try_all_ads=%v: unexpected AD stats %v", tc.tryAll, ads)
+		}
+	}
+}
+
+func TestAccountWorker_Provision_LimitExceeded(t *testing.T) {
+	launches := 0
+	mock := &MockComputeClient{
+		ListInstancesFunc: func(ctx context.Context, request core.ListInstancesRequest) (core.ListInstancesResponse, error) {
+			return core.ListInstancesResponse{Items: []core.Instance{}}, nil
+		},
+		LaunchInstanceFunc: func(ctx context.Context, request core.LaunchInstanceRequest) (core.LaunchInstanceResponse, error) {
+			launches++
+			return core.LaunchInstanceResponse{}, newServiceError(400, "The following service limits were exceeded: standard-a1-core-count. Request a service limit increase from the service limits page in the console.")
+		},
+	}
+	var available int64
+	limitsMock := &MockLimitsClient{
+		GetResourceAvailabilityFunc: func(ctx context.Context, request limits.GetResourceAvailabilityRequest) (limits.GetResourceAvailabilityResponse, error) {
+			if *request.LimitName != "standard-a1-core-count" || *request.AvailabilityDomain != "AD-1" {
+				t.Errorf("unexpected limit check %s in %s", *request.LimitName, *request.AvailabilityDomain)
+			}
+			return limits.GetResourceAvailabilityResponse{ResourceAvailability: limits.ResourceAvailability{
+				Used: common.Int64(4 - available), Available: common.Int64(available),
+			}}, nil
+		},
+	}
+
+	w := &AccountWorker{
+		AccountName:          "test",
+		Config:               &config.AccountConfig{AvailabilityDomain: "AD-1", OCPUs: 4, MemoryGB: 24},
+		Logger:               newMockLogger(),
+		Notifier:             notifier.New(config.NotificationConfig{Enabled: false}),
+		Tracker:              notifier.NewTracker(),
+		ComputeClient:        mock,
+		IdentityClient:       &MockIdentityClient{},
+		VirtualNetworkClient: &MockVirtualNetworkClient{},
+		LimitsClient:         limitsMock,
+	}
+
+	// Used up: the account stops instead of retrying
+	_, retry, err := w.Provision(context.Background())
+	if retry || err == nil || !strings.Contains(err.Error(), "service limit exhausted") {
+		t.Fatalf("expected a non-retryable limit error, got retry=%v err=%v", retry, err)
+	}
+	if _, retry, err := w.Provision(context.Background()); retry || err != nil || launches != 1 {
+		t.Fatalf("expected the next cycle to skip the launch, got retry=%v err=%v launches=%d", retry, err, launches)
+	}
+
+	// Room again (e.g. an old instance was terminated): launches resume, and a limit
+	// error with room left is retried like capacity
+	available = 4
+	if _, retry, err := w.Provision(context.Background()); !retry || err != nil || launches != 2 {
+		t.Fatalf("expected a retried launch, got retry=%v err=%v launches=%d", retry, err, launches)
+	}
+}
+
+func TestAccountWorker_Provision_CheckLimits(t *testing.T) {
+	launches := 0
+	mock := &MockComputeClient{
+		ListInstancesFunc: func(ctx context.Context, request core.ListInstancesRequest) (core.ListInstancesResponse, error) {
+			return core.ListInstancesResponse{Items: []core.Instance{}}, nil
+		},
+		LaunchInstanceFunc: func(ctx context.Context, request core.LaunchInstanceRequest) (core.LaunchInstanceResponse, error) {
+			launches++
+			return core.LaunchInstanceResponse{}, newServiceError(500, "Out of host capacity")
+		},
+	}
+	var available int64
+	checked := 0
+	limitsMock := &MockLimitsClient{
+		GetResourceAvailabilityFunc: func(ctx context.Context, request limits.GetResourceAvailabilityRequest) (limits.GetResourceAvailabilityResponse, error) {
+			checked++
+			scale := int64(1)
+			if *request.LimitName == "standard-a1-memory-count" {
+				scale = 6
+			}
+			return limits.GetResourceAvailabilityResponse{ResourceAvailability: limits.ResourceAvailability{
+				Used: common.Int64((4 - available) * scale), Available: common.Int64(available * scale),
+			}}, nil
+		},
+	}
+
+	w := &AccountWorker{
+		AccountName:          "test",
+		Config:               &config.AccountConfig{Shape: "VM.Standard.A1.Flex", AvailabilityDomain: "AD-1", OCPUs: 4, MemoryGB: 24, CheckLimits: true},
+		Logger:               newMockLogger(),
+		Notifier:             notifier.New(config.NotificationConfig{Enabled: false}),
+		Tracker:              notifier.NewTracker(),
+		ComputeClient:        mock,
+		IdentityClient:       &MockIdentityClient{},
+		VirtualNetworkClient: &MockVirtualNetworkClient{},
+		LimitsClient:         limitsMock,
+	}
+
+	// Used up: no launch is sent, and the account shows as limited
+	for range 2 {
+		if _, retry, err := w.Provision(context.Background()); retry || err != nil || launches != 0 {
+			t.Fatalf("expected no launch while the limits are used up, got retry=%v err=%v launches=%d", retry, err, launches)
+		}
+	}
+	if limited := w.Tracker.Snapshot().Accounts["test"].Limited; !strings.Contains(limited, "standard-a1-core-count (4 used, 0 available)") {
+		t.Errorf("expected the account marked limited, got %q", limited)
+	}
+
+	available = 4
+	if _, retry, err := w.Provision(context.Background()); !retry || err != nil || launches != 1 {
+		t.Fatalf("expected a launch once the limits have room, got retry=%v err=%v launches=%d", retry, err, launches)
+	}
+	if limited := w.Tracker.Snapshot().Accounts["test"].Limited; limited != "" {
+		t.Errorf("expected the limited state cleared, got %q", limited)
+	}
+
+	// Without the option the limits aren't asked
+	w.Config.CheckLimits, checked = false, 0
+	w.Provision(context.Background())
+	if checked != 0 || launches != 2 {
+		t.Errorf("expected a launch without limit checks, got %d checks and %d launches", checked, launches)
+	}
+}
+
+func TestAccountWorker_Provision_Tags(t *testing.T) {
+	var launched core.LaunchInstanceDetails
+	mock := &MockComputeClient{
+		ListInstancesFunc: func(ctx context.Context, request core.ListInstancesRequest) (core.ListInstancesResponse, error) {
+			return core.ListInstancesResponse{Items: []core.Instance{}}, nil
+		},
+		LaunchInstanceFunc: func(ctx context.Context, request core.LaunchInstanceRequest) (core.LaunchInstanceResponse, error) {
+			launched = request.LaunchInstanceDetails
+			return core.LaunchInstanceResponse{}, newServiceError(500, "Out of host capacity")
+		},
+	}
+
+	w := &AccountWorker{
+		AccountName: "test",
+		Config: &config.AccountConfig{
+			AvailabilityDomain: "AD-1",
+			FreeformTags:       map[string]string{"env": "lab", ownerTagKey: "someone-else"},
+			DefinedTags:        map[string]map[string]string{"Operations": {"CostCenter": "42"}},
+		},
+		Logger:               newMockLogger(),
+		Notifier:             notifier.New(config.NotificationConfig{Enabled: false}),
+		Tracker:              notifier.NewTracker(),
+		ComputeClient:        mock,
+		IdentityClient:       &MockIdentityClient{},
+		VirtualNetworkClient: &MockVirtualNetworkClient{},
+	}
+	w.Provision(context.Background())
+
+	// The owner tags can't be overridden, or discovery would lose the instance
+	if launched.FreeformTags["env"] != "lab" || launched.FreeformTags[ownerTagKey] != buildinfo.Product || launched.FreeformTags[accountTagKey] != "test" {
+		t.Errorf("unexpected freeform tags %v", launched.FreeformTags)
+	}
+	if launched.DefinedTags["Operations"]["CostCenter"] != "42" {
+		t.Errorf("unexpected defined tags %v", launched.DefinedTags)
+	}
+}
+
+func TestAccountWorker_Provision_BootVolumeVPUs(t *testing.T) {
+	var source core.InstanceSourceViaImageDetails
+	mock := &MockComputeClient{
+		ListInstancesFunc: func(ctx context.Context, request core.ListInstancesRequest) (core.ListInstancesResponse, error) {
+			return core.ListInstancesResponse{Items: []core.Instance{}}, nil
+		},
+		LaunchInstanceFunc: func(ctx context.Context, request core.LaunchInstanceRequest) (core.LaunchInstanceResponse, error) {
+			source = request.SourceDetails.(core.InstanceSourceViaImageDetails)
+			return core.LaunchInstanceResponse{}, newServiceError(500, "Out of host capacity")
+		},
+	}
+
+	w := &AccountWorker{
+		AccountName:          "test",
+		Config:               &config.AccountConfig{AvailabilityDomain: "AD-1", BootVolumeSizeGB: 100, BootVolumeVPUsPerGB: 20},
+		Logger:               newMockLogger(),
+		Notifier:             notifier.New(config.NotificationConfig{Enabled: false}),
+		Tracker:              notifier.NewTracker(),
+		ComputeClient:        mock,
+		IdentityClient:       &MockIdentityClient{},
+		VirtualNetworkClient: &MockVirtualNetworkClient{},
+	}
+	w.Provision(context.Background())
+
+	if source.BootVolumeVpusPerGB == nil || *source.BootVolumeVpusPerGB != 20 || *source.BootVolumeSizeInGBs != 100 {
+		t.Errorf("unexpected boot volume in the launch request: %+v", source)
+	}
+
+	// Without the option OCI's default (Balanced) applies
+	w.Config.BootVolumeVPUsPerGB = 0
+	w.Provision(context.Background())
+	if source.BootVolumeVpusPerGB != nil {
+		t.Errorf("expected no VPUs in the launch request, got %d", *source.BootVolumeVpusPerGB)
+	}
+}
+
 func TestAccountWorker_Provision_RateLimit(t *testing.T) {
-	mock := &MockClient{
+	mock := &MockComputeClient{
 		ListInstancesFunc: func(ctx context.Context, request core.ListInstancesRequest) (core.ListInstancesResponse, error) {
 			return core.ListInstancesResponse{Items: []core.Instance{}}, nil
 		},
@@ -237,7 +553,7 @@ func TestAccountWorker_Provision_RateLimit(t *testing.T) {
 		Notifier:             notifier.New(config.NotificationConfig{Enabled: false}),
 		Tracker:              notifier.NewTracker(),
 		ComputeClient:        mock,
-		IdentityClient:       mock,
+		IdentityClient:       &MockIdentityClient{},
 		VirtualNetworkClient: &MockVirtualNetworkClient{},
 	}
 
@@ -263,7 +579,7 @@ func TestVerifyInstance_Success(t *testing.T) {
 	privateIP := "192.168.1.1"
 	vnicID := "vnic-1"
 
-	mock := &MockClient{
+	mock := &MockComputeClient{
 		GetInstanceFunc: func(ctx context.Context, request core.GetInstanceRequest) (core.GetInstanceResponse, error) {
 			return core.GetInstanceResponse{
 				Instance: core.Instance{
@@ -327,7 +643,7 @@ func TestVerifyInstance_SpecsMismatch(t *testing.T) {
 	ocpus := float32(2)   // Different from config
 	memory := float32(12) // Different from config
 
-	mock := &MockClient{
+	mock := &MockComputeClient{
 		GetInstanceFunc: func(ctx context.Context, request core.GetInstanceRequest) (core.GetInstanceResponse, error) {
 			return core.GetInstanceResponse{
 				Instance: core.Instance{
@@ -368,7 +684,7 @@ func TestVerifyInstance_SpecsMismatch(t *testing.T) {
 func TestVerifyInstance_Terminated(t *testing.T) {
 	instID := "inst-terminated"
 
-	mock := &MockClient{
+	mock := &MockComputeClient{
 		GetInstanceFunc: func(ctx context.Context, request core.GetInstanceRequest) (core.GetInstanceResponse, error) {
 			return core.GetInstanceResponse{
 				Instance: core.Instance{
@@ -393,6 +709,36 @@ func TestVerifyInstance_Terminated(t *testing.T) {
 	}
 }
 
+func TestVerifyInstance_Timeout(t *testing.T) {
+	instID := "inst-slow"
+	mock := &MockComputeClient{
+		GetInstanceFunc: func(ctx context.Context, request core.GetInstanceRequest) (core.GetInstanceResponse, error) {
+			return core.GetInstanceResponse{
+				Instance: core.Instance{Id: &instID, LifecycleState: core.InstanceLifecycleStateProvisioning},
+			}, nil
+		},
+	}
+	w := &AccountWorker{
+		AccountName:          "test",
+		Config:               &config.AccountConfig{},
+		Logger:               newMockLogger(),
+		ComputeClient:        mock,
+		VirtualNetworkClient: &MockVirtualNetworkClient{},
+		PollInterval:         10 * time.Millisecond,
+		VerifyTimeout:        verifyLookupTime + 50*time.Millisecond,
+	}
+
+	// The wait for RUNNING is the verification window less the lookup time
+	start := time.Now()
+	_, err := w.VerifyInstance(context.Background(), instID)
+	if err == nil || !strings.Contains(err.Error(), "not running after 50ms") || time.Since(start) > 5*time.Second {
+		t.Errorf("expected a 50ms wait for RUNNING, got %v after %v", err, time.Since(start))
+	}
+	if d := (&AccountWorker{}).runningWait(); d != 5*time.Minute {
+		t.Errorf("expected a 5m wait by default, got %v", d)
+	}
+}
+
 func TestVerifyInstance_IPRetrieval(t *testing.T) {
 	instID := "inst-ip"
 	ocpus := float32(4)
@@ -400,7 +746,7 @@ func TestVerifyInstance_IPRetrieval(t *testing.T) {
 	publicIP := "203.0.113.42"
 	vnicID := "vnic-primary"
 
-	mock := &MockClient{
+	mock := &MockComputeClient{
 		GetInstanceFunc: func(ctx context.Context, request core.GetInstanceRequest) (core.GetInstanceResponse, error) {
 			return core.GetInstanceResponse{
 				Instance: core.Instance{
@@ -425,14 +771,14 @@ func TestVerifyInstance_IPRetrieval(t *testing.T) {
 	mockVNet := &MockVirtualNetworkClient{
 		GetVnicFunc: func(ctx context.Context, request core.GetVnicRequest) (core.GetVnicResponse, error) {
 			return core.GetVnicResponse{
-				Vnic: core.Vnic{PublicIp: &publicIP},
+				Vnic: core.Vnic{PublicIp: &publicIP, Ipv6Addresses: []string{"2001:db8::42"}},
 			}, nil
 		},
 	}
 
 	w := &AccountWorker{
 		AccountName:          "test",
-		Config:               &config.AccountConfig{OCPUs: 4, MemoryGB: 24},
+		Config:               &config.AccountConfig{OCPUs: 4, MemoryGB: 24, AssignIPv6: true},
 		Logger:               newMockLogger(),
 		ComputeClient:        mock,
 		VirtualNetworkClient: mockVNet,
@@ -445,6 +791,9 @@ func TestVerifyInstance_IPRetrieval(t *testing.T) {
 	if result.PublicIP != publicIP {
 		t.Errorf("expected public IP %s, got %s", publicIP, result.PublicIP)
 	}
+	if result.IPv6 != "2001:db8::42" {
+		t.Errorf("expected IPv6 2001:db8::42, got %s", result.IPv6)
+	}
 }
 
 func TestProvisioner_SkipProvisionedAccounts(t *testing.T) {
@@ -471,7 +820,7 @@ func TestProvisioner_SkipProvisionedAccounts(t *testing.T) {
 
 	// For this test, we need to set up mock clients on the workers
 	for _, worker := range p.Workers {
-		worker.ComputeClient = &MockClient{
+		worker.ComputeClient = &MockComputeClient{
 			ListInstancesFunc: func(ctx context.Context, request core.ListInstancesRequest) (core.ListInstancesResponse, error) {
 				// Return existing instance to prevent actual provisioning
 				return core.ListInstancesResponse{
@@ -479,7 +828,7 @@ func TestProvisioner_SkipProvisionedAccounts(t *testing.T) {
 				}, nil
 			},
 		}
-		worker.IdentityClient = &MockClient{}
+		worker.IdentityClient = &MockIdentityClient{}
 		worker.VirtualNetworkClient = &MockVirtualNetworkClient{}
 	}
 
@@ -515,3 +864,2671 @@ func TestTracker_IncSuccess(t *testing.T) {
 		t.Errorf("expected snapshot SuccessCount=2, got %d", snapshot.SuccessCount)
 	}
 }
+
+// --- Circuit Breaker Tests ---
+
+func TestBreaker_TripsAndRecovers(t *testing.T) {
+	now := time.Now()
+	b := NewBreaker(3, 10*time.Minute)
+	b.now = func() time.Time { return now }
+
+	if b.Failure() || b.Failure() {
+		t.Fatal("breaker tripped before threshold")
+	}
+	b.Success() // A healthy response resets the budget
+	b.Failure()
+	b.Failure()
+	if !b.Failure() {
+		t.Fatal("expected breaker to trip on 3rd consecutive failure")
+	}
+	if ok, _ := b.Allow(); ok {
+		t.Error("expected breaker to block attempts while open")
+	}
+
+	now = now.Add(11 * time.Minute)
+	if ok, _ := b.Allow(); !ok {
+		t.Error("expected breaker to close after cooldown")
+	}
+}
+
+// --- Tenancy Pacer Tests ---
+
+// fakePacerClock replaces the Pacer's clock and sleep, recording the waits.
+func fakePacerClock(p *Pacer) *[]time.Duration {
+	now := time.Now()
+	var waits []time.Duration
+	p.now = func() time.Time { return now }
+	p.sleep = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		now = now.Add(d)
+		return nil
+	}
+	return &waits
+}
+
+func TestPacer_SpacesSharedTenancy(t *testing.T) {
+	p := NewPacer(map[string]string{"alice": "t1", "bob": "t1", "solo": "t2"}, time.Minute)
+	waits := fakePacerClock(p)
+	ctx := context.Background()
+
+	for _, tenancy := range []string{"t1", "t1", "t2", "t2"} {
+		if _, err := p.Wait(ctx, tenancy); err != nil {
+			t.Fatal(err)
+		}
+		p.Done(tenancy)
+	}
+	if len(*waits) != 1 || (*waits)[0] != time.Minute {
+		t.Errorf("expected one 1m wait for the shared tenancy only, got %v", *waits)
+	}
+	if got := p.Accounts("t1"); len(got) != 2 || got[0] != "alice" || got[1] != "bob" {
+		t.Errorf("unexpected accounts for t1: %v", got)
+	}
+}
+
+func TestPacer_BacksOffOnRateLimit(t *testing.T) {
+	p := NewPacer(map[string]string{"solo": "t1"}, 0)
+	waits := fakePacerClock(p)
+	ctx := context.Background()
+
+	if d := p.Observe("t1", newServiceError(429, "TooManyRequests")); d != time.Minute {
+		t.Errorf("expected 1m backoff, got %v", d)
+	}
+	if d := p.Observe("t1", newServiceError(429, "TooManyRequests")); d != 2*time.Minute {
+		t.Errorf("expected backoff to double, got %v", d)
+	}
+	p.Wait(ctx, "t1")
+	p.Done("t1")
+	if len(*waits) != 1 || (*waits)[0] != 2*time.Minute {
+		t.Errorf("expected the next attempt to wait out the backoff, got %v", *waits)
+	}
+
+	p.Observe("t1", nil)
+	if d := p.Observe("t1", newServiceError(429, "TooManyRequests")); d != time.Minute {
+		t.Errorf("expected a success to reset the backoff, got %v", d)
+	}
+	if d := p.Observe("t1", newServiceError(503, "Service Unavailable")); d != 0 {
+		t.Errorf("expected no backoff for non-429 errors, got %v", d)
+	}
+
+	var nilPacer *Pacer
+	if d, err := nilPacer.Wait(ctx, "t1"); d != 0 || err != nil {
+		t.Error("expected a nil Pacer to never wait")
+	}
+}
+
+func TestProvisioner_BreakerPausesAllAccounts(t *testing.T) {
+	cfg := &config.Config{
+		Accounts: map[string]*config.AccountConfig{
+			"account1": {Enabled: true},
+			"account2": {Enabled: true},
+			"account3": {Enabled: true},
+		},
+		Retry: config.RetryConfig{BreakerThreshold: 2, BreakerCooldownMinutes: 30},
+	}
+
+	p := New(cfg, newMockLogger(), notifier.NewTracker())
+
+	calls := 0
+	for _, worker := range p.Workers {
+		worker.ComputeClient = &MockComputeClient{
+			ListInstancesFunc: func(ctx context.Context, request core.ListInstancesRequest) (core.ListInstancesResponse, error) {
+				calls++
+				return core.ListInstancesResponse{}, newServiceError(503, "Service Unavailable")
+			},
+		}
+		worker.IdentityClient = &MockIdentityClient{}
+		worker.VirtualNetworkClient = &MockVirtualNetworkClient{}
+	}
+
+	p.RunCycle(context.Background())
+
+	if calls != 2 {
+		t.Errorf("expected breaker to stop after 2 failing accounts, got %d calls", calls)
+	}
+	if ok, _ := p.Breaker.Allow(); ok {
+		t.Error("expected breaker to be open")
+	}
+}
+
+func TestIsTransientAPIError(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"capacity 500", newServiceError(500, "Out of host capacity"), false},
+		{"rate limit", newServiceError(429, "TooManyRequests"), false},
+		{"not found", newServiceError(404, "NotAuthorizedOrNotFound"), false},
+		{"bad gateway", newServiceError(502, "Bad Gateway"), true},
+		{"timeout", context.DeadlineExceeded, true},
+		{"shutdown", context.Canceled, false},
+	}
+	for _, c := range cases {
+		if got := isTransientAPIError(c.err); got != c.want {
+			t.Errorf("%s: got %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+// --- GetInstance Cache Tests ---
+
+func TestInstanceCache_ServesFreshEntries(t *testing.T) {
+	calls := 0
+	mock := &MockComputeClient{
+		GetInstanceFunc: func(ctx context.Context, request core.GetInstanceRequest) (core.GetInstanceResponse, error) {
+			calls++
+			return core.GetInstanceResponse{Instance: core.Instance{LifecycleState: core.InstanceLifecycleStateProvisioning}}, nil
+		},
+	}
+	now := time.Now()
+	c := newInstanceCache(mock, 10*time.Second)
+	c.now = func() time.Time { return now }
+
+	req := core.GetInstanceRequest{InstanceId: common.String("inst-1")}
+	c.GetInstance(context.Background(), req)
+	c.GetInstance(context.Background(), req)
+	if calls != 1 {
+		t.Errorf("expected 1 API call within TTL, got %d", calls)
+	}
+
+	now = now.Add(11 * time.Second)
+	c.GetInstance(context.Background(), req)
+	if calls != 2 {
+		t.Errorf("expected refetch after TTL, got %d calls", calls)
+	}
+}
+
+func TestInstanceCache_RevalidatesWithETag(t *testing.T) {
+	var gotETag string
+	mock := &MockComputeClient{
+		GetInstanceFunc: func(ctx context.Context, request core.GetInstanceRequest) (core.GetInstanceResponse, error) {
+			if etag, ok := ctx.Value(ifNoneMatchKey{}).(string); ok {
+				gotETag = etag
+				return core.GetInstanceResponse{RawResponse: &http.Response{StatusCode: http.StatusNotModified}}, nil
+			}
+			return core.GetInstanceResponse{
+				Instance: core.Instance{LifecycleState: core.InstanceLifecycleStateRunning},
+				Etag:     common.String("v1"),
+			}, nil
+		},
+	}
+	now := time.Now()
+	c := newInstanceCache(mock, time.Second)
+	c.now = func() time.Time { return now }
+
+	req := core.GetInstanceRequest{InstanceId: common.String("inst-1")}
+	c.GetInstance(context.Background(), req)
+	now = now.Add(2 * time.Second)
+	resp, err := c.GetInstance(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotETag != "v1" {
+		t.Errorf("expected If-None-Match v1, got %q", gotETag)
+	}
+	if resp.Instance.LifecycleState != core.InstanceLifecycleStateRunning {
+		t.Errorf("expected cached body on 304, got state %q", resp.Instance.LifecycleState)
+	}
+}
+
+func TestConditionalGet(t *testing.T) {
+	ctx := context.WithValue(context.Background(), ifNoneMatchKey{}, "v2")
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "https://iaas.example.com/instances/x", nil)
+	conditionalGet(req)
+	if got := req.Header.Get("If-None-Match"); got != "v2" {
+		t.Errorf("expected If-None-Match v2, got %q", got)
+	}
+
+	plain, _ := http.NewRequest(http.MethodGet, "https://iaas.example.com/instances/x", nil)
+	conditionalGet(plain)
+	if got := plain.Header.Get("If-None-Match"); got != "" {
+		t.Errorf("expected no header without cached ETag, got %q", got)
+	}
+}
+
+func TestProvisioner_PausedAccountIsSkipped(t *testing.T) {
+	cfg := &config.Config{
+		Accounts: map[string]*config.AccountConfig{
+			"noisy": {Enabled: true},
+			"quiet": {Enabled: true},
+		},
+	}
+
+	p := New(cfg, newMockLogger(), notifier.NewTracker())
+
+	attempts := map[string]int{}
+	for _, worker := range p.Workers {
+		name := worker.AccountName
+		worker.ComputeClient = &MockComputeClient{
+			ListInstancesFunc: func(ctx context.Context, request core.ListInstancesRequest) (core.ListInstancesResponse, error) {
+				attempts[name]++
+				return core.ListInstancesResponse{Items: []core.Instance{{LifecycleState: core.InstanceLifecycleStateRunning}}}, nil
+			},
+		}
+		worker.IdentityClient = &MockIdentityClient{}
+		worker.VirtualNetworkClient = &MockVirtualNetworkClient{}
+	}
+
+	if !p.SetAccountPaused("noisy", true) {
+		t.Fatal("expected pause to change state")
+	}
+	if p.SetAccountPaused("noisy", true) {
+		t.Error("expected repeated pause to be a no-op")
+	}
+	if p.SetAccountPaused("missing", true) {
+		t.Error("expected unknown account to be rejected")
+	}
+
+	p.RunCycle(context.Background())
+
+	if attempts["noisy"] != 0 {
+		t.Errorf("expected paused account to be skipped, got %d attempts", attempts["noisy"])
+	}
+	if attempts["quiet"] != 1 {
+		t.Errorf("expected other account to keep running, got %d attempts", attempts["quiet"])
+	}
+}
+
+func TestProvisioner_PauseSurvivesReload(t *testing.T) {
+	cfg := &config.Config{
+		StateFile: filepath.Join(t.TempDir(), "state.json"),
+		Accounts: map[string]*config.AccountConfig{
+			"noisy": {Enabled: true},
+			"quiet": {Enabled: true},
+		},
+	}
+	p := New(cfg, newMockLogger(), notifier.NewTracker())
+	p.SetAccountPaused("noisy", true)
+
+	// A reload or restart builds a new Provisioner from the same state
+	reloaded := New(cfg, newMockLogger(), notifier.NewTracker())
+	if !reloaded.IsAccountPaused("noisy") || reloaded.IsAccountPaused("quiet") {
+		t.Fatal("expected only noisy to stay paused")
+	}
+	reloaded.SetAccountPaused("noisy", false)
+	if New(cfg, newMockLogger(), notifier.NewTracker()).IsAccountPaused("noisy") {
+		t.Error("expected the resume to persist too")
+	}
+}
+
+func TestAccountWorker_Provision_NamingTemplate(t *testing.T) {
+	instID := "inst-1"
+	var launched core.LaunchInstanceDetails
+	var checked []string
+
+	mock := &MockComputeClient{
+		ListInstancesFunc: func(ctx context.Context, request core.ListInstancesRequest) (core.ListInstancesResponse, error) {
+			checked = append(checked, *request.DisplayName)
+			return core.ListInstancesResponse{}, nil
+		},
+		LaunchInstanceFunc: func(ctx context.Context, request core.LaunchInstanceRequest) (core.LaunchInstanceResponse, error) {
+			launched = request.LaunchInstanceDetails
+			return core.LaunchInstanceResponse{Instance: core.Instance{Id: &instID}}, nil
+		},
+		GetInstanceFunc: func(ctx context.Context, request core.GetInstanceRequest) (core.GetInstanceResponse, error) {
+			return core.GetInstanceResponse{Instance: core.Instance{Id: &instID, LifecycleState: core.InstanceLifecycleStateRunning}}, nil
+		},
+	}
+
+	st, _ := state.Open(nil)
+	st.CommitSequence("work", 2)
+
+	w := &AccountWorker{
+		AccountName: "work",
+		Config: &config.AccountConfig{
+			AvailabilityDomain: "AD-1",
+			DisplayName:        "arm-{{.Account}}-{{.Seq}}",
+			HostnameLabel:      "arm{{.Seq}}",
+		},
+		Logger:               newMockLogger(),
+		Notifier:             notifier.New(config.NotificationConfig{Enabled: false}),
+		Tracker:              notifier.NewTracker(),
+		State:                st,
+		ComputeClient:        mock,
+		IdentityClient:       &MockIdentityClient{},
+		VirtualNetworkClient: &MockVirtualNetworkClient{},
+	}
+
+	if _, _, err := w.Provision(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(checked) != 1 || checked[0] != "arm-work-2" {
+		t.Errorf("expected existing-instance check for arm-work-2, got %v", checked)
+	}
+	if *launched.DisplayName != "arm-work-3" || *launched.CreateVnicDetails.HostnameLabel != "arm3" {
+		t.Errorf("expected launch as arm-work-3/arm3, got %s/%s", *launched.DisplayName, *launched.CreateVnicDetails.HostnameLabel)
+	}
+	if got := st.Sequence("work"); got != 3 {
+		t.Errorf("expected sequence 3 to be committed, got %d", got)
+	}
+}
+
+func TestAccountWorker_Provision_HostnameConflict(t *testing.T) {
+	instID := "inst-1"
+	conflict := newServiceError(400, "Hostname label 'armvm' is already in use in subnet")
+
+	for _, autoSuffix := range []bool{false, true} {
+		var labels []string
+		mock := &MockComputeClient{
+			LaunchInstanceFunc: func(ctx context.Context, request core.LaunchInstanceRequest) (core.LaunchInstanceResponse, error) {
+				label := *request.CreateVnicDetails.HostnameLabel
+				labels = append(labels, label)
+				if label == "armvm" {
+					return core.LaunchInstanceResponse{}, conflict
+				}
+				return core.LaunchInstanceResponse{Instance: core.Instance{Id: &instID}}, nil
+			},
+			GetInstanceFunc: func(ctx context.Context, request core.GetInstanceRequest) (core.GetInstanceResponse, error) {
+				return core.GetInstanceResponse{Instance: core.Instance{Id: &instID, LifecycleState: core.InstanceLifecycleStateRunning}}, nil
+			},
+		}
+		network := &MockVirtualNetworkClient{
+			ListPrivateIpsFunc: func(ctx context.Context, request core.ListPrivateIpsRequest) (core.ListPrivateIpsResponse, error) {
+				return core.ListPrivateIpsResponse{Items: []core.PrivateIp{
+					{HostnameLabel: common.String("armvm")},
+					{HostnameLabel: common.String("armvm-2")},
+				}}, nil
+			},
+		}
+		w := &AccountWorker{
+			AccountName:          "test",
+			Config:               &config.AccountConfig{AvailabilityDomain: "AD-1", HostnameLabel: "armvm", HostnameAutoSuffix: autoSuffix},
+			Logger:               newMockLogger(),
+			Notifier:             notifier.New(config.NotificationConfig{Enabled: false}),
+			Tracker:              notifier.NewTracker(),
+			ComputeClient:        mock,
+			IdentityClient:       &MockIdentityClient{},
+			VirtualNetworkClient: network,
+		}
+
+		success, _, err := w.Provision(context.Background())
+		if !autoSuffix {
+			if success || err == nil || len(labels) != 1 {
+				t.Errorf("expected a single failed launch without auto-suffix, got success=%v err=%v labels=%v", success, err, labels)
+			}
+			continue
+		}
+		if !success || err != nil {
+			t.Fatalf("expected the relaunch to succeed, got success=%v err=%v", success, err)
+		}
+		if len(labels) != 2 || labels[1] != "armvm-3" {
+			t.Errorf("expected a relaunch as the first free label armvm-3, got %v", labels)
+		}
+	}
+}
+
+func TestAccountWorker_Preflight(t *testing.T) {
+	a1 := func(shape string, ocpus, mem float32, state core.InstanceLifecycleStateEnum) core.Instance {
+		return core.Instance{
+			Shape:          common.String(shape),
+			LifecycleState: state,
+			ShapeConfig:    &core.InstanceShapeConfig{Ocpus: common.Float32(ocpus), MemoryInGBs: common.Float32(mem)},
+		}
+	}
+	mock := &MockComputeClient{
+		ListInstancesFunc: func(ctx context.Context, request core.ListInstancesRequest) (core.ListInstancesResponse, error) {
+			if request.DisplayName != nil {
+				return core.ListInstancesResponse{}, nil
+			}
+			return core.ListInstancesResponse{Items: []core.Instance{
+				a1("VM.Standard.A1.Flex", 1, 6, core.InstanceLifecycleStateRunning),
+				a1("VM.Standard.A1.Flex", 2, 12, core.InstanceLifecycleStateTerminated),
+				a1("VM.Standard.E2.1.Micro", 1, 1, core.InstanceLifecycleStateRunning),
+			}}, nil
+		},
+	}
+
+	newWorker := func(autoShrink bool) *AccountWorker {
+		return &AccountWorker{
+			AccountName: "test",
+			Config: &config.AccountConfig{
+				TenancyOCID: "tenancy",
+				Shape:       "VM.Standard.A1.Flex",
+				OCPUs:       4,
+				MemoryGB:    24,
+				AutoShrink:  autoShrink,
+			},
+			Logger:        newMockLogger(),
+			Notifier:      notifier.New(config.NotificationConfig{Enabled: false}),
+			ComputeClient: mock,
+		}
+	}
+
+	w := newWorker(true)
+	ocpus, memory, ok, err := w.preflight(context.Background(), w.shape())
+	if err != nil || !ok {
+		t.Fatalf("expected shrunk launch, got ok=%v err=%v", ok, err)
+	}
+	if ocpus != 3 || memory != 18 {
+		t.Errorf("expected 3 OCPUs / 18 GB, got %.0f / %.0f", ocpus, memory)
+	}
+
+	w = newWorker(false)
+	if _, _, ok, _ := w.preflight(context.Background(), w.shape()); ok {
+		t.Error("expected launch to be skipped without auto_shrink")
+	}
+	if !w.preflightAlerted {
+		t.Error("expected usage alert to be sent")
+	}
+
+	// PAYG accounts turn the guard off and launch as configured.
+	w = newWorker(false)
+	w.Config.FreeTierGuard = common.Bool(false)
+	ocpus, memory, ok, err = w.preflight(context.Background(), w.shape())
+	if err != nil || !ok || ocpus != 4 || memory != 24 {
+		t.Errorf("expected an unchecked 4 OCPU / 24 GB launch, got %.0f / %.0f ok=%v err=%v", ocpus, memory, ok, err)
+	}
+}
+
+func TestAccountWorker_CheckAnnouncements(t *testing.T) {
+	calls := 0
+	mock := &MockAnnouncementClient{
+		ListAnnouncementsFunc: func(ctx context.Context, request announcementsservice.ListAnnouncementsRequest) (announcementsservice.ListAnnouncementsResponse, error) {
+			calls++
+			return announcementsservice.ListAnnouncementsResponse{
+				AnnouncementsCollection: announcementsservice.AnnouncementsCollection{
+					Items: []announcementsservice.AnnouncementSummary{
+						{Id: common.String("a1"), Summary: common.String("Verify your account to keep resources"), AnnouncementType: announcementsservice.BaseAnnouncementAnnouncementTypeInformation},
+						{Id: common.String("a2"), Summary: common.String("Object Storage maintenance"), AnnouncementType: announcementsservice.BaseAnnouncementAnnouncementTypeScheduledMaintenance},
+						{Id: common.String("a3"), Summary: common.String("Update your API keys"), AnnouncementType: announcementsservice.BaseAnnouncementAnnouncementTypeActionRequired},
+					},
+				},
+			}, nil
+		},
+	}
+
+	st, _ := state.Open(nil)
+	w := &AccountWorker{
+		AccountName:          "test",
+		Config:               &config.AccountConfig{TenancyOCID: "tenancy"},
+		Logger:               newMockLogger(),
+		Notifier:             notifier.New(config.NotificationConfig{Enabled: false}),
+		State:                st,
+		ComputeClient:        &MockComputeClient{},
+		IdentityClient:       &MockIdentityClient{},
+		VirtualNetworkClient: &MockVirtualNetworkClient{},
+		AnnouncementClient:   mock,
+		AnnouncementInterval: time.Hour,
+	}
+
+	if err := w.CheckAnnouncements(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for id, want := range map[string]bool{"a1": false, "a2": true, "a3": false} {
+		if isNew, _ := st.MarkAnnouncement("test", id); isNew != want {
+			t.Errorf("announcement %s: expected new=%v after check, got %v", id, want, isNew)
+		}
+	}
+
+	// Within the interval the API is not polled again
+	if err := w.CheckAnnouncements(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 API call within the interval, got %d", calls)
+	}
+}
+
+func TestAccountWorker_MonthToDateCost(t *testing.T) {
+	var details usageapi.RequestSummarizedUsagesDetails
+	mock := &MockUsageClient{
+		RequestSummarizedUsagesFunc: func(ctx context.Context, request usageapi.RequestSummarizedUsagesRequest) (usageapi.RequestSummarizedUsagesResponse, error) {
+			details = request.RequestSummarizedUsagesDetails
+			return usageapi.RequestSummarizedUsagesResponse{
+				UsageAggregation: usageapi.UsageAggregation{Items: []usageapi.UsageSummary{
+					{ComputedAmount: common.Float32(1.5), Currency: common.String("USD")},
+					{ComputedAmount: common.Float32(0.5), Currency: common.String("USD")},
+					{ComputedAmount: common.Float32(4), IsForecast: common.Bool(true)},
+				}},
+			}, nil
+		},
+	}
+
+	w := &AccountWorker{
+		AccountName:          "payg",
+		Config:               &config.AccountConfig{TenancyOCID: "tenancy", CostReport: true},
+		Logger:               newMockLogger(),
+		ComputeClient:        &MockComputeClient{},
+		IdentityClient:       &MockIdentityClient{},
+		VirtualNetworkClient: &MockVirtualNetworkClient{},
+		UsageClient:          mock,
+	}
+
+	cost, err := w.MonthToDateCost(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cost.Spent != 2 || cost.Projected != 6 || cost.Currency != "USD" {
+		t.Errorf("expected 2 USD spent / 6 projected, got %+v", cost)
+	}
+	if start := details.TimeUsageStarted.Time; start.Day() != 1 || start.Hour() != 0 {
+		t.Errorf("expected usage window to start at the beginning of the month, got %v", start)
+	}
+}
+
+func TestAccountWorker_TagRequests(t *testing.T) {
+	w := &AccountWorker{UserAgent: "oci-arm-provisioner/dev (install abcd1234)"}
+	client := common.BaseClient{UserAgent: "Oracle-GoSDK/65", Interceptor: conditionalGet}
+	w.tagRequests(&client)
+
+	if client.UserAgent != "Oracle-GoSDK/65 oci-arm-provisioner/dev (install abcd1234)" {
+		t.Errorf("unexpected User-Agent %q", client.UserAgent)
+	}
+
+	req, _ := http.NewRequest("GET", "https://example.com", nil)
+	ctx := context.WithValue(req.Context(), ifNoneMatchKey{}, "etag-1")
+	req = req.WithContext(ctx)
+	if err := client.Interceptor(req); err != nil {
+		t.Fatalf("interceptor failed: %v", err)
+	}
+	if got := req.Header.Get("opc-client-info"); got != "oci-arm-provisioner/dev" {
+		t.Errorf("unexpected opc-client-info %q", got)
+	}
+	if req.Header.Get("If-None-Match") != "etag-1" {
+		t.Error("expected the existing interceptor to still run")
+	}
+}
+
+func TestAccountWorker_AttemptCorrelation(t *testing.T) {
+	var tokens []string
+	w := &AccountWorker{
+		AccountName: "test",
+		Config:      &config.AccountConfig{AvailabilityDomain: "auto", TryAllADs: true},
+		Logger:      newMockLogger(),
+		Notifier:    notifier.New(config.NotificationConfig{Enabled: false}),
+		Tracker:     notifier.NewTracker(),
+		ComputeClient: &MockComputeClient{
+			ListInstancesFunc: func(ctx context.Context, request core.ListInstancesRequest) (core.ListInstancesResponse, error) {
+				return core.ListInstancesResponse{}, nil
+			},
+			LaunchInstanceFunc: func(ctx context.Context, request core.LaunchInstanceRequest) (core.LaunchInstanceResponse, error) {
+				tokens = append(tokens, *request.OpcRetryToken)
+				return core.LaunchInstanceResponse{}, newServiceError(500, "Out of host capacity")
+			},
+		},
+		IdentityClient: &MockIdentityClient{
+			ListAvailabilityDomainsFunc: func(ctx context.Context, request identity.ListAvailabilityDomainsRequest) (identity.ListAvailabilityDomainsResponse, error) {
+				return identity.ListAvailabilityDomainsResponse{Items: []identity.AvailabilityDomain{{Name: common.String("AD-1")}, {Name: common.String("AD-2")}}}, nil
+			},
+		},
+		VirtualNetworkClient: &MockVirtualNetworkClient{},
+	}
+	w.State, _ = state.Open(nil)
+
+	w.Provision(context.Background())
+	id := w.attemptID
+	if len(id) != 12 || w.Logger.Attempt("test") != id {
+		t.Fatalf("expected a 12 digit attempt ID tagging the log, got %q", id)
+	}
+	if len(tokens) != 2 || tokens[0] != id+"-ad1" || tokens[1] != id+"-ad2" {
+		t.Errorf("expected a retry token per AD derived from %s, got %v", id, tokens)
+	}
+	w.endAttempt()
+	if w.Logger.Attempt("test") != "" {
+		t.Error("expected the log tag removed after the attempt")
+	}
+
+	// Each OCI call of the attempt sends its own opc-request-id
+	client := common.BaseClient{}
+	w.tagRequests(&client)
+	ctx := w.beginAttempt(context.Background())
+	for n := 1; n <= 2; n++ {
+		req, _ := http.NewRequestWithContext(ctx, "GET", "https://example.com", nil)
+		client.Interceptor(req)
+		if want := fmt.Sprintf("%s-%d", w.attemptID, n); req.Header.Get("opc-request-id") != want {
+			t.Errorf("expected opc-request-id %s, got %q", want, req.Header.Get("opc-request-id"))
+		}
+	}
+	req, _ := http.NewRequest("GET", "https://example.com", nil)
+	client.Interceptor(req)
+	if req.Header.Get("opc-request-id") != "" {
+		t.Error("expected no opc-request-id outside an attempt")
+	}
+}
+
+func TestProvisioner_Retarget(t *testing.T) {
+	cfg := &config.Config{
+		Accounts: map[string]*config.AccountConfig{
+			"personal": {Enabled: true, Region: "sa-saopaulo-1", AvailabilityDomain: "auto", ImageOCID: "ocid1.image.oc1.sa-saopaulo-1.aaaa"},
+		},
+	}
+	p := New(cfg, newMockLogger(), notifier.NewTracker())
+
+	var regions []string
+	p.identityFor = func(acc *config.AccountConfig, region string) (IdentityClientOps, error) {
+		regions = append(regions, region)
+		return &MockIdentityClient{
+			ListRegionSubscriptionsFunc: func(ctx context.Context, request identity.ListRegionSubscriptionsRequest) (identity.ListRegionSubscriptionsResponse, error) {
+				return identity.ListRegionSubscriptionsResponse{Items: []identity.RegionSubscription{
+					{RegionName: common.String("sa-vinhedo-1"), Status: identity.RegionSubscriptionStatusReady},
+					{RegionName: common.String("us-ashburn-1"), Status: identity.RegionSubscriptionStatusInProgress},
+					{RegionName: common.String("sa-saopaulo-1"), Status: identity.RegionSubscriptionStatusReady},
+				}}, nil
+			},
+			ListAvailabilityDomainsFunc: func(ctx context.Context, request identity.ListAvailabilityDomainsRequest) (identity.ListAvailabilityDomainsResponse, error) {
+				return identity.ListAvailabilityDomainsResponse{Items: []identity.AvailabilityDomain{{Name: common.String("Uocm:SA-VINHEDO-1-AD-1")}}}, nil
+			},
+		}, nil
+	}
+
+	got, err := p.Regions(context.Background(), "personal")
+	if err != nil || len(got) != 2 || got[0] != "sa-saopaulo-1" || got[1] != "sa-vinhedo-1" {
+		t.Fatalf("expected the two ready regions, got %v, %v", got, err)
+	}
+	ads, err := p.AvailabilityDomains(context.Background(), "personal", "sa-vinhedo-1")
+	if err != nil || len(ads) != 1 || regions[1] != "sa-vinhedo-1" {
+		t.Fatalf("expected ADs from the chosen region, got %v (queried %v), %v", ads, regions, err)
+	}
+
+	if err := p.Retarget("missing", Target{Region: "sa-vinhedo-1", AvailabilityDomain: "auto"}); err == nil {
+		t.Error("expected unknown account to be rejected")
+	}
+	old := p.Workers[0]
+	old.ComputeClient = &MockComputeClient{}
+	if err := p.Retarget("personal", Target{Region: "sa-vinhedo-1", AvailabilityDomain: ads[0]}); err != nil {
+		t.Fatalf("Retarget failed: %v", err)
+	}
+	if keys := otherRegionOCIDs(cfg.Accounts["personal"], "sa-vinhedo-1"); len(keys) != 1 || keys[0] != "image_ocid" {
+		t.Errorf("expected image_ocid to be flagged for the new region, got %v", keys)
+	}
+	if keys := otherRegionOCIDs(&config.AccountConfig{ImageOCID: "ocid1.image.oc1.iad.aaaa"}, "us-ashburn-1"); len(keys) != 0 {
+		t.Errorf("expected short region codes to match, got %v", keys)
+	}
+
+	// The switch happens at the worker's next turn, with fresh clients
+	p.RunCycle(context.Background())
+	w := p.Workers[0]
+	if w == old || w.Config.Region != "sa-vinhedo-1" || w.Config.AvailabilityDomain != "Uocm:SA-VINHEDO-1-AD-1" {
+		t.Errorf("expected a restarted worker in the new region, got %+v", w.Config)
+	}
+	if w.ComputeClient != nil {
+		t.Error("expected the restarted worker to create its own clients")
+	}
+	if cfg.Accounts["personal"].Region != "sa-saopaulo-1" {
+		t.Error("expected the loaded config to be left untouched")
+	}
+}
+
+func TestIsAuthError(t *testing.T) {
+	cases := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{newServiceError(401, "NotAuthenticated"), true},
+		{newServiceError(500, "Out of host capacity"), false},
+		{credentialsError{errors.New("key file not found: /tmp/key.pem")}, true},
+		{fmt.Errorf("cycle: %w", credentialsError{errors.New("bad key")}), true},
+		{errors.New("dial tcp: i/o timeout"), false},
+	}
+	for _, c := range cases {
+		if got := isAuthError(c.err); got != c.want {
+			t.Errorf("isAuthError(%v) = %v, want %v", c.err, got, c.want)
+		}
+	}
+}
+
+func TestAccountWorker_CheckMilestones(t *testing.T) {
+	tracker := notifier.NewTracker()
+	tracker.StartTime = time.Now().Add(-8 * 24 * time.Hour)
+	w := &AccountWorker{
+		AccountName: "test",
+		Logger:      newMockLogger(),
+		Notifier:    notifier.New(config.NotificationConfig{Enabled: false}),
+		Tracker:     tracker,
+		Milestones:  config.MilestoneConfig{Attempts: 500, Days: 7},
+	}
+
+	for i := 0; i < 499; i++ {
+		tracker.RecordAttempt("test")
+	}
+	w.checkMilestones()
+	if w.nextAttempts != 500 || w.nextDays != 14 {
+		t.Fatalf("expected only the day milestone, next at %d attempts / %d days", w.nextAttempts, w.nextDays)
+	}
+
+	for i := 0; i < 600; i++ {
+		tracker.RecordAttempt("test")
+	}
+	w.checkMilestones()
+	if w.nextAttempts != 1500 {
+		t.Errorf("expected next attempt milestone at 1500 after passing 1000, got %d", w.nextAttempts)
+	}
+
+	for n, want := range map[int]string{0: "0", 999: "999", 1000: "1,000", 1234567: "1,234,567", -4500: "-4,500"} {
+		if got := formatCount(n); got != want {
+			t.Errorf("formatCount(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+// roundTripFunc answers HTTP requests made by the notifier.
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestAccountWorker_WatchAcks(t *testing.T) {
+	defer func(interval time.Duration) { ackPollInterval = interval }(ackPollInterval)
+	ackPollInterval = time.Millisecond
+
+	n := notifier.New(config.NotificationConfig{NtfyTopic: "topic"})
+	receipt := notifier.Receipt{InstanceID: "ocid1.instance.test", AckID: "abcd1234", Deliveries: []notifier.Delivery{
+		{Provider: "ntfy", Status: notifier.DeliveryDelivered, Attempts: 1},
+		{Provider: "webhook", Status: notifier.DeliveryFailed, Attempts: 3, Error: "api returned status: 502"},
+	}}
+	n.Client.Transport = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		body := `{"event":"message","message":"ack:abcd1234"}` + "\n"
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body))}, nil
+	})
+	st, _ := state.Open(nil)
+	w := &AccountWorker{AccountName: "test", Logger: newMockLogger(), Notifier: n, State: st}
+
+	w.recordReceipt(receipt)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	w.watchAcks(ctx, receipt) // returns once every button was pressed
+
+	saved, ok := st.Notification("test")
+	if !ok || len(saved.Deliveries) != 2 {
+		t.Fatalf("expected a saved receipt with 2 deliveries, got %+v", saved)
+	}
+	if saved.Deliveries[0].Status != "acknowledged" || saved.Deliveries[1].Status != "failed" {
+		t.Errorf("expected ntfy acknowledged and webhook failed, got %+v", saved.Deliveries)
+	}
+	if ctx.Err() != nil {
+		t.Error("watchAcks did not stop after the acknowledgement")
+	}
+}
+
+func TestProvisioner_Discover(t *testing.T) {
+	tagged := func(id, account string, lifecycle core.InstanceLifecycleStateEnum) core.Instance {
+		return core.Instance{
+			Id:             common.String(id),
+			DisplayName:    common.String("arm-" + id),
+			LifecycleState: lifecycle,
+			FreeformTags:   (&AccountWorker{AccountName: account}).ownerTags(),
+		}
+	}
+	compute := &MockComputeClient{
+		ListInstancesFunc: func(ctx context.Context, request core.ListInstancesRequest) (core.ListInstancesResponse, error) {
+			return core.ListInstancesResponse{Items: []core.Instance{
+				tagged("mine", "test", core.InstanceLifecycleStateRunning),
+				tagged("gone", "test", core.InstanceLifecycleStateTerminated),
+				tagged("theirs", "other", core.InstanceLifecycleStateRunning),
+				{Id: common.String("untagged"), LifecycleState: core.InstanceLifecycleStateRunning},
+			}}, nil
+		},
+		ListVnicAttachmentsFunc: func(ctx context.Context, request core.ListVnicAttachmentsRequest) (core.ListVnicAttachmentsResponse, error) {
+			return core.ListVnicAttachmentsResponse{Items: []core.VnicAttachment{
+				{VnicId: common.String("vnic"), LifecycleState: core.VnicAttachmentLifecycleStateAttached},
+			}}, nil
+		},
+	}
+	network := &MockVirtualNetworkClient{
+		GetVnicFunc: func(ctx context.Context, request core.GetVnicRequest) (core.GetVnicResponse, error) {
+			return core.GetVnicResponse{Vnic: core.Vnic{PublicIp: common.String("203.0.113.9")}}, nil
+		},
+	}
+	st, _ := state.Open(nil)
+	w := &AccountWorker{
+		AccountName:          "test",
+		Config:               &config.AccountConfig{},
+		Logger:               newMockLogger(),
+		ComputeClient:        compute,
+		IdentityClient:       &MockIdentityClient{},
+		VirtualNetworkClient: network,
+		State:                st,
+	}
+	p := &Provisioner{
+		Logger:      w.Logger,
+		Notifier:    notifier.New(config.NotificationConfig{Enabled: false}),
+		State:       st,
+		Workers:     []*AccountWorker{w},
+		Provisioned: make(map[string]bool),
+	}
+
+	p.Discover(context.Background())
+
+	if !p.Provisioned["test"] {
+		t.Error("expected the account to be marked provisioned")
+	}
+	want := []state.Instance{{ID: "mine", Name: "arm-mine", PublicIP: "203.0.113.9"}}
+	if got := st.Instances("test"); len(got) != 1 || got[0] != want[0] {
+		t.Errorf("expected only the live instance tagged for this account, got %+v", got)
+	}
+}
+
+func TestAccountWorker_WatchIPs(t *testing.T) {
+	var events []iphook.Event
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var ev iphook.Event
+		json.NewDecoder(r.Body).Decode(&ev)
+		events = append(events, ev)
+	}))
+	defer srv.Close()
+
+	publicIP := "203.0.113.1"
+	compute := &MockComputeClient{
+		ListVnicAttachmentsFunc: func(ctx context.Context, request core.ListVnicAttachmentsRequest) (core.ListVnicAttachmentsResponse, error) {
+			return core.ListVnicAttachmentsResponse{Items: []core.VnicAttachment{
+				{VnicId: common.String("vnic"), LifecycleState: core.VnicAttachmentLifecycleStateAttached},
+			}}, nil
+		},
+	}
+	network := &MockVirtualNetworkClient{
+		GetVnicFunc: func(ctx context.Context, request core.GetVnicRequest) (core.GetVnicResponse, error) {
+			return core.GetVnicResponse{Vnic: core.Vnic{PublicIp: common.String(publicIP)}}, nil
+		},
+	}
+	st, _ := state.Open(nil)
+	w := &AccountWorker{
+		AccountName:          "test",
+		Config:               &config.AccountConfig{},
+		Logger:               newMockLogger(),
+		State:                st,
+		IPHook:               iphook.New(config.IPHookConfig{URL: srv.URL}),
+		ComputeClient:        compute,
+		IdentityClient:       &MockIdentityClient{},
+		VirtualNetworkClient: network,
+	}
+
+	// A launch reports the first IP
+	w.trackInstance(context.Background(), state.Instance{ID: "inst", Name: "arm", PublicIP: publicIP})
+	w.watchIPs(context.Background()) // unchanged: no call
+	publicIP = "203.0.113.2"
+	w.watchIPs(context.Background())
+
+	want := []iphook.Event{
+		{Account: "test", InstanceID: "inst", PublicIP: "203.0.113.1"},
+		{Account: "test", InstanceID: "inst", PublicIP: "203.0.113.2", PreviousIP: "203.0.113.1"},
+	}
+	if len(events) != len(want) || events[0] != want[0] || events[1] != want[1] {
+		t.Errorf("hook events = %+v, want %+v", events, want)
+	}
+	if got := st.Instances("test"); len(got) != 1 || got[0].PublicIP != "203.0.113.2" {
+		t.Errorf("expected the new IP to be recorded, got %+v", got)
+	}
+}
+
+func TestProvisioner_DuplicateDaemonIsSkipped(t *testing.T) {
+	cfg := &config.Config{
+		Accounts: map[string]*config.AccountConfig{
+			"hunted": {Enabled: true, TenancyOCID: "ocid1.tenancy.dup", UserOCID: "ocid1.user.hunted"},
+			"free":   {Enabled: true, TenancyOCID: "ocid1.tenancy.dup", UserOCID: "ocid1.user.free"},
+		},
+	}
+
+	p := New(cfg, newMockLogger(), notifier.NewTracker())
+	p.lockDir = t.TempDir()
+
+	attempts := map[string]int{}
+	for _, worker := range p.Workers {
+		name := worker.AccountName
+		worker.ComputeClient = &MockComputeClient{
+			ListInstancesFunc: func(ctx context.Context, request core.ListInstancesRequest) (core.ListInstancesResponse, error) {
+				attempts[name]++
+				return core.ListInstancesResponse{Items: []core.Instance{{LifecycleState: core.InstanceLifecycleStateRunning}}}, nil
+			},
+		}
+		worker.IdentityClient = &MockIdentityClient{}
+		worker.VirtualNetworkClient = &MockVirtualNetworkClient{}
+	}
+
+	// Another copy of the tool holds the lock for "hunted"
+	key := lockKey("ocid1.tenancy.dup", "ocid1.user.hunted")
+	other := flock.New(filepath.Join(p.lockDir, key+".lock"))
+	if ok, err := other.TryLock(); !ok || err != nil {
+		t.Fatalf("failed to take the lock: %v", err)
+	}
+	owner, _ := json.Marshal(lockHolder{PID: 4242, Host: "other-host", Since: time.Now()})
+	os.WriteFile(filepath.Join(p.lockDir, key+".json"), owner, 0600)
+
+	p.RunCycle(context.Background())
+	if attempts["hunted"] != 0 {
+		t.Errorf("expected duplicated account to be skipped, got %d attempts", attempts["hunted"])
+	}
+	if attempts["free"] != 1 {
+		t.Errorf("expected other account to keep running, got %d attempts", attempts["free"])
+	}
+	var warnings []string
+	warn := func(msg string) { warnings = append(warnings, msg) }
+	if ok, holder, _ := claimAccount(p.lockDir, key, warn); ok || holder.PID != 4242 || holder.Host != "other-host" || len(warnings) != 0 {
+		t.Errorf("expected the other copy to be reported, got ok=%v holder=%q warnings=%q", ok, holder, warnings)
+	}
+
+	// A broken owner file is reported instead of ignored
+	os.WriteFile(filepath.Join(p.lockDir, key+".json"), []byte("{"), 0600)
+	if ok, holder, _ := claimAccount(p.lockDir, key, warn); ok || holder.PID != 0 || len(warnings) != 1 {
+		t.Errorf("expected a warning about the owner file, got ok=%v holder=%q warnings=%q", ok, holder, warnings)
+	}
+
+	// Once the other copy exits, this one takes over
+	other.Unlock()
+	p.RunCycle(context.Background())
+	if attempts["hunted"] != 1 {
+		t.Errorf("expected account to be picked up after the lock was released, got %d attempts", attempts["hunted"])
+	}
+}
+
+func TestProvisioner_DuplicateCheckSkipsAccountsWithoutUser(t *testing.T) {
+	p := &Provisioner{Logger: newMockLogger(), lockDir: t.TempDir()}
+	w := &AccountWorker{AccountName: "principal", Config: &config.AccountConfig{Auth: config.AuthInstancePrincipal, TenancyOCID: "ocid1.tenancy.a"}}
+
+	// Another deployment with instance principals in the same tenancy
+	other := flock.New(filepath.Join(p.lockDir, lockKey("ocid1.tenancy.a", "")+".lock"))
+	if ok, err := other.TryLock(); !ok || err != nil {
+		t.Fatalf("failed to take the lock: %v", err)
+	}
+	defer other.Unlock()
+	if !p.claim(w) {
+		t.Error("expected an account without a user OCID not to be blocked")
+	}
+}
+
+func TestReadHistory_CapacityWindows(t *testing.T) {
+	log := `2026/10/01 10:00:00 [main] [WARN] OCI Error 500: Out of host capacity.
+2026/10/01 10:00:00 [main] [WARN] Capacity/Limit error. Will retry.
+2026/10/01 10:15:00 [main] [INFO] Waiting 900s before next cycle
+2026/10/01 10:15:00 [main] [WARN] Capacity/Limit error. Will retry.
+2026/10/01 10:30:00 [main] [SUCCESS] Instance Launched: ocid1.instance.oc1..a
+`
+	obs, err := ReadHistory(strings.NewReader(log))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(obs) != 3 || obs[0].Account != "main" || obs[0].Success || !obs[2].Success {
+		t.Fatalf("unexpected observations: %+v", obs)
+	}
+
+	c, err := CapacityFromHistory(obs, 5*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Period != 35*time.Minute || len(c.Windows) != 1 {
+		t.Fatalf("unexpected capacity: %+v", c)
+	}
+	for at, want := range map[time.Duration]bool{
+		29 * time.Minute:                false,
+		30 * time.Minute:                true,
+		34 * time.Minute:                true,
+		35*time.Minute + 30*time.Minute: true, // Repeats after the period.
+		35*time.Minute + 36*time.Minute: false,
+	} {
+		if got := c.Available(at); got != want {
+			t.Errorf("Available(%v) = %v, want %v", at, got, want)
+		}
+	}
+
+	if _, err := CapacityFromHistory(obs[:2], 5*time.Minute); err == nil {
+		t.Error("expected an error for a history without successes")
+	}
+}
+
+func TestSimulate_ShorterIntervalProvisionsSooner(t *testing.T) {
+	opts := SimOptions{
+		Tenancies:   map[string]string{"a": "t1", "b": "t1"},
+		Capacity:    Capacity{Windows: []Window{{Start: 12 * time.Hour, End: 12*time.Hour + 10*time.Minute}}, Period: 24 * time.Hour},
+		AttemptTime: 5 * time.Second,
+		Horizon:     7 * 24 * time.Hour,
+		Runs:        50,
+		Seed:        1,
+	}
+	opts.Scheduler.TenancyIntervalSeconds = 60
+
+	opts.Scheduler.CycleIntervalSeconds = 60
+	fast := Simulate(opts)
+	opts.Scheduler.CycleIntervalSeconds = 3600
+	slow := Simulate(opts)
+
+	if fast.Hunts != 100 || fast.Provisioned != 100 {
+		t.Fatalf("expected every hunt to succeed with a 1m interval, got %d/%d", fast.Provisioned, fast.Hunts)
+	}
+	if fast.P90 > 24*time.Hour {
+		t.Errorf("expected a 1m interval to catch the first window, got p90 %v", fast.P90)
+	}
+	if slow.Median <= fast.Median {
+		t.Errorf("expected a 1h interval to miss windows: median %v vs %v", slow.Median, fast.Median)
+	}
+	if fast.PerDay <= slow.PerDay {
+		t.Errorf("expected a 1m interval to cost more attempts: %.0f vs %.0f a day", fast.PerDay, slow.PerDay)
+	}
+
+	opts.Scheduler.CycleIntervalSeconds = 0
+	opts.Scheduler.TenancyIntervalSeconds = 0
+	opts.RateLimit = 1
+	if limited := Simulate(opts); limited.RateLimited == 0 {
+		t.Error("expected attempts over the rate limit to get 429s")
+	}
+}
+
+func TestProbeRegions(t *testing.T) {
+	cfg := &config.Config{Accounts: map[string]*config.AccountConfig{
+		"home":    {Enabled: true, Region: "eu-frankfurt-1"},
+		"work":    {Enabled: true, Region: "eu-frankfurt-1"},
+		"gov":     {Enabled: true, Region: "us-langley-1"},
+		"typo":    {Enabled: true, Region: "eu-frankfurt-9"},
+		"private": {Enabled: true, Region: "eu-frankfurt-1", RealmDomain: "example-realm.com"},
+		"off":     {Enabled: false, Region: "ap-tokyo-1"},
+	}}
+	client := &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.Contains(req.URL.Host, "frankfurt-9") {
+			return nil, errors.New("no such host")
+		}
+		// OCI answers unauthenticated requests with 401, which still proves it is reachable
+		return &http.Response{StatusCode: http.StatusUnauthorized, Body: io.NopCloser(strings.NewReader(""))}, nil
+	})}
+
+	got := make(map[string]Probe)
+	for _, p := range probeRegions(context.Background(), cfg, client) {
+		got[p.Endpoint] = p
+	}
+	for _, want := range []struct {
+		endpoint, realm, accounts string
+		reachable                 bool
+	}{
+		{"https://iaas.eu-frankfurt-1.oraclecloud.com", "oc1", "home,work", true},
+		{"https://iaas.us-langley-1.oraclegovcloud.com", "oc2", "gov", true},
+		{"https://iaas.eu-frankfurt-9.oraclecloud.com", "", "typo", false},
+		{"https://iaas.eu-frankfurt-1.example-realm.com", "example-realm.com", "private", true},
+	} {
+		p, ok := got[want.endpoint]
+		if !ok {
+			t.Errorf("no probe of %s in %v", want.endpoint, got)
+			continue
+		}
+		if p.Realm != want.realm || strings.Join(p.Accounts, ",") != want.accounts || (p.Err == nil) != want.reachable {
+			t.Errorf("%s: got realm %q, accounts %v, error %v", want.endpoint, p.Realm, p.Accounts, p.Err)
+		}
+	}
+	if len(got) != 4 {
+		t.Errorf("expected 4 probes (disabled accounts skipped), got %d", len(got))
+	}
+}
+
+func TestSetRealmDomain(t *testing.T) {
+	w := &AccountWorker{Config: &config.AccountConfig{Region: "us-ashburn-1", RealmDomain: "example-realm.com"}}
+	client := &common.BaseClient{Host: "https://usageapi.us-ashburn-1.oci.oraclecloud.com"}
+	w.setRealmDomain(client, "us-ashburn-1")
+	if client.Host != "https://usageapi.us-ashburn-1.oci.example-realm.com" {
+		t.Errorf("unexpected host %s", client.Host)
+	}
+}
+
+func TestAccountWorker_HostKeys(t *testing.T) {
+	console := `[   12.3] cloud-init[812]: Cloud-init v. 24.1 running 'modules:final'
+<14>Oct 16 10:00:01 ec2: #############################################################
+<14>Oct 16 10:00:01 ec2: -----BEGIN SSH HOST KEY FINGERPRINTS-----
+<14>Oct 16 10:00:01 ec2: 256 SHA256:8fQmZ1tV0nR3kYw5pXc2uLh6sGd9aJe4bTo7iNq1vWs root@arm (ECDSA)
+<14>Oct 16 10:00:01 ec2: 256 SHA256:Jr2xKb7YpE4mVt9cQw1nHs6uLz3aFg8dRo5iTy0kPe root@arm (ED25519)
+<14>Oct 16 10:00:01 ec2: -----END SSH HOST KEY FINGERPRINTS-----
+`
+	var deleted bool
+	mock := &MockComputeClient{
+		CaptureConsoleHistoryFunc: func(ctx context.Context, request core.CaptureConsoleHistoryRequest) (core.CaptureConsoleHistoryResponse, error) {
+			return core.CaptureConsoleHistoryResponse{ConsoleHistory: core.ConsoleHistory{Id: common.String("history"), LifecycleState: core.ConsoleHistoryLifecycleStateRequested}}, nil
+		},
+		GetConsoleHistoryFunc: func(ctx context.Context, request core.GetConsoleHistoryRequest) (core.GetConsoleHistoryResponse, error) {
+			return core.GetConsoleHistoryResponse{ConsoleHistory: core.ConsoleHistory{LifecycleState: core.ConsoleHistoryLifecycleStateSucceeded}}, nil
+		},
+		GetConsoleHistoryContentFunc: func(ctx context.Context, request core.GetConsoleHistoryContentRequest) (core.GetConsoleHistoryContentResponse, error) {
+			return core.GetConsoleHistoryContentResponse{Value: common.String(console)}, nil
+		},
+		DeleteConsoleHistoryFunc: func(ctx context.Context, request core.DeleteConsoleHistoryRequest) (core.DeleteConsoleHistoryResponse, error) {
+			deleted = *request.InstanceConsoleHistoryId == "history"
+			return core.DeleteConsoleHistoryResponse{}, nil
+		},
+	}
+	w := &AccountWorker{AccountName: "test", Config: &config.AccountConfig{}, Logger: newMockLogger(), ComputeClient: mock, PollInterval: time.Millisecond}
+
+	keys, err := w.hostKeys(context.Background(), "instance")
+	if err != nil {
+		t.Fatalf("hostKeys failed: %v", err)
+	}
+	want := []string{"ECDSA SHA256:8fQmZ1tV0nR3kYw5pXc2uLh6sGd9aJe4bTo7iNq1vWs", "ED25519 SHA256:Jr2xKb7YpE4mVt9cQw1nHs6uLz3aFg8dRo5iTy0kPe"}
+	if !slices.Equal(keys, want) {
+		t.Errorf("expected %v, got %v", want, keys)
+	}
+	if !deleted {
+		t.Error("expected the console history capture to be deleted")
+	}
+
+	// Without the permission to capture, don't wait for the keys
+	mock.CaptureConsoleHistoryFunc = func(ctx context.Context, request core.CaptureConsoleHistoryRequest) (core.CaptureConsoleHistoryResponse, error) {
+		return core.CaptureConsoleHistoryResponse{}, newServiceError(404, "NotAuthorizedOrNotFound")
+	}
+	if _, err := w.hostKeys(context.Background(), "instance"); err == nil {
+		t.Error("expected an error when console history can't be captured")
+	}
+}
+
+func TestAccountWorker_WaitForCloudInit(t *testing.T) {
+	console := `[   12.3] cloud-init[812]: Cloud-init v. 24.1 running 'modules:final'
+[   73.1] cloud-init[812]: Cloud-init v. 24.1 finished at Thu, 16 Oct 2026 10:01:13 +0000. Datasource DataSourceOracle.  Up 73.09 seconds
+`
+	reads := 0
+	mock := &MockComputeClient{
+		CaptureConsoleHistoryFunc: func(ctx context.Context, request core.CaptureConsoleHistoryRequest) (core.CaptureConsoleHistoryResponse, error) {
+			return core.CaptureConsoleHistoryResponse{ConsoleHistory: core.ConsoleHistory{Id: common.String("history"), LifecycleState: core.ConsoleHistoryLifecycleStateSucceeded}}, nil
+		},
+		GetConsoleHistoryContentFunc: func(ctx context.Context, request core.GetConsoleHistoryContentRequest) (core.GetConsoleHistoryContentResponse, error) {
+			// Still running on the first read
+			if reads++; reads == 1 {
+				return core.GetConsoleHistoryContentResponse{Value: common.String(console[:strings.Index(console, "\n")+1])}, nil
+			}
+			return core.GetConsoleHistoryContentResponse{Value: common.String(console)}, nil
+		},
+		DeleteConsoleHistoryFunc: func(ctx context.Context, request core.DeleteConsoleHistoryRequest) (core.DeleteConsoleHistoryResponse, error) {
+			return core.DeleteConsoleHistoryResponse{}, nil
+		},
+	}
+	w := &AccountWorker{AccountName: "test", Config: &config.AccountConfig{}, Logger: newMockLogger(), ComputeClient: mock, PollInterval: time.Millisecond}
+
+	uptime, err := w.waitForCloudInit(context.Background(), "instance")
+	if err != nil {
+		t.Fatalf("waitForCloudInit failed: %v", err)
+	}
+	if uptime != 73090*time.Millisecond || reads != 2 {
+		t.Errorf("expected to wait for the finished line at 73.09s, got %v after %d reads", uptime, reads)
+	}
+
+	// Cloud-init that never finishes is given up on
+	mock.GetConsoleHistoryContentFunc = func(ctx context.Context, request core.GetConsoleHistoryContentRequest) (core.GetConsoleHistoryContentResponse, error) {
+		return core.GetConsoleHistoryContentResponse{Value: common.String("cloud-init[812]: Cloud-init v. 24.1 running 'modules:final'\n")}, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := w.waitForCloudInit(ctx, "instance"); err == nil || !strings.Contains(err.Error(), "did not finish") {
+		t.Errorf("expected a timeout, got %v", err)
+	}
+}
+
+func TestProvisioner_Teardown(t *testing.T) {
+	created := time.Now().Add(-47 * time.Hour)
+	var terminated []string
+	compute := &MockComputeClient{
+		GetInstanceFunc: func(ctx context.Context, request core.GetInstanceRequest) (core.GetInstanceResponse, error) {
+			return core.GetInstanceResponse{Instance: core.Instance{
+				Id:             request.InstanceId,
+				LifecycleState: core.InstanceLifecycleStateRunning,
+				TimeCreated:    &common.SDKTime{Time: created},
+			}}, nil
+		},
+		TerminateInstanceFunc: func(ctx context.Context, request core.TerminateInstanceRequest) (core.TerminateInstanceResponse, error) {
+			if *request.PreserveBootVolume {
+				t.Error("expected the boot volume to be deleted")
+			}
+			terminated = append(terminated, *request.InstanceId)
+			return core.TerminateInstanceResponse{}, nil
+		},
+	}
+	st, _ := state.Open(nil)
+	st.RecordInstance("test", state.Instance{ID: "arm", Name: "arm-1"})
+	w := &AccountWorker{
+		AccountName:          "test",
+		Config:               &config.AccountConfig{Teardown: config.TeardownConfig{AfterDays: 2}},
+		Logger:               newMockLogger(),
+		Notifier:             notifier.New(config.NotificationConfig{Enabled: false}),
+		ComputeClient:        compute,
+		IdentityClient:       &MockIdentityClient{},
+		VirtualNetworkClient: &MockVirtualNetworkClient{},
+		State:                st,
+	}
+	p := &Provisioner{
+		Logger:      w.Logger,
+		State:       st,
+		Workers:     []*AccountWorker{w},
+		Provisioned: map[string]bool{"test": true},
+		paused:      make(map[string]bool),
+	}
+
+	// An hour before the teardown, only the warning goes out
+	p.checkTeardown(context.Background(), w)
+	if len(terminated) != 0 || !w.teardownWarned["arm"] || !p.Provisioned["test"] {
+		t.Fatalf("expected a warning only, terminated %v", terminated)
+	}
+
+	created = created.Add(-2 * time.Hour)
+	p.checkTeardown(context.Background(), w)
+	if !slices.Equal(terminated, []string{"arm"}) {
+		t.Fatalf("expected the instance to be terminated, got %v", terminated)
+	}
+	if len(st.Instances("test")) != 0 {
+		t.Error("expected the instance to be forgotten")
+	}
+	// Without relaunch the account waits to be resumed
+	if p.Provisioned["test"] || !p.IsAccountPaused("test") {
+		t.Error("expected the account to be paused after the teardown")
+	}
+	if !st.Paused("test") {
+		t.Error("expected the pause to be persisted, so a restart doesn't relaunch")
+	}
+}
+
+func TestProvisioner_TeardownOfExistingInstance(t *testing.T) {
+	var terminated []string
+	compute := &MockComputeClient{
+		ListInstancesFunc: func(ctx context.Context, request core.ListInstancesRequest) (core.ListInstancesResponse, error) {
+			return core.ListInstancesResponse{Items: []core.Instance{{
+				Id: common.String("arm"), DisplayName: common.String("arm-1"), LifecycleState: core.InstanceLifecycleStateRunning,
+			}}}, nil
+		},
+		GetInstanceFunc: func(ctx context.Context, request core.GetInstanceRequest) (core.GetInstanceResponse, error) {
+			return core.GetInstanceResponse{Instance: core.Instance{
+				Id: request.InstanceId, LifecycleState: core.InstanceLifecycleStateRunning, TimeCreated: &common.SDKTime{Time: time.Now()},
+			}}, nil
+		},
+		TerminateInstanceFunc: func(ctx context.Context, request core.TerminateInstanceRequest) (core.TerminateInstanceResponse, error) {
+			terminated = append(terminated, *request.InstanceId)
+			return core.TerminateInstanceResponse{}, nil
+		},
+	}
+	st, _ := state.Open(nil)
+	w := &AccountWorker{
+		AccountName:          "test",
+		Config:               &config.AccountConfig{DisplayName: "arm-1", Teardown: config.TeardownConfig{AfterDays: 2}},
+		Logger:               newMockLogger(),
+		Notifier:             notifier.New(config.NotificationConfig{Enabled: false}),
+		Tracker:              notifier.NewTracker(),
+		ComputeClient:        compute,
+		IdentityClient:       &MockIdentityClient{},
+		VirtualNetworkClient: &MockVirtualNetworkClient{},
+		State:                st,
+	}
+	p := &Provisioner{
+		Logger:      w.Logger,
+		State:       st,
+		Workers:     []*AccountWorker{w},
+		Provisioned: map[string]bool{"test": true},
+		paused:      make(map[string]bool),
+	}
+
+	// Nothing recorded yet is not a finished teardown
+	p.checkTeardown(context.Background(), w)
+	if !p.Provisioned["test"] || p.IsAccountPaused("test") {
+		t.Fatal("expected the account to stay provisioned without a recorded instance")
+	}
+
+	// An instance found by its name is recorded, so the teardown times it
+	if success, _, err := w.Provision(context.Background()); !success || err != nil {
+		t.Fatalf("expected the existing instance to count, got %v, %v", success, err)
+	}
+	if got := st.Instances("test"); len(got) != 1 || got[0].ID != "arm" {
+		t.Fatalf("expected the existing instance recorded, got %+v", got)
+	}
+	p.checkTeardown(context.Background(), w)
+	if len(terminated) != 0 || !p.Provisioned["test"] || p.IsAccountPaused("test") {
+		t.Errorf("expected the instance kept until its teardown time, terminated %v", terminated)
+	}
+}
+
+func TestAccountWorker_ReservePublicIP(t *testing.T) {
+	var existing []core.PublicIp
+	var calls []string
+	compute := &MockComputeClient{
+		ListVnicAttachmentsFunc: func(ctx context.Context, request core.ListVnicAttachmentsRequest) (core.ListVnicAttachmentsResponse, error) {
+			return core.ListVnicAttachmentsResponse{Items: []core.VnicAttachment{
+				{VnicId: common.String("vnic"), LifecycleState: core.VnicAttachmentLifecycleStateAttached},
+			}}, nil
+		},
+	}
+	network := &MockVirtualNetworkClient{
+		ListPrivateIpsFunc: func(ctx context.Context, request core.ListPrivateIpsRequest) (core.ListPrivateIpsResponse, error) {
+			return core.ListPrivateIpsResponse{Items: []core.PrivateIp{
+				{Id: common.String("secondary"), IsPrimary: common.Bool(false)},
+				{Id: common.String("primary"), IsPrimary: common.Bool(true)},
+			}}, nil
+		},
+		ListPublicIpsFunc: func(ctx context.Context, request core.ListPublicIpsRequest) (core.ListPublicIpsResponse, error) {
+			return core.ListPublicIpsResponse{Items: existing}, nil
+		},
+		GetPublicIpByPrivateIpIdFunc: func(ctx context.Context, request core.GetPublicIpByPrivateIpIdRequest) (core.GetPublicIpByPrivateIpIdResponse, error) {
+			return core.GetPublicIpByPrivateIpIdResponse{PublicIp: core.PublicIp{Id: common.String("ephemeral"), Lifetime: core.PublicIpLifetimeEphemeral}}, nil
+		},
+		DeletePublicIpFunc: func(ctx context.Context, request core.DeletePublicIpRequest) (core.DeletePublicIpResponse, error) {
+			calls = append(calls, "delete "+*request.PublicIpId)
+			return core.DeletePublicIpResponse{}, nil
+		},
+		CreatePublicIpFunc: func(ctx context.Context, request core.CreatePublicIpRequest) (core.CreatePublicIpResponse, error) {
+			calls = append(calls, fmt.Sprintf("create %s for %s", *request.DisplayName, *request.PrivateIpId))
+			return core.CreatePublicIpResponse{PublicIp: core.PublicIp{IpAddress: common.String("203.0.113.50")}}, nil
+		},
+		UpdatePublicIpFunc: func(ctx context.Context, request core.UpdatePublicIpRequest) (core.UpdatePublicIpResponse, error) {
+			calls = append(calls, fmt.Sprintf("assign %s to %s", *request.PublicIpId, *request.PrivateIpId))
+			return core.UpdatePublicIpResponse{PublicIp: core.PublicIp{IpAddress: common.String("203.0.113.60")}}, nil
+		},
+	}
+	w := &AccountWorker{
+		AccountName:          "test",
+		Config:               &config.AccountConfig{ReservedPublicIP: "new"},
+		Logger:               newMockLogger(),
+		ComputeClient:        compute,
+		VirtualNetworkClient: network,
+	}
+
+	ip, err := w.reservePublicIP(context.Background(), "instance")
+	if err != nil || ip != "203.0.113.50" {
+		t.Fatalf("expected a new reserved IP, got %q, %v", ip, err)
+	}
+	want := []string{"delete ephemeral", "create oci-arm-provisioner-test for primary"}
+	if !slices.Equal(calls, want) {
+		t.Errorf("expected %v, got %v", want, calls)
+	}
+
+	// The next instance reuses the account's reserved IP
+	calls = nil
+	existing = []core.PublicIp{{Id: common.String("reserved"), DisplayName: common.String("oci-arm-provisioner-test"), LifecycleState: core.PublicIpLifecycleStateAvailable}}
+	if ip, err := w.reservePublicIP(context.Background(), "instance"); err != nil || ip != "203.0.113.60" {
+		t.Fatalf("expected the reserved IP to be reused, got %q, %v", ip, err)
+	}
+	want = []string{"delete ephemeral", "assign reserved to primary"}
+	if !slices.Equal(calls, want) {
+		t.Errorf("expected %v, got %v", want, calls)
+	}
+
+	// One still held by another instance is left alone, and so is the ephemeral IP
+	calls = nil
+	existing[0].PrivateIpId = common.String("elsewhere")
+	if _, err := w.reservePublicIP(context.Background(), "instance"); err == nil || len(calls) != 0 {
+		t.Errorf("expected an error and no changes, got %v and %v", err, calls)
+	}
+}
+
+func TestAccountWorker_Bootstrap(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	metadata := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer Oracle" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprintln(w, "ocid1.instance.x86")
+	}))
+	defer metadata.Close()
+	defer func(url string) { metadataURL = url }(metadataURL)
+	metadataURL = metadata.URL
+
+	var terminated []string
+	out := filepath.Join(t.TempDir(), "migrated")
+	w := &AccountWorker{
+		AccountName: "test",
+		Config: &config.AccountConfig{
+			Auth:      config.AuthInstancePrincipal,
+			Bootstrap: config.BootstrapConfig{MigrateCommand: "exit 3", TerminateHost: true},
+		},
+		Logger:   newMockLogger(),
+		Notifier: notifier.New(config.NotificationConfig{Enabled: false}),
+		ComputeClient: &MockComputeClient{
+			TerminateInstanceFunc: func(ctx context.Context, request core.TerminateInstanceRequest) (core.TerminateInstanceResponse, error) {
+				terminated = append(terminated, *request.InstanceId)
+				return core.TerminateInstanceResponse{}, nil
+			},
+		},
+		Verified: &VerifiedInstance{InstanceID: "ocid1.instance.arm", PublicIP: "203.0.113.7", Verified: true},
+	}
+
+	// A failed migration keeps the host
+	w.bootstrap(context.Background())
+	if len(terminated) != 0 {
+		t.Fatalf("expected the host to be kept, terminated %v", terminated)
+	}
+
+	// So does an instance that failed verification, without running the command
+	w.Config.Bootstrap.MigrateCommand = `echo "$OCI_INSTANCE_ID $OCI_PUBLIC_IP" > ` + out
+	w.Verified = &VerifiedInstance{InstanceID: "ocid1.instance.arm", Errors: []string{"instance never reached RUNNING"}}
+	w.bootstrap(context.Background())
+	if _, err := os.Stat(out); !os.IsNotExist(err) || len(terminated) != 0 {
+		t.Fatalf("expected no migration and the host to be kept, terminated %v", terminated)
+	}
+
+	w.Verified = &VerifiedInstance{InstanceID: "ocid1.instance.arm", PublicIP: "203.0.113.7", Verified: true}
+	w.bootstrap(context.Background())
+	if got, _ := os.ReadFile(out); strings.TrimSpace(string(got)) != "ocid1.instance.arm 203.0.113.7" {
+		t.Errorf("expected the new instance in the command's environment, got %q", got)
+	}
+	if !slices.Equal(terminated, []string{"ocid1.instance.x86"}) {
+		t.Errorf("expected the host to be terminated, got %v", terminated)
+	}
+
+	// Never terminate the instance that was just launched
+	terminated = nil
+	w.Verified.InstanceID = "ocid1.instance.x86"
+	w.bootstrap(context.Background())
+	if len(terminated) != 0 {
+		t.Errorf("expected the new instance to be kept, terminated %v", terminated)
+	}
+}
+
+func TestAccountWorker_ImageAlias(t *testing.T) {
+	image := func(id, version, name string) core.Image {
+		return core.Image{Id: common.String(id), OperatingSystemVersion: common.String(version), DisplayName: common.String(name)}
+	}
+	calls := 0
+	var listErr error
+	compute := &MockComputeClient{
+		ListImagesFunc: func(ctx context.Context, request core.ListImagesRequest) (core.ListImagesResponse, error) {
+			calls++
+			if *request.OperatingSystem != "Canonical Ubuntu" || *request.Shape != "VM.Standard.A1.Flex" || request.SortOrder != core.ListImagesSortOrderDesc {
+				t.Errorf("unexpected request %+v", request)
+			}
+			if listErr != nil {
+				return core.ListImagesResponse{}, listErr
+			}
+			if request.Page == nil {
+				return core.ListImagesResponse{
+					Items: []core.Image{
+						image("minimal", "22.04 Minimal aarch64", "Canonical-Ubuntu-22.04-Minimal-aarch64-2025.01.31-0"),
+						image("noble", "24.04", "Canonical-Ubuntu-24.04-aarch64-2025.01.31-0"),
+					},
+					OpcNextPage: common.String("2"),
+				}, nil
+			}
+			return core.ListImagesResponse{Items: []core.Image{
+				image("jammy", "22.04", "Canonical-Ubuntu-22.04-aarch64-2025.01.31-0"),
+				image("jammy-old", "22.04", "Canonical-Ubuntu-22.04-aarch64-2024.10.06-0"),
+			}}, nil
+		},
+	}
+	w := &AccountWorker{
+		AccountName:   "test",
+		Config:        &config.AccountConfig{Image: "ubuntu-22.04-arm", TenancyOCID: "tenancy"},
+		Logger:        newMockLogger(),
+		ComputeClient: compute,
+	}
+
+	id, err := w.imageID(context.Background(), "VM.Standard.A1.Flex")
+	if err != nil || id != "jammy" {
+		t.Fatalf("expected the newest 22.04 image, got %q, %v", id, err)
+	}
+	if id, _ := w.imageID(context.Background(), "VM.Standard.A1.Flex"); id != "jammy" || calls != 2 {
+		t.Errorf("expected the cached image without listing again, got %q after %d calls", id, calls)
+	}
+
+	// Once the cache expires, a failed lookup keeps the image
+	w.images["VM.Standard.A1.Flex"] = resolvedImage{id: "jammy", name: "jammy", at: time.Now().Add(-imageCacheTTL)}
+	listErr = newServiceError(500, "InternalError")
+	if id, err := w.imageID(context.Background(), "VM.Standard.A1.Flex"); err != nil || id != "jammy" {
+		t.Errorf("expected the cached image on error, got %q, %v", id, err)
+	}
+
+	w.Config.Image = "ubuntu-22.04-minimal-arm"
+	w.images = nil
+	listErr = nil
+	if id, err := w.imageID(context.Background(), "VM.Standard.A1.Flex"); err != nil || id != "minimal" {
+		t.Errorf("expected the minimal image, got %q, %v", id, err)
+	}
+
+	// image_ocid is used as is
+	w.Config.Image, w.Config.ImageOCID = "", "ocid1.image.fixed"
+	if id, _ := w.imageID(context.Background(), "VM.Standard.A1.Flex"); id != "ocid1.image.fixed" {
+		t.Errorf("expected image_ocid, got %q", id)
+	}
+}
+
+func TestAccountWorker_MissingPolicies(t *testing.T) {
+	notFound := newServiceError(404, "Authorization failed or requested resource not found.")
+	var listErr, subnetErr, launchErr error
+	launches := 0
+	compute := &MockComputeClient{
+		ListInstancesFunc: func(ctx context.Context, request core.ListInstancesRequest) (core.ListInstancesResponse, error) {
+			return core.ListInstancesResponse{}, listErr
+		},
+		LaunchInstanceFunc: func(ctx context.Context, request core.LaunchInstanceRequest) (core.LaunchInstanceResponse, error) {
+			launches++
+			return core.LaunchInstanceResponse{}, launchErr
+		},
+	}
+	network := &MockVirtualNetworkClient{
+		GetSubnetFunc: func(ctx context.Context, request core.GetSubnetRequest) (core.GetSubnetResponse, error) {
+			return core.GetSubnetResponse{}, subnetErr
+		},
+	}
+	st, _ := state.Open(nil)
+	w := &AccountWorker{
+		AccountName: "test",
+		Config: &config.AccountConfig{
+			TenancyOCID:        "ocid1.tenancy.x",
+			CompartmentOCID:    "ocid1.compartment.x",
+			SubnetOCID:         "ocid1.subnet.x",
+			AvailabilityDomain: "AD-1",
+		},
+		Logger:               newMockLogger(),
+		Notifier:             notifier.New(config.NotificationConfig{Enabled: false}),
+		Tracker:              notifier.NewTracker(),
+		State:                st,
+		ComputeClient:        compute,
+		IdentityClient:       &MockIdentityClient{},
+		VirtualNetworkClient: network,
+	}
+
+	listErr, subnetErr = notFound, notFound
+	_, retry, err := w.Provision(context.Background())
+	if err == nil || retry || launches != 0 {
+		t.Fatalf("expected missing policies to stop before launching, got retry=%v, %d launches, %v", retry, launches, err)
+	}
+	for _, want := range []string{
+		"Allow group <your-group> to manage instance-family in compartment id ocid1.compartment.x",
+		"Allow group <your-group> to use virtual-network-family in compartment id ocid1.compartment.x",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in %v", want, err)
+		}
+	}
+
+	// Once the policies are in place the launch goes ahead, and a refused launch says what to check
+	listErr, subnetErr, launchErr = nil, nil, notFound
+	_, _, err = w.Provision(context.Background())
+	if launches != 1 || err == nil || !strings.Contains(err.Error(), "use volume-family") {
+		t.Fatalf("expected the launch error to list the launch policies, got %d launches, %v", launches, err)
+	}
+	if w.policiesOK {
+		t.Error("expected a refused launch to check the policies again")
+	}
+
+	// Policies for the whole tenancy, signed as the instance
+	w.Config.CompartmentOCID, w.Config.Auth = "", config.AuthInstancePrincipal
+	if got := w.policyStatement("use volume-family"); got != "Allow dynamic-group <your-dynamic-group> to use volume-family in tenancy" {
+		t.Errorf("unexpected statement %q", got)
+	}
+}
+
+func TestAccountWorker_RefreshSession(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as the OCI CLI")
+	}
+	dir := t.TempDir()
+	tokenFile := filepath.Join(dir, "token")
+	token := func(exp time.Time) string {
+		payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"exp": %d}`, exp.Unix())))
+		return "eyJhbGciOiJSUzI1NiJ9." + payload + ".c2ln"
+	}
+	ociConfig := filepath.Join(dir, "config")
+	os.WriteFile(ociConfig, []byte("[sso]\nkey_file="+filepath.Join(dir, "key.pem")+"\nsecurity_token_file="+tokenFile+"\n"), 0600)
+
+	// The fake CLI writes a token valid for an hour, or fails when asked to
+	fresh := token(time.Now().Add(time.Hour))
+	ociCommand = filepath.Join(dir, "oci")
+	defer func() { ociCommand = "oci" }()
+	os.WriteFile(ociCommand, []byte("#!/bin/sh\n[ -f "+dir+"/fail ] && { echo 'ERROR: session expired'; exit 1; }\necho '"+fresh+"' > "+tokenFile+"\n"), 0700)
+
+	w := &AccountWorker{
+		AccountName: "test",
+		Config:      &config.AccountConfig{Auth: config.AuthSecurityToken, OCIProfile: "sso", OCIConfigFile: ociConfig},
+		Logger:      newMockLogger(),
+	}
+
+	// Far from expiry: nothing to do
+	valid := token(time.Now().Add(50 * time.Minute))
+	os.WriteFile(tokenFile, []byte(valid), 0600)
+	if err := w.refreshSession(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if content, _ := os.ReadFile(tokenFile); string(content) != valid {
+		t.Error("expected the token to be left alone")
+	}
+
+	// About to expire: refreshed
+	os.WriteFile(tokenFile, []byte(token(time.Now().Add(5*time.Minute))), 0600)
+	if err := w.refreshSession(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expiry, err := sessionExpiry(tokenFile); err != nil || time.Until(expiry) < 50*time.Minute {
+		t.Errorf("expected a refreshed token, got %v, %v", expiry, err)
+	}
+
+	// A failed refresh only matters once the token expired
+	os.WriteFile(filepath.Join(dir, "fail"), nil, 0600)
+	os.WriteFile(tokenFile, []byte(token(time.Now().Add(5*time.Minute))), 0600)
+	if err := w.refreshSession(context.Background()); err != nil {
+		t.Errorf("expected only a warning before expiry, got %v", err)
+	}
+	os.WriteFile(tokenFile, []byte(token(time.Now().Add(-time.Minute))), 0600)
+	err := w.refreshSession(context.Background())
+	if !isAuthError(err) || !strings.Contains(err.Error(), "oci session authenticate --profile-name sso") {
+		t.Errorf("expected an authentication error telling to sign in again, got %v", err)
+	}
+}
+
+func TestProvisioner_DiscoverAlreadyNotified(t *testing.T) {
+	compute := &MockComputeClient{
+		ListInstancesFunc: func(ctx context.Context, request core.ListInstancesRequest) (core.ListInstancesResponse, error) {
+			return core.ListInstancesResponse{Items: []core.Instance{{
+				Id:             common.String("mine"),
+				DisplayName:    common.String("arm-mine"),
+				LifecycleState: core.InstanceLifecycleStateRunning,
+				FreeformTags:   (&AccountWorker{AccountName: "test"}).ownerTags(),
+			}}}, nil
+		},
+	}
+	// The state lost the instance, but remembers announcing it
+	st, _ := state.Open(nil)
+	st.MarkNotified("test", "mine")
+
+	var logs []string
+	l := newMockLogger()
+	l.AddHook(func(level, account, msg string) { logs = append(logs, msg) })
+	w := &AccountWorker{
+		AccountName:          "test",
+		Config:               &config.AccountConfig{},
+		Logger:               l,
+		ComputeClient:        compute,
+		IdentityClient:       &MockIdentityClient{},
+		VirtualNetworkClient: &MockVirtualNetworkClient{},
+		State:                st,
+	}
+	p := &Provisioner{
+		Logger:      l,
+		Notifier:    notifier.New(config.NotificationConfig{Enabled: false}),
+		State:       st,
+		Workers:     []*AccountWorker{w},
+		Provisioned: make(map[string]bool),
+	}
+
+	p.Discover(context.Background())
+
+	joined := strings.Join(logs, "\n")
+	if !strings.Contains(joined, "Found managed instance arm-mine") || !strings.Contains(joined, "not notifying again") {
+		t.Errorf("expected the rediscovery logged and its notification skipped, got:\n%s", joined)
+	}
+	if !p.Provisioned["test"] {
+		t.Error("expected the account to be marked provisioned")
+	}
+}
+
+func TestAccountWorker_InlineKey(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPEM := string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+	// Kubernetes secrets and .env files often carry the PEM on one line
+	t.Setenv("OCI_TEST_KEY", strings.ReplaceAll(keyPEM, "\n", `\n`))
+
+	for name, acc := range map[string]*config.AccountConfig{
+		"key_content": {KeyContent: keyPEM},
+		"key_env":     {KeyEnv: "OCI_TEST_KEY"},
+	} {
+		w := &AccountWorker{AccountName: "test", Config: acc, Logger: newMockLogger()}
+		provider, err := w.getProvider()
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if got, err := provider.PrivateRSAKey(); err != nil || !got.Equal(key) {
+			t.Errorf("%s: expected the inline key, got %v", name, err)
+		}
+	}
+
+	w := &AccountWorker{AccountName: "test", Config: &config.AccountConfig{KeyContent: "not a key"}, Logger: newMockLogger()}
+	if _, err := w.getProvider(); err == nil || !strings.Contains(err.Error(), "failed to decode PEM block from key_content") {
+		t.Errorf("expected a PEM error naming key_content, got %v", err)
+	}
+}
+
+func TestAccountWorker_ProxyURL(t *testing.T) {
+	var mu sync.Mutex
+	var tunnels []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		tunnels = append(tunnels, r.Method+" "+r.Host)
+		mu.Unlock()
+		http.Error(w, "denied", http.StatusForbidden)
+	}))
+	defer proxy.Close()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := &AccountWorker{AccountName: "test", Logger: newMockLogger(), Config: &config.AccountConfig{
+		UserOCID:    "ocid1.user.oc1..test",
+		TenancyOCID: "ocid1.tenancy.oc1..test",
+		Fingerprint: "aa:bb:cc",
+		Region:      "us-ashburn-1",
+		KeyContent:  string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})),
+		ProxyURL:    proxy.URL,
+	}}
+	if err := w.initClients(); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := w.IdentityClient.ListAvailabilityDomains(ctx, identity.ListAvailabilityDomainsRequest{CompartmentId: common.String("ocid1.tenancy.oc1..test")}); err == nil {
+		t.Fatal("expected the proxy to refuse the request")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(tunnels) == 0 || tunnels[0] != "CONNECT identity.us-ashburn-1.oci.oraclecloud.com:443" {
+		t.Errorf("expected the request tunneled through the proxy, got %v", tunnels)
+	}
+}
+
+func TestAccountWorker_VaultKey(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	secretID := "ocid1.vaultsecret.oc1.eu-frankfurt-1.abc"
+
+	calls := 0
+	w := &AccountWorker{
+		AccountName: "test",
+		Config:      &config.AccountConfig{KeyVaultSecretOCID: secretID},
+		Logger:      newMockLogger(),
+		SecretsClient: &MockSecretsClient{
+			GetSecretBundleFunc: func(ctx context.Context, request secrets.GetSecretBundleRequest) (secrets.GetSecretBundleResponse, error) {
+				calls++
+				if *request.SecretId != secretID || request.Stage != secrets.GetSecretBundleStageCurrent {
+					t.Errorf("unexpected request %+v", request)
+				}
+				return secrets.GetSecretBundleResponse{SecretBundle: secrets.SecretBundle{
+					SecretBundleContent: secrets.Base64SecretBundleContentDetails{Content: common.String(base64.StdEncoding.EncodeToString(keyPEM))},
+				}}, nil
+			},
+		},
+	}
+	for i := 0; i < 2; i++ {
+		provider, err := w.getProvider()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, err := provider.PrivateRSAKey(); err != nil || !got.Equal(key) {
+			t.Errorf("expected the vault key, got %v", err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected the secret to be read once, got %d reads", calls)
+	}
+
+	w = &AccountWorker{
+		AccountName: "test",
+		Config:      &config.AccountConfig{KeyVaultSecretOCID: secretID},
+		Logger:      newMockLogger(),
+		SecretsClient: &MockSecretsClient{
+			GetSecretBundleFunc: func(ctx context.Context, request secrets.GetSecretBundleRequest) (secrets.GetSecretBundleResponse, error) {
+				return secrets.GetSecretBundleResponse{}, newServiceError(404, "NotAuthorizedOrNotFound")
+			},
+		},
+	}
+	if _, err := w.getProvider(); err == nil || !strings.Contains(err.Error(), "read secret-bundles") {
+		t.Errorf("expected a hint about the secret-bundles policy, got %v", err)
+	}
+}
+
+func TestProvisioner_ReclaimReusesBootVolume(t *testing.T) {
+	alive := true
+	var source core.InstanceSourceDetails
+	var launchAD string
+	compute := &MockComputeClient{
+		GetInstanceFunc: func(ctx context.Context, request core.GetInstanceRequest) (core.GetInstanceResponse, error) {
+			if !alive {
+				return core.GetInstanceResponse{}, newServiceError(404, "NotAuthorizedOrNotFound")
+			}
+			return core.GetInstanceResponse{Instance: core.Instance{
+				Id:                 request.InstanceId,
+				LifecycleState:     core.InstanceLifecycleStateRunning,
+				AvailabilityDomain: common.String("AD-2"),
+				CompartmentId:      common.String("compartment"),
+			}}, nil
+		},
+		ListBootVolumeAttachmentsFunc: func(ctx context.Context, request core.ListBootVolumeAttachmentsRequest) (core.ListBootVolumeAttachmentsResponse, error) {
+			return core.ListBootVolumeAttachmentsResponse{Items: []core.BootVolumeAttachment{
+				{BootVolumeId: common.String("bv"), LifecycleState: core.BootVolumeAttachmentLifecycleStateAttached},
+			}}, nil
+		},
+		ListInstancesFunc: func(ctx context.Context, request core.ListInstancesRequest) (core.ListInstancesResponse, error) {
+			return core.ListInstancesResponse{}, nil
+		},
+		LaunchInstanceFunc: func(ctx context.Context, request core.LaunchInstanceRequest) (core.LaunchInstanceResponse, error) {
+			source, launchAD = request.SourceDetails, *request.AvailabilityDomain
+			return core.LaunchInstanceResponse{}, newServiceError(500, "Out of host capacity")
+		},
+	}
+	volumeState := core.BootVolumeLifecycleStateAvailable
+	storage := &MockBlockstorageClient{
+		GetBootVolumeFunc: func(ctx context.Context, request core.GetBootVolumeRequest) (core.GetBootVolumeResponse, error) {
+			return core.GetBootVolumeResponse{BootVolume: core.BootVolume{Id: request.BootVolumeId, LifecycleState: volumeState}}, nil
+		},
+	}
+	st, _ := state.Open(nil)
+	st.RecordInstance("test", state.Instance{ID: "arm", Name: "arm-1"})
+	w := &AccountWorker{
+		AccountName: "test",
+		Config: &config.AccountConfig{
+			AvailabilityDomain: "AD-1",
+			Reclaim:            config.ReclaimConfig{Relaunch: true, ReuseBootVolume: true},
+		},
+		Logger:               newMockLogger(),
+		Notifier:             notifier.New(config.NotificationConfig{Enabled: false}),
+		Tracker:              notifier.NewTracker(),
+		ComputeClient:        compute,
+		IdentityClient:       &MockIdentityClient{},
+		VirtualNetworkClient: &MockVirtualNetworkClient{},
+		BlockstorageClient:   storage,
+		State:                st,
+	}
+	p := &Provisioner{
+		Logger:      w.Logger,
+		State:       st,
+		Workers:     []*AccountWorker{w},
+		Provisioned: map[string]bool{"test": true},
+	}
+
+	// While the instance runs, its boot volume is recorded
+	p.checkReclaimed(context.Background(), w)
+	if got := st.Instances("test"); !p.Provisioned["test"] || len(got) != 1 || got[0].BootVolumeID != "bv" || got[0].AvailabilityDomain != "AD-2" {
+		t.Fatalf("expected the boot volume recorded, got %+v", got)
+	}
+
+	// Once it is gone, the account hunts again from that volume, in its AD
+	alive = false
+	p.checkReclaimed(context.Background(), w)
+	if volume, ok := st.BootVolume("test"); p.Provisioned["test"] || len(st.Instances("test")) != 0 || !ok || volume.ID != "bv" {
+		t.Fatalf("expected the instance forgotten and its boot volume kept, got %+v", volume)
+	}
+	w.Provision(context.Background())
+	if via, ok := source.(core.InstanceSourceViaBootVolumeDetails); !ok || *via.BootVolumeId != "bv" || launchAD != "AD-2" {
+		t.Errorf("expected a launch from the boot volume in AD-2, got %+v in %s", source, launchAD)
+	}
+
+	// A volume deleted in the meantime falls back to the image
+	volumeState = core.BootVolumeLifecycleStateTerminated
+	w.Provision(context.Background())
+	if _, ok := source.(core.InstanceSourceViaImageDetails); !ok || launchAD != "AD-1" {
+		t.Errorf("expected a launch from the image, got %+v in %s", source, launchAD)
+	}
+	if _, ok := st.BootVolume("test"); ok {
+		t.Error("expected the deleted boot volume to be dropped")
+	}
+
+	// Without a recorded instance nothing was reclaimed
+	p.Provisioned["test"] = true
+	p.checkReclaimed(context.Background(), w)
+	if !p.Provisioned["test"] {
+		t.Error("expected the account to stay provisioned without a recorded instance")
+	}
+}
+
+func TestAccountWorker_Provision_InstanceCount(t *testing.T) {
+	ocpus, memory := float32(2), float32(12)
+	var launched []core.Instance
+	mock := &MockComputeClient{
+		ListInstancesFunc: func(ctx context.Context, request core.ListInstancesRequest) (core.ListInstancesResponse, error) {
+			return core.ListInstancesResponse{Items: launched}, nil
+		},
+		LaunchInstanceFunc: func(ctx context.Context, request core.LaunchInstanceRequest) (core.LaunchInstanceResponse, error) {
+			inst := core.Instance{
+				Id:             common.String(fmt.Sprintf("inst-%d", len(launched)+1)),
+				DisplayName:    request.DisplayName,
+				LifecycleState: core.InstanceLifecycleStateRunning,
+				FreeformTags:   request.FreeformTags,
+			}
+			launched = append(launched, inst)
+			return core.LaunchInstanceResponse{Instance: inst}, nil
+		},
+		GetInstanceFunc: func(ctx context.Context, request core.GetInstanceRequest) (core.GetInstanceResponse, error) {
+			return core.GetInstanceResponse{Instance: core.Instance{
+				Id:             request.InstanceId,
+				LifecycleState: core.InstanceLifecycleStateRunning,
+				ShapeConfig:    &core.InstanceShapeConfig{Ocpus: &ocpus, MemoryInGBs: &memory},
+			}}, nil
+		},
+		ListVnicAttachmentsFunc: func(ctx context.Context, request core.ListVnicAttachmentsRequest) (core.ListVnicAttachmentsResponse, error) {
+			return core.ListVnicAttachmentsResponse{}, nil
+		},
+	}
+	w := &AccountWorker{
+		AccountName:          "test",
+		Config:               &config.AccountConfig{AvailabilityDomain: "AD-1", OCPUs: ocpus, MemoryGB: memory, InstanceCount: 2, DisplayName: "arm-{{.Seq}}"},
+		Logger:               newMockLogger(),
+		Notifier:             notifier.New(config.NotificationConfig{Enabled: false}),
+		Tracker:              notifier.NewTracker(),
+		ComputeClient:        mock,
+		IdentityClient:       &MockIdentityClient{},
+		VirtualNetworkClient: &MockVirtualNetworkClient{},
+	}
+	w.State, _ = state.Open(nil)
+
+	// Each launch is one more instance, numbered in the notification
+	for i := 1; i <= 2; i++ {
+		success, _, err := w.Provision(context.Background())
+		if err != nil || !success {
+			t.Fatalf("launch %d: expected success, got %v", i, err)
+		}
+		if w.Verified.Number != i || w.Verified.Count != 2 || w.launchesLeft != 2-i {
+			t.Errorf("launch %d: expected instance %d of 2, got %d of %d with %d left", i, i, w.Verified.Number, w.Verified.Count, w.launchesLeft)
+		}
+	}
+	if len(launched) != 2 || *launched[0].DisplayName != "arm-1" || *launched[1].DisplayName != "arm-2" {
+		t.Fatalf("expected arm-1 and arm-2, got %+v", launched)
+	}
+	if got := w.State.Instances("test"); len(got) != 2 {
+		t.Errorf("expected both instances tracked, got %+v", got)
+	}
+
+	// With both running, nothing more is launched
+	if success, _, err := w.Provision(context.Background()); err != nil || !success || len(launched) != 2 || w.launchesLeft != 0 {
+		t.Errorf("expected no third launch, got %d launches, %v", len(launched), err)
+	}
+}
+
+func TestProvisioner_SplitOnCapacity(t *testing.T) {
+	var launched []*core.LaunchInstanceShapeConfigDetails
+	var launchADs []string
+	var terminated []string
+	available := false
+	compute := &MockComputeClient{
+		ListInstancesFunc: func(ctx context.Context, request core.ListInstancesRequest) (core.ListInstancesResponse, error) {
+			return core.ListInstancesResponse{}, nil
+		},
+		LaunchInstanceFunc: func(ctx context.Context, request core.LaunchInstanceRequest) (core.LaunchInstanceResponse, error) {
+			launched = append(launched, request.ShapeConfig)
+			launchADs = append(launchADs, *request.AvailabilityDomain)
+			if *request.ShapeConfig.Ocpus == 4 && !available {
+				return core.LaunchInstanceResponse{}, newServiceError(500, "Out of host capacity")
+			}
+			return core.LaunchInstanceResponse{Instance: core.Instance{Id: common.String(fmt.Sprintf("inst-%d", len(launched)))}}, nil
+		},
+		GetInstanceFunc: func(ctx context.Context, request core.GetInstanceRequest) (core.GetInstanceResponse, error) {
+			return core.GetInstanceResponse{Instance: core.Instance{Id: request.InstanceId, LifecycleState: core.InstanceLifecycleStateRunning}}, nil
+		},
+		ListVnicAttachmentsFunc: func(ctx context.Context, request core.ListVnicAttachmentsRequest) (core.ListVnicAttachmentsResponse, error) {
+			return core.ListVnicAttachmentsResponse{}, nil
+		},
+		TerminateInstanceFunc: func(ctx context.Context, request core.TerminateInstanceRequest) (core.TerminateInstanceResponse, error) {
+			terminated = append(terminated, *request.InstanceId)
+			return core.TerminateInstanceResponse{}, nil
+		},
+		CreateComputeCapacityReportFunc: func(ctx context.Context, request core.CreateComputeCapacityReportRequest) (core.CreateComputeCapacityReportResponse, error) {
+			status := core.CapacityReportShapeAvailabilityAvailabilityStatusOutOfHostCapacity
+			if available && *request.AvailabilityDomain == "AD-2" {
+				status = core.CapacityReportShapeAvailabilityAvailabilityStatusAvailable
+			}
+			return core.CreateComputeCapacityReportResponse{ComputeCapacityReport: core.ComputeCapacityReport{
+				ShapeAvailabilities: []core.CapacityReportShapeAvailability{{AvailabilityStatus: status}},
+			}}, nil
+		},
+	}
+	st, _ := state.Open(nil)
+	w := &AccountWorker{
+		AccountName: "test",
+		Config: &config.AccountConfig{
+			AvailabilityDomain: "auto", Shape: "VM.Standard.E4.Flex", OCPUs: 4, MemoryGB: 24,
+			SplitOnCapacity: true, FallbackAfter: 1,
+		},
+		Logger:        newMockLogger(),
+		Notifier:      notifier.New(config.NotificationConfig{Enabled: false}),
+		Tracker:       notifier.NewTracker(),
+		ComputeClient: compute,
+		IdentityClient: &MockIdentityClient{
+			ListAvailabilityDomainsFunc: func(ctx context.Context, request identity.ListAvailabilityDomainsRequest) (identity.ListAvailabilityDomainsResponse, error) {
+				return identity.ListAvailabilityDomainsResponse{Items: []identity.AvailabilityDomain{{Name: common.String("AD-1")}, {Name: common.String("AD-2")}}}, nil
+			},
+		},
+		VirtualNetworkClient: &MockVirtualNetworkClient{},
+		State:                st,
+	}
+	p := &Provisioner{Logger: w.Logger, State: st, Workers: []*AccountWorker{w}, Provisioned: map[string]bool{}}
+
+	// A capacity error on the full size moves to half of it, which launches
+	if success, _, _ := w.Provision(context.Background()); success {
+		t.Fatal("expected the full size to fail")
+	}
+	if success, _, err := w.Provision(context.Background()); !success || err != nil {
+		t.Fatalf("expected the slice to launch, got %v", err)
+	}
+	if len(launched) != 2 || *launched[1].Ocpus != 2 || *launched[1].MemoryInGBs != 12 {
+		t.Fatalf("expected 4/24 then 2/12, got %+v", launched)
+	}
+	if slice, ok := w.slice(); !ok || slice.ID != "inst-2" || w.shape().OCPUs != 4 {
+		t.Fatalf("expected inst-2 recorded as a slice and the next hunt at full size, got %+v", slice)
+	}
+	p.Provisioned["test"] = true
+
+	// No room for the full size: the slice stays
+	if p.checkMergeUp(context.Background(), w) || len(terminated) != 0 {
+		t.Fatal("expected no merge-up without capacity")
+	}
+
+	// Room in AD-2: the slice is replaced by the full size, launched there
+	available = true
+	w.lastMergeCheck = time.Time{}
+	if !p.checkMergeUp(context.Background(), w) || p.Provisioned["test"] || len(terminated) != 1 || terminated[0] != "inst-2" {
+		t.Fatalf("expected the slice terminated, got %v", terminated)
+	}
+	if success, _, err := w.Provision(context.Background()); !success || err != nil {
+		t.Fatalf("expected the full size to launch, got %v", err)
+	}
+	if n := len(launched); *launched[n-1].Ocpus != 4 || launchADs[n-1] != "AD-2" {
+		t.Errorf("expected 4 OCPUs in AD-2, got %v in %s", *launched[n-1].Ocpus, launchADs[n-1])
+	}
+	if got := st.Instances("test"); len(got) != 1 || got[0].Slice {
+		t.Errorf("expected only the full-size instance, got %+v", got)
+	}
+}
+
+func TestAccountWorker_UploadReport(t *testing.T) {
+	got := make(map[string]string)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got[filepath.Base(r.URL.Path)] = string(body)
+	}))
+	defer srv.Close()
+
+	w := &AccountWorker{
+		AccountName: "test",
+		Config:      &config.AccountConfig{},
+		Logger:      newMockLogger(),
+		ComputeClient: &MockComputeClient{
+			GetInstanceFunc: func(ctx context.Context, request core.GetInstanceRequest) (core.GetInstanceResponse, error) {
+				return core.GetInstanceResponse{Instance: core.Instance{Id: request.InstanceId, Shape: common.String("VM.Standard.A1.Flex")}}, nil
+			},
+			CaptureConsoleHistoryFunc: func(ctx context.Context, request core.CaptureConsoleHistoryRequest) (core.CaptureConsoleHistoryResponse, error) {
+				return core.CaptureConsoleHistoryResponse{}, newServiceError(404, "NotAuthorizedOrNotFound")
+			},
+		},
+		Report: report.New(config.SuccessReportConfig{PARURL: srv.URL + "/p/token/n/ns/b/bucket/o/"}),
+	}
+	w.uploadReport(context.Background(), &VerifiedInstance{InstanceID: "ocid1.instance.oc1..launched", Verified: true})
+
+	if len(got) != 2 || got["console.txt"] != "" {
+		t.Fatalf("expected the instance and verification without the console, got %v", got)
+	}
+	if !strings.Contains(got["instance.json"], "VM.Standard.A1.Flex") || !strings.Contains(got["verification.json"], `"Verified": true`) {
+		t.Errorf("unexpected report %v", got)
+	}
+}
+
+func TestAccountWorker_Provision_UpgradeExisting(t *testing.T) {
+	room := false
+	terminated := false
+	var source core.InstanceSourceDetails
+	var launchErr error
+	existing := core.Instance{
+		Id:                 common.String("small"),
+		DisplayName:        common.String("arm"),
+		Shape:              common.String("VM.Standard.A1.Flex"),
+		ShapeConfig:        &core.InstanceShapeConfig{Ocpus: common.Float32(2), MemoryInGBs: common.Float32(12)},
+		LifecycleState:     core.InstanceLifecycleStateRunning,
+		AvailabilityDomain: common.String("AD-2"),
+		CompartmentId:      common.String("compartment"),
+	}
+	compute := &MockComputeClient{
+		ListInstancesFunc: func(ctx context.Context, request core.ListInstancesRequest) (core.ListInstancesResponse, error) {
+			if terminated {
+				return core.ListInstancesResponse{}, nil
+			}
+			return core.ListInstancesResponse{Items: []core.Instance{existing}}, nil
+		},
+		CreateComputeCapacityReportFunc: func(ctx context.Context, request core.CreateComputeCapacityReportRequest) (core.CreateComputeCapacityReportResponse, error) {
+			if *request.AvailabilityDomain != "AD-2" || *request.ShapeAvailabilities[0].InstanceShapeConfig.Ocpus != 4 {
+				t.Errorf("expected a report for 4 OCPUs in the instance's AD, got %+v", request)
+			}
+			status := core.CapacityReportShapeAvailabilityAvailabilityStatusOutOfHostCapacity
+			if room {
+				status = core.CapacityReportShapeAvailabilityAvailabilityStatusAvailable
+			}
+			return core.CreateComputeCapacityReportResponse{ComputeCapacityReport: core.ComputeCapacityReport{
+				ShapeAvailabilities: []core.CapacityReportShapeAvailability{{AvailabilityStatus: status}},
+			}}, nil
+		},
+		ListBootVolumeAttachmentsFunc: func(ctx context.Context, request core.ListBootVolumeAttachmentsRequest) (core.ListBootVolumeAttachmentsResponse, error) {
+			return core.ListBootVolumeAttachmentsResponse{Items: []core.BootVolumeAttachment{
+				{BootVolumeId: common.String("bv"), LifecycleState: core.BootVolumeAttachmentLifecycleStateAttached},
+			}}, nil
+		},
+		TerminateInstanceFunc: func(ctx context.Context, request core.TerminateInstanceRequest) (core.TerminateInstanceResponse, error) {
+			if !*request.PreserveBootVolume {
+				t.Error("expected the boot volume preserved")
+			}
+			terminated = true
+			return core.TerminateInstanceResponse{}, nil
+		},
+		GetInstanceFunc: func(ctx context.Context, request core.GetInstanceRequest) (core.GetInstanceResponse, error) {
+			time.Sleep(100 * time.Millisecond) // Terminating takes longer than the launch timeout
+			return core.GetInstanceResponse{Instance: core.Instance{LifecycleState: core.InstanceLifecycleStateTerminated}}, nil
+		},
+		LaunchInstanceFunc: func(ctx context.Context, request core.LaunchInstanceRequest) (core.LaunchInstanceResponse, error) {
+			source, launchErr = request.SourceDetails, ctx.Err()
+			return core.LaunchInstanceResponse{}, newServiceError(500, "Out of host capacity")
+		},
+	}
+	w := &AccountWorker{
+		AccountName: "test",
+		Config: &config.AccountConfig{
+			AvailabilityDomain: "AD-2",
+			Shape:              "VM.Standard.A1.Flex",
+			OCPUs:              4,
+			MemoryGB:           24,
+			DisplayName:        "arm",
+		},
+		Logger:               newMockLogger(),
+		Notifier:             notifier.New(config.NotificationConfig{Enabled: false}),
+		Tracker:              notifier.NewTracker(),
+		LaunchTimeout:        50 * time.Millisecond,
+		ComputeClient:        compute,
+		IdentityClient:       &MockIdentityClient{},
+		VirtualNetworkClient: &MockVirtualNetworkClient{},
+		BlockstorageClient: &MockBlockstorageClient{
+			GetBootVolumeFunc: func(ctx context.Context, request core.GetBootVolumeRequest) (core.GetBootVolumeResponse, error) {
+				return core.GetBootVolumeResponse{BootVolume: core.BootVolume{LifecycleState: core.BootVolumeLifecycleStateAvailable}}, nil
+			},
+		},
+	}
+	w.State, _ = state.Open(nil)
+
+	// Without upgrade_existing the smaller instance only counts as done
+	if success, _, err := w.Provision(context.Background()); !success || err != nil {
+		t.Fatalf("expected the existing instance to count, got %v, %v", success, err)
+	}
+
+	// With it, the account waits for room for the full size
+	w.Config.UpgradeExisting = true
+	if success, _, err := w.Provision(context.Background()); success || err != nil || terminated {
+		t.Fatalf("expected to keep waiting for capacity, got %v, %v", success, err)
+	}
+
+	// Then replaces the instance and launches from its boot volume
+	room = true
+	w.Provision(context.Background())
+	if !terminated {
+		t.Fatal("expected the small instance terminated")
+	}
+	if via, ok := source.(core.InstanceSourceViaBootVolumeDetails); !ok || *via.BootVolumeId != "bv" {
+		t.Errorf("expected a launch from the boot volume, got %+v", source)
+	}
+	if launchErr != nil {
+		t.Errorf("expected the relaunch to get its own timeout, got %v", launchErr)
+	}
+}
+
+func TestAccountWorker_Throttle(t *testing.T) {
+	w := &AccountWorker{
+		AccountName:   "test",
+		Config:        &config.AccountConfig{},
+		Logger:        newMockLogger(),
+		Notifier:      notifier.New(config.NotificationConfig{Enabled: false}),
+		ThrottleRatio: 0.5,
+	}
+	network := errors.New("dial tcp: i/o timeout")
+	capacity := newServiceError(500, "Out of host capacity")
+
+	// Capacity errors are expected answers, not failures
+	for i := 0; i < failureWindow; i++ {
+		w.observe(capacity)
+	}
+	if w.throttled() {
+		t.Fatal("expected capacity errors not to slow the account down")
+	}
+
+	// Mostly failing requests stretch the attempts to every 2nd cycle
+	for i := 0; i < failureWindow; i++ {
+		w.observe(network)
+	}
+	var ran []bool
+	for i := 0; i < 2; i++ {
+		ran = append(ran, !w.throttled())
+	}
+	if fmt.Sprint(ran) != "[false true]" {
+		t.Fatalf("expected every 2nd cycle, got %v", ran)
+	}
+
+	// An attempt that still fails doubles the stretch
+	w.observe(network)
+	ran = ran[:0]
+	for i := 0; i < 4; i++ {
+		ran = append(ran, !w.throttled())
+	}
+	if fmt.Sprint(ran) != "[false false false true]" {
+		t.Fatalf("expected every 4th cycle, got %v", ran)
+	}
+
+	// One good attempt restores full speed
+	w.observe(nil)
+	w.observe(capacity)
+	if w.throttled() || w.throttled() || w.failures.factor != 1 {
+		t.Errorf("expected full speed again, got factor %d", w.failures.factor)
+	}
+
+	// Disabled
+	w.ThrottleRatio = 0
+	for i := 0; i < failureWindow; i++ {
+		w.observe(network)
+	}
+	if w.throttled() {
+		t.Error("expected no throttling with throttle_ratio 0")
+	}
+}
+
+func TestAccountWorker_Provision_CapacityProbe(t *testing.T) {
+	var launched []string
+	roomIn := ""
+	var reportErr error
+	w := &AccountWorker{
+		AccountName: "test",
+		Config:      &config.AccountConfig{Shape: "VM.Standard.A1.Flex", AvailabilityDomain: "auto", TryAllADs: true, OCPUs: 4, MemoryGB: 24, CapacityProbe: true},
+		Logger:      newMockLogger(),
+		Notifier:    notifier.New(config.NotificationConfig{Enabled: false}),
+		Tracker:     notifier.NewTracker(),
+		ComputeClient: &MockComputeClient{
+			ListInstancesFunc: func(ctx context.Context, request core.ListInstancesRequest) (core.ListInstancesResponse, error) {
+				return core.ListInstancesResponse{}, nil
+			},
+			CreateComputeCapacityReportFunc: func(ctx context.Context, request core.CreateComputeCapacityReportRequest) (core.CreateComputeCapacityReportResponse, error) {
+				if reportErr != nil {
+					return core.CreateComputeCapacityReportResponse{}, reportErr
+				}
+				if cfg := request.ShapeAvailabilities[0].InstanceShapeConfig; *cfg.Ocpus != 4 || *cfg.MemoryInGBs != 24 {
+					t.Errorf("unexpected size in the capacity report %+v", cfg)
+				}
+				status := core.CapacityReportShapeAvailabilityAvailabilityStatusOutOfHostCapacity
+				if *request.AvailabilityDomain == roomIn {
+					status = core.CapacityReportShapeAvailabilityAvailabilityStatusAvailable
+				}
+				return core.CreateComputeCapacityReportResponse{ComputeCapacityReport: core.ComputeCapacityReport{
+					ShapeAvailabilities: []core.CapacityReportShapeAvailability{
+						{FaultDomain: common.String("FAULT-DOMAIN-1"), AvailabilityStatus: core.CapacityReportShapeAvailabilityAvailabilityStatusOutOfHostCapacity},
+						{FaultDomain: common.String("FAULT-DOMAIN-2"), AvailabilityStatus: status},
+					},
+				}}, nil
+			},
+			LaunchInstanceFunc: func(ctx context.Context, request core.LaunchInstanceRequest) (core.LaunchInstanceResponse, error) {
+				launched = append(launched, *request.AvailabilityDomain+"/"+safeString(request.FaultDomain))
+				return core.LaunchInstanceResponse{}, newServiceError(500, "Out of host capacity")
+			},
+		},
+		IdentityClient: &MockIdentityClient{
+			ListAvailabilityDomainsFunc: func(ctx context.Context, request identity.ListAvailabilityDomainsRequest) (identity.ListAvailabilityDomainsResponse, error) {
+				return identity.ListAvailabilityDomainsResponse{Items: []identity.AvailabilityDomain{{Name: common.String("AD-1")}, {Name: common.String("AD-2")}}}, nil
+			},
+		},
+		VirtualNetworkClient: &MockVirtualNetworkClient{},
+	}
+	w.State, _ = state.Open(nil)
+
+	// No AD has room: nothing is launched, and the account retries
+	if _, retry, err := w.Provision(context.Background()); !retry || err != nil || len(launched) != 0 {
+		t.Fatalf("expected a retry without launches, got retry=%v err=%v launched=%v", retry, err, launched)
+	}
+
+	// Only the AD with room is tried, in the fault domain that has it
+	roomIn = "AD-2"
+	w.Provision(context.Background())
+	if strings.Join(launched, ",") != "AD-2/FAULT-DOMAIN-2" {
+		t.Fatalf("expected one launch in AD-2's second fault domain, got %v", launched)
+	}
+
+	// Without a report, every AD is tried as before
+	launched, reportErr = nil, newServiceError(404, "NotAuthorizedOrNotFound")
+	w.Provision(context.Background())
+	if len(launched) != 2 || strings.Contains(strings.Join(launched, ","), "FAULT") {
+		t.Errorf("expected launches in both ADs without a fault domain, got %v", launched)
+	}
+}
+
+func TestAccountWorker_Provision_Sweep(t *testing.T) {
+	var launched []string
+	listed := 0
+	w := &AccountWorker{
+		AccountName: "test",
+		Config:      &config.AccountConfig{AvailabilityDomain: "auto", Sweep: config.SweepConfig{Enabled: true, MaxRequests: 4}},
+		Logger:      newMockLogger(),
+		Notifier:    notifier.New(config.NotificationConfig{Enabled: false}),
+		Tracker:     notifier.NewTracker(),
+		ComputeClient: &MockComputeClient{
+			ListInstancesFunc: func(ctx context.Context, request core.ListInstancesRequest) (core.ListInstancesResponse, error) {
+				return core.ListInstancesResponse{}, nil
+			},
+			LaunchInstanceFunc: func(ctx context.Context, request core.LaunchInstanceRequest) (core.LaunchInstanceResponse, error) {
+				launched = append(launched, *request.AvailabilityDomain+"/"+safeString(request.FaultDomain))
+				return core.LaunchInstanceResponse{}, newServiceError(500, "Out of host capacity")
+			},
+		},
+		IdentityClient: &MockIdentityClient{
+			ListAvailabilityDomainsFunc: func(ctx context.Context, request identity.ListAvailabilityDomainsRequest) (identity.ListAvailabilityDomainsResponse, error) {
+				return identity.ListAvailabilityDomainsResponse{Items: []identity.AvailabilityDomain{{Name: common.String("AD-1")}, {Name: common.String("AD-2")}}}, nil
+			},
+			ListFaultDomainsFunc: func(ctx context.Context, request identity.ListFaultDomainsRequest) (identity.ListFaultDomainsResponse, error) {
+				listed++
+				var items []identity.FaultDomain
+				for i := 1; i <= 3; i++ {
+					items = append(items, identity.FaultDomain{Name: common.String(fmt.Sprintf("FD-%d", i))})
+				}
+				return identity.ListFaultDomainsResponse{Items: items}, nil
+			},
+		},
+		VirtualNetworkClient: &MockVirtualNetworkClient{},
+	}
+	w.State, _ = state.Open(nil)
+
+	// Every fault domain in turn, across ADs, until the request budget is spent
+	if _, retry, err := w.Provision(context.Background()); !retry || err != nil {
+		t.Fatalf("expected a retry, got retry=%v err=%v", retry, err)
+	}
+	if got := strings.Join(launched, ","); got != "AD-1/FD-1,AD-1/FD-2,AD-1/FD-3,AD-2/FD-1" {
+		t.Errorf("unexpected sweep %s", got)
+	}
+
+	// The fault domains are only listed once
+	launched = nil
+	w.Provision(context.Background())
+	if listed != 2 || len(launched) != 4 || launched[0] != "AD-2/FD-1" {
+		t.Errorf("expected the next sweep to start at AD-2 without listing again, got %d lists and %v", listed, launched)
+	}
+
+	// A sweep that runs out of launch timeout counts as a capacity miss
+	launched = nil
+	w.Config.Sweep.DelaySeconds = 1
+	w.LaunchTimeout = 50 * time.Millisecond
+	w.Retry = config.RetryConfig{ExponentialBackoff: true, BaseIntervalMinutes: 1, MaxIntervalMinutes: 10}
+	if _, retry, err := w.Provision(context.Background()); !retry || err != nil || len(launched) != 1 {
+		t.Fatalf("expected one request and a retry, got %v, retry=%v err=%v", launched, retry, err)
+	}
+	if b, ok := w.State.Backoff("test"); !ok || b.Misses != 1 {
+		t.Errorf("expected the truncated sweep to count as a miss, got %+v", b)
+	}
+}
+
+func TestAccountWorker_LaunchADs_Prefer(t *testing.T) {
+	w := &AccountWorker{
+		AccountName: "test",
+		Config:      &config.AccountConfig{AvailabilityDomain: "auto", TryAllADs: true},
+		Tracker:     notifier.NewTracker(),
+		IdentityClient: &MockIdentityClient{
+			ListAvailabilityDomainsFunc: func(ctx context.Context, request identity.ListAvailabilityDomainsRequest) (identity.ListAvailabilityDomainsResponse, error) {
+				var items []identity.AvailabilityDomain
+				for i := 1; i <= 4; i++ {
+					items = append(items, identity.AvailabilityDomain{Name: common.String(fmt.Sprintf("AD-%d", i))})
+				}
+				return identity.ListAvailabilityDomainsResponse{Items: items}, nil
+			},
+		},
+	}
+
+	// Without any results, the rotation order
+	if ads, _ := w.launchADs(context.Background()); strings.Join(ads, ",") != "AD-1,AD-2,AD-3,AD-4" {
+		t.Errorf("expected the rotation order, got %v", ads)
+	}
+
+	// The fewest capacity errors in a row first, then the most recent launch
+	w.Tracker.RecordCapacityIn("test", "AD-2")
+	w.Tracker.RecordCapacityIn("test", "AD-3")
+	w.Tracker.RecordCapacityIn("test", "AD-3")
+	w.Tracker.RecordCapacityIn("test", "AD-4")
+	w.Tracker.RecordLaunchIn("test", "AD-4")
+	if ads, _ := w.launchADs(context.Background()); strings.Join(ads, ",") != "AD-4,AD-1,AD-2,AD-3" {
+		t.Errorf("expected AD-4 (launched) first and AD-3 (2 misses) last, got %v", ads)
+	}
+
+	// Only the preferred one without try_all_ads
+	w.Config.TryAllADs = false
+	if ads, _ := w.launchADs(context.Background()); len(ads) != 1 || ads[0] != "AD-4" {
+		t.Errorf("expected only AD-4, got %v", ads)
+	}
+}
+
+func TestAccountWorker_Provision_ErrorClasses(t *testing.T) {
+	var launchErr error
+	launches := 0
+	w := &AccountWorker{
+		AccountName: "test",
+		Config:      &config.AccountConfig{AvailabilityDomain: "auto", TryAllADs: true},
+		Logger:      newMockLogger(),
+		Notifier:    notifier.New(config.NotificationConfig{Enabled: false}),
+		Tracker:     notifier.NewTracker(),
+		ComputeClient: &MockComputeClient{
+			LaunchInstanceFunc: func(ctx context.Context, request core.LaunchInstanceRequest) (core.LaunchInstanceResponse, error) {
+				launches++
+				return core.LaunchInstanceResponse{}, launchErr
+			},
+		},
+		IdentityClient: &MockIdentityClient{
+			ListAvailabilityDomainsFunc: func(ctx context.Context, request identity.ListAvailabilityDomainsRequest) (identity.ListAvailabilityDomainsResponse, error) {
+				return identity.ListAvailabilityDomainsResponse{Items: []identity.AvailabilityDomain{{Name: common.String("AD-1")}, {Name: common.String("AD-2")}}}, nil
+			},
+		},
+		VirtualNetworkClient: &MockVirtualNetworkClient{},
+	}
+	w.State, _ = state.Open(nil)
+
+	for _, c := range []struct {
+		err      error
+		retry    bool
+		launches int // Capacity moves on to the next AD; other errors don't
+	}{
+		{newServiceError(500, "Out of host capacity."), true, 2},
+		{newServiceError(502, "Bad Gateway"), true, 1},
+		{newServiceError(429, "Too many requests"), true, 1},
+		{newServiceError(409, "Conflict"), false, 1},
+	} {
+		launchErr, launches = c.err, 0
+		_, retry, err := w.Provision(context.Background())
+		if retry != c.retry || (err == nil) != c.retry || launches != c.launches {
+			t.Errorf("%v: expected retry=%v after %d launches, got retry=%v err=%v after %d", c.err, c.retry, c.launches, retry, err, launches)
+		}
+	}
+
+	// A 5xx other than capacity doesn't count as a capacity hit
+	stats := w.Tracker.Snapshot().Accounts["test"]
+	if stats.CapacityErrors != 2 || stats.ErrorClasses["server"] != 1 || stats.ErrorClasses["rate_limit"] != 1 || stats.ErrorClasses["other"] != 1 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+func TestProvisioner_PreviewLaunches(t *testing.T) {
+	st, _ := state.Open(nil)
+	worker := func(name string, cfg *config.AccountConfig) *AccountWorker {
+		cfg.Shape, cfg.OCPUs, cfg.MemoryGB = "VM.Standard.A1.Flex", 4, 24
+		cfg.SubnetOCID, cfg.BootVolumeSizeGB = "ocid1.subnet.oc1..a", 50
+		return &AccountWorker{
+			AccountName:          name,
+			Config:               cfg,
+			Logger:               newMockLogger(),
+			State:                st,
+			ComputeClient:        &MockComputeClient{},
+			IdentityClient:       &MockIdentityClient{},
+			VirtualNetworkClient: &MockVirtualNetworkClient{},
+		}
+	}
+	p := &Provisioner{Workers: []*AccountWorker{
+		worker("b", &config.AccountConfig{AvailabilityDomain: "AD-1", ImageOCID: "ocid1.image.oc1..b", DisplayName: "arm-b"}),
+		worker("a", &config.AccountConfig{AvailabilityDomain: "auto", ImageOCID: "ocid1.image.oc1..a"}),
+		worker("c", &config.AccountConfig{AvailabilityDomain: "auto", Image: "windows-95"}),
+	}}
+
+	previews := p.PreviewLaunches(context.Background())
+	if len(previews) != 3 || previews[0].Account != "a" || previews[1].Account != "b" || previews[2].Account != "c" {
+		t.Fatalf("expected the accounts sorted by name, got %+v", previews)
+	}
+	if a := previews[0]; a.Err != nil || a.Details.AvailabilityDomain != nil || *a.Details.ShapeConfig.Ocpus != 4 {
+		t.Errorf("expected no AD with auto and the configured size, got %+v", a)
+	}
+	b := previews[1].Details
+	if b.AvailabilityDomain == nil || *b.AvailabilityDomain != "AD-1" || *b.DisplayName != "arm-b" ||
+		*b.SourceDetails.(core.InstanceSourceViaImageDetails).ImageId != "ocid1.image.oc1..b" {
+		t.Errorf("unexpected request for b: %+v", b)
+	}
+	if previews[2].Err == nil {
+		t.Error("expected an error for an unknown image alias")
+	}
+}
+
+func TestBackoffWait(t *testing.T) {
+	r := config.RetryConfig{BaseIntervalMinutes: 15, MaxIntervalMinutes: 120}
+	for misses, want := range map[int]time.Duration{1: 15 * time.Minute, 2: 30 * time.Minute, 4: 120 * time.Minute, 10: 120 * time.Minute} {
+		if got := backoffWait(r, misses); got != want {
+			t.Errorf("%d misses: expected %v, got %v", misses, want, got)
+		}
+	}
+	// A max below the base is the base
+	if got := backoffWait(config.RetryConfig{BaseIntervalMinutes: 15, MaxIntervalMinutes: 5}, 3); got != 15*time.Minute {
+		t.Errorf("expected the base, got %v", got)
+	}
+}
+
+func TestProvisioner_CapacityBackoff(t *testing.T) {
+	cfg := &config.Config{
+		Accounts: map[string]*config.AccountConfig{
+			"main": {Enabled: true, AvailabilityDomain: "AD-1", Shape: "VM.Standard.A1.Flex", OCPUs: 4, MemoryGB: 24},
+		},
+		Retry:     config.RetryConfig{BaseIntervalMinutes: 1, MaxIntervalMinutes: 4, ExponentialBackoff: true},
+		StateFile: filepath.Join(t.TempDir(), "state.json"),
+	}
+	launches := 0
+	start := func() (*Provisioner, *AccountWorker) {
+		p := New(cfg, newMockLogger(), notifier.NewTracker())
+		w := p.Workers[0]
+		w.ComputeClient = &MockComputeClient{
+			LaunchInstanceFunc: func(ctx context.Context, request core.LaunchInstanceRequest) (core.LaunchInstanceResponse, error) {
+				launches++
+				return core.LaunchInstanceResponse{}, newServiceError(500, "Out of host capacity.")
+			},
+		}
+		w.IdentityClient = &MockIdentityClient{}
+		w.VirtualNetworkClient = &MockVirtualNetworkClient{}
+		return p, w
+	}
+	p, w := start()
+
+	// A miss holds the next attempt back for the base interval
+	p.RunCycle(context.Background())
+	if left := w.backingOff(); launches != 1 || left <= 50*time.Second || left > time.Minute {
+		t.Fatalf("expected one launch and a 1m backoff, got %d launches and %v", launches, left)
+	}
+	p.RunCycle(context.Background())
+	if launches != 1 {
+		t.Errorf("expected the account to be skipped while backing off, got %d launches", launches)
+	}
+
+	// A restart keeps waiting
+	p, w = start()
+	p.RunCycle(context.Background())
+	if launches != 1 || w.backingOff() <= 50*time.Second {
+		t.Errorf("expected the backoff to survive a restart, got %d launches and %v", launches, w.backingOff())
+	}
+
+	// Each miss in a row doubles the wait
+	w.State.SetBackoff("main", &state.Backoff{Misses: 1})
+	p.RunCycle(context.Background())
+	b, _ := w.State.Backoff("main")
+	if left := w.backingOff(); launches != 2 || b.Misses != 2 || left <= 110*time.Second {
+		t.Errorf("expected a 2m backoff after the 2nd miss, got %d launches, %d misses and %v", launches, b.Misses, left)
+	}
+
+	// A wait saved under a longer max is cut to the current one
+	w.State.SetBackoff("main", &state.Backoff{Misses: 9, Until: time.Now().Add(time.Hour)})
+	if left := w.backingOff(); left != 4*time.Minute {
+		t.Errorf("expected the wait capped at max_interval_minutes, got %v", left)
+	}
+
+	// Without exponential_backoff, every cycle attempts
+	w.Retry.ExponentialBackoff = false
+	p.RunCycle(context.Background())
+	p.RunCycle(context.Background())
+	if launches != 4 || w.backingOff() != 0 {
+		t.Errorf("expected no backoff when disabled, got %d launches", launches)
+	}
+}
+
+func TestDemo(t *testing.T) {
+	l := logger.NewConsole()
+	l.SetConsoleOutput(io.Discard)
+	var notified []string
+	l.AddHook(func(level, account, msg string) {
+		if account == "NOTIFY" {
+			notified = append(notified, msg)
+		}
+	})
+	p := New(config.Demo(), l, notifier.NewTracker())
+	if len(p.Workers) != 3 {
+		t.Fatalf("expected the 3 demo accounts, got %d", len(p.Workers))
+	}
+	for _, w := range p.Workers {
+		if _, ok := w.ComputeClient.(*demoCloud); !ok {
+			t.Errorf("%s: expected the simulated OCI, got %T", w.AccountName, w.ComputeClient)
+		}
+		if err := w.initClients(); err != nil {
+			t.Errorf("%s: expected no credentials to be needed, got %v", w.AccountName, err)
+		}
+	}
+	if err := p.Notifier.SendSuccess("personal", "ocid1.instance.oc1..demo", "eu-frankfurt-1"); err != nil {
+		t.Fatal(err)
+	}
+	if len(notified) == 0 || !strings.Contains(notified[0], "personal") {
+		t.Errorf("expected the notification in the log, got %q", notified)
+	}
+
+	cloud := newDemoCloud("eu-frankfurt-1")
+	details := core.LaunchInstanceDetails{
+		CompartmentId: common.String("ocid1.compartment.oc1..demo"),
+		DisplayName:   common.String("arm-personal-1"),
+		ShapeConfig:   &core.LaunchInstanceShapeConfigDetails{Ocpus: common.Float32(4), MemoryInGBs: common.Float32(24)},
+	}
+	var launched *core.Instance
+	capacity, throttled := 0, 0
+	for i := 0; i < 1000; i++ {
+		resp, err := cloud.LaunchInstance(context.Background(), core.LaunchInstanceRequest{LaunchInstanceDetails: details})
+		switch errorclass.Of(err) {
+		case errorclass.None:
+			launched = &resp.Instance
+		case errorclass.Capacity:
+			capacity++
+		case errorclass.RateLimit:
+			throttled++
+		default:
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if launched == nil || capacity == 0 || throttled == 0 {
+		t.Fatalf("expected launches, capacity errors and rate limits, got %v, %d, %d", launched != nil, capacity, throttled)
+	}
+
+	get := func() core.InstanceLifecycleStateEnum {
+		resp, err := cloud.GetInstance(context.Background(), core.GetInstanceRequest{InstanceId: launched.Id})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.LifecycleState
+	}
+	if state := get(); state != core.InstanceLifecycleStateProvisioning {
+		t.Errorf("expected a new instance to be PROVISIONING, got %s", state)
+	}
+	cloud.instances[*launched.Id].launched = time.Now().Add(-demoBootTime)
+	if state := get(); state != core.InstanceLifecycleStateRunning {
+		t.Errorf("expected the instance to be RUNNING after booting, got %s", state)
+	}
+	console, _ := cloud.GetConsoleHistoryContent(context.Background(), core.GetConsoleHistoryContentRequest{})
+	if keys := parseHostKeys(*console.Value); len(keys) != 3 {
+		t.Errorf("expected 3 host keys on the console, got %v", keys)
+	}
+}
+
+func TestAccountWorker_ShowRemedy(t *testing.T) {
+	w := &AccountWorker{AccountName: "test", Logger: newMockLogger()}
+	auth := newServiceError(401, "The required information to complete authentication was not provided or was incorrect.")
+
+	w.showRemedy(auth)
+	if w.remedyShown == "" {
+		t.Fatal("expected the fix for a 401 to be logged")
+	}
+	// A capacity retry returns no error, but an unknown error keeps the fix shown
+	w.showRemedy(errors.New("connection reset"))
+	if w.remedyShown == "" {
+		t.Error("expected an unknown error not to reset the shown fix")
+	}
+	w.showRemedy(nil)
+	if w.remedyShown != "" {
+		t.Error("expected a cycle without an error to reset the shown fix")
+	}
+
+	fields := remedyFields(auth)
+	if len(fields) != 1 || !strings.Contains(fields[0].Value, "1. Compare fingerprint") {
+		t.Errorf("unexpected notification fields %+v", fields)
+	}
+	if remedyFields(newServiceError(500, "Out of host capacity")) != nil {
+		t.Error("expected no fix for a capacity error")
+	}
+}
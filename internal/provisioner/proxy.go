@@ -0,0 +1,33 @@
+package provisioner
+
+import (
+	"crypto/tls"
+	"net/http"
+	"time"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+)
+
+// useProxy sends client's requests through the account's proxy_url, if any. The SDK's
+// TLS settings, e.g. a custom CA bundle, still apply.
+func (w *AccountWorker) useProxy(client *common.BaseClient) {
+	proxy := w.Config.Proxy()
+	if proxy == nil {
+		return
+	}
+	proxied := func(tlsConfig *tls.Config) (http.RoundTripper, error) {
+		t := http.DefaultTransport.(*http.Transport).Clone()
+		t.TLSClientConfig = tlsConfig
+		t.Proxy = http.ProxyURL(proxy)
+		return t, nil
+	}
+	if c, ok := client.HTTPClient.(*http.Client); ok {
+		if wrapper, ok := c.Transport.(*common.OciHTTPTransportWrapper); ok {
+			wrapper.TransportTemplate = proxied
+			return
+		}
+	}
+	// Not the SDK's default dispatcher: replace it rather than let requests bypass the proxy
+	transport, _ := proxied(nil)
+	client.HTTPClient = &http.Client{Timeout: time.Minute, Transport: transport}
+}
@@ -0,0 +1,168 @@
+package provisioner
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/core"
+	"github.com/yourusername/oci-arm-provisioner/internal/notifier"
+	"github.com/yourusername/oci-arm-provisioner/internal/state"
+)
+
+// checkReclaimed watches a provisioned account with reclaim.relaunch. Once a recorded
+// instance is gone and fewer than instance_count are left the account hunts again.
+func (p *Provisioner) checkReclaimed(ctx context.Context, w *AccountWorker) {
+	if !w.Config.Reclaim.Relaunch {
+		return
+	}
+	tracked := len(w.State.Instances(w.AccountName))
+	left, err := w.watchInstances(ctx)
+	if err != nil {
+		p.Logger.Warn(w.AccountName, fmt.Sprintf("Instance check failed: %v", err))
+		return
+	}
+	// Instances that were never recorded can't have been reclaimed
+	if left == tracked || left >= w.instanceCount() {
+		return
+	}
+	p.Provisioned[w.AccountName] = false
+	w.Verified = nil
+}
+
+// watchInstances checks the account's recorded instances still exist and reports how
+// many do. Terminated ones are forgotten, keeping their boot volume for the next launch
+// with reuse_boot_volume.
+func (w *AccountWorker) watchInstances(ctx context.Context) (int, error) {
+	instances := w.State.Instances(w.AccountName)
+	if err := w.initClients(); err != nil {
+		return len(instances), err
+	}
+
+	left := 0
+	for _, inst := range instances {
+		resp, err := w.ComputeClient.GetInstance(ctx, core.GetInstanceRequest{InstanceId: common.String(inst.ID)})
+		w.observe(err)
+		if err != nil && !isNotFound(err) {
+			w.Logger.Warn(w.AccountName, fmt.Sprintf("Could not check %s: %v", inst.Name, err))
+			left++
+			continue
+		}
+		// Terminating instances may still take their boot volume with them
+		if err == nil && resp.LifecycleState != core.InstanceLifecycleStateTerminated {
+			left++
+			if w.Config.Reclaim.ReuseBootVolume && inst.BootVolumeID == "" {
+				w.recordBootVolume(ctx, inst, resp.Instance)
+			}
+			continue
+		}
+		w.reclaimed(ctx, inst)
+	}
+	return left, nil
+}
+
+// recordBootVolume looks up and records where inst's boot volume is.
+func (w *AccountWorker) recordBootVolume(ctx context.Context, inst state.Instance, live core.Instance) {
+	id, err := w.bootVolumeOf(ctx, live)
+	if err != nil {
+		w.Logger.Warn(w.AccountName, fmt.Sprintf("Could not look up the boot volume of %s: %v", inst.Name, err))
+		return
+	}
+	if id == "" {
+		return
+	}
+	inst.BootVolumeID, inst.AvailabilityDomain = id, *live.AvailabilityDomain
+	if _, err := w.State.RecordInstance(w.AccountName, inst); err != nil {
+		w.Logger.Warn(w.AccountName, fmt.Sprintf("Failed to persist instance: %v", err))
+	}
+}
+
+// bootVolumeOf returns the ID of the boot volume attached to live, or "" if none is.
+func (w *AccountWorker) bootVolumeOf(ctx context.Context, live core.Instance) (string, error) {
+	if live.AvailabilityDomain == nil {
+		return "", nil
+	}
+	resp, err := w.ComputeClient.ListBootVolumeAttachments(ctx, core.ListBootVolumeAttachmentsRequest{
+		AvailabilityDomain: live.AvailabilityDomain,
+		CompartmentId:      live.CompartmentId,
+		InstanceId:         live.Id,
+	})
+	w.observe(err)
+	if err != nil {
+		return "", err
+	}
+	for _, a := range resp.Items {
+		if a.BootVolumeId != nil && a.LifecycleState == core.BootVolumeAttachmentLifecycleStateAttached {
+			return *a.BootVolumeId, nil
+		}
+	}
+	return "", nil
+}
+
+// reclaimed forgets an instance that is gone and tells the user what happens next.
+func (w *AccountWorker) reclaimed(ctx context.Context, inst state.Instance) {
+	w.forget(inst, "is gone (terminated outside the tool or reclaimed)")
+
+	bootVolume := "Not kept (reclaim.reuse_boot_volume is off)"
+	if w.Config.Reclaim.ReuseBootVolume {
+		bootVolume = "Gone - relaunching from the image"
+		if w.bootVolumeAvailable(ctx, inst.BootVolumeID) {
+			bootVolume = "Survived - relaunching from it with your data"
+			if err := w.State.SetBootVolume(w.AccountName, &state.BootVolume{
+				ID:                 inst.BootVolumeID,
+				AvailabilityDomain: inst.AvailabilityDomain,
+				Instance:           inst.Name,
+			}); err != nil {
+				w.Logger.Warn(w.AccountName, fmt.Sprintf("Failed to persist boot volume: %v", err))
+			}
+		}
+		w.Logger.Info(w.AccountName, "♻️ Boot volume: "+bootVolume)
+	}
+
+	if err := w.Notifier.Send(notifier.Message{
+		Title: "♻️ Instance Gone",
+		Fields: []notifier.Field{
+			{Name: "Account", Value: w.AccountName},
+			{Name: "Instance", Value: fmt.Sprintf("%s · %s", inst.Name, inst.ID)},
+			{Name: "Boot Volume", Value: bootVolume},
+			{Name: "Next", Value: "Hunting for a new instance"},
+		},
+		Color:    notifier.ColorError,
+		Priority: 4,
+		Tags:     "recycle,warning",
+	}); err != nil {
+		w.Logger.Error(w.AccountName, fmt.Sprintf("Notification failed: %v", err))
+	}
+}
+
+// bootVolumeAvailable reports whether the boot volume id exists and can be launched from.
+func (w *AccountWorker) bootVolumeAvailable(ctx context.Context, id string) bool {
+	if id == "" || w.BlockstorageClient == nil {
+		return false
+	}
+	resp, err := w.BlockstorageClient.GetBootVolume(ctx, core.GetBootVolumeRequest{BootVolumeId: common.String(id)})
+	w.observe(err)
+	return err == nil && resp.LifecycleState == core.BootVolumeLifecycleStateAvailable
+}
+
+// reusableBootVolume returns the boot volume recorded for the next launch, if it can
+// still be used. One that is gone is dropped, and the launch uses the image.
+func (w *AccountWorker) reusableBootVolume(ctx context.Context) (state.BootVolume, bool, error) {
+	volume, ok := w.State.BootVolume(w.AccountName)
+	if !ok || !w.Config.ReusesBootVolume() || w.BlockstorageClient == nil {
+		return volume, false, nil
+	}
+	resp, err := w.BlockstorageClient.GetBootVolume(ctx, core.GetBootVolumeRequest{BootVolumeId: common.String(volume.ID)})
+	w.observe(err)
+	if err != nil && !isNotFound(err) {
+		return volume, false, fmt.Errorf("checking the boot volume of %s: %w", volume.Instance, err)
+	}
+	if err == nil && resp.LifecycleState == core.BootVolumeLifecycleStateAvailable {
+		return volume, true, nil
+	}
+	w.Logger.Warn(w.AccountName, fmt.Sprintf("♻️ The boot volume of %s is gone - launching from the image", volume.Instance))
+	if err := w.State.SetBootVolume(w.AccountName, nil); err != nil {
+		w.Logger.Warn(w.AccountName, fmt.Sprintf("Failed to persist boot volume: %v", err))
+	}
+	return volume, false, nil
+}
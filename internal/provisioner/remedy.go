@@ -0,0 +1,39 @@
+package provisioner
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yourusername/oci-arm-provisioner/internal/notifier"
+	"github.com/yourusername/oci-arm-provisioner/internal/remedy"
+)
+
+// showRemedy logs how to fix err if it is a known error. The same problem is explained
+// once, and again only after a cycle without an error.
+func (w *AccountWorker) showRemedy(err error) {
+	r, ok := remedy.For(err)
+	if !ok {
+		if err == nil {
+			w.remedyShown = ""
+		}
+		return
+	}
+	if r.Problem == w.remedyShown {
+		return
+	}
+	w.remedyShown = r.Problem
+	w.Logger.Warn(w.AccountName, "💡 "+r.String())
+}
+
+// remedyFields returns the fix for err as a notification field, if it is a known error.
+func remedyFields(err error) []notifier.Field {
+	r, ok := remedy.For(err)
+	if !ok {
+		return nil
+	}
+	steps := make([]string, len(r.Steps))
+	for i, step := range r.Steps {
+		steps[i] = fmt.Sprintf("%d. %s", i+1, step)
+	}
+	return []notifier.Field{{Name: "How To Fix", Value: r.Problem + ":\n" + strings.Join(steps, "\n")}}
+}
@@ -0,0 +1,52 @@
+package provisioner
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/core"
+	"github.com/yourusername/oci-arm-provisioner/internal/report"
+)
+
+// reportConsoleWait bounds the console capture for the success report.
+const reportConsoleWait = time.Minute
+
+// uploadReport puts the instance as OCI describes it, the verification results and the
+// serial console output in the success_report bucket. What can't be read is left out.
+func (w *AccountWorker) uploadReport(ctx context.Context, verified *VerifiedInstance) {
+	if w.Report == nil {
+		return
+	}
+	r := report.Report{
+		Account:      w.AccountName,
+		InstanceID:   verified.InstanceID,
+		AttemptID:    w.attemptID,
+		Time:         time.Now(),
+		Verification: verified,
+	}
+
+	resp, err := w.ComputeClient.GetInstance(ctx, core.GetInstanceRequest{InstanceId: common.String(verified.InstanceID)})
+	w.observe(err)
+	if err != nil {
+		w.Logger.Warn(w.AccountName, fmt.Sprintf("Success report without instance details: %v", err))
+	} else {
+		r.Instance = resp.Instance
+	}
+
+	consoleCtx, cancel := context.WithTimeout(ctx, reportConsoleWait)
+	console, err := w.consoleHistory(consoleCtx, verified.InstanceID)
+	cancel()
+	if err != nil {
+		w.Logger.Warn(w.AccountName, fmt.Sprintf("Success report without console output: %v", err))
+	}
+	r.Console = console
+
+	prefix, err := w.Report.Upload(ctx, r)
+	if err != nil {
+		w.Logger.Error(w.AccountName, fmt.Sprintf("Success report upload failed: %v", err))
+		return
+	}
+	w.Logger.Info(w.AccountName, fmt.Sprintf("🗄️ Success report uploaded to %s", prefix))
+}
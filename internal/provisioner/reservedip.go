@@ -0,0 +1,145 @@
+package provisioner
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/core"
+	"github.com/yourusername/oci-arm-provisioner/internal/buildinfo"
+)
+
+// reservedIPName is the display name of the reserved public IP created with
+// reserved_public_ip: new, so the account's next instance finds and reuses it.
+func (w *AccountWorker) reservedIPName() string {
+	return buildinfo.Product + "-" + w.AccountName
+}
+
+// reservePublicIP moves the instance's primary private IP from its ephemeral public IP to
+// the account's reserved one and returns the reserved address.
+func (w *AccountWorker) reservePublicIP(ctx context.Context, instanceID string) (string, error) {
+	privateIP, err := w.primaryPrivateIP(ctx, instanceID)
+	if err != nil {
+		return "", err
+	}
+	reserved, err := w.reservedIP(ctx)
+	if err != nil {
+		return "", err
+	}
+	if reserved != nil && reserved.PrivateIpId != nil && *reserved.PrivateIpId != privateIP {
+		return "", fmt.Errorf("reserved public IP %s is still assigned to another instance", safeString(reserved.IpAddress))
+	}
+
+	// A private IP has at most one public IP, so the ephemeral one goes first
+	current, err := w.VirtualNetworkClient.GetPublicIpByPrivateIpId(ctx, core.GetPublicIpByPrivateIpIdRequest{
+		GetPublicIpByPrivateIpIdDetails: core.GetPublicIpByPrivateIpIdDetails{PrivateIpId: common.String(privateIP)},
+	})
+	switch {
+	case isNotFound(err):
+	case err != nil:
+		return "", fmt.Errorf("GetPublicIpByPrivateIpId failed: %w", err)
+	case current.Lifetime == core.PublicIpLifetimeEphemeral:
+		if _, err := w.VirtualNetworkClient.DeletePublicIp(ctx, core.DeletePublicIpRequest{PublicIpId: current.Id}); err != nil {
+			return "", fmt.Errorf("failed to release the ephemeral public IP: %w", err)
+		}
+	case reserved != nil && safeString(current.Id) == safeString(reserved.Id):
+		return safeString(reserved.IpAddress), nil
+	}
+
+	if reserved == nil {
+		resp, err := w.VirtualNetworkClient.CreatePublicIp(ctx, core.CreatePublicIpRequest{CreatePublicIpDetails: core.CreatePublicIpDetails{
+			CompartmentId: common.String(w.Config.CompartmentOCID),
+			Lifetime:      core.CreatePublicIpDetailsLifetimeReserved,
+			DisplayName:   common.String(w.reservedIPName()),
+			PrivateIpId:   common.String(privateIP),
+			FreeformTags:  w.ownerTags(),
+		}})
+		if err != nil {
+			return "", fmt.Errorf("CreatePublicIp failed: %w", err)
+		}
+		return safeString(resp.IpAddress), nil
+	}
+	resp, err := w.VirtualNetworkClient.UpdatePublicIp(ctx, core.UpdatePublicIpRequest{
+		PublicIpId:            reserved.Id,
+		UpdatePublicIpDetails: core.UpdatePublicIpDetails{PrivateIpId: common.String(privateIP)},
+	})
+	if err != nil {
+		return "", fmt.Errorf("UpdatePublicIp failed: %w", err)
+	}
+	return safeString(resp.IpAddress), nil
+}
+
+// reservedIP returns the reserved public IP to use: the configured OCID, or the one
+// created earlier for reserved_public_ip: new (nil if there is none yet).
+func (w *AccountWorker) reservedIP(ctx context.Context) (*core.PublicIp, error) {
+	if w.Config.ReservedPublicIP != "new" {
+		resp, err := w.VirtualNetworkClient.GetPublicIp(ctx, core.GetPublicIpRequest{PublicIpId: common.String(w.Config.ReservedPublicIP)})
+		if err != nil {
+			return nil, fmt.Errorf("reserved public IP %s: %w", w.Config.ReservedPublicIP, err)
+		}
+		if resp.Lifetime != core.PublicIpLifetimeReserved {
+			return nil, fmt.Errorf("public IP %s is not a reserved IP", w.Config.ReservedPublicIP)
+		}
+		return &resp.PublicIp, nil
+	}
+
+	req := core.ListPublicIpsRequest{
+		Scope:         core.ListPublicIpsScopeRegion,
+		Lifetime:      core.ListPublicIpsLifetimeReserved,
+		CompartmentId: common.String(w.Config.CompartmentOCID),
+	}
+	for {
+		resp, err := w.VirtualNetworkClient.ListPublicIps(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("ListPublicIps failed: %w", err)
+		}
+		for _, ip := range resp.Items {
+			if safeString(ip.DisplayName) != w.reservedIPName() {
+				continue
+			}
+			switch ip.LifecycleState {
+			case core.PublicIpLifecycleStateTerminating, core.PublicIpLifecycleStateTerminated:
+				continue
+			}
+			return &ip, nil
+		}
+		if resp.OpcNextPage == nil {
+			return nil, nil
+		}
+		req.Page = resp.OpcNextPage
+	}
+}
+
+// primaryPrivateIP returns the OCID of the primary private IP of the instance's first
+// attached VNIC.
+func (w *AccountWorker) primaryPrivateIP(ctx context.Context, instanceID string) (string, error) {
+	vnics, err := w.ComputeClient.ListVnicAttachments(ctx, core.ListVnicAttachmentsRequest{
+		CompartmentId: common.String(w.Config.CompartmentOCID),
+		InstanceId:    common.String(instanceID),
+	})
+	if err != nil {
+		return "", fmt.Errorf("ListVnicAttachments failed: %w", err)
+	}
+	for _, att := range vnics.Items {
+		if att.VnicId == nil || att.LifecycleState != core.VnicAttachmentLifecycleStateAttached {
+			continue
+		}
+		ips, err := w.VirtualNetworkClient.ListPrivateIps(ctx, core.ListPrivateIpsRequest{VnicId: att.VnicId})
+		if err != nil {
+			return "", fmt.Errorf("ListPrivateIps failed: %w", err)
+		}
+		for _, ip := range ips.Items {
+			if ip.IsPrimary != nil && *ip.IsPrimary {
+				return safeString(ip.Id), nil
+			}
+		}
+	}
+	return "", fmt.Errorf("no primary private IP found")
+}
+
+// isNotFound reports whether OCI answered 404.
+func isNotFound(err error) bool {
+	serviceErr, ok := common.IsServiceError(err)
+	return ok && serviceErr.GetHTTPStatusCode() == http.StatusNotFound
+}
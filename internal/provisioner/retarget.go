@@ -0,0 +1,210 @@
+package provisioner
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/identity"
+	"github.com/yourusername/oci-arm-provisioner/internal/config"
+	"github.com/yourusername/oci-arm-provisioner/internal/notifier"
+)
+
+// Target is a region and availability domain ("auto" for discovery) to launch in.
+type Target struct {
+	Region             string
+	AvailabilityDomain string
+}
+
+// identityClient returns an Identity client for account bound to region. The lookups run
+// outside the provisioning loop, so they use their own client instead of the worker's.
+func (p *Provisioner) identityClient(account, region string) (IdentityClientOps, error) {
+	acc, ok := p.Config.Accounts[account]
+	if !ok {
+		return nil, fmt.Errorf("unknown account '%s'", account)
+	}
+	if p.identityFor != nil {
+		return p.identityFor(acc, region)
+	}
+
+	w := &AccountWorker{AccountName: account, Config: acc, Logger: p.Logger}
+	provider, err := w.getProvider()
+	if err != nil {
+		return nil, err
+	}
+	client, err := identity.NewIdentityClientWithConfigurationProvider(provider)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create identity client: %w", err)
+	}
+	client.SetRegion(region)
+	w.setRealmDomain(&client.BaseClient, region)
+	w.UserAgent = p.userAgent
+	w.tagRequests(&client.BaseClient)
+	w.useProxy(&client.BaseClient)
+	return &client, nil
+}
+
+// Regions lists the regions the account's tenancy is subscribed to, sorted.
+func (p *Provisioner) Regions(ctx context.Context, account string) ([]string, error) {
+	acc, ok := p.Config.Accounts[account]
+	if !ok {
+		return nil, fmt.Errorf("unknown account '%s'", account)
+	}
+	client, err := p.identityClient(account, acc.Region)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	resp, err := client.ListRegionSubscriptions(ctx, identity.ListRegionSubscriptionsRequest{
+		TenancyId: common.String(acc.TenancyOCID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list region subscriptions: %w", err)
+	}
+
+	regions := make([]string, 0, len(resp.Items))
+	for _, sub := range resp.Items {
+		if sub.Status == identity.RegionSubscriptionStatusReady && sub.RegionName != nil {
+			regions = append(regions, *sub.RegionName)
+		}
+	}
+	sort.Strings(regions)
+	return regions, nil
+}
+
+// AvailabilityDomains lists the ADs the account can launch in within region.
+func (p *Provisioner) AvailabilityDomains(ctx context.Context, account, region string) ([]string, error) {
+	client, err := p.identityClient(account, region)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	resp, err := client.ListAvailabilityDomains(ctx, identity.ListAvailabilityDomainsRequest{
+		CompartmentId: common.String(p.Config.Accounts[account].TenancyOCID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ADs in %s: %w", region, err)
+	}
+
+	ads := make([]string, 0, len(resp.Items))
+	for _, ad := range resp.Items {
+		if ad.Name != nil {
+			ads = append(ads, *ad.Name)
+		}
+	}
+	return ads, nil
+}
+
+// Retarget moves an account to a new region/AD. The account's worker is restarted with
+// fresh clients at its next turn in RunCycle, so a cycle in progress is not disturbed.
+func (p *Provisioner) Retarget(account string, target Target) error {
+	if _, ok := p.Config.Accounts[account]; !ok {
+		return fmt.Errorf("unknown account '%s'", account)
+	}
+	if target.Region == "" || target.AvailabilityDomain == "" {
+		return fmt.Errorf("region and availability domain are required")
+	}
+
+	p.retargetMu.Lock()
+	if p.retargets == nil {
+		p.retargets = make(map[string]Target)
+	}
+	p.retargets[account] = target
+	p.retargetMu.Unlock()
+
+	p.Logger.Info(account, fmt.Sprintf("🧭 Switching to %s / %s - worker restarts at its next turn", target.Region, target.AvailabilityDomain))
+	for _, key := range otherRegionOCIDs(p.Config.Accounts[account], target.Region) {
+		p.Logger.Warn(account, fmt.Sprintf("%s is from another region - update it for %s or launches will fail", key, target.Region))
+	}
+	msg := notifier.Message{
+		Title: "🧭 Account Retargeted",
+		Fields: []notifier.Field{
+			{Name: "Account", Value: account},
+			{Name: "Region", Value: target.Region},
+			{Name: "Availability Domain", Value: target.AvailabilityDomain},
+		},
+		Color: notifier.ColorInfo,
+		Tags:  "compass",
+	}
+	if err := p.Notifier.Send(msg); err != nil {
+		p.Logger.Error(account, fmt.Sprintf("Notification failed: %v", err))
+	}
+	return nil
+}
+
+// otherRegionOCIDs returns the config keys holding regional OCIDs (image, subnet) that
+// don't belong to region.
+func otherRegionOCIDs(acc *config.AccountConfig, region string) []string {
+	var keys []string
+	for key, ocid := range map[string]string{"image_ocid": acc.ImageOCID, "subnet_ocid": acc.SubnetOCID} {
+		// ocid1.<type>.<realm>.<region>.<unique>; older OCIDs use the short code, e.g. "iad"
+		parts := strings.Split(ocid, ".")
+		if len(parts) < 5 || parts[3] == "" {
+			continue
+		}
+		if string(common.StringToRegion(parts[3])) != region {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// applyRetarget replaces the worker at index i if a retarget is pending for it.
+func (p *Provisioner) applyRetarget(i int) *AccountWorker {
+	old := p.Workers[i]
+
+	p.retargetMu.Lock()
+	target, ok := p.retargets[old.AccountName]
+	delete(p.retargets, old.AccountName)
+	p.retargetMu.Unlock()
+	if !ok {
+		return old
+	}
+
+	cfg := *old.Config
+	cfg.Region, cfg.AvailabilityDomain = target.Region, target.AvailabilityDomain
+	p.Workers[i] = &AccountWorker{
+		AccountName:          old.AccountName,
+		Config:               &cfg,
+		Logger:               old.Logger,
+		Notifier:             old.Notifier,
+		Tracker:              old.Tracker,
+		Breaker:              old.Breaker,
+		Pacer:                old.Pacer,
+		State:                old.State,
+		IPHook:               old.IPHook,
+		Report:               old.Report,
+		Events:               old.Events,
+		PollInterval:         old.PollInterval,
+		LaunchTimeout:        old.LaunchTimeout,
+		VerifyTimeout:        old.VerifyTimeout,
+		ThrottleRatio:        old.ThrottleRatio,
+		Retry:                old.Retry,
+		UserAgent:            old.UserAgent,
+		AnnouncementInterval: old.AnnouncementInterval,
+		Milestones:           old.Milestones,
+		nextAttempts:         old.nextAttempts,
+		nextDays:             old.nextDays,
+	}
+	if p.Config.Demo {
+		p.Workers[i].useDemo()
+	}
+	p.Logger.Info(old.AccountName, fmt.Sprintf("🔄 Worker restarted in %s / %s", cfg.Region, cfg.AvailabilityDomain))
+	return p.Workers[i]
+}
+
+// Fields returns the account config fields that persist the target.
+func (t Target) Fields() []config.AccountField {
+	return []config.AccountField{
+		{Key: "region", Value: t.Region},
+		{Key: "availability_domain", Value: t.AvailabilityDomain},
+	}
+}
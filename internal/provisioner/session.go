@@ -0,0 +1,108 @@
+package provisioner
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/yourusername/oci-arm-provisioner/internal/config"
+)
+
+// ociCommand is the OCI CLI, which refreshes session tokens. Tests override it.
+var ociCommand = "oci"
+
+// sessionRefreshMargin is how long before it expires a session token is refreshed.
+// Tokens last an hour; OCI refreshes them for up to 24 hours after signing in.
+const sessionRefreshMargin = 15 * time.Minute
+
+// sessionProvider signs with an OCI CLI session. The SDK reads the token file on every
+// request, so a refreshed token is used right away. Region can differ from the profile's.
+type sessionProvider struct {
+	common.ConfigurationProvider
+	region string
+}
+
+func (p sessionProvider) Region() (string, error) { return p.region, nil }
+
+// Refreshable tells the SDK the token can change between requests.
+func (p sessionProvider) Refreshable() bool { return true }
+
+// newSessionProvider returns the provider for an auth: security_token account.
+func (w *AccountWorker) newSessionProvider() (common.ConfigurationProvider, error) {
+	provider, err := common.ConfigurationProviderForSessionTokenWithProfile(w.Config.OCIConfigFile, w.Config.OCIProfile, "")
+	if err != nil {
+		return nil, err
+	}
+	return sessionProvider{ConfigurationProvider: provider, region: w.Config.Region}, nil
+}
+
+// sessionExpiry returns when the session token (a JWT) in path expires.
+func sessionExpiry(path string) (time.Time, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	parts := strings.Split(strings.TrimSpace(string(content)), ".")
+	if len(parts) != 3 {
+		return time.Time{}, errors.New("not a session token")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("not a session token: %w", err)
+	}
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == 0 {
+		return time.Time{}, errors.New("session token has no expiry")
+	}
+	return time.Unix(claims.Exp, 0), nil
+}
+
+// refreshSession keeps an auth: security_token session alive: shortly before the token
+// expires it runs "oci session refresh". A failed refresh is only an error once the
+// token has expired; then the user has to sign in again.
+func (w *AccountWorker) refreshSession(ctx context.Context) error {
+	if w.Config.Auth != config.AuthSecurityToken {
+		return nil
+	}
+	signIn := fmt.Sprintf("sign in again with: oci session authenticate --profile-name %s", w.Config.OCIProfile)
+	path, err := w.Config.SessionTokenFile()
+	if err != nil {
+		return credentialsError{fmt.Errorf("session token: %w (%s)", err, signIn)}
+	}
+	expiry, err := sessionExpiry(path)
+	if err != nil {
+		return credentialsError{fmt.Errorf("session token %s: %w (%s)", path, err, signIn)}
+	}
+	if time.Until(expiry) > sessionRefreshMargin {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, ociCommand, "session", "refresh",
+		"--profile", w.Config.OCIProfile, "--config-file", w.Config.OCIConfigFile).CombinedOutput()
+	if err == nil {
+		expiry, err = sessionExpiry(path)
+	}
+	if err != nil {
+		if tail := strings.TrimSpace(string(out)); tail != "" {
+			err = fmt.Errorf("%w: %s", err, tail[strings.LastIndex(tail, "\n")+1:])
+		}
+		if time.Now().Before(expiry) {
+			w.Logger.Warn(w.AccountName, fmt.Sprintf("Session token refresh failed, it expires at %s: %v", expiry.Format("15:04"), err))
+			return nil
+		}
+		return credentialsError{fmt.Errorf("session expired and could not be refreshed (%v); %s", err, signIn)}
+	}
+	w.Logger.Info(w.AccountName, fmt.Sprintf("🔄 Session token refreshed, valid until %s", expiry.Format("15:04")))
+	return nil
+}
@@ -0,0 +1,44 @@
+package provisioner
+
+import (
+	"fmt"
+
+	"github.com/yourusername/oci-arm-provisioner/internal/config"
+)
+
+// shape returns the shape the next launch uses: the account's shape, or the fallback
+// or slice the worker has moved on to.
+func (w *AccountWorker) shape() config.ShapeOption {
+	if w.Config.SplitOnCapacity {
+		slices := w.Config.Slices()
+		return slices[min(w.splitLevel, len(slices)-1)]
+	}
+	shapes := w.Config.Shapes()
+	return shapes[w.shapeIndex%len(shapes)]
+}
+
+// capacityMiss counts a capacity error on the current shape, and moves on to the next
+// shape of the chain, or the next smaller slice, after fallback_after of them in a row.
+// It also backs the account off (see backOff).
+func (w *AccountWorker) capacityMiss() {
+	w.backOff()
+	if w.Config.SplitOnCapacity {
+		w.splitMiss()
+		return
+	}
+	shapes := w.Config.Shapes()
+	if len(shapes) < 2 {
+		return
+	}
+	after := w.Config.FallbackAfter
+	if after <= 0 {
+		after = config.DefaultFallbackAfter
+	}
+	if w.shapeMisses++; w.shapeMisses < after {
+		return
+	}
+	from := shapes[w.shapeIndex%len(shapes)]
+	w.shapeIndex = (w.shapeIndex + 1) % len(shapes)
+	w.shapeMisses = 0
+	w.Logger.Warn(w.AccountName, fmt.Sprintf("🔀 %d capacity errors in a row on %s - trying %s", after, from.Shape, shapes[w.shapeIndex]))
+}
@@ -0,0 +1,227 @@
+package provisioner
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/yourusername/oci-arm-provisioner/internal/config"
+)
+
+// Window is a stretch of time, as offsets from the start of a Capacity pattern, during
+// which a launch would find free capacity.
+type Window struct {
+	Start, End time.Duration
+}
+
+// Capacity is a repeating pattern of capacity windows.
+type Capacity struct {
+	Windows []Window      // Sorted by Start.
+	Period  time.Duration // The pattern repeats after Period.
+}
+
+// Available reports whether a launch at offset at would succeed.
+func (c Capacity) Available(at time.Duration) bool {
+	if c.Period > 0 {
+		at %= c.Period
+	}
+	i := sort.Search(len(c.Windows), func(i int) bool { return c.Windows[i].End > at })
+	return i < len(c.Windows) && c.Windows[i].Start <= at
+}
+
+// Observation is one launch attempt read back from the log.
+type Observation struct {
+	At      time.Time
+	Account string
+	Success bool // Otherwise the launch hit a capacity error.
+}
+
+// logAttempt matches the log file lines written for a launch outcome.
+var logAttempt = regexp.MustCompile(`^(\d{4}/\d{2}/\d{2} \d{2}:\d{2}:\d{2}) \[([^\]]+)\] \[(WARN|SUCCESS)\] (Capacity/Limit error|Instance Launched)`)
+
+// ReadHistory reads the launch attempts recorded in a provisioner.log.
+func ReadHistory(r io.Reader) ([]Observation, error) {
+	var obs []Observation
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		m := logAttempt.FindStringSubmatch(scanner.Text())
+		if m == nil {
+			continue
+		}
+		at, err := time.ParseInLocation("2006/01/02 15:04:05", m[1], time.Local)
+		if err != nil {
+			continue
+		}
+		obs = append(obs, Observation{At: at, Account: m[2], Success: m[3] == "SUCCESS"})
+	}
+	sort.SliceStable(obs, func(i, j int) bool { return obs[i].At.Before(obs[j].At) })
+	return obs, scanner.Err()
+}
+
+// CapacityFromHistory turns recorded attempts into a capacity pattern: every success
+// opens a window of the given length, and the pattern repeats over the recorded span.
+func CapacityFromHistory(obs []Observation, window time.Duration) (Capacity, error) {
+	if len(obs) == 0 {
+		return Capacity{}, errors.New("no launch attempts in the history")
+	}
+	first := obs[0].At
+	c := Capacity{Period: obs[len(obs)-1].At.Sub(first) + window}
+	for _, o := range obs {
+		if o.Success {
+			start := o.At.Sub(first)
+			c.Windows = append(c.Windows, Window{Start: start, End: start + window})
+		}
+	}
+	if len(c.Windows) == 0 {
+		return Capacity{}, fmt.Errorf("none of the %d recorded attempts succeeded, so there is no capacity to replay; use a synthetic pattern", len(obs))
+	}
+	return c, nil
+}
+
+// SyntheticCapacity returns windows of the given length that open at random, perDay
+// times a day on average, over span.
+func SyntheticCapacity(perDay float64, window, span time.Duration, rng *rand.Rand) Capacity {
+	c := Capacity{Period: span}
+	mean := float64(24*time.Hour) / perDay
+	for at := time.Duration(rng.ExpFloat64() * mean); at < span; at += time.Duration(rng.ExpFloat64() * mean) {
+		c.Windows = append(c.Windows, Window{Start: at, End: at + window})
+	}
+	return c
+}
+
+// SimOptions describes a simulated run of the scheduler.
+type SimOptions struct {
+	Scheduler config.SchedulerConfig
+	Tenancies map[string]string // Account -> tenancy OCID of the accounts hunting, as for NewPacer.
+	Capacity  Capacity          // Shared by every account.
+
+	AttemptTime time.Duration // How long one attempt takes (API calls).
+	RateLimit   int           // Attempts per minute a tenancy gets before a 429; 0 = unlimited.
+	Horizon     time.Duration // Give up after this long.
+	Runs        int           // Each run starts at a random point of the pattern.
+	Seed        int64
+}
+
+// SimResult summarizes the time-to-provision over all runs and accounts.
+type SimResult struct {
+	Hunts       int           // Account runs simulated.
+	Provisioned int           // Of which got an instance within the horizon.
+	Median, P90 time.Duration // Time-to-provision of those that did.
+	PerDay      float64       // Launch attempts per day per tenancy while hunting.
+	RateLimited int           // Attempts answered with a 429.
+}
+
+// simRateLimited is the 429 a simulated attempt gets over SimOptions.RateLimit.
+type simRateLimited struct{}
+
+func (simRateLimited) Error() string           { return "simulated 429 TooManyRequests" }
+func (simRateLimited) GetHTTPStatusCode() int  { return 429 }
+func (simRateLimited) GetMessage() string      { return "TooManyRequests" }
+func (simRateLimited) GetCode() string         { return "TooManyRequests" }
+func (simRateLimited) GetOpcRequestID() string { return "" }
+
+// Simulate replays the capacity pattern through the same cycle order, account delay,
+// cycle interval and tenancy Pacer as RunCycle, on a virtual clock.
+func Simulate(opts SimOptions) SimResult {
+	rng := rand.New(rand.NewSource(opts.Seed))
+	accounts := make([]string, 0, len(opts.Tenancies))
+	for name := range opts.Tenancies {
+		accounts = append(accounts, name)
+	}
+	sort.Strings(accounts)
+
+	var res SimResult
+	var took []time.Duration
+	var attempts int
+	var hunted time.Duration
+	for run := 0; run < opts.Runs; run++ {
+		var phase time.Duration
+		if opts.Capacity.Period > 0 {
+			phase = time.Duration(rng.Int63n(int64(opts.Capacity.Period)))
+		}
+		got, n, elapsed, limited := simulateRun(opts, accounts, phase)
+		res.Hunts += len(accounts)
+		res.Provisioned += len(got)
+		res.RateLimited += limited
+		took = append(took, got...)
+		attempts += n
+		hunted += elapsed
+	}
+
+	if hunted > 0 {
+		tenancies := make(map[string]bool)
+		for _, t := range opts.Tenancies {
+			tenancies[t] = true
+		}
+		res.PerDay = float64(attempts) / float64(len(tenancies)) / hunted.Hours() * 24
+	}
+	if len(took) > 0 {
+		sort.Slice(took, func(i, j int) bool { return took[i] < took[j] })
+		res.Median = took[(len(took)-1)/2]
+		res.P90 = took[(len(took)-1)*9/10]
+	}
+	return res
+}
+
+// simulateRun hunts every account once, starting at phase in the capacity pattern. It
+// returns the time-to-provision of the accounts that succeeded, the attempts made, how
+// long the hunt lasted and how many attempts were rate limited.
+func simulateRun(opts SimOptions, accounts []string, phase time.Duration) ([]time.Duration, int, time.Duration, int) {
+	var clock time.Duration
+	pacer := NewPacer(opts.Tenancies, time.Duration(opts.Scheduler.TenancyIntervalSeconds)*time.Second)
+	epoch := time.Unix(0, 0)
+	pacer.now = func() time.Time { return epoch.Add(clock) }
+	pacer.sleep = func(ctx context.Context, d time.Duration) error {
+		clock += d
+		return nil
+	}
+
+	accountDelay := time.Duration(opts.Scheduler.AccountDelaySeconds) * time.Second
+	cycleInterval := time.Duration(opts.Scheduler.CycleIntervalSeconds) * time.Second
+	recent := make(map[string][]time.Duration) // Attempts in the last minute, per tenancy.
+	done := make(map[string]bool)
+	var took []time.Duration
+	var attempts, limited int
+
+	for clock < opts.Horizon && len(done) < len(accounts) {
+		for i, name := range accounts {
+			if done[name] {
+				continue
+			}
+			tenancy := opts.Tenancies[name]
+			pacer.Wait(context.Background(), tenancy)
+
+			window := recent[tenancy]
+			for len(window) > 0 && clock-window[0] >= time.Minute {
+				window = window[1:]
+			}
+			attempts++
+			var err error
+			switch {
+			case opts.RateLimit > 0 && len(window) >= opts.RateLimit:
+				err = simRateLimited{}
+				limited++
+			case opts.Capacity.Available(phase + clock):
+				done[name] = true
+				took = append(took, clock)
+			}
+			recent[tenancy] = append(window, clock)
+			clock += opts.AttemptTime
+			pacer.Observe(tenancy, err)
+			pacer.Done(tenancy)
+
+			if i < len(accounts)-1 {
+				clock += accountDelay
+			}
+		}
+		clock += cycleInterval
+	}
+	return took, attempts, min(clock, opts.Horizon), limited
+}
@@ -0,0 +1,161 @@
+package provisioner
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/core"
+	"github.com/oracle/oci-go-sdk/v65/identity"
+	"github.com/yourusername/oci-arm-provisioner/internal/config"
+	"github.com/yourusername/oci-arm-provisioner/internal/notifier"
+	"github.com/yourusername/oci-arm-provisioner/internal/state"
+)
+
+// mergeCheckInterval is how often a slice's account asks the capacity report whether
+// the full size would fit.
+const mergeCheckInterval = 10 * time.Minute
+
+// splitMiss counts a capacity error with split_on_capacity, and moves on to the next
+// smaller slice after fallback_after of them in a row. The smallest slice is kept.
+func (w *AccountWorker) splitMiss() {
+	slices := w.Config.Slices()
+	if w.splitLevel >= len(slices)-1 {
+		return
+	}
+	if w.shapeMisses++; w.shapeMisses < w.Config.FallbackAfter {
+		return
+	}
+	from := slices[w.splitLevel]
+	w.splitLevel++
+	w.shapeMisses = 0
+	w.Logger.Warn(w.AccountName, fmt.Sprintf("🪓 %d capacity errors in a row on %s - trying %s", w.Config.FallbackAfter, from, slices[w.splitLevel]))
+}
+
+// launchedSlice reports whether the launch in progress is smaller than the full size,
+// and starts the next hunt at the full size again.
+func (w *AccountWorker) launchedSlice() bool {
+	slice := w.Config.SplitOnCapacity && w.splitLevel > 0
+	w.splitLevel, w.shapeMisses = 0, 0
+	return slice
+}
+
+// slice returns the account's instance that was launched as a slice, if any.
+func (w *AccountWorker) slice() (state.Instance, bool) {
+	for _, inst := range w.State.Instances(w.AccountName) {
+		if inst.Slice {
+			return inst, true
+		}
+	}
+	return state.Instance{}, false
+}
+
+// checkMergeUp replaces a provisioned account's slice with the full size once the
+// capacity report shows room for it in one of the account's ADs: the slice is
+// terminated and the account hunts again right away, in that AD. It reports whether it
+// did, so RunCycle launches in the same cycle.
+func (p *Provisioner) checkMergeUp(ctx context.Context, w *AccountWorker) bool {
+	if !w.Config.SplitOnCapacity || time.Since(w.lastMergeCheck) < mergeCheckInterval {
+		return false
+	}
+	inst, ok := w.slice()
+	if !ok {
+		return false
+	}
+	if err := w.initClients(); err != nil {
+		return false
+	}
+	w.lastMergeCheck = time.Now()
+
+	full := w.Config.Slices()[0]
+	ad, err := w.capacityFor(ctx, full)
+	if err != nil {
+		p.Logger.Warn(w.AccountName, fmt.Sprintf("Capacity report failed: %v", err))
+		return false
+	}
+	if ad == "" {
+		return false
+	}
+
+	p.Logger.Success(w.AccountName, fmt.Sprintf("⬆️ Capacity for %s in %s - replacing %s", full, ad, inst.Name))
+	_, err = w.ComputeClient.TerminateInstance(ctx, core.TerminateInstanceRequest{
+		InstanceId:         common.String(inst.ID),
+		PreserveBootVolume: common.Bool(false),
+	})
+	w.observe(err)
+	if err != nil {
+		p.Logger.Error(w.AccountName, fmt.Sprintf("Could not terminate %s to merge up: %v", inst.Name, err))
+		return false
+	}
+	w.forget(inst, "was terminated to make room for the full size")
+	if err := w.Notifier.Send(notifier.Message{
+		Title: "⬆️ Merging Up",
+		Fields: []notifier.Field{
+			{Name: "Account", Value: w.AccountName},
+			{Name: "Terminated", Value: fmt.Sprintf("%s · %s", inst.Name, inst.ID)},
+			{Name: "Launching", Value: fmt.Sprintf("%s in %s", full, ad)},
+			{Name: "Note", Value: "If the capacity is gone by then, the account splits again after fallback_after capacity errors."},
+		},
+		Color: notifier.ColorInfo,
+		Tags:  "arrow_up",
+	}); err != nil {
+		p.Logger.Error(w.AccountName, fmt.Sprintf("Notification failed: %v", err))
+	}
+
+	p.Provisioned[w.AccountName] = false
+	w.Verified = nil
+	w.splitLevel, w.shapeMisses = 0, 0
+	w.mergeAD = ad
+	return true
+}
+
+// capacityFor returns the first of the account's ADs where the capacity report has room
+// for opt, or "" if none has.
+func (w *AccountWorker) capacityFor(ctx context.Context, opt config.ShapeOption) (string, error) {
+	ads := []string{w.Config.AvailabilityDomain}
+	if w.Config.AvailabilityDomain == "auto" {
+		resp, err := w.IdentityClient.ListAvailabilityDomains(ctx, identity.ListAvailabilityDomainsRequest{
+			CompartmentId: common.String(w.Config.TenancyOCID),
+		})
+		w.observe(err)
+		if err != nil {
+			return "", fmt.Errorf("failed to list ADs: %w", err)
+		}
+		ads = ads[:0]
+		for _, ad := range resp.Items {
+			ads = append(ads, safeString(ad.Name))
+		}
+	}
+	return w.capacityIn(ctx, opt, ads)
+}
+
+// capacityIn returns the first of ads where the capacity report has room for opt, or
+// "" if none has.
+func (w *AccountWorker) capacityIn(ctx context.Context, opt config.ShapeOption, ads []string) (string, error) {
+	for _, ad := range ads {
+		resp, err := w.ComputeClient.CreateComputeCapacityReport(ctx, core.CreateComputeCapacityReportRequest{
+			CreateComputeCapacityReportDetails: core.CreateComputeCapacityReportDetails{
+				CompartmentId:      common.String(w.Config.TenancyOCID),
+				AvailabilityDomain: common.String(ad),
+				ShapeAvailabilities: []core.CreateCapacityReportShapeAvailabilityDetails{{
+					InstanceShape: common.String(opt.Shape),
+					InstanceShapeConfig: &core.CapacityReportInstanceShapeConfig{
+						Ocpus:       common.Float32(opt.OCPUs),
+						MemoryInGBs: common.Float32(opt.MemoryGB),
+					},
+				}},
+			},
+		})
+		w.observe(err)
+		if err != nil {
+			return "", err
+		}
+		for _, a := range resp.ShapeAvailabilities {
+			if a.AvailabilityStatus == core.CapacityReportShapeAvailabilityAvailabilityStatusAvailable {
+				return ad, nil
+			}
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,45 @@
+package provisioner
+
+import (
+	"fmt"
+
+	"github.com/oracle/oci-go-sdk/v65/objectstorage"
+	"github.com/yourusername/oci-arm-provisioner/internal/buildinfo"
+	"github.com/yourusername/oci-arm-provisioner/internal/state"
+)
+
+// stateBackend returns where state is kept: the configured remote store, the state file,
+// or nil (memory only).
+func (p *Provisioner) stateBackend() (state.Backend, string, error) {
+	b := p.Config.StateBackend
+	switch b.Type {
+	case "redis":
+		backend, err := state.NewRedisBackend(b.RedisURL, b.Key)
+		if err != nil {
+			return nil, "", err
+		}
+		return backend, fmt.Sprintf("redis %s key %s", backend.Addr, b.Key), nil
+
+	case "object_storage":
+		acc := p.Config.Accounts[b.Account]
+		w := &AccountWorker{AccountName: b.Account, Config: acc, Logger: p.Logger, UserAgent: buildinfo.UserAgent("", p.Config.UserAgent)} // install ID lives in the state
+		provider, err := w.getProvider()
+		if err != nil {
+			return nil, "", err
+		}
+		client, err := objectstorage.NewObjectStorageClientWithConfigurationProvider(provider)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to create object storage client: %w", err)
+		}
+		client.SetRegion(acc.Region)
+		w.tagRequests(&client.BaseClient)
+		w.useProxy(&client.BaseClient)
+		backend := &state.ObjectStorageBackend{Client: &client, Namespace: b.Namespace, Bucket: b.Bucket, Object: b.Key}
+		return backend, fmt.Sprintf("bucket %s object %s", b.Bucket, b.Key), nil
+	}
+
+	if p.Config.StateFile == "" {
+		return nil, "memory only", nil
+	}
+	return state.FileBackend{Path: p.Config.StateFile}, p.Config.StateFile, nil
+}
@@ -0,0 +1,54 @@
+package provisioner
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/yourusername/oci-arm-provisioner/internal/buildinfo"
+	"github.com/yourusername/oci-arm-provisioner/internal/notifier"
+)
+
+// ReportExit logs a human-readable summary of the run and, if enabled, sends it as a
+// final notification so a restart does not lose what happened.
+func (p *Provisioner) ReportExit() {
+	stats := p.Tracker.Snapshot()
+	uptime := time.Since(stats.StartTime).Round(time.Second)
+
+	p.Logger.Section("📋 Run Summary")
+	p.Logger.Plain(fmt.Sprintf("🏷️  Version: %s", buildinfo.String()))
+	p.Logger.Plain(fmt.Sprintf("🕒 Uptime: %s | 🔄 Cycles: %d | ⚠️  Capacity Hits: %d | ❌ Errors: %d | ✅ Provisioned: %d",
+		uptime, stats.TotalCycles, stats.CapacityErrors, stats.OtherErrors, stats.SuccessCount))
+	for _, name := range stats.AccountNames() {
+		lines := strings.Split(stats.Accounts[name].Summary(), "\n")
+		p.Logger.Plain(fmt.Sprintf("  • %s: %s", name, lines[0]))
+		for _, line := range lines[1:] {
+			p.Logger.Plain("      " + line)
+		}
+	}
+
+	if p.Config.Notifications.Enabled && p.Config.Notifications.ExitSummary {
+		if err := p.Notifier.SendExitSummary(stats); err != nil {
+			p.Logger.Error("NOTIFIER", fmt.Sprintf("Failed to send exit summary: %v", err))
+		}
+	}
+}
+
+// CostReport collects the month-to-date spend of every account with cost_report enabled.
+// Failures are logged and the account is left out of the report.
+func (p *Provisioner) CostReport(ctx context.Context) map[string]notifier.Cost {
+	costs := make(map[string]notifier.Cost)
+	for _, w := range p.Workers {
+		if !w.Config.CostReport {
+			continue
+		}
+		cost, err := w.MonthToDateCost(ctx)
+		if err != nil {
+			p.Logger.Warn(w.AccountName, fmt.Sprintf("Cost report failed: %v", err))
+			continue
+		}
+		costs[w.AccountName] = cost
+	}
+	return costs
+}
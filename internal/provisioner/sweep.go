@@ -0,0 +1,94 @@
+package provisioner
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/identity"
+)
+
+// launchTarget is where one launch request of an attempt goes. An empty fault domain
+// lets OCI pick one.
+type launchTarget struct {
+	ad, faultDomain string
+}
+
+func (t launchTarget) String() string {
+	if t.faultDomain == "" {
+		return t.ad
+	}
+	return t.ad + "/" + t.faultDomain
+}
+
+// launchTargets returns where this attempt launches: each of ads, in the fault domain the
+// capacity report saw room in, if any. With sweep, every fault domain of each AD is a
+// target of its own, the reported one first, up to sweep.max_requests.
+func (w *AccountWorker) launchTargets(ctx context.Context, ads []string, faultDomains map[string]string) []launchTarget {
+	var targets []launchTarget
+	for _, ad := range ads {
+		reported := faultDomains[ad]
+		if !w.Config.Sweep.Enabled {
+			targets = append(targets, launchTarget{ad, reported})
+			continue
+		}
+		fds, err := w.faultDomains(ctx, ad)
+		if err != nil || len(fds) == 0 {
+			if err != nil {
+				w.Logger.Warn(w.AccountName, fmt.Sprintf("Could not list the fault domains of %s, sweeping the AD as a whole: %v", ad, err))
+			}
+			targets = append(targets, launchTarget{ad, reported})
+			continue
+		}
+		if reported != "" {
+			targets = append(targets, launchTarget{ad, reported})
+		}
+		for _, fd := range fds {
+			if fd != reported {
+				targets = append(targets, launchTarget{ad, fd})
+			}
+		}
+	}
+	if !w.Config.Sweep.Enabled {
+		return targets
+	}
+
+	if budget := w.Config.Sweep.MaxRequests; budget > 0 && len(targets) > budget {
+		w.Logger.Info(w.AccountName, fmt.Sprintf("🧹 Sweep limited to %d of %d AD and fault domain combinations", budget, len(targets)))
+		targets = targets[:budget]
+	}
+	names := make([]string, len(targets))
+	for i, t := range targets {
+		names[i] = t.String()
+	}
+	w.Logger.Info(w.AccountName, fmt.Sprintf("🧹 Sweeping %s", strings.Join(names, ", ")))
+	return targets
+}
+
+// faultDomains lists the fault domains of ad, cached for the worker's lifetime since
+// they don't change.
+func (w *AccountWorker) faultDomains(ctx context.Context, ad string) ([]string, error) {
+	if fds, ok := w.fds[ad]; ok {
+		return fds, nil
+	}
+	resp, err := w.IdentityClient.ListFaultDomains(ctx, identity.ListFaultDomainsRequest{
+		CompartmentId:      common.String(w.Config.TenancyOCID),
+		AvailabilityDomain: common.String(ad),
+	})
+	w.observe(err)
+	if err != nil {
+		return nil, err
+	}
+	var fds []string
+	for _, fd := range resp.Items {
+		if fd.Name != nil {
+			fds = append(fds, *fd.Name)
+		}
+	}
+	if w.fds == nil {
+		w.fds = make(map[string][]string)
+	}
+	w.fds[ad] = fds
+	return fds, nil
+}
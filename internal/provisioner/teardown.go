@@ -0,0 +1,165 @@
+package provisioner
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/core"
+	"github.com/yourusername/oci-arm-provisioner/internal/notifier"
+	"github.com/yourusername/oci-arm-provisioner/internal/state"
+)
+
+// teardown terminates the account's recorded instances that reached their teardown time,
+// after a warning notification warn_hours before. It reports how many instances are
+// left, and does nothing unless teardown is configured.
+func (w *AccountWorker) teardown(ctx context.Context, now time.Time) (int, error) {
+	instances := w.State.Instances(w.AccountName)
+	if !w.Config.Teardown.Enabled() {
+		return len(instances), nil
+	}
+	if err := w.initClients(); err != nil {
+		return len(instances), err
+	}
+
+	left := 0
+	for _, inst := range instances {
+		resp, err := w.ComputeClient.GetInstance(ctx, core.GetInstanceRequest{InstanceId: common.String(inst.ID)})
+		w.observe(err)
+		if isNotFound(err) {
+			w.forget(inst, "no longer exists")
+			continue
+		}
+		if err != nil {
+			w.Logger.Warn(w.AccountName, fmt.Sprintf("Could not check the teardown time of %s: %v", inst.Name, err))
+			left++
+			continue
+		}
+		switch resp.LifecycleState {
+		case core.InstanceLifecycleStateTerminated, core.InstanceLifecycleStateTerminating:
+			w.forget(inst, "was terminated outside the tool")
+			continue
+		}
+		if resp.TimeCreated == nil {
+			left++
+			continue
+		}
+
+		due, _ := w.Config.Teardown.Due(resp.TimeCreated.Time)
+		if now.Before(due) {
+			if !now.Before(due.Add(-w.Config.Teardown.Warning())) {
+				w.warnTeardown(inst, due)
+			}
+			left++
+			continue
+		}
+		if err := w.terminate(ctx, inst); err != nil {
+			w.Logger.Error(w.AccountName, fmt.Sprintf("Teardown of %s failed: %v", inst.Name, err))
+			left++
+		}
+	}
+	return left, nil
+}
+
+// warnTeardown sends the notice that inst is about to be terminated, once per instance.
+func (w *AccountWorker) warnTeardown(inst state.Instance, due time.Time) {
+	if w.teardownWarned[inst.ID] {
+		return
+	}
+	if w.teardownWarned == nil {
+		w.teardownWarned = make(map[string]bool)
+	}
+	w.teardownWarned[inst.ID] = true
+
+	w.Logger.Warn(w.AccountName, fmt.Sprintf("⏳ %s will be terminated at %s", inst.Name, due.Local().Format("2006-01-02 15:04")))
+	if err := w.Notifier.Send(notifier.Message{
+		Title: "⏳ Teardown Scheduled",
+		Fields: []notifier.Field{
+			{Name: "Account", Value: w.AccountName},
+			{Name: "Instance", Value: fmt.Sprintf("%s · %s · %s", inst.Name, orNoIP(inst.PublicIP), inst.ID)},
+			{Name: "Terminates At", Value: due.Local().Format("2006-01-02 15:04 MST")},
+			{Name: "Hint", Value: "Back up anything you want to keep. To keep the instance, remove teardown from the account's config and reload."},
+		},
+		Color:    notifier.ColorInfo,
+		Priority: 4,
+		Tags:     "hourglass,warning",
+	}); err != nil {
+		w.Logger.Error(w.AccountName, fmt.Sprintf("Notification failed: %v", err))
+	}
+}
+
+// terminate terminates inst, forgets it and confirms the teardown.
+func (w *AccountWorker) terminate(ctx context.Context, inst state.Instance) error {
+	keep := w.Config.Teardown.KeepBootVolume
+	_, err := w.ComputeClient.TerminateInstance(ctx, core.TerminateInstanceRequest{
+		InstanceId:         common.String(inst.ID),
+		PreserveBootVolume: common.Bool(keep),
+	})
+	w.observe(err)
+	if err != nil {
+		return err
+	}
+	w.forget(inst, "was torn down")
+
+	bootVolume := "Deleted"
+	if keep {
+		bootVolume = "Kept (still counts against the free tier's 200 GB)"
+	}
+	next := "Account paused - resume it to hunt again"
+	if w.Config.Teardown.Relaunch {
+		next = "Hunting for a new instance"
+	}
+	if err := w.Notifier.Send(notifier.Message{
+		Title: "🧨 Instance Torn Down",
+		Fields: []notifier.Field{
+			{Name: "Account", Value: w.AccountName},
+			{Name: "Instance", Value: fmt.Sprintf("%s · %s", inst.Name, inst.ID)},
+			{Name: "Boot Volume", Value: bootVolume},
+			{Name: "Next", Value: next},
+		},
+		Color:    notifier.ColorInfo,
+		Priority: 3,
+		Tags:     "bomb",
+	}); err != nil {
+		w.Logger.Error(w.AccountName, fmt.Sprintf("Notification failed: %v", err))
+	}
+	return nil
+}
+
+// forget drops inst from the state, logging why.
+func (w *AccountWorker) forget(inst state.Instance, why string) {
+	w.Logger.Warn(w.AccountName, fmt.Sprintf("🧨 %s %s", inst.Name, why))
+	delete(w.teardownWarned, inst.ID)
+	if err := w.State.ForgetInstance(w.AccountName, inst.ID); err != nil {
+		w.Logger.Warn(w.AccountName, fmt.Sprintf("Failed to persist instance: %v", err))
+	}
+}
+
+// checkTeardown runs the teardown of a provisioned account. Once its last instance is
+// gone the account hunts again with relaunch, and is paused otherwise.
+func (p *Provisioner) checkTeardown(ctx context.Context, w *AccountWorker) {
+	if !w.Config.Teardown.Enabled() {
+		return
+	}
+	// Without a recorded instance there is nothing to time the teardown by, and no
+	// instance left doesn't mean one was torn down
+	if len(w.State.Instances(w.AccountName)) == 0 {
+		p.Logger.Warn(w.AccountName, "No recorded instance to tear down - skipping the teardown check")
+		return
+	}
+	left, err := w.teardown(ctx, time.Now())
+	if err != nil {
+		p.Logger.Warn(w.AccountName, fmt.Sprintf("Teardown check failed: %v", err))
+		return
+	}
+	if left > 0 {
+		return
+	}
+	p.Provisioned[w.AccountName] = false
+	w.Verified = nil
+	if !w.Config.Teardown.Relaunch {
+		p.setPaused(w.AccountName, true)
+		p.Logger.Warn(w.AccountName, "Paused after teardown - resume the account to hunt again")
+	}
+}
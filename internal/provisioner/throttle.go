@@ -0,0 +1,104 @@
+package provisioner
+
+import (
+	"fmt"
+
+	"github.com/yourusername/oci-arm-provisioner/internal/notifier"
+)
+
+const (
+	// failureWindow is how many of an account's latest API calls the failure ratio covers.
+	failureWindow = 20
+	// maxThrottle is the most cycles an account's attempts are stretched to.
+	maxThrottle = 8
+)
+
+// failureRate is an account's recent API call outcomes and how far its attempts are
+// stretched because of them.
+type failureRate struct {
+	outcomes []bool // Latest calls first to last, true for a failure; at most failureWindow.
+	factor   int    // Attempts run every factor cycles; 0 or 1 is full speed.
+	skip     int    // Cycles left to skip before the next attempt.
+}
+
+// record adds the outcome of an API call.
+func (f *failureRate) record(failed bool) {
+	f.outcomes = append(f.outcomes, failed)
+	if len(f.outcomes) > failureWindow {
+		f.outcomes = f.outcomes[1:]
+	}
+}
+
+// ratio returns the share of failures among the recorded calls.
+func (f *failureRate) ratio() float64 {
+	if len(f.outcomes) == 0 {
+		return 0
+	}
+	failed := 0
+	for _, o := range f.outcomes {
+		if o {
+			failed++
+		}
+	}
+	return float64(failed) / float64(len(f.outcomes))
+}
+
+// recordOutcome counts err towards the account's failure ratio. Capacity errors, rate
+// limits and other answers from OCI are not failures; network errors, 5xx and rejected
+// credentials are.
+func (w *AccountWorker) recordOutcome(err error) {
+	if w.ThrottleRatio <= 0 {
+		return
+	}
+	failed := isTransientAPIError(err) || isAuthError(err)
+	if failed {
+		w.lastFailure = err
+	}
+	w.failures.record(failed)
+}
+
+// throttled reports whether the account skips this cycle's attempt. A full window with
+// at least ThrottleRatio failures stretches its attempts to every 2nd cycle, and each
+// attempt that still fails as much doubles that, up to maxThrottle. While throttled only
+// the calls since the last attempt count, so one good attempt restores full speed.
+func (w *AccountWorker) throttled() bool {
+	f := &w.failures
+	if w.ThrottleRatio <= 0 {
+		return false
+	}
+	if f.skip > 0 {
+		f.skip--
+		return true
+	}
+
+	slowed := f.factor > 1
+	if (!slowed && len(f.outcomes) < failureWindow) || (slowed && len(f.outcomes) == 0) {
+		return false
+	}
+	ratio := f.ratio()
+	if ratio < w.ThrottleRatio {
+		if slowed {
+			f.factor = 1
+			w.Logger.Success(w.AccountName, "🐇 Requests succeed again - back to an attempt every cycle")
+			if err := w.Notifier.ResolveAlert(notifier.ClassErrorRate, w.AccountName); err != nil {
+				w.Logger.Error(w.AccountName, fmt.Sprintf("Notification failed: %v", err))
+			}
+		}
+		return false
+	}
+
+	f.factor = min(max(f.factor*2, 2), maxThrottle)
+	f.skip = f.factor - 2 // This cycle is the first one skipped
+	detail := fmt.Sprintf("%.0f%% of the last %d requests failed for reasons other than capacity", ratio*100, len(f.outcomes))
+	f.outcomes = f.outcomes[:0]
+	w.Logger.Warn(w.AccountName, fmt.Sprintf("🐢 %s - attempting every %d cycles until they succeed", detail, f.factor))
+	if err := w.Notifier.SendAlert(notifier.Alert{
+		Class:   notifier.ClassErrorRate,
+		Account: w.AccountName,
+		Detail:  detail,
+		Fields:  append([]notifier.Field{{Name: "Attempts", Value: fmt.Sprintf("Every %d cycles", f.factor)}}, remedyFields(w.lastFailure)...),
+	}); err != nil {
+		w.Logger.Error(w.AccountName, fmt.Sprintf("Notification failed: %v", err))
+	}
+	return true
+}
@@ -0,0 +1,38 @@
+package provisioner
+
+import (
+	"time"
+
+	"github.com/yourusername/oci-arm-provisioner/internal/config"
+)
+
+// Timeouts used when a worker has none configured (timeouts in the config).
+const (
+	defaultLaunchTimeout = config.DefaultLaunchSeconds * time.Second
+	defaultVerifyTimeout = config.DefaultVerifyMinutes * time.Minute
+)
+
+// verifyLookupTime is the part of the verification window kept for the shape and IP
+// lookups after the instance is RUNNING.
+const verifyLookupTime = time.Minute
+
+// launchTimeout returns how long one launch attempt may take.
+func (w *AccountWorker) launchTimeout() time.Duration {
+	if w.LaunchTimeout > 0 {
+		return w.LaunchTimeout
+	}
+	return defaultLaunchTimeout
+}
+
+// verifyTimeout returns how long the verification of a launched instance may take.
+func (w *AccountWorker) verifyTimeout() time.Duration {
+	if w.VerifyTimeout > 0 {
+		return w.VerifyTimeout
+	}
+	return defaultVerifyTimeout
+}
+
+// runningWait returns how long verification waits for the instance to be RUNNING.
+func (w *AccountWorker) runningWait() time.Duration {
+	return max(w.verifyTimeout()-verifyLookupTime, w.pollInterval())
+}
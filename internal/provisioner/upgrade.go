@@ -0,0 +1,108 @@
+package provisioner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/core"
+	"github.com/yourusername/oci-arm-provisioner/internal/config"
+	"github.com/yourusername/oci-arm-provisioner/internal/notifier"
+	"github.com/yourusername/oci-arm-provisioner/internal/state"
+)
+
+// upgradeTerminateWait bounds the wait for an instance replaced by upgrade_existing to
+// terminate and release its boot volume.
+const upgradeTerminateWait = 5 * time.Minute
+
+// undersized reports whether inst runs the account's shape with fewer OCPUs or less
+// memory than it asks for. It returns the size inst has and the size asked for.
+func (w *AccountWorker) undersized(inst core.Instance) (have, want config.ShapeOption, small bool) {
+	want = w.Config.Shapes()[0]
+	have = config.ShapeOption{Shape: safeString(inst.Shape)}
+	if inst.ShapeConfig == nil || inst.ShapeConfig.Ocpus == nil || inst.ShapeConfig.MemoryInGBs == nil {
+		return have, want, false
+	}
+	have.OCPUs, have.MemoryGB = *inst.ShapeConfig.Ocpus, *inst.ShapeConfig.MemoryInGBs
+	if want.Fixed || have.Shape != want.Shape {
+		return have, want, false
+	}
+	return have, want, have.OCPUs < want.OCPUs || have.MemoryGB < want.MemoryGB
+}
+
+// upgradeExisting handles an existing instance smaller than the account asks for. Without
+// upgrade_existing it is only reported. With it, once the capacity report has room in
+// the instance's AD, the instance is terminated with its boot volume kept for the
+// launch that follows. It reports whether the instance is gone.
+func (w *AccountWorker) upgradeExisting(ctx context.Context, inst core.Instance, have, want config.ShapeOption) (bool, error) {
+	name, ad := safeString(inst.DisplayName), safeString(inst.AvailabilityDomain)
+	if !w.Config.UpgradeExisting {
+		w.Logger.Warn(w.AccountName, fmt.Sprintf("%s is %s, smaller than %s. Set upgrade_existing: true to replace it.", name, have, want))
+		return false, nil
+	}
+
+	room, err := w.capacityIn(ctx, want, []string{ad})
+	if err != nil {
+		return false, fmt.Errorf("capacity report: %w", err)
+	}
+	if room == "" {
+		w.Logger.Info(w.AccountName, fmt.Sprintf("⏫ %s is %s - waiting for capacity for %s in %s", name, have, want, ad))
+		return false, nil
+	}
+
+	bootVolume, err := w.bootVolumeOf(ctx, inst)
+	if err != nil {
+		return false, fmt.Errorf("not replacing %s: could not look up its boot volume: %w", name, err)
+	}
+	if bootVolume == "" {
+		return false, fmt.Errorf("not replacing %s: it has no attached boot volume", name)
+	}
+	w.Logger.Success(w.AccountName, fmt.Sprintf("⏫ Capacity for %s in %s - replacing %s, %s", want, ad, name, have))
+	_, err = w.ComputeClient.TerminateInstance(ctx, core.TerminateInstanceRequest{
+		InstanceId:         inst.Id,
+		PreserveBootVolume: common.Bool(true),
+	})
+	w.observe(err)
+	if err != nil {
+		return false, fmt.Errorf("could not terminate %s to upgrade it: %w", name, err)
+	}
+	if err := w.State.SetBootVolume(w.AccountName, &state.BootVolume{ID: bootVolume, AvailabilityDomain: ad, Instance: name}); err != nil {
+		w.Logger.Warn(w.AccountName, fmt.Sprintf("Failed to persist boot volume: %v", err))
+	}
+	w.forget(state.Instance{ID: safeString(inst.Id), Name: name}, "was terminated to relaunch it at the full size")
+	if err := w.Notifier.Send(notifier.Message{
+		Title: "⏫ Upgrading Instance",
+		Fields: []notifier.Field{
+			{Name: "Account", Value: w.AccountName},
+			{Name: "Terminated", Value: fmt.Sprintf("%s · %s · %s", name, safeString(inst.Id), have)},
+			{Name: "Launching", Value: fmt.Sprintf("%s in %s, from its boot volume", want, ad)},
+			{Name: "Note", Value: "If the capacity is gone by then, the account hunts for it from the boot volume."},
+		},
+		Color: notifier.ColorInfo,
+		Tags:  "arrow_double_up",
+	}); err != nil {
+		w.Logger.Error(w.AccountName, fmt.Sprintf("Notification failed: %v", err))
+	}
+	return true, w.waitTerminated(ctx, safeString(inst.Id))
+}
+
+// waitTerminated polls the instance until it is terminated, so its boot volume is
+// detached and can be launched from.
+func (w *AccountWorker) waitTerminated(ctx context.Context, instanceID string) error {
+	ctx, cancel := context.WithTimeout(ctx, upgradeTerminateWait)
+	defer cancel()
+	for {
+		resp, err := w.ComputeClient.GetInstance(ctx, core.GetInstanceRequest{InstanceId: common.String(instanceID)})
+		w.observe(err)
+		if isNotFound(err) || (err == nil && resp.LifecycleState == core.InstanceLifecycleStateTerminated) {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return errors.New("the replaced instance is still terminating; launching from its boot volume next cycle")
+		case <-time.After(w.pollInterval()):
+		}
+	}
+}
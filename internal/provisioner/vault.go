@@ -0,0 +1,81 @@
+package provisioner
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/common/auth"
+	"github.com/oracle/oci-go-sdk/v65/secrets"
+	"github.com/yourusername/oci-arm-provisioner/internal/config"
+)
+
+// vaultTimeout bounds reading the key from the vault.
+const vaultTimeout = 30 * time.Second
+
+// readVaultKey returns the key stored in key_vault_secret_ocid. It is read once per
+// worker, so at startup and after a reload, and never written to disk.
+func (w *AccountWorker) readVaultKey() ([]byte, error) {
+	if w.vaultKey != nil {
+		return w.vaultKey, nil
+	}
+	secretID := w.Config.KeyVaultSecretOCID
+	if w.SecretsClient == nil {
+		client, err := w.newSecretsClient(secretID)
+		if err != nil {
+			return nil, err
+		}
+		w.SecretsClient = client
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), vaultTimeout)
+	defer cancel()
+	resp, err := w.SecretsClient.GetSecretBundle(ctx, secrets.GetSecretBundleRequest{
+		SecretId: common.String(secretID),
+		Stage:    secrets.GetSecretBundleStageCurrent,
+	})
+	if err != nil {
+		if isNotAuthorizedOrNotFound(err) {
+			return nil, fmt.Errorf("key_vault_secret_ocid: %s not found, or the instance's dynamic group lacks 'read secret-bundles' on it: %w", secretID, err)
+		}
+		return nil, fmt.Errorf("key_vault_secret_ocid: %w", err)
+	}
+	content, ok := resp.SecretBundleContent.(secrets.Base64SecretBundleContentDetails)
+	if !ok || content.Content == nil {
+		return nil, fmt.Errorf("key_vault_secret_ocid: %s has no base64 content", secretID)
+	}
+	key, err := base64.StdEncoding.DecodeString(*content.Content)
+	if err != nil {
+		return nil, fmt.Errorf("key_vault_secret_ocid: decoding the secret: %w", err)
+	}
+	if len(key) > config.MaxKeySize {
+		return nil, fmt.Errorf("key_vault_secret_ocid: key too large (%d bytes), max is %d", len(key), config.MaxKeySize)
+	}
+	w.Logger.Info(w.AccountName, "🔐 API key read from the vault")
+	w.vaultKey = key
+	return key, nil
+}
+
+// newSecretsClient reads secrets with the instance principal of the host, in the
+// secret's region (part of its OCID) or else the account's.
+func (w *AccountWorker) newSecretsClient(secretID string) (SecretsClientOps, error) {
+	region := w.Config.Region
+	if parts := strings.Split(secretID, "."); len(parts) > 3 && parts[3] != "" {
+		region = string(common.StringToRegion(parts[3]))
+	}
+	provider, err := auth.InstancePrincipalConfigurationProviderForRegion(common.StringToRegion(region))
+	if err != nil {
+		return nil, fmt.Errorf("key_vault_secret_ocid: reading the vault needs an instance principal (running on an OCI instance?): %w", err)
+	}
+	client, err := secrets.NewSecretsClientWithConfigurationProvider(provider)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create secrets client: %w", err)
+	}
+	w.setRealmDomain(&client.BaseClient, region)
+	w.tagRequests(&client.BaseClient)
+	w.useProxy(&client.BaseClient)
+	return &client, nil
+}
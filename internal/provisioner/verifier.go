@@ -7,6 +7,8 @@ import (
 
 	"github.com/oracle/oci-go-sdk/v65/common"
 	"github.com/oracle/oci-go-sdk/v65/core"
+	"github.com/yourusername/oci-arm-provisioner/internal/notifier"
+	"github.com/yourusername/oci-arm-provisioner/internal/state"
 )
 
 // VerifiedInstance contains the verification results for a launched instance.
@@ -15,6 +17,9 @@ type VerifiedInstance struct {
 	DisplayName   string
 	PublicIP      string
 	PrivateIP     string
+	IPv6          string        // First IPv6 address of the primary VNIC, with assign_ipv6.
+	RunningAfter  time.Duration // From the start of the verification to RUNNING; 0 if it never got there.
+	PublicIPAfter time.Duration // From the start of the verification to a public IP; 0 if none was seen.
 	State         string
 	Shape         string
 	OCPUs         float32
@@ -22,30 +27,43 @@ type VerifiedInstance struct {
 	Region        string
 	Verified      bool
 	SpecsMismatch bool
+	HostKeys      []string // SSH host key fingerprints from the serial console, e.g. "ED25519 SHA256:...".
+	Number        int      // Which of the account's instance_count instances this is, from 1.
+	Count         int      // The account's instance_count.
 	Errors        []string
 }
 
 // Getter methods for logger interface compatibility
-func (v *VerifiedInstance) GetInstanceID() string { return v.InstanceID }
-func (v *VerifiedInstance) GetPublicIP() string   { return v.PublicIP }
-func (v *VerifiedInstance) GetOCPUs() float32     { return v.OCPUs }
-func (v *VerifiedInstance) GetMemoryGB() float32  { return v.MemoryGB }
-func (v *VerifiedInstance) GetState() string      { return v.State }
-func (v *VerifiedInstance) GetRegion() string     { return v.Region }
+func (v *VerifiedInstance) GetInstanceID() string  { return v.InstanceID }
+func (v *VerifiedInstance) GetPublicIP() string    { return v.PublicIP }
+func (v *VerifiedInstance) GetIPv6() string        { return v.IPv6 }
+func (v *VerifiedInstance) GetOCPUs() float32      { return v.OCPUs }
+func (v *VerifiedInstance) GetMemoryGB() float32   { return v.MemoryGB }
+func (v *VerifiedInstance) GetState() string       { return v.State }
+func (v *VerifiedInstance) GetRegion() string      { return v.Region }
+func (v *VerifiedInstance) GetHostKeys() []string  { return v.HostKeys }
+func (v *VerifiedInstance) GetOrdinal() (int, int) { return v.Number, v.Count }
 
 // VerifyInstance polls OCI to confirm the instance is RUNNING and specs match.
 // It retrieves the public IP and validates the shape configuration.
 func (w *AccountWorker) VerifyInstance(ctx context.Context, instanceID string) (*VerifiedInstance, error) {
+	return w.verifyInstance(ctx, instanceID, w.Config.OCPUs, w.Config.MemoryGB)
+}
+
+// verifyInstance is VerifyInstance against explicit specs (which differ from the config
+// when the request was shrunk to fit the free-tier allotment).
+func (w *AccountWorker) verifyInstance(ctx context.Context, instanceID string, wantOCPUs, wantMemoryGB float32) (*VerifiedInstance, error) {
 	result := &VerifiedInstance{
 		InstanceID: instanceID,
 		Region:     w.Config.Region,
 		Errors:     []string{},
 	}
 
-	// 1. Poll for RUNNING state (max 5 minutes, check every 10s)
-	const maxWait = 5 * time.Minute
-	const pollInterval = 10 * time.Second
-	deadline := time.Now().Add(maxWait)
+	// 1. Poll for RUNNING state (timeouts.verify_minutes less a minute, check every poll interval)
+	maxWait := w.runningWait()
+	pollInterval := w.pollInterval()
+	start := time.Now()
+	deadline := start.Add(maxWait)
 
 	w.Logger.Info(w.AccountName, "Verifying instance launch...")
 
@@ -90,6 +108,7 @@ func (w *AccountWorker) VerifyInstance(ctx context.Context, instanceID string) (
 		result.Errors = append(result.Errors, "Timeout waiting for RUNNING state")
 		return result, fmt.Errorf("verification timeout: instance not running after %v", maxWait)
 	}
+	result.RunningAfter = time.Since(start)
 
 	// 2. Verify Shape Configuration
 	if instance.ShapeConfig != nil {
@@ -102,13 +121,13 @@ func (w *AccountWorker) VerifyInstance(ctx context.Context, instanceID string) (
 	}
 
 	// Check if specs match requested config
-	if result.OCPUs != w.Config.OCPUs {
+	if result.OCPUs != wantOCPUs {
 		result.SpecsMismatch = true
-		result.Errors = append(result.Errors, fmt.Sprintf("OCPUs mismatch: requested %.1f, got %.1f", w.Config.OCPUs, result.OCPUs))
+		result.Errors = append(result.Errors, fmt.Sprintf("OCPUs mismatch: requested %.1f, got %.1f", wantOCPUs, result.OCPUs))
 	}
-	if result.MemoryGB != w.Config.MemoryGB {
+	if result.MemoryGB != wantMemoryGB {
 		result.SpecsMismatch = true
-		result.Errors = append(result.Errors, fmt.Sprintf("Memory mismatch: requested %.1fGB, got %.1fGB", w.Config.MemoryGB, result.MemoryGB))
+		result.Errors = append(result.Errors, fmt.Sprintf("Memory mismatch: requested %.1fGB, got %.1fGB", wantMemoryGB, result.MemoryGB))
 	}
 
 	if !result.SpecsMismatch {
@@ -117,41 +136,30 @@ func (w *AccountWorker) VerifyInstance(ctx context.Context, instanceID string) (
 		w.Logger.Warn(w.AccountName, "Specs mismatch detected!")
 	}
 
-	// 3. Get VNIC Attachments to retrieve IP
-	vnicResp, err := w.ComputeClient.ListVnicAttachments(ctx, core.ListVnicAttachmentsRequest{
-		CompartmentId: common.String(w.Config.CompartmentOCID),
-		InstanceId:    common.String(instanceID),
-	})
-	if err != nil {
-		result.Errors = append(result.Errors, fmt.Sprintf("ListVnicAttachments failed: %v", err))
-		w.Logger.Warn(w.AccountName, fmt.Sprintf("Could not retrieve VNIC attachments: %v", err))
-	} else if len(vnicResp.Items) > 0 {
-		// Get the primary VNIC
-		for _, att := range vnicResp.Items {
-			if att.VnicId != nil && att.LifecycleState == core.VnicAttachmentLifecycleStateAttached {
-				vnic, err := w.VirtualNetworkClient.GetVnic(ctx, core.GetVnicRequest{
-					VnicId: att.VnicId,
-				})
-				if err != nil {
-					result.Errors = append(result.Errors, fmt.Sprintf("GetVnic failed: %v", err))
-					continue
-				}
-				if vnic.Vnic.PublicIp != nil {
-					result.PublicIP = *vnic.Vnic.PublicIp
-				}
-				if vnic.Vnic.PrivateIp != nil {
-					result.PrivateIP = *vnic.Vnic.PrivateIp
-				}
-				break // Got the primary VNIC
-			}
+	// 3. Get the IPs from the primary VNIC
+	vnic, err := w.primaryVnic(ctx, instanceID)
+	if vnic != nil {
+		result.PublicIP, result.PrivateIP = safeString(vnic.PublicIp), safeString(vnic.PrivateIp)
+		if len(vnic.Ipv6Addresses) > 0 {
+			result.IPv6 = vnic.Ipv6Addresses[0]
 		}
 	}
+	if err != nil {
+		result.Errors = append(result.Errors, err.Error())
+		w.Logger.Warn(w.AccountName, fmt.Sprintf("Could not retrieve VNIC: %v", err))
+	}
 
 	if result.PublicIP != "" {
+		result.PublicIPAfter = time.Since(start)
 		w.Logger.Info(w.AccountName, fmt.Sprintf("Public IP: %s ✓", result.PublicIP))
 	} else {
 		w.Logger.Warn(w.AccountName, "No public IP assigned (may take a moment)")
 	}
+	if result.IPv6 != "" {
+		w.Logger.Info(w.AccountName, fmt.Sprintf("IPv6: %s ✓", result.IPv6))
+	} else if w.Config.AssignIPv6 {
+		w.Logger.Warn(w.AccountName, "No IPv6 address assigned (does the subnet have an IPv6 prefix?)")
+	}
 
 	// Mark as verified if no critical errors
 	result.Verified = len(result.Errors) == 0 || (len(result.Errors) > 0 && !result.SpecsMismatch && result.State == "RUNNING")
@@ -159,6 +167,75 @@ func (w *AccountWorker) VerifyInstance(ctx context.Context, instanceID string) (
 	return result, nil
 }
 
+// recordVerification adds how the instance came up to the run's metrics and the
+// account's history, and warns when OCI keeps delivering other specs than requested.
+func (w *AccountWorker) recordVerification(v *VerifiedInstance, shape string, verifyErr error) {
+	outcome := notifier.Verification{RunningAfter: v.RunningAfter, PublicIPAfter: v.PublicIPAfter, SpecsMismatch: v.SpecsMismatch}
+	w.Tracker.RecordVerification(w.AccountName, outcome)
+	w.Logger.Info(w.AccountName, "⏱️ "+outcome.String())
+
+	record := state.Verification{
+		InstanceID:      v.InstanceID,
+		At:              time.Now(),
+		Shape:           shape,
+		RunningSeconds:  v.RunningAfter.Seconds(),
+		PublicIPSeconds: v.PublicIPAfter.Seconds(),
+		SpecsMismatch:   v.SpecsMismatch,
+	}
+	if verifyErr != nil {
+		record.Error = verifyErr.Error()
+	}
+	if err := w.State.RecordVerification(w.AccountName, record); err != nil {
+		w.Logger.Warn(w.AccountName, fmt.Sprintf("Failed to persist verification: %v", err))
+	}
+
+	if !v.SpecsMismatch {
+		return
+	}
+	history := w.State.Verifications(w.AccountName)
+	mismatches := 0
+	for _, h := range history {
+		if h.SpecsMismatch {
+			mismatches++
+		}
+	}
+	if mismatches > 1 {
+		w.Logger.Warn(w.AccountName, fmt.Sprintf("Specs mismatched in %d of the last %d launches - check the shape sizes against the tenancy's limits", mismatches, len(history)))
+	}
+}
+
+// primaryIPs returns the public and private IP of the instance's first attached VNIC.
+func (w *AccountWorker) primaryIPs(ctx context.Context, instanceID string) (public, private string, err error) {
+	vnic, err := w.primaryVnic(ctx, instanceID)
+	if vnic == nil {
+		return "", "", err
+	}
+	return safeString(vnic.PublicIp), safeString(vnic.PrivateIp), nil
+}
+
+// primaryVnic returns the instance's first attached VNIC, or nil if there is none yet.
+func (w *AccountWorker) primaryVnic(ctx context.Context, instanceID string) (*core.Vnic, error) {
+	vnicResp, err := w.ComputeClient.ListVnicAttachments(ctx, core.ListVnicAttachmentsRequest{
+		CompartmentId: common.String(w.Config.CompartmentOCID),
+		InstanceId:    common.String(instanceID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ListVnicAttachments failed: %w", err)
+	}
+	for _, att := range vnicResp.Items {
+		if att.VnicId == nil || att.LifecycleState != core.VnicAttachmentLifecycleStateAttached {
+			continue
+		}
+		vnic, vnicErr := w.VirtualNetworkClient.GetVnic(ctx, core.GetVnicRequest{VnicId: att.VnicId})
+		if vnicErr != nil {
+			err = fmt.Errorf("GetVnic failed: %w", vnicErr)
+			continue
+		}
+		return &vnic.Vnic, nil
+	}
+	return nil, err
+}
+
 // safeString safely dereferences a string pointer
 func safeString(s *string) string {
 	if s == nil {
@@ -0,0 +1,120 @@
+// Package remedy maps frequent OCI errors to the steps that fix them, so the log, the
+// dashboard and notifications can say what to do instead of only repeating the API
+// message.
+package remedy
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+)
+
+// Remedy is what to do about one kind of error.
+type Remedy struct {
+	Problem string   // What went wrong, in plain words.
+	Steps   []string // What to check, most likely cause first.
+}
+
+// String renders r on one line, for the log.
+func (r Remedy) String() string {
+	var b strings.Builder
+	b.WriteString(r.Problem + ".")
+	for i, step := range r.Steps {
+		fmt.Fprintf(&b, " %d) %s", i+1, step)
+	}
+	return b.String()
+}
+
+// entry is a known error: match gets the lowercased error text.
+type entry struct {
+	match  func(msg string) bool
+	remedy Remedy
+}
+
+// known are checked in order; the first match wins.
+var known = []entry{
+	{
+		match: func(msg string) bool {
+			return strings.Contains(msg, "notauthenticated") || strings.Contains(msg, "http status code: 401")
+		},
+		remedy: Remedy{
+			Problem: "OCI did not accept the API key",
+			Steps: []string{
+				"Compare fingerprint with the key listed under Profile > API keys in the OCI Console; a deleted or replaced key fails like this.",
+				"Check that key_file is the private key of that API key, not another one you generated.",
+				"Check user_ocid and tenancy_ocid: they must be the user the key belongs to and that user's tenancy.",
+				"Make sure the machine's clock is right; OCI rejects requests signed more than 5 minutes off.",
+				"A new key can take a minute to be accepted after it is added.",
+			},
+		},
+	},
+	{
+		match: func(msg string) bool {
+			return strings.Contains(msg, "limitexceeded") || strings.Contains(msg, "service limit")
+		},
+		remedy: Remedy{
+			Problem: "The tenancy's service limit for this shape is used up",
+			Steps: []string{
+				"Look at Governance > Limits, Quotas and Usage in the OCI Console for the compute limits named in the error.",
+				"Terminate instances you no longer need; Always Free tenancies get 4 A1 OCPUs and 24 GB in total.",
+				"Lower ocpus/memory_gb to fit what is left, or set auto_shrink: true.",
+				"On a paid account, request a service limit increase from the same page.",
+			},
+		},
+	},
+	{
+		match: func(msg string) bool {
+			return strings.Contains(msg, "hostnamelabel") || strings.Contains(msg, "hostname label")
+		},
+		remedy: Remedy{
+			Problem: "OCI rejected the instance's hostname",
+			Steps: []string{
+				"A hostname must be 1-63 letters, digits or hyphens and start with a letter; display_name is turned into one.",
+				"It must be unique in the subnet: another instance (or a terminated one still listed) may hold it.",
+				"The VCN and the subnet both need a DNS label for hostnames; set them in the OCI Console or launch in a subnet that has one.",
+			},
+		},
+	},
+	{
+		match: func(msg string) bool {
+			return strings.Contains(msg, "shape") && strings.Contains(msg, "image") &&
+				(strings.Contains(msg, "not compatible") || strings.Contains(msg, "incompatible") ||
+					strings.Contains(msg, "not valid") || strings.Contains(msg, "not supported"))
+		},
+		remedy: Remedy{
+			Problem: "The image does not run on the shape",
+			Steps: []string{
+				"Ampere A1 shapes need an aarch64 (ARM) image; x86 images only run on AMD and Intel shapes such as VM.Standard.E2.1.Micro.",
+				"Use an image alias like image: ubuntu-22.04-arm, which picks a matching image for each shape.",
+				"With image_ocid, take the OCID from the same region and check the image's compatible shapes in the OCI Console.",
+			},
+		},
+	},
+}
+
+// For returns the remedy for err, if it is a known error. The status and code of an OCI
+// service error count even when a wrapping message leaves them out.
+func For(err error) (Remedy, bool) {
+	if err == nil {
+		return Remedy{}, false
+	}
+	msg := err.Error()
+	var serviceErr common.ServiceError
+	if errors.As(err, &serviceErr) {
+		msg += fmt.Sprintf(" Http Status Code: %d. Error Code: %s.", serviceErr.GetHTTPStatusCode(), serviceErr.GetCode())
+	}
+	return Lookup(msg)
+}
+
+// Lookup returns the remedy for an error message, if it is a known error.
+func Lookup(msg string) (Remedy, bool) {
+	msg = strings.ToLower(msg)
+	for _, e := range known {
+		if e.match(msg) {
+			return e.remedy, true
+		}
+	}
+	return Remedy{}, false
+}
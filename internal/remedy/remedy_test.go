@@ -0,0 +1,36 @@
+package remedy
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	for msg, want := range map[string]string{
+		"Error returned by Compute Service. Http Status Code: 401. Error Code: NotAuthenticated.":                         "API key",
+		"The following service limits were exceeded: standard-a1-core-count":                                              "service limit",
+		"Error Code: InvalidParameter. Message: Invalid hostnameLabel: must be unique in the subnet":                      "hostname",
+		"Error Code: InvalidParameter. Message: Shape VM.Standard.A1.Flex is not compatible with image ocid1.image.oc1..": "image does not run",
+		"Out of host capacity.": "",
+	} {
+		r, ok := Lookup(msg)
+		if want == "" {
+			if ok {
+				t.Errorf("%q: expected no remedy, got %q", msg, r.Problem)
+			}
+			continue
+		}
+		if !ok || !strings.Contains(r.Problem, want) || len(r.Steps) == 0 {
+			t.Errorf("%q: expected a %q remedy, got %+v", msg, want, r)
+		}
+	}
+
+	if _, ok := For(nil); ok {
+		t.Error("expected no remedy without an error")
+	}
+	r, _ := For(errors.New("NotAuthenticated"))
+	if s := r.String(); !strings.HasPrefix(s, "OCI did not accept the API key. 1) ") || !strings.Contains(s, " 2) ") {
+		t.Errorf("unexpected one-line remedy %q", s)
+	}
+}
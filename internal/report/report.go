@@ -0,0 +1,131 @@
+// Package report uploads a record of each provisioned instance to an Object Storage
+// bucket through a pre-authenticated request (PAR), a durable copy that needs no OCI
+// credentials and survives the machine the provisioner runs on.
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/yourusername/oci-arm-provisioner/internal/buildinfo"
+	"github.com/yourusername/oci-arm-provisioner/internal/config"
+)
+
+// DefaultTimeout bounds each upload.
+const DefaultTimeout = 30 * time.Second
+
+// Report is what is known about an instance when it was provisioned.
+type Report struct {
+	Account      string
+	InstanceID   string
+	AttemptID    string    // The launch attempt, if any.
+	Time         time.Time // Names the report's folder.
+	Instance     any       // Uploaded as instance.json, e.g. the instance as OCI returns it.
+	Verification any       // Uploaded as verification.json.
+	Console      string    // Serial console output, uploaded as console.txt when not empty.
+}
+
+// Prefix is the folder the report's objects are put under:
+// <account>/<time>-<end of the instance OCID>/.
+func (r Report) Prefix() string {
+	id := r.InstanceID
+	if len(id) > 8 {
+		id = id[len(id)-8:]
+	}
+	return r.Account + "/" + r.Time.UTC().Format("20060102T150405Z") + "-" + id + "/"
+}
+
+// Uploader puts reports through the configured PAR. Client is overridable for tests.
+type Uploader struct {
+	Config config.SuccessReportConfig
+	Client *http.Client
+}
+
+// New creates an Uploader, or returns nil if none is configured.
+func New(cfg config.SuccessReportConfig) *Uploader {
+	if !cfg.Enabled() {
+		return nil
+	}
+	return &Uploader{Config: cfg, Client: &http.Client{}}
+}
+
+// Upload puts r's objects and returns the prefix they were put under. Errors never
+// contain the PAR URL, which is a credential. A nil *Uploader does nothing.
+func (u *Uploader) Upload(ctx context.Context, r Report) (string, error) {
+	if u == nil {
+		return "", nil
+	}
+	timeout := DefaultTimeout
+	if d, err := time.ParseDuration(u.Config.Timeout); err == nil && d > 0 {
+		timeout = d
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	prefix := r.Prefix()
+	meta := map[string]string{"account": r.Account, "instance_id": r.InstanceID, "attempt_id": r.AttemptID, "time": r.Time.UTC().Format(time.RFC3339)}
+	for _, o := range []struct {
+		name  string
+		value any
+	}{{"instance.json", r.Instance}, {"verification.json", r.Verification}} {
+		data, err := json.MarshalIndent(withMeta(o.value, meta), "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", o.name, err)
+		}
+		if err := u.put(ctx, prefix+o.name, "application/json", data); err != nil {
+			return "", fmt.Errorf("%s: %w", o.name, err)
+		}
+	}
+	if r.Console != "" {
+		if err := u.put(ctx, prefix+"console.txt", "text/plain; charset=utf-8", []byte(r.Console)); err != nil {
+			return "", fmt.Errorf("console.txt: %w", err)
+		}
+	}
+	return prefix, nil
+}
+
+// withMeta wraps v with the report's account, instance and attempt, so each object
+// reads on its own.
+func withMeta(v any, meta map[string]string) any {
+	return struct {
+		Meta map[string]string `json:"report"`
+		Data any               `json:"data"`
+	}{meta, v}
+}
+
+func (u *Uploader) put(ctx context.Context, name, contentType string, body []byte) error {
+	segments := strings.Split(name, "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, u.Config.PARURL+strings.Join(segments, "/"), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("invalid par_url")
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("User-Agent", buildinfo.UserAgent("", ""))
+
+	resp, err := u.Client.Do(req)
+	if err != nil {
+		// *url.Error repeats the request URL; keep only the cause
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			return urlErr.Err
+		}
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
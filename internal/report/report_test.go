@@ -0,0 +1,91 @@
+package report
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yourusername/oci-arm-provisioner/internal/config"
+)
+
+var report = Report{
+	Account:      "personal",
+	InstanceID:   "ocid1.instance.oc1.test.abcdefgh12345678",
+	AttemptID:    "a1b2c3d4e5f6",
+	Time:         time.Date(2026, 10, 16, 8, 30, 0, 0, time.UTC),
+	Instance:     map[string]string{"lifecycleState": "RUNNING"},
+	Verification: map[string]bool{"verified": true},
+	Console:      "ci-info: ready\n",
+}
+
+func TestUpload(t *testing.T) {
+	var mu sync.Mutex
+	got := make(map[string]string)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			t.Errorf("expected PUT, got %s", r.Method)
+		}
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		got[r.URL.Path] = string(body)
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	prefix, err := New(config.SuccessReportConfig{PARURL: srv.URL + "/p/token/n/ns/b/bucket/o/"}).Upload(context.Background(), report)
+	if err != nil {
+		t.Fatalf("Upload failed: %v", err)
+	}
+	if prefix != "personal/20261016T083000Z-12345678/" {
+		t.Errorf("unexpected prefix %q", prefix)
+	}
+	base := "/p/token/n/ns/b/bucket/o/" + prefix
+	if len(got) != 3 || got[base+"console.txt"] != report.Console {
+		t.Fatalf("expected instance, verification and console objects, got %v", got)
+	}
+	var instance struct {
+		Report map[string]string `json:"report"`
+		Data   map[string]string `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(got[base+"instance.json"]), &instance); err != nil {
+		t.Fatal(err)
+	}
+	if instance.Report["attempt_id"] != "a1b2c3d4e5f6" || instance.Data["lifecycleState"] != "RUNNING" {
+		t.Errorf("unexpected instance.json %+v", instance)
+	}
+}
+
+func TestUpload_Error(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "PAR expired", http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	par := srv.URL + "/p/secret-token/n/ns/b/bucket/o/"
+	_, err := New(config.SuccessReportConfig{PARURL: par}).Upload(context.Background(), report)
+	if err == nil || !strings.Contains(err.Error(), "status 404: PAR expired") {
+		t.Fatalf("expected a 404 error, got %v", err)
+	}
+
+	srv.Close()
+	_, err = New(config.SuccessReportConfig{PARURL: par}).Upload(context.Background(), report)
+	if err == nil || strings.Contains(err.Error(), "secret-token") {
+		t.Errorf("expected an error without the PAR URL, got %v", err)
+	}
+}
+
+func TestNew_Disabled(t *testing.T) {
+	u := New(config.SuccessReportConfig{})
+	if u != nil {
+		t.Fatal("expected no uploader without par_url")
+	}
+	if _, err := u.Upload(context.Background(), report); err != nil {
+		t.Errorf("nil uploader should do nothing, got %v", err)
+	}
+}
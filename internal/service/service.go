@@ -0,0 +1,175 @@
+// Package service registers the provisioner with the host's service manager
+// (systemd on Linux, launchd on macOS, the Service Control Manager on Windows)
+// so it can run unattended without hand-written unit files.
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// Name is the identifier used for the service on every platform.
+const Name = "oci-arm-provisioner"
+
+// launchdLabel is the reverse-DNS label macOS expects for launch agents.
+const launchdLabel = "io.github.joaodalvi.oci-arm-provisioner"
+
+// Options describes how the service manager should start the provisioner.
+type Options struct {
+	Executable string   // Absolute path to the provisioner binary.
+	Args       []string // Arguments passed to the binary (e.g. --headless --config ...).
+	WorkDir    string   // Working directory; relative log paths resolve against it.
+}
+
+// DefaultOptions builds Options for the running binary.
+// The service always runs headless and pins the config path so it does not
+// depend on the working directory the service manager chooses.
+func DefaultOptions(configPath string) (Options, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return Options{}, fmt.Errorf("failed to locate executable: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+
+	args := []string{"--headless"}
+	if configPath != "" {
+		if abs, err := filepath.Abs(configPath); err == nil {
+			configPath = abs
+		}
+		args = append(args, "--config", configPath)
+	}
+
+	workDir, err := os.UserHomeDir()
+	if err != nil {
+		workDir = filepath.Dir(exe)
+	}
+
+	return Options{
+		Executable: exe,
+		Args:       args,
+		WorkDir:    workDir,
+	}, nil
+}
+
+// Install registers and starts the service.
+func Install(opts Options) error {
+	if opts.Executable == "" {
+		return fmt.Errorf("executable path is required")
+	}
+	return install(opts)
+}
+
+// Uninstall stops and removes the service.
+func Uninstall() error {
+	return uninstall()
+}
+
+// Status returns a human-readable description of the service state.
+func Status() (string, error) {
+	return status()
+}
+
+const systemdUnitTemplate = `[Unit]
+Description=OCI ARM VM Provisioner (Daemon)
+Wants=network-online.target
+After=network-online.target
+
+[Service]
+Type=simple
+ExecStart={{.Command}}
+Restart=always
+RestartSec=60
+WorkingDirectory={{.WorkDir}}
+
+[Install]
+WantedBy={{.WantedBy}}
+`
+
+// renderSystemdUnit produces a unit file equivalent to deployments/systemd,
+// with ExecStart pointing at the installed binary.
+func renderSystemdUnit(opts Options, wantedBy string) (string, error) {
+	parts := []string{systemdQuote(opts.Executable)}
+	for _, a := range opts.Args {
+		parts = append(parts, systemdQuote(a))
+	}
+	data := struct {
+		Command  string
+		WorkDir  string
+		WantedBy string
+	}{
+		Command:  strings.Join(parts, " "),
+		WorkDir:  opts.WorkDir,
+		WantedBy: wantedBy,
+	}
+	return renderTemplate(systemdUnitTemplate, data)
+}
+
+// systemdQuote wraps arguments containing whitespace in double quotes.
+func systemdQuote(s string) string {
+	if strings.ContainsAny(s, " \t\"") {
+		return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+	}
+	return s
+}
+
+const launchdPlistTemplate = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>{{html .Label}}</string>
+	<key>ProgramArguments</key>
+	<array>
+{{- range .Args}}
+		<string>{{html .}}</string>
+{{- end}}
+	</array>
+	<key>WorkingDirectory</key>
+	<string>{{html .WorkDir}}</string>
+	<key>RunAtLoad</key>
+	<true/>
+	<key>KeepAlive</key>
+	<true/>
+	<key>ThrottleInterval</key>
+	<integer>60</integer>
+	<key>StandardOutPath</key>
+	<string>{{html .LogPath}}</string>
+	<key>StandardErrorPath</key>
+	<string>{{html .LogPath}}</string>
+</dict>
+</plist>
+`
+
+// renderLaunchdPlist produces a launch agent definition for macOS.
+func renderLaunchdPlist(opts Options, logPath string) (string, error) {
+	data := struct {
+		Label   string
+		Args    []string
+		WorkDir string
+		LogPath string
+	}{
+		Label:   launchdLabel,
+		Args:    append([]string{opts.Executable}, opts.Args...),
+		WorkDir: opts.WorkDir,
+		LogPath: logPath,
+	}
+	return renderTemplate(launchdPlistTemplate, data)
+}
+
+func renderTemplate(text string, data interface{}) (string, error) {
+	t, err := template.New("service").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
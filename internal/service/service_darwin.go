@@ -0,0 +1,79 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// plistPath returns the per-user launch agent location.
+func plistPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve home directory: %w", err)
+	}
+	return filepath.Join(home, "Library", "LaunchAgents", launchdLabel+".plist"), nil
+}
+
+func launchctl(args ...string) (string, error) {
+	out, err := exec.Command("launchctl", args...).CombinedOutput()
+	return strings.TrimSpace(string(out)), err
+}
+
+func install(opts Options) error {
+	path, err := plistPath()
+	if err != nil {
+		return err
+	}
+	home, _ := os.UserHomeDir()
+	logPath := filepath.Join(home, "Library", "Logs", Name+".log")
+
+	plist, err := renderLaunchdPlist(opts, logPath)
+	if err != nil {
+		return fmt.Errorf("failed to render plist: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, []byte(plist), 0644); err != nil {
+		return fmt.Errorf("failed to write plist: %w", err)
+	}
+
+	// Reload in case a previous definition is still registered.
+	launchctl("unload", path)
+	if out, err := launchctl("load", "-w", path); err != nil {
+		return fmt.Errorf("launchctl load failed: %v (%s)", err, out)
+	}
+	return nil
+}
+
+func uninstall() error {
+	path, err := plistPath()
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil
+	}
+	launchctl("unload", "-w", path)
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("failed to remove plist: %w", err)
+	}
+	return nil
+}
+
+func status() (string, error) {
+	path, err := plistPath()
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return "not installed", nil
+	}
+	if _, err := launchctl("list", launchdLabel); err != nil {
+		return "installed (not loaded)", nil
+	}
+	return "installed (loaded)", nil
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// systemdScope resolves where the unit lives and which systemctl flags to use.
+// Root installs a system unit; everyone else gets a user unit, matching the
+// user-mode layout the Makefile and install.sh already use.
+func systemdScope() (unitDir string, flags []string, wantedBy string, err error) {
+	if os.Geteuid() == 0 {
+		return "/etc/systemd/system", nil, "multi-user.target", nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", nil, "", fmt.Errorf("failed to resolve home directory: %w", err)
+	}
+	return filepath.Join(home, ".config", "systemd", "user"), []string{"--user"}, "default.target", nil
+}
+
+func systemctl(flags []string, args ...string) (string, error) {
+	cmd := exec.Command("systemctl", append(flags, args...)...)
+	out, err := cmd.CombinedOutput()
+	return strings.TrimSpace(string(out)), err
+}
+
+func install(opts Options) error {
+	unitDir, flags, wantedBy, err := systemdScope()
+	if err != nil {
+		return err
+	}
+	unit, err := renderSystemdUnit(opts, wantedBy)
+	if err != nil {
+		return fmt.Errorf("failed to render unit: %w", err)
+	}
+
+	if err := os.MkdirAll(unitDir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", unitDir, err)
+	}
+	unitPath := filepath.Join(unitDir, Name+".service")
+	if err := os.WriteFile(unitPath, []byte(unit), 0644); err != nil {
+		return fmt.Errorf("failed to write unit file: %w", err)
+	}
+
+	if out, err := systemctl(flags, "daemon-reload"); err != nil {
+		return fmt.Errorf("systemctl daemon-reload failed: %v (%s)", err, out)
+	}
+	if out, err := systemctl(flags, "enable", "--now", Name); err != nil {
+		return fmt.Errorf("systemctl enable failed: %v (%s)", err, out)
+	}
+	return nil
+}
+
+func uninstall() error {
+	unitDir, flags, _, err := systemdScope()
+	if err != nil {
+		return err
+	}
+	// Disabling a unit that is already gone is not an error worth surfacing.
+	systemctl(flags, "disable", "--now", Name)
+
+	unitPath := filepath.Join(unitDir, Name+".service")
+	if err := os.Remove(unitPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove unit file: %w", err)
+	}
+	if out, err := systemctl(flags, "daemon-reload"); err != nil {
+		return fmt.Errorf("systemctl daemon-reload failed: %v (%s)", err, out)
+	}
+	return nil
+}
+
+func status() (string, error) {
+	unitDir, flags, _, err := systemdScope()
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(filepath.Join(unitDir, Name+".service")); os.IsNotExist(err) {
+		return "not installed", nil
+	}
+	// is-active exits non-zero for inactive units; the output is still meaningful.
+	active, _ := systemctl(flags, "is-active", Name)
+	enabled, _ := systemctl(flags, "is-enabled", Name)
+	return fmt.Sprintf("installed (%s, %s)", active, enabled), nil
+}
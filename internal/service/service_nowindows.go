@@ -0,0 +1,14 @@
+//go:build !windows
+
+package service
+
+import "context"
+
+// IsWindowsService always reports false outside Windows.
+func IsWindowsService() bool { return false }
+
+// Run executes fn directly; systemd and launchd supervise the process themselves.
+func Run(ctx context.Context, fn func(ctx context.Context)) error {
+	fn(ctx)
+	return nil
+}
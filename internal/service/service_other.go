@@ -0,0 +1,20 @@
+//go:build !linux && !darwin && !windows
+
+package service
+
+import (
+	"fmt"
+	"runtime"
+)
+
+func install(opts Options) error {
+	return fmt.Errorf("service management is not supported on %s", runtime.GOOS)
+}
+
+func uninstall() error {
+	return fmt.Errorf("service management is not supported on %s", runtime.GOOS)
+}
+
+func status() (string, error) {
+	return "", fmt.Errorf("service management is not supported on %s", runtime.GOOS)
+}
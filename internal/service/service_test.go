@@ -0,0 +1,57 @@
+package service
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderSystemdUnit(t *testing.T) {
+	opts := Options{
+		Executable: "/usr/local/bin/oci-arm-provisioner",
+		Args:       []string{"--headless", "--config", "/home/me/My Configs/config.yaml"},
+		WorkDir:    "/home/me",
+	}
+
+	unit, err := renderSystemdUnit(opts, "default.target")
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+
+	checks := []string{
+		`ExecStart=/usr/local/bin/oci-arm-provisioner --headless --config "/home/me/My Configs/config.yaml"`,
+		"WorkingDirectory=/home/me",
+		"WantedBy=default.target",
+		"Restart=always",
+	}
+	for _, want := range checks {
+		if !strings.Contains(unit, want) {
+			t.Errorf("unit missing %q\n%s", want, unit)
+		}
+	}
+}
+
+func TestRenderLaunchdPlist(t *testing.T) {
+	opts := Options{
+		Executable: "/usr/local/bin/oci-arm-provisioner",
+		Args:       []string{"--headless", "--config", "/Users/me/a&b/config.yaml"},
+		WorkDir:    "/Users/me",
+	}
+
+	plist, err := renderLaunchdPlist(opts, "/Users/me/Library/Logs/oci-arm-provisioner.log")
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+
+	checks := []string{
+		"<string>" + launchdLabel + "</string>",
+		"<string>/usr/local/bin/oci-arm-provisioner</string>",
+		"<string>--headless</string>",
+		"<string>/Users/me/a&amp;b/config.yaml</string>",
+		"<key>KeepAlive</key>",
+	}
+	for _, want := range checks {
+		if !strings.Contains(plist, want) {
+			t.Errorf("plist missing %q\n%s", want, plist)
+		}
+	}
+}
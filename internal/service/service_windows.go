@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/mgr"
+)
+
+func install(opts Options) error {
+	m, err := mgr.Connect()
+	if err != nil {
+		return fmt.Errorf("failed to connect to service manager (run as Administrator): %w", err)
+	}
+	defer m.Disconnect()
+
+	if s, err := m.OpenService(Name); err == nil {
+		s.Close()
+		return fmt.Errorf("service %s already installed", Name)
+	}
+
+	s, err := m.CreateService(Name, opts.Executable, mgr.Config{
+		DisplayName: "OCI ARM Provisioner",
+		Description: "Provisions Oracle Cloud Always Free ARM instances.",
+		StartType:   mgr.StartAutomatic,
+	}, opts.Args...)
+	if err != nil {
+		return fmt.Errorf("failed to create service: %w", err)
+	}
+	defer s.Close()
+
+	// Restart on failure after a minute, mirroring RestartSec=60 on systemd.
+	recovery := []mgr.RecoveryAction{
+		{Type: mgr.ServiceRestart, Delay: time.Minute},
+		{Type: mgr.ServiceRestart, Delay: time.Minute},
+		{Type: mgr.ServiceRestart, Delay: time.Minute},
+	}
+	if err := s.SetRecoveryActions(recovery, uint32((24 * time.Hour).Seconds())); err != nil {
+		return fmt.Errorf("failed to set recovery actions: %w", err)
+	}
+
+	if err := s.Start(); err != nil {
+		return fmt.Errorf("service created but failed to start: %w", err)
+	}
+	return nil
+}
+
+func uninstall() error {
+	m, err := mgr.Connect()
+	if err != nil {
+		return fmt.Errorf("failed to connect to service manager (run as Administrator): %w", err)
+	}
+	defer m.Disconnect()
+
+	s, err := m.OpenService(Name)
+	if err != nil {
+		return nil
+	}
+	defer s.Close()
+
+	// Stopping an already-stopped service fails harmlessly.
+	s.Control(svc.Stop)
+	if err := s.Delete(); err != nil {
+		return fmt.Errorf("failed to delete service: %w", err)
+	}
+	return nil
+}
+
+func status() (string, error) {
+	m, err := mgr.Connect()
+	if err != nil {
+		return "", fmt.Errorf("failed to connect to service manager: %w", err)
+	}
+	defer m.Disconnect()
+
+	s, err := m.OpenService(Name)
+	if err != nil {
+		return "not installed", nil
+	}
+	defer s.Close()
+
+	st, err := s.Query()
+	if err != nil {
+		return "", fmt.Errorf("failed to query service: %w", err)
+	}
+	switch st.State {
+	case svc.Running:
+		return "installed (running)", nil
+	case svc.Stopped:
+		return "installed (stopped)", nil
+	case svc.StartPending:
+		return "installed (starting)", nil
+	case svc.StopPending:
+		return "installed (stopping)", nil
+	}
+	return fmt.Sprintf("installed (state %d)", st.State), nil
+}
+
+// IsWindowsService reports whether the process was started by the Service Control Manager.
+func IsWindowsService() bool {
+	ok, err := svc.IsWindowsService()
+	return err == nil && ok
+}
+
+// Run executes fn under the Service Control Manager when started as a service,
+// cancelling its context when the SCM asks the service to stop.
+func Run(ctx context.Context, fn func(ctx context.Context)) error {
+	if !IsWindowsService() {
+		fn(ctx)
+		return nil
+	}
+	return svc.Run(Name, &handler{ctx: ctx, fn: fn})
+}
+
+type handler struct {
+	ctx context.Context
+	fn  func(ctx context.Context)
+}
+
+// Execute implements svc.Handler.
+func (h *handler) Execute(args []string, requests <-chan svc.ChangeRequest, changes chan<- svc.Status) (bool, uint32) {
+	const accepted = svc.AcceptStop | svc.AcceptShutdown
+	changes <- svc.Status{State: svc.StartPending}
+
+	ctx, cancel := context.WithCancel(h.ctx)
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		h.fn(ctx)
+		close(done)
+	}()
+
+	changes <- svc.Status{State: svc.Running, Accepts: accepted}
+
+	for {
+		select {
+		case <-done:
+			changes <- svc.Status{State: svc.StopPending}
+			return false, 0
+		case req := <-requests:
+			switch req.Cmd {
+			case svc.Interrogate:
+				changes <- req.CurrentStatus
+			case svc.Stop, svc.Shutdown:
+				changes <- svc.Status{State: svc.StopPending}
+				cancel()
+				<-done
+				return false, 0
+			}
+		}
+	}
+}
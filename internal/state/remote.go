@@ -0,0 +1,240 @@
+package state
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/objectstorage"
+)
+
+// defaultTimeout bounds each remote read or write.
+const defaultTimeout = 10 * time.Second
+
+// RedisBackend stores state as a single Redis key.
+type RedisBackend struct {
+	Addr     string // host:port
+	Username string // Redis 6 ACL user; empty for password-only AUTH.
+	Password string
+	DB       int
+	TLS      bool
+	Key      string
+	Timeout  time.Duration // Per operation (default 10s).
+}
+
+// NewRedisBackend parses a redis:// or rediss:// (TLS) URL.
+func NewRedisBackend(rawURL, key string) (*RedisBackend, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redis URL: %w", err)
+	}
+	if u.Scheme != "redis" && u.Scheme != "rediss" {
+		return nil, fmt.Errorf("invalid redis URL: scheme must be redis:// or rediss://")
+	}
+	r := &RedisBackend{Addr: u.Host, TLS: u.Scheme == "rediss", Key: key}
+	if u.Port() == "" {
+		r.Addr = net.JoinHostPort(u.Hostname(), "6379")
+	}
+	if u.User != nil {
+		r.Username = u.User.Username()
+		r.Password, _ = u.User.Password()
+	}
+	if db := strings.Trim(u.Path, "/"); db != "" {
+		if r.DB, err = strconv.Atoi(db); err != nil {
+			return nil, fmt.Errorf("invalid redis URL: database must be a number, got %q", db)
+		}
+	}
+	return r, nil
+}
+
+func (r *RedisBackend) Read() ([]byte, error) {
+	return r.do("GET", r.Key)
+}
+
+func (r *RedisBackend) Write(data []byte) error {
+	_, err := r.do("SET", r.Key, string(data))
+	return err
+}
+
+// do runs a command on a fresh connection and returns its reply. State changes are rare,
+// so a connection pool isn't worth keeping.
+func (r *RedisBackend) do(args ...string) ([]byte, error) {
+	timeout := r.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	dialer := &net.Dialer{Timeout: timeout}
+	var conn net.Conn
+	var err error
+	if r.TLS {
+		host, _, _ := net.SplitHostPort(r.Addr)
+		conn, err = tls.DialWithDialer(dialer, "tcp", r.Addr, &tls.Config{ServerName: host})
+	} else {
+		conn, err = dialer.Dial("tcp", r.Addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("redis: %w", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	var cmds [][]string
+	switch {
+	case r.Username != "" && r.Password != "":
+		cmds = append(cmds, []string{"AUTH", r.Username, r.Password})
+	case r.Password != "":
+		cmds = append(cmds, []string{"AUTH", r.Password})
+	}
+	if r.DB != 0 {
+		cmds = append(cmds, []string{"SELECT", strconv.Itoa(r.DB)})
+	}
+	cmds = append(cmds, args)
+
+	rd := bufio.NewReader(conn)
+	var reply []byte
+	for _, cmd := range cmds {
+		if err := writeCommand(conn, cmd); err != nil {
+			return nil, fmt.Errorf("redis: %w", err)
+		}
+		if reply, err = readReply(rd); err != nil {
+			return nil, err
+		}
+	}
+	return reply, nil
+}
+
+// writeCommand sends args as a RESP array of bulk strings.
+func writeCommand(w io.Writer, args []string) error {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, a := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(a), a)
+	}
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// readReply reads a simple string, integer, error or bulk string reply. A nil bulk string
+// (missing key) returns (nil, nil).
+func readReply(r *bufio.Reader) ([]byte, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, fmt.Errorf("redis: %w", err)
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, fmt.Errorf("redis: empty reply")
+	}
+	switch line[0] {
+	case '+', ':':
+		return []byte(line[1:]), nil
+	case '-':
+		return nil, fmt.Errorf("redis: %s", line[1:])
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("redis: bad bulk length %q", line)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, n+2) // payload + CRLF
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, fmt.Errorf("redis: %w", err)
+		}
+		return buf[:n], nil
+	}
+	return nil, fmt.Errorf("redis: unexpected reply %q", line)
+}
+
+// ObjectStore is the subset of the OCI Object Storage client used by ObjectStorageBackend.
+type ObjectStore interface {
+	GetNamespace(ctx context.Context, request objectstorage.GetNamespaceRequest) (objectstorage.GetNamespaceResponse, error)
+	GetObject(ctx context.Context, request objectstorage.GetObjectRequest) (objectstorage.GetObjectResponse, error)
+	PutObject(ctx context.Context, request objectstorage.PutObjectRequest) (objectstorage.PutObjectResponse, error)
+}
+
+// ObjectStorageBackend stores state as an object in an OCI Object Storage bucket.
+type ObjectStorageBackend struct {
+	Client    ObjectStore
+	Namespace string // Looked up on first use if empty.
+	Bucket    string
+	Object    string
+	Timeout   time.Duration // Per operation (default 10s).
+}
+
+func (o *ObjectStorageBackend) Read() ([]byte, error) {
+	ctx, cancel := o.context()
+	defer cancel()
+	ns, err := o.namespace(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := o.Client.GetObject(ctx, objectstorage.GetObjectRequest{
+		NamespaceName: common.String(ns),
+		BucketName:    common.String(o.Bucket),
+		ObjectName:    common.String(o.Object),
+	})
+	if serviceErr, ok := common.IsServiceError(err); ok && serviceErr.GetHTTPStatusCode() == 404 && serviceErr.GetCode() == "ObjectNotFound" {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("object storage: %w", err)
+	}
+	defer resp.Content.Close()
+	return io.ReadAll(resp.Content)
+}
+
+func (o *ObjectStorageBackend) Write(data []byte) error {
+	ctx, cancel := o.context()
+	defer cancel()
+	ns, err := o.namespace(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = o.Client.PutObject(ctx, objectstorage.PutObjectRequest{
+		NamespaceName: common.String(ns),
+		BucketName:    common.String(o.Bucket),
+		ObjectName:    common.String(o.Object),
+		ContentLength: common.Int64(int64(len(data))),
+		ContentType:   common.String("application/json"),
+		PutObjectBody: io.NopCloser(bytes.NewReader(data)),
+	})
+	if err != nil {
+		return fmt.Errorf("object storage: %w", err)
+	}
+	return nil
+}
+
+func (o *ObjectStorageBackend) context() (context.Context, context.CancelFunc) {
+	timeout := o.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	return context.WithTimeout(context.Background(), timeout)
+}
+
+// namespace returns the configured namespace, or the tenancy's, remembering it.
+func (o *ObjectStorageBackend) namespace(ctx context.Context) (string, error) {
+	if o.Namespace != "" {
+		return o.Namespace, nil
+	}
+	resp, err := o.Client.GetNamespace(ctx, objectstorage.GetNamespaceRequest{})
+	if err != nil {
+		return "", fmt.Errorf("object storage: looking up namespace: %w", err)
+	}
+	if resp.Value == nil {
+		return "", fmt.Errorf("object storage: empty namespace")
+	}
+	o.Namespace = *resp.Value
+	return o.Namespace, nil
+}
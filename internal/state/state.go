@@ -0,0 +1,477 @@
+package state
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Backend stores the serialized state blob.
+type Backend interface {
+	Read() ([]byte, error) // Returns (nil, nil) if nothing has been stored yet.
+	Write(data []byte) error
+}
+
+// State is runtime data that must survive restarts (e.g. naming sequences, attempt history).
+// All methods are safe for concurrent use; every mutation is written through to the backend.
+// A nil *State is valid and remembers nothing.
+type State struct {
+	mu       sync.Mutex
+	backend  Backend
+	ID       string                   `json:"install_id,omitempty"` // See InstallID.
+	Accounts map[string]*AccountState `json:"accounts"`
+}
+
+// AccountState holds the persisted data for a single account.
+type AccountState struct {
+	Sequence      int      `json:"sequence"`                // Last sequence number used for a launched instance.
+	Announcements []string `json:"announcements,omitempty"` // OCI announcement IDs already alerted on.
+
+	// Attempt history across all runs.
+	Attempts       int       `json:"attempts,omitempty"`
+	CapacityErrors int       `json:"capacity_errors,omitempty"`
+	LastAttempt    time.Time `json:"last_attempt,omitzero"`
+
+	// Capacity backoff (retry.exponential_backoff), so a restart doesn't retry at once.
+	Backoff *Backoff `json:"backoff,omitempty"`
+
+	// Paused until resumed, so a restart or reload doesn't start hunting again.
+	Paused bool `json:"paused,omitempty"`
+
+	// Delivery record of the last success notification.
+	LastNotification *Receipt `json:"last_notification,omitempty"`
+
+	// Instance IDs a success notification was sent for, so a restart never repeats one.
+	Notified []string `json:"notified,omitempty"`
+
+	// Instances launched by, or found tagged for, this account.
+	Instances []Instance `json:"instances,omitempty"`
+
+	// Boot volume a reclaimed instance left behind, for the next launch to start from.
+	BootVolume *BootVolume `json:"boot_volume,omitempty"`
+
+	// Outcomes of the post-launch verifications, oldest first (at most MaxVerifications).
+	Verifications []Verification `json:"verifications,omitempty"`
+}
+
+// Backoff is an account's wait after capacity misses in a row.
+type Backoff struct {
+	Misses int       `json:"misses"` // Cycles in a row that ended without capacity.
+	Until  time.Time `json:"until"`  // No attempt before this.
+}
+
+// MaxVerifications is how many verification outcomes are kept per account.
+const MaxVerifications = 20
+
+// Verification is how a launched instance came up.
+type Verification struct {
+	InstanceID      string    `json:"instance_id"`
+	At              time.Time `json:"at"`
+	Shape           string    `json:"shape,omitempty"`
+	RunningSeconds  float64   `json:"running_seconds,omitempty"`   // From launch to RUNNING; 0 if it never got there.
+	PublicIPSeconds float64   `json:"public_ip_seconds,omitempty"` // From launch to a public IP; 0 if none was seen.
+	SpecsMismatch   bool      `json:"specs_mismatch,omitempty"`
+	Error           string    `json:"error,omitempty"` // Why the verification failed.
+}
+
+// Instance is a tool-managed instance of an account.
+type Instance struct {
+	ID       string `json:"id"`
+	Name     string `json:"name,omitempty"`
+	PublicIP string `json:"public_ip,omitempty"`
+
+	// Where its boot volume is, recorded for reclaim.reuse_boot_volume.
+	BootVolumeID       string `json:"boot_volume_id,omitempty"`
+	AvailabilityDomain string `json:"availability_domain,omitempty"`
+
+	// Slice is set on an instance launched smaller by split_on_capacity, which is
+	// replaced by the full size once there is capacity for it.
+	Slice bool `json:"slice,omitempty"`
+}
+
+// BootVolume is the boot volume of an instance that is gone.
+type BootVolume struct {
+	ID                 string `json:"id"`
+	AvailabilityDomain string `json:"availability_domain"`
+	Instance           string `json:"instance"` // Name of the instance it came from.
+}
+
+// Receipt records what happened to a success notification on each provider.
+type Receipt struct {
+	InstanceID string     `json:"instance_id"`
+	SentAt     time.Time  `json:"sent_at"`
+	Deliveries []Delivery `json:"deliveries"`
+}
+
+// Delivery is one provider's outcome: delivered, failed or acknowledged.
+type Delivery struct {
+	Provider string    `json:"provider"`
+	Status   string    `json:"status"`
+	Attempts int       `json:"attempts"`
+	Error    string    `json:"error,omitempty"`
+	At       time.Time `json:"at"`
+}
+
+// Open loads state from backend. A nil backend keeps state in memory only.
+func Open(backend Backend) (*State, error) {
+	s := &State{backend: backend, Accounts: make(map[string]*AccountState)}
+	if backend == nil {
+		return s, nil
+	}
+
+	data, err := backend.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading state: %w", err)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, s); err != nil {
+			return nil, fmt.Errorf("parsing state: %w", err)
+		}
+		if s.Accounts == nil {
+			s.Accounts = make(map[string]*AccountState)
+		}
+	}
+	return s, nil
+}
+
+// account returns the entry for name, creating it if needed. Caller holds mu.
+func (s *State) account(name string) *AccountState {
+	acc, ok := s.Accounts[name]
+	if !ok {
+		acc = &AccountState{}
+		s.Accounts[name] = acc
+	}
+	return acc
+}
+
+// save writes the current state to the backend. Caller holds mu.
+func (s *State) save() error {
+	if s.backend == nil {
+		return nil
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return s.backend.Write(data)
+}
+
+// Sequence returns the last sequence number committed for an account (0 if none).
+func (s *State) Sequence(account string) int {
+	if s == nil {
+		return 0
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if acc, ok := s.Accounts[account]; ok {
+		return acc.Sequence
+	}
+	return 0
+}
+
+// CommitSequence records seq as used for an account.
+func (s *State) CommitSequence(account string, seq int) error {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.account(account).Sequence = seq
+	return s.save()
+}
+
+// InstallID returns a random identifier for this deployment, creating it on first use.
+// It is sent in the User-Agent so Oracle support (and you) can tell deployments apart.
+func (s *State) InstallID() (string, error) {
+	if s == nil {
+		return "", nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ID != "" {
+		return s.ID, nil
+	}
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	s.ID = hex.EncodeToString(b)
+	return s.ID, s.save()
+}
+
+// MarkAnnouncement records an announcement as alerted and reports whether it was new.
+// A nil State treats every announcement as new.
+func (s *State) MarkAnnouncement(account, id string) (bool, error) {
+	if s == nil {
+		return true, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	acc := s.account(account)
+	for _, seen := range acc.Announcements {
+		if seen == id {
+			return false, nil
+		}
+	}
+	acc.Announcements = append(acc.Announcements, id)
+	return true, s.save()
+}
+
+// RecordAttempt adds a launch attempt to the account's history.
+func (s *State) RecordAttempt(account string, capacity bool, at time.Time) error {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	acc := s.account(account)
+	acc.Attempts++
+	if capacity {
+		acc.CapacityErrors++
+	}
+	acc.LastAttempt = at
+	return s.save()
+}
+
+// SetBackoff records the account's capacity backoff, or clears it with nil.
+func (s *State) SetBackoff(account string, b *Backoff) error {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.account(account).Backoff = b
+	return s.save()
+}
+
+// Backoff returns the account's capacity backoff.
+func (s *State) Backoff(account string) (Backoff, bool) {
+	if s == nil {
+		return Backoff{}, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	acc, ok := s.Accounts[account]
+	if !ok || acc.Backoff == nil {
+		return Backoff{}, false
+	}
+	return *acc.Backoff, true
+}
+
+// SetPaused records whether the account is paused.
+func (s *State) SetPaused(account string, paused bool) error {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.account(account).Paused = paused
+	return s.save()
+}
+
+// Paused reports whether the account was left paused.
+func (s *State) Paused(account string) bool {
+	if s == nil {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	acc, ok := s.Accounts[account]
+	return ok && acc.Paused
+}
+
+// RecordInstance adds or updates an instance of an account and reports whether it was
+// not known before.
+func (s *State) RecordInstance(account string, inst Instance) (bool, error) {
+	if s == nil {
+		return true, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	acc := s.account(account)
+	for i, known := range acc.Instances {
+		if known.ID == inst.ID {
+			if known == inst {
+				return false, nil
+			}
+			acc.Instances[i] = inst
+			return false, s.save()
+		}
+	}
+	acc.Instances = append(acc.Instances, inst)
+	return true, s.save()
+}
+
+// ForgetInstance removes a terminated instance of an account.
+func (s *State) ForgetInstance(account, id string) error {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	acc := s.account(account)
+	for i, known := range acc.Instances {
+		if known.ID == id {
+			acc.Instances = append(acc.Instances[:i], acc.Instances[i+1:]...)
+			return s.save()
+		}
+	}
+	return nil
+}
+
+// Instances returns the instances recorded for an account.
+func (s *State) Instances(account string) []Instance {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if acc, ok := s.Accounts[account]; ok {
+		return append([]Instance(nil), acc.Instances...)
+	}
+	return nil
+}
+
+// SetBootVolume records the boot volume the account's next launch starts from, or
+// clears it with nil.
+func (s *State) SetBootVolume(account string, v *BootVolume) error {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.account(account).BootVolume = v
+	return s.save()
+}
+
+// BootVolume returns the boot volume the account's next launch starts from.
+func (s *State) BootVolume(account string) (BootVolume, bool) {
+	if s == nil {
+		return BootVolume{}, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	acc, ok := s.Accounts[account]
+	if !ok || acc.BootVolume == nil {
+		return BootVolume{}, false
+	}
+	return *acc.BootVolume, true
+}
+
+// RecordVerification adds the outcome of a verification to the account's history,
+// dropping the oldest beyond MaxVerifications.
+func (s *State) RecordVerification(account string, v Verification) error {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	acc := s.account(account)
+	acc.Verifications = append(acc.Verifications, v)
+	if n := len(acc.Verifications); n > MaxVerifications {
+		acc.Verifications = append([]Verification(nil), acc.Verifications[n-MaxVerifications:]...)
+	}
+	return s.save()
+}
+
+// Verifications returns the verification history of an account, oldest first.
+func (s *State) Verifications(account string) []Verification {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if acc, ok := s.Accounts[account]; ok {
+		return append([]Verification(nil), acc.Verifications...)
+	}
+	return nil
+}
+
+// SetNotification records the delivery of an account's latest success notification.
+func (s *State) SetNotification(account string, r Receipt) error {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.account(account).LastNotification = &r
+	return s.save()
+}
+
+// MarkNotified records that the success notification for an instance was sent.
+func (s *State) MarkNotified(account, id string) error {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	acc := s.account(account)
+	if slices.Contains(acc.Notified, id) {
+		return nil
+	}
+	acc.Notified = append(acc.Notified, id)
+	return s.save()
+}
+
+// Notified reports whether a success notification was sent for an instance. State
+// written before MarkNotified existed still has the last notification's receipt.
+func (s *State) Notified(account, id string) bool {
+	if s == nil {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	acc, ok := s.Accounts[account]
+	if !ok {
+		return false
+	}
+	return slices.Contains(acc.Notified, id) || (acc.LastNotification != nil && acc.LastNotification.InstanceID == id)
+}
+
+// Notification returns the delivery record of an account's latest success notification.
+func (s *State) Notification(account string) (Receipt, bool) {
+	if s == nil {
+		return Receipt{}, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	acc, ok := s.Accounts[account]
+	if !ok || acc.LastNotification == nil {
+		return Receipt{}, false
+	}
+	r := *acc.LastNotification
+	r.Deliveries = append([]Delivery(nil), r.Deliveries...)
+	return r, true
+}
+
+// FileBackend stores state as JSON on the local disk.
+type FileBackend struct {
+	Path string
+}
+
+func (f FileBackend) Read() ([]byte, error) {
+	data, err := os.ReadFile(f.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return data, err
+}
+
+// Write replaces the file atomically so a crash never leaves half-written state.
+func (f FileBackend) Write(data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(f.Path), 0755); err != nil {
+		return err
+	}
+	tmp := f.Path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, f.Path)
+}
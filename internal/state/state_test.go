@@ -0,0 +1,183 @@
+package state
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestState_PersistsSequences(t *testing.T) {
+	backend := FileBackend{Path: filepath.Join(t.TempDir(), "state.json")}
+
+	s, err := Open(backend)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if got := s.Sequence("acc"); got != 0 {
+		t.Errorf("expected empty sequence, got %d", got)
+	}
+	if err := s.CommitSequence("acc", 3); err != nil {
+		t.Fatalf("CommitSequence: %v", err)
+	}
+
+	reopened, err := Open(backend)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if got := reopened.Sequence("acc"); got != 3 {
+		t.Errorf("expected persisted sequence 3, got %d", got)
+	}
+}
+
+func TestState_Backoff(t *testing.T) {
+	backend := FileBackend{Path: filepath.Join(t.TempDir(), "state.json")}
+	s, _ := Open(backend)
+	until := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	if err := s.SetBackoff("acc", &Backoff{Misses: 3, Until: until}); err != nil {
+		t.Fatalf("SetBackoff: %v", err)
+	}
+
+	reopened, _ := Open(backend)
+	if b, ok := reopened.Backoff("acc"); !ok || b.Misses != 3 || !b.Until.Equal(until) {
+		t.Errorf("expected the persisted backoff, got %+v, %v", b, ok)
+	}
+	reopened.SetBackoff("acc", nil)
+	if _, ok := reopened.Backoff("acc"); ok {
+		t.Error("expected the backoff cleared")
+	}
+}
+
+func TestState_Paused(t *testing.T) {
+	backend := FileBackend{Path: filepath.Join(t.TempDir(), "state.json")}
+	s, _ := Open(backend)
+	if err := s.SetPaused("acc", true); err != nil {
+		t.Fatalf("SetPaused: %v", err)
+	}
+
+	reopened, _ := Open(backend)
+	if !reopened.Paused("acc") || reopened.Paused("other") {
+		t.Error("expected only acc to stay paused")
+	}
+	reopened.SetPaused("acc", false)
+	if reopened.Paused("acc") {
+		t.Error("expected acc resumed")
+	}
+}
+
+func TestState_MemoryOnly(t *testing.T) {
+	s, err := Open(nil)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if err := s.CommitSequence("acc", 1); err != nil {
+		t.Fatalf("CommitSequence: %v", err)
+	}
+	if got := s.Sequence("acc"); got != 1 {
+		t.Errorf("expected 1, got %d", got)
+	}
+}
+
+func TestState_MarkAnnouncement(t *testing.T) {
+	backend := FileBackend{Path: filepath.Join(t.TempDir(), "state.json")}
+	s, _ := Open(backend)
+
+	if isNew, err := s.MarkAnnouncement("acc", "ann-1"); err != nil || !isNew {
+		t.Fatalf("expected first mark to be new, got %v, %v", isNew, err)
+	}
+
+	reopened, _ := Open(backend)
+	if isNew, _ := reopened.MarkAnnouncement("acc", "ann-1"); isNew {
+		t.Error("expected announcement to be remembered across restarts")
+	}
+	if isNew, _ := reopened.MarkAnnouncement("other", "ann-1"); !isNew {
+		t.Error("expected announcements to be tracked per account")
+	}
+}
+
+func TestState_InstallID(t *testing.T) {
+	backend := FileBackend{Path: filepath.Join(t.TempDir(), "state.json")}
+	s, _ := Open(backend)
+
+	id, err := s.InstallID()
+	if err != nil || len(id) != 8 {
+		t.Fatalf("expected an 8-char install ID, got %q, %v", id, err)
+	}
+	reopened, _ := Open(backend)
+	if again, _ := reopened.InstallID(); again != id {
+		t.Errorf("expected install ID to persist, got %q then %q", id, again)
+	}
+}
+
+func TestState_RecordAttempt(t *testing.T) {
+	backend := FileBackend{Path: filepath.Join(t.TempDir(), "state.json")}
+	s, _ := Open(backend)
+	at := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	s.RecordAttempt("acc", true, at)
+	s.RecordAttempt("acc", false, at.Add(time.Minute))
+
+	reopened, _ := Open(backend)
+	acc := reopened.Accounts["acc"]
+	if acc == nil || acc.Attempts != 2 || acc.CapacityErrors != 1 || !acc.LastAttempt.Equal(at.Add(time.Minute)) {
+		t.Errorf("unexpected history after reopen: %+v", acc)
+	}
+}
+
+func TestState_RecordInstance(t *testing.T) {
+	s, _ := Open(nil)
+	inst := Instance{ID: "ocid1.instance.a", Name: "arm-1"}
+
+	if isNew, _ := s.RecordInstance("acc", inst); !isNew {
+		t.Error("expected first record to be new")
+	}
+	inst.PublicIP = "203.0.113.1"
+	if isNew, _ := s.RecordInstance("acc", inst); isNew {
+		t.Error("expected an update of a known instance not to be new")
+	}
+	if got := s.Instances("acc"); len(got) != 1 || got[0].PublicIP != "203.0.113.1" {
+		t.Errorf("expected one updated instance, got %+v", got)
+	}
+
+	s.ForgetInstance("acc", inst.ID)
+	if got := s.Instances("acc"); len(got) != 0 {
+		t.Errorf("expected the instance to be forgotten, got %+v", got)
+	}
+}
+
+func TestState_RecordVerification(t *testing.T) {
+	backend := FileBackend{Path: filepath.Join(t.TempDir(), "state.json")}
+	s, _ := Open(backend)
+	for i := range MaxVerifications + 2 {
+		s.RecordVerification("acc", Verification{InstanceID: fmt.Sprintf("i%d", i), RunningSeconds: 40})
+	}
+
+	reopened, _ := Open(backend)
+	got := reopened.Verifications("acc")
+	if len(got) != MaxVerifications || got[0].InstanceID != "i2" || got[len(got)-1].InstanceID != fmt.Sprintf("i%d", MaxVerifications+1) {
+		t.Errorf("expected the newest %d verifications, got %+v", MaxVerifications, got)
+	}
+}
+
+func TestState_Notified(t *testing.T) {
+	backend := FileBackend{Path: filepath.Join(t.TempDir(), "state.json")}
+	s, _ := Open(backend)
+
+	if s.Notified("acc", "ocid1.instance.a") {
+		t.Error("expected nothing notified yet")
+	}
+	if err := s.MarkNotified("acc", "ocid1.instance.a"); err != nil {
+		t.Fatal(err)
+	}
+	s.SetNotification("acc", Receipt{InstanceID: "ocid1.instance.b"})
+
+	reopened, _ := Open(backend)
+	for id, want := range map[string]bool{"ocid1.instance.a": true, "ocid1.instance.b": true, "ocid1.instance.c": false} {
+		if got := reopened.Notified("acc", id); got != want {
+			t.Errorf("%s: expected notified %v across restarts, got %v", id, want, got)
+		}
+	}
+	if reopened.Notified("other", "ocid1.instance.a") {
+		t.Error("expected notifications to be tracked per account")
+	}
+}
@@ -0,0 +1,58 @@
+// Package statusfile writes one small status file per account into status_dir, for
+// dashboards (Homepage, Glance, scripts) that read files instead of calling the API.
+package statusfile
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/yourusername/oci-arm-provisioner/internal/api"
+	"gopkg.in/yaml.v3"
+)
+
+// File is the content of an account's status file.
+type File struct {
+	api.Account `yaml:",inline"`
+	Paused      bool      `json:"paused" yaml:"paused"` // Everything is paused, not only this account.
+	Updated     time.Time `json:"updated" yaml:"updated"`
+}
+
+// Write replaces <dir>/<account>.<format> for each account in s. Each file is written
+// to a temporary name first, so a dashboard never reads half a file.
+func Write(dir, format string, s api.Status) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	for _, a := range s.Accounts {
+		f := File{Account: a, Paused: s.Paused, Updated: now}
+		var data []byte
+		var err error
+		if format == "yaml" {
+			data, err = yaml.Marshal(f)
+		} else {
+			data, err = json.MarshalIndent(f, "", "  ")
+			data = append(data, '\n')
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", a.Name, err)
+		}
+		path := filepath.Join(dir, fileName(a.Name)+"."+format)
+		if err := os.WriteFile(path+".tmp", data, 0644); err != nil {
+			return err
+		}
+		if err := os.Rename(path+".tmp", path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fileName keeps an account name from reaching outside the directory.
+func fileName(account string) string {
+	return strings.NewReplacer("/", "_", `\`, "_").Replace(account)
+}
@@ -0,0 +1,52 @@
+package statusfile
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourusername/oci-arm-provisioner/internal/api"
+)
+
+func TestWrite(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "status")
+	attempt := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	s := api.Status{Accounts: []api.Account{
+		{Name: "work", State: api.StateHunting, Region: "us-ashburn-1", Attempts: 3, LastAttempt: attempt, LastError: "Out of host capacity"},
+		{Name: "../home", State: api.StateProvisioned, InstanceID: "ocid1.instance.oc1..a", PublicIP: "203.0.113.7"},
+	}}
+
+	if err := Write(dir, "json", s); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "work.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var f File
+	if err := json.Unmarshal(data, &f); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, data)
+	}
+	if f.State != api.StateHunting || f.Attempts != 3 || !f.LastAttempt.Equal(attempt) || f.Updated.IsZero() {
+		t.Errorf("unexpected status file %+v", f)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".._home.json")); err != nil {
+		t.Errorf("expected the account name kept inside the directory: %v", err)
+	}
+
+	if err := Write(dir, "yaml", s); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	data, _ = os.ReadFile(filepath.Join(dir, ".._home.yaml"))
+	for _, want := range []string{"state: provisioned", "public_ip: 203.0.113.7", "paused: false"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("expected %q in the YAML file:\n%s", want, data)
+		}
+	}
+	if strings.Contains(string(data), "last_attempt") {
+		t.Errorf("expected no last_attempt before the first attempt:\n%s", data)
+	}
+}
@@ -0,0 +1,109 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/yourusername/oci-arm-provisioner/internal/logger"
+	"github.com/yourusername/oci-arm-provisioner/internal/remedy"
+)
+
+// accessibleLogLines is how much recent activity the accessible dashboard shows.
+const accessibleLogLines = 5
+
+// viewAccessible renders the screen as plain labeled lines for screen readers and
+// braille displays: no colors, borders or emoji, and every status spelled out.
+func (m Model) viewAccessible() string {
+	var b strings.Builder
+	state := "running"
+	if m.Paused {
+		state = "paused"
+	}
+	fmt.Fprintf(&b, "OCI ARM Provisioner, %s. Uptime %s. Cycle %d.\n\n",
+		state, time.Since(m.StartTime).Round(time.Second), m.TotalCycles)
+	if len(m.Successes) > 0 {
+		b.WriteString(m.accessibleSuccess())
+	}
+
+	switch m.CurrentView {
+	case ViewDashboard:
+		b.WriteString(m.accessibleDashboard())
+	case ViewLogs:
+		b.WriteString("Logs, newest last.\n")
+		for _, l := range m.Logs[max(0, len(m.Logs)-max(accessibleLogLines, m.Height-6)):] {
+			b.WriteString(accessibleLog(l) + "\n")
+		}
+	case ViewConfig:
+		b.WriteString(m.viewConfig())
+	case ViewHelp:
+		b.WriteString(m.viewHelp())
+	case ViewRetarget:
+		b.WriteString(m.viewRetarget())
+	}
+
+	keys := "Keys: question mark for help, d dashboard, l logs, p pause, r resume, q quit."
+	return logger.PlainText(strings.TrimRight(b.String(), "\n")) + "\n\n" + keys
+}
+
+// accessibleDashboard lists the stats, schedule, every account and the latest activity.
+func (m Model) accessibleDashboard() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Provisioned %d. Capacity errors %d. Cycles %d.\n", m.SuccessCount, m.CapacityErrors, m.TotalCycles)
+	fmt.Fprintf(&b, "Schedule: cycle every %d seconds, %d seconds between accounts", m.Intervals.Cycle, m.Intervals.Delay)
+	if m.Intervals.Jitter > 0 {
+		fmt.Fprintf(&b, ", each moved randomly by up to %d percent", m.Intervals.Jitter)
+	}
+	b.WriteString(".\n\n")
+
+	fmt.Fprintf(&b, "Accounts: %d.\n", len(m.Accounts))
+	for i, acc := range m.Accounts {
+		fields := []string{fmt.Sprintf("%d. %s, %s", i+1, acc.Name, acc.State)}
+		if i == m.SelectedIdx {
+			fields[0] += ", selected"
+		}
+		if acc.Notes != "" {
+			fields = append(fields, "Notes: "+acc.Notes)
+		}
+		ad := acc.AvailabilityDomain
+		if ad == "" {
+			ad = "auto"
+		}
+		fields = append(fields,
+			fmt.Sprintf("Region %s, AD %s", acc.Region, ad),
+			fmt.Sprintf("%.0f OCPU, %.0f GB", acc.OCPUs, acc.MemoryGB),
+			fmt.Sprintf("Capacity errors %d", acc.CapacityHits),
+		)
+		if acc.PublicIP != "" {
+			fields = append(fields, "Public IP "+acc.PublicIP)
+		}
+		if acc.LastError != "" {
+			fields = append(fields, "Last error: "+acc.LastError)
+			if r, ok := remedy.Lookup(acc.LastError); ok {
+				fields = append(fields, "How to fix: "+r.String())
+			}
+		}
+		if acc.Delivery != "" {
+			fields = append(fields, "Notification: "+acc.Delivery)
+		}
+		// Clean each field so a removed emoji doesn't leave "notes ." behind
+		for j := range fields {
+			fields[j] = logger.PlainText(fields[j])
+		}
+		b.WriteString(strings.Join(fields, ". ") + ".\n")
+	}
+
+	b.WriteString("\nRecent activity:\n")
+	if len(m.Logs) == 0 {
+		b.WriteString("None yet.\n")
+	}
+	for _, l := range m.Logs[max(0, len(m.Logs)-accessibleLogLines):] {
+		b.WriteString(accessibleLog(l) + "\n")
+	}
+	return b.String()
+}
+
+// accessibleLog formats a log entry like the accessible console output.
+func accessibleLog(l LogEntry) string {
+	return logger.AccessibleLine(l.Time.Format("15:04:05"), l.Level, l.Account, l.Message)
+}
@@ -0,0 +1,166 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/yourusername/oci-arm-provisioner/internal/config"
+	"github.com/yourusername/oci-arm-provisioner/internal/logger"
+)
+
+// Success is an instance the runner just launched, shown in an overlay until dismissed.
+type Success struct {
+	Account    string
+	InstanceID string
+	PublicIP   string
+	Specs      string
+	SSH        string // e.g. "ssh ubuntu@203.0.113.7"; empty without a public IP.
+}
+
+// Escalation while a success overlay is open: the bell keeps ringing until a key is
+// pressed, for about a minute at most.
+const (
+	bellInterval = 3 * time.Second
+	bellRepeats  = 20
+	flashes      = 3
+	flashLength  = 120 * time.Millisecond
+)
+
+// alertOut receives the bells and screen flashes. Bubbletea renders to stdout, so they
+// go to stderr, which is the same terminal. Tests override it.
+var alertOut io.Writer = os.Stderr
+
+// successMsg is sent when the runner verified a new instance.
+type successMsg Success
+
+// bellMsg rings the next bell of an open success overlay.
+type bellMsg struct{}
+
+// successCmd waits for the next launched instance.
+func successCmd(successChan <-chan Success) tea.Cmd {
+	return func() tea.Msg {
+		return successMsg(<-successChan)
+	}
+}
+
+// sshCommand is how to log in to a new instance of acc, guessing the user from the
+// image alias. Platform images use "opc", Ubuntu ones "ubuntu".
+func sshCommand(acc *config.AccountConfig, ip string) string {
+	if ip == "" {
+		return ""
+	}
+	user := "<user>"
+	if alias, err := config.ParseImageAlias(acc.Image); err == nil {
+		user = "opc"
+		if alias.OperatingSystem == "Canonical Ubuntu" {
+			user = "ubuntu"
+		}
+	}
+	return fmt.Sprintf("ssh %s@%s", user, ip)
+}
+
+// celebrate opens the overlay for s and, unless celebration.silent is set, starts the
+// bells and flashes the screen.
+func (m Model) celebrate(s Success) (Model, tea.Cmd) {
+	m.Successes = append(m.Successes, s)
+	if m.Config.Celebration.Silent {
+		return m, nil
+	}
+	ringing := m.bellsLeft > 0
+	m.bellsLeft = bellRepeats
+	cmds := []tea.Cmd{ring}
+	if !m.Accessible {
+		cmds = append(cmds, flash)
+	}
+	if !ringing {
+		cmds = append(cmds, nextBell())
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// updateBell rings again while an overlay is open.
+func (m Model) updateBell() (Model, tea.Cmd) {
+	if len(m.Successes) == 0 || m.bellsLeft <= 0 {
+		m.bellsLeft = 0
+		return m, nil
+	}
+	m.bellsLeft--
+	return m, tea.Batch(ring, nextBell())
+}
+
+// dismissSuccess closes the oldest overlay; the bell stops with the last one.
+func (m Model) dismissSuccess() Model {
+	m.Successes = m.Successes[1:]
+	if len(m.Successes) == 0 {
+		m.bellsLeft = 0
+	}
+	return m
+}
+
+func nextBell() tea.Cmd {
+	return tea.Tick(bellInterval, func(time.Time) tea.Msg { return bellMsg{} })
+}
+
+// ring writes a terminal bell.
+func ring() tea.Msg {
+	fmt.Fprint(alertOut, "\a")
+	return nil
+}
+
+// flash inverts the screen a few times (the terminal's visual bell).
+func flash() tea.Msg {
+	for i := 0; i < flashes; i++ {
+		fmt.Fprint(alertOut, "\x1b[?5h")
+		time.Sleep(flashLength)
+		fmt.Fprint(alertOut, "\x1b[?5l")
+		time.Sleep(flashLength)
+	}
+	return nil
+}
+
+// viewSuccess renders the oldest open success overlay in the middle of the screen.
+func (m Model) viewSuccess() string {
+	s := m.Successes[0]
+	ip := s.PublicIP
+	if ip == "" {
+		ip = "(no public IP yet)"
+	}
+	rows := [][2]string{{"Account", s.Account}, {"Instance", s.InstanceID}, {"Public IP", ip}, {"Specs", s.Specs}}
+	if s.SSH != "" {
+		rows = append(rows, [2]string{"SSH", s.SSH})
+	}
+
+	var b strings.Builder
+	b.WriteString(m.Styles.StatusProvisioned.Render("🚀🎉 INSTANCE PROVISIONED! 🎉🚀") + "\n\n")
+	for _, r := range rows {
+		b.WriteString(m.Styles.Label.Render(fmt.Sprintf("%-10s ", r[0])) + m.Styles.Value.Render(r[1]) + "\n")
+	}
+	hint := "press any key to close"
+	if more := len(m.Successes) - 1; more > 0 {
+		hint = fmt.Sprintf("press any key for the next one (%d more)", more)
+	}
+	b.WriteString("\n" + m.Styles.Muted.Render(hint))
+
+	box := m.Styles.CardSuccess.Width(0).MarginRight(0).MarginBottom(0).Render(b.String())
+	return lipgloss.Place(max(0, m.Width-8), max(0, m.Height-10), lipgloss.Center, lipgloss.Center, box)
+}
+
+// accessibleSuccess is the overlay as plain text.
+func (m Model) accessibleSuccess() string {
+	s := m.Successes[0]
+	fields := []string{"Instance provisioned for " + s.Account, "Instance " + s.InstanceID}
+	if s.PublicIP != "" {
+		fields = append(fields, "Public IP "+s.PublicIP)
+	}
+	fields = append(fields, s.Specs)
+	if s.SSH != "" {
+		fields = append(fields, "Log in with "+s.SSH)
+	}
+	return logger.PlainText(strings.Join(fields, ". ")) + ". Press any key to close.\n\n"
+}
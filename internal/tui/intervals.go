@@ -0,0 +1,104 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/yourusername/oci-arm-provisioner/internal/config"
+)
+
+// intervalSaveDelay batches quick +/- presses into a single config write.
+const intervalSaveDelay = 2 * time.Second
+
+// intervals are the scheduler timings, in seconds.
+type intervals struct {
+	Cycle  int // cycle_interval_seconds
+	Delay  int // account_delay_seconds
+	Jitter int // jitter_percent; shown only, not adjusted by keys
+}
+
+// saveIntervalsMsg fires after the last change; stale ones (older seq) are ignored.
+type saveIntervalsMsg struct{ seq int }
+
+// intervalsSavedMsg reports the outcome of persisting the intervals.
+type intervalsSavedMsg struct {
+	Intervals intervals
+	Err       error
+}
+
+// SetIntervals applies new scheduler timings. The cycle ticker restarts with the new
+// interval as soon as the runner is between cycles; the account delay is used from the
+// next wait between accounts.
+func (r *ProvisionerRunner) SetIntervals(iv intervals) {
+	// Keep only the latest change if the runner hasn't picked up the previous one
+	select {
+	case <-r.intervalChan:
+	default:
+	}
+	r.intervalChan <- iv
+}
+
+// step is how much one keypress changes an interval of v seconds.
+func step(v int) int {
+	switch {
+	case v < 60:
+		return 5
+	case v < 600:
+		return 30
+	default:
+		return 60
+	}
+}
+
+// adjustIntervals applies a +/- keypress and schedules the config write.
+func (m Model) adjustIntervals(cycle, delay int) (Model, tea.Cmd) {
+	iv := m.Intervals
+	switch {
+	case cycle > 0:
+		iv.Cycle += step(iv.Cycle)
+	case cycle < 0:
+		iv.Cycle = max(config.MinCycleInterval, iv.Cycle-step(iv.Cycle-1))
+	case delay > 0:
+		iv.Delay += step(iv.Delay)
+	case delay < 0:
+		iv.Delay = max(0, iv.Delay-step(iv.Delay-1))
+	}
+	if iv == m.Intervals || m.Runner == nil {
+		return m, nil
+	}
+
+	m.Intervals = iv
+	m.Runner.SetIntervals(iv)
+	m.intervalsSeq++
+	seq := m.intervalsSeq
+	return m, tea.Tick(intervalSaveDelay, func(time.Time) tea.Msg { return saveIntervalsMsg{seq} })
+}
+
+// saveIntervals writes the intervals to config.yaml once the user stops adjusting them.
+func (m Model) saveIntervals(msg saveIntervalsMsg) tea.Cmd {
+	if msg.seq != m.intervalsSeq || m.ConfigPath == "" {
+		return nil
+	}
+	iv, path := m.Intervals, m.ConfigPath
+	return func() tea.Msg {
+		err := config.SetSchedulerFields(path, []config.AccountField{
+			{Key: "cycle_interval_seconds", Value: iv.Cycle},
+			{Key: "account_delay_seconds", Value: iv.Delay},
+		}, "tui scheduler change")
+		return intervalsSavedMsg{Intervals: iv, Err: err}
+	}
+}
+
+// logIntervalsSaved reports a config write in the log pane.
+func (m Model) logIntervalsSaved(msg intervalsSavedMsg) {
+	if m.Runner == nil {
+		return
+	}
+	if msg.Err != nil {
+		m.Runner.Logger.Error("SCHEDULER", fmt.Sprintf("Failed to save intervals: %v", msg.Err))
+		return
+	}
+	m.Runner.Logger.Success("SCHEDULER", fmt.Sprintf("Saved cycle %ds / account delay %ds to config", msg.Intervals.Cycle, msg.Intervals.Delay))
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/yourusername/oci-arm-provisioner/internal/remedy"
 )
 
 // viewDashboard renders the main dashboard using a split-view layout
@@ -79,6 +80,12 @@ func (m Model) renderAccountsList(width, height int) string {
 		case "waiting":
 			statusStyle = m.Styles.StatusWaiting
 			icon = IconWaiting
+		case "paused":
+			statusStyle = m.Styles.Muted
+			icon = IconPaused
+		case "limited":
+			statusStyle = m.Styles.StatusError
+			icon = IconLimited
 		case "error":
 			statusStyle = m.Styles.StatusError
 			icon = IconError
@@ -87,6 +94,10 @@ func (m Model) renderAccountsList(width, height int) string {
 		}
 
 		row := fmt.Sprintf("%s%s %s", cursor, statusStyle.Render(icon), style.Render(acc.Name))
+		if acc.Notes != "" {
+			// Notes are a hint; cut them rather than wrap the row
+			row = lipgloss.NewStyle().MaxWidth(max(1, width-6)).Render(row + " " + m.Styles.Muted.Render(acc.Notes))
+		}
 		rows = append(rows, row)
 	}
 
@@ -114,14 +125,22 @@ func (m Model) renderDetailsPane(width, height int) string {
 		acc := m.Accounts[m.SelectedIdx]
 
 		title := m.Styles.Title.Render(acc.Name)
+		if acc.Notes != "" {
+			title = lipgloss.JoinVertical(lipgloss.Left, title, m.Styles.Muted.Render(acc.Notes))
+		}
 
 		grid := []string{
 			fmt.Sprintf("%s %s", m.Styles.Label.Render("Region:"), m.Styles.Value.Render(acc.Region)),
+			fmt.Sprintf("%s %s", m.Styles.Label.Render("AD:    "), m.Styles.Value.Render(acc.AvailabilityDomain)),
 			fmt.Sprintf("%s %s", m.Styles.Label.Render("Status:"), m.renderStatusBadge(acc.State)),
 			fmt.Sprintf("%s %s", m.Styles.Label.Render("Specs: "), m.Styles.Value.Render(fmt.Sprintf("%.0f OCPU / %.0f GB", acc.OCPUs, acc.MemoryGB))),
 			"",
 			fmt.Sprintf("%s %d", m.Styles.Label.Render("Errors:"), acc.CapacityHits),
 		}
+		if acc.Delivery != "" {
+			grid = append(grid, fmt.Sprintf("%s %s", m.Styles.Label.Render("Notify:"), m.Styles.Value.Render(acc.Delivery)))
+		}
+		grid = append(grid, m.renderRemedy(acc.LastError, width-8)...)
 
 		details = lipgloss.JoinVertical(lipgloss.Left,
 			title,
@@ -135,8 +154,20 @@ func (m Model) renderDetailsPane(width, height int) string {
 	sepWidth := max(20, width-6)
 	separator := strings.Repeat("─", sepWidth)
 
+	schedule := fmt.Sprintf("%s %s   %s %s",
+		m.Styles.Label.Render("⏱ Cycle:"),
+		m.Styles.Value.Render(fmt.Sprintf("%ds", m.Intervals.Cycle)),
+		m.Styles.Label.Render("Delay:"),
+		m.Styles.Value.Render(fmt.Sprintf("%ds", m.Intervals.Delay)),
+	)
+	if m.Intervals.Jitter > 0 {
+		schedule += fmt.Sprintf("   %s %s", m.Styles.Label.Render("Jitter:"), m.Styles.Value.Render(fmt.Sprintf("±%d%%", m.Intervals.Jitter)))
+	}
+	schedule += m.Styles.Muted.Render("  (+/- [/])")
+
 	content := lipgloss.JoinVertical(lipgloss.Left,
 		statsBar,
+		schedule,
 		"",
 		m.Styles.Muted.Render(separator),
 		"",
@@ -154,6 +185,24 @@ func (m Model) renderDetailsPane(width, height int) string {
 		Render(content)
 }
 
+// renderRemedy renders the last error and, for a known one, the steps to fix it.
+func (m Model) renderRemedy(lastError string, width int) []string {
+	if lastError == "" {
+		return nil
+	}
+	wrap := lipgloss.NewStyle().Width(max(20, width))
+	lines := []string{"", m.Styles.Label.Render("Last error:"), m.Styles.Muted.Inherit(wrap).Render(lastError)}
+	r, ok := remedy.Lookup(lastError)
+	if !ok {
+		return lines
+	}
+	lines = append(lines, "", m.Styles.StatusWaiting.Render("💡 "+r.Problem))
+	for i, step := range r.Steps {
+		lines = append(lines, wrap.Render(fmt.Sprintf("%d. %s", i+1, step)))
+	}
+	return lines
+}
+
 // renderStatsBarInline renders the stats in a clean inline format
 func (m Model) renderStatsBarInline() string {
 	return fmt.Sprintf("%s %s   %s %s   %s %s",
@@ -195,19 +244,7 @@ func (m Model) renderLogsPane(height int, width int) string {
 		// Format: Time [Level] Msg
 		ts := l.Time.Format("15:04:05")
 
-		var levelStyle lipgloss.Style
-		switch l.Level {
-		case "info":
-			levelStyle = m.Styles.StatusRunning
-		case "warn":
-			levelStyle = m.Styles.StatusWaiting
-		case "error":
-			levelStyle = m.Styles.StatusError
-		case "success":
-			levelStyle = m.Styles.StatusProvisioned
-		default:
-			levelStyle = m.Styles.Muted
-		}
+		levelStyle := m.levelStyle(l.Level)
 
 		// Truncate message if it's too long
 		// Time (8) + Space + Level (~7) + Space + Account (~12) + Space + Msg
@@ -254,6 +291,10 @@ func (m Model) renderStatusBadge(state string) string {
 		return m.Styles.StatusRunning.Render("RUNNING")
 	case "waiting":
 		return m.Styles.StatusWaiting.Render("WAITING")
+	case "paused":
+		return m.Styles.Muted.Render("PAUSED")
+	case "limited":
+		return m.Styles.StatusError.Render("LIMITED")
 	case "error":
 		return m.Styles.StatusError.Render("ERROR")
 	}
@@ -0,0 +1,112 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// logLevels are the levels of the log legend, in the order shown.
+var logLevels = []string{"info", "warn", "error", "success"}
+
+// levelStyle is the color of a log level, in the log viewer and the dashboard pane alike.
+func (m Model) levelStyle(level string) lipgloss.Style {
+	switch level {
+	case "info":
+		return m.Styles.StatusRunning
+	case "warn":
+		return m.Styles.StatusWaiting
+	case "error":
+		return m.Styles.StatusError
+	case "success":
+		return m.Styles.StatusProvisioned
+	default:
+		return m.Styles.Muted
+	}
+}
+
+// renderLogLegend shows what the level colors mean, the error jump keys and where the
+// marked error is among all of them.
+func (m Model) renderLogLegend() string {
+	var parts []string
+	for _, level := range logLevels {
+		parts = append(parts, m.levelStyle(level).Render("● "+level))
+	}
+	errors := m.errorEntries()
+	jump := "n/N next/prev error"
+	switch {
+	case len(errors) == 0:
+		jump = "no errors"
+	case m.errorMark >= 0:
+		for i, e := range errors {
+			if e == m.errorMark {
+				jump += fmt.Sprintf(" (%d of %d)", i+1, len(errors))
+			}
+		}
+	default:
+		jump += fmt.Sprintf(" (%d)", len(errors))
+	}
+	return strings.Join(parts, "  ") + "   " + m.Styles.Muted.Render(jump)
+}
+
+// errorEntries returns the indexes of the error entries in Logs.
+func (m Model) errorEntries() []int {
+	var idx []int
+	for i, l := range m.Logs {
+		if l.Level == "error" {
+			idx = append(idx, i)
+		}
+	}
+	return idx
+}
+
+// entryAt returns the index of the log entry shown on viewport line y.
+func (m Model) entryAt(y int) int {
+	for i, start := range m.logLines {
+		if start > y {
+			return i - 1
+		}
+	}
+	return len(m.logLines) - 1
+}
+
+// jumpToError marks the next (dir 1) or previous (dir -1) error entry, wrapping around,
+// and scrolls it into the upper part of the viewer. Without a marked error the search
+// starts from what is on screen.
+func (m *Model) jumpToError(dir int) {
+	errors := m.errorEntries()
+	if len(errors) == 0 {
+		return
+	}
+	from := m.errorMark
+	if from < 0 {
+		from = m.entryAt(m.Viewport.YOffset) - 1
+		if dir < 0 {
+			from = m.entryAt(m.Viewport.YOffset+m.Viewport.Height) + 1
+		}
+	}
+
+	next := -1
+	if dir > 0 {
+		next = errors[0]
+		for _, e := range errors {
+			if e > from {
+				next = e
+				break
+			}
+		}
+	} else {
+		next = errors[len(errors)-1]
+		for i := len(errors) - 1; i >= 0; i-- {
+			if errors[i] < from {
+				next = errors[i]
+				break
+			}
+		}
+	}
+
+	m.errorMark = next
+	m.updateViewportContent()
+	m.Viewport.SetYOffset(max(0, m.logLines[next]-m.Viewport.Height/3))
+}
@@ -0,0 +1,93 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/yourusername/oci-arm-provisioner/internal/notifier"
+)
+
+// providerTestMsg reports the outcome of a test notification.
+type providerTestMsg struct {
+	Provider string
+	Err      error
+}
+
+// notifier returns the runner's notifier, or nil without a runner.
+func (m Model) notifier() *notifier.Notifier {
+	if m.Runner == nil || m.Runner.Provisioner == nil {
+		return nil
+	}
+	return m.Runner.Provisioner.Notifier
+}
+
+// sendProviderTest sends a test message to the selected provider in the background.
+func (m Model) sendProviderTest() tea.Cmd {
+	n := m.notifier()
+	if n == nil {
+		return nil
+	}
+	providers := n.Providers()
+	if m.ProviderIdx >= len(providers) {
+		return nil
+	}
+	provider := providers[m.ProviderIdx]
+	m.Runner.Logger.Info("NOTIFY", fmt.Sprintf("Sending a test notification to %s...", provider))
+	return func() tea.Msg {
+		return providerTestMsg{Provider: provider, Err: n.SendTest(provider)}
+	}
+}
+
+// logProviderTest reports a test notification in the log pane.
+func (m Model) logProviderTest(msg providerTestMsg) {
+	if m.Runner == nil {
+		return
+	}
+	if msg.Err != nil {
+		m.Runner.Logger.Error("NOTIFY", fmt.Sprintf("Test notification to %s failed: %v", msg.Provider, msg.Err))
+		return
+	}
+	m.Runner.Logger.Success("NOTIFY", fmt.Sprintf("Test notification sent to %s", msg.Provider))
+}
+
+// renderProviders lists the enabled notification providers with the outcome of their
+// last send and last error.
+func (m Model) renderProviders() string {
+	var b strings.Builder
+	b.WriteString(m.Styles.Subtitle.Render("🔔 Notification Providers") + "\n\n")
+
+	n := m.notifier()
+	if n == nil || !n.Config.Enabled {
+		b.WriteString(m.Styles.Muted.Render("Notifications are disabled."))
+		return b.String()
+	}
+	statuses := n.Status()
+	if len(statuses) == 0 {
+		b.WriteString(m.Styles.Muted.Render("No providers configured."))
+		return b.String()
+	}
+
+	for i, st := range statuses {
+		cursor := "  "
+		if i == m.ProviderIdx {
+			cursor = "→ "
+		}
+		var status string
+		switch {
+		case st.Sends == 0:
+			status = m.Styles.Muted.Render("nothing sent yet")
+		case st.OK:
+			status = m.Styles.StatusProvisioned.Render("ok") + m.Styles.Muted.Render(fmt.Sprintf(" at %s, %d sent", st.LastSent.Format("15:04:05"), st.Sends))
+		default:
+			status = m.Styles.StatusError.Render("failed") + m.Styles.Muted.Render(fmt.Sprintf(" at %s, %d sent", st.LastSent.Format("15:04:05"), st.Sends))
+		}
+		fmt.Fprintf(&b, "%s%s %s\n", cursor, m.Styles.Label.Render(fmt.Sprintf("%-9s", st.Provider)), status)
+		if st.LastError != "" {
+			b.WriteString("    " + m.Styles.Muted.Render(fmt.Sprintf("last error at %s: %s", st.ErrorAt.Format("15:04:05"), st.LastError)) + "\n")
+		}
+	}
+	b.WriteString("\n" + m.Styles.Muted.Render("↑/↓ select, enter sends a test notification"))
+	return b.String()
+}
@@ -0,0 +1,227 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/yourusername/oci-arm-provisioner/internal/config"
+	"github.com/yourusername/oci-arm-provisioner/internal/provisioner"
+)
+
+// pickerStep is the stage of the region/AD picker
+type pickerStep int
+
+const (
+	pickRegion pickerStep = iota
+	pickAD
+)
+
+// Picker holds the state of the region/AD switcher for one account
+type Picker struct {
+	Account  string
+	Step     pickerStep
+	Region   string   // Region chosen in the first step
+	Current  string   // Current value, marked in the list
+	Options  []string // Choices fetched from the Identity API
+	Selected int
+	Loading  bool
+	Err      error
+}
+
+// regionsMsg carries the subscribed regions of an account
+type regionsMsg struct {
+	Account string
+	Regions []string
+	Err     error
+}
+
+// adsMsg carries the availability domains of a region
+type adsMsg struct {
+	Account string
+	Region  string
+	ADs     []string
+	Err     error
+}
+
+// retargetMsg reports the outcome of saving a new region/AD
+type retargetMsg struct {
+	Account string
+	Target  provisioner.Target
+	Err     error
+}
+
+// Retarget persists a new region/AD for an account to the config file and restarts its
+// worker with it.
+func (r *ProvisionerRunner) Retarget(path, name string, target provisioner.Target) error {
+	if path == "" {
+		return fmt.Errorf("config file location unknown")
+	}
+	if err := config.SetAccountFields(path, name, target.Fields(), "tui region/AD switch"); err != nil {
+		return err
+	}
+	if err := r.Provisioner.Retarget(name, target); err != nil {
+		return err
+	}
+	r.updateAccountStatus(name, func(s *AccountStatus) {
+		s.Region, s.AvailabilityDomain = target.Region, target.AvailabilityDomain
+	})
+	return nil
+}
+
+// startRetarget opens the picker for the selected account and fetches its regions
+func (m Model) startRetarget() (Model, tea.Cmd) {
+	if m.Runner == nil || m.SelectedIdx >= len(m.Accounts) {
+		return m, nil
+	}
+	acc := m.Accounts[m.SelectedIdx]
+	if acc.Provisioned {
+		m.Runner.Logger.Info(acc.Name, "Already provisioned - nothing to retarget")
+		return m, nil
+	}
+
+	m.Picker = Picker{Account: acc.Name, Step: pickRegion, Current: acc.Region, Loading: true}
+	m.pickerAD = acc.AvailabilityDomain
+	m.CurrentView = ViewRetarget
+
+	runner, ctx := m.Runner, m.ctx
+	return m, func() tea.Msg {
+		regions, err := runner.Provisioner.Regions(ctx, acc.Name)
+		return regionsMsg{Account: acc.Name, Regions: regions, Err: err}
+	}
+}
+
+// updatePicker handles keys while the picker is open
+func (m Model) updatePicker(msg tea.KeyMsg) (Model, tea.Cmd) {
+	p := &m.Picker
+	switch {
+	case key.Matches(msg, m.Keys.Escape), msg.String() == "q":
+		m.CurrentView = ViewDashboard
+		m.Picker = Picker{}
+
+	case key.Matches(msg, m.Keys.Up):
+		if p.Selected > 0 {
+			p.Selected--
+		}
+
+	case key.Matches(msg, m.Keys.Down):
+		if p.Selected < len(p.Options)-1 {
+			p.Selected++
+		}
+
+	case key.Matches(msg, m.Keys.Enter):
+		if p.Loading || p.Selected >= len(p.Options) {
+			return m, nil
+		}
+		choice := p.Options[p.Selected]
+		runner, ctx, account := m.Runner, m.ctx, p.Account
+
+		if p.Step == pickRegion {
+			current := m.pickerAD
+			if choice != p.Current {
+				current = "auto" // AD names are per region
+			}
+			*p = Picker{Account: account, Step: pickAD, Region: choice, Current: current, Loading: true}
+			return m, func() tea.Msg {
+				ads, err := runner.Provisioner.AvailabilityDomains(ctx, account, choice)
+				return adsMsg{Account: account, Region: choice, ADs: ads, Err: err}
+			}
+		}
+
+		target := provisioner.Target{Region: p.Region, AvailabilityDomain: choice}
+		p.Loading = true
+		path := m.ConfigPath
+		return m, func() tea.Msg {
+			return retargetMsg{Account: account, Target: target, Err: runner.Retarget(path, account, target)}
+		}
+	}
+	return m, nil
+}
+
+// updatePickerResult applies the result of a picker lookup or save
+func (m Model) updatePickerResult(msg tea.Msg) (Model, tea.Cmd) {
+	p := &m.Picker
+	switch msg := msg.(type) {
+	case regionsMsg:
+		if msg.Account != p.Account || p.Step != pickRegion {
+			return m, nil
+		}
+		p.Loading, p.Err, p.Options = false, msg.Err, msg.Regions
+		p.Selected = indexOf(p.Options, p.Current)
+
+	case adsMsg:
+		if msg.Account != p.Account || msg.Region != p.Region {
+			return m, nil
+		}
+		p.Loading, p.Err = false, msg.Err
+		p.Options = append([]string{"auto"}, msg.ADs...)
+		p.Selected = indexOf(p.Options, p.Current)
+
+	case retargetMsg:
+		if msg.Err != nil {
+			p.Loading, p.Err = false, msg.Err
+			m.Runner.Logger.Error(msg.Account, fmt.Sprintf("Region/AD switch failed: %v", msg.Err))
+			return m, nil
+		}
+		m.Runner.Logger.Success(msg.Account, fmt.Sprintf("Saved %s / %s to config", msg.Target.Region, msg.Target.AvailabilityDomain))
+		m.CurrentView = ViewDashboard
+		m.Picker = Picker{}
+	}
+	return m, nil
+}
+
+// viewRetarget renders the region/AD picker
+func (m Model) viewRetarget() string {
+	p := m.Picker
+	var content strings.Builder
+
+	title := fmt.Sprintf("🧭 Switch region · %s", p.Account)
+	hint := "Regions your tenancy is subscribed to"
+	if p.Step == pickAD {
+		title = fmt.Sprintf("🧭 Switch AD · %s · %s", p.Account, p.Region)
+		hint = "'auto' picks the first AD at launch"
+	}
+	content.WriteString(m.Styles.Title.Render(title) + "\n")
+	content.WriteString(m.Styles.Muted.Render(hint) + "\n\n")
+
+	height := max(0, m.Height-14)
+	switch {
+	case p.Err != nil:
+		content.WriteString(m.Styles.StatusError.Render("❌ "+p.Err.Error()) + "\n")
+	case p.Loading:
+		content.WriteString(m.Spinner.View() + " " + m.Styles.Muted.Render("Loading...") + "\n")
+	case len(p.Options) == 0:
+		content.WriteString(m.Styles.Muted.Render("(Nothing to choose from)") + "\n")
+	default:
+		// Keep the selection in view on long region lists
+		visible := max(3, height-6)
+		start := max(0, min(p.Selected-visible/2, len(p.Options)-visible))
+		end := min(len(p.Options), start+visible)
+		for i := start; i < end; i++ {
+			line := "  " + p.Options[i]
+			if p.Options[i] == p.Current {
+				line += m.Styles.Muted.Render(" (current)")
+			}
+			if i == p.Selected {
+				line = m.Styles.Highlight.Render("▶ "+p.Options[i]) + strings.TrimPrefix(line, "  "+p.Options[i])
+			}
+			content.WriteString(line + "\n")
+		}
+	}
+	content.WriteString("\n" + m.Styles.Muted.Render("↑/↓ choose · enter select · esc cancel"))
+
+	return lipgloss.NewStyle().Height(height).Render(content.String())
+}
+
+// indexOf returns the position of s in list, or 0
+func indexOf(list []string, s string) int {
+	for i, v := range list {
+		if v == s {
+			return i
+		}
+	}
+	return 0
+}
@@ -2,15 +2,22 @@ package tui
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"sync"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/yourusername/oci-arm-provisioner/internal/api"
 	"github.com/yourusername/oci-arm-provisioner/internal/config"
+	"github.com/yourusername/oci-arm-provisioner/internal/control"
 	"github.com/yourusername/oci-arm-provisioner/internal/logger"
+	"github.com/yourusername/oci-arm-provisioner/internal/logstore"
 	"github.com/yourusername/oci-arm-provisioner/internal/notifier"
 	"github.com/yourusername/oci-arm-provisioner/internal/provisioner"
+	"github.com/yourusername/oci-arm-provisioner/internal/state"
+	"github.com/yourusername/oci-arm-provisioner/internal/statusfile"
 )
 
 // ProvisionerRunner manages the background provisioning process
@@ -19,12 +26,14 @@ type ProvisionerRunner struct {
 	Logger      *logger.Logger
 	Tracker     *notifier.Tracker
 	Provisioner *provisioner.Provisioner
+	Logs        *logstore.Store // Recent log lines, shared by the log view and the API.
 
 	// Communication channels
-	statusChan chan AccountStatusUpdate
-	logChan    chan LogEntry
-	pauseChan  chan bool
-	stopChan   chan struct{}
+	statusChan   chan AccountStatusUpdate
+	successChan  chan Success
+	pauseChan    chan bool
+	stopChan     chan struct{}
+	intervalChan chan intervals
 
 	// State
 	mu       sync.RWMutex
@@ -53,26 +62,65 @@ func NewProvisionerRunner(cfg *config.Config, l *logger.Logger, tracker *notifie
 	for name, acc := range cfg.Accounts {
 		if acc.Enabled {
 			accounts[name] = &AccountStatus{
-				Name:     name,
-				Region:   acc.Region,
-				State:    "waiting",
-				OCPUs:    acc.OCPUs,
-				MemoryGB: acc.MemoryGB,
+				Name:               name,
+				Notes:              acc.Notes,
+				Region:             acc.Region,
+				AvailabilityDomain: acc.AvailabilityDomain,
+				State:              "waiting",
+				OCPUs:              acc.OCPUs,
+				MemoryGB:           acc.MemoryGB,
 			}
 		}
 	}
 
-	return &ProvisionerRunner{
-		Config:      cfg,
-		Logger:      l,
-		Tracker:     tracker,
-		Provisioner: provisioner.New(cfg, l, tracker),
-		statusChan:  make(chan AccountStatusUpdate, 100),
-		logChan:     make(chan LogEntry, 1000),
-		pauseChan:   make(chan bool),
-		stopChan:    make(chan struct{}),
-		accounts:    accounts,
+	r := &ProvisionerRunner{
+		Config:       cfg,
+		Logger:       l,
+		Tracker:      tracker,
+		Provisioner:  provisioner.New(cfg, l, tracker),
+		Logs:         logstore.New(logstore.Size),
+		statusChan:   make(chan AccountStatusUpdate, 100),
+		successChan:  make(chan Success, 10),
+		pauseChan:    make(chan bool),
+		stopChan:     make(chan struct{}),
+		intervalChan: make(chan intervals, 1),
+		accounts:     accounts,
 	}
+	r.Provisioner.OnVerified = r.applyVerified
+	return r
+}
+
+// applyVerified shows a newly launched instance as soon as it is verified, with the IP and
+// shape it actually got, and opens the success overlay.
+func (r *ProvisionerRunner) applyVerified(name string, v *provisioner.VerifiedInstance) {
+	s := Success{
+		Account:    name,
+		InstanceID: v.InstanceID,
+		PublicIP:   v.PublicIP,
+		Specs:      fmt.Sprintf("%.0f OCPUs / %.0f GB RAM", v.OCPUs, v.MemoryGB),
+	}
+	if acc := r.Config.Accounts[name]; acc != nil {
+		s.SSH = sshCommand(acc, v.PublicIP)
+	}
+	select {
+	case r.successChan <- s:
+	default:
+	}
+
+	r.updateAccountStatus(name, func(s *AccountStatus) {
+		s.State = "provisioned"
+		s.Provisioned = true
+		s.InstanceID = v.InstanceID
+		if v.PublicIP != "" {
+			s.PublicIP = v.PublicIP
+		}
+		if v.OCPUs > 0 {
+			s.OCPUs, s.MemoryGB = v.OCPUs, v.MemoryGB
+		}
+		if v.Region != "" {
+			s.Region = v.Region
+		}
+	})
 }
 
 // Start begins the provisioning loop in a goroutine
@@ -112,14 +160,32 @@ func (r *ProvisionerRunner) IsPaused() bool {
 	return r.paused
 }
 
+// SetAccountPaused pauses or resumes a single account while the others keep running
+func (r *ProvisionerRunner) SetAccountPaused(name string, paused bool) {
+	if !r.Provisioner.SetAccountPaused(name, paused) {
+		return
+	}
+	r.updateAccountStatus(name, func(s *AccountStatus) {
+		s.Paused = paused
+		if s.Provisioned {
+			return
+		}
+		if paused {
+			s.State = "paused"
+		} else {
+			s.State = "waiting"
+		}
+	})
+}
+
 // StatusChan returns the channel for status updates
 func (r *ProvisionerRunner) StatusChan() <-chan AccountStatusUpdate {
 	return r.statusChan
 }
 
-// LogChan returns the channel for log entries
-func (r *ProvisionerRunner) LogChan() <-chan LogEntry {
-	return r.logChan
+// SuccessChan returns the channel of launched instances
+func (r *ProvisionerRunner) SuccessChan() <-chan Success {
+	return r.successChan
 }
 
 // GetAccounts returns current account statuses
@@ -142,15 +208,53 @@ func (r *ProvisionerRunner) runLoop(ctx context.Context) {
 
 	cycleCount := 0
 
-	// Run first cycle immediately
+	// Management API for ocarmctl, reading the same log lines as the log view
+	var apiCommands <-chan control.Command
+	if r.Config.API.Listen != "" {
+		apiCommands = api.Serve(ctx, r.Config.API, func() api.Status {
+			return api.Snapshot(r.Provisioner, r.IsPaused())
+		}, r.Logs, func(err error) {
+			r.Logger.Error("API", err.Error())
+		})
+	}
+
+	// Check the regions are reachable, pick up instances launched before (reinstall, new
+	// machine), then run the first cycle
+	r.Provisioner.ProbeRegions(ctx)
+	r.Provisioner.Discover(ctx)
 	r.runCycle(ctx, &cycleCount)
 
+	var commands <-chan control.Command
+	if r.Config.ControlDir != "" {
+		commands = control.Watch(ctx, r.Config.ControlDir, func(file string, err error) {
+			r.Logger.Error("CONTROL", fmt.Sprintf("%s: %v", file, err))
+		})
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		case <-r.stopChan:
 			return
+		case cmd, ok := <-commands:
+			if !ok {
+				commands = nil
+				continue
+			}
+			r.applyCommand(ctx, cmd, &cycleCount, ticker)
+		case cmd, ok := <-apiCommands:
+			if !ok {
+				apiCommands = nil
+				continue
+			}
+			r.applyCommand(ctx, cmd, &cycleCount, ticker)
+		case iv := <-r.intervalChan:
+			// Applied here, between cycles, so RunCycle never sees a half-updated config
+			r.Config.Scheduler.CycleIntervalSeconds = iv.Cycle
+			r.Config.Scheduler.AccountDelaySeconds = iv.Delay
+			ticker.Reset(time.Duration(iv.Cycle) * time.Second)
+			r.Logger.Info("SCHEDULER", fmt.Sprintf("Cycle interval %ds, account delay %ds", iv.Cycle, iv.Delay))
 		case <-ticker.C:
 			r.mu.RLock()
 			paused := r.paused
@@ -159,8 +263,34 @@ func (r *ProvisionerRunner) runLoop(ctx context.Context) {
 			if !paused {
 				r.runCycle(ctx, &cycleCount)
 			}
+			if s := r.Config.Scheduler; s.JitterPercent > 0 {
+				ticker.Reset(s.Jitter(time.Duration(s.CycleIntervalSeconds) * time.Second))
+			}
+		}
+	}
+}
+
+// applyCommand runs a control_dir or API command
+func (r *ProvisionerRunner) applyCommand(ctx context.Context, cmd control.Command, cycleCount *int, ticker *time.Ticker) {
+	r.Logger.Info("CONTROL", fmt.Sprintf("📥 %s", cmd))
+	switch cmd.Action {
+	case control.ActionPause, control.ActionResume:
+		pause := cmd.Action == control.ActionPause
+		if cmd.Account == "" {
+			r.SetPaused(pause)
+		} else if _, known := r.accounts[cmd.Account]; !known {
+			r.Logger.Error("CONTROL", fmt.Sprintf("Unknown account '%s'", cmd.Account))
+		} else {
+			r.SetAccountPaused(cmd.Account, pause)
 		}
+	case control.ActionTrigger:
+		r.runCycle(ctx, cycleCount)
+		s := r.Config.Scheduler
+		ticker.Reset(s.Jitter(time.Duration(s.CycleIntervalSeconds) * time.Second))
+	case control.ActionReload:
+		r.Logger.Warn("CONTROL", "The dashboard can't reload config.yaml - restart it, or run with --headless")
 	}
+	r.writeStatus()
 }
 
 // runCycle executes a single provisioning cycle
@@ -178,6 +308,11 @@ func (r *ProvisionerRunner) runCycle(ctx context.Context, cycleCount *int) {
 			continue
 		}
 
+		// Leave paused accounts as they are
+		if r.Provisioner.IsAccountPaused(name) {
+			continue
+		}
+
 		r.updateAccountStatus(name, func(s *AccountStatus) {
 			s.State = "running"
 		})
@@ -205,11 +340,44 @@ func (r *ProvisionerRunner) runCycle(ctx context.Context, cycleCount *int) {
 	// Update capacity hits from tracker
 	stats := r.Tracker.Snapshot()
 	for name := range r.accounts {
+		acc := stats.Accounts[name]
 		r.updateAccountStatus(name, func(s *AccountStatus) {
-			// Distribute capacity errors evenly for now (could be per-account)
-			s.CapacityHits = stats.CapacityErrors / max(1, len(r.accounts))
+			s.CapacityHits = acc.CapacityErrors
+			s.LastError = acc.LastError
+			if acc.Limited != "" && s.State == "waiting" {
+				s.State = "limited"
+				s.LastError = "Service limit exhausted: " + acc.Limited
+			}
+			if n := len(acc.Instances); n > 0 {
+				s.InstanceID = acc.Instances[n-1].ID
+				s.PublicIP = acc.Instances[n-1].PublicIP
+			}
+			if receipt, ok := r.Provisioner.State.Notification(name); ok {
+				s.Delivery = deliverySummary(receipt)
+			}
 		})
 	}
+	r.writeStatus()
+}
+
+// writeStatus refreshes the status_dir files, if configured.
+func (r *ProvisionerRunner) writeStatus() {
+	if r.Config.StatusDir == "" {
+		return
+	}
+	if err := statusfile.Write(r.Config.StatusDir, r.Config.StatusFormat, api.Snapshot(r.Provisioner, r.IsPaused())); err != nil {
+		r.Logger.Error("STATUS", fmt.Sprintf("Failed to write status files: %v", err))
+	}
+}
+
+// deliverySummary renders a notification receipt: ✓ delivered, ✓✓ acknowledged, ✗ failed.
+func deliverySummary(receipt state.Receipt) string {
+	marks := map[string]string{"delivered": "✓", "acknowledged": "✓✓", "failed": "✗"}
+	parts := make([]string, 0, len(receipt.Deliveries))
+	for _, d := range receipt.Deliveries {
+		parts = append(parts, d.Provider+" "+marks[d.Status])
+	}
+	return strings.Join(parts, " · ")
 }
 
 // updateAccountStatus updates an account and sends the update
@@ -238,12 +406,16 @@ func accountUpdateCmd(statusChan <-chan AccountStatusUpdate) tea.Cmd {
 // accountUpdateMsg is sent when an account status changes
 type accountUpdateMsg AccountStatusUpdate
 
-// logUpdateCmd creates a tea.Cmd that waits for log entries
-func logUpdateCmd(logChan <-chan LogEntry) tea.Cmd {
+// logUpdateCmd creates a tea.Cmd that waits for log lines after cursor
+func logUpdateCmd(logs *logstore.Store, cursor int64) tea.Cmd {
 	return func() tea.Msg {
-		return logUpdateMsg(<-logChan)
+		<-logs.Changed(cursor)
+		return logUpdateMsg{Entries: logs.Since(cursor, logstore.Size), Cursor: cursor}
 	}
 }
 
-// logUpdateMsg is sent when a new log entry arrives
-type logUpdateMsg LogEntry
+// logUpdateMsg is sent when new log lines arrive
+type logUpdateMsg struct {
+	Entries []logstore.Entry
+	Cursor  int64 // The seq the lines follow; a gap to the first means lines were missed.
+}
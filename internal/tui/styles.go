@@ -248,6 +248,8 @@ const (
 	IconInfo    = "ℹ️"
 	IconRunning = "🔄"
 	IconWaiting = "⏳"
+	IconPaused  = "⏸️"
+	IconLimited = "⛔"
 	IconRocket  = "🚀"
 	IconCheck   = "✓"
 	IconCross   = "✗"
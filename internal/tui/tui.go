@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"os"
 	"strings"
 	"time"
 
@@ -15,9 +16,12 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
+	"github.com/yourusername/oci-arm-provisioner/internal/bundle"
 	"github.com/yourusername/oci-arm-provisioner/internal/config"
 	"github.com/yourusername/oci-arm-provisioner/internal/logger"
+	"github.com/yourusername/oci-arm-provisioner/internal/logstore"
 	"github.com/yourusername/oci-arm-provisioner/internal/notifier"
+	"gopkg.in/yaml.v3"
 )
 
 // View represents different screens in the TUI
@@ -28,20 +32,25 @@ const (
 	ViewLogs
 	ViewConfig
 	ViewHelp
+	ViewRetarget
 )
 
 // AccountStatus represents the current state of an account
 type AccountStatus struct {
-	Name         string
-	Region       string
-	State        string // "running", "provisioned", "waiting", "error"
-	InstanceID   string
-	PublicIP     string
-	OCPUs        float32
-	MemoryGB     float32
-	CapacityHits int
-	LastError    string
-	Provisioned  bool
+	Name               string
+	Notes              string // accounts.<name>.notes
+	Region             string
+	AvailabilityDomain string
+	State              string // "running", "provisioned", "waiting", "paused", "limited", "error"
+	InstanceID         string
+	PublicIP           string
+	OCPUs              float32
+	MemoryGB           float32
+	CapacityHits       int
+	LastError          string
+	Delivery           string // Where the success notification got through, e.g. "ntfy ✓✓ · webhook ✗".
+	Provisioned        bool
+	Paused             bool
 }
 
 // tickMsg is sent periodically to update the UI
@@ -61,6 +70,15 @@ type KeyMap struct {
 	Config    key.Binding
 	Pause     key.Binding
 	Resume    key.Binding
+	Toggle    key.Binding
+	Bundle    key.Binding
+	Retarget  key.Binding
+	CycleUp   key.Binding
+	CycleDown key.Binding
+	DelayUp   key.Binding
+	DelayDown key.Binding
+	NextError key.Binding
+	PrevError key.Binding
 	Up        key.Binding
 	Down      key.Binding
 	Enter     key.Binding
@@ -99,6 +117,42 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("r"),
 			key.WithHelp("r", "resume"),
 		),
+		Toggle: key.NewBinding(
+			key.WithKeys(" "),
+			key.WithHelp("space", "pause/resume account"),
+		),
+		Retarget: key.NewBinding(
+			key.WithKeys("t"),
+			key.WithHelp("t", "switch region/AD"),
+		),
+		Bundle: key.NewBinding(
+			key.WithKeys("b"),
+			key.WithHelp("b", "debug bundle"),
+		),
+		CycleUp: key.NewBinding(
+			key.WithKeys("+", "="),
+			key.WithHelp("+", "longer cycle interval"),
+		),
+		CycleDown: key.NewBinding(
+			key.WithKeys("-"),
+			key.WithHelp("-", "shorter cycle interval"),
+		),
+		DelayUp: key.NewBinding(
+			key.WithKeys("]"),
+			key.WithHelp("]", "longer account delay"),
+		),
+		DelayDown: key.NewBinding(
+			key.WithKeys("["),
+			key.WithHelp("[", "shorter account delay"),
+		),
+		NextError: key.NewBinding(
+			key.WithKeys("n"),
+			key.WithHelp("n", "next error"),
+		),
+		PrevError: key.NewBinding(
+			key.WithKeys("N"),
+			key.WithHelp("N", "previous error"),
+		),
 		Up: key.NewBinding(
 			key.WithKeys("up", "k"),
 			key.WithHelp("↑/k", "up"),
@@ -131,7 +185,9 @@ func (k KeyMap) ShortHelp() []key.Binding {
 func (k KeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		{k.Dashboard, k.Logs, k.Config},
-		{k.Pause, k.Resume},
+		{k.Pause, k.Resume, k.Toggle, k.Retarget, k.Bundle},
+		{k.CycleUp, k.CycleDown, k.DelayUp, k.DelayDown},
+		{k.NextError, k.PrevError},
 		{k.Up, k.Down, k.Enter, k.Escape},
 		{k.Help, k.Quit},
 	}
@@ -140,15 +196,17 @@ func (k KeyMap) FullHelp() [][]key.Binding {
 // Model is the main TUI model
 type Model struct {
 	// Configuration
-	Config  *config.Config
-	Tracker *notifier.Tracker
-	Runner  *ProvisionerRunner
+	Config     *config.Config
+	ConfigPath string // Where region/AD switches are saved
+	Tracker    *notifier.Tracker
+	Runner     *ProvisionerRunner
 
 	// UI State
 	CurrentView View
 	Width       int
 	Height      int
 	Ready       bool
+	Accessible  bool // Plain labeled text instead of the styled layout; see viewAccessible.
 
 	// Dashboard state
 	Accounts    []AccountStatus
@@ -164,6 +222,23 @@ type Model struct {
 	// Logs
 	Logs               []LogEntry
 	DashboardLogOffset int
+	logLines           []int // Viewport line each entry of Logs starts at.
+	errorMark          int   // Entry of Logs jumped to with n/N; -1 for none.
+
+	// Scheduler timings, adjusted live with +/- and [/]
+	Intervals    intervals
+	intervalsSeq int
+
+	// Notification providers panel of the config view
+	ProviderIdx int
+
+	// Region/AD picker
+	Picker   Picker
+	pickerAD string // AD of the account when the picker opened
+
+	// Instances launched since the last keypress, shown one at a time over the view
+	Successes []Success
+	bellsLeft int
 
 	// Components
 	Keys     KeyMap
@@ -179,7 +254,7 @@ type Model struct {
 }
 
 // New creates a new TUI model
-func New(cfg *config.Config, tracker *notifier.Tracker, runner *ProvisionerRunner) Model {
+func New(cfg *config.Config, path string, tracker *notifier.Tracker, runner *ProvisionerRunner) Model {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	// Initialize accounts from config
@@ -187,11 +262,13 @@ func New(cfg *config.Config, tracker *notifier.Tracker, runner *ProvisionerRunne
 	for name, acc := range cfg.Accounts {
 		if acc.Enabled {
 			accounts = append(accounts, AccountStatus{
-				Name:     name,
-				Region:   acc.Region,
-				State:    "waiting",
-				OCPUs:    acc.OCPUs,
-				MemoryGB: acc.MemoryGB,
+				Name:               name,
+				Notes:              acc.Notes,
+				Region:             acc.Region,
+				AvailabilityDomain: acc.AvailabilityDomain,
+				State:              "waiting",
+				OCPUs:              acc.OCPUs,
+				MemoryGB:           acc.MemoryGB,
 			})
 		}
 	}
@@ -209,10 +286,12 @@ func New(cfg *config.Config, tracker *notifier.Tracker, runner *ProvisionerRunne
 
 	return Model{
 		Config:      cfg,
+		ConfigPath:  path,
 		Tracker:     tracker,
 		Runner:      runner,
 		CurrentView: ViewDashboard,
 		Accounts:    accounts,
+		Intervals:   intervals{Cycle: cfg.Scheduler.CycleIntervalSeconds, Delay: cfg.Scheduler.AccountDelaySeconds, Jitter: cfg.Scheduler.JitterPercent},
 		StartTime:   time.Now(),
 		Keys:        DefaultKeyMap(),
 		Styles:      NewStyles(DefaultTheme),
@@ -220,7 +299,8 @@ func New(cfg *config.Config, tracker *notifier.Tracker, runner *ProvisionerRunne
 		Viewport:    vp,
 		Spinner:     s,
 		Progress:    prog,
-		Logs:        make([]LogEntry, 0, 1000),
+		Logs:        make([]LogEntry, 0, logstore.Size),
+		errorMark:   -1,
 		ctx:         ctx,
 		cancel:      cancel,
 	}
@@ -241,7 +321,8 @@ func (m Model) Init() tea.Cmd {
 	// Listen for account updates and logs if runner is available
 	if m.Runner != nil {
 		cmds = append(cmds, accountUpdateCmd(m.Runner.StatusChan()))
-		cmds = append(cmds, logUpdateCmd(m.Runner.LogChan()))
+		cmds = append(cmds, logUpdateCmd(m.Runner.Logs, 0))
+		cmds = append(cmds, successCmd(m.Runner.SuccessChan()))
 	}
 
 	return tea.Batch(cmds...)
@@ -338,6 +419,15 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case tea.KeyMsg:
+		// Any key closes a success overlay
+		if len(m.Successes) > 0 && msg.String() != "ctrl+c" {
+			return m.dismissSuccess(), nil
+		}
+		// The picker owns the keyboard while it is open
+		if m.CurrentView == ViewRetarget && msg.String() != "ctrl+c" {
+			return m.updatePicker(msg)
+		}
+
 		switch {
 		case key.Matches(msg, m.Keys.Quit):
 			m.cancel()
@@ -371,21 +461,96 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.Runner.SetPaused(false)
 			}
 
+		case key.Matches(msg, m.Keys.Toggle):
+			if m.CurrentView == ViewDashboard && m.Runner != nil && m.SelectedIdx < len(m.Accounts) {
+				acc := m.Accounts[m.SelectedIdx]
+				m.Runner.SetAccountPaused(acc.Name, !acc.Paused)
+			}
+
+		case key.Matches(msg, m.Keys.Bundle):
+			m.writeBundle()
+
+		case key.Matches(msg, m.Keys.Retarget):
+			if m.CurrentView == ViewDashboard {
+				return m.startRetarget()
+			}
+
+		case key.Matches(msg, m.Keys.CycleUp):
+			return m.adjustIntervals(1, 0)
+
+		case key.Matches(msg, m.Keys.CycleDown):
+			return m.adjustIntervals(-1, 0)
+
+		case key.Matches(msg, m.Keys.DelayUp):
+			return m.adjustIntervals(0, 1)
+
+		case key.Matches(msg, m.Keys.DelayDown):
+			return m.adjustIntervals(0, -1)
+
+		case key.Matches(msg, m.Keys.NextError):
+			if m.CurrentView == ViewLogs {
+				m.jumpToError(1)
+				return m, nil
+			}
+
+		case key.Matches(msg, m.Keys.PrevError):
+			if m.CurrentView == ViewLogs {
+				m.jumpToError(-1)
+				return m, nil
+			}
+
 		case key.Matches(msg, m.Keys.Up):
 			if m.CurrentView == ViewDashboard && m.SelectedIdx > 0 {
 				m.SelectedIdx--
 			}
+			if m.CurrentView == ViewConfig && m.ProviderIdx > 0 {
+				m.ProviderIdx--
+			}
 
 		case key.Matches(msg, m.Keys.Down):
 			if m.CurrentView == ViewDashboard && m.SelectedIdx < len(m.Accounts)-1 {
 				m.SelectedIdx++
 			}
+			if n := m.notifier(); m.CurrentView == ViewConfig && n != nil && m.ProviderIdx < len(n.Providers())-1 {
+				m.ProviderIdx++
+			}
+
+		case key.Matches(msg, m.Keys.Enter):
+			if m.CurrentView == ViewConfig {
+				return m, m.sendProviderTest()
+			}
 
 		case key.Matches(msg, m.Keys.Escape):
 			m.CurrentView = ViewDashboard
 		}
 
+	case regionsMsg, adsMsg, retargetMsg:
+		return m.updatePickerResult(msg)
+
+	case saveIntervalsMsg:
+		return m, m.saveIntervals(msg)
+
+	case intervalsSavedMsg:
+		m.logIntervalsSaved(msg)
+
+	case providerTestMsg:
+		m.logProviderTest(msg)
+
+	case successMsg:
+		m, cmd = m.celebrate(Success(msg))
+		if m.Runner != nil {
+			cmd = tea.Batch(cmd, successCmd(m.Runner.SuccessChan()))
+		}
+		return m, cmd
+
+	case bellMsg:
+		return m.updateBell()
+
 	case tickMsg:
+		// Pick up a pause or resume from the control directory
+		if m.Runner != nil {
+			m.Paused = m.Runner.IsPaused()
+		}
 		// Update stats from tracker
 		if m.Tracker != nil {
 			stats := m.Tracker.Snapshot()
@@ -420,23 +585,37 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		cmds = append(cmds, cmd)
 
 	case logUpdateMsg:
-		// Add new log entry
-		m.Logs = append(m.Logs, LogEntry(msg))
+		// Add the new lines, noting any the store overwrote before they were read
+		cursor := msg.Cursor
+		if len(msg.Entries) > 0 {
+			if missed := msg.Entries[0].Seq - cursor - 1; missed > 0 {
+				m.Logs = append(m.Logs, LogEntry{Time: msg.Entries[0].Time, Level: "warn", Message: fmt.Sprintf("… %d log lines skipped", missed)})
+			}
+			for _, e := range msg.Entries {
+				m.Logs = append(m.Logs, LogEntry{Time: e.Time, Level: strings.ToLower(e.Level), Account: e.Account, Message: e.Message})
+			}
+			cursor = msg.Entries[len(msg.Entries)-1].Seq
+		}
 		// Keep logs detailed but limited history
-		if len(m.Logs) > 1000 {
-			m.Logs = m.Logs[len(m.Logs)-1000:]
+		if len(m.Logs) > logstore.Size {
+			dropped := len(m.Logs) - logstore.Size
+			m.Logs = m.Logs[dropped:]
+			if m.errorMark -= dropped; m.errorMark < 0 {
+				m.errorMark = -1
+			}
 		}
 
-		// Update viewport content
+		// Follow new lines, unless an error was jumped to and is being read
+		following := m.errorMark < 0 || m.Viewport.AtBottom()
 		m.updateViewportContent()
-		// Auto-scroll to bottom if we were at bottom?
-		// Viewport.SetContent usually resets to top.
-		// We want logs to auto-scroll.
-		m.Viewport.GotoBottom()
+		if following {
+			m.errorMark = -1
+			m.Viewport.GotoBottom()
+		}
 
 		// Continue listening for logs
 		if m.Runner != nil {
-			return m, logUpdateCmd(m.Runner.LogChan())
+			return m, logUpdateCmd(m.Runner.Logs, cursor)
 		}
 
 	}
@@ -458,17 +637,24 @@ func (m Model) View() string {
 	if !m.Ready {
 		return "Initializing..."
 	}
+	if m.Accessible {
+		return m.viewAccessible()
+	}
 
 	var content string
-	switch m.CurrentView {
-	case ViewDashboard:
+	switch {
+	case len(m.Successes) > 0:
+		content = m.viewSuccess()
+	case m.CurrentView == ViewDashboard:
 		content = m.viewDashboard()
-	case ViewLogs:
+	case m.CurrentView == ViewLogs:
 		content = m.viewLogs()
-	case ViewConfig:
+	case m.CurrentView == ViewConfig:
 		content = m.viewConfig()
-	case ViewHelp:
+	case m.CurrentView == ViewHelp:
 		content = m.viewHelp()
+	case m.CurrentView == ViewRetarget:
+		content = m.viewRetarget()
 	}
 
 	return m.Styles.App.Width(m.Width - 4).Height(m.Height).Render(
@@ -557,7 +743,7 @@ func (m Model) renderFooter() string {
 func (m Model) viewLogs() string {
 	// Return viewport view (content updated in Update)
 	return lipgloss.JoinVertical(lipgloss.Left,
-		m.Styles.Title.Render("📋 Logs"),
+		m.Styles.Title.Render("📋 Logs")+"  "+m.renderLogLegend(),
 		"",
 		m.Viewport.View(),
 	)
@@ -567,29 +753,25 @@ func (m Model) viewLogs() string {
 func (m *Model) updateViewportContent() {
 	var content strings.Builder
 
+	m.logLines = m.logLines[:0]
 	if len(m.Logs) == 0 {
 		content.WriteString(m.Styles.Muted.Render("No logs yet..."))
 	} else {
-		for _, log := range m.Logs {
+		line := 0
+		for i, log := range m.Logs {
 			timeStr := log.Time.Format("15:04:05")
-			var levelStyle lipgloss.Style
-			switch log.Level {
-			case "success":
-				levelStyle = m.Styles.StatusProvisioned
-			case "error":
-				levelStyle = m.Styles.StatusError
-			case "warn":
-				levelStyle = m.Styles.StatusWaiting
-			default:
-				levelStyle = m.Styles.Label
+			if i == m.errorMark {
+				timeStr = "▶ " + timeStr
 			}
 
 			logLine := fmt.Sprintf("[%s] %s [%s] %s",
 				timeStr,
-				levelStyle.Render(log.Level),
+				m.levelStyle(log.Level).Render(log.Level),
 				m.Styles.Highlight.Render(log.Account),
 				log.Message,
 			)
+			m.logLines = append(m.logLines, line)
+			line += strings.Count(log.Message, "\n") + 1
 			content.WriteString(logLine + "\n")
 		}
 	}
@@ -597,9 +779,10 @@ func (m *Model) updateViewportContent() {
 	m.Viewport.SetContent(content.String())
 }
 
-// viewConfig renders the config editor (placeholder)
+// viewConfig renders the config view: the notification providers, until the editor lands
 func (m Model) viewConfig() string {
 	content := m.Styles.Title.Render("⚙️ Configuration") + "\n\n" +
+		m.renderProviders() + "\n\n" +
 		m.Styles.Muted.Render("Config editor coming soon...")
 
 	// Force content to fill available vertical space to push Footer to bottom
@@ -625,6 +808,13 @@ func (m Model) viewHelp() string {
 		{"c / 3", "Configuration"},
 		{"p", "Pause provisioning"},
 		{"r", "Resume provisioning"},
+		{"space", "Pause/resume selected account"},
+		{"t", "Switch region/AD of selected account"},
+		{"b", "Save a debug bundle for bug reports"},
+		{"+ / -", "Longer/shorter cycle interval (saved to config)"},
+		{"] / [", "Longer/shorter delay between accounts"},
+		{"n / N", "Next/previous error (log viewer)"},
+		{"enter", "Send a test notification (config view)"},
 		{"↑/k", "Navigate up"},
 		{"↓/j", "Navigate down"},
 		{"?", "Toggle help"},
@@ -646,6 +836,32 @@ func (m Model) viewHelp() string {
 	return lipgloss.NewStyle().Height(height).Render(content.String())
 }
 
+// writeBundle saves a debug bundle with a snapshot of the current screen and the
+// attempt history of this run to the working directory.
+func (m Model) writeBundle() {
+	opts := bundle.Options{LogDir: "logs", Status: m.View()}
+	if data, err := yaml.Marshal(m.Config); err == nil {
+		opts.Config = data
+	}
+	if m.Config != nil {
+		opts.StateFile = m.Config.StateFile
+	}
+	if m.Tracker != nil {
+		stats := m.Tracker.Snapshot()
+		opts.Stats = &stats
+	}
+
+	path, err := bundle.Create(".", opts)
+	if m.Runner == nil {
+		return
+	}
+	if err != nil {
+		m.Runner.Logger.Error("TUI", fmt.Sprintf("Failed to create debug bundle: %v", err))
+		return
+	}
+	m.Runner.Logger.Success("TUI", fmt.Sprintf("📦 Debug bundle saved to %s", path))
+}
+
 // max returns the maximum of two integers
 func max(a, b int) int {
 	if a > b {
@@ -664,8 +880,9 @@ func (m *Model) UpdateAccountStatus(name string, status AccountStatus) {
 	}
 }
 
-// Run starts the TUI application with full provisioner integration
-func Run(cfg *config.Config, tracker *notifier.Tracker, l *logger.Logger) error {
+// Run starts the TUI application with full provisioner integration.
+// path is the config file, updated when an account is switched to another region/AD.
+func Run(cfg *config.Config, path string, tracker *notifier.Tracker, l *logger.Logger) error {
 	// 1. Silence console output to prevent TUI corruption
 	// We'll restore it when TUI exits (though usually program exits then)
 	l.SetConsoleOutput(io.Discard)
@@ -673,32 +890,32 @@ func Run(cfg *config.Config, tracker *notifier.Tracker, l *logger.Logger) error
 	// Create the provisioner runner
 	runner := NewProvisionerRunner(cfg, l, tracker)
 
-	// 2. Hook logger to TUI log channel
-	// This captures logs from the provisioner (which uses l) and sends them to the TUI
-	l.AddHook(func(level, account, msg string) {
-		entry := LogEntry{
-			Time:    time.Now(),
-			Level:   strings.ToLower(level),
-			Account: account,
-			Message: msg,
-		}
-		// Non-blocking send to avoid blocking the provisioner
-		select {
-		case runner.logChan <- entry:
-		default:
-			// Drop log if channel full to prevent blocking
-		}
-	})
+	// 2. Keep the provisioner's log lines (it uses l) for the log view and the API.
+	// Adding never blocks; a reader that falls behind skips the oldest lines.
+	l.AddHook(runner.Logs.Add)
 
 	// Create TUI model with runner
-	model := New(cfg, tracker, runner)
+	model := New(cfg, path, tracker, runner)
+	opts := []tea.ProgramOption{tea.WithAltScreen(), tea.WithMouseCellMotion()}
+	if l.Accessible() {
+		// Unstyled, in the main screen, so screen readers can review the output
+		model.Accessible = true
+		model.Styles = Styles{}
+		opts = nil
+	}
 
 	// Create and run the program
-	p := tea.NewProgram(model, tea.WithAltScreen(), tea.WithMouseCellMotion())
+	p := tea.NewProgram(model, opts...)
 	_, err := p.Run()
 
 	// Stop the runner when TUI exits
 	runner.Stop()
+	runner.Provisioner.Notifier.Flush()
+
+	// Restore console output so the run summary is visible after the alt screen closes
+	l.SetConsoleOutput(os.Stdout)
+	runner.Provisioner.ReportExit()
+	runner.Provisioner.Events.Close()
 
 	return err
 }
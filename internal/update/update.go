@@ -0,0 +1,291 @@
+// Package update checks GitHub releases for newer versions and replaces the
+// running binary in place.
+package update
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Repo is the GitHub repository releases are published to.
+const Repo = "joaodalvi/oci-arm-provisioner"
+
+// binaryName matches the project_name used by .goreleaser.yaml.
+const binaryName = "oci-arm-provisioner"
+
+// MaxDownloadSize bounds release archive downloads.
+const MaxDownloadSize = 100 * 1024 * 1024
+
+// Release is the subset of the GitHub release API response we use.
+type Release struct {
+	TagName string  `json:"tag_name"`
+	HTMLURL string  `json:"html_url"`
+	Assets  []Asset `json:"assets"`
+}
+
+// Asset is a downloadable file attached to a release.
+type Asset struct {
+	Name               string `json:"name"`
+	BrowserDownloadURL string `json:"browser_download_url"`
+}
+
+// Version returns the release tag without its leading "v".
+func (r *Release) Version() string {
+	return strings.TrimPrefix(r.TagName, "v")
+}
+
+// Checker queries the GitHub API. BaseURL is overridable for tests.
+type Checker struct {
+	Client  *http.Client
+	BaseURL string
+}
+
+// NewChecker creates a Checker for the public GitHub API.
+func NewChecker() *Checker {
+	return &Checker{
+		Client:  &http.Client{Timeout: 30 * time.Second},
+		BaseURL: "https://api.github.com",
+	}
+}
+
+// Latest fetches the most recent published release.
+func (c *Checker) Latest(ctx context.Context) (*Release, error) {
+	url := fmt.Sprintf("%s/repos/%s/releases/latest", c.BaseURL, Repo)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("req creation failed: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("api returned status: %d", resp.StatusCode)
+	}
+
+	var rel Release
+	if err := json.NewDecoder(resp.Body).Decode(&rel); err != nil {
+		return nil, fmt.Errorf("failed to decode release: %w", err)
+	}
+	return &rel, nil
+}
+
+// IsNewer reports whether version latest is strictly greater than current.
+// Versions are compared as dotted integers; a non-numeric current (e.g. "dev")
+// is never considered outdated.
+func IsNewer(latest, current string) bool {
+	l, ok1 := parseVersion(latest)
+	c, ok2 := parseVersion(current)
+	if !ok1 || !ok2 {
+		return false
+	}
+	for i := 0; i < 3; i++ {
+		if l[i] != c[i] {
+			return l[i] > c[i]
+		}
+	}
+	return false
+}
+
+func parseVersion(v string) ([3]int, bool) {
+	var out [3]int
+	v = strings.TrimPrefix(v, "v")
+	// Drop pre-release/build suffixes (e.g. "1.2.3-rc1").
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+	parts := strings.Split(v, ".")
+	if len(parts) == 0 || len(parts) > 3 {
+		return out, false
+	}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return out, false
+		}
+		out[i] = n
+	}
+	return out, true
+}
+
+// AssetName returns the archive name goreleaser produces for this platform.
+func AssetName(version, goos, goarch string) string {
+	ext := "tar.gz"
+	if goos == "windows" {
+		ext = "zip"
+	}
+	return fmt.Sprintf("%s_%s_%s_%s.%s", binaryName, version, goos, goarch, ext)
+}
+
+// Apply downloads the release archive for the running platform, verifies it
+// against the release checksums, and swaps it in for the binary at exePath.
+func (c *Checker) Apply(ctx context.Context, rel *Release, exePath string) error {
+	name := AssetName(rel.Version(), runtime.GOOS, runtime.GOARCH)
+
+	var archiveURL, checksumsURL string
+	for _, a := range rel.Assets {
+		switch a.Name {
+		case name:
+			archiveURL = a.BrowserDownloadURL
+		case "checksums.txt":
+			checksumsURL = a.BrowserDownloadURL
+		}
+	}
+	if archiveURL == "" {
+		return fmt.Errorf("release %s has no asset %s", rel.TagName, name)
+	}
+	if checksumsURL == "" {
+		return fmt.Errorf("release %s has no checksums.txt", rel.TagName)
+	}
+
+	archive, err := c.download(ctx, archiveURL)
+	if err != nil {
+		return fmt.Errorf("failed to download %s: %w", name, err)
+	}
+	sums, err := c.download(ctx, checksumsURL)
+	if err != nil {
+		return fmt.Errorf("failed to download checksums: %w", err)
+	}
+	if err := verifyChecksum(archive, sums, name); err != nil {
+		return err
+	}
+
+	bin, err := extractBinary(archive, runtime.GOOS == "windows")
+	if err != nil {
+		return err
+	}
+	return replaceExecutable(exePath, bin)
+}
+
+func (c *Checker) download(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("status %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, MaxDownloadSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MaxDownloadSize {
+		return nil, fmt.Errorf("download exceeds %d bytes", MaxDownloadSize)
+	}
+	return data, nil
+}
+
+// verifyChecksum checks data against the sha256 line for name in a goreleaser checksums.txt.
+func verifyChecksum(data, sums []byte, name string) error {
+	sum := sha256.Sum256(data)
+	got := hex.EncodeToString(sum[:])
+
+	scanner := bufio.NewScanner(bytes.NewReader(sums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[1] == name {
+			if !strings.EqualFold(fields[0], got) {
+				return fmt.Errorf("checksum mismatch for %s", name)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("no checksum listed for %s", name)
+}
+
+// extractBinary pulls the provisioner executable out of a release archive.
+func extractBinary(archive []byte, isZip bool) ([]byte, error) {
+	if isZip {
+		zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to open zip: %w", err)
+		}
+		for _, f := range zr.File {
+			if filepath.Base(f.Name) == binaryName+".exe" {
+				rc, err := f.Open()
+				if err != nil {
+					return nil, err
+				}
+				defer rc.Close()
+				return io.ReadAll(io.LimitReader(rc, MaxDownloadSize))
+			}
+		}
+		return nil, fmt.Errorf("%s.exe not found in archive", binaryName)
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open gzip: %w", err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tar: %w", err)
+		}
+		if hdr.Typeflag == tar.TypeReg && filepath.Base(hdr.Name) == binaryName {
+			return io.ReadAll(io.LimitReader(tr, MaxDownloadSize))
+		}
+	}
+	return nil, fmt.Errorf("%s not found in archive", binaryName)
+}
+
+// replaceExecutable swaps the binary at path for bin. The old binary is moved
+// aside first because Windows refuses to overwrite a running executable.
+func replaceExecutable(path string, bin []byte) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to stat executable: %w", err)
+	}
+
+	newPath := path + ".new"
+	oldPath := path + ".old"
+	if err := os.WriteFile(newPath, bin, info.Mode().Perm()|0100); err != nil {
+		return fmt.Errorf("failed to write new binary: %w", err)
+	}
+
+	os.Remove(oldPath)
+	if err := os.Rename(path, oldPath); err != nil {
+		os.Remove(newPath)
+		return fmt.Errorf("failed to move current binary aside: %w", err)
+	}
+	if err := os.Rename(newPath, path); err != nil {
+		// Roll back so the user is not left without a binary.
+		os.Rename(oldPath, path)
+		return fmt.Errorf("failed to install new binary: %w", err)
+	}
+
+	if runtime.GOOS != "windows" {
+		os.Remove(oldPath)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package update
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIsNewer(t *testing.T) {
+	cases := []struct {
+		latest, current string
+		want            bool
+	}{
+		{"0.3.0", "0.2.1", true},
+		{"v0.2.2", "0.2.1", true},
+		{"1.0.0", "0.9.9", true},
+		{"0.2.1", "0.2.1", false},
+		{"0.2.0", "0.2.1", false},
+		{"0.3.0-rc1", "0.2.1", true},
+		{"0.3.0", "dev", false},
+		{"garbage", "0.2.1", false},
+	}
+	for _, c := range cases {
+		if got := IsNewer(c.latest, c.current); got != c.want {
+			t.Errorf("IsNewer(%q, %q) = %v, want %v", c.latest, c.current, got, c.want)
+		}
+	}
+}
+
+func TestChecker_Latest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/"+Repo+"/releases/latest" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		fmt.Fprint(w, `{"tag_name":"v0.9.0","html_url":"https://example.com/rel","assets":[{"name":"checksums.txt","browser_download_url":"https://example.com/c"}]}`)
+	}))
+	defer srv.Close()
+
+	c := NewChecker()
+	c.BaseURL = srv.URL
+
+	rel, err := c.Latest(context.Background())
+	if err != nil {
+		t.Fatalf("Latest failed: %v", err)
+	}
+	if rel.Version() != "0.9.0" {
+		t.Errorf("expected version 0.9.0, got %s", rel.Version())
+	}
+	if len(rel.Assets) != 1 || rel.Assets[0].Name != "checksums.txt" {
+		t.Errorf("unexpected assets: %+v", rel.Assets)
+	}
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	data := []byte("archive-bytes")
+	sum := sha256.Sum256(data)
+	sums := []byte(hex.EncodeToString(sum[:]) + "  app_1.0.0_linux_amd64.tar.gz\n")
+
+	if err := verifyChecksum(data, sums, "app_1.0.0_linux_amd64.tar.gz"); err != nil {
+		t.Errorf("expected checksum to match: %v", err)
+	}
+	if err := verifyChecksum([]byte("tampered"), sums, "app_1.0.0_linux_amd64.tar.gz"); err == nil {
+		t.Error("expected checksum mismatch")
+	}
+	if err := verifyChecksum(data, sums, "missing.tar.gz"); err == nil {
+		t.Error("expected error for unlisted asset")
+	}
+}
+
+func TestExtractAndReplace(t *testing.T) {
+	// Build a tar.gz shaped like a goreleaser archive.
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, body := range map[string]string{"README.md": "readme", binaryName: "new-binary"} {
+		tw.WriteHeader(&tar.Header{Name: name, Mode: 0755, Size: int64(len(body)), Typeflag: tar.TypeReg})
+		tw.Write([]byte(body))
+	}
+	tw.Close()
+	gz.Close()
+
+	bin, err := extractBinary(buf.Bytes(), false)
+	if err != nil {
+		t.Fatalf("extract failed: %v", err)
+	}
+	if string(bin) != "new-binary" {
+		t.Fatalf("unexpected binary content %q", bin)
+	}
+
+	exe := filepath.Join(t.TempDir(), binaryName)
+	if err := os.WriteFile(exe, []byte("old-binary"), 0755); err != nil {
+		t.Fatalf("failed to write fake exe: %v", err)
+	}
+	if err := replaceExecutable(exe, bin); err != nil {
+		t.Fatalf("replace failed: %v", err)
+	}
+	got, _ := os.ReadFile(exe)
+	if string(got) != "new-binary" {
+		t.Errorf("expected replaced binary, got %q", got)
+	}
+}
@@ -2,12 +2,14 @@ package wizard
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"text/template"
 
+	"github.com/yourusername/oci-arm-provisioner/internal/config"
 	"github.com/yourusername/oci-arm-provisioner/internal/logger"
 )
 
@@ -29,32 +31,49 @@ func RunOCI(l *logger.Logger) {
 
 	// 2. Credentials
 	fmt.Println("\n--- Credentials ---")
-	fmt.Println("Find these in OCI Console -> Profile -> Tenancy / User Settings.")
+	var userOCID, tenancyOCID, fingerprint, region, keyPath string
+	fmt.Print("👉 Paste the config snippet shown after adding an API key in the Console? (y/N): ")
+	usePaste, _ := reader.ReadString('\n')
+	if strings.ToLower(strings.TrimSpace(usePaste)) == "y" {
+		fmt.Println("Paste it below, then press ENTER on an empty line:")
+		p, err := ParseOCISnippet(reader)
+		if err != nil {
+			l.Error("WIZARD", fmt.Sprintf("Could not read snippet: %v", err))
+		} else {
+			userOCID, tenancyOCID, fingerprint, region, keyPath = p.User, p.Tenancy, p.Fingerprint, p.Region, p.KeyFile
+		}
+	}
+
+	if userOCID == "" {
+		fmt.Println("Find these in OCI Console -> Profile -> Tenancy / User Settings.")
 
-	fmt.Print("👉 User OCID (ocid1.user...): ")
-	userOCID, _ := reader.ReadString('\n')
-	userOCID = strings.TrimSpace(userOCID)
+		fmt.Print("👉 User OCID (ocid1.user...): ")
+		userOCID, _ = reader.ReadString('\n')
+		userOCID = strings.TrimSpace(userOCID)
 
-	fmt.Print("👉 Tenancy OCID (ocid1.tenancy...): ")
-	tenancyOCID, _ := reader.ReadString('\n')
-	tenancyOCID = strings.TrimSpace(tenancyOCID)
+		fmt.Print("👉 Tenancy OCID (ocid1.tenancy...): ")
+		tenancyOCID, _ = reader.ReadString('\n')
+		tenancyOCID = strings.TrimSpace(tenancyOCID)
 
-	fmt.Print("👉 API Key Fingerprint (xx:xx:xx...): ")
-	fingerprint, _ := reader.ReadString('\n')
-	fingerprint = strings.TrimSpace(fingerprint)
+		fmt.Print("👉 API Key Fingerprint (xx:xx:xx...): ")
+		fingerprint, _ = reader.ReadString('\n')
+		fingerprint = strings.TrimSpace(fingerprint)
 
-	fmt.Print("👉 Region (e.g. us-ashburn-1, sa-saopaulo-1): ")
-	region, _ := reader.ReadString('\n')
-	region = strings.TrimSpace(region)
+		fmt.Print("👉 Region (e.g. us-ashburn-1, sa-saopaulo-1): ")
+		region, _ = reader.ReadString('\n')
+		region = strings.TrimSpace(region)
+	}
 
 	// 3. Key File
-	fmt.Println("\n--- API Key ---")
-	fmt.Println("Path to your private key file (PEM).")
-	fmt.Print("👉 Path (default '~/.oci/oci_api_key.pem'): ")
-	keyPath, _ := reader.ReadString('\n')
-	keyPath = strings.TrimSpace(keyPath)
 	if keyPath == "" {
-		keyPath = "~/.oci/oci_api_key.pem"
+		fmt.Println("\n--- API Key ---")
+		fmt.Println("Path to your private key file (PEM).")
+		fmt.Printf("👉 Path (default '%s'): ", defaultKeyPath)
+		keyPath, _ = reader.ReadString('\n')
+		keyPath = strings.TrimSpace(keyPath)
+		if keyPath == "" {
+			keyPath = defaultKeyPath
+		}
 	}
 
 	// Validate Key Path (simple check)
@@ -146,7 +165,7 @@ const configTemplate = `accounts:
     shape: "{{.Shape}}"
     ocpus: {{.OCPUs}}
     memory_gb: {{.Memory}}
-    image_ocid: "ocid1.image.oc1.iad.aaaa..." # TODO: UPDATE THIS ID FROM ORACLE DOCS FOR YOUR REGION
+    image: "ubuntu-22.04-arm" # Or image_ocid: a specific image of your region
     ssh_public_key: "{{.SSHKey}}"
     boot_volume_size_gb: 50
     display_name: "arm-instance-1"
@@ -184,25 +203,11 @@ type configData struct {
 }
 
 func saveOCIConfig(path, profile, user, tenancy, finger, key, region, compartment, shape string, ocpus, memory float32, ssh string) error {
-	if _, err := os.Stat(path); err == nil {
-		// File exists
-		// For now, we backup and overwrite, OR we could warn.
-		// "Pro" move is to backup.
-		os.Rename(path, path+".bak")
-		fmt.Printf("⚠️  Existing %s moved to %s.bak\n", path, path)
-	}
-
 	t, err := template.New("config").Parse(configTemplate)
 	if err != nil {
 		return err
 	}
 
-	f, err := os.Create(path)
-	if err != nil {
-		return err
-	}
-	defer f.Close()
-
 	data := configData{
 		ProfileName:     profile,
 		UserOCID:        user,
@@ -217,5 +222,14 @@ func saveOCIConfig(path, profile, user, tenancy, finger, key, region, compartmen
 		SSHKey:          ssh,
 	}
 
-	return t.Execute(f, data)
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return err
+	}
+
+	// Existing configs are backed up by config.Write (see `config rollback`)
+	if _, err := os.Stat(path); err == nil {
+		fmt.Printf("⚠️  Existing %s replaced (previous version saved in %s)\n", path, config.HistoryDir)
+	}
+	return config.Write(path, buf.Bytes(), "setup wizard")
 }
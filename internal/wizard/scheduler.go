@@ -0,0 +1,102 @@
+package wizard
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/yourusername/oci-arm-provisioner/internal/config"
+	"github.com/yourusername/oci-arm-provisioner/internal/logger"
+)
+
+// ProposeScheduler returns the scheduler timings of preset p for accounts launching
+// from one IP across regions. The preset's account delay is read as the shortest
+// average gap between requests to one region, its cycle interval as how often each
+// account tries; the attempts are then spread evenly over the resulting loop.
+func ProposeScheduler(p config.SchedulerPreset, accounts, regions int) config.SchedulerConfig {
+	accounts = max(accounts, 1)
+	regions = min(max(regions, 1), accounts)
+	perRegion := (accounts + regions - 1) / regions
+
+	s := p.Scheduler
+	loop := max(s.CycleIntervalSeconds, perRegion*s.AccountDelaySeconds)
+	s.AccountDelaySeconds = loop / accounts
+	s.CycleIntervalSeconds = max(loop-(accounts-1)*s.AccountDelaySeconds, config.MinCycleInterval)
+	if accounts == 1 {
+		s.AccountDelaySeconds = 0
+	}
+	s.Preset = p.Name
+	return s
+}
+
+// TuneScheduler asks how many accounts and regions run from this IP and how hard to
+// push, proposes scheduler timings and, once confirmed, writes them to the config at path.
+func TuneScheduler(l *logger.Logger, path string, in *bufio.Reader) error {
+	accounts, regions := 1, 1
+	if cfg, _, err := config.LoadConfig(path); err == nil {
+		accounts, regions = countAccounts(cfg)
+	}
+
+	l.Section("⏱️  Scheduler Tuning")
+	accounts = askInt(in, "👉 How many accounts run from this machine's IP", accounts)
+	regions = askInt(in, "👉 In how many regions do they launch", regions)
+
+	fmt.Println("\nHow hard should they push?")
+	for i, p := range config.SchedulerPresets {
+		fmt.Printf("%d. %s - %s\n", i+1, p.Name, p.Description)
+	}
+	choice := askInt(in, fmt.Sprintf("Enter choice (1-%d)", len(config.SchedulerPresets)), 2)
+	if choice > len(config.SchedulerPresets) {
+		return fmt.Errorf("invalid choice %d", choice)
+	}
+	s := ProposeScheduler(config.SchedulerPresets[choice-1], accounts, regions)
+
+	fmt.Printf("\nProposed scheduler (preset %s):\n", s.Preset)
+	fmt.Printf("  cycle_interval_seconds:   %d\n", s.CycleIntervalSeconds)
+	fmt.Printf("  account_delay_seconds:    %d\n", s.AccountDelaySeconds)
+	fmt.Printf("  tenancy_interval_seconds: %d\n", s.TenancyIntervalSeconds)
+	fmt.Printf("  jitter_percent:           %d\n", s.JitterPercent)
+	fmt.Printf("Each account tries about every %ds.\n", s.CycleIntervalSeconds+(accounts-1)*s.AccountDelaySeconds)
+	fmt.Printf("👉 Write this to %s? [Y/n]: ", path)
+	answer, _ := in.ReadString('\n')
+	if answer = strings.ToLower(strings.TrimSpace(answer)); answer != "" && answer != "y" && answer != "yes" {
+		fmt.Println("Nothing written.")
+		return nil
+	}
+
+	if err := config.SetSchedulerFields(path, []config.AccountField{
+		{Key: "preset", Value: s.Preset},
+		{Key: "cycle_interval_seconds", Value: s.CycleIntervalSeconds},
+		{Key: "account_delay_seconds", Value: s.AccountDelaySeconds},
+		{Key: "tenancy_interval_seconds", Value: s.TenancyIntervalSeconds},
+		{Key: "jitter_percent", Value: s.JitterPercent},
+	}, "config tune"); err != nil {
+		return err
+	}
+	l.Success("WIZARD", fmt.Sprintf("✅ Saved the %s scheduler to %s", s.Preset, path))
+	return nil
+}
+
+// countAccounts returns the number of enabled accounts in cfg and of their regions.
+func countAccounts(cfg *config.Config) (accounts, regions int) {
+	seen := make(map[string]bool)
+	for _, acc := range cfg.Accounts {
+		if acc.Enabled {
+			accounts++
+			seen[acc.Region] = true
+		}
+	}
+	return max(accounts, 1), max(len(seen), 1)
+}
+
+// askInt prompts for a positive number, returning def on an empty or invalid answer.
+func askInt(in *bufio.Reader, prompt string, def int) int {
+	fmt.Printf("%s (default %d): ", prompt, def)
+	answer, _ := in.ReadString('\n')
+	n, err := strconv.Atoi(strings.TrimSpace(answer))
+	if err != nil || n < 1 {
+		return def
+	}
+	return n
+}
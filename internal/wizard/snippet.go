@@ -0,0 +1,166 @@
+package wizard
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/yourusername/oci-arm-provisioner/internal/config"
+	"github.com/yourusername/oci-arm-provisioner/internal/logger"
+)
+
+// defaultKeyPath is where the OCI CLI and the Console instructions put the API key.
+const defaultKeyPath = "~/.oci/oci_api_key.pem"
+
+// OCIProfile is the content of the "Configuration file preview" the OCI Console shows
+// after adding an API key:
+//
+//	[DEFAULT]
+//	user=ocid1.user.oc1..aaaa
+//	fingerprint=12:34:...
+//	tenancy=ocid1.tenancy.oc1..aaaa
+//	region=us-ashburn-1
+//	key_file=<path to your private keyfile> # TODO
+type OCIProfile struct {
+	Name        string // Section name, e.g. "DEFAULT".
+	User        string
+	Fingerprint string
+	Tenancy     string
+	Region      string
+	KeyFile     string // Empty when the snippet still has the placeholder.
+}
+
+// ParseOCISnippet reads a pasted snippet until EOF or the first blank line after it.
+func ParseOCISnippet(r *bufio.Reader) (OCIProfile, error) {
+	var p OCIProfile
+	seen := false
+	for {
+		line, err := r.ReadString('\n')
+		line = strings.TrimSpace(line)
+		if line == "" && seen {
+			break
+		}
+		if line != "" && !strings.HasPrefix(line, "#") {
+			if perr := p.parseLine(line); perr != nil {
+				return p, perr
+			}
+			seen = true
+		}
+		if err != nil {
+			break
+		}
+	}
+
+	if p.User == "" || p.Tenancy == "" || p.Fingerprint == "" || p.Region == "" {
+		return p, errors.New("snippet must contain user, fingerprint, tenancy and region")
+	}
+	return p, nil
+}
+
+func (p *OCIProfile) parseLine(line string) error {
+	if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+		p.Name = strings.Trim(line, "[]")
+		return nil
+	}
+	key, value, ok := strings.Cut(line, "=")
+	if !ok {
+		return fmt.Errorf("unexpected line in snippet: %q", line)
+	}
+	if i := strings.Index(value, " #"); i >= 0 {
+		value = value[:i] // "key_file=<path to your private keyfile> # TODO"
+	}
+	value = strings.TrimSpace(value)
+	switch strings.TrimSpace(key) {
+	case "user":
+		p.User = value
+	case "fingerprint":
+		p.Fingerprint = value
+	case "tenancy":
+		p.Tenancy = value
+	case "region":
+		p.Region = value
+	case "key_file":
+		if !strings.HasPrefix(value, "<") {
+			p.KeyFile = value
+		}
+	}
+	return nil
+}
+
+// AccountName returns a config-friendly account name for the profile.
+func (p OCIProfile) AccountName() string {
+	if p.Name == "" {
+		return "default"
+	}
+	return strings.ToLower(p.Name)
+}
+
+// ImportOCISnippet reads a Console config snippet from in and adds it as a new account
+// to the config at path (creating the file if needed). name and keyFile override the
+// snippet; if the snippet has no key path the user is asked for one.
+func ImportOCISnippet(l *logger.Logger, path string, in *bufio.Reader, name, keyFile string) error {
+	p, err := ParseOCISnippet(in)
+	if err != nil {
+		return err
+	}
+
+	if name == "" {
+		name = p.AccountName()
+	}
+	if keyFile == "" {
+		keyFile = p.KeyFile
+	}
+	if keyFile == "" {
+		fmt.Printf("👉 Path to the private key you downloaded (default '%s'): ", defaultKeyPath)
+		keyFile, _ = in.ReadString('\n')
+		keyFile = strings.TrimSpace(keyFile)
+		if keyFile == "" {
+			keyFile = defaultKeyPath
+		}
+	}
+
+	// No config yet: generate a complete one from the wizard template
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		if err := saveOCIConfig(path, name, p.User, p.Tenancy, p.Fingerprint, keyFile, p.Region, p.Tenancy, "VM.Standard.A1.Flex", 4, 24, ""); err != nil {
+			return err
+		}
+		l.Success("WIZARD", fmt.Sprintf("✅ Created %s with account '%s'", path, name))
+		fmt.Println("Next: set image (e.g. ubuntu-22.04-arm) and ssh_public_key for the account.")
+		return nil
+	}
+
+	fields := []config.AccountField{
+		{Key: "enabled", Value: true},
+		{Key: "user_ocid", Value: p.User},
+		{Key: "tenancy_ocid", Value: p.Tenancy},
+		{Key: "fingerprint", Value: p.Fingerprint},
+		{Key: "key_file", Value: keyFile},
+		{Key: "region", Value: p.Region},
+		{Key: "compartment_ocid", Value: p.Tenancy},
+	}
+	// Without a shared 'defaults:' block the account needs its own instance specs.
+	hasDefaults, err := config.HasDefaults(path)
+	if err != nil {
+		return err
+	}
+	if !hasDefaults {
+		fields = append(fields,
+			config.AccountField{Key: "availability_domain", Value: "auto"},
+			config.AccountField{Key: "shape", Value: "VM.Standard.A1.Flex"},
+			config.AccountField{Key: "ocpus", Value: 4},
+			config.AccountField{Key: "memory_gb", Value: 24},
+			config.AccountField{Key: "boot_volume_size_gb", Value: 50},
+		)
+	}
+
+	if err := config.AddAccount(path, name, fields, "import-oci-snippet"); err != nil {
+		return err
+	}
+	l.Success("WIZARD", fmt.Sprintf("✅ Added account '%s' to %s", name, path))
+	if !hasDefaults {
+		fmt.Println("Next: set image (e.g. ubuntu-22.04-arm) and ssh_public_key for the account.")
+	}
+	return nil
+}
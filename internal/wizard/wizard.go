@@ -28,11 +28,13 @@ func RunNotifications(l *logger.Logger) {
 	fmt.Println("1. Discord / Slack")
 	fmt.Println("2. Telegram")
 	fmt.Println("3. Ntfy.sh (Zero Setup)")
-	fmt.Print("Enter choice (1/2/3): ")
+	fmt.Println("4. Gotify")
+	fmt.Println("5. Microsoft Teams")
+	fmt.Print("Enter choice (1-5): ")
 	choice, _ := reader.ReadString('\n')
 	choice = strings.TrimSpace(choice)
 
-	var webhookURL, telegramToken, telegramChatID, ntfyTopic, gotifyURL, gotifyToken string
+	var webhookURL, telegramToken, telegramChatID, ntfyTopic, gotifyURL, gotifyToken, teamsURL string
 
 	if choice == "1" {
 		// Discord/Slack Flow
@@ -99,6 +101,15 @@ func RunNotifications(l *logger.Logger) {
 		fmt.Print("👉 Enter App Token: ")
 		gotifyToken, _ = reader.ReadString('\n')
 		gotifyToken = strings.TrimSpace(gotifyToken)
+	} else if choice == "5" {
+		// Teams Flow
+		fmt.Println("\n--- Microsoft Teams Setup ---")
+		fmt.Println("1. In the channel, open ... -> Workflows.")
+		fmt.Println("2. Pick 'Post to a channel when a webhook request is received' and finish the setup.")
+		fmt.Println("3. Copy the URL it shows.")
+		fmt.Print("👉 Paste the Workflow URL: ")
+		teamsURL, _ = reader.ReadString('\n')
+		teamsURL = strings.TrimSpace(teamsURL)
 	} else {
 		l.Error("WIZARD", "Invalid choice.")
 		return
@@ -107,13 +118,14 @@ func RunNotifications(l *logger.Logger) {
 	// 2. Test Configuration
 	fmt.Println("\nTesting connection...")
 	testCfg := config.NotificationConfig{
-		Enabled:        true,
-		WebhookURL:     webhookURL,
-		TelegramToken:  telegramToken,
-		TelegramChatID: telegramChatID,
-		NtfyTopic:      ntfyTopic,
-		GotifyURL:      gotifyURL,
-		GotifyToken:    gotifyToken,
+		Enabled:         true,
+		WebhookURL:      webhookURL,
+		TelegramToken:   telegramToken,
+		TelegramChatID:  telegramChatID,
+		NtfyTopic:       ntfyTopic,
+		GotifyURL:       gotifyURL,
+		GotifyToken:     gotifyToken,
+		TeamsWebhookURL: teamsURL,
 	}
 	n := notifier.New(testCfg)
 
@@ -130,7 +142,7 @@ func RunNotifications(l *logger.Logger) {
 	}
 
 	// 3. Save to Config
-	if err := saveConfig(webhookURL, telegramToken, telegramChatID, ntfyTopic, gotifyURL, gotifyToken); err != nil {
+	if err := saveConfig(webhookURL, telegramToken, telegramChatID, ntfyTopic, gotifyURL, gotifyToken, teamsURL); err != nil {
 		l.Error("WIZARD", fmt.Sprintf("Failed to save config: %v", err))
 	} else {
 		l.Success("WIZARD", "✅ Config updated successfully!")
@@ -170,7 +182,7 @@ func pollTelegramChatID(token string) (string, error) {
 }
 
 // saveConfig updates valid fields in config.yaml
-func saveConfig(webhook, tgToken, tgChatID, ntfyTopic, gotifyURL, gotifyToken string) error {
+func saveConfig(webhook, tgToken, tgChatID, ntfyTopic, gotifyURL, gotifyToken, teamsURL string) error {
 	path := "config.yaml"
 	content, err := os.ReadFile(path)
 	if err != nil {
@@ -199,6 +211,9 @@ func saveConfig(webhook, tgToken, tgChatID, ntfyTopic, gotifyURL, gotifyToken st
 	if gotifyToken != "" {
 		replacements["gotify_token"] = gotifyToken
 	}
+	if teamsURL != "" {
+		replacements["teams_webhook_url"] = teamsURL
+	}
 
 	// Allow adding missing keys if logic permits, but regex replacement is safer for existing files.
 	// For simplicity, we assume keys exist or we warn.
@@ -234,6 +249,5 @@ func saveConfig(webhook, tgToken, tgChatID, ntfyTopic, gotifyURL, gotifyToken st
 	}
 
 	output := strings.Join(updatedLines, "\n")
-	info, _ := os.Stat(path)
-	return os.WriteFile(path, []byte(output), info.Mode())
+	return config.Write(path, []byte(output), "notification wizard")
 }
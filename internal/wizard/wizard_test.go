@@ -1,15 +1,19 @@
 package wizard
 
 import (
+	"bufio"
+	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/yourusername/oci-arm-provisioner/internal/config"
+	"github.com/yourusername/oci-arm-provisioner/internal/logger"
 )
 
 func TestSaveOCIConfig(t *testing.T) {
-	tmpFile := "config_test_gen.yaml"
-	defer os.Remove(tmpFile)
-	defer os.Remove(tmpFile + ".bak")
+	tmpFile := filepath.Join(t.TempDir(), "config.yaml")
 
 	// 1. Create Config
 	err := saveOCIConfig(
@@ -65,7 +69,126 @@ func TestSaveOCIConfig(t *testing.T) {
 		t.Fatalf("Failed to overwrite config: %v", err)
 	}
 
-	if _, err := os.Stat(tmpFile + ".bak"); os.IsNotExist(err) {
-		t.Error("Backup file was not created on overwrite")
+	history, err := config.History(tmpFile)
+	if err != nil {
+		t.Fatalf("Failed to read config history: %v", err)
+	}
+	if len(history) != 2 || history[1].Backup == "" {
+		t.Errorf("Backup was not recorded on overwrite: %+v", history)
+	}
+}
+
+const consoleSnippet = `[DEFAULT]
+user=ocid1.user.oc1..aaaauser
+fingerprint=12:34:56:78:90:ab:cd:ef
+tenancy=ocid1.tenancy.oc1..aaaatenancy
+region=sa-saopaulo-1
+key_file=<path to your private keyfile> # TODO
+
+`
+
+func TestParseOCISnippet(t *testing.T) {
+	p, err := ParseOCISnippet(bufio.NewReader(strings.NewReader(consoleSnippet + "ignored=after blank line\n")))
+	if err != nil {
+		t.Fatalf("ParseOCISnippet failed: %v", err)
+	}
+	if p.User != "ocid1.user.oc1..aaaauser" || p.Tenancy != "ocid1.tenancy.oc1..aaaatenancy" ||
+		p.Fingerprint != "12:34:56:78:90:ab:cd:ef" || p.Region != "sa-saopaulo-1" {
+		t.Errorf("unexpected profile: %+v", p)
+	}
+	if p.KeyFile != "" {
+		t.Errorf("expected placeholder key_file to be dropped, got %q", p.KeyFile)
+	}
+	if p.AccountName() != "default" {
+		t.Errorf("expected account name 'default', got %q", p.AccountName())
+	}
+
+	if _, err := ParseOCISnippet(bufio.NewReader(strings.NewReader("[DEFAULT]\nregion=x\n"))); err == nil {
+		t.Error("expected error for incomplete snippet")
+	}
+}
+
+func TestImportOCISnippet(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	existing := `# My accounts
+defaults:
+  ocpus: 4
+accounts:
+  personal:
+    enabled: true # keep me
+`
+	if err := os.WriteFile(path, []byte(existing), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	l, _ := logger.New(t.TempDir())
+	in := bufio.NewReader(strings.NewReader(consoleSnippet))
+	if err := ImportOCISnippet(l, path, in, "work", "~/.oci/work.pem"); err != nil {
+		t.Fatalf("ImportOCISnippet failed: %v", err)
+	}
+
+	content, _ := os.ReadFile(path)
+	s := string(content)
+	for _, want := range []string{"# My accounts", "# keep me", "  work:", `key_file: "~/.oci/work.pem"`, `region: "sa-saopaulo-1"`} {
+		if !strings.Contains(s, want) {
+			t.Errorf("config missing %q:\n%s", want, s)
+		}
+	}
+	if strings.Contains(s, "shape:") {
+		t.Error("expected instance specs to come from the defaults block")
+	}
+
+	in = bufio.NewReader(strings.NewReader(consoleSnippet))
+	if err := ImportOCISnippet(l, path, in, "work", "~/.oci/work.pem"); err == nil {
+		t.Error("expected error when the account already exists")
+	}
+}
+
+func TestProposeScheduler(t *testing.T) {
+	balanced, _ := config.LookupSchedulerPreset("balanced")
+	for _, tc := range []struct {
+		accounts, regions int
+		want              string
+	}{
+		{1, 1, "900 0"},   // One account: the preset's cycle
+		{2, 1, "450 450"}, // Spread over the same loop
+		{4, 1, "450 450"}, // Four requests to one region need a longer loop
+		{4, 2, "225 225"}, // Two regions share the load
+	} {
+		s := ProposeScheduler(balanced, tc.accounts, tc.regions)
+		if got := fmt.Sprintf("%d %d", s.CycleIntervalSeconds, s.AccountDelaySeconds); got != tc.want {
+			t.Errorf("%d accounts in %d regions: expected %s, got %s", tc.accounts, tc.regions, tc.want, got)
+		}
+		if s.Preset != "balanced" || s.JitterPercent != 10 {
+			t.Errorf("expected the preset's name and jitter, got %+v", s)
+		}
+	}
+}
+
+func TestTuneScheduler(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("# My config\nscheduler:\n  poll_interval_seconds: 10 # keep me\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	l, _ := logger.New(t.TempDir())
+
+	// Declined: nothing changes
+	if err := TuneScheduler(l, path, bufio.NewReader(strings.NewReader("3\n1\n3\nn\n"))); err != nil {
+		t.Fatalf("TuneScheduler failed: %v", err)
+	}
+	if content, _ := os.ReadFile(path); strings.Contains(string(content), "preset") {
+		t.Errorf("expected nothing written when declined:\n%s", content)
+	}
+
+	// 3 accounts in one region, aggressive
+	if err := TuneScheduler(l, path, bufio.NewReader(strings.NewReader("3\n1\n3\n\n"))); err != nil {
+		t.Fatalf("TuneScheduler failed: %v", err)
+	}
+	content, _ := os.ReadFile(path)
+	s := string(content)
+	for _, want := range []string{"# keep me", `preset: "aggressive"`, "cycle_interval_seconds: 100", "account_delay_seconds: 100", "jitter_percent: 5"} {
+		if !strings.Contains(s, want) {
+			t.Errorf("config missing %q:\n%s", want, s)
+		}
 	}
 }
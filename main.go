@@ -2,78 +2,153 @@ package main
 
 import (
 	"context"
-	"flag"
 	"fmt"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"sync/atomic"
 	"syscall"
 	"time"
 
 	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/cobra"
+	"github.com/yourusername/oci-arm-provisioner/internal/api"
+	"github.com/yourusername/oci-arm-provisioner/internal/buildinfo"
 	"github.com/yourusername/oci-arm-provisioner/internal/config"
+	"github.com/yourusername/oci-arm-provisioner/internal/control"
 	"github.com/yourusername/oci-arm-provisioner/internal/logger"
+	"github.com/yourusername/oci-arm-provisioner/internal/logstore"
+	"github.com/yourusername/oci-arm-provisioner/internal/mqtt"
 	"github.com/yourusername/oci-arm-provisioner/internal/notifier"
 	"github.com/yourusername/oci-arm-provisioner/internal/provisioner"
+	"github.com/yourusername/oci-arm-provisioner/internal/service"
+	"github.com/yourusername/oci-arm-provisioner/internal/statusfile"
 	"github.com/yourusername/oci-arm-provisioner/internal/tui"
+	"github.com/yourusername/oci-arm-provisioner/internal/update"
 	"github.com/yourusername/oci-arm-provisioner/internal/wizard"
 )
 
+// version is overridden at build time via -ldflags "-X main.version=...".
+var version = "0.2.1"
+
+// stateless disables all filesystem writes (--stateless): logs go to stdout only and
+// runtime state is kept in memory, or in a remote state_backend.
+var stateless bool
+
+// accessible forces screen-reader friendly output (--accessible), like config's accessible.
+var accessible bool
+
 func main() {
-	// 0. Parse Flags
-	setupNotifications := flag.Bool("setup-notifications", false, "Run the notification setup wizard")
-	setupOCI := flag.Bool("setup", false, "Run the OCI setup wizard (config.yaml)")
-	headless := flag.Bool("headless", false, "Run in headless mode (log-only, no TUI)")
-	flag.Parse()
+	buildinfo.Version = version
+	// Double-clicking the binary on Windows opens the dashboard, not a "use a terminal" notice.
+	cobra.MousetrapHelpText = ""
+	os.Exit(execute(newRootCmd(), os.Args[1:]))
+}
+
+// run starts the provisioner: the TUI by default, or the log-only loop with --headless.
+// With --demo the TUI runs against a simulated OCI instead of the config's accounts.
+func run(opts rootOptions) error {
+	headless := opts.headless
+
+	// Windows services start in System32; anchor relative paths (logs/) next to the binary.
+	if service.IsWindowsService() {
+		headless = true
+		if exe, err := os.Executable(); err == nil {
+			os.Chdir(filepath.Dir(exe))
+		}
+	}
 
 	// 1. Setup Context with Cancellation
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer cancel()
 
 	// 2. Initialize Logger
-	l, err := logger.New("logs")
-	if err != nil {
-		panic(fmt.Sprintf("Failed to initialize logger: %v", err))
+	l := logger.NewConsole()
+	switch {
+	case stateless:
+		headless = true
+	case !opts.demo: // The demo writes no files either
+		var err error
+		if l, err = logger.New("logs"); err != nil {
+			return fmt.Errorf("failed to initialize logger: %w", err)
+		}
 	}
+	l.SetAccessible(accessible)
+	// Set before the config is read, so a config error is JSON too
+	l.SetJSON(os.Getenv("OCI_LOG_FORMAT") == "json")
 
 	// Wizard Modes
-	if *setupNotifications {
+	if opts.setupNotifications {
 		wizard.RunNotifications(l)
-		return
+		return nil
 	}
-	if *setupOCI {
+	if opts.setup {
 		wizard.RunOCI(l)
-		return
+		return nil
 	}
 
-	// 3. Load Initial Configuration
-	cfg, path, err := config.LoadConfig("")
-	if err != nil {
-		l.Error("INIT", fmt.Sprintf("Failed to load config: %v", err))
-		os.Exit(1)
+	// 3. Load Initial Configuration; the demo has its own, and no file to save changes to
+	cfg, path := config.Demo(), ""
+	if !opts.demo {
+		var err error
+		if cfg, path, err = loadConfig(opts.configPath); err != nil {
+			l.Error("INIT", fmt.Sprintf("Failed to load config: %v", err))
+			return reportedError{err}
+		}
+	}
+
+	applyLoggerOptions(l, cfg)
+	if cfg.Logging.ConsoleFormat == "json" {
+		headless = true // The dashboard would interleave with the JSON stream
 	}
 
 	// 4. Initialize Tracker
 	tracker := notifier.NewTracker()
 
+	if cfg.Updates.CheckOnStartup {
+		go checkForUpdates(ctx, l, cfg)
+	}
+
 	// 5. Run TUI or Headless mode
-	if !*headless {
+	if !headless {
 		// TUI Mode (default) - runs provisioner in background
-		if err := tui.Run(cfg, tracker, l); err != nil {
+		if err := tui.Run(cfg, path, tracker, l); err != nil {
 			l.Error("TUI", fmt.Sprintf("TUI error: %v", err))
-			os.Exit(1)
+			return reportedError{err}
 		}
-		return
+		return nil
 	}
 
 	// Headless Mode (original behavior)
+	if err := service.Run(ctx, func(ctx context.Context) {
+		runHeadless(ctx, l, cfg, path, tracker)
+	}); err != nil {
+		l.Error("SERVICE", fmt.Sprintf("Service error: %v", err))
+		return reportedError{err}
+	}
+	return nil
+}
+
+// runHeadless runs the log-only provisioning loop until ctx is cancelled.
+func runHeadless(ctx context.Context, l *logger.Logger, cfg *config.Config, path string, tracker *notifier.Tracker) {
 	l.Section("🚀 OCI ARM Provisioner (Headless Mode)")
-	l.Plain(fmt.Sprintf("Version: %s", "0.2.1"))
+	l.Plain(fmt.Sprintf("Version: %s", version))
 	l.Plain(fmt.Sprintf("📂 Config: %s", path))
+	if stateless {
+		l.Plain("🔒 Stateless: no files written, state kept in memory")
+	}
+
+	// Recent log lines for the management API, kept from the start
+	logs := logstore.New(logstore.Size)
+	if cfg.API.Listen != "" {
+		l.AddHook(logs.Add)
+	}
 
 	// Initialize Provisioner for headless mode
 	prov := provisioner.New(cfg, l, tracker)
 	logAccountSummary(l, cfg)
+	prov.ProbeRegions(ctx)
+	prov.Discover(ctx)
 
 	watcher, err := fsnotify.NewWatcher()
 	if err != nil {
@@ -157,16 +232,79 @@ func main() {
 		digestTicker.Stop()                                 // Stop immediately
 	}
 
+	// Command files dropped into control_dir (applied here, between cycles)
+	var commands <-chan control.Command
+	if cfg.ControlDir != "" {
+		commands = control.Watch(ctx, cfg.ControlDir, func(file string, err error) {
+			l.Error("CONTROL", fmt.Sprintf("%s: %v", file, err))
+		})
+		l.Plain(fmt.Sprintf("📥 Control Directory: %s", cfg.ControlDir))
+	}
+	var paused atomic.Bool
+
+	// Management API for ocarmctl; its commands are applied like control_dir ones.
+	// The listener keeps the address it started with until the next restart.
+	current := atomic.Pointer[provisioner.Provisioner]{}
+	current.Store(prov)
+	var apiCommands <-chan control.Command
+	if cfg.API.Listen != "" {
+		apiCommands = api.Serve(ctx, cfg.API, func() api.Status {
+			return api.Snapshot(current.Load(), paused.Load())
+		}, logs, func(err error) {
+			l.Error("API", err.Error())
+		})
+		l.Plain(fmt.Sprintf("🔌 Management API: %s", cfg.API.Listen))
+	}
+
 	cycleCount := 1
 
+	// apply runs a control_dir or API command
+	apply := func(cmd control.Command) {
+		l.Info("CONTROL", fmt.Sprintf("📥 %s", cmd))
+		switch cmd.Action {
+		case control.ActionPause, control.ActionResume:
+			pause := cmd.Action == control.ActionPause
+			if cmd.Account == "" {
+				paused.Store(pause)
+			} else if _, known := cfg.Accounts[cmd.Account]; !known {
+				l.Error("CONTROL", fmt.Sprintf("Unknown account '%s'", cmd.Account))
+			} else {
+				prov.SetAccountPaused(cmd.Account, pause)
+			}
+		case control.ActionTrigger:
+			next := cfg.Scheduler.Jitter(interval)
+			runCycle(ctx, l, prov, next, cycleCount)
+			cycleCount++
+			ticker.Reset(next)
+		case control.ActionReload:
+			// The update arrives through configUpdates, on a later pass of this loop
+			go reload(l, path, configUpdates)
+		}
+		writeStatus(l, cfg, prov, paused.Load())
+	}
+
+	if cfg.StatusDir != "" {
+		l.Plain(fmt.Sprintf("🗂️  Status Files: %s", cfg.StatusDir))
+	}
+
 	// Run first cycle immediately
-	runCycle(ctx, l, prov, interval, cycleCount)
+	next := cfg.Scheduler.Jitter(interval)
+	runCycle(ctx, l, prov, next, cycleCount)
 	cycleCount++
+	if next != interval {
+		ticker.Reset(next)
+	}
+	writeStatus(l, cfg, prov, false)
 
 	for {
 		select {
 		case <-ctx.Done():
 			l.Section("Shutdown Signal Received")
+			if err := prov.Notifier.Flush(); err != nil {
+				l.Error("NOTIFIER", fmt.Sprintf("Failed to flush queued notifications: %v", err))
+			}
+			prov.ReportExit()
+			prov.Events.Close()
 			l.Plain("Exiting gracefully...")
 			return
 
@@ -174,28 +312,62 @@ func main() {
 			// Apply New Configuration
 			l.Success("RELOAD", "Configuration applied successfully!")
 
-			// 1. Update Provisioner
+			// 1. Update Provisioner (deliver anything the old notifier still holds)
+			prov.Notifier.Flush()
+			prov.Events.Close()
 			cfg = newCfg
+			applyLoggerOptions(l, cfg)
 			prov = provisioner.New(cfg, l, tracker)
+			current.Store(prov)
 			logAccountSummary(l, cfg)
+			prov.ProbeRegions(ctx)
+			prov.Discover(ctx)
 
 			// 2. Update Ticker if interval changed
 			newInterval := time.Duration(cfg.Scheduler.CycleIntervalSeconds) * time.Second
 			if newInterval != interval {
 				l.Plain(fmt.Sprintf("⏱️  Updating Schedule: %v -> %v", interval, newInterval))
 				interval = newInterval
-				ticker.Reset(interval)
+				ticker.Reset(cfg.Scheduler.Jitter(interval))
+			}
+
+		case cmd, ok := <-commands:
+			if !ok {
+				commands = nil
+				continue
 			}
+			apply(cmd)
+
+		case cmd, ok := <-apiCommands:
+			if !ok {
+				apiCommands = nil
+				continue
+			}
+			apply(cmd)
 
 		case <-ticker.C:
-			runCycle(ctx, l, prov, interval, cycleCount)
+			if paused.Load() {
+				l.Plain("⏸️  Paused - skipping cycle (drop a resume command to continue)")
+				continue
+			}
+			// With jitter, each wait is drawn anew and counts from the end of the cycle
+			next := cfg.Scheduler.Jitter(interval)
+			runCycle(ctx, l, prov, next, cycleCount)
 			cycleCount++
+			if next != interval {
+				ticker.Reset(next)
+			}
+			writeStatus(l, cfg, prov, false)
 
 		case <-digestTicker.C:
+			prov.Events.Publish(mqtt.DigestEvent(tracker.Snapshot(), time.Now()))
 			if cfg.Notifications.Enabled {
 				l.Plain("📊 Sending Digest...")
 				n := notifier.New(cfg.Notifications) // Create temp notifier with current config
-				if err := n.SendDigest(tracker.Snapshot()); err != nil {
+				n.Notes = cfg.AccountNotes()
+				stats := tracker.Snapshot()
+				stats.Costs = prov.CostReport(ctx)
+				if err := n.SendDigest(stats); err != nil {
 					l.Error("NOTIFIER", fmt.Sprintf("Failed to send digest: %v", err))
 				}
 			}
@@ -203,6 +375,19 @@ func main() {
 	}
 }
 
+// applyLoggerOptions passes the celebration, accessibility and level settings to the logger.
+func applyLoggerOptions(l *logger.Logger, cfg *config.Config) {
+	l.SetAccessible(accessible || cfg.Accessible)
+	l.SetJSON(cfg.Logging.ConsoleFormat == "json")
+	l.SetLevel(cfg.Logging.Level)
+	l.SetCelebration(logger.CelebrationOptions{
+		Silent:    cfg.Celebration.Silent,
+		Beeps:     cfg.Celebration.Beeps,
+		Pattern:   cfg.Celebration.Pattern,
+		SoundFile: cfg.Celebration.SoundFile,
+	})
+}
+
 func logAccountSummary(l *logger.Logger, cfg *config.Config) {
 	count := 0
 	names := []string{}
@@ -237,15 +422,60 @@ func runCycle(ctx context.Context, l *logger.Logger, prov *provisioner.Provision
 		interval, nextRun.Format("15:04:05")))
 }
 
-// Helper to reload config safely
+// writeStatus refreshes the status_dir files, if configured.
+func writeStatus(l *logger.Logger, cfg *config.Config, prov *provisioner.Provisioner, paused bool) {
+	if cfg.StatusDir == "" {
+		return
+	}
+	if err := statusfile.Write(cfg.StatusDir, cfg.StatusFormat, api.Snapshot(prov, paused)); err != nil {
+		l.Error("STATUS", fmt.Sprintf("Failed to write status files: %v", err))
+	}
+}
+
+// loadConfig loads the config and applies the --stateless overrides.
+func loadConfig(path string) (*config.Config, string, error) {
+	if stateless {
+		config.IncludeCacheDir = ""
+	}
+	cfg, loadPath, err := config.LoadConfig(path)
+	if err == nil && stateless {
+		cfg.StateFile = "" // in memory unless state_backend is remote
+		cfg.StatusDir = ""
+	}
+	return cfg, loadPath, err
+}
+
 // Helper to reload config safely
 func reload(l *logger.Logger, path string, updates chan<- *config.Config) {
 	// Debounce/Settle
 	time.Sleep(100 * time.Millisecond)
-	newCfg, _, err := config.LoadConfig(path)
+	newCfg, _, err := loadConfig(path)
 	if err != nil {
 		l.Error("RELOAD", fmt.Sprintf("Failed to reload config: %v (keeping old config)", err))
 		return
 	}
 	updates <- newCfg
 }
+
+// checkForUpdates logs (and notifies) when a newer release is available.
+func checkForUpdates(ctx context.Context, l *logger.Logger, cfg *config.Config) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	rel, err := update.NewChecker().Latest(ctx)
+	if err != nil {
+		l.Warn("UPDATE", fmt.Sprintf("Update check failed: %v", err))
+		return
+	}
+	if !update.IsNewer(rel.Version(), version) {
+		return
+	}
+
+	l.Info("UPDATE", fmt.Sprintf("⬆️  Version %s is available (running %s). Run 'oci-arm-provisioner self-update' or see %s", rel.Version(), version, rel.HTMLURL))
+	if cfg.Notifications.Enabled {
+		n := notifier.New(cfg.Notifications)
+		if err := n.SendUpdateAvailable(version, rel.Version(), rel.HTMLURL); err != nil {
+			l.Error("NOTIFIER", fmt.Sprintf("Failed to send update notice: %v", err))
+		}
+	}
+}